@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/bitcoin/bitcoind"
+	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
+)
+
+// connectBitcoinChain connects to the Bitcoin chain using the backend
+// selected in the given config.
+func connectBitcoinChain(
+	ctx context.Context,
+	bitcoinConfig config.BitcoinConfig,
+) (bitcoin.Chain, error) {
+	switch bitcoinConfig.Backend {
+	case "", config.ElectrumBitcoinBackend:
+		btcChain, err := electrum.Connect(ctx, bitcoinConfig.Electrum)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"could not connect to Electrum chain: [%v]",
+				err,
+			)
+		}
+		return btcChain, nil
+	case config.BitcoindBitcoinBackend:
+		btcChain, err := bitcoind.Connect(ctx, bitcoinConfig.Bitcoind)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"could not connect to bitcoind chain: [%v]",
+				err,
+			)
+		}
+		return btcChain, nil
+	default:
+		return nil, fmt.Errorf(
+			"unsupported Bitcoin backend: [%s]",
+			bitcoinConfig.Backend,
+		)
+	}
+}
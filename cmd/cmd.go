@@ -36,6 +36,11 @@ func init() {
 		PingCommand,
 		EthereumCommand,
 		MaintainerCommand,
+		WalletCommand,
+		StorageCommand,
+		PreParamsCommand,
+		PreParamsWorkerCommand,
+		JournalCommand,
 	)
 }
 
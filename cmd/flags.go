@@ -12,6 +12,7 @@ import (
 	"github.com/keep-network/keep-common/pkg/cmd/flag"
 	"github.com/keep-network/keep-common/pkg/rate"
 	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/bitcoin/bitcoind"
 	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
 	chainEthereum "github.com/keep-network/keep-core/pkg/chain/ethereum"
 	"github.com/keep-network/keep-core/pkg/clientinfo"
@@ -37,8 +38,10 @@ func initFlags(
 		switch category {
 		case config.Ethereum:
 			initEthereumFlags(cmd, cfg)
-		case config.BitcoinElectrum:
+		case config.Bitcoin:
+			initBitcoinFlags(cmd, cfg)
 			initBitcoinElectrumFlags(cmd, cfg)
+			initBitcoindFlags(cmd, cfg)
 		case config.Network:
 			initNetworkFlags(cmd, cfg)
 		case config.Storage:
@@ -156,6 +159,25 @@ func initEthereumFlags(cmd *cobra.Command, cfg *config.Config) {
 	)
 }
 
+// Initialize flags for Bitcoin configuration.
+func initBitcoinFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.Backend,
+		"bitcoin.backend",
+		config.ElectrumBitcoinBackend,
+		fmt.Sprintf(
+			"Bitcoin chain backend (one of: %s).",
+			strings.Join(
+				[]string{
+					config.ElectrumBitcoinBackend,
+					config.BitcoindBitcoinBackend,
+				},
+				", ",
+			),
+		),
+	)
+}
+
 // Initialize flags for Bitcoin electrum configuration.
 func initBitcoinElectrumFlags(cmd *cobra.Command, cfg *config.Config) {
 	cmd.Flags().StringVar(
@@ -165,6 +187,13 @@ func initBitcoinElectrumFlags(cmd *cobra.Command, cfg *config.Config) {
 		"URL to the Electrum server in format: `hostname:port`.",
 	)
 
+	cmd.Flags().StringSliceVar(
+		&cfg.Bitcoin.Electrum.FallbackURLs,
+		"bitcoin.electrum.fallbackUrls",
+		[]string{},
+		"URLs to fallback Electrum servers in format: `hostname:port`.",
+	)
+
 	electrum.ProtocolVarFlag(
 		cmd.Flags(),
 		&cfg.Bitcoin.Electrum.Protocol,
@@ -210,6 +239,51 @@ func initBitcoinElectrumFlags(cmd *cobra.Command, cfg *config.Config) {
 		electrum.DefaultKeepAliveInterval,
 		"Interval for connection keep alive requests.",
 	)
+
+	cmd.Flags().UintVar(
+		&cfg.Bitcoin.Electrum.MaxBlockHeightDeviation,
+		"bitcoin.electrum.maxBlockHeightDeviation",
+		electrum.DefaultMaxBlockHeightDeviation,
+		"Maximum number of blocks the latest block height reported by an Electrum server can deviate from heights reported by other servers.",
+	)
+}
+
+// Initialize flags for Bitcoin bitcoind configuration.
+func initBitcoindFlags(cmd *cobra.Command, cfg *config.Config) {
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.Bitcoind.URL,
+		"bitcoin.bitcoind.url",
+		"",
+		"URL to the bitcoind JSON-RPC endpoint in format: `http://hostname:port`.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.Bitcoind.Username,
+		"bitcoin.bitcoind.username",
+		"",
+		"Username used to authenticate bitcoind JSON-RPC requests.",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Bitcoin.Bitcoind.Password,
+		"bitcoin.bitcoind.password",
+		"",
+		"Password used to authenticate bitcoind JSON-RPC requests.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Bitcoin.Bitcoind.RequestTimeout,
+		"bitcoin.bitcoind.requestTimeout",
+		bitcoind.DefaultRequestTimeout,
+		"Timeout for a single attempt of bitcoind JSON-RPC request.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Bitcoin.Bitcoind.RequestRetryTimeout,
+		"bitcoin.bitcoind.requestRetryTimeout",
+		bitcoind.DefaultRequestRetryTimeout,
+		"Timeout for bitcoind JSON-RPC request retries.",
+	)
 }
 
 // Initialize flags for Network configuration.
@@ -320,6 +394,34 @@ func initTbtcFlags(cmd *cobra.Command, cfg *config.Config) {
 		tbtc.DefaultKeyGenerationConcurrency,
 		"tECDSA key generation concurrency.",
 	)
+
+	cmd.Flags().StringVar(
+		&cfg.Tbtc.PreParamsWorkerAddress,
+		"tbtc.preParamsWorkerAddress",
+		"",
+		"Address to listen on for tECDSA pre-parameters delivered by external workers.",
+	)
+
+	cmd.Flags().IntVar(
+		&cfg.Tbtc.AttemptJournalMaxSessions,
+		"tbtc.attemptJournalMaxSessions",
+		tbtc.DefaultAttemptJournalMaxSessions,
+		"Maximum number of DKG and signing sessions kept in the attempt journal.",
+	)
+
+	cmd.Flags().DurationVar(
+		&cfg.Tbtc.AttemptJournalRetention,
+		"tbtc.attemptJournalRetention",
+		tbtc.DefaultAttemptJournalRetention,
+		"Retention period of DKG and signing sessions kept in the attempt journal.",
+	)
+
+	cmd.Flags().IntVar(
+		&cfg.Tbtc.PresignaturesPoolSize,
+		"tbtc.presignaturesPoolSize",
+		tbtc.DefaultPresignaturesPoolSize,
+		"Maximum number of tECDSA presignatures kept for each wallet signer.",
+	)
 }
 
 // Initialize flags for Maintainer configuration.
@@ -330,6 +432,13 @@ func initMaintainerFlags(command *cobra.Command, cfg *config.Config) {
 		false,
 		"start Bitcoin difficulty maintainer",
 	)
+
+	command.Flags().BoolVar(
+		&cfg.Maintainer.Spv,
+		"spv",
+		false,
+		"start SPV maintainer; it is not started unless this flag is set",
+	)
 }
 
 // Initialize flags for Developer configuration.
@@ -346,6 +455,7 @@ func initDeveloperFlags(command *cobra.Command) {
 	}
 
 	initContractAddressFlag(chainEthereum.BridgeContractName)
+	initContractAddressFlag(chainEthereum.EcdsaDkgValidatorContractName)
 	initContractAddressFlag(chainEthereum.LightRelayContractName)
 	initContractAddressFlag(chainEthereum.RandomBeaconContractName)
 	initContractAddressFlag(chainEthereum.TokenStakingContractName)
@@ -81,6 +81,13 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: big.NewInt(1250000000000000000),
 		defaultValue:          big.NewInt(500000000000000000),
 	},
+	"bitcoin.backend": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Backend },
+		flagName:              "--bitcoin.backend",
+		flagValue:             "bitcoind",
+		expectedValueFromFlag: "bitcoind",
+		defaultValue:          "electrum",
+	},
 	"bitcoin.electrum.url": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Electrum.URL },
 		flagName:              "--bitcoin.electrum.url",
@@ -88,6 +95,16 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: "url.to.electrum:18332",
 		defaultValue:          "",
 	},
+	"bitcoin.electrum.fallbackUrls": {
+		readValueFunc: func(c *config.Config) interface{} { return c.Bitcoin.Electrum.FallbackURLs },
+		flagName:      "--bitcoin.electrum.fallbackUrls",
+		flagValue:     `"fallback1.url.to.electrum:18332","fallback2.url.to.electrum:18332"`,
+		expectedValueFromFlag: []string{
+			"fallback1.url.to.electrum:18332",
+			"fallback2.url.to.electrum:18332",
+		},
+		defaultValue: []string{},
+	},
 	"bitcoin.electrum.protocol": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Electrum.Protocol },
 		flagName:              "--bitcoin.electrum.protocol",
@@ -130,6 +147,48 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: 660 * time.Second,
 		defaultValue:          300 * time.Second,
 	},
+	"bitcoin.electrum.maxBlockHeightDeviation": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Electrum.MaxBlockHeightDeviation },
+		flagName:              "--bitcoin.electrum.maxBlockHeightDeviation",
+		flagValue:             "5",
+		expectedValueFromFlag: uint(5),
+		defaultValue:          uint(3),
+	},
+	"bitcoin.bitcoind.url": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Bitcoind.URL },
+		flagName:              "--bitcoin.bitcoind.url",
+		flagValue:             "http://url.to.bitcoind:18332",
+		expectedValueFromFlag: "http://url.to.bitcoind:18332",
+		defaultValue:          "",
+	},
+	"bitcoin.bitcoind.username": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Bitcoind.Username },
+		flagName:              "--bitcoin.bitcoind.username",
+		flagValue:             "bitcoind-user",
+		expectedValueFromFlag: "bitcoind-user",
+		defaultValue:          "",
+	},
+	"bitcoin.bitcoind.password": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Bitcoind.Password },
+		flagName:              "--bitcoin.bitcoind.password",
+		flagValue:             "bitcoind-password",
+		expectedValueFromFlag: "bitcoind-password",
+		defaultValue:          "",
+	},
+	"bitcoin.bitcoind.requestTimeout": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Bitcoind.RequestTimeout },
+		flagName:              "--bitcoin.bitcoind.requestTimeout",
+		flagValue:             "45s",
+		expectedValueFromFlag: 45 * time.Second,
+		defaultValue:          30 * time.Second,
+	},
+	"bitcoin.bitcoind.requestRetryTimeout": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Bitcoin.Bitcoind.RequestRetryTimeout },
+		flagName:              "--bitcoin.bitcoind.requestRetryTimeout",
+		flagValue:             "3m",
+		expectedValueFromFlag: 180 * time.Second,
+		defaultValue:          120 * time.Second,
+	},
 	"network.bootstrap": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.LibP2P.Bootstrap },
 		flagName:              "--network.bootstrap",
@@ -233,6 +292,34 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: 101,
 		defaultValue:          runtime.GOMAXPROCS(0),
 	},
+	"tbtc.preParamsWorkerAddress": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Tbtc.PreParamsWorkerAddress },
+		flagName:              "--tbtc.preParamsWorkerAddress",
+		flagValue:             "127.0.0.1:9650",
+		expectedValueFromFlag: "127.0.0.1:9650",
+		defaultValue:          "",
+	},
+	"tbtc.attemptJournalMaxSessions": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Tbtc.AttemptJournalMaxSessions },
+		flagName:              "--tbtc.attemptJournalMaxSessions",
+		flagValue:             "250",
+		expectedValueFromFlag: 250,
+		defaultValue:          1000,
+	},
+	"tbtc.attemptJournalRetention": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Tbtc.AttemptJournalRetention },
+		flagName:              "--tbtc.attemptJournalRetention",
+		flagValue:             "168h",
+		expectedValueFromFlag: 7 * 24 * time.Hour,
+		defaultValue:          30 * 24 * time.Hour,
+	},
+	"tbtc.presignaturesPoolSize": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Tbtc.PresignaturesPoolSize },
+		flagName:              "--tbtc.presignaturesPoolSize",
+		flagValue:             "25",
+		expectedValueFromFlag: 25,
+		defaultValue:          10,
+	},
 	"maintainer.bitcoinDifficulty": {
 		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.BitcoinDifficulty },
 		flagName:              "--bitcoinDifficulty",
@@ -240,6 +327,13 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: true,
 		defaultValue:          false,
 	},
+	"maintainer.spv": {
+		readValueFunc:         func(c *config.Config) interface{} { return c.Maintainer.Spv },
+		flagName:              "--spv",
+		flagValue:             "", // don't provide any value
+		expectedValueFromFlag: true,
+		defaultValue:          false,
+	},
 	"developer.randomBeaconAddress": {
 		readValueFunc: func(c *config.Config) interface{} {
 			address, _ := c.Ethereum.ContractAddress(chainEthereum.RandomBeaconContractName)
@@ -280,6 +374,16 @@ var cmdFlagsTests = map[string]struct {
 		expectedValueFromFlag: common.HexToAddress("0x68e20afD773fDF1231B5cbFeA7040e73e79cAc36"),
 		defaultValue:          common.HexToAddress(ethereumTbtc.LightRelayAddress),
 	},
+	"developer.ecdsaDkgValidatorAddress": {
+		readValueFunc: func(c *config.Config) interface{} {
+			address, _ := c.Ethereum.ContractAddress(chainEthereum.EcdsaDkgValidatorContractName)
+			return address
+		},
+		flagName:              "--developer.ecdsaDkgValidatorAddress",
+		flagValue:             "0x0125c8977a02b2fa3970b1ed9af02f5bedd4ef27",
+		expectedValueFromFlag: common.HexToAddress("0x0125c8977a02b2Fa3970b1ED9AF02f5Bedd4eF27"),
+		defaultValue:          common.HexToAddress(ethereumEcdsa.EcdsaDkgValidatorAddress),
+	},
 	"developer.tokenStakingAddress": {
 		readValueFunc: func(c *config.Config) interface{} {
 			address, _ := c.Ethereum.ContractAddress(chainEthereum.TokenStakingContractName)
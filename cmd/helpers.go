@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
+	"syscall"
 
 	commonEthereum "github.com/keep-network/keep-common/pkg/chain/ethereum"
 	"github.com/keep-network/keep-core/build"
 	chainEthereum "github.com/keep-network/keep-core/pkg/chain/ethereum"
+	"golang.org/x/term"
 )
 
 func nodeHeader(addrStrings []string, operator string, port int, ethereumConfig commonEthereum.Config) {
@@ -110,3 +113,51 @@ func buildContractAddresses(lineLength int, prefix, suffix string, ethereumConfi
 	}
 	return firstLine + buildMultiLine(lineLength, prefix, suffix, "", entries)
 }
+
+// readSecret reads the password or secret with the given name from the given
+// environment variable or prompts the user for it if the variable is not
+// set. If the confirm flag is set, the user has to enter the password twice.
+func readSecret(
+	name string,
+	envVariable string,
+	confirm bool,
+) (string, error) {
+	if password := os.Getenv(envVariable); password != "" {
+		return password, nil
+	}
+
+	readPassword := func(prompt string) (string, error) {
+		fmt.Print(prompt)
+		passwordBytes, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Print("\n")
+		if err != nil {
+			return "", fmt.Errorf("unable to read password: [%w]", err)
+		}
+
+		return strings.TrimSpace(string(passwordBytes)), nil
+	}
+
+	password, err := readPassword(fmt.Sprintf("Enter %s password: ", name))
+	if err != nil {
+		return "", err
+	}
+
+	if password == "" {
+		return "", fmt.Errorf("%s password must not be empty", name)
+	}
+
+	if !confirm {
+		return password, nil
+	}
+
+	confirmation, err := readPassword(fmt.Sprintf("Confirm %s password: ", name))
+	if err != nil {
+		return "", err
+	}
+
+	if password != confirmation {
+		return "", fmt.Errorf("%s passwords do not match", name)
+	}
+
+	return password, nil
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// JournalCommand contains the definition of the journal command-line
+// subcommand and its own subcommands.
+var JournalCommand = &cobra.Command{
+	Use:   "journal",
+	Short: "Inspects the DKG and signing attempt journal",
+	Long:  journalDescription,
+}
+
+const journalDescription = `The journal command allows inspecting the journal
+of DKG and signing sessions executed by the client. For each attempt of
+a session, the journal holds the members that announced their readiness,
+the members excluded from the attempt, the outcome, and the duration.
+The journal is held in the client's work directory and is read directly from
+disk, so the commands can be used while the client is running or stopped.
+
+See the subcommand help for additional details.`
+
+var journalListCommand = &cobra.Command{
+	Use:   "list",
+	Short: "Lists sessions held in the journal",
+	Long: `Lists sessions held in the journal, from the newest to the oldest one.
+Sessions can be filtered by the protocol and the outcome.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		protocol, err := cmd.Flags().GetString(journalProtocolFlag)
+		if err != nil {
+			return err
+		}
+
+		failedOnly, err := cmd.Flags().GetBool(journalFailedFlag)
+		if err != nil {
+			return err
+		}
+
+		limit, err := cmd.Flags().GetInt(journalLimitFlag)
+		if err != nil {
+			return err
+		}
+
+		sessions, err := readAttemptJournal()
+		if err != nil {
+			return err
+		}
+
+		listed := 0
+		for i := len(sessions) - 1; i >= 0; i-- {
+			session := sessions[i]
+
+			if protocol != "" && session.Protocol != protocol {
+				continue
+			}
+			if failedOnly && session.Outcome != tbtc.AttemptFailed {
+				continue
+			}
+			if limit > 0 && listed == limit {
+				break
+			}
+
+			printJournalSession(session, false)
+			listed++
+		}
+
+		if listed == 0 {
+			fmt.Println("no sessions found in the journal")
+		}
+
+		return nil
+	},
+}
+
+const (
+	journalProtocolFlag = "protocol"
+	journalFailedFlag   = "failed"
+	journalLimitFlag    = "limit"
+)
+
+var journalShowCommand = &cobra.Command{
+	Use:   "show [seed-or-message]",
+	Short: "Shows attempts of sessions with the given DKG seed or message",
+	Long: `Shows all attempts of sessions executed for the given hex-encoded DKG
+seed or signed message. There is a separate session for each member
+controlled by the client.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, ok := new(big.Int).SetString(
+			strings.TrimPrefix(strings.ToLower(args[0]), "0x"),
+			16,
+		)
+		if !ok {
+			return fmt.Errorf("invalid seed or message [%s]", args[0])
+		}
+
+		sessions, err := readAttemptJournal()
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, session := range sessions {
+			if session.Key != key.Text(16) {
+				continue
+			}
+
+			printJournalSession(session, true)
+			found = true
+		}
+
+		if !found {
+			return fmt.Errorf("no sessions found for [%s]", args[0])
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	for _, command := range []*cobra.Command{
+		journalListCommand,
+		journalShowCommand,
+	} {
+		initFlags(command, &configFilePath, clientConfig, config.JournalCategories...)
+
+		command.PreRun = func(cmd *cobra.Command, args []string) {
+			if err := clientConfig.ReadConfig(
+				configFilePath,
+				cmd.Flags(),
+				config.JournalCategories...,
+			); err != nil {
+				logger.Fatalf("error reading config: %v", err)
+			}
+		}
+
+		JournalCommand.AddCommand(command)
+	}
+
+	journalListCommand.Flags().String(
+		journalProtocolFlag,
+		"",
+		fmt.Sprintf(
+			"Lists only sessions of the given protocol: %s or %s.",
+			tbtc.DkgJournalProtocol,
+			tbtc.SigningJournalProtocol,
+		),
+	)
+
+	journalListCommand.Flags().Bool(
+		journalFailedFlag,
+		false,
+		"Lists only failed sessions.",
+	)
+
+	journalListCommand.Flags().Int(
+		journalLimitFlag,
+		20,
+		"Maximum number of listed sessions. All sessions are listed if set to 0.",
+	)
+}
+
+// readAttemptJournal reads all sessions held in the attempt journal of
+// the tbtc work persistence, sorted from the oldest to the newest one.
+func readAttemptJournal() ([]*tbtc.AttemptJournalSession, error) {
+	workPersistence, err := initializeTbtcWorkPersistence(false)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, errs := tbtc.ReadAttemptJournal(workPersistence)
+	for _, err := range errs {
+		fmt.Printf("cannot read journal: [%v]\n", err)
+	}
+
+	return sessions, nil
+}
+
+func printJournalSession(session *tbtc.AttemptJournalSession, details bool) {
+	outcome := session.Outcome
+	if outcome == "" {
+		outcome = "in progress"
+	}
+
+	fmt.Printf(
+		"%s [0x%s] member [%v]: %s\n",
+		session.Protocol,
+		session.Key,
+		session.MemberIndex,
+		outcome,
+	)
+	if session.Wallet != "" {
+		fmt.Printf("  wallet:         0x%s\n", session.Wallet)
+	}
+	fmt.Printf("  start block:    %v\n", session.StartBlock)
+	fmt.Printf("  started at:     %s\n", session.StartedAt.Format(time.RFC3339))
+	if session.Outcome != "" {
+		fmt.Printf("  duration:       %s\n", session.Duration.Round(time.Second))
+	}
+	if session.FailureReason != "" {
+		fmt.Printf("  failure reason: %s\n", session.FailureReason)
+	}
+	fmt.Printf("  attempts:       %v\n", len(session.Attempts))
+
+	if !details {
+		return
+	}
+
+	for _, attempt := range session.Attempts {
+		fmt.Printf("  attempt [%v]: %s\n", attempt.Number, attempt.Outcome)
+		fmt.Printf("    start block:      %v\n", attempt.StartBlock)
+		fmt.Printf(
+			"    started at:       %s\n",
+			attempt.StartedAt.Format(time.RFC3339),
+		)
+		fmt.Printf(
+			"    duration:         %s\n",
+			attempt.Duration.Round(time.Millisecond),
+		)
+		fmt.Printf(
+			"    ready members:    %v %v\n",
+			len(attempt.ReadyMembers),
+			attempt.ReadyMembers,
+		)
+		if len(attempt.ExcludedMembers) > 0 {
+			fmt.Printf("    excluded members: %v\n", attempt.ExcludedMembers)
+		}
+		if attempt.FailureReason != "" {
+			fmt.Printf("    failure reason:   %s\n", attempt.FailureReason)
+		}
+	}
+}
@@ -3,12 +3,15 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
 	"github.com/keep-network/keep-core/config"
-	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
 	"github.com/keep-network/keep-core/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/maintainer"
 )
 
@@ -42,23 +45,52 @@ func init() {
 // maintainers initializes maintainer tasks specified by flags passed to the
 // maintainer command.
 func maintainers(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := signal.NotifyContext(
+		context.Background(),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+	defer cancel()
 
-	btcChain, err := electrum.Connect(ctx, clientConfig.Bitcoin.Electrum)
+	btcChain, err := connectBitcoinChain(ctx, clientConfig.Bitcoin)
 	if err != nil {
-		return fmt.Errorf("could not connect to Electrum chain: [%v]", err)
+		return err
 	}
 
-	chain, err := ethereum.ConnectBitcoinDifficulty(ctx, clientConfig.Ethereum)
+	btcDiffChain, spvChain, err := ethereum.ConnectMaintainer(
+		ctx,
+		clientConfig.Ethereum,
+	)
 	if err != nil {
 		return fmt.Errorf(
-			"could not connect to Bitcoin difficulty chain: [%v]",
+			"could not connect to maintainer chains: [%v]",
 			err,
 		)
 	}
 
-	maintainer.Initialize(ctx, clientConfig.Maintainer, btcChain, chain)
+	clientInfoRegistry, isConfigured := clientinfo.Initialize(
+		ctx,
+		clientConfig.ClientInfo.Port,
+	)
+	if !isConfigured {
+		logger.Infof("client info endpoint not configured")
+	}
+
+	stopMaintainers := maintainer.Initialize(
+		ctx,
+		clientConfig.Maintainer,
+		btcChain,
+		clientConfig.Bitcoin.SupportsTransactionsHistory(),
+		btcDiffChain,
+		spvChain,
+		clientInfoRegistry,
+	)
 
 	<-ctx.Done()
-	return fmt.Errorf("unexpected context cancellation")
+
+	logger.Infof("shutting down maintainers")
+	stopMaintainers()
+	logger.Infof("maintainers stopped")
+
+	return nil
 }
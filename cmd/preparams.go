@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/tecdsa/dkg"
+)
+
+// PreParamsCommand contains the definition of the preparams command-line
+// subcommand and its own subcommands.
+var PreParamsCommand = &cobra.Command{
+	Use:   "preparams",
+	Short: "Manages the tECDSA pre-parameters pool",
+	Long:  preParamsDescription,
+}
+
+const preParamsDescription = `The preparams command allows managing the pool of
+tECDSA pre-parameters held in the client's work directory. The client does
+not join the sortition pool until the pool is filled, and generating the
+pre-parameters may take hours after a fresh install. The pool can be
+inspected and filled ahead of the client start with these commands.
+
+The generate and purge commands are refused if the client is running
+against the storage.
+
+See the subcommand help for additional details.`
+
+// preParamsAgeBuckets are the upper bounds of the age buckets the
+// pre-parameters are grouped in by the show command.
+var preParamsAgeBuckets = []struct {
+	name   string
+	maxAge time.Duration
+}{
+	{"< 1 day", 24 * time.Hour},
+	{"1-7 days", 7 * 24 * time.Hour},
+	{"7-30 days", 30 * 24 * time.Hour},
+}
+
+var preParamsShowCommand = &cobra.Command{
+	Use:   "show",
+	Short: "Shows the size and age distribution of the pool",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workPersistence, err := initializeTbtcWorkPersistence(false)
+		if err != nil {
+			return err
+		}
+
+		storedPreParams, errs := dkg.ReadStoredPreParams(workPersistence)
+		for _, err := range errs {
+			fmt.Printf("cannot read pre-params storage: [%v]\n", err)
+		}
+
+		var valid, invalid []*dkg.StoredPreParams
+		for _, entry := range storedPreParams {
+			if entry.Err != nil {
+				invalid = append(invalid, entry)
+			} else {
+				valid = append(valid, entry)
+			}
+		}
+
+		fmt.Printf(
+			"pool size:         %v of %v\n",
+			len(valid),
+			clientConfig.Tbtc.PreParamsPoolSize,
+		)
+		fmt.Printf("invalid entries:   %v\n", len(invalid))
+
+		if len(valid) > 0 {
+			// Valid entries are sorted from the oldest to the newest one.
+			fmt.Printf(
+				"oldest entry:      %s\n",
+				valid[0].CreationTimestamp.Format(time.RFC3339),
+			)
+			fmt.Printf(
+				"newest entry:      %s\n",
+				valid[len(valid)-1].CreationTimestamp.Format(time.RFC3339),
+			)
+
+			bucketCounts := make([]int, len(preParamsAgeBuckets)+1)
+			for _, entry := range valid {
+				age := time.Since(entry.CreationTimestamp)
+
+				bucket := len(preParamsAgeBuckets)
+				for i, ageBucket := range preParamsAgeBuckets {
+					if age < ageBucket.maxAge {
+						bucket = i
+						break
+					}
+				}
+
+				bucketCounts[bucket]++
+			}
+
+			fmt.Println("age distribution:")
+			for i, ageBucket := range preParamsAgeBuckets {
+				fmt.Printf("  %-10s %v\n", ageBucket.name, bucketCounts[i])
+			}
+			fmt.Printf(
+				"  %-10s %v\n",
+				"> 30 days",
+				bucketCounts[len(preParamsAgeBuckets)],
+			)
+		}
+
+		for _, entry := range invalid {
+			fmt.Printf("invalid entry [%s]: [%v]\n", entry.ID, entry.Err)
+		}
+
+		return nil
+	},
+}
+
+var preParamsGenerateCommand = &cobra.Command{
+	Use:   "generate",
+	Short: "Generates pre-parameters into the pool",
+	Long: `Generates pre-parameters and saves them in the client's work
+directory so they are loaded to the pool once the client starts. By default,
+the pool is filled up to the configured pool size. The generation timeout and
+concurrency level are taken from the tbtc configuration. The generation can
+be interrupted at any time; pre-parameters generated so far are kept.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, err := cmd.Flags().GetInt(preParamsGenerateCountFlag)
+		if err != nil {
+			return err
+		}
+
+		workPersistence, err := initializeTbtcWorkPersistence(true)
+		if err != nil {
+			return err
+		}
+
+		if count <= 0 {
+			storedPreParams, errs := dkg.ReadStoredPreParams(workPersistence)
+			if len(errs) > 0 {
+				return fmt.Errorf(
+					"cannot read pre-params storage: [%w]",
+					errs[0],
+				)
+			}
+
+			count = clientConfig.Tbtc.PreParamsPoolSize
+			for _, entry := range storedPreParams {
+				if entry.Err == nil {
+					count--
+				}
+			}
+
+			if count <= 0 {
+				fmt.Println("pre-params pool is already full")
+				return nil
+			}
+		}
+
+		ctx, cancel := signal.NotifyContext(
+			context.Background(),
+			os.Interrupt,
+			syscall.SIGTERM,
+		)
+		defer cancel()
+
+		fmt.Printf(
+			"generating [%v] pre-params with concurrency level [%v]\n",
+			count,
+			clientConfig.Tbtc.PreParamsGenerationConcurrency,
+		)
+
+		generated, err := dkg.GeneratePreParams(
+			ctx,
+			logger,
+			workPersistence,
+			count,
+			clientConfig.Tbtc.PreParamsGenerationTimeout,
+			clientConfig.Tbtc.PreParamsGenerationConcurrency,
+			func(entry *dkg.StoredPreParams, took time.Duration) {
+				fmt.Printf(
+					"generated pre-params [%s], took: [%s]\n",
+					entry.ID,
+					took.Round(time.Millisecond),
+				)
+			},
+		)
+		fmt.Printf("generated [%v] of [%v] pre-params\n", generated, count)
+		if err != nil {
+			return fmt.Errorf("cannot generate pre-params: [%w]", err)
+		}
+
+		return nil
+	},
+}
+
+const preParamsGenerateCountFlag = "count"
+
+var preParamsPurgeCommand = &cobra.Command{
+	Use:   "purge",
+	Short: "Removes invalid and stale pre-parameters from the pool",
+	Long: `Removes pre-parameters that are corrupted or fail the validation from
+the client's work directory. If the maximum age is set, pre-parameters older
+than that age are removed as well.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		maxAge, err := cmd.Flags().GetDuration(preParamsPurgeMaxAgeFlag)
+		if err != nil {
+			return err
+		}
+
+		workPersistence, err := initializeTbtcWorkPersistence(true)
+		if err != nil {
+			return err
+		}
+
+		purged, err := dkg.PurgeStoredPreParams(logger, workPersistence, maxAge)
+		for _, entry := range purged {
+			if entry.Err != nil {
+				fmt.Printf(
+					"removed invalid pre-params [%s]: [%v]\n",
+					entry.ID,
+					entry.Err,
+				)
+			} else {
+				fmt.Printf(
+					"removed stale pre-params [%s] created at [%s]\n",
+					entry.ID,
+					entry.CreationTimestamp.Format(time.RFC3339),
+				)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("cannot purge pre-params: [%w]", err)
+		}
+
+		fmt.Printf("removed [%v] pre-params\n", len(purged))
+
+		return nil
+	},
+}
+
+const preParamsPurgeMaxAgeFlag = "max-age"
+
+func init() {
+	for _, command := range []*cobra.Command{
+		preParamsShowCommand,
+		preParamsGenerateCommand,
+		preParamsPurgeCommand,
+	} {
+		initFlags(command, &configFilePath, clientConfig, config.PreParamsCategories...)
+
+		command.PreRun = func(cmd *cobra.Command, args []string) {
+			if err := clientConfig.ReadConfig(
+				configFilePath,
+				cmd.Flags(),
+				config.PreParamsCategories...,
+			); err != nil {
+				logger.Fatalf("error reading config: %v", err)
+			}
+		}
+
+		PreParamsCommand.AddCommand(command)
+	}
+
+	preParamsGenerateCommand.Flags().Int(
+		preParamsGenerateCountFlag,
+		0,
+		"Number of pre-parameters to generate. Fills the pool up to the "+
+			"configured pool size if not set.",
+	)
+
+	preParamsPurgeCommand.Flags().Duration(
+		preParamsPurgeMaxAgeFlag,
+		0,
+		"Maximum age of pre-parameters kept in the pool. Only invalid "+
+			"pre-parameters are removed if not set.",
+	)
+}
+
+// initializeTbtcWorkPersistence initializes the tbtc work persistence handle
+// holding the pre-parameters pool. If exclusive access is requested, the
+// storage is locked so that the client cannot be started against it until
+// the command completes.
+func initializeTbtcWorkPersistence(
+	exclusive bool,
+) (persistence.BasicHandle, error) {
+	clientStorage, err := initializeStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	if exclusive {
+		if err := clientStorage.Lock(); err != nil {
+			return nil, fmt.Errorf("cannot lock storage: [%w]", err)
+		}
+	}
+
+	workPersistence, err := clientStorage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot initialize tbtc work persistence: [%w]",
+			err,
+		)
+	}
+
+	return workPersistence, nil
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+	"github.com/keep-network/keep-core/pkg/tecdsa/dkg"
+)
+
+// PreParamsWorkerCommand contains the definition of the preparams-worker
+// command-line subcommand.
+var PreParamsWorkerCommand = &cobra.Command{
+	Use:   "preparams-worker",
+	Short: "Generates tECDSA pre-parameters for a client",
+	Long:  preParamsWorkerDescription,
+	Args:  cobra.NoArgs,
+	RunE:  runPreParamsWorker,
+}
+
+var preParamsWorkerDescription = fmt.Sprintf(`The preparams-worker command
+generates tECDSA pre-parameters and delivers them to a client so the
+generation does not compete for CPU with the client's protocols. The worker
+can run on another machine. The client must be started with the
+tbtc.preParamsWorkerAddress option set; the worker delivers pre-parameters
+to that address.
+
+The pre-parameters are encrypted and authenticated with a secret shared by
+the worker and the client. The worker reads the secret from the %s
+environment variable or the prompt. The client validates the received
+pre-parameters before adding them to its pool. When the client's pool is
+full, the worker waits and delivers the pre-parameters later.`,
+	config.PreParamsWorkerSecretEnvVariable,
+)
+
+const (
+	preParamsWorkerNodeURLFlag     = "node-url"
+	preParamsWorkerCountFlag       = "count"
+	preParamsWorkerTimeoutFlag     = "timeout"
+	preParamsWorkerConcurrencyFlag = "concurrency"
+)
+
+func init() {
+	PreParamsWorkerCommand.Flags().String(
+		preParamsWorkerNodeURLFlag,
+		"",
+		"URL of the client's pre-parameters endpoint, e.g. http://10.0.0.1:9650.",
+	)
+	if err := PreParamsWorkerCommand.MarkFlagRequired(
+		preParamsWorkerNodeURLFlag,
+	); err != nil {
+		logger.Panicf("cannot mark flag as required: [%v]", err)
+	}
+
+	PreParamsWorkerCommand.Flags().Int(
+		preParamsWorkerCountFlag,
+		0,
+		"Number of pre-parameters to deliver. Runs until interrupted if not set.",
+	)
+
+	PreParamsWorkerCommand.Flags().Duration(
+		preParamsWorkerTimeoutFlag,
+		tbtc.DefaultPreParamsGenerationTimeout,
+		"tECDSA pre-parameters generation timeout.",
+	)
+
+	PreParamsWorkerCommand.Flags().Int(
+		preParamsWorkerConcurrencyFlag,
+		runtime.GOMAXPROCS(0),
+		"tECDSA pre-parameters generation concurrency.",
+	)
+}
+
+func runPreParamsWorker(cmd *cobra.Command, args []string) error {
+	nodeURL, err := cmd.Flags().GetString(preParamsWorkerNodeURLFlag)
+	if err != nil {
+		return err
+	}
+
+	count, err := cmd.Flags().GetInt(preParamsWorkerCountFlag)
+	if err != nil {
+		return err
+	}
+
+	timeout, err := cmd.Flags().GetDuration(preParamsWorkerTimeoutFlag)
+	if err != nil {
+		return err
+	}
+
+	concurrency, err := cmd.Flags().GetInt(preParamsWorkerConcurrencyFlag)
+	if err != nil {
+		return err
+	}
+
+	secret, err := readSecret(
+		"pre-params worker",
+		config.PreParamsWorkerSecretEnvVariable,
+		false,
+	)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(
+		context.Background(),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+	defer cancel()
+
+	worker := dkg.NewPreParamsWorker(
+		logger,
+		dkg.NewLocalPreParamsGenerator(timeout, concurrency),
+		nodeURL,
+		secret,
+		dkg.DefaultPreParamsDeliveryRetryDelay,
+	)
+
+	logger.Infof(
+		"delivering pre-params to [%s] with concurrency level [%d]",
+		nodeURL,
+		concurrency,
+	)
+
+	delivered, err := worker.Run(ctx, count, func(took time.Duration) {
+		logger.Infof(
+			"delivered pre-params, took: [%s]",
+			took.Round(time.Millisecond),
+		)
+	})
+	logger.Infof("delivered [%d] pre-params", delivered)
+	if err != nil {
+		return fmt.Errorf("pre-params worker failed: [%w]", err)
+	}
+
+	return nil
+}
@@ -45,10 +45,14 @@ func init() {
 		fmt.Sprintf(`%s
 Environment variables:
     %s    Password for Keep operator account keyfile decryption.
+    %s     Password for storage encryption; defaults to the keyfile password.
+    %s     Secret shared with external pre-parameters workers.
     %s                 Space-delimited set of log level directives; set to "help" for help.
 `,
 			StartCommand.UsageString(),
 			config.EthereumPasswordEnvVariable,
+			config.StoragePasswordEnvVariable,
+			config.PreParamsWorkerSecretEnvVariable,
 			config.LogLevelEnvVariable,
 		),
 	)
@@ -84,12 +88,49 @@ func start(cmd *cobra.Command) error {
 		firewall.NewAllowList(bootstrapPeersPublicKeys),
 	)
 
+	retransmissionTicker := retransmission.NewTicker(
+		blockCounter.WatchBlocks(ctx),
+	)
+
+	// Initialize the storage only for non-bootstrap nodes. Bootstrap nodes
+	// keep bans of misbehaving peers in memory only.
+	var clientStorage storage.Storage
+	var connectOptions []libp2p.ConnectOption
+	if !clientConfig.LibP2P.Bootstrap {
+		clientStorage, err = storage.Initialize(clientConfig.Storage)
+		if err != nil {
+			return fmt.Errorf("cannot initialize storage: [%w]", err)
+		}
+
+		// Lock the storage for the client lifetime so that no other process,
+		// e.g. a storage restore, modifies it while the client is running.
+		if err := clientStorage.Lock(); err != nil {
+			return fmt.Errorf("cannot lock storage: [%w]", err)
+		}
+
+		networkDataPersistence, err := clientStorage.InitializeWorkPersistence(
+			"network",
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot initialize network data persistence: [%w]",
+				err,
+			)
+		}
+
+		connectOptions = append(
+			connectOptions,
+			libp2p.WithReputationPersistence(networkDataPersistence),
+		)
+	}
+
 	netProvider, err := libp2p.Connect(
 		ctx,
 		clientConfig.LibP2P,
 		operatorPrivateKey,
 		firewall,
-		retransmission.NewTicker(blockCounter.WatchBlocks(ctx)),
+		retransmissionTicker,
+		connectOptions...,
 	)
 	if err != nil {
 		return fmt.Errorf("failed while creating the network provider: [%v]", err)
@@ -108,21 +149,14 @@ func start(cmd *cobra.Command) error {
 		netProvider,
 		signing,
 		blockCounter,
+		retransmissionTicker,
 	)
 
 	// Initialize beacon and tbtc only for non-bootstrap nodes.
 	// Skip initialization for bootstrap nodes as they are only used for network
 	// discovery.
 	if !clientConfig.LibP2P.Bootstrap {
-		storage, err := storage.Initialize(
-			clientConfig.Storage,
-			clientConfig.Ethereum.KeyFilePassword,
-		)
-		if err != nil {
-			return fmt.Errorf("cannot initialize storage: [%w]", err)
-		}
-
-		beaconKeyStorePersistence, err := storage.InitializeKeyStorePersistence(
+		beaconKeyStorePersistence, err := clientStorage.InitializeKeyStorePersistence(
 			"beacon",
 		)
 		if err != nil {
@@ -132,7 +166,7 @@ func start(cmd *cobra.Command) error {
 			)
 		}
 
-		tbtcKeyStorePersistence, err := storage.InitializeKeyStorePersistence(
+		tbtcKeyStorePersistence, err := clientStorage.InitializeKeyStorePersistence(
 			"tbtc",
 		)
 		if err != nil {
@@ -142,7 +176,7 @@ func start(cmd *cobra.Command) error {
 			)
 		}
 
-		tbtcDataPersistence, err := storage.InitializeWorkPersistence("tbtc")
+		tbtcDataPersistence, err := clientStorage.InitializeWorkPersistence("tbtc")
 		if err != nil {
 			return fmt.Errorf(
 				"cannot initialize tbtc data persistence: [%w]",
@@ -150,6 +184,11 @@ func start(cmd *cobra.Command) error {
 			)
 		}
 
+		btcChain, err := connectBitcoinChain(ctx, clientConfig.Bitcoin)
+		if err != nil {
+			return err
+		}
+
 		scheduler := generator.StartScheduler()
 
 		err = beacon.Initialize(
@@ -166,6 +205,7 @@ func start(cmd *cobra.Command) error {
 		err = tbtc.Initialize(
 			ctx,
 			tbtcChain,
+			btcChain,
 			netProvider,
 			tbtcKeyStorePersistence,
 			tbtcDataPersistence,
@@ -188,6 +228,7 @@ func initializeClientInfo(
 	netProvider net.Provider,
 	signing chain.Signing,
 	blockCounter chain.BlockCounter,
+	retransmissionTicker *retransmission.Ticker,
 ) *clientinfo.Registry {
 	registry, isConfigured := clientinfo.Initialize(ctx, config.ClientInfo.Port)
 	if !isConfigured {
@@ -206,6 +247,16 @@ func initializeClientInfo(
 		config.ClientInfo.NetworkMetricsTick,
 	)
 
+	registry.ObserveRetransmissionCache(
+		retransmissionTicker.CacheMetrics(),
+		config.ClientInfo.NetworkMetricsTick,
+	)
+
+	registry.ObserveNetworkTraffic(
+		netProvider,
+		config.ClientInfo.NetworkMetricsTick,
+	)
+
 	registry.ObserveEthConnectivity(
 		blockCounter,
 		config.ClientInfo.EthereumMetricsTick,
@@ -220,6 +271,8 @@ func initializeClientInfo(
 		build.Version,
 		build.Revision,
 	)
+	registry.RegisterNetworkTrafficSource(netProvider)
+	registry.RegisterPeerScoresSource(netProvider)
 
 	logger.Infof(
 		"enabled client info endpoint on port [%v]",
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/storage"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// StorageBackupPasswordEnvVariable is the environment variable holding the
+// password used to encrypt and decrypt storage backup archives.
+const StorageBackupPasswordEnvVariable = "KEEP_STORAGE_BACKUP_PASSWORD"
+
+// StorageNewPasswordEnvVariable is the environment variable holding the new
+// storage encryption password used by the password rotation.
+const StorageNewPasswordEnvVariable = "KEEP_STORAGE_NEW_PASSWORD"
+
+// StorageCommand contains the definition of the storage command-line
+// subcommand and its own subcommands.
+var StorageCommand = &cobra.Command{
+	Use:   "storage",
+	Short: "Manages the client's storage",
+	Long:  storageDescription,
+}
+
+var storageDescription = fmt.Sprintf(`The storage command allows managing the client's
+storage directory holding key shares and other sensitive data.
+
+Backup archives hold all files of the keystore, work and quarantine
+directories along with a manifest describing them. Archives are encrypted
+with a password read from the %s environment
+variable or provided in the prompt. Files in the archive stay encrypted with the storage
+encryption password, so the same storage password must be used when the
+archive is restored.
+
+See the subcommand help for additional details.`, StorageBackupPasswordEnvVariable)
+
+var storageBackupCommand = &cobra.Command{
+	Use:   "backup [archive-file]",
+	Short: "Backs up the storage to an encrypted archive",
+	Long: `Backs up the storage to an encrypted archive. The backup is refused
+if the client is running against the storage as files could change while
+they are archived.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archivePath := filepath.Clean(args[0])
+
+		if _, err := os.Stat(archivePath); err == nil {
+			return fmt.Errorf("archive file [%s] already exists", archivePath)
+		}
+
+		clientStorage, err := initializeStorage()
+		if err != nil {
+			return err
+		}
+
+		password, err := readSecret(
+			"storage backup",
+			StorageBackupPasswordEnvVariable,
+			true,
+		)
+		if err != nil {
+			return err
+		}
+
+		archiveFile, err := os.OpenFile(
+			archivePath,
+			os.O_CREATE|os.O_EXCL|os.O_WRONLY,
+			0600,
+		)
+		if err != nil {
+			return fmt.Errorf("cannot create archive file: [%w]", err)
+		}
+
+		manifest, err := clientStorage.Backup(archiveFile, password)
+		if closeErr := archiveFile.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("cannot close archive file: [%w]", closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(archivePath)
+			return err
+		}
+
+		fmt.Printf("storage backed up to [%s]\n", archivePath)
+		printBackupManifest(manifest)
+
+		return nil
+	},
+}
+
+var storageRestoreCommand = &cobra.Command{
+	Use:   "restore [archive-file]",
+	Short: "Restores the storage from an encrypted archive",
+	Long: `Restores the storage from an encrypted archive. The restore is refused
+if the client is running against the storage. The archive is validated
+before any file is written: all files must be decryptable with the storage
+encryption password, tBTC wallet files must hold valid signers, and files
+already present in the storage must not be newer than those in the archive.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archiveFile, err := os.Open(filepath.Clean(args[0]))
+		if err != nil {
+			return fmt.Errorf("cannot open archive file: [%w]", err)
+		}
+		defer archiveFile.Close()
+
+		clientStorage, err := initializeStorage()
+		if err != nil {
+			return err
+		}
+
+		password, err := readSecret(
+			"storage backup",
+			StorageBackupPasswordEnvVariable,
+			false,
+		)
+		if err != nil {
+			return err
+		}
+
+		manifest, err := clientStorage.Restore(
+			archiveFile,
+			password,
+			map[string]func(content []byte) error{
+				"tbtc": tbtc.ValidateStoredSigner,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("cannot restore storage: [%w]", err)
+		}
+
+		fmt.Printf("storage restored from [%s]\n", args[0])
+		printBackupManifest(manifest)
+
+		return nil
+	},
+}
+
+var storageRotatePasswordCommand = &cobra.Command{
+	Use:   "rotate-password",
+	Short: "Re-encrypts the storage with a new password",
+	Long: fmt.Sprintf(`Re-encrypts all files of the keystore, work and quarantine directories
+with a new storage encryption password. The new password is read from the %s
+environment variable or provided in the prompt. The rotation is refused if
+the client is running against the storage.
+
+The client cannot use the storage until the rotation completes. If the
+rotation is interrupted, run the command again with the same current and new
+passwords to resume it. Once the rotation completes, set the new password as
+the storage encryption password in the config file or the %s
+environment variable.`,
+		StorageNewPasswordEnvVariable,
+		config.StoragePasswordEnvVariable,
+	),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clientStorage, err := initializeStorage()
+		if err != nil {
+			return err
+		}
+
+		newPassword, err := readSecret(
+			"new storage",
+			StorageNewPasswordEnvVariable,
+			true,
+		)
+		if err != nil {
+			return err
+		}
+
+		progress, err := clientStorage.RotatePassword(
+			newPassword,
+			func(progress storage.PasswordRotationProgress) {
+				processed := progress.RotatedFiles + progress.SkippedFiles
+				if processed%100 == 0 || processed == progress.TotalFiles {
+					fmt.Printf(
+						"processed [%v] of [%v] files\n",
+						processed,
+						progress.TotalFiles,
+					)
+				}
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("cannot rotate storage password: [%w]", err)
+		}
+
+		fmt.Printf(
+			"storage password rotated; re-encrypted [%v] files, "+
+				"[%v] files were already re-encrypted\n",
+			progress.RotatedFiles,
+			progress.SkippedFiles,
+		)
+		fmt.Printf(
+			"set the new password as the storage encryption password "+
+				"in the config file or the %s environment variable\n",
+			config.StoragePasswordEnvVariable,
+		)
+
+		return nil
+	},
+}
+
+func init() {
+	for _, command := range []*cobra.Command{
+		storageBackupCommand,
+		storageRestoreCommand,
+		storageRotatePasswordCommand,
+	} {
+		initFlags(command, &configFilePath, clientConfig, config.StorageCategories...)
+
+		command.PreRun = func(cmd *cobra.Command, args []string) {
+			if err := clientConfig.ReadConfig(
+				configFilePath,
+				cmd.Flags(),
+				config.StorageCategories...,
+			); err != nil {
+				logger.Fatalf("error reading config: %v", err)
+			}
+		}
+
+		StorageCommand.AddCommand(command)
+	}
+}
+
+// initializeStorage initializes the client's storage using the configured
+// directory and encryption password.
+func initializeStorage() (*storage.Storage, error) {
+	clientStorage, err := storage.Initialize(clientConfig.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("cannot initialize storage: [%w]", err)
+	}
+
+	return &clientStorage, nil
+}
+
+// printBackupManifest prints the content of the given backup manifest.
+func printBackupManifest(manifest *storage.BackupManifest) {
+	fmt.Printf(
+		"  client version:  %s (%s)\n",
+		manifest.ClientVersion,
+		manifest.ClientRevision,
+	)
+	fmt.Printf(
+		"  created at:      %s\n",
+		manifest.CreatedAt.Format(time.RFC3339),
+	)
+	fmt.Printf("  files:           %v\n", manifest.FilesCount)
+	fmt.Printf("  tBTC wallets:    %v\n", len(manifest.KeyStore["tbtc"]))
+	fmt.Printf("  beacon groups:   %v\n", len(manifest.KeyStore["beacon"]))
+	fmt.Printf("  pre-params:      %v\n", manifest.Work["tbtc"]["preparams"])
+	fmt.Printf("  quarantined:     %v\n", manifest.Quarantine)
+
+	persistenceNames := make([]string, 0, len(manifest.KeyStore))
+	for persistenceName := range manifest.KeyStore {
+		persistenceNames = append(persistenceNames, persistenceName)
+	}
+	sort.Strings(persistenceNames)
+
+	for _, persistenceName := range persistenceNames {
+		for _, dir := range manifest.KeyStore[persistenceName] {
+			fmt.Printf("    %s/%s\n", persistenceName, dir)
+		}
+	}
+}
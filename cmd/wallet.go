@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/spf13/cobra"
+
+	commonEthereum "github.com/keep-network/keep-common/pkg/chain/ethereum"
+	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/config"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// WalletBackupPasswordEnvVariable is the environment variable holding the
+// password used to encrypt exported wallet backups.
+const WalletBackupPasswordEnvVariable = "KEEP_WALLET_BACKUP_PASSWORD"
+
+// WalletCommand contains the definition of the wallet command-line
+// subcommand and its own subcommands.
+var WalletCommand = &cobra.Command{
+	Use:   "wallet",
+	Short: "Inspects tBTC wallets held by the client",
+	Long:  walletDescription,
+}
+
+const walletDescription = `The wallet command allows inspecting tBTC wallets
+whose key shares are held in the client's storage. The storage is read
+directly from disk, so the commands can be used while the client is stopped.
+The storage is decrypted with the Ethereum account password.
+
+See the subcommand help for additional details.`
+
+var walletListCommand = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all wallets held in the storage",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storedWallets, err := readStoredWallets()
+		if err != nil {
+			return err
+		}
+
+		if len(storedWallets) == 0 {
+			fmt.Println("no wallets found in the storage")
+			return nil
+		}
+
+		for _, storedWallet := range storedWallets {
+			printStoredWallet(storedWallet, false)
+		}
+
+		return nil
+	},
+}
+
+var walletShowCommand = &cobra.Command{
+	Use:   "show [wallet-public-key-hash]",
+	Short: "Shows details of a single wallet held in the storage",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storedWallet, err := findStoredWallet(args[0])
+		if err != nil {
+			return err
+		}
+
+		printStoredWallet(storedWallet, true)
+
+		return nil
+	},
+}
+
+var walletExportCommand = &cobra.Command{
+	Use:   "export [wallet-public-key-hash]",
+	Short: "Exports an encrypted backup of a single wallet's key shares",
+	Long: fmt.Sprintf(`Exports an encrypted backup of a single wallet's key shares.
+The backup is written to the output directory in the same layout as the
+client's tbtc keystore directory. The backup is encrypted with a password
+read from the %s environment variable or
+provided in the prompt.`, WalletBackupPasswordEnvVariable),
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputDir, err := cmd.Flags().GetString(walletExportOutputFlag)
+		if err != nil {
+			return err
+		}
+
+		storedWallet, err := findStoredWallet(args[0])
+		if err != nil {
+			return err
+		}
+
+		password, err := readSecret(
+			"wallet backup",
+			WalletBackupPasswordEnvVariable,
+			true,
+		)
+		if err != nil {
+			return err
+		}
+
+		outputDir = filepath.Clean(outputDir)
+		if err := persistence.EnsureDirectoryExists(
+			filepath.Dir(outputDir),
+			filepath.Base(outputDir),
+		); err != nil {
+			return fmt.Errorf("cannot create output directory: [%w]", err)
+		}
+
+		diskHandle, err := persistence.NewProtectedDiskHandle(outputDir)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot create [%s] disk handle: [%w]",
+				outputDir,
+				err,
+			)
+		}
+
+		err = storedWallet.Export(
+			persistence.NewEncryptedProtectedPersistence(diskHandle, password),
+		)
+		if err != nil {
+			return fmt.Errorf("cannot export wallet: [%w]", err)
+		}
+
+		fmt.Printf(
+			"exported [%v] key shares of wallet [0x%x] to [%s]\n",
+			len(storedWallet.MemberIndexes()),
+			storedWallet.PublicKeyHash(),
+			outputDir,
+		)
+
+		return nil
+	},
+}
+
+const walletExportOutputFlag = "output"
+
+var walletVerifyCommand = &cobra.Command{
+	Use:   "verify [wallet-public-key-hash]",
+	Short: "Verifies key shares of wallets held in the storage",
+	Long: `Verifies that every key share held in the storage is consistent and
+reconstructs the declared wallet public key. All wallets are verified if no
+wallet public key hash is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var storedWallets []*tbtc.StoredWallet
+		if len(args) == 1 {
+			storedWallet, err := findStoredWallet(args[0])
+			if err != nil {
+				return err
+			}
+
+			storedWallets = append(storedWallets, storedWallet)
+		} else {
+			var err error
+			storedWallets, err = readStoredWallets()
+			if err != nil {
+				return err
+			}
+		}
+
+		invalidWallets := 0
+		for _, storedWallet := range storedWallets {
+			if err := storedWallet.VerifyShares(); err != nil {
+				invalidWallets++
+				fmt.Printf(
+					"wallet [0x%x]: INVALID\n%v\n",
+					storedWallet.PublicKeyHash(),
+					err,
+				)
+				continue
+			}
+
+			fmt.Printf(
+				"wallet [0x%x]: OK (%v key shares)\n",
+				storedWallet.PublicKeyHash(),
+				len(storedWallet.MemberIndexes()),
+			)
+		}
+
+		if invalidWallets > 0 {
+			return fmt.Errorf(
+				"[%v] of [%v] wallets failed verification",
+				invalidWallets,
+				len(storedWallets),
+			)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	for _, command := range []*cobra.Command{
+		walletListCommand,
+		walletShowCommand,
+		walletExportCommand,
+		walletVerifyCommand,
+	} {
+		initFlags(command, &configFilePath, clientConfig, config.WalletCategories...)
+
+		command.PreRun = func(cmd *cobra.Command, args []string) {
+			if err := clientConfig.ReadConfig(
+				configFilePath,
+				cmd.Flags(),
+				config.WalletCategories...,
+			); err != nil {
+				logger.Fatalf("error reading config: %v", err)
+			}
+		}
+
+		WalletCommand.AddCommand(command)
+	}
+
+	walletExportCommand.Flags().String(
+		walletExportOutputFlag,
+		"",
+		"Directory the wallet backup is written to.",
+	)
+	if err := walletExportCommand.MarkFlagRequired(
+		walletExportOutputFlag,
+	); err != nil {
+		logger.Panicf("cannot mark output flag as required: [%v]", err)
+	}
+}
+
+// readStoredWallets reads all wallets held in the tbtc keystore. Signer files
+// that could not be read are reported as warnings.
+func readStoredWallets() ([]*tbtc.StoredWallet, error) {
+	clientStorage, err := initializeStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	tbtcKeyStorePersistence, err := clientStorage.InitializeKeyStorePersistence(
+		"tbtc",
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot initialize tbtc keystore persistence: [%w]",
+			err,
+		)
+	}
+
+	storedWallets, errs := tbtc.ReadStoredWallets(tbtcKeyStorePersistence)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+
+	return storedWallets, nil
+}
+
+// findStoredWallet finds the wallet with the given hex-encoded 20-byte
+// public key hash in the tbtc keystore.
+func findStoredWallet(walletPublicKeyHashString string) (
+	*tbtc.StoredWallet,
+	error,
+) {
+	walletPublicKeyHash, err := hex.DecodeString(
+		strings.TrimPrefix(walletPublicKeyHashString, "0x"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode wallet public key hash: [%w]", err)
+	}
+
+	if len(walletPublicKeyHash) != 20 {
+		return nil, fmt.Errorf(
+			"wallet public key hash must be 20 bytes long; got [%v]",
+			len(walletPublicKeyHash),
+		)
+	}
+
+	storedWallets, err := readStoredWallets()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, storedWallet := range storedWallets {
+		publicKeyHash := storedWallet.PublicKeyHash()
+		if bytes.Equal(publicKeyHash[:], walletPublicKeyHash) {
+			return storedWallet, nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"wallet [0x%x] not found in the storage",
+		walletPublicKeyHash,
+	)
+}
+
+// printStoredWallet prints details of the given wallet. If the details flag
+// is set, the storage key and operators of all signing group seats are
+// printed as well.
+func printStoredWallet(storedWallet *tbtc.StoredWallet, details bool) {
+	publicKey := storedWallet.PublicKey()
+	publicKeyHash := storedWallet.PublicKeyHash()
+
+	address, err := btcutil.NewAddressWitnessPubKeyHash(
+		publicKeyHash[:],
+		walletBitcoinNetworkParams(),
+	)
+	addressString := "unknown"
+	if err != nil {
+		logger.Errorf("cannot compute wallet P2WPKH address: [%v]", err)
+	} else {
+		addressString = address.EncodeAddress()
+	}
+
+	fmt.Printf("wallet [0x%x]\n", publicKeyHash)
+	fmt.Printf(
+		"  public key:      0x%x\n",
+		elliptic.Marshal(publicKey.Curve, publicKey.X, publicKey.Y),
+	)
+	fmt.Printf("  P2WPKH address:  %s\n", addressString)
+	fmt.Printf("  member indexes:  %v\n", storedWallet.MemberIndexes())
+
+	operators := storedWallet.SigningGroupOperators()
+
+	if !details {
+		fmt.Printf("  signing group:   %v\n\n", operators)
+		return
+	}
+
+	fmt.Printf("  storage key:     %s\n", storedWallet.StorageKey())
+	fmt.Printf("  signing group:\n")
+	for i, operator := range operators {
+		fmt.Printf("    [%v] %s\n", i+1, operator)
+	}
+}
+
+// walletBitcoinNetworkParams returns the parameters of the Bitcoin network
+// the wallets are expected to live on, based on the Ethereum network the
+// client is configured for.
+func walletBitcoinNetworkParams() *chaincfg.Params {
+	if clientConfig.Ethereum.Network == commonEthereum.Mainnet {
+		return &chaincfg.MainNetParams
+	}
+
+	return &chaincfg.TestNet3Params
+}
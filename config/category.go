@@ -5,7 +5,7 @@ type Category int
 const (
 	General Category = iota
 	Ethereum
-	BitcoinElectrum
+	Bitcoin
 	Network
 	Storage
 	ClientInfo
@@ -18,6 +18,7 @@ const (
 var StartCmdCategories = []Category{
 	General,
 	Ethereum,
+	Bitcoin,
 	Network,
 	Storage,
 	ClientInfo,
@@ -28,15 +29,41 @@ var StartCmdCategories = []Category{
 // MaintainerCategories are categories needed for the maintainer command.
 var MaintainerCategories = []Category{
 	Ethereum,
-	BitcoinElectrum,
+	Bitcoin,
+	ClientInfo,
 	Maintainer,
 }
 
+// WalletCategories are categories needed for the wallet command.
+var WalletCategories = []Category{
+	Ethereum,
+	Storage,
+}
+
+// StorageCategories are categories needed for the storage command.
+var StorageCategories = []Category{
+	Ethereum,
+	Storage,
+}
+
+// PreParamsCategories are categories needed for the preparams command.
+var PreParamsCategories = []Category{
+	Ethereum,
+	Storage,
+	Tbtc,
+}
+
+// JournalCategories are categories needed for the journal command.
+var JournalCategories = []Category{
+	Ethereum,
+	Storage,
+}
+
 // AllCategories are all available categories.
 var AllCategories = []Category{
 	General,
 	Ethereum,
-	BitcoinElectrum,
+	Bitcoin,
 	Network,
 	Storage,
 	ClientInfo,
@@ -14,6 +14,7 @@ import (
 	"golang.org/x/term"
 
 	commonEthereum "github.com/keep-network/keep-common/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/bitcoin/bitcoind"
 	"github.com/keep-network/keep-core/pkg/bitcoin/electrum"
 	"github.com/keep-network/keep-core/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/maintainer"
@@ -30,6 +31,16 @@ const (
 	// It's just the name of the environment variable.
 	EthereumPasswordEnvVariable = "KEEP_ETHEREUM_PASSWORD"
 
+	// #nosec G101 (look for hardcoded credentials)
+	// This line doesn't contain any credentials.
+	// It's just the name of the environment variable.
+	StoragePasswordEnvVariable = "KEEP_STORAGE_PASSWORD"
+
+	// #nosec G101 (look for hardcoded credentials)
+	// This line doesn't contain any credentials.
+	// It's just the name of the environment variable.
+	PreParamsWorkerSecretEnvVariable = "KEEP_PREPARAMS_WORKER_SECRET"
+
 	// LogLevelEnvVariable can be used to define logging configuration.
 	LogLevelEnvVariable = "LOG_LEVEL"
 )
@@ -45,10 +56,29 @@ type Config struct {
 	Tbtc       tbtc.Config
 }
 
+// Bitcoin chain backends the client can use.
+const (
+	ElectrumBitcoinBackend = "electrum"
+	BitcoindBitcoinBackend = "bitcoind"
+)
+
 // BitcoinConfig defines the configuration for Bitcoin.
 type BitcoinConfig struct {
+	// Backend determines the Bitcoin chain backend used by the client, either
+	// `electrum` or `bitcoind`. Electrum is used if the backend is not set.
+	// The bitcoind backend does not support transaction history lookups
+	// required by the SPV maintainer.
+	Backend string
 	// Electrum defines the configuration for the Electrum client.
 	Electrum electrum.Config
+	// Bitcoind defines the configuration for the bitcoind client.
+	Bitcoind bitcoind.Config
+}
+
+// SupportsTransactionsHistory returns true if the configured Bitcoin chain
+// backend can look up transactions history of public key hashes.
+func (bc *BitcoinConfig) SupportsTransactionsHistory() bool {
+	return bc.Backend != BitcoindBitcoinBackend
 }
 
 // Bind the flags to the viper configuration. Viper reads configuration from
@@ -127,6 +157,9 @@ func (c *Config) ReadConfig(configFilePath string, flagSet *pflag.FlagSet, categ
 		return fmt.Errorf("failed to resolve peers: %w", err)
 	}
 
+	// Resolve tBTC protocol timing.
+	c.resolveTbtcProtocolTiming()
+
 	// Validate configuration.
 	if err := validateConfig(c, categories...); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
@@ -158,6 +191,21 @@ func (c *Config) ReadConfig(configFilePath string, flagSet *pflag.FlagSet, categ
 		c.Ethereum.Account.KeyFilePassword = password
 	}
 
+	if c.Storage.EncryptionPassword == "" {
+		c.Storage.EncryptionPassword = os.Getenv(StoragePasswordEnvVariable)
+	}
+
+	// The storage used to be encrypted with the Ethereum account password
+	// before the separate storage password was introduced. Fall back to it
+	// so storages of existing clients remain readable.
+	if strings.TrimSpace(c.Storage.EncryptionPassword) == "" {
+		c.Storage.EncryptionPassword = c.Ethereum.Account.KeyFilePassword
+	}
+
+	if c.Tbtc.PreParamsWorkerSecret == "" {
+		c.Tbtc.PreParamsWorkerSecret = os.Getenv(PreParamsWorkerSecretEnvVariable)
+	}
+
 	return nil
 }
 
@@ -178,10 +226,26 @@ func validateConfig(config *Config, categories ...Category) error {
 					"missing value for ethereum.keyFile; see ethereum section in configuration",
 				))
 			}
-		case BitcoinElectrum:
-			if config.Bitcoin.Electrum.URL == "" {
+		case Bitcoin:
+			switch config.Bitcoin.Backend {
+			case "", ElectrumBitcoinBackend:
+				if config.Bitcoin.Electrum.URL == "" {
+					result = multierror.Append(result, fmt.Errorf(
+						"missing value for bitcoin.electrum.url; see bitcoin electrum section in configuration",
+					))
+				}
+			case BitcoindBitcoinBackend:
+				if config.Bitcoin.Bitcoind.URL == "" {
+					result = multierror.Append(result, fmt.Errorf(
+						"missing value for bitcoin.bitcoind.url; see bitcoin bitcoind section in configuration",
+					))
+				}
+			default:
 				result = multierror.Append(result, fmt.Errorf(
-					"missing value for bitcoin.electrum.url; see bitcoin electrum section in configuration",
+					"unsupported value for bitcoin.backend: [%s]; expected one of: [%s, %s]",
+					config.Bitcoin.Backend,
+					ElectrumBitcoinBackend,
+					BitcoindBitcoinBackend,
 				))
 			}
 		case Network:
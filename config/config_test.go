@@ -67,11 +67,12 @@ func TestReadConfigFromFile(t *testing.T) {
 		"Ethereum.Developer - map": {
 			readValueFunc: func(c *Config) interface{} { return c.Ethereum.ContractAddresses },
 			expectedValue: map[string]string{
-				"randombeacon":   "0xcf64c2a367341170cb4e09cf8c0ed137d8473ceb",
-				"walletregistry": "0x143ba24e66fce8bca22f7d739f9a932c519b1c76",
-				"tokenstaking":   "0xa363a197f1bbb8877f50350234e3f15fb4175457",
-				"bridge":         "0x138D2a0c87BA9f6BE1DCc13D6224A6aCE9B6b6F0",
-				"lightrelay":     "0x68e20afD773fDF1231B5cbFeA7040e73e79cAc36",
+				"randombeacon":      "0xcf64c2a367341170cb4e09cf8c0ed137d8473ceb",
+				"walletregistry":    "0x143ba24e66fce8bca22f7d739f9a932c519b1c76",
+				"tokenstaking":      "0xa363a197f1bbb8877f50350234e3f15fb4175457",
+				"bridge":            "0x138D2a0c87BA9f6BE1DCc13D6224A6aCE9B6b6F0",
+				"lightrelay":        "0x68e20afD773fDF1231B5cbFeA7040e73e79cAc36",
+				"ecdsadkgvalidator": "0x0125c8977a02b2fa3970b1ed9af02f5bedd4ef27",
 			},
 		},
 		"Developer - RandomBeacon": {
@@ -111,10 +112,30 @@ func TestReadConfigFromFile(t *testing.T) {
 			},
 			expectedValue: "0x68e20afD773fDF1231B5cbFeA7040e73e79cAc36",
 		},
+		"Ethereum.Developer - EcdsaDkgValidator": {
+			readValueFunc: func(c *Config) interface{} {
+				address, _ := c.Ethereum.ContractAddress(
+					ethereum.EcdsaDkgValidatorContractName,
+				)
+				return address.String()
+			},
+			expectedValue: "0x0125c8977a02b2Fa3970b1ED9AF02f5Bedd4eF27",
+		},
+		"Bitcoin.Backend": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Backend },
+			expectedValue: "electrum",
+		},
 		"Bitcoin.Electrum.URL": {
 			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Electrum.URL },
 			expectedValue: "url.to.electrum:18332",
 		},
+		"Bitcoin.Electrum.FallbackURLs": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Electrum.FallbackURLs },
+			expectedValue: []string{
+				"fallback1.url.to.electrum:18332",
+				"fallback2.url.to.electrum:18332",
+			},
+		},
 		"Bitcoin.Electrum.Protocol": {
 			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Electrum.Protocol },
 			expectedValue: electrum.SSL,
@@ -139,6 +160,30 @@ func TestReadConfigFromFile(t *testing.T) {
 			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Electrum.KeepAliveInterval },
 			expectedValue: 720 * time.Second,
 		},
+		"Bitcoin.Electrum.MaxBlockHeightDeviation": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Electrum.MaxBlockHeightDeviation },
+			expectedValue: uint(5),
+		},
+		"Bitcoin.Bitcoind.URL": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.URL },
+			expectedValue: "http://url.to.bitcoind:18332",
+		},
+		"Bitcoin.Bitcoind.Username": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.Username },
+			expectedValue: "bitcoind-user",
+		},
+		"Bitcoin.Bitcoind.Password": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.Password },
+			expectedValue: "bitcoind-password",
+		},
+		"Bitcoin.Bitcoind.RequestTimeout": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.RequestTimeout },
+			expectedValue: 45 * time.Second,
+		},
+		"Bitcoin.Bitcoind.RequestRetryTimeout": {
+			readValueFunc: func(c *Config) interface{} { return c.Bitcoin.Bitcoind.RequestRetryTimeout },
+			expectedValue: 180 * time.Second,
+		},
 		"Network.Port": {
 			readValueFunc: func(c *Config) interface{} { return c.LibP2P.Port },
 			expectedValue: 27001,
@@ -165,6 +210,10 @@ func TestReadConfigFromFile(t *testing.T) {
 			readValueFunc: func(c *Config) interface{} { return c.Storage.Dir },
 			expectedValue: "/my/secure/location",
 		},
+		"Storage.EncryptionPassword": {
+			readValueFunc: func(c *Config) interface{} { return c.Storage.EncryptionPassword },
+			expectedValue: "storage-password",
+		},
 		"ClientInfo.Port": {
 			readValueFunc: func(c *Config) interface{} { return c.ClientInfo.Port },
 			expectedValue: 3498,
@@ -181,6 +230,10 @@ func TestReadConfigFromFile(t *testing.T) {
 			readValueFunc: func(c *Config) interface{} { return c.Maintainer.BitcoinDifficulty },
 			expectedValue: true,
 		},
+		"Maintainer.Spv": {
+			readValueFunc: func(c *Config) interface{} { return c.Maintainer.Spv },
+			expectedValue: true,
+		},
 	}
 
 	for _, filePath := range filePaths {
@@ -278,6 +331,63 @@ func TestReadConfig_ReadPassword(t *testing.T) {
 	}
 }
 
+func TestReadConfig_ReadStoragePassword(t *testing.T) {
+	var configReadTests = map[string]struct {
+		configFilePath      string
+		envVariablePassword string
+		expectedPassword    string
+	}{
+		"password in file; password in environment variable": {
+			configFilePath:      "../test/config.toml",
+			envVariablePassword: "storage-SECRET-as-ENVIRONMENT-VARIABLE",
+			expectedPassword:    "storage-password",
+		},
+		"no password in file; password in environment variable": {
+			configFilePath:      "../test/config_no_password.toml",
+			envVariablePassword: "storage-SECRET-as-ENVIRONMENT-VARIABLE",
+			expectedPassword:    "storage-SECRET-as-ENVIRONMENT-VARIABLE",
+		},
+		"no password in file; no password in environment variable": {
+			configFilePath:      "../test/config_no_password.toml",
+			envVariablePassword: "",
+			// Falls back to the Ethereum account password.
+			expectedPassword: "ethereum-SECRET-as-ENVIRONMENT-VARIABLE",
+		},
+	}
+
+	for testName, test := range configReadTests {
+		t.Run(testName, func(t *testing.T) {
+			if err := os.Setenv(
+				EthereumPasswordEnvVariable,
+				"ethereum-SECRET-as-ENVIRONMENT-VARIABLE",
+			); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Setenv(
+				StoragePasswordEnvVariable,
+				test.envVariablePassword,
+			); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Unsetenv(StoragePasswordEnvVariable)
+
+			cfg := &Config{}
+			err := cfg.ReadConfig(test.configFilePath, nil, AllCategories...)
+			if err != nil {
+				t.Fatalf("failed to read test config: [%v]", err)
+			}
+
+			if cfg.Storage.EncryptionPassword != test.expectedPassword {
+				t.Errorf(
+					"\nexpected: %s\nactual:   %s",
+					test.expectedPassword,
+					cfg.Storage.EncryptionPassword,
+				)
+			}
+		})
+	}
+}
+
 func TestReadConfig_ReadContracts(t *testing.T) {
 	if err := os.Setenv(EthereumPasswordEnvVariable, "password from env var"); err != nil {
 		t.Fatal(err)
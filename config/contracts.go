@@ -41,6 +41,7 @@ func initializeContractAddressesAliases() {
 	aliasEthereumContract(chainEthereum.WalletRegistryContractName)
 	aliasEthereumContract(chainEthereum.BridgeContractName)
 	aliasEthereumContract(chainEthereum.LightRelayContractName)
+	aliasEthereumContract(chainEthereum.EcdsaDkgValidatorContractName)
 }
 
 // resolveContractsAddresses verifies if contracts addresses are configured, if not
@@ -69,6 +70,10 @@ func (c *Config) resolveContractsAddresses() {
 		chainEthereum.WalletRegistryContractName,
 		ethereumEcdsa.WalletRegistryAddress,
 	)
+	resolveContractAddress(
+		chainEthereum.EcdsaDkgValidatorContractName,
+		ethereumEcdsa.EcdsaDkgValidatorAddress,
+	)
 	resolveContractAddress(
 		chainEthereum.BridgeContractName,
 		ethereumTbtc.BridgeAddress,
@@ -0,0 +1,25 @@
+package config
+
+import (
+	commonEthereum "github.com/keep-network/keep-common/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+// resolveTbtcProtocolTiming fills the tBTC protocol timing values that were
+// not set in the config file with the values from the protocol timing profile
+// of the given ethereum network. Values explicitly set to zero are kept. The
+// mainnet profile is used for unknown networks.
+func (c *Config) resolveTbtcProtocolTiming() {
+	var profile tbtc.ProtocolTiming
+
+	switch c.Ethereum.Network {
+	case commonEthereum.Goerli:
+		profile = tbtc.TestnetProtocolTiming
+	case commonEthereum.Developer:
+		profile = tbtc.DeveloperProtocolTiming
+	default:
+		profile = tbtc.MainnetProtocolTiming
+	}
+
+	c.Tbtc.ProtocolTiming = c.Tbtc.ProtocolTiming.WithDefaults(profile).Config()
+}
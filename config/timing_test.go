@@ -0,0 +1,95 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	commonEthereum "github.com/keep-network/keep-common/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+func TestResolveTbtcProtocolTiming(t *testing.T) {
+	customTimingFn := func(base tbtc.ProtocolTiming) tbtc.ProtocolTiming {
+		base.DkgStartedConfirmationBlocks = 33
+		base.SigningAttemptMaximumProtocolBlocks = 44
+		return base
+	}
+
+	zeroTimingFn := func(base tbtc.ProtocolTiming) tbtc.ProtocolTiming {
+		base.SigningBatchInterludeBlocks = 0
+		return base
+	}
+
+	uint64Ptr := func(value uint64) *uint64 {
+		return &value
+	}
+
+	var tests = map[string]struct {
+		network                commonEthereum.Network
+		configuredTiming       tbtc.ProtocolTimingConfig
+		expectedProtocolTiming tbtc.ProtocolTiming
+	}{
+		"mainnet network": {
+			network:                commonEthereum.Mainnet,
+			expectedProtocolTiming: tbtc.MainnetProtocolTiming,
+		},
+		"goerli network": {
+			network:                commonEthereum.Goerli,
+			expectedProtocolTiming: tbtc.TestnetProtocolTiming,
+		},
+		"developer network": {
+			network:                commonEthereum.Developer,
+			expectedProtocolTiming: tbtc.DeveloperProtocolTiming,
+		},
+		"unknown network": {
+			network:                commonEthereum.Unknown,
+			expectedProtocolTiming: tbtc.MainnetProtocolTiming,
+		},
+		"developer network with overridden values": {
+			network: commonEthereum.Developer,
+			configuredTiming: tbtc.ProtocolTimingConfig{
+				DkgStartedConfirmationBlocks:        uint64Ptr(33),
+				SigningAttemptMaximumProtocolBlocks: uint64Ptr(44),
+			},
+			expectedProtocolTiming: customTimingFn(
+				tbtc.DeveloperProtocolTiming,
+			),
+		},
+		"developer network with value explicitly set to zero": {
+			network: commonEthereum.Developer,
+			configuredTiming: tbtc.ProtocolTimingConfig{
+				SigningBatchInterludeBlocks: uint64Ptr(0),
+			},
+			expectedProtocolTiming: zeroTimingFn(
+				tbtc.DeveloperProtocolTiming,
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Ethereum.Network = test.network
+			cfg.Tbtc.ProtocolTiming = test.configuredTiming
+
+			cfg.resolveTbtcProtocolTiming()
+
+			// All values are resolved so the defaults passed here
+			// must not be used.
+			actualProtocolTiming := cfg.Tbtc.ProtocolTiming.WithDefaults(
+				tbtc.ProtocolTiming{},
+			)
+
+			if !reflect.DeepEqual(
+				test.expectedProtocolTiming,
+				actualProtocolTiming,
+			) {
+				t.Errorf(
+					"unexpected protocol timing\nexpected: %+v\nactual:   %+v\n",
+					test.expectedProtocolTiming,
+					actualProtocolTiming,
+				)
+			}
+		})
+	}
+}
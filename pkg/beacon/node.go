@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"sync"
 
 	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
 	"go.uber.org/zap"
@@ -132,6 +133,7 @@ func (n *node) JoinDKGIfEligible(
 			selectedOperators,
 			signing,
 		)
+		membershipValidator.SetPeerReputation(n.netProvider.PeerReputation())
 
 		err = broadcastChannel.SetFilter(membershipValidator.IsInGroup)
 		if err != nil {
@@ -142,12 +144,23 @@ func (n *node) JoinDKGIfEligible(
 			)
 		}
 
+		// The broadcast channel is temporary so close it once all members
+		// controlled by this node are done.
+		membersWg := &sync.WaitGroup{}
+		membersWg.Add(len(indexes))
+		go func() {
+			membersWg.Wait()
+			n.netProvider.CloseBroadcastChannel(channelName)
+		}()
+
 		for _, index := range indexes {
 			// Capture the member index for the goroutine. The group member
 			// index should be in range [1, groupSize] so we need to add 1.
 			memberIndex := index + 1
 
 			go func() {
+				defer membersWg.Done()
+
 				n.protocolLatch.Lock()
 				defer n.protocolLatch.Unlock()
 
@@ -357,6 +370,7 @@ func (n *node) GenerateRelayEntry(
 		groupMembers,
 		n.beaconChain.Signing(),
 	)
+	membershipValidator.SetPeerReputation(n.netProvider.PeerReputation())
 
 	err = channel.SetFilter(membershipValidator.IsInGroup)
 	if err != nil {
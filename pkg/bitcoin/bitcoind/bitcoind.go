@@ -0,0 +1,798 @@
+package bitcoind
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ipfs/go-log"
+	"go.uber.org/zap"
+
+	"github.com/keep-network/keep-common/pkg/wrappers"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+var logger = log.Logger("keep-bitcoind")
+
+// rpcInvalidAddressOrKeyErrorCode is the code of the error returned by
+// bitcoind for a transaction that was not found.
+const rpcInvalidAddressOrKeyErrorCode = -5
+
+// ErrTransactionsHistoryNotSupported is returned by
+// GetTransactionsForPublicKeyHash as bitcoind does not maintain an address
+// index required to determine the transaction history of a public key hash.
+var ErrTransactionsHistoryNotSupported = errors.New(
+	"transactions history of a public key hash is not supported by bitcoind",
+)
+
+// Connection is a handle for interactions with a Bitcoin Core node (bitcoind)
+// over its JSON-RPC interface.
+//
+// Some of the bitcoin.Chain functions require specific node configuration:
+//   - GetTransaction and GetTransactionConfirmations require the transaction
+//     index to be enabled (`txindex=1`) to look up transactions that are
+//     neither in the mempool nor related to the node's wallet,
+//   - GetUnspentOutputsForScript uses the `scantxoutset` call which scans
+//     the whole UTXO set and may take a while; bitcoind runs one scan at
+//     a time so, scans of the connection are serialized; unconfirmed outputs
+//     are found only if the mempool does not exceed the configured
+//     inspection limit, see inspectMempool,
+//   - GetTransactionsForPublicKeyHash is not supported as bitcoind does not
+//     maintain an address index.
+type Connection struct {
+	parentCtx context.Context
+	client    *rpcClient
+	config    Config
+
+	// scanMutex serializes `scantxoutset` calls as bitcoind refuses to start
+	// a scan while another one is in progress.
+	scanMutex sync.Mutex
+
+	mempoolMutex sync.Mutex
+	// mempoolTransactions caches transactions seen in the mempool during
+	// previous mempool inspections, keyed by their IDs.
+	mempoolTransactions map[string]*mempoolTransaction
+}
+
+// mempoolTransaction is a mempool transaction cached by the connection.
+type mempoolTransaction struct {
+	hash        bitcoin.Hash
+	wtxid       string
+	transaction *bitcoin.Transaction
+}
+
+// Connect initializes handle with provided Config.
+func Connect(parentCtx context.Context, config Config) (bitcoin.Chain, error) {
+	if config.RequestTimeout == 0 {
+		config.RequestTimeout = DefaultRequestTimeout
+	}
+	if config.RequestRetryTimeout == 0 {
+		config.RequestRetryTimeout = DefaultRequestRetryTimeout
+	}
+	if config.MempoolInspectionLimit == 0 {
+		config.MempoolInspectionLimit = DefaultMempoolInspectionLimit
+	}
+
+	c := &Connection{
+		parentCtx: parentCtx,
+		client:    newRPCClient(config.URL, config.Username, config.Password),
+		config:    config,
+	}
+
+	if err := c.verifyServer(); err != nil {
+		return nil, fmt.Errorf("failed to verify bitcoind server: [%w]", err)
+	}
+
+	return c, nil
+}
+
+// GetTransaction gets the transaction with the given transaction hash.
+// If the transaction with the given hash was not found on the chain,
+// this function returns an error.
+func (c *Connection) GetTransaction(
+	transactionHash bitcoin.Hash,
+) (*bitcoin.Transaction, error) {
+	txID := transactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	var rawTransaction string
+	if err := c.call("getrawtransaction", &rawTransaction, txID, false); err != nil {
+		return nil, fmt.Errorf(
+			"failed to get raw transaction with ID [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	transactionBytes, err := hex.DecodeString(rawTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode a hex string: [%w]", err)
+	}
+
+	result := new(bitcoin.Transaction)
+	if err := result.Deserialize(transactionBytes); err != nil {
+		return nil, fmt.Errorf("failed to deserialize a transaction: [%w]", err)
+	}
+
+	return result, nil
+}
+
+// GetTransactionConfirmations gets the number of confirmations for the
+// transaction with the given transaction hash. If the transaction with the
+// given hash was not found on the chain, this function returns an error.
+func (c *Connection) GetTransactionConfirmations(
+	transactionHash bitcoin.Hash,
+) (uint, error) {
+	txID := transactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	// The confirmations field is not present for transactions that are
+	// still in the mempool, so it is left as zero.
+	var verboseTransaction struct {
+		Confirmations uint `json:"confirmations"`
+	}
+
+	if err := c.call(
+		"getrawtransaction",
+		&verboseTransaction,
+		txID,
+		true,
+	); err != nil {
+		return 0, fmt.Errorf(
+			"failed to get verbose transaction with ID [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	return verboseTransaction.Confirmations, nil
+}
+
+// BroadcastTransaction broadcasts the given transaction over the
+// network of the Bitcoin chain nodes. If the broadcast action could not be
+// done, this function returns an error. This function does not give any
+// guarantees regarding transaction mining. The transaction may be mined or
+// rejected eventually.
+func (c *Connection) BroadcastTransaction(
+	transaction *bitcoin.Transaction,
+) error {
+	rawTx := hex.EncodeToString(transaction.Serialize())
+
+	rawTxLogger := logger.With(
+		zap.String("rawTx", rawTx),
+	)
+	rawTxLogger.Debugf("broadcasting transaction")
+
+	var txID string
+	if err := c.call("sendrawtransaction", &txID, rawTx); err != nil {
+		return fmt.Errorf("failed to broadcast the transaction: [%w]", err)
+	}
+
+	rawTxLogger.Infof("transaction broadcast successful: [%s]", txID)
+
+	return nil
+}
+
+// GetLatestBlockHeight gets the height of the latest block (tip). If the
+// latest block was not determined, this function returns an error.
+func (c *Connection) GetLatestBlockHeight() (uint, error) {
+	var blockCount uint
+	if err := c.call("getblockcount", &blockCount); err != nil {
+		return 0, fmt.Errorf("failed to get block count: [%w]", err)
+	}
+
+	return blockCount, nil
+}
+
+// GetBlockHeader gets the block header for the given block height. If the
+// block with the given height was not found on the chain, this function
+// returns an error.
+func (c *Connection) GetBlockHeader(
+	blockHeight uint,
+) (*bitcoin.BlockHeader, error) {
+	blockHash, err := c.getBlockHash(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawBlockHeader string
+	if err := c.call(
+		"getblockheader",
+		&rawBlockHeader,
+		blockHash,
+		false,
+	); err != nil {
+		return nil, fmt.Errorf(
+			"failed to get block header [%s]: [%w]",
+			blockHash,
+			err,
+		)
+	}
+
+	blockHeaderBytes, err := hex.DecodeString(rawBlockHeader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode a hex string: [%w]", err)
+	}
+
+	if len(blockHeaderBytes) != bitcoin.BlockHeaderByteLength {
+		return nil, fmt.Errorf(
+			"unexpected block header length [%v]; expected [%v]",
+			len(blockHeaderBytes),
+			bitcoin.BlockHeaderByteLength,
+		)
+	}
+
+	var serializedBlockHeader [bitcoin.BlockHeaderByteLength]byte
+	copy(serializedBlockHeader[:], blockHeaderBytes)
+
+	result := new(bitcoin.BlockHeader)
+	result.Deserialize(serializedBlockHeader)
+
+	return result, nil
+}
+
+// GetBlockHeaders gets block headers of the given count of consecutive
+// blocks starting from the given block height. The returned headers are
+// ordered by block height in the ascending order. If any of the blocks
+// was not found on the chain, this function returns an error.
+func (c *Connection) GetBlockHeaders(
+	startHeight uint,
+	count uint,
+) ([]*bitcoin.BlockHeader, error) {
+	result := make([]*bitcoin.BlockHeader, count)
+
+	for i := uint(0); i < count; i++ {
+		blockHeader, err := c.GetBlockHeader(startHeight + i)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get block header at height [%v]: [%w]",
+				startHeight+i,
+				err,
+			)
+		}
+
+		result[i] = blockHeader
+	}
+
+	return result, nil
+}
+
+// GetTransactionMerkle gets the Merkle branch proving the inclusion of
+// the transaction with the given hash in the block with the given height.
+// If the transaction was not found in the given block, this function
+// returns an error.
+func (c *Connection) GetTransactionMerkle(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	blockHash, err := c.getBlockHash(blockHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	// bitcoind does not expose the Merkle branch of a single transaction
+	// so, it is computed from identifiers of all transactions in the block.
+	var block struct {
+		Tx []string `json:"tx"`
+	}
+
+	if err := c.call("getblock", &block, blockHash, 1); err != nil {
+		return nil, fmt.Errorf("failed to get block [%s]: [%w]", blockHash, err)
+	}
+
+	blockTransactionHashes := make([]bitcoin.Hash, len(block.Tx))
+	position := -1
+	for i, txID := range block.Tx {
+		blockTransactionHashes[i], err = bitcoin.NewHashFromString(
+			txID,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse hash of transaction [%s]: [%w]",
+				txID,
+				err,
+			)
+		}
+
+		if blockTransactionHashes[i] == transactionHash {
+			position = i
+		}
+	}
+
+	if position < 0 {
+		return nil, fmt.Errorf(
+			"transaction [%s] not found in block [%s]",
+			transactionHash.Hex(bitcoin.ReversedByteOrder),
+			blockHash,
+		)
+	}
+
+	return &bitcoin.TransactionMerkleProof{
+		BlockHeight: blockHeight,
+		MerkleNodes: computeMerkleBranch(blockTransactionHashes, position),
+		Position:    uint(position),
+	}, nil
+}
+
+// GetTransactionsForPublicKeyHash gets confirmed transactions related to
+// the given public key hash, i.e. transactions funding or spending outputs
+// locked using either a P2PKH or P2WPKH script of that hash. The
+// returned transactions are ordered by block height in the ascending
+// order, i.e. the latest transaction is at the end of the slice. The
+// limit parameter determines the maximum number of the latest
+// transactions returned.
+//
+// bitcoind does not maintain an address index so, the transaction history
+// of an arbitrary public key hash cannot be determined. This function
+// always returns ErrTransactionsHistoryNotSupported.
+func (c *Connection) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*bitcoin.Transaction, error) {
+	return nil, ErrTransactionsHistoryNotSupported
+}
+
+// GetUnspentOutputsForScript gets all unspent outputs locked using the
+// given script. Outputs of unconfirmed transactions are returned as well.
+//
+// bitcoind scans only the UTXO set of the current chain tip so, the
+// mempool is inspected separately and outputs created by mempool
+// transactions are added to the result. Each output is then checked
+// against the UTXO set including the mempool, using the `gettxout` call,
+// so outputs spent by mempool transactions are removed from the result.
+func (c *Connection) GetUnspentOutputsForScript(
+	script bitcoin.Script,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	// The mempool is inspected before the UTXO set is scanned. That way,
+	// a mempool transaction mined in the meantime is seen in both and
+	// its outputs are not missed. Duplicates are removed below.
+	mempoolOutputs, err := c.inspectMempool(script)
+	if err != nil {
+		return nil, err
+	}
+
+	unspentOutputs, err := c.scanUnspentOutputs(script)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*bitcoin.UnspentTransactionOutput, 0)
+	seenOutpoints := make(map[bitcoin.TransactionOutpoint]bool)
+
+	appendOutput := func(output *bitcoin.UnspentTransactionOutput) {
+		outpoint := *output.Outpoint
+		if seenOutpoints[outpoint] {
+			return
+		}
+
+		seenOutpoints[outpoint] = true
+		candidates = append(candidates, output)
+	}
+
+	for _, unspentOutput := range unspentOutputs {
+		transactionHash, err := bitcoin.NewHashFromString(
+			unspentOutput.TxID,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse hash of transaction [%s]: [%w]",
+				unspentOutput.TxID,
+				err,
+			)
+		}
+
+		appendOutput(&bitcoin.UnspentTransactionOutput{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: transactionHash,
+				OutputIndex:     unspentOutput.Vout,
+			},
+			Value: convertBtcToSatoshi(unspentOutput.Amount),
+		})
+	}
+
+	for _, mempoolOutput := range mempoolOutputs {
+		appendOutput(mempoolOutput)
+	}
+
+	result := make([]*bitcoin.UnspentTransactionOutput, 0)
+
+	for _, candidate := range candidates {
+		unspent, err := c.isUnspent(candidate.Outpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		if unspent {
+			result = append(result, candidate)
+		}
+	}
+
+	return result, nil
+}
+
+// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+// transaction to be confirmed within the given number of blocks. If the
+// fee could not be estimated, this function returns an error.
+func (c *Connection) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	// According to bitcoind docs, the returned fee rate is BTC/kvB. The
+	// fee rate is not present if the node does not have enough information
+	// to estimate the fee.
+	var estimation struct {
+		FeeRate *float64 `json:"feerate"`
+		Errors  []string `json:"errors"`
+	}
+
+	if err := c.call("estimatesmartfee", &estimation, blocks); err != nil {
+		return 0, fmt.Errorf("failed to estimate fee: [%w]", err)
+	}
+
+	if estimation.FeeRate == nil || *estimation.FeeRate <= 0 {
+		return 0, fmt.Errorf(
+			"fee could not be estimated; node returned errors: [%s]",
+			strings.Join(estimation.Errors, "; "),
+		)
+	}
+
+	// Round the final value up to not underestimate the fee.
+	satPerKbFee := convertBtcToSatoshi(*estimation.FeeRate)
+	satPerVByteFee := (satPerKbFee + 999) / 1000
+
+	return satPerVByteFee, nil
+}
+
+// scannedUnspentOutput is an unspent output returned by the `scantxoutset`
+// call.
+type scannedUnspentOutput struct {
+	TxID   string  `json:"txid"`
+	Vout   uint32  `json:"vout"`
+	Amount float64 `json:"amount"`
+	Height uint    `json:"height"`
+}
+
+// scanUnspentOutputs scans the UTXO set for outputs locked using any of
+// the given scripts. Scans are serialized as bitcoind runs only one scan
+// at a time.
+func (c *Connection) scanUnspentOutputs(
+	scripts ...bitcoin.Script,
+) ([]*scannedUnspentOutput, error) {
+	c.scanMutex.Lock()
+	defer c.scanMutex.Unlock()
+
+	descriptors := make([]string, len(scripts))
+	for i, script := range scripts {
+		descriptors[i] = fmt.Sprintf("raw(%s)", hex.EncodeToString(script))
+	}
+
+	var scanResult struct {
+		Success  bool                    `json:"success"`
+		Unspents []*scannedUnspentOutput `json:"unspents"`
+	}
+
+	if err := c.call(
+		"scantxoutset",
+		&scanResult,
+		"start",
+		descriptors,
+	); err != nil {
+		return nil, fmt.Errorf(
+			"failed to scan unspent outputs for descriptors [%s]: [%w]",
+			strings.Join(descriptors, ", "),
+			err,
+		)
+	}
+
+	if !scanResult.Success {
+		return nil, fmt.Errorf(
+			"scan of unspent outputs for descriptors [%s] did not succeed",
+			strings.Join(descriptors, ", "),
+		)
+	}
+
+	return scanResult.Unspents, nil
+}
+
+// inspectMempool goes through transactions currently in the mempool and
+// returns outputs locked using the given script.
+//
+// The mempool entries are listed with a single verbose `getrawmempool`
+// call. Transactions are cached by the connection between inspections so,
+// only transactions that entered the mempool, or whose witness changed,
+// since the previous inspection are fetched with `getrawtransaction`, one
+// call per transaction. Transactions that left the mempool are removed from
+// the cache. The first inspection fetches the whole mempool and the cache
+// holds all mempool transactions in memory so, the number of inspected
+// transactions is bounded by the configured mempool inspection limit. If the
+// mempool holds more transactions, it is not inspected, the cache is
+// released, and no outputs are returned.
+func (c *Connection) inspectMempool(script bitcoin.Script) (
+	[]*bitcoin.UnspentTransactionOutput,
+	error,
+) {
+	var mempoolEntries map[string]struct {
+		WTxID string `json:"wtxid"`
+	}
+	if err := c.call("getrawmempool", &mempoolEntries, true); err != nil {
+		return nil, fmt.Errorf("failed to get mempool: [%w]", err)
+	}
+
+	c.mempoolMutex.Lock()
+	defer c.mempoolMutex.Unlock()
+
+	if len(mempoolEntries) > c.config.MempoolInspectionLimit {
+		logger.Warnf(
+			"mempool holds [%v] transactions which exceeds the inspection "+
+				"limit [%v]; outputs of unconfirmed transactions are "+
+				"not looked up",
+			len(mempoolEntries),
+			c.config.MempoolInspectionLimit,
+		)
+
+		c.mempoolTransactions = nil
+		return []*bitcoin.UnspentTransactionOutput{}, nil
+	}
+
+	if c.mempoolTransactions == nil {
+		c.mempoolTransactions = make(map[string]*mempoolTransaction)
+	}
+
+	// Transactions that left the mempool are no longer needed.
+	for txID := range c.mempoolTransactions {
+		if _, ok := mempoolEntries[txID]; !ok {
+			delete(c.mempoolTransactions, txID)
+		}
+	}
+
+	// Transactions are processed in a deterministic order to return
+	// the outputs in the same order for the same mempool.
+	txIDs := make([]string, 0, len(mempoolEntries))
+	for txID := range mempoolEntries {
+		txIDs = append(txIDs, txID)
+	}
+	sort.Strings(txIDs)
+
+	outputs := make([]*bitcoin.UnspentTransactionOutput, 0)
+
+	for _, txID := range txIDs {
+		cached, err := c.getMempoolTransaction(txID, mempoolEntries[txID].WTxID)
+		if err != nil {
+			return nil, err
+		}
+		if cached == nil {
+			// The transaction left the mempool in the meantime. If it
+			// was mined, its outputs are part of the UTXO set.
+			continue
+		}
+
+		for i, output := range cached.transaction.Outputs {
+			if !bytes.Equal(output.PublicKeyScript, script) {
+				continue
+			}
+
+			outputs = append(outputs, &bitcoin.UnspentTransactionOutput{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: cached.hash,
+					OutputIndex:     uint32(i),
+				},
+				Value: output.Value,
+			})
+		}
+	}
+
+	return outputs, nil
+}
+
+// isUnspent checks whether the given outpoint is unspent, taking the mempool
+// into account. Outputs of unconfirmed transactions are unspent as long as
+// no other mempool transaction spends them.
+func (c *Connection) isUnspent(
+	outpoint *bitcoin.TransactionOutpoint,
+) (bool, error) {
+	txID := outpoint.TransactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	// The result is null if the output is spent or does not exist.
+	var txOut map[string]interface{}
+	if err := c.call(
+		"gettxout",
+		&txOut,
+		txID,
+		outpoint.OutputIndex,
+		true,
+	); err != nil {
+		return false, fmt.Errorf(
+			"failed to get output [%s:%d]: [%w]",
+			txID,
+			outpoint.OutputIndex,
+			err,
+		)
+	}
+
+	return txOut != nil, nil
+}
+
+// getMempoolTransaction returns the mempool transaction with the given ID
+// and witness transaction ID from the cache or fetches it from bitcoind if
+// it is not cached yet. Returns nil if the transaction is no longer in the
+// mempool. Must be called with the mempool mutex held.
+func (c *Connection) getMempoolTransaction(
+	txID string,
+	wtxid string,
+) (*mempoolTransaction, error) {
+	if cached, ok := c.mempoolTransactions[txID]; ok && cached.wtxid == wtxid {
+		return cached, nil
+	}
+
+	transactionHash, err := bitcoin.NewHashFromString(
+		txID,
+		bitcoin.ReversedByteOrder,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot parse hash of transaction [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	transaction, err := c.GetTransaction(transactionHash)
+	if err != nil {
+		var rpcErr *rpcError
+		if errors.As(err, &rpcErr) &&
+			rpcErr.Code == rpcInvalidAddressOrKeyErrorCode {
+			delete(c.mempoolTransactions, txID)
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf(
+			"cannot get mempool transaction [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	cached := &mempoolTransaction{
+		hash:        transactionHash,
+		wtxid:       wtxid,
+		transaction: transaction,
+	}
+	c.mempoolTransactions[txID] = cached
+
+	return cached, nil
+}
+
+// getBlockHash gets the hash of the block with the given height, in the
+// reversed byte order used by bitcoind.
+func (c *Connection) getBlockHash(blockHeight uint) (string, error) {
+	var blockHash string
+	if err := c.call("getblockhash", &blockHash, blockHeight); err != nil {
+		return "", fmt.Errorf(
+			"failed to get hash of block at height [%v]: [%w]",
+			blockHeight,
+			err,
+		)
+	}
+
+	return blockHash, nil
+}
+
+func (c *Connection) verifyServer() error {
+	var blockchainInfo struct {
+		Chain  string `json:"chain"`
+		Blocks uint   `json:"blocks"`
+	}
+
+	if err := c.call("getblockchaininfo", &blockchainInfo); err != nil {
+		return fmt.Errorf("failed to get blockchain info: [%w]", err)
+	}
+
+	logger.Infof(
+		"connected to bitcoind server [chain: [%s], blocks: [%d]]",
+		blockchainInfo.Chain,
+		blockchainInfo.Blocks,
+	)
+
+	var indexInfo map[string]interface{}
+	if err := c.call("getindexinfo", &indexInfo); err != nil {
+		return fmt.Errorf("failed to get index info: [%w]", err)
+	}
+
+	if _, ok := indexInfo["txindex"]; !ok {
+		logger.Warnf(
+			"bitcoind server does not maintain the transaction index; " +
+				"transactions will be found only if they are in the " +
+				"mempool; please enable the `txindex` option",
+		)
+	}
+
+	return nil
+}
+
+// call executes the given JSON-RPC method, retrying it on connection
+// failures. Errors returned by bitcoind for the request itself are not
+// retried as they are not expected to change.
+func (c *Connection) call(
+	method string,
+	result interface{},
+	params ...interface{},
+) error {
+	var rpcErr *rpcError
+
+	err := wrappers.DoWithDefaultRetry(
+		c.parentCtx,
+		c.config.RequestRetryTimeout,
+		func(ctx context.Context) error {
+			requestCtx, requestCancel := context.WithTimeout(
+				ctx,
+				c.config.RequestTimeout,
+			)
+			defer requestCancel()
+
+			err := c.client.call(requestCtx, method, result, params...)
+			if errors.As(err, &rpcErr) {
+				return nil
+			}
+
+			if err != nil {
+				return fmt.Errorf("request failed: [%w]", err)
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if rpcErr != nil {
+		return rpcErr
+	}
+
+	return nil
+}
+
+// computeMerkleBranch computes the Merkle branch proving the inclusion of
+// the transaction at the given position in the block with the given
+// transaction hashes. The branch consists of sibling hashes on the path
+// from the transaction to the Merkle root, starting from the bottom level.
+func computeMerkleBranch(
+	transactionHashes []bitcoin.Hash,
+	position int,
+) []bitcoin.Hash {
+	var branch []bitcoin.Hash
+
+	level := make([]bitcoin.Hash, len(transactionHashes))
+	copy(level, transactionHashes)
+
+	for len(level) > 1 {
+		// Levels with an odd number of nodes duplicate the last node.
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+
+		branch = append(branch, level[position^1])
+
+		nextLevel := make([]bitcoin.Hash, len(level)/2)
+		for i := range nextLevel {
+			nextLevel[i] = bitcoin.ComputeHash(
+				append(level[2*i][:], level[2*i+1][:]...),
+			)
+		}
+
+		level = nextLevel
+		position /= 2
+	}
+
+	return branch
+}
+
+// convertBtcToSatoshi converts the given BTC amount, as returned by
+// bitcoind, to satoshi. The value is rounded to get rid of the floating
+// point precision artifacts.
+func convertBtcToSatoshi(btc float64) int64 {
+	return int64(math.Round(btc * 1e8))
+}
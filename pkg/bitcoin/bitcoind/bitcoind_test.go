@@ -0,0 +1,1044 @@
+package bitcoind
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+
+	testData "github.com/keep-network/keep-core/internal/testdata/bitcoin"
+)
+
+const (
+	testUsername = "user"
+	testPassword = "password"
+)
+
+// rpcHandler handles a single JSON-RPC method of the fake bitcoind server.
+type rpcHandler func(params []json.RawMessage) (interface{}, *rpcError)
+
+// fakeServer is a fake bitcoind JSON-RPC server.
+type fakeServer struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	handlers map[string]rpcHandler
+	calls    map[string]int
+}
+
+func newFakeServer(t *testing.T, handlers map[string]rpcHandler) *fakeServer {
+	fs := &fakeServer{
+		handlers: handlers,
+		calls:    make(map[string]int),
+	}
+
+	fs.Server = httptest.NewServer(http.HandlerFunc(fs.handle))
+	t.Cleanup(fs.Close)
+
+	return fs
+}
+
+func (fs *fakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	if !ok || username != testUsername || password != testPassword {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var request struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	fs.mutex.Lock()
+	fs.calls[request.Method]++
+	handler, ok := fs.handlers[request.Method]
+	fs.mutex.Unlock()
+
+	var result interface{}
+	var rpcErr *rpcError
+	if ok {
+		result, rpcErr = handler(request.Params)
+	} else {
+		rpcErr = &rpcError{Code: -32601, Message: "Method not found"}
+	}
+
+	// Mimic bitcoind which responds with error statuses for RPC errors.
+	if rpcErr != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"result": result,
+		"error":  rpcErr,
+		"id":     request.ID,
+	})
+}
+
+func (fs *fakeServer) callsCount(method string) int {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	return fs.calls[method]
+}
+
+func newTestConnection(server *fakeServer) *Connection {
+	return &Connection{
+		parentCtx: context.Background(),
+		client:    newRPCClient(server.URL, testUsername, testPassword),
+		config: Config{
+			URL:                    server.URL,
+			Username:               testUsername,
+			Password:               testPassword,
+			RequestTimeout:         time.Second,
+			RequestRetryTimeout:    2 * time.Second,
+			MempoolInspectionLimit: DefaultMempoolInspectionLimit,
+		},
+	}
+}
+
+func unmarshalParam[T any](t *testing.T, param json.RawMessage) T {
+	var result T
+	if err := json.Unmarshal(param, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestConnect(t *testing.T) {
+	server := newFakeServer(t, map[string]rpcHandler{
+		"getblockchaininfo": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{"chain": "test", "blocks": 100}, nil
+		},
+		"getindexinfo": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{}, nil
+		},
+	})
+
+	_, err := Connect(
+		context.Background(),
+		Config{
+			URL:      server.URL,
+			Username: testUsername,
+			Password: testPassword,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"getblockchaininfo calls",
+		1,
+		server.callsCount("getblockchaininfo"),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"getindexinfo calls",
+		1,
+		server.callsCount("getindexinfo"),
+	)
+}
+
+func TestConnect_Unauthorized(t *testing.T) {
+	server := newFakeServer(t, map[string]rpcHandler{})
+
+	_, err := Connect(
+		context.Background(),
+		Config{
+			URL:                 server.URL,
+			Username:            testUsername,
+			Password:            "wrong",
+			RequestTimeout:      100 * time.Millisecond,
+			RequestRetryTimeout: 200 * time.Millisecond,
+		},
+	)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestGetTransaction(t *testing.T) {
+	testTransaction := testData.Transactions["input: P2PKH, output: P2SH, P2WPKH"]
+
+	server := newFakeServer(t, map[string]rpcHandler{
+		"getrawtransaction": func(params []json.RawMessage) (interface{}, *rpcError) {
+			txID := unmarshalParam[string](t, params[0])
+			if txID != testTransaction.TxHash.Hex(bitcoin.ReversedByteOrder) {
+				return nil, &rpcError{Code: -5, Message: "No such transaction"}
+			}
+			if unmarshalParam[bool](t, params[1]) {
+				t.Errorf("unexpected verbose request")
+			}
+			return hex.EncodeToString(testTransaction.BitcoinTx.Serialize()), nil
+		},
+	})
+
+	connection := newTestConnection(server)
+
+	transaction, err := connection.GetTransaction(testTransaction.TxHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(&testTransaction.BitcoinTx, transaction) {
+		t.Errorf(
+			"unexpected transaction\nexpected: %v\nactual:   %v\n",
+			&testTransaction.BitcoinTx,
+			transaction,
+		)
+	}
+}
+
+func TestGetTransaction_NotFound(t *testing.T) {
+	server := newFakeServer(t, map[string]rpcHandler{
+		"getrawtransaction": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return nil, &rpcError{Code: -5, Message: "No such transaction"}
+		},
+	})
+
+	connection := newTestConnection(server)
+
+	_, err := connection.GetTransaction(bitcoin.Hash{0x01})
+
+	var rpcErr *rpcError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+
+	expectedErr := &rpcError{Code: -5, Message: "No such transaction"}
+	if !reflect.DeepEqual(expectedErr, rpcErr) {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v\n",
+			expectedErr,
+			rpcErr,
+		)
+	}
+
+	// Errors returned by bitcoind for the request itself must not be retried.
+	testutils.AssertIntsEqual(
+		t,
+		"getrawtransaction calls",
+		1,
+		server.callsCount("getrawtransaction"),
+	)
+}
+
+func TestGetTransactionConfirmations(t *testing.T) {
+	confirmedTxID := bitcoin.Hash{0x01}
+	mempoolTxID := bitcoin.Hash{0x02}
+
+	server := newFakeServer(t, map[string]rpcHandler{
+		"getrawtransaction": func(params []json.RawMessage) (interface{}, *rpcError) {
+			if !unmarshalParam[bool](t, params[1]) {
+				t.Errorf("unexpected non-verbose request")
+			}
+
+			switch unmarshalParam[string](t, params[0]) {
+			case confirmedTxID.Hex(bitcoin.ReversedByteOrder):
+				return map[string]interface{}{"confirmations": 7}, nil
+			case mempoolTxID.Hex(bitcoin.ReversedByteOrder):
+				return map[string]interface{}{}, nil
+			default:
+				return nil, &rpcError{Code: -5, Message: "No such transaction"}
+			}
+		},
+	})
+
+	connection := newTestConnection(server)
+
+	var tests = map[string]struct {
+		transactionHash       bitcoin.Hash
+		expectedConfirmations uint
+	}{
+		"confirmed transaction": {
+			transactionHash:       confirmedTxID,
+			expectedConfirmations: 7,
+		},
+		"mempool transaction": {
+			transactionHash:       mempoolTxID,
+			expectedConfirmations: 0,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			confirmations, err := connection.GetTransactionConfirmations(
+				test.transactionHash,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"confirmations",
+				int(test.expectedConfirmations),
+				int(confirmations),
+			)
+		})
+	}
+}
+
+func TestBroadcastTransaction(t *testing.T) {
+	testTransaction := testData.Transactions["input: P2PKH, output: P2SH, P2WPKH"]
+
+	var broadcastRawTx string
+	server := newFakeServer(t, map[string]rpcHandler{
+		"sendrawtransaction": func(params []json.RawMessage) (interface{}, *rpcError) {
+			broadcastRawTx = unmarshalParam[string](t, params[0])
+			return testTransaction.TxHash.Hex(bitcoin.ReversedByteOrder), nil
+		},
+	})
+
+	connection := newTestConnection(server)
+
+	err := connection.BroadcastTransaction(&testTransaction.BitcoinTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"broadcast raw transaction",
+		hex.EncodeToString(testTransaction.BitcoinTx.Serialize()),
+		broadcastRawTx,
+	)
+}
+
+func TestGetLatestBlockHeight(t *testing.T) {
+	server := newFakeServer(t, map[string]rpcHandler{
+		"getblockcount": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return 2135502, nil
+		},
+	})
+
+	connection := newTestConnection(server)
+
+	blockHeight, err := connection.GetLatestBlockHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "block height", 2135502, int(blockHeight))
+}
+
+func TestGetBlockHeaders(t *testing.T) {
+	blockHeaders := map[uint]*bitcoin.BlockHeader{
+		100: {
+			Version:                 1,
+			PreviousBlockHeaderHash: bitcoin.Hash{0x01},
+			MerkleRootHash:          bitcoin.Hash{0x02},
+			Time:                    1641914003,
+			Bits:                    436256810,
+			Nonce:                   778087099,
+		},
+		101: {
+			Version:                 2,
+			PreviousBlockHeaderHash: bitcoin.Hash{0x03},
+			MerkleRootHash:          bitcoin.Hash{0x04},
+			Time:                    1641914603,
+			Bits:                    436256810,
+			Nonce:                   2184722531,
+		},
+	}
+
+	server := newFakeServer(t, map[string]rpcHandler{
+		"getblockhash": func(params []json.RawMessage) (interface{}, *rpcError) {
+			height := unmarshalParam[uint](t, params[0])
+			if _, ok := blockHeaders[height]; !ok {
+				return nil, &rpcError{Code: -8, Message: "Block height out of range"}
+			}
+			return fmt.Sprintf("hash-%d", height), nil
+		},
+		"getblockheader": func(params []json.RawMessage) (interface{}, *rpcError) {
+			if unmarshalParam[bool](t, params[1]) {
+				t.Errorf("unexpected verbose request")
+			}
+
+			var height uint
+			_, err := fmt.Sscanf(unmarshalParam[string](t, params[0]), "hash-%d", &height)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			serializedHeader := blockHeaders[height].Serialize()
+			return hex.EncodeToString(serializedHeader[:]), nil
+		},
+	})
+
+	connection := newTestConnection(server)
+
+	headers, err := connection.GetBlockHeaders(100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHeaders := []*bitcoin.BlockHeader{blockHeaders[100], blockHeaders[101]}
+	if !reflect.DeepEqual(expectedHeaders, headers) {
+		t.Errorf(
+			"unexpected block headers\nexpected: %v\nactual:   %v\n",
+			expectedHeaders,
+			headers,
+		)
+	}
+
+	_, err = connection.GetBlockHeaders(100, 3)
+	if err == nil {
+		t.Fatal("expected error for a missing block")
+	}
+}
+
+func TestGetTransactionMerkle(t *testing.T) {
+	// Transactions of the Bitcoin mainnet block 100000:
+	// https://blockstream.info/block/000000000003ba27aa200b1cecaad478d2b00432346c3f1f3986da1afd33e506
+	blockTxIDs := []string{
+		"8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87",
+		"fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4",
+		"6359f0868171b1d194cbee1af2f16ea598ae8fad666d9b012c8ed2b79a236ec4",
+		"e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d",
+	}
+	expectedMerkleRoot := "f3e94742aca4b5ef85488dc37c06c3282295ffec960994b2c0d5ac2a25a95766"
+
+	server := newFakeServer(t, map[string]rpcHandler{
+		"getblockhash": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return "block-hash", nil
+		},
+		"getblock": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{"tx": blockTxIDs}, nil
+		},
+	})
+
+	connection := newTestConnection(server)
+
+	for position, txID := range blockTxIDs {
+		t.Run(txID, func(t *testing.T) {
+			transactionHash, err := bitcoin.NewHashFromString(
+				txID,
+				bitcoin.ReversedByteOrder,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			proof, err := connection.GetTransactionMerkle(transactionHash, 100000)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"block height",
+				100000,
+				int(proof.BlockHeight),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"position",
+				int(position),
+				int(proof.Position),
+			)
+
+			// Fold the branch to make sure it leads to the block's Merkle root.
+			current := transactionHash
+			index := proof.Position
+			for _, node := range proof.MerkleNodes {
+				if index%2 == 0 {
+					current = bitcoin.ComputeHash(append(current[:], node[:]...))
+				} else {
+					current = bitcoin.ComputeHash(append(node[:], current[:]...))
+				}
+				index /= 2
+			}
+
+			testutils.AssertStringsEqual(
+				t,
+				"merkle root",
+				expectedMerkleRoot,
+				current.Hex(bitcoin.ReversedByteOrder),
+			)
+		})
+	}
+
+	_, err := connection.GetTransactionMerkle(bitcoin.Hash{0x01}, 100000)
+	if err == nil {
+		t.Fatal("expected error for a transaction not included in the block")
+	}
+}
+
+func TestGetUnspentOutputsForScript(t *testing.T) {
+	script := bitcoin.Script{0x00, 0x14, 0x01}
+
+	// Mempool transaction spending one of the confirmed outputs and
+	// creating a new output locked using the script.
+	spendingTransaction := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: bitcoin.Hash{0x02},
+					OutputIndex:     0,
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{Value: 1000, PublicKeyScript: bitcoin.Script{0x00, 0x14, 0x02}},
+			{Value: 2000, PublicKeyScript: script},
+		},
+	}
+	// Mempool transaction that was mined after the mempool was fetched so,
+	// its output is returned by both the mempool and the UTXO set scan.
+	minedTransaction := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: bitcoin.Hash{0x03},
+					OutputIndex:     0,
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{Value: 3000, PublicKeyScript: script},
+		},
+	}
+
+	mempoolTransactions := map[string]*bitcoin.Transaction{
+		spendingTransaction.Hash().Hex(bitcoin.ReversedByteOrder): spendingTransaction,
+		minedTransaction.Hash().Hex(bitcoin.ReversedByteOrder):    minedTransaction,
+	}
+
+	// Outputs spent by mempool transactions.
+	spentOutpoints := map[string]bool{
+		fmt.Sprintf("%s:0", bitcoin.Hash{0x02}.Hex(bitcoin.ReversedByteOrder)): true,
+	}
+
+	server := newFakeServer(t, map[string]rpcHandler{
+		"getrawmempool": func(params []json.RawMessage) (interface{}, *rpcError) {
+			if !unmarshalParam[bool](t, params[0]) {
+				t.Errorf("mempool should be requested in the verbose mode")
+			}
+
+			return newMempoolEntries(
+				spendingTransaction,
+				minedTransaction,
+			).with(
+				// Transaction that left the mempool in the meantime.
+				bitcoin.Hash{0x04}.Hex(bitcoin.ReversedByteOrder),
+				bitcoin.Hash{0x04}.Hex(bitcoin.ReversedByteOrder),
+			), nil
+		},
+		"getrawtransaction": func(params []json.RawMessage) (interface{}, *rpcError) {
+			transaction, ok := mempoolTransactions[unmarshalParam[string](t, params[0])]
+			if !ok {
+				return nil, &rpcError{Code: -5, Message: "No such transaction"}
+			}
+			return hex.EncodeToString(transaction.Serialize()), nil
+		},
+		"gettxout": func(params []json.RawMessage) (interface{}, *rpcError) {
+			if !unmarshalParam[bool](t, params[2]) {
+				t.Errorf("mempool should be included")
+			}
+
+			outpoint := fmt.Sprintf(
+				"%s:%d",
+				unmarshalParam[string](t, params[0]),
+				unmarshalParam[uint32](t, params[1]),
+			)
+			if spentOutpoints[outpoint] {
+				return nil, nil
+			}
+			return map[string]interface{}{"confirmations": 1}, nil
+		},
+		"scantxoutset": func(params []json.RawMessage) (interface{}, *rpcError) {
+			testutils.AssertStringsEqual(
+				t,
+				"action",
+				"start",
+				unmarshalParam[string](t, params[0]),
+			)
+
+			expectedDescriptors := []string{"raw(001401)"}
+			descriptors := unmarshalParam[[]string](t, params[1])
+			if !reflect.DeepEqual(expectedDescriptors, descriptors) {
+				t.Errorf(
+					"unexpected descriptors\nexpected: %v\nactual:   %v\n",
+					expectedDescriptors,
+					descriptors,
+				)
+			}
+
+			return map[string]interface{}{
+				"success": true,
+				"unspents": []map[string]interface{}{
+					{
+						"txid":   bitcoin.Hash{0x01}.Hex(bitcoin.ReversedByteOrder),
+						"vout":   1,
+						"amount": 0.0136055,
+						"height": 100,
+					},
+					{
+						"txid":   bitcoin.Hash{0x02}.Hex(bitcoin.ReversedByteOrder),
+						"vout":   0,
+						"amount": 0.0001,
+						"height": 101,
+					},
+					{
+						"txid":   minedTransaction.Hash().Hex(bitcoin.ReversedByteOrder),
+						"vout":   0,
+						"amount": 0.00003,
+						"height": 102,
+					},
+				},
+			}, nil
+		},
+	})
+
+	connection := newTestConnection(server)
+
+	unspentOutputs, err := connection.GetUnspentOutputsForScript(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedUnspentOutputs := []*bitcoin.UnspentTransactionOutput{
+		{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: bitcoin.Hash{0x01},
+				OutputIndex:     1,
+			},
+			Value: 1360550,
+		},
+		{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: minedTransaction.Hash(),
+				OutputIndex:     0,
+			},
+			Value: 3000,
+		},
+		{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: spendingTransaction.Hash(),
+				OutputIndex:     1,
+			},
+			Value: 2000,
+		},
+	}
+
+	if !reflect.DeepEqual(expectedUnspentOutputs, unspentOutputs) {
+		t.Errorf(
+			"unexpected unspent outputs\nexpected: %v\nactual:   %v\n",
+			expectedUnspentOutputs,
+			unspentOutputs,
+		)
+	}
+}
+
+func TestGetUnspentOutputsForScript_MempoolCache(t *testing.T) {
+	script := bitcoin.Script{0x00, 0x14, 0x01}
+
+	newTransaction := func(previousHash bitcoin.Hash) *bitcoin.Transaction {
+		return &bitcoin.Transaction{
+			Version: 1,
+			Inputs: []*bitcoin.TransactionInput{
+				{
+					Outpoint: &bitcoin.TransactionOutpoint{
+						TransactionHash: previousHash,
+						OutputIndex:     0,
+					},
+					Sequence: 0xffffffff,
+				},
+			},
+			Outputs: []*bitcoin.TransactionOutput{
+				{Value: 1000, PublicKeyScript: script},
+			},
+		}
+	}
+
+	transaction1 := newTransaction(bitcoin.Hash{0x01})
+	transaction2 := newTransaction(bitcoin.Hash{0x02})
+	transaction3 := newTransaction(bitcoin.Hash{0x03})
+
+	// The mutex guards the state of the fake server.
+	var mutex sync.Mutex
+	var mempool mempoolEntries
+	fetchedTransactions := make([]string, 0)
+
+	setMempool := func(entries mempoolEntries) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		mempool = entries
+	}
+
+	transactions := map[string]*bitcoin.Transaction{
+		transaction1.Hash().Hex(bitcoin.ReversedByteOrder): transaction1,
+		transaction2.Hash().Hex(bitcoin.ReversedByteOrder): transaction2,
+		transaction3.Hash().Hex(bitcoin.ReversedByteOrder): transaction3,
+	}
+
+	server := newFakeServer(t, map[string]rpcHandler{
+		"getrawmempool": func(params []json.RawMessage) (interface{}, *rpcError) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return mempool, nil
+		},
+		"getrawtransaction": func(params []json.RawMessage) (interface{}, *rpcError) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			txID := unmarshalParam[string](t, params[0])
+			fetchedTransactions = append(fetchedTransactions, txID)
+			return hex.EncodeToString(transactions[txID].Serialize()), nil
+		},
+		"gettxout": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{"confirmations": 0}, nil
+		},
+		"scantxoutset": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{
+				"success":  true,
+				"unspents": []map[string]interface{}{},
+			}, nil
+		},
+	})
+
+	connection := newTestConnection(server)
+
+	assertFetchedTransactions := func(
+		description string,
+		expectedTransactions ...*bitcoin.Transaction,
+	) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		expectedTxIDs := make([]string, len(expectedTransactions))
+		for i, transaction := range expectedTransactions {
+			expectedTxIDs[i] = transaction.Hash().Hex(bitcoin.ReversedByteOrder)
+		}
+		sort.Strings(expectedTxIDs)
+		sort.Strings(fetchedTransactions)
+
+		if !reflect.DeepEqual(expectedTxIDs, fetchedTransactions) {
+			t.Errorf(
+				"unexpected transactions fetched %s\n"+
+					"expected: %v\n"+
+					"actual:   %v\n",
+				description,
+				expectedTxIDs,
+				fetchedTransactions,
+			)
+		}
+
+		fetchedTransactions = make([]string, 0)
+	}
+
+	assertUnspentOutputsCount := func(expectedCount int) {
+		unspentOutputs, err := connection.GetUnspentOutputsForScript(script)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testutils.AssertIntsEqual(
+			t,
+			"unspent outputs count",
+			expectedCount,
+			len(unspentOutputs),
+		)
+	}
+
+	setMempool(newMempoolEntries(transaction1, transaction2))
+	assertUnspentOutputsCount(2)
+	assertFetchedTransactions("on the first inspection", transaction1, transaction2)
+
+	assertUnspentOutputsCount(2)
+	assertFetchedTransactions("when the mempool did not change")
+
+	// Transaction 1 is mined and transaction 3 enters the mempool.
+	setMempool(newMempoolEntries(transaction2, transaction3))
+	assertUnspentOutputsCount(2)
+	assertFetchedTransactions("when the mempool changed", transaction3)
+
+	testutils.AssertIntsEqual(
+		t,
+		"cached transactions count",
+		2,
+		len(connection.mempoolTransactions),
+	)
+
+	// The witness of transaction 2 is replaced.
+	setMempool(newMempoolEntries(transaction3).with(
+		transaction2.Hash().Hex(bitcoin.ReversedByteOrder),
+		bitcoin.Hash{0x05}.Hex(bitcoin.ReversedByteOrder),
+	))
+	assertUnspentOutputsCount(2)
+	assertFetchedTransactions("when the witness changed", transaction2)
+}
+
+func TestGetUnspentOutputsForScript_MempoolInspectionLimit(t *testing.T) {
+	script := bitcoin.Script{0x00, 0x14, 0x01}
+
+	unconfirmedTransaction := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: bitcoin.Hash{0x01},
+					OutputIndex:     0,
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{Value: 1000, PublicKeyScript: script},
+		},
+	}
+
+	server := newFakeServer(t, map[string]rpcHandler{
+		"getrawmempool": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return newMempoolEntries(unconfirmedTransaction).with(
+				bitcoin.Hash{0x02}.Hex(bitcoin.ReversedByteOrder),
+				bitcoin.Hash{0x02}.Hex(bitcoin.ReversedByteOrder),
+			), nil
+		},
+		"getrawtransaction": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return hex.EncodeToString(unconfirmedTransaction.Serialize()), nil
+		},
+		"gettxout": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{"confirmations": 1}, nil
+		},
+		"scantxoutset": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return map[string]interface{}{
+				"success": true,
+				"unspents": []map[string]interface{}{
+					{
+						"txid":   bitcoin.Hash{0x03}.Hex(bitcoin.ReversedByteOrder),
+						"vout":   0,
+						"amount": 0.0001,
+						"height": 100,
+					},
+				},
+			}, nil
+		},
+	})
+
+	connection := newTestConnection(server)
+	connection.config.MempoolInspectionLimit = 1
+	connection.mempoolTransactions = map[string]*mempoolTransaction{
+		unconfirmedTransaction.Hash().Hex(bitcoin.ReversedByteOrder): {},
+	}
+
+	unspentOutputs, err := connection.GetUnspentOutputsForScript(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the confirmed output is returned as the mempool holds more
+	// transactions than the inspection limit.
+	expectedUnspentOutputs := []*bitcoin.UnspentTransactionOutput{
+		{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: bitcoin.Hash{0x03},
+				OutputIndex:     0,
+			},
+			Value: 10000,
+		},
+	}
+
+	if !reflect.DeepEqual(expectedUnspentOutputs, unspentOutputs) {
+		t.Errorf(
+			"unexpected unspent outputs\nexpected: %v\nactual:   %v\n",
+			expectedUnspentOutputs,
+			unspentOutputs,
+		)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"getrawtransaction calls",
+		0,
+		server.callsCount("getrawtransaction"),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"cached transactions count",
+		0,
+		len(connection.mempoolTransactions),
+	)
+}
+
+func TestGetUnspentOutputsForScript_ConcurrentScans(t *testing.T) {
+	var mutex sync.Mutex
+	scanInProgress := false
+
+	server := newFakeServer(t, map[string]rpcHandler{
+		"getrawmempool": func(params []json.RawMessage) (interface{}, *rpcError) {
+			return newMempoolEntries(), nil
+		},
+		"scantxoutset": func(params []json.RawMessage) (interface{}, *rpcError) {
+			mutex.Lock()
+			if scanInProgress {
+				mutex.Unlock()
+				return nil, &rpcError{Code: -8, Message: "Scan already in progress"}
+			}
+			scanInProgress = true
+			mutex.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mutex.Lock()
+			scanInProgress = false
+			mutex.Unlock()
+
+			return map[string]interface{}{
+				"success":  true,
+				"unspents": []map[string]interface{}{},
+			}, nil
+		},
+	})
+
+	connection := newTestConnection(server)
+
+	const scansCount = 5
+
+	var wg sync.WaitGroup
+	errs := make(chan error, scansCount)
+
+	for i := 0; i < scansCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			_, err := connection.GetUnspentOutputsForScript(
+				bitcoin.Script{0x00, 0x14, byte(i)},
+			)
+			errs <- err
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("unexpected error: [%v]", err)
+		}
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"scantxoutset calls",
+		scansCount,
+		server.callsCount("scantxoutset"),
+	)
+}
+
+// mempoolEntries is the result of the verbose `getrawmempool` call.
+type mempoolEntries map[string]map[string]interface{}
+
+func newMempoolEntries(transactions ...*bitcoin.Transaction) mempoolEntries {
+	entries := make(mempoolEntries)
+	for _, transaction := range transactions {
+		entries = entries.with(
+			transaction.Hash().Hex(bitcoin.ReversedByteOrder),
+			transaction.WitnessHash().Hex(bitcoin.ReversedByteOrder),
+		)
+	}
+	return entries
+}
+
+func (me mempoolEntries) with(txID string, wtxid string) mempoolEntries {
+	me[txID] = map[string]interface{}{"wtxid": wtxid}
+	return me
+}
+
+func TestGetTransactionsForPublicKeyHash(t *testing.T) {
+	server := newFakeServer(t, map[string]rpcHandler{})
+
+	connection := newTestConnection(server)
+
+	_, err := connection.GetTransactionsForPublicKeyHash([20]byte{0x01}, 0)
+
+	testutils.AssertErrorsSame(t, ErrTransactionsHistoryNotSupported, err)
+}
+
+func TestEstimateSatPerVByteFee(t *testing.T) {
+	var tests = map[string]struct {
+		estimation     map[string]interface{}
+		expectedFee    int64
+		expectedErrMsg string
+	}{
+		"fee estimated": {
+			estimation: map[string]interface{}{
+				"feerate": 0.00012345,
+				"blocks":  6,
+			},
+			// 12345 sat/kvB rounded up to sat/vB.
+			expectedFee: 13,
+		},
+		"fee not estimated": {
+			estimation: map[string]interface{}{
+				"errors": []string{"Insufficient data or no feerate found"},
+				"blocks": 6,
+			},
+			expectedErrMsg: "fee could not be estimated; node returned " +
+				"errors: [Insufficient data or no feerate found]",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			server := newFakeServer(t, map[string]rpcHandler{
+				"estimatesmartfee": func(params []json.RawMessage) (interface{}, *rpcError) {
+					testutils.AssertIntsEqual(
+						t,
+						"blocks",
+						6,
+						int(unmarshalParam[uint32](t, params[0])),
+					)
+					return test.estimation, nil
+				},
+			})
+
+			connection := newTestConnection(server)
+
+			fee, err := connection.EstimateSatPerVByteFee(6)
+
+			if test.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				testutils.AssertStringsEqual(
+					t,
+					"error",
+					test.expectedErrMsg,
+					err.Error(),
+				)
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(t, "fee", int(test.expectedFee), int(fee))
+		})
+	}
+}
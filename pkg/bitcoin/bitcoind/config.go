@@ -0,0 +1,33 @@
+package bitcoind
+
+import "time"
+
+const (
+	// DefaultRequestTimeout is a default timeout used for a single attempt of
+	// bitcoind JSON-RPC request.
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultRequestRetryTimeout is a default timeout used for bitcoind
+	// JSON-RPC request retries.
+	DefaultRequestRetryTimeout = 2 * time.Minute
+	// DefaultMempoolInspectionLimit is a default maximum number of mempool
+	// transactions inspected while looking for unconfirmed outputs.
+	DefaultMempoolInspectionLimit = 50000
+)
+
+// Config holds configurable properties.
+type Config struct {
+	// URL to the bitcoind JSON-RPC endpoint in format: `http://hostname:port`.
+	URL string
+	// Username used to authenticate JSON-RPC requests.
+	Username string
+	// Password used to authenticate JSON-RPC requests.
+	Password string
+	// Timeout for a single attempt of bitcoind JSON-RPC request.
+	RequestTimeout time.Duration
+	// Timeout for bitcoind JSON-RPC request retries.
+	RequestRetryTimeout time.Duration
+	// Maximum number of mempool transactions inspected while looking for
+	// unconfirmed outputs. If the mempool holds more transactions, outputs
+	// of unconfirmed transactions are not looked up.
+	MempoolInspectionLimit int
+}
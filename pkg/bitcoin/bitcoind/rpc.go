@@ -0,0 +1,145 @@
+package bitcoind
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// rpcRequest is a bitcoind JSON-RPC request.
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// rpcResponse is a bitcoind JSON-RPC response.
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+	ID     uint64          `json:"id"`
+}
+
+// rpcError is an error returned by bitcoind for a JSON-RPC request that
+// reached the node but could not be handled, e.g. because of invalid
+// parameters or a missing transaction.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (re *rpcError) Error() string {
+	return fmt.Sprintf("rpc error [code: [%d], message: [%s]]", re.Code, re.Message)
+}
+
+// rpcClient is a minimal client of the bitcoind JSON-RPC interface.
+type rpcClient struct {
+	url        string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	lastRequestID uint64
+}
+
+func newRPCClient(url string, username string, password string) *rpcClient {
+	return &rpcClient{
+		url:        url,
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{},
+	}
+}
+
+// call executes the given JSON-RPC method with the given parameters and
+// unmarshals the response result into the given result value. If bitcoind
+// responds with an error, the returned error is of the *rpcError type.
+func (rc *rpcClient) call(
+	ctx context.Context,
+	method string,
+	result interface{},
+	params ...interface{},
+) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	request := &rpcRequest{
+		JSONRPC: "1.0",
+		ID:      atomic.AddUint64(&rc.lastRequestID, 1),
+		Method:  method,
+		Params:  params,
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("cannot marshal request: [%w]", err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		rc.url,
+		bytes.NewReader(requestBody),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create HTTP request: [%w]", err)
+	}
+
+	httpRequest.Header.Set("Content-Type", "application/json")
+	if rc.username != "" || rc.password != "" {
+		httpRequest.SetBasicAuth(rc.username, rc.password)
+	}
+
+	httpResponse, err := rc.httpClient.Do(httpRequest)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: [%w]", err)
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read HTTP response body: [%w]", err)
+	}
+
+	// bitcoind responds with non-200 status codes along with a JSON body
+	// in case of RPC errors, so the body is decoded regardless of the status.
+	// Bodies that cannot be decoded, e.g. for authorization failures, are
+	// reported using the HTTP status.
+	var response rpcResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		if httpResponse.StatusCode != http.StatusOK {
+			return fmt.Errorf(
+				"unexpected HTTP response status: [%s]",
+				httpResponse.Status,
+			)
+		}
+
+		return fmt.Errorf("cannot unmarshal response: [%w]", err)
+	}
+
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if response.ID != request.ID {
+		return fmt.Errorf(
+			"unexpected response ID [%d]; expected [%d]",
+			response.ID,
+			request.ID,
+		)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("cannot unmarshal result: [%w]", err)
+		}
+	}
+
+	return nil
+}
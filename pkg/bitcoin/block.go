@@ -59,6 +59,36 @@ func (bh *BlockHeader) Serialize() [BlockHeaderByteLength]byte {
 	return result
 }
 
+// Deserialize deserializes the given byte array to a BlockHeader. The
+// byte array is expected to use the block header serialization format:
+// [Version][PreviousBlockHeaderHash][MerkleRootHash][Time][Bits][Nonce].
+func (bh *BlockHeader) Deserialize(data [BlockHeaderByteLength]byte) {
+	offset := 0
+
+	// Version
+	bh.Version = int32(binary.LittleEndian.Uint32(data[offset:]))
+	offset += 4
+
+	// PreviousBlockHeaderHash
+	copy(bh.PreviousBlockHeaderHash[:], data[offset:])
+	offset += len(bh.PreviousBlockHeaderHash)
+
+	// MerkleRootHash
+	copy(bh.MerkleRootHash[:], data[offset:])
+	offset += len(bh.MerkleRootHash)
+
+	// Time
+	bh.Time = binary.LittleEndian.Uint32(data[offset:])
+	offset += 4
+
+	// Bits
+	bh.Bits = binary.LittleEndian.Uint32(data[offset:])
+	offset += 4
+
+	// Nonce
+	bh.Nonce = binary.LittleEndian.Uint32(data[offset:])
+}
+
 // Hash calculates the block header's hash as the double SHA-256 of the
 // block header serialization format:
 // [Version][PreviousBlockHeaderHash][MerkleRootHash][Time][Bits][Nonce].
@@ -28,4 +28,44 @@ type Chain interface {
 	// block with the given height was not found on the chain, this function
 	// returns an error.
 	GetBlockHeader(blockHeight uint) (*BlockHeader, error)
+
+	// GetBlockHeaders gets block headers of the given count of consecutive
+	// blocks starting from the given block height. The returned headers are
+	// ordered by block height in the ascending order. If any of the blocks
+	// was not found on the chain, this function returns an error.
+	GetBlockHeaders(startHeight uint, count uint) ([]*BlockHeader, error)
+
+	// GetTransactionMerkle gets the Merkle branch proving the inclusion of
+	// the transaction with the given hash in the block with the given height.
+	// If the transaction was not found in the given block, this function
+	// returns an error.
+	GetTransactionMerkle(
+		transactionHash Hash,
+		blockHeight uint,
+	) (*TransactionMerkleProof, error)
+
+	// GetTransactionsForPublicKeyHash gets confirmed transactions related to
+	// the given public key hash, i.e. transactions funding or spending outputs
+	// locked using either a P2PKH or P2WPKH script of that hash. The
+	// returned transactions are ordered by block height in the ascending
+	// order, i.e. the latest transaction is at the end of the slice. The
+	// limit parameter determines the maximum number of the latest
+	// transactions returned.
+	GetTransactionsForPublicKeyHash(
+		publicKeyHash [20]byte,
+		limit int,
+	) ([]*Transaction, error)
+
+	// GetUnspentOutputsForScript gets all unspent outputs locked using the
+	// given script. Outputs of unconfirmed transactions are returned as well.
+	GetUnspentOutputsForScript(script Script) ([]*UnspentTransactionOutput, error)
+
+	// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+	// transaction to be confirmed within the given number of blocks. If the
+	// fee could not be estimated, this function returns an error. Estimates
+	// depend on the backend so they are meant for parties proposing wallet
+	// transactions, e.g. deposit sweep proposal submitters. Wallet signers
+	// must agree on the fee of the transaction they sign so, they compute it
+	// from the transaction virtual size and a rate known to all of them.
+	EstimateSatPerVByteFee(blocks uint32) (int64, error)
 }
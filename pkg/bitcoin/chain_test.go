@@ -44,6 +44,37 @@ func (lc *localChain) GetBlockHeader(
 	panic("not implemented")
 }
 
+func (lc *localChain) GetBlockHeaders(
+	startHeight uint,
+	count uint,
+) ([]*BlockHeader, error) {
+	panic("not implemented")
+}
+
+func (lc *localChain) GetTransactionMerkle(
+	transactionHash Hash,
+	blockHeight uint,
+) (*TransactionMerkleProof, error) {
+	panic("not implemented")
+}
+
+func (lc *localChain) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*Transaction, error) {
+	panic("not implemented")
+}
+
+func (lc *localChain) GetUnspentOutputsForScript(
+	script Script,
+) ([]*UnspentTransactionOutput, error) {
+	panic("not implemented")
+}
+
+func (lc *localChain) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	panic("not implemented")
+}
+
 func (lc *localChain) addTransaction(
 	transaction *Transaction,
 ) error {
@@ -3,6 +3,7 @@ package electrum
 import (
 	"bytes"
 	"encoding/hex"
+	"fmt"
 
 	"github.com/btcsuite/btcd/v2/wire"
 	"github.com/checksum0/go-electrum/electrum"
@@ -18,6 +19,49 @@ func convertBlockHeader(electrumResult *electrum.GetBlockHeaderResult) (*bitcoin
 		return nil, err
 	}
 
+	return deserializeBlockHeader(headerBytes)
+}
+
+// convertBlockHeaders transforms a chunk of concatenated block headers
+// returned from Electrum protocol to the format expected by the bitcoin.Chain
+// interface.
+func convertBlockHeaders(
+	electrumResult *electrum.GetBlockHeadersResult,
+) ([]*bitcoin.BlockHeader, error) {
+	headersBytes, err := hex.DecodeString(electrumResult.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(headersBytes)%bitcoin.BlockHeaderByteLength != 0 {
+		return nil, fmt.Errorf(
+			"headers chunk length [%v] is not a multiple of [%v]",
+			len(headersBytes),
+			bitcoin.BlockHeaderByteLength,
+		)
+	}
+
+	headersCount := len(headersBytes) / bitcoin.BlockHeaderByteLength
+	result := make([]*bitcoin.BlockHeader, headersCount)
+	for i := 0; i < headersCount; i++ {
+		offset := i * bitcoin.BlockHeaderByteLength
+
+		result[i], err = deserializeBlockHeader(
+			headersBytes[offset : offset+bitcoin.BlockHeaderByteLength],
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot deserialize block header [%v]: [%w]",
+				i,
+				err,
+			)
+		}
+	}
+
+	return result, nil
+}
+
+func deserializeBlockHeader(headerBytes []byte) (*bitcoin.BlockHeader, error) {
 	buf := bytes.NewBuffer(headerBytes)
 
 	var b wire.BlockHeader
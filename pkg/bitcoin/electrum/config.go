@@ -18,12 +18,20 @@ const (
 	// DefaultKeepAliveInterval is a default interval used for Electrum server
 	// connection keep alive requests.
 	DefaultKeepAliveInterval = 5 * time.Minute
+	// DefaultMaxBlockHeightDeviation is a default maximum number of blocks
+	// the latest block height reported by an Electrum server can deviate
+	// from heights reported by other servers.
+	DefaultMaxBlockHeightDeviation = 3
 )
 
 // Config holds configurable properties.
 type Config struct {
 	// URL to the Electrum server in format: `hostname:port`.
 	URL string
+	// URLs to fallback Electrum servers in format: `hostname:port`. Fallback
+	// servers are used when the primary server becomes unavailable or
+	// unreliable. All servers use the same connection protocol.
+	FallbackURLs []string
 	// Electrum server connection protocol (`TCP` or `SSL`).
 	Protocol Protocol
 	// Timeout for a single attempt of Electrum connection establishment.
@@ -38,4 +46,16 @@ type Config struct {
 	// An Electrum server may disconnect clients that have not sent any requests
 	// for roughly 10 minutes.
 	KeepAliveInterval time.Duration
+	// Maximum number of blocks the latest block height reported by a server
+	// can deviate from heights reported by other servers. Servers exceeding
+	// the deviation are considered lagging or unreliable. The latest block
+	// heights are cross-checked with the keep alive interval. The check is
+	// performed only if at least three servers respond.
+	MaxBlockHeightDeviation uint
+}
+
+// urls returns URLs of all configured Electrum servers, starting with the
+// primary server.
+func (c Config) urls() []string {
+	return append([]string{c.URL}, c.FallbackURLs...)
 }
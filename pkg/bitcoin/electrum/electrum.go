@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -26,15 +27,20 @@ var (
 	logger                    = log.Logger("keep-electrum")
 )
 
-// Connection is a handle for interactions with Electrum server.
+// Connection is a handle for interactions with Electrum servers. The
+// connection holds a pool of Electrum servers and fails over to another
+// server of the pool when the currently used one becomes unavailable
+// or unreliable.
 type Connection struct {
-	parentCtx   context.Context
-	client      *electrum.Client
-	clientMutex *sync.RWMutex
-	config      Config
+	parentCtx context.Context
+	pool      *serverPool
+	config    Config
 }
 
-// Connect initializes handle with provided Config.
+// Connect initializes handle with provided Config. Connections to all
+// configured servers are established, but it is enough that one of them
+// succeeds. Servers that could not be connected are retried later, on
+// failover or during health checks.
 func Connect(parentCtx context.Context, config Config) (bitcoin.Chain, error) {
 	if config.ConnectTimeout == 0 {
 		config.ConnectTimeout = DefaultConnectTimeout
@@ -51,29 +57,87 @@ func Connect(parentCtx context.Context, config Config) (bitcoin.Chain, error) {
 	if config.KeepAliveInterval == 0 {
 		config.KeepAliveInterval = DefaultKeepAliveInterval
 	}
+	if config.MaxBlockHeightDeviation == 0 {
+		config.MaxBlockHeightDeviation = DefaultMaxBlockHeightDeviation
+	}
 
 	c := &Connection{
-		parentCtx:   parentCtx,
-		config:      config,
-		clientMutex: &sync.RWMutex{},
+		parentCtx: parentCtx,
+		pool:      newServerPool(config.urls()),
+		config:    config,
 	}
 
-	if err := c.electrumConnect(); err != nil {
-		return nil, fmt.Errorf("failed to initialize electrum client: [%w]", err)
+	wg := sync.WaitGroup{}
+	wg.Add(c.pool.size())
+
+	errChan := make(chan error, c.pool.size())
+
+	for _, s := range c.pool.servers {
+		go func(s *server) {
+			defer wg.Done()
+
+			if err := c.initializeServer(s); err != nil {
+				logger.Warnf(
+					"failed to initialize electrum server [%s]: [%v]",
+					s.url,
+					err,
+				)
+
+				c.pool.penalize(s, serverMaxScore)
+				errChan <- err
+			}
+		}(s)
 	}
 
-	if err := c.verifyServer(); err != nil {
-		return nil, fmt.Errorf("failed to verify electrum server: [%w]", err)
+	wg.Wait()
+	close(errChan)
+
+	var lastErr error
+	for err := range errChan {
+		lastErr = err
 	}
 
-	// Keep the connection alive and check the connection health.
-	go c.keepAlive()
+	// Fail over to the healthiest of the remaining servers if the primary
+	// server could not be initialized.
+	if c.pool.score(c.pool.activeServer()) == 0 {
+		c.failoverFrom(c.pool.activeServer())
+
+		if c.pool.score(c.pool.activeServer()) == 0 {
+			c.pool.shutdown()
+
+			return nil, fmt.Errorf(
+				"failed to initialize any electrum server: [%w]",
+				lastErr,
+			)
+		}
+	}
 
-	// TODO: Add reconnects on lost connection.
+	logger.Infof("using electrum server [%s]", c.pool.activeServer().url)
+
+	// Keep connections alive and check their health.
+	go c.keepAlive()
 
 	return c, nil
 }
 
+// initializeServer establishes the connection to the given server and
+// verifies the server.
+func (c *Connection) initializeServer(s *server) error {
+	s.clientMutex.Lock()
+	err := c.electrumConnect(s)
+	s.clientMutex.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to initialize electrum client: [%w]", err)
+	}
+
+	if err := c.verifyServer(s); err != nil {
+		return fmt.Errorf("failed to verify electrum server: [%w]", err)
+	}
+
+	return nil
+}
+
 // GetTransaction gets the transaction with the given transaction hash.
 // If the transaction with the given hash was not found on the chain,
 // this function returns an error.
@@ -162,10 +226,7 @@ func (c *Connection) GetTransactionConfirmations(
 	txBlockHeight := int32(math.MinInt32)
 txOutLoop:
 	for _, txOut := range tx.TxOut {
-		script := txOut.PkScript
-		scriptHash := sha256.Sum256(script)
-		reversedScriptHash := byteutils.Reverse(scriptHash[:])
-		reversedScriptHashString := hex.EncodeToString(reversedScriptHash)
+		reversedScriptHashString := computeScriptHash(txOut.PkScript)
 
 		scriptHashHistory, err := requestWithRetry(
 			c,
@@ -252,25 +313,12 @@ func (c *Connection) BroadcastTransaction(
 // GetLatestBlockHeight gets the height of the latest block (tip). If the
 // latest block was not determined, this function returns an error.
 func (c *Connection) GetLatestBlockHeight() (uint, error) {
-	blockHeight, err := requestWithRetry(
-		c,
-		func(ctx context.Context, client *electrum.Client) (int32, error) {
-			headersChan, err := client.SubscribeHeaders(ctx)
-			if err != nil {
-				return 0, fmt.Errorf("failed to get the blocks tip height: [%w]", err)
-			}
-			tip := <-headersChan
-			return tip.Height, nil
-		})
+	blockHeight, err := requestWithRetry(c, getLatestBlockHeight)
 	if err != nil {
 		return 0, fmt.Errorf("failed to subscribe for headers: [%w]", err)
 	}
 
-	if blockHeight > 0 {
-		return uint(blockHeight), nil
-	}
-
-	return 0, nil
+	return blockHeight, nil
 }
 
 // GetBlockHeader gets the block header for the given block height. If the
@@ -300,16 +348,289 @@ func (c *Connection) GetBlockHeader(
 	return blockHeader, nil
 }
 
-func (c *Connection) electrumConnect() error {
+// GetBlockHeaders gets block headers of the given count of consecutive
+// blocks starting from the given block height. The returned headers are
+// ordered by block height in the ascending order. If any of the blocks
+// was not found on the chain, this function returns an error.
+func (c *Connection) GetBlockHeaders(
+	startHeight uint,
+	count uint,
+) ([]*bitcoin.BlockHeader, error) {
+	getBlockHeadersResult, err := requestWithRetry(
+		c,
+		func(
+			ctx context.Context,
+			client *electrum.Client,
+		) (*electrum.GetBlockHeadersResult, error) {
+			return client.GetBlockHeaders(
+				ctx,
+				uint32(startHeight),
+				uint32(count),
+			)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block headers: [%w]", err)
+	}
+
+	if uint(getBlockHeadersResult.Count) != count {
+		return nil, fmt.Errorf(
+			"expected [%v] block headers but got [%v]",
+			count,
+			getBlockHeadersResult.Count,
+		)
+	}
+
+	blockHeaders, err := convertBlockHeaders(getBlockHeadersResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert block headers: [%w]", err)
+	}
+
+	return blockHeaders, nil
+}
+
+// GetTransactionMerkle gets the Merkle branch proving the inclusion of
+// the transaction with the given hash in the block with the given height.
+// If the transaction was not found in the given block, this function
+// returns an error.
+func (c *Connection) GetTransactionMerkle(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	txID := transactionHash.Hex(bitcoin.ReversedByteOrder)
+
+	getMerkleProofResult, err := requestWithRetry(
+		c,
+		func(
+			ctx context.Context,
+			client *electrum.Client,
+		) (*electrum.GetMerkleProofResult, error) {
+			return client.GetMerkleProof(ctx, txID, uint32(blockHeight))
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get merkle proof of transaction [%s]: [%w]",
+			txID,
+			err,
+		)
+	}
+
+	merkleNodes := make([]bitcoin.Hash, len(getMerkleProofResult.Merkle))
+	for i, merkleNode := range getMerkleProofResult.Merkle {
+		// Electrum returns Merkle branch hashes in the reversed byte order.
+		merkleNodes[i], err = bitcoin.NewHashFromString(
+			merkleNode,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse merkle node [%s]: [%w]",
+				merkleNode,
+				err,
+			)
+		}
+	}
+
+	return &bitcoin.TransactionMerkleProof{
+		BlockHeight: uint(getMerkleProofResult.Height),
+		MerkleNodes: merkleNodes,
+		Position:    uint(getMerkleProofResult.Position),
+	}, nil
+}
+
+// GetTransactionsForPublicKeyHash gets confirmed transactions related to
+// the given public key hash, i.e. transactions funding or spending outputs
+// locked using either a P2PKH or P2WPKH script of that hash. The
+// returned transactions are ordered by block height in the ascending
+// order, i.e. the latest transaction is at the end of the slice. The
+// limit parameter determines the maximum number of the latest
+// transactions returned.
+func (c *Connection) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*bitcoin.Transaction, error) {
+	p2pkh, err := bitcoin.PayToPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build P2PKH script: [%w]", err)
+	}
+
+	p2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build P2WPKH script: [%w]", err)
+	}
+
+	// The same transaction can pay both scripts so, make sure it is
+	// returned only once.
+	historyItems := make(map[string]*electrum.GetMempoolResult)
+
+	for _, script := range [][]byte{p2pkh, p2wpkh} {
+		scriptHash := computeScriptHash(script)
+
+		history, err := requestWithRetry(
+			c,
+			func(
+				ctx context.Context,
+				client *electrum.Client,
+			) ([]*electrum.GetMempoolResult, error) {
+				return client.GetHistory(ctx, scriptHash)
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to get history for script [0x%x]: [%w]",
+				script,
+				err,
+			)
+		}
+
+		for _, item := range history {
+			// Unconfirmed transactions have the height of `0` or `-1`.
+			if item.Height <= 0 {
+				continue
+			}
+
+			historyItems[item.Hash] = item
+		}
+	}
+
+	sortedItems := make([]*electrum.GetMempoolResult, 0, len(historyItems))
+	for _, item := range historyItems {
+		sortedItems = append(sortedItems, item)
+	}
+
+	sort.SliceStable(sortedItems, func(i, j int) bool {
+		return sortedItems[i].Height < sortedItems[j].Height
+	})
+
+	if limit > 0 && len(sortedItems) > limit {
+		sortedItems = sortedItems[len(sortedItems)-limit:]
+	}
+
+	transactions := make([]*bitcoin.Transaction, len(sortedItems))
+	for i, item := range sortedItems {
+		transactionHash, err := bitcoin.NewHashFromString(
+			item.Hash,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse hash of transaction [%s]: [%w]",
+				item.Hash,
+				err,
+			)
+		}
+
+		transaction, err := c.GetTransaction(transactionHash)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get transaction [%s]: [%w]",
+				item.Hash,
+				err,
+			)
+		}
+
+		transactions[i] = transaction
+	}
+
+	return transactions, nil
+}
+
+// GetUnspentOutputsForScript gets all unspent outputs locked using the
+// given script. Outputs of unconfirmed transactions are returned as well.
+func (c *Connection) GetUnspentOutputsForScript(
+	script bitcoin.Script,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	scriptHash := computeScriptHash(script)
+
+	unspentOutputs, err := requestWithRetry(
+		c,
+		func(
+			ctx context.Context,
+			client *electrum.Client,
+		) ([]*electrum.ListUnspentResult, error) {
+			return client.ListUnspent(ctx, scriptHash)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get unspent outputs for script [0x%x]: [%w]",
+			script,
+			err,
+		)
+	}
+
+	result := make([]*bitcoin.UnspentTransactionOutput, len(unspentOutputs))
+	for i, unspentOutput := range unspentOutputs {
+		transactionHash, err := bitcoin.NewHashFromString(
+			unspentOutput.Hash,
+			bitcoin.ReversedByteOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse hash of transaction [%s]: [%w]",
+				unspentOutput.Hash,
+				err,
+			)
+		}
+
+		result[i] = &bitcoin.UnspentTransactionOutput{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: transactionHash,
+				OutputIndex:     unspentOutput.Position,
+			},
+			Value: int64(unspentOutput.Value),
+		}
+	}
+
+	return result, nil
+}
+
+// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+// transaction to be confirmed within the given number of blocks. If the
+// fee could not be estimated, this function returns an error.
+func (c *Connection) EstimateSatPerVByteFee(blocks uint32) (int64, error) {
+	// According to Electrum protocol docs, the returned fee is BTC/KB.
+	btcPerKbFee, err := requestWithRetry(
+		c,
+		func(ctx context.Context, client *electrum.Client) (float32, error) {
+			return client.GetFee(ctx, blocks)
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get fee: [%w]", err)
+	}
+
+	// The Electrum server returns -1 if the fee could not be estimated,
+	// e.g. if the daemon does not have enough information.
+	if btcPerKbFee <= 0 {
+		return 0, fmt.Errorf(
+			"fee could not be estimated; server returned [%v]",
+			btcPerKbFee,
+		)
+	}
+
+	// Round the sat/KB value to get rid of the float32 precision artifacts
+	// before converting to sat/vbyte. Round the final value up to not
+	// underestimate the fee.
+	satPerKbFee := int64(math.Round(float64(btcPerKbFee) * 1e8))
+	satPerVByteFee := (satPerKbFee + 999) / 1000
+
+	return satPerVByteFee, nil
+}
+
+// electrumConnect establishes the connection to the given server.
+// The caller must hold the server's client mutex.
+func (c *Connection) electrumConnect(s *server) error {
 	var client *electrum.Client
 	var err error
 	switch c.config.Protocol {
 	case TCP:
-		logger.Debug("establishing TCP connection to electrum server...")
+		logger.Debugf("establishing TCP connection to electrum server [%s]...", s.url)
 		client, err = connectWithRetry(
 			c,
 			func(ctx context.Context) (*electrum.Client, error) {
-				return electrum.NewClientTCP(ctx, c.config.URL)
+				return electrum.NewClientTCP(ctx, s.url)
 			},
 		)
 	case SSL:
@@ -317,11 +638,11 @@ func (c *Connection) electrumConnect() error {
 		// #nosec G402 (TLS InsecureSkipVerify set true)
 		tlsConfig := &tls.Config{InsecureSkipVerify: true}
 
-		logger.Debug("establishing SSL connection to electrum server...")
+		logger.Debugf("establishing SSL connection to electrum server [%s]...", s.url)
 		client, err = connectWithRetry(
 			c,
 			func(ctx context.Context) (*electrum.Client, error) {
-				return electrum.NewClientSSL(ctx, c.config.URL, tlsConfig)
+				return electrum.NewClientSSL(ctx, s.url, tlsConfig)
 			},
 		)
 	default:
@@ -329,20 +650,21 @@ func (c *Connection) electrumConnect() error {
 	}
 
 	if err == nil {
-		c.client = client
+		s.client = client
 	}
 
 	return err
 }
 
-func (c *Connection) verifyServer() error {
+func (c *Connection) verifyServer(s *server) error {
 	type Server struct {
 		version  string
 		protocol string
 	}
 
-	server, err := requestWithRetry(
+	server, err := requestFromServer(
 		c,
+		s,
 		func(ctx context.Context, client *electrum.Client) (*Server, error) {
 			serverVersion, protocolVersion, err := client.ServerVersion(ctx)
 			if err != nil {
@@ -356,7 +678,8 @@ func (c *Connection) verifyServer() error {
 	}
 
 	logger.Infof(
-		"connected to electrum server [version: [%s], protocol: [%s]]",
+		"connected to electrum server [%s] [version: [%s], protocol: [%s]]",
+		s.url,
 		server.version,
 		server.protocol,
 	)
@@ -365,7 +688,7 @@ func (c *Connection) verifyServer() error {
 	if !slices.Contains(supportedProtocolVersions, server.protocol) {
 		logger.Warnf(
 			"electrum server [%s] runs an unsupported protocol version: [%s]; expected one of: [%s]",
-			c.config.URL,
+			s.url,
 			server.protocol,
 			strings.Join(supportedProtocolVersions, ","),
 		)
@@ -374,34 +697,118 @@ func (c *Connection) verifyServer() error {
 	return nil
 }
 
+// keepAlive periodically checks the health of all servers of the pool. The
+// health check requests keep the connections alive as well.
 func (c *Connection) keepAlive() {
 	ticker := time.NewTicker(c.config.KeepAliveInterval)
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			_, err := requestWithRetry(
-				c,
-				func(ctx context.Context, client *electrum.Client) (interface{}, error) {
-					return nil, client.Ping(ctx)
-				},
+			c.checkServersHealth()
+		case <-c.parentCtx.Done():
+			c.pool.shutdown()
+			return
+		}
+	}
+}
+
+// checkServersHealth requests the latest block height from all servers of
+// the pool. Servers that do not respond are reconnected. Servers whose
+// heights deviate from heights reported by other servers are considered
+// lagging or unreliable. Health scores of misbehaving servers are decreased
+// and the pool fails over to another server if the active one misbehaves.
+func (c *Connection) checkServersHealth() {
+	var respondingServers []*server
+	var heights []uint
+
+	for _, s := range c.pool.servers {
+		height, err := requestFromServer(c, s, getLatestBlockHeight)
+		if err != nil {
+			logger.Warnf(
+				"electrum server [%s] health check failed: [%v]; reconnecting",
+				s.url,
+				err,
 			)
-			if err != nil {
+
+			c.pool.penalize(s, serverHealthCheckFailurePenalty)
+
+			// Close the possibly dead socket so the connection is
+			// established again.
+			s.shutdown()
+			if err := c.reconnectIfShutdown(s); err != nil {
 				logger.Errorf(
-					"failed to ping the electrum server; "+
-						"please verify health of the electrum server: [%v]",
+					"failed to reconnect to electrum server [%s]: [%v]",
+					s.url,
 					err,
 				)
-			} else {
-				// Adjust ticker starting at the time of the latest successful ping.
-				ticker = time.NewTicker(c.config.KeepAliveInterval)
 			}
-		case <-c.parentCtx.Done():
-			ticker.Stop()
-			c.client.Shutdown()
-			return
+
+			c.failoverFrom(s)
+			continue
+		}
+
+		respondingServers = append(respondingServers, s)
+		heights = append(heights, height)
+	}
+
+	deviatingServers := findDeviatingHeights(
+		heights,
+		c.config.MaxBlockHeightDeviation,
+	)
+
+	for i, s := range respondingServers {
+		if !slices.Contains(deviatingServers, i) {
+			c.pool.reward(s)
 		}
 	}
+
+	for _, i := range deviatingServers {
+		s := respondingServers[i]
+
+		logger.Warnf(
+			"electrum server [%s] reported block height [%d] deviating "+
+				"from heights reported by other servers: [%v]",
+			s.url,
+			heights[i],
+			heights,
+		)
+
+		c.pool.penalize(s, serverHeightDeviationPenalty)
+		c.failoverFrom(s)
+	}
+}
+
+// failoverFrom switches the active server to another one if the given
+// server is active.
+func (c *Connection) failoverFrom(failed *server) {
+	active, switched, err := c.pool.failover(failed)
+	if err != nil {
+		logger.Warnf(
+			"cannot switch from electrum server [%s]: [%v]",
+			failed.url,
+			err,
+		)
+		return
+	}
+
+	if switched {
+		logger.Warnf(
+			"switched from electrum server [%s] to [%s]",
+			failed.url,
+			active.url,
+		)
+	}
+}
+
+// computeScriptHash computes the script hash in the format expected by the
+// Electrum protocol, i.e. the SHA-256 hash of the script, in the reversed
+// byte order, encoded as a hexadecimal string.
+func computeScriptHash(script []byte) string {
+	scriptHash := sha256.Sum256(script)
+	reversedScriptHash := byteutils.Reverse(scriptHash[:])
+	return hex.EncodeToString(reversedScriptHash)
 }
 
 func connectWithRetry(
@@ -431,26 +838,66 @@ func connectWithRetry(
 	return result, err
 }
 
+// requestWithRetry sends the request to the active server of the pool,
+// retrying it for the configured time. If the retries are exhausted, the
+// pool fails over to another server and the request is retried there.
+// Every server of the pool is tried at most once.
 func requestWithRetry[K interface{}](
 	c *Connection,
 	requestFn func(ctx context.Context, client *electrum.Client) (K, error),
 ) (K, error) {
 	var result K
+	var err error
+
+	for attempt := 0; attempt < c.pool.size(); attempt++ {
+		s := c.pool.activeServer()
+
+		result, err = requestFromServer(c, s, requestFn)
+		if err == nil {
+			c.pool.reward(s)
+			return result, nil
+		}
+
+		if c.parentCtx.Err() != nil {
+			break
+		}
+
+		logger.Warnf(
+			"request to electrum server [%s] failed: [%v]",
+			s.url,
+			err,
+		)
+
+		c.pool.penalize(s, serverRequestFailurePenalty)
+		c.failoverFrom(s)
+	}
+
+	return result, err
+}
+
+// requestFromServer sends the request to the given server, retrying it
+// for the configured time.
+func requestFromServer[K interface{}](
+	c *Connection,
+	s *server,
+	requestFn func(ctx context.Context, client *electrum.Client) (K, error),
+) (K, error) {
+	var result K
 
 	err := wrappers.DoWithDefaultRetry(
 		c.parentCtx,
 		c.config.RequestRetryTimeout,
 		func(ctx context.Context) error {
-			if err := c.reconnectIfShutdown(); err != nil {
+			if err := c.reconnectIfShutdown(s); err != nil {
 				return err
 			}
 
 			requestCtx, requestCancel := context.WithTimeout(ctx, c.config.RequestTimeout)
 			defer requestCancel()
 
-			c.clientMutex.RLock()
-			r, err := requestFn(requestCtx, c.client)
-			c.clientMutex.RUnlock()
+			s.clientMutex.RLock()
+			r, err := requestFn(requestCtx, s.client)
+			s.clientMutex.RUnlock()
 
 			if err != nil {
 				return fmt.Errorf("request failed: [%w]", err)
@@ -463,19 +910,44 @@ func requestWithRetry[K interface{}](
 	return result, err
 }
 
-func (c *Connection) reconnectIfShutdown() error {
-	c.clientMutex.Lock()
-	defer c.clientMutex.Unlock()
+func (c *Connection) reconnectIfShutdown(s *server) error {
+	s.clientMutex.Lock()
+	defer s.clientMutex.Unlock()
 
-	isClientShutdown := c.client.IsShutdown()
-	if isClientShutdown {
-		logger.Warn("connection to electrum server is down; reconnecting...")
-		err := c.electrumConnect()
+	if s.isShutdown() {
+		logger.Warnf(
+			"connection to electrum server [%s] is down; reconnecting...",
+			s.url,
+		)
+		err := c.electrumConnect(s)
 		if err != nil {
-			return fmt.Errorf("failed to reconnect to electrum server: [%w]", err)
+			return fmt.Errorf(
+				"failed to reconnect to electrum server [%s]: [%w]",
+				s.url,
+				err,
+			)
 		}
-		logger.Info("reconnected to electrum server")
+		logger.Infof("reconnected to electrum server [%s]", s.url)
 	}
 
 	return nil
 }
+
+// getLatestBlockHeight gets the height of the latest block (tip) known to
+// the server the given client is connected to.
+func getLatestBlockHeight(
+	ctx context.Context,
+	client *electrum.Client,
+) (uint, error) {
+	headersChan, err := client.SubscribeHeaders(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the blocks tip height: [%w]", err)
+	}
+	tip := <-headersChan
+
+	if tip.Height > 0 {
+		return uint(tip.Height), nil
+	}
+
+	return 0, nil
+}
@@ -290,6 +290,28 @@ func TestGetBlockHeader_Negative_Integration(t *testing.T) {
 	}
 }
 
+func TestEstimateSatPerVByteFee_Integration(t *testing.T) {
+	for testName, config := range configs {
+		t.Run(testName, func(t *testing.T) {
+			electrum := newTestConnection(t, config)
+
+			result, err := electrum.EstimateSatPerVByteFee(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The result is always at least 1 sat/vbyte.
+			if result < 1 {
+				t.Errorf(
+					"invalid result (greater or equal match)\nexpected: %v\nactual:   %v",
+					1,
+					result,
+				)
+			}
+		})
+	}
+}
+
 func newTestConnection(t *testing.T, config Config) bitcoin.Chain {
 	electrum, err := Connect(context.Background(), config)
 	if err != nil {
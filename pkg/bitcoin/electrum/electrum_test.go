@@ -0,0 +1,73 @@
+package electrum
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/checksum0/go-electrum/electrum"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+func TestComputeScriptHash(t *testing.T) {
+	// Example taken from the Electrum protocol documentation:
+	// https://electrumx.readthedocs.io/en/latest/protocol-basics.html#script-hashes
+	script, err := hex.DecodeString(
+		"76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertStringsEqual(
+		t,
+		"script hash",
+		"8b01df4e368ea28f8dc0423bcf7a4923e3a12d307c875e47a0cfbf90b5c39161",
+		computeScriptHash(script),
+	)
+}
+
+func TestConvertBlockHeaders(t *testing.T) {
+	expectedHeaders := []*bitcoin.BlockHeader{
+		{
+			Version:                 1,
+			PreviousBlockHeaderHash: bitcoin.Hash{0x01},
+			MerkleRootHash:          bitcoin.Hash{0x02},
+			Time:                    1641914003,
+			Bits:                    436256810,
+			Nonce:                   778087099,
+		},
+		{
+			Version:                 2,
+			PreviousBlockHeaderHash: bitcoin.Hash{0x03},
+			MerkleRootHash:          bitcoin.Hash{0x04},
+			Time:                    1641914603,
+			Bits:                    436256810,
+			Nonce:                   2184722531,
+		},
+	}
+
+	var headersBytes []byte
+	for _, header := range expectedHeaders {
+		serializedHeader := header.Serialize()
+		headersBytes = append(headersBytes, serializedHeader[:]...)
+	}
+
+	headers, err := convertBlockHeaders(&electrum.GetBlockHeadersResult{
+		Count:   uint32(len(expectedHeaders)),
+		Headers: hex.EncodeToString(headersBytes),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(expectedHeaders, headers) {
+		t.Errorf(
+			"unexpected block headers\nexpected: %v\nactual:   %v\n",
+			expectedHeaders,
+			headers,
+		)
+	}
+}
@@ -0,0 +1,216 @@
+package electrum
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/checksum0/go-electrum/electrum"
+)
+
+const (
+	// serverMaxScore is the maximum health score of an Electrum server. All
+	// servers start with this score.
+	serverMaxScore = 100
+	// serverSuccessReward is the score added to the server's health score
+	// after each successful request.
+	serverSuccessReward = 1
+	// serverRequestFailurePenalty is the score subtracted from the server's
+	// health score when request retries against the server are exhausted.
+	serverRequestFailurePenalty = 25
+	// serverHealthCheckFailurePenalty is the score subtracted from the server's
+	// health score when the server does not respond to a health check.
+	serverHealthCheckFailurePenalty = 50
+	// serverHeightDeviationPenalty is the score subtracted from the server's
+	// health score when the latest block height reported by the server
+	// deviates from the height reported by other servers.
+	serverHeightDeviationPenalty = 50
+)
+
+// server represents a single Electrum server of the connection pool.
+type server struct {
+	url string
+
+	clientMutex sync.RWMutex
+	// client is nil if the connection to the server has never been
+	// established.
+	client *electrum.Client
+}
+
+// isShutdown determines whether the connection to the server is down.
+// The caller must hold the client mutex.
+func (s *server) isShutdown() bool {
+	return s.client == nil || s.client.IsShutdown()
+}
+
+// shutdown closes the connection to the server, if established.
+func (s *server) shutdown() {
+	s.clientMutex.Lock()
+	defer s.clientMutex.Unlock()
+
+	if s.client != nil {
+		s.client.Shutdown()
+	}
+}
+
+// serverPool holds all Electrum servers the connection can use along with
+// their health scores. All requests are sent to the active server. The pool
+// fails over to the healthiest of remaining servers when the active server
+// misbehaves.
+type serverPool struct {
+	mutex sync.RWMutex
+
+	servers []*server
+	scores  map[*server]int
+	active  *server
+}
+
+func newServerPool(urls []string) *serverPool {
+	servers := make([]*server, len(urls))
+	scores := make(map[*server]int, len(urls))
+
+	for i, url := range urls {
+		servers[i] = &server{url: url}
+		scores[servers[i]] = serverMaxScore
+	}
+
+	return &serverPool{
+		servers: servers,
+		scores:  scores,
+		active:  servers[0],
+	}
+}
+
+// activeServer returns the server requests should be sent to.
+func (sp *serverPool) activeServer() *server {
+	sp.mutex.RLock()
+	defer sp.mutex.RUnlock()
+
+	return sp.active
+}
+
+// size returns the number of servers in the pool.
+func (sp *serverPool) size() int {
+	return len(sp.servers)
+}
+
+// score returns the current health score of the given server.
+func (sp *serverPool) score(s *server) int {
+	sp.mutex.RLock()
+	defer sp.mutex.RUnlock()
+
+	return sp.scores[s]
+}
+
+// reward increases the health score of the given server.
+func (sp *serverPool) reward(s *server) {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	if score := sp.scores[s] + serverSuccessReward; score < serverMaxScore {
+		sp.scores[s] = score
+	} else {
+		sp.scores[s] = serverMaxScore
+	}
+}
+
+// penalize decreases the health score of the given server by the given
+// penalty. The score never drops below zero.
+func (sp *serverPool) penalize(s *server, penalty int) {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	if score := sp.scores[s] - penalty; score > 0 {
+		sp.scores[s] = score
+	} else {
+		sp.scores[s] = 0
+	}
+}
+
+// activate makes the given server active.
+func (sp *serverPool) activate(s *server) {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	sp.active = s
+}
+
+// failover switches the active server to the healthiest server other than
+// the failed one. Servers with equal scores are preferred in the order they
+// were configured. The function does nothing if the failed server is no
+// longer active, i.e. another request has already failed over. Returns
+// the active server after the failover and a flag indicating whether the
+// active server has changed. Returns an error and keeps the failed server
+// active if there is no other server with a non-zero health score.
+func (sp *serverPool) failover(failed *server) (*server, bool, error) {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	if sp.active != failed {
+		return sp.active, false, nil
+	}
+
+	var candidate *server
+	for _, s := range sp.servers {
+		if s == failed {
+			continue
+		}
+
+		if candidate == nil || sp.scores[s] > sp.scores[candidate] {
+			candidate = s
+		}
+	}
+
+	if candidate == nil || sp.scores[candidate] == 0 {
+		return sp.active, false, fmt.Errorf(
+			"no healthy electrum server to fail over to",
+		)
+	}
+
+	sp.active = candidate
+
+	return candidate, true, nil
+}
+
+// shutdown closes connections to all servers of the pool.
+func (sp *serverPool) shutdown() {
+	for _, s := range sp.servers {
+		s.shutdown()
+	}
+}
+
+// findDeviatingHeights returns indices of block heights deviating from the
+// median of all given heights by more than the given maximum deviation.
+// The median is used as a reference to detect both lagging servers and
+// servers reporting heights ahead of the rest of the network. For an even
+// number of heights, the higher of the two middle values is used. At least
+// three heights are required to tell which one deviates; with fewer heights
+// the function returns nothing so that an honest server is not penalized
+// because of a single misbehaving one.
+func findDeviatingHeights(heights []uint, maxDeviation uint) []int {
+	if len(heights) < 3 {
+		return nil
+	}
+
+	sorted := make([]uint, len(heights))
+	copy(sorted, heights)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	median := sorted[len(sorted)/2]
+
+	var deviating []int
+	for i, height := range heights {
+		var deviation uint
+		if height > median {
+			deviation = height - median
+		} else {
+			deviation = median - height
+		}
+
+		if deviation > maxDeviation {
+			deviating = append(deviating, i)
+		}
+	}
+
+	return deviating
+}
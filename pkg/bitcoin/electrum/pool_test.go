@@ -0,0 +1,206 @@
+package electrum
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+func TestServerPool_Failover(t *testing.T) {
+	var tests = map[string]struct {
+		scores         []int
+		failedIndex    int
+		expectedActive int
+		expectedSwitch bool
+		expectedError  error
+	}{
+		"single server": {
+			scores:         []int{0},
+			failedIndex:    0,
+			expectedActive: 0,
+			expectedSwitch: false,
+			expectedError: fmt.Errorf(
+				"no healthy electrum server to fail over to",
+			),
+		},
+		"no healthy server": {
+			scores:         []int{10, 0, 0},
+			failedIndex:    0,
+			expectedActive: 0,
+			expectedSwitch: false,
+			expectedError: fmt.Errorf(
+				"no healthy electrum server to fail over to",
+			),
+		},
+		"healthiest server is chosen": {
+			scores:         []int{10, 50, 80},
+			failedIndex:    0,
+			expectedActive: 2,
+			expectedSwitch: true,
+		},
+		"configuration order is preferred on equal scores": {
+			scores:         []int{10, 80, 80},
+			failedIndex:    0,
+			expectedActive: 1,
+			expectedSwitch: true,
+		},
+		"failed server is never chosen": {
+			scores:         []int{100, 10, 20},
+			failedIndex:    0,
+			expectedActive: 2,
+			expectedSwitch: true,
+		},
+		"failed server is not active": {
+			scores:         []int{100, 10, 20},
+			failedIndex:    1,
+			expectedActive: 0,
+			expectedSwitch: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			urls := make([]string, len(test.scores))
+			for i := range urls {
+				urls[i] = string(rune('a' + i))
+			}
+
+			pool := newServerPool(urls)
+			for i, score := range test.scores {
+				pool.scores[pool.servers[i]] = score
+			}
+
+			active, switched, err := pool.failover(
+				pool.servers[test.failedIndex],
+			)
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected error\nexpected: %v\nactual:   %v\n",
+					test.expectedError,
+					err,
+				)
+			}
+
+			testutils.AssertStringsEqual(
+				t,
+				"active server",
+				pool.servers[test.expectedActive].url,
+				active.url,
+			)
+			testutils.AssertStringsEqual(
+				t,
+				"pool active server",
+				pool.servers[test.expectedActive].url,
+				pool.activeServer().url,
+			)
+			testutils.AssertBoolsEqual(
+				t,
+				"switched",
+				test.expectedSwitch,
+				switched,
+			)
+		})
+	}
+}
+
+func TestServerPool_Scores(t *testing.T) {
+	pool := newServerPool([]string{"a"})
+	s := pool.activeServer()
+
+	testutils.AssertIntsEqual(t, "initial score", serverMaxScore, pool.score(s))
+
+	pool.reward(s)
+	testutils.AssertIntsEqual(t, "score after reward", serverMaxScore, pool.score(s))
+
+	pool.penalize(s, serverRequestFailurePenalty)
+	testutils.AssertIntsEqual(
+		t,
+		"score after penalty",
+		serverMaxScore-serverRequestFailurePenalty,
+		pool.score(s),
+	)
+
+	pool.reward(s)
+	testutils.AssertIntsEqual(
+		t,
+		"score after penalty and reward",
+		serverMaxScore-serverRequestFailurePenalty+serverSuccessReward,
+		pool.score(s),
+	)
+
+	pool.penalize(s, 2*serverMaxScore)
+	testutils.AssertIntsEqual(t, "score after max penalty", 0, pool.score(s))
+}
+
+func TestFindDeviatingHeights(t *testing.T) {
+	var tests = map[string]struct {
+		heights      []uint
+		maxDeviation uint
+		expected     []int
+	}{
+		"no heights": {
+			heights:      []uint{},
+			maxDeviation: 3,
+			expected:     nil,
+		},
+		"single height": {
+			heights:      []uint{100},
+			maxDeviation: 3,
+			expected:     nil,
+		},
+		"heights within deviation": {
+			heights:      []uint{100, 103, 98},
+			maxDeviation: 3,
+			expected:     nil,
+		},
+		"lagging server": {
+			heights:      []uint{100, 101, 90},
+			maxDeviation: 3,
+			expected:     []int{2},
+		},
+		"server ahead of the network": {
+			heights:      []uint{200, 101, 100},
+			maxDeviation: 3,
+			expected:     []int{0},
+		},
+		"two servers with lagging one": {
+			heights:      []uint{90, 100},
+			maxDeviation: 3,
+			expected:     nil,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			actual := findDeviatingHeights(test.heights, test.maxDeviation)
+
+			if !reflect.DeepEqual(test.expected, actual) {
+				t.Errorf(
+					"unexpected deviating heights\nexpected: %v\nactual:   %v\n",
+					test.expected,
+					actual,
+				)
+			}
+		})
+	}
+}
+
+func TestConfig_Urls(t *testing.T) {
+	config := Config{
+		URL:          "primary:50001",
+		FallbackURLs: []string{"fallback1:50001", "fallback2:50001"},
+	}
+
+	expected := []string{"primary:50001", "fallback1:50001", "fallback2:50001"}
+	actual := config.urls()
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf(
+			"unexpected urls\nexpected: %v\nactual:   %v\n",
+			expected,
+			actual,
+		)
+	}
+}
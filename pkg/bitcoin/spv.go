@@ -0,0 +1,58 @@
+package bitcoin
+
+// TransactionMerkleProof holds the Merkle branch proving the inclusion of
+// a transaction in a block.
+type TransactionMerkleProof struct {
+	// BlockHeight is the height of the block the transaction was included in.
+	BlockHeight uint
+
+	// MerkleNodes is the list of hashes forming the Merkle branch of the
+	// transaction. The hashes are ordered from the bottom of the Merkle tree
+	// to its top, i.e. the first hash is the sibling of the transaction hash.
+	MerkleNodes []Hash
+
+	// Position is the 0-based position of the transaction in the block.
+	Position uint
+}
+
+// SpvProof contains data required to prove the inclusion of a Bitcoin
+// transaction in the Bitcoin blockchain using simplified payment verification.
+type SpvProof struct {
+	// MerkleProof is the concatenation of the Merkle branch hashes, in the
+	// internal byte order, proving the inclusion of the transaction in
+	// the block.
+	MerkleProof []byte
+
+	// TxIndexInBlock is the 0-based position of the transaction in the block.
+	TxIndexInBlock uint
+
+	// BitcoinHeaders is the concatenation of serialized block headers,
+	// starting from the header of the block containing the transaction and
+	// followed by headers of consecutive blocks confirming it.
+	BitcoinHeaders []byte
+}
+
+// NewSpvProof constructs an SPV proof from the given Merkle proof of the
+// transaction and the given headers of consecutive blocks starting from the
+// block containing the transaction.
+func NewSpvProof(
+	merkleProof *TransactionMerkleProof,
+	blockHeaders []*BlockHeader,
+) *SpvProof {
+	merkleProofBytes := make([]byte, 0, len(merkleProof.MerkleNodes)*HashByteLength)
+	for _, merkleNode := range merkleProof.MerkleNodes {
+		merkleProofBytes = append(merkleProofBytes, merkleNode[:]...)
+	}
+
+	headersBytes := make([]byte, 0, len(blockHeaders)*BlockHeaderByteLength)
+	for _, blockHeader := range blockHeaders {
+		serializedHeader := blockHeader.Serialize()
+		headersBytes = append(headersBytes, serializedHeader[:]...)
+	}
+
+	return &SpvProof{
+		MerkleProof:    merkleProofBytes,
+		TxIndexInBlock: merkleProof.Position,
+		BitcoinHeaders: headersBytes,
+	}
+}
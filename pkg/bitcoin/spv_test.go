@@ -0,0 +1,41 @@
+package bitcoin
+
+import (
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+func TestNewSpvProof(t *testing.T) {
+	merkleProof := &TransactionMerkleProof{
+		BlockHeight: 100,
+		MerkleNodes: []Hash{{0x01}, {0x02}},
+		Position:    3,
+	}
+
+	blockHeaders := []*BlockHeader{
+		{Version: 1, Bits: 0xaa},
+		{Version: 2, Bits: 0xbb},
+	}
+
+	proof := NewSpvProof(merkleProof, blockHeaders)
+
+	var expectedMerkleProof []byte
+	expectedMerkleProof = append(expectedMerkleProof, merkleProof.MerkleNodes[0][:]...)
+	expectedMerkleProof = append(expectedMerkleProof, merkleProof.MerkleNodes[1][:]...)
+
+	var expectedHeaders []byte
+	for _, blockHeader := range blockHeaders {
+		serializedHeader := blockHeader.Serialize()
+		expectedHeaders = append(expectedHeaders, serializedHeader[:]...)
+	}
+
+	testutils.AssertBytesEqual(t, expectedMerkleProof, proof.MerkleProof)
+	testutils.AssertBytesEqual(t, expectedHeaders, proof.BitcoinHeaders)
+	testutils.AssertIntsEqual(
+		t,
+		"transaction index in block",
+		3,
+		int(proof.TxIndexInBlock),
+	)
+}
@@ -1,6 +1,11 @@
 package bitcoin
 
-import "bytes"
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/btcsuite/btcd/wire"
+)
 
 // TransactionSerializationFormat represents the Bitcoin transaction
 // serialization format.
@@ -124,6 +129,52 @@ func (t *Transaction) WitnessHash() Hash {
 	return ComputeHash(t.Serialize(Witness))
 }
 
+// SerializeVersion serializes the transaction version to a little-endian
+// 4-byte array as required by the Bitcoin transaction serialization format.
+func (t *Transaction) SerializeVersion() [4]byte {
+	var result [4]byte
+	binary.LittleEndian.PutUint32(result[:], uint32(t.Version))
+	return result
+}
+
+// SerializeInputs serializes the transaction inputs to a byte array prepended
+// by a CompactSizeUint denoting the total number of inputs. The result does
+// not include the witness data of inputs.
+func (t *Transaction) SerializeInputs() []byte {
+	standard := t.Serialize(Standard)
+	outputsLength := len(t.SerializeOutputs())
+	// The Standard format is [version][inputs][outputs][locktime] where
+	// version and locktime are 4 bytes long.
+	return standard[4 : len(standard)-outputsLength-4]
+}
+
+// SerializeOutputs serializes the transaction outputs to a byte array
+// prepended by a CompactSizeUint denoting the total number of outputs.
+func (t *Transaction) SerializeOutputs() []byte {
+	buffer := new(bytes.Buffer)
+
+	// Writing to a bytes.Buffer never returns an error.
+	_ = wire.WriteVarInt(buffer, 0, uint64(len(t.Outputs)))
+	for _, output := range t.Outputs {
+		_ = wire.WriteTxOut(
+			buffer,
+			0,
+			0,
+			wire.NewTxOut(output.Value, output.PublicKeyScript),
+		)
+	}
+
+	return buffer.Bytes()
+}
+
+// SerializeLocktime serializes the transaction locktime to a little-endian
+// 4-byte array as required by the Bitcoin transaction serialization format.
+func (t *Transaction) SerializeLocktime() [4]byte {
+	var result [4]byte
+	binary.LittleEndian.PutUint32(result[:], t.Locktime)
+	return result
+}
+
 // TransactionOutpoint represents a Bitcoin transaction outpoint.
 // For reference, see:
 // https://developer.bitcoin.org/reference/transactions.html#outpoint-the-specific-part-of-a-specific-output
@@ -11,6 +11,11 @@ import (
 	"github.com/btcsuite/btcd/wire"
 )
 
+// witnessScaleFactor is the factor by which the non-witness data of the
+// transaction is scaled while computing the transaction weight, as defined
+// by BIP-0141.
+const witnessScaleFactor = 4
+
 // TransactionBuilder is a component that is responsible for the whole
 // transaction creation process. It assembles an unsigned transaction,
 // prepares it for signing, and applies the given signatures in order to
@@ -254,50 +259,58 @@ func (tb *TransactionBuilder) AddSignatures(
 			signature.PublicKey,
 		).SerializeCompressed()
 
-		sigHashArgs := tb.sigHashArgs[i]
-
-		if sigHashArgs.witness {
-			witness := wire.TxWitness{
-				signatureBytes,
-				publicKeyBytes,
-			}
-
-			// If the Witness field was pre-filled with data, put them at
-			// the end of the final witness field. This is the case for
-			// P2WSH inputs.
-			if len(input.Witness) == 1 {
-				witness = append(witness, input.Witness[0])
-			}
-
-			input.Witness = witness
-		} else {
-			builder := txscript.NewScriptBuilder().
-				AddData(signatureBytes).
-				AddData(publicKeyBytes)
-
-			// If the SignatureScript field was pre-filled with data, put them
-			// at the end of the final SignatureScript field. This is the case
-			// for P2SH inputs.
-			if len(input.SignatureScript) > 0 {
-				builder.AddData(input.SignatureScript)
-			}
-
-			script, err := builder.Script()
-			if err != nil {
-				return nil, fmt.Errorf(
-					"cannot build signature script for input [%v]: [%v]",
-					i,
-					err,
-				)
-			}
-
-			input.SignatureScript = script
+		err := fillSignatureData(
+			input,
+			tb.sigHashArgs[i].witness,
+			signatureBytes,
+			publicKeyBytes,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot build signature script for input [%v]: [%v]",
+				i,
+				err,
+			)
 		}
 	}
 
 	return tb.internal.toTransaction(), nil
 }
 
+// EstimateVirtualSize estimates the virtual size of the transaction being
+// built, as defined by BIP-0141. Inputs signatures are not known before
+// signing so, this function assumes each input will be signed using
+// a signature of the maximum possible length. The returned value is
+// therefore an upper bound of the final transaction's virtual size and can
+// be safely used to compute the transaction fee before signing.
+func (tb *TransactionBuilder) EstimateVirtualSize() (int64, error) {
+	// Work on a copy to not alter the state of the transaction being built.
+	estimated := &internalTransaction{tb.internal.Copy()}
+
+	// A DER signature takes at most 72 bytes. One more byte is needed for
+	// the sighash type. Public keys are always compressed.
+	dummySignature := make([]byte, 73)
+	dummyPublicKey := make([]byte, 33)
+
+	for i, input := range estimated.TxIn {
+		err := fillSignatureData(
+			input,
+			tb.sigHashArgs[i].witness,
+			dummySignature,
+			dummyPublicKey,
+		)
+		if err != nil {
+			return 0, fmt.Errorf(
+				"cannot build signature script for input [%v]: [%v]",
+				i,
+				err,
+			)
+		}
+	}
+
+	return estimated.virtualSize(), nil
+}
+
 // TotalInputsValue returns the total value of transaction inputs.
 func (tb *TransactionBuilder) TotalInputsValue() int64 {
 	totalInputsValue := int64(0)
@@ -309,6 +322,55 @@ func (tb *TransactionBuilder) TotalInputsValue() int64 {
 	return totalInputsValue
 }
 
+// fillSignatureData puts the given signature and public key into the
+// given input. For witness inputs, the data lands in the witness field.
+// Otherwise, the signature script is built. Data already present in the
+// input, i.e. the redeem script of P2SH/P2WSH inputs, is put at the end.
+func fillSignatureData(
+	input *wire.TxIn,
+	witness bool,
+	signatureBytes []byte,
+	publicKeyBytes []byte,
+) error {
+	if witness {
+		inputWitness := wire.TxWitness{
+			signatureBytes,
+			publicKeyBytes,
+		}
+
+		// If the Witness field was pre-filled with data, put them at
+		// the end of the final witness field. This is the case for
+		// P2WSH inputs.
+		if len(input.Witness) == 1 {
+			inputWitness = append(inputWitness, input.Witness[0])
+		}
+
+		input.Witness = inputWitness
+
+		return nil
+	}
+
+	builder := txscript.NewScriptBuilder().
+		AddData(signatureBytes).
+		AddData(publicKeyBytes)
+
+	// If the SignatureScript field was pre-filled with data, put them
+	// at the end of the final SignatureScript field. This is the case
+	// for P2SH inputs.
+	if len(input.SignatureScript) > 0 {
+		builder.AddData(input.SignatureScript)
+	}
+
+	script, err := builder.Script()
+	if err != nil {
+		return err
+	}
+
+	input.SignatureScript = script
+
+	return nil
+}
+
 // inputSigHashArgs is a helper structure holding some arguments required to
 // compute a sighash for the given input.
 type inputSigHashArgs struct {
@@ -363,6 +425,17 @@ func (it *internalTransaction) fromTransaction(transaction *Transaction) {
 	it.LockTime = transaction.Locktime
 }
 
+// virtualSize computes the virtual size of the transaction, i.e. its weight
+// divided by 4 and rounded up, as defined by BIP-0141.
+func (it *internalTransaction) virtualSize() int64 {
+	baseSize := it.SerializeSizeStripped()
+	totalSize := it.SerializeSize()
+
+	weight := baseSize*(witnessScaleFactor-1) + totalSize
+
+	return int64((weight + witnessScaleFactor - 1) / witnessScaleFactor)
+}
+
 func (it *internalTransaction) toTransaction() *Transaction {
 	inputs := make([]*TransactionInput, len(it.TxIn))
 	for i, input := range it.TxIn {
@@ -234,6 +234,7 @@ func TestTransactionBuilder_Signing(t *testing.T) {
 		signatures                   []*SignatureContainer
 		expectedSigHashesHexes       []string
 		expectedSignedTransactionHex string
+		expectedVirtualSize          int64
 	}{
 		// https://live.blockcypher.com/btc-testnet/tx/435d4aff6d4bc34134877bd3213c17970142fdd04d4113d534120033b9eecb2e
 		"P2WPKH, P2SH and P2WSH inputs with one P2WPKH output": {
@@ -294,6 +295,7 @@ func TestTransactionBuilder_Signing(t *testing.T) {
 				"126b2edd1b3c28dbff6cd48a9eb666558cb59d1008db60bb5f7bbf1a0d45e588",
 			},
 			expectedSignedTransactionHex: "010000000001036896f9abcac13ce6bd2b80d125bedf997ff6330e999f2f605ea15ea542f2eaf80000000000ffffffffed0ae94da996c6f3b89dfe967675d4808251db93e81022ae9e038d06f92efed400000000c948304502210092327ddff69a2b8c7ae787c5d590a2f14586089e6339e942d56e82aa42052cd902204c0d1700ba1ac617da27fee032a57937c9607f0187199ed3c46954df845643d7012103989d253b17a6a0f41838b84ff0d20e8898f9d7b1a98f2564da4cc29dcf8581d94c5c14934b98637ca318a4d6e7ca6ffd1690b8e77df6377508f9f0c90d000395237576a9148db50eb52063ea9d98b3eac91489a90f738986f68763ac6776a914e257eccafbc07c381642ce6e7e55120fb077fbed8804e0250162b175ac68ffffffffe37f552fc23fa0032bfd00c8eef5f5c22bf85fe4c6e735857719ff8a4ff66eb80000000000ffffffff0180ed0000000000001600148db50eb52063ea9d98b3eac91489a90f738986f602483045022100baf754252d0d6a49aceba7eb0ec40b4cc568e8c659e168b96598a11cf56dc078022051117466ee998a3fc72221006817e8cfe9c2e71ad622ff811a0bf100d888d49c012103989d253b17a6a0f41838b84ff0d20e8898f9d7b1a98f2564da4cc29dcf8581d90003473044022014a535eb334656665ac69a678dbf7c019c4f13262e9ea4d195c61a00cd5f698d022023c0062913c4614bdff07f94475ceb4c585df53f71611776c3521ed8f8785913012103989d253b17a6a0f41838b84ff0d20e8898f9d7b1a98f2564da4cc29dcf8581d95c14934b98637ca318a4d6e7ca6ffd1690b8e77df6377508f9f0c90d000395237576a9148db50eb52063ea9d98b3eac91489a90f738986f68763ac6776a914e257eccafbc07c381642ce6e7e55120fb077fbed8804e0250162b175ac6800000000",
+			expectedVirtualSize:          445,
 		},
 		// https://live.blockcypher.com/btc-testnet/tx/7831d0dfde7e160f3b9bb66c433710f0d3110d73ea78b9db65e81c091a6718a0
 		"P2WSH and P2PKH inputs with one P2WPKH output": {
@@ -342,6 +344,7 @@ func TestTransactionBuilder_Signing(t *testing.T) {
 				"f75ee5a069404db9a8684159589c59b01c913135a47d36828b433019e46733f1",
 			},
 			expectedSignedTransactionHex: "01000000000102173a201f597a2c8ccd7842303a6653bb87437fb08dae671731a075403b32a2fd0000000000ffffffffe19612be756bf7e740b47bec0e24845089ace48c78d473cb34949b3007c4a2c8000000006a47304402204382deb051f9f3e2b539e4bac2d1a50faf8d66bc7a3a3f3d286dabd96d92b58b02207c74c6aaf48e25d07e02bb4039606d77ecfd80c492c050ab2486af6027fc2d5a012103989d253b17a6a0f41838b84ff0d20e8898f9d7b1a98f2564da4cc29dcf8581d9ffffffff0108840000000000001600148db50eb52063ea9d98b3eac91489a90f738986f603483045022100c52bc876cdee80a3061ace3ffbce5e860942d444cd38e00e5f63fd8e818d7e7c022040a7017bb8213991697705e7092c481526c788a4731d06e582dc1c57bed7243b012103989d253b17a6a0f41838b84ff0d20e8898f9d7b1a98f2564da4cc29dcf8581d95c14934b98637ca318a4d6e7ca6ffd1690b8e77df6377508f9f0c90d000395237576a9148db50eb52063ea9d98b3eac91489a90f738986f68763ac6776a914e257eccafbc07c381642ce6e7e55120fb077fbed880448f2b262b175ac680000000000",
+			expectedVirtualSize:          283,
 		},
 	}
 
@@ -387,6 +390,18 @@ func TestTransactionBuilder_Signing(t *testing.T) {
 				})
 			}
 
+			virtualSize, err := builder.EstimateVirtualSize()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"estimated virtual size",
+				int(test.expectedVirtualSize),
+				int(virtualSize),
+			)
+
 			sigHashes, err := builder.ComputeSignatureHashes()
 			if err != nil {
 				t.Fatal(err)
@@ -425,6 +440,19 @@ func TestTransactionBuilder_Signing(t *testing.T) {
 				transaction.Serialize(),
 				hexToSlice(t, test.expectedSignedTransactionHex),
 			)
+
+			// The estimation assumes signatures of the maximum length so,
+			// it must not be lower than the actual virtual size.
+			signedTransaction := newInternalTransaction()
+			signedTransaction.fromTransaction(transaction)
+			if signedTransaction.virtualSize() > virtualSize {
+				t.Errorf(
+					"estimated virtual size [%v] is lower than "+
+						"the actual one [%v]",
+					virtualSize,
+					signedTransaction.virtualSize(),
+				)
+			}
 		})
 	}
 }
@@ -98,6 +98,34 @@ func TestTransaction_WitnessHash(t *testing.T) {
 	)
 }
 
+func TestTransaction_SerializeParts(t *testing.T) {
+	transaction := transactionFixture(t)
+
+	version := transaction.SerializeVersion()
+	inputs := transaction.SerializeInputs()
+	outputs := transaction.SerializeOutputs()
+	locktime := transaction.SerializeLocktime()
+
+	testutils.AssertBytesEqual(t, []byte{0x01, 0x00, 0x00, 0x00}, version[:])
+	testutils.AssertBytesEqual(t, []byte{0x00, 0x00, 0x00, 0x00}, locktime[:])
+
+	testutils.AssertIntsEqual(t, "inputs count", 3, int(inputs[0]))
+	testutils.AssertIntsEqual(t, "outputs count", 1, int(outputs[0]))
+
+	// Concatenated parts must form the Standard serialization format.
+	var concatenated []byte
+	concatenated = append(concatenated, version[:]...)
+	concatenated = append(concatenated, inputs...)
+	concatenated = append(concatenated, outputs...)
+	concatenated = append(concatenated, locktime[:]...)
+
+	testutils.AssertBytesEqual(
+		t,
+		transaction.Serialize(Standard),
+		concatenated,
+	)
+}
+
 // transactionFixture returns a real testnet transaction:
 // https://live.blockcypher.com/btc-testnet/tx/435d4aff6d4bc34134877bd3213c17970142fdd04d4113d534120033b9eecb2e
 func transactionFixture(t *testing.T) *Transaction {
@@ -0,0 +1,366 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package abi
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// EcdsaDkgValidatorMetaData contains all meta data concerning the EcdsaDkgValidator contract.
+var EcdsaDkgValidatorMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[],\"name\":\"activeThreshold\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"groupSize\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"groupThreshold\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"publicKeyByteSize\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"signatureByteSize\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"sortitionPool\",\"outputs\":[{\"internalType\":\"contractSortitionPool\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// EcdsaDkgValidatorABI is the input ABI used to generate the binding from.
+// Deprecated: Use EcdsaDkgValidatorMetaData.ABI instead.
+var EcdsaDkgValidatorABI = EcdsaDkgValidatorMetaData.ABI
+
+// EcdsaDkgValidator is an auto generated Go binding around an Ethereum contract.
+type EcdsaDkgValidator struct {
+	EcdsaDkgValidatorCaller     // Read-only binding to the contract
+	EcdsaDkgValidatorTransactor // Write-only binding to the contract
+	EcdsaDkgValidatorFilterer   // Log filterer for contract events
+}
+
+// EcdsaDkgValidatorCaller is an auto generated read-only Go binding around an Ethereum contract.
+type EcdsaDkgValidatorCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// EcdsaDkgValidatorTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type EcdsaDkgValidatorTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// EcdsaDkgValidatorFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type EcdsaDkgValidatorFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// EcdsaDkgValidatorSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type EcdsaDkgValidatorSession struct {
+	Contract     *EcdsaDkgValidator // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts      // Call options to use throughout this session
+	TransactOpts bind.TransactOpts  // Transaction auth options to use throughout this session
+}
+
+// EcdsaDkgValidatorCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type EcdsaDkgValidatorCallerSession struct {
+	Contract *EcdsaDkgValidatorCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts            // Call options to use throughout this session
+}
+
+// EcdsaDkgValidatorTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type EcdsaDkgValidatorTransactorSession struct {
+	Contract     *EcdsaDkgValidatorTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts            // Transaction auth options to use throughout this session
+}
+
+// EcdsaDkgValidatorRaw is an auto generated low-level Go binding around an Ethereum contract.
+type EcdsaDkgValidatorRaw struct {
+	Contract *EcdsaDkgValidator // Generic contract binding to access the raw methods on
+}
+
+// EcdsaDkgValidatorCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type EcdsaDkgValidatorCallerRaw struct {
+	Contract *EcdsaDkgValidatorCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// EcdsaDkgValidatorTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type EcdsaDkgValidatorTransactorRaw struct {
+	Contract *EcdsaDkgValidatorTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewEcdsaDkgValidator creates a new instance of EcdsaDkgValidator, bound to a specific deployed contract.
+func NewEcdsaDkgValidator(address common.Address, backend bind.ContractBackend) (*EcdsaDkgValidator, error) {
+	contract, err := bindEcdsaDkgValidator(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &EcdsaDkgValidator{EcdsaDkgValidatorCaller: EcdsaDkgValidatorCaller{contract: contract}, EcdsaDkgValidatorTransactor: EcdsaDkgValidatorTransactor{contract: contract}, EcdsaDkgValidatorFilterer: EcdsaDkgValidatorFilterer{contract: contract}}, nil
+}
+
+// NewEcdsaDkgValidatorCaller creates a new read-only instance of EcdsaDkgValidator, bound to a specific deployed contract.
+func NewEcdsaDkgValidatorCaller(address common.Address, caller bind.ContractCaller) (*EcdsaDkgValidatorCaller, error) {
+	contract, err := bindEcdsaDkgValidator(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &EcdsaDkgValidatorCaller{contract: contract}, nil
+}
+
+// NewEcdsaDkgValidatorTransactor creates a new write-only instance of EcdsaDkgValidator, bound to a specific deployed contract.
+func NewEcdsaDkgValidatorTransactor(address common.Address, transactor bind.ContractTransactor) (*EcdsaDkgValidatorTransactor, error) {
+	contract, err := bindEcdsaDkgValidator(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &EcdsaDkgValidatorTransactor{contract: contract}, nil
+}
+
+// NewEcdsaDkgValidatorFilterer creates a new log filterer instance of EcdsaDkgValidator, bound to a specific deployed contract.
+func NewEcdsaDkgValidatorFilterer(address common.Address, filterer bind.ContractFilterer) (*EcdsaDkgValidatorFilterer, error) {
+	contract, err := bindEcdsaDkgValidator(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &EcdsaDkgValidatorFilterer{contract: contract}, nil
+}
+
+// bindEcdsaDkgValidator binds a generic wrapper to an already deployed contract.
+func bindEcdsaDkgValidator(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(EcdsaDkgValidatorABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_EcdsaDkgValidator *EcdsaDkgValidatorRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _EcdsaDkgValidator.Contract.EcdsaDkgValidatorCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_EcdsaDkgValidator *EcdsaDkgValidatorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _EcdsaDkgValidator.Contract.EcdsaDkgValidatorTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_EcdsaDkgValidator *EcdsaDkgValidatorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _EcdsaDkgValidator.Contract.EcdsaDkgValidatorTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _EcdsaDkgValidator.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_EcdsaDkgValidator *EcdsaDkgValidatorTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _EcdsaDkgValidator.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_EcdsaDkgValidator *EcdsaDkgValidatorTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _EcdsaDkgValidator.Contract.contract.Transact(opts, method, params...)
+}
+
+// ActiveThreshold is a free data retrieval call binding the contract method 0x281efe71.
+//
+// Solidity: function activeThreshold() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCaller) ActiveThreshold(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _EcdsaDkgValidator.contract.Call(opts, &out, "activeThreshold")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ActiveThreshold is a free data retrieval call binding the contract method 0x281efe71.
+//
+// Solidity: function activeThreshold() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorSession) ActiveThreshold() (*big.Int, error) {
+	return _EcdsaDkgValidator.Contract.ActiveThreshold(&_EcdsaDkgValidator.CallOpts)
+}
+
+// ActiveThreshold is a free data retrieval call binding the contract method 0x281efe71.
+//
+// Solidity: function activeThreshold() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCallerSession) ActiveThreshold() (*big.Int, error) {
+	return _EcdsaDkgValidator.Contract.ActiveThreshold(&_EcdsaDkgValidator.CallOpts)
+}
+
+// GroupSize is a free data retrieval call binding the contract method 0x63b635ea.
+//
+// Solidity: function groupSize() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCaller) GroupSize(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _EcdsaDkgValidator.contract.Call(opts, &out, "groupSize")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GroupSize is a free data retrieval call binding the contract method 0x63b635ea.
+//
+// Solidity: function groupSize() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorSession) GroupSize() (*big.Int, error) {
+	return _EcdsaDkgValidator.Contract.GroupSize(&_EcdsaDkgValidator.CallOpts)
+}
+
+// GroupSize is a free data retrieval call binding the contract method 0x63b635ea.
+//
+// Solidity: function groupSize() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCallerSession) GroupSize() (*big.Int, error) {
+	return _EcdsaDkgValidator.Contract.GroupSize(&_EcdsaDkgValidator.CallOpts)
+}
+
+// GroupThreshold is a free data retrieval call binding the contract method 0x6dcc64f8.
+//
+// Solidity: function groupThreshold() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCaller) GroupThreshold(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _EcdsaDkgValidator.contract.Call(opts, &out, "groupThreshold")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GroupThreshold is a free data retrieval call binding the contract method 0x6dcc64f8.
+//
+// Solidity: function groupThreshold() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorSession) GroupThreshold() (*big.Int, error) {
+	return _EcdsaDkgValidator.Contract.GroupThreshold(&_EcdsaDkgValidator.CallOpts)
+}
+
+// GroupThreshold is a free data retrieval call binding the contract method 0x6dcc64f8.
+//
+// Solidity: function groupThreshold() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCallerSession) GroupThreshold() (*big.Int, error) {
+	return _EcdsaDkgValidator.Contract.GroupThreshold(&_EcdsaDkgValidator.CallOpts)
+}
+
+// PublicKeyByteSize is a free data retrieval call binding the contract method 0x05f8ae15.
+//
+// Solidity: function publicKeyByteSize() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCaller) PublicKeyByteSize(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _EcdsaDkgValidator.contract.Call(opts, &out, "publicKeyByteSize")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// PublicKeyByteSize is a free data retrieval call binding the contract method 0x05f8ae15.
+//
+// Solidity: function publicKeyByteSize() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorSession) PublicKeyByteSize() (*big.Int, error) {
+	return _EcdsaDkgValidator.Contract.PublicKeyByteSize(&_EcdsaDkgValidator.CallOpts)
+}
+
+// PublicKeyByteSize is a free data retrieval call binding the contract method 0x05f8ae15.
+//
+// Solidity: function publicKeyByteSize() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCallerSession) PublicKeyByteSize() (*big.Int, error) {
+	return _EcdsaDkgValidator.Contract.PublicKeyByteSize(&_EcdsaDkgValidator.CallOpts)
+}
+
+// SignatureByteSize is a free data retrieval call binding the contract method 0x89ef44b0.
+//
+// Solidity: function signatureByteSize() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCaller) SignatureByteSize(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _EcdsaDkgValidator.contract.Call(opts, &out, "signatureByteSize")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// SignatureByteSize is a free data retrieval call binding the contract method 0x89ef44b0.
+//
+// Solidity: function signatureByteSize() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorSession) SignatureByteSize() (*big.Int, error) {
+	return _EcdsaDkgValidator.Contract.SignatureByteSize(&_EcdsaDkgValidator.CallOpts)
+}
+
+// SignatureByteSize is a free data retrieval call binding the contract method 0x89ef44b0.
+//
+// Solidity: function signatureByteSize() view returns(uint256)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCallerSession) SignatureByteSize() (*big.Int, error) {
+	return _EcdsaDkgValidator.Contract.SignatureByteSize(&_EcdsaDkgValidator.CallOpts)
+}
+
+// SortitionPool is a free data retrieval call binding the contract method 0xb54a2374.
+//
+// Solidity: function sortitionPool() view returns(address)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCaller) SortitionPool(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _EcdsaDkgValidator.contract.Call(opts, &out, "sortitionPool")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// SortitionPool is a free data retrieval call binding the contract method 0xb54a2374.
+//
+// Solidity: function sortitionPool() view returns(address)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorSession) SortitionPool() (common.Address, error) {
+	return _EcdsaDkgValidator.Contract.SortitionPool(&_EcdsaDkgValidator.CallOpts)
+}
+
+// SortitionPool is a free data retrieval call binding the contract method 0xb54a2374.
+//
+// Solidity: function sortitionPool() view returns(address)
+func (_EcdsaDkgValidator *EcdsaDkgValidatorCallerSession) SortitionPool() (common.Address, error) {
+	return _EcdsaDkgValidator.Contract.SortitionPool(&_EcdsaDkgValidator.CallOpts)
+}
@@ -0,0 +1,327 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated command and any manual changes will be lost.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	chainutil "github.com/keep-network/keep-common/pkg/chain/ethereum/ethutil"
+	"github.com/keep-network/keep-common/pkg/cmd"
+	"github.com/keep-network/keep-core/pkg/chain/ethereum/ecdsa/gen/contract"
+
+	"github.com/spf13/cobra"
+)
+
+var EcdsaDkgValidatorCommand *cobra.Command
+
+var ecdsaDkgValidatorDescription = `The ecdsa-dkg-validator command allows calling the EcdsaDkgValidator contract on an
+	Ethereum network. It has subcommands corresponding to each contract method,
+	which respectively each take parameters based on the contract method's
+	parameters.
+
+	Subcommands will submit a non-mutating call to the network and output the
+	result.
+
+	All subcommands can be called against a specific block by passing the
+	-b/--block flag.
+
+	Subcommands for mutating methods may be submitted as a mutating transaction
+	by passing the -s/--submit flag. In this mode, this command will terminate
+	successfully once the transaction has been submitted, but will not wait for
+	the transaction to be included in a block. They return the transaction hash.
+
+	Calls that require ether to be paid will get 0 ether by default, which can
+	be changed by passing the -v/--value flag.`
+
+func init() {
+	EcdsaDkgValidatorCommand := &cobra.Command{
+		Use:   "ecdsa-dkg-validator",
+		Short: `Provides access to the EcdsaDkgValidator contract.`,
+		Long:  ecdsaDkgValidatorDescription,
+	}
+
+	EcdsaDkgValidatorCommand.AddCommand(
+		edvActiveThresholdCommand(),
+		edvGroupSizeCommand(),
+		edvGroupThresholdCommand(),
+		edvPublicKeyByteSizeCommand(),
+		edvSignatureByteSizeCommand(),
+		edvSortitionPoolCommand(),
+	)
+
+	ModuleCommand.AddCommand(EcdsaDkgValidatorCommand)
+}
+
+/// ------------------- Const methods -------------------
+
+func edvActiveThresholdCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:                   "active-threshold",
+		Short:                 "Calls the view method activeThreshold on the EcdsaDkgValidator contract.",
+		Args:                  cmd.ArgCountChecker(0),
+		RunE:                  edvActiveThreshold,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+	}
+
+	cmd.InitConstFlags(c)
+
+	return c
+}
+
+func edvActiveThreshold(c *cobra.Command, args []string) error {
+	contract, err := initializeEcdsaDkgValidator(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := contract.ActiveThresholdAtBlock(
+		cmd.BlockFlagValue.Int,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	cmd.PrintOutput(result)
+
+	return nil
+}
+
+func edvGroupSizeCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:                   "group-size",
+		Short:                 "Calls the view method groupSize on the EcdsaDkgValidator contract.",
+		Args:                  cmd.ArgCountChecker(0),
+		RunE:                  edvGroupSize,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+	}
+
+	cmd.InitConstFlags(c)
+
+	return c
+}
+
+func edvGroupSize(c *cobra.Command, args []string) error {
+	contract, err := initializeEcdsaDkgValidator(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := contract.GroupSizeAtBlock(
+		cmd.BlockFlagValue.Int,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	cmd.PrintOutput(result)
+
+	return nil
+}
+
+func edvGroupThresholdCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:                   "group-threshold",
+		Short:                 "Calls the view method groupThreshold on the EcdsaDkgValidator contract.",
+		Args:                  cmd.ArgCountChecker(0),
+		RunE:                  edvGroupThreshold,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+	}
+
+	cmd.InitConstFlags(c)
+
+	return c
+}
+
+func edvGroupThreshold(c *cobra.Command, args []string) error {
+	contract, err := initializeEcdsaDkgValidator(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := contract.GroupThresholdAtBlock(
+		cmd.BlockFlagValue.Int,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	cmd.PrintOutput(result)
+
+	return nil
+}
+
+func edvPublicKeyByteSizeCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:                   "public-key-byte-size",
+		Short:                 "Calls the view method publicKeyByteSize on the EcdsaDkgValidator contract.",
+		Args:                  cmd.ArgCountChecker(0),
+		RunE:                  edvPublicKeyByteSize,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+	}
+
+	cmd.InitConstFlags(c)
+
+	return c
+}
+
+func edvPublicKeyByteSize(c *cobra.Command, args []string) error {
+	contract, err := initializeEcdsaDkgValidator(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := contract.PublicKeyByteSizeAtBlock(
+		cmd.BlockFlagValue.Int,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	cmd.PrintOutput(result)
+
+	return nil
+}
+
+func edvSignatureByteSizeCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:                   "signature-byte-size",
+		Short:                 "Calls the view method signatureByteSize on the EcdsaDkgValidator contract.",
+		Args:                  cmd.ArgCountChecker(0),
+		RunE:                  edvSignatureByteSize,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+	}
+
+	cmd.InitConstFlags(c)
+
+	return c
+}
+
+func edvSignatureByteSize(c *cobra.Command, args []string) error {
+	contract, err := initializeEcdsaDkgValidator(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := contract.SignatureByteSizeAtBlock(
+		cmd.BlockFlagValue.Int,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	cmd.PrintOutput(result)
+
+	return nil
+}
+
+func edvSortitionPoolCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:                   "sortition-pool",
+		Short:                 "Calls the view method sortitionPool on the EcdsaDkgValidator contract.",
+		Args:                  cmd.ArgCountChecker(0),
+		RunE:                  edvSortitionPool,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+	}
+
+	cmd.InitConstFlags(c)
+
+	return c
+}
+
+func edvSortitionPool(c *cobra.Command, args []string) error {
+	contract, err := initializeEcdsaDkgValidator(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := contract.SortitionPoolAtBlock(
+		cmd.BlockFlagValue.Int,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	cmd.PrintOutput(result)
+
+	return nil
+}
+
+/// ------------------- Non-const methods -------------------
+
+/// ------------------- Initialization -------------------
+
+func initializeEcdsaDkgValidator(c *cobra.Command) (*contract.EcdsaDkgValidator, error) {
+	cfg := *ModuleCommand.GetConfig()
+
+	client, err := ethclient.Dial(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to host chain node: [%v]", err)
+	}
+
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to resolve host chain id: [%v]",
+			err,
+		)
+	}
+
+	key, err := chainutil.DecryptKeyFile(
+		cfg.Account.KeyFile,
+		cfg.Account.KeyFilePassword,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to read KeyFile: %s: [%v]",
+			cfg.Account.KeyFile,
+			err,
+		)
+	}
+
+	miningWaiter := chainutil.NewMiningWaiter(client, cfg)
+
+	blockCounter, err := chainutil.NewBlockCounter(client)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to create block counter: [%v]",
+			err,
+		)
+	}
+
+	address, err := cfg.ContractAddress("EcdsaDkgValidator")
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get %s address: [%w]",
+			"EcdsaDkgValidator",
+			err,
+		)
+	}
+
+	return contract.NewEcdsaDkgValidator(
+		address,
+		chainID,
+		key,
+		client,
+		chainutil.NewNonceManager(client, key.Address),
+		miningWaiter,
+		blockCounter,
+		&sync.Mutex{},
+	)
+}
@@ -0,0 +1,326 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contract
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	hostchainabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-common/pkg/chain/ethereum"
+	chainutil "github.com/keep-network/keep-common/pkg/chain/ethereum/ethutil"
+	"github.com/keep-network/keep-core/pkg/chain/ethereum/ecdsa/gen/abi"
+)
+
+// Create a package-level logger for this contract. The logger exists at
+// package level so that the logger is registered at startup and can be
+// included or excluded from logging at startup by name.
+var edvLogger = log.Logger("keep-contract-EcdsaDkgValidator")
+
+type EcdsaDkgValidator struct {
+	contract          *abi.EcdsaDkgValidator
+	contractAddress   common.Address
+	contractABI       *hostchainabi.ABI
+	caller            bind.ContractCaller
+	transactor        bind.ContractTransactor
+	callerOptions     *bind.CallOpts
+	transactorOptions *bind.TransactOpts
+	errorResolver     *chainutil.ErrorResolver
+	nonceManager      *ethereum.NonceManager
+	miningWaiter      *chainutil.MiningWaiter
+	blockCounter      *ethereum.BlockCounter
+
+	transactionMutex *sync.Mutex
+}
+
+func NewEcdsaDkgValidator(
+	contractAddress common.Address,
+	chainId *big.Int,
+	accountKey *keystore.Key,
+	backend bind.ContractBackend,
+	nonceManager *ethereum.NonceManager,
+	miningWaiter *chainutil.MiningWaiter,
+	blockCounter *ethereum.BlockCounter,
+	transactionMutex *sync.Mutex,
+) (*EcdsaDkgValidator, error) {
+	callerOptions := &bind.CallOpts{
+		From: accountKey.Address,
+	}
+
+	transactorOptions, err := bind.NewKeyedTransactorWithChainID(
+		accountKey.PrivateKey,
+		chainId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate transactor: [%v]", err)
+	}
+
+	contract, err := abi.NewEcdsaDkgValidator(
+		contractAddress,
+		backend,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to instantiate contract at address: %s [%v]",
+			contractAddress.String(),
+			err,
+		)
+	}
+
+	contractABI, err := hostchainabi.JSON(strings.NewReader(abi.EcdsaDkgValidatorABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate ABI: [%v]", err)
+	}
+
+	return &EcdsaDkgValidator{
+		contract:          contract,
+		contractAddress:   contractAddress,
+		contractABI:       &contractABI,
+		caller:            backend,
+		transactor:        backend,
+		callerOptions:     callerOptions,
+		transactorOptions: transactorOptions,
+		errorResolver:     chainutil.NewErrorResolver(backend, &contractABI, &contractAddress),
+		nonceManager:      nonceManager,
+		miningWaiter:      miningWaiter,
+		blockCounter:      blockCounter,
+		transactionMutex:  transactionMutex,
+	}, nil
+}
+
+// ----- Non-const Methods ------
+
+// ----- Const Methods ------
+
+func (edv *EcdsaDkgValidator) ActiveThreshold() (*big.Int, error) {
+	result, err := edv.contract.ActiveThreshold(
+		edv.callerOptions,
+	)
+
+	if err != nil {
+		return result, edv.errorResolver.ResolveError(
+			err,
+			edv.callerOptions.From,
+			nil,
+			"activeThreshold",
+		)
+	}
+
+	return result, err
+}
+
+func (edv *EcdsaDkgValidator) ActiveThresholdAtBlock(
+	blockNumber *big.Int,
+) (*big.Int, error) {
+	var result *big.Int
+
+	err := chainutil.CallAtBlock(
+		edv.callerOptions.From,
+		blockNumber,
+		nil,
+		edv.contractABI,
+		edv.caller,
+		edv.errorResolver,
+		edv.contractAddress,
+		"activeThreshold",
+		&result,
+	)
+
+	return result, err
+}
+
+func (edv *EcdsaDkgValidator) GroupSize() (*big.Int, error) {
+	result, err := edv.contract.GroupSize(
+		edv.callerOptions,
+	)
+
+	if err != nil {
+		return result, edv.errorResolver.ResolveError(
+			err,
+			edv.callerOptions.From,
+			nil,
+			"groupSize",
+		)
+	}
+
+	return result, err
+}
+
+func (edv *EcdsaDkgValidator) GroupSizeAtBlock(
+	blockNumber *big.Int,
+) (*big.Int, error) {
+	var result *big.Int
+
+	err := chainutil.CallAtBlock(
+		edv.callerOptions.From,
+		blockNumber,
+		nil,
+		edv.contractABI,
+		edv.caller,
+		edv.errorResolver,
+		edv.contractAddress,
+		"groupSize",
+		&result,
+	)
+
+	return result, err
+}
+
+func (edv *EcdsaDkgValidator) GroupThreshold() (*big.Int, error) {
+	result, err := edv.contract.GroupThreshold(
+		edv.callerOptions,
+	)
+
+	if err != nil {
+		return result, edv.errorResolver.ResolveError(
+			err,
+			edv.callerOptions.From,
+			nil,
+			"groupThreshold",
+		)
+	}
+
+	return result, err
+}
+
+func (edv *EcdsaDkgValidator) GroupThresholdAtBlock(
+	blockNumber *big.Int,
+) (*big.Int, error) {
+	var result *big.Int
+
+	err := chainutil.CallAtBlock(
+		edv.callerOptions.From,
+		blockNumber,
+		nil,
+		edv.contractABI,
+		edv.caller,
+		edv.errorResolver,
+		edv.contractAddress,
+		"groupThreshold",
+		&result,
+	)
+
+	return result, err
+}
+
+func (edv *EcdsaDkgValidator) PublicKeyByteSize() (*big.Int, error) {
+	result, err := edv.contract.PublicKeyByteSize(
+		edv.callerOptions,
+	)
+
+	if err != nil {
+		return result, edv.errorResolver.ResolveError(
+			err,
+			edv.callerOptions.From,
+			nil,
+			"publicKeyByteSize",
+		)
+	}
+
+	return result, err
+}
+
+func (edv *EcdsaDkgValidator) PublicKeyByteSizeAtBlock(
+	blockNumber *big.Int,
+) (*big.Int, error) {
+	var result *big.Int
+
+	err := chainutil.CallAtBlock(
+		edv.callerOptions.From,
+		blockNumber,
+		nil,
+		edv.contractABI,
+		edv.caller,
+		edv.errorResolver,
+		edv.contractAddress,
+		"publicKeyByteSize",
+		&result,
+	)
+
+	return result, err
+}
+
+func (edv *EcdsaDkgValidator) SignatureByteSize() (*big.Int, error) {
+	result, err := edv.contract.SignatureByteSize(
+		edv.callerOptions,
+	)
+
+	if err != nil {
+		return result, edv.errorResolver.ResolveError(
+			err,
+			edv.callerOptions.From,
+			nil,
+			"signatureByteSize",
+		)
+	}
+
+	return result, err
+}
+
+func (edv *EcdsaDkgValidator) SignatureByteSizeAtBlock(
+	blockNumber *big.Int,
+) (*big.Int, error) {
+	var result *big.Int
+
+	err := chainutil.CallAtBlock(
+		edv.callerOptions.From,
+		blockNumber,
+		nil,
+		edv.contractABI,
+		edv.caller,
+		edv.errorResolver,
+		edv.contractAddress,
+		"signatureByteSize",
+		&result,
+	)
+
+	return result, err
+}
+
+func (edv *EcdsaDkgValidator) SortitionPool() (common.Address, error) {
+	result, err := edv.contract.SortitionPool(
+		edv.callerOptions,
+	)
+
+	if err != nil {
+		return result, edv.errorResolver.ResolveError(
+			err,
+			edv.callerOptions.From,
+			nil,
+			"sortitionPool",
+		)
+	}
+
+	return result, err
+}
+
+func (edv *EcdsaDkgValidator) SortitionPoolAtBlock(
+	blockNumber *big.Int,
+) (common.Address, error) {
+	var result common.Address
+
+	err := chainutil.CallAtBlock(
+		edv.callerOptions.From,
+		blockNumber,
+		nil,
+		edv.contractABI,
+		edv.caller,
+		edv.errorResolver,
+		edv.contractAddress,
+		"sortitionPool",
+		&result,
+	)
+
+	return result, err
+}
+
+// ------ Events -------
@@ -13,4 +13,11 @@ var (
 
 	// WalletRegistryAddress is a WalletRegistry contract's address read from the NPM package.
 	WalletRegistryAddress string = strings.TrimSpace(walletRegistryAddressFileContent)
+
+	//go:embed _address/EcdsaDkgValidator
+	ecdsaDkgValidatorAddressFileContent string
+
+	// EcdsaDkgValidatorAddress is an EcdsaDkgValidator contract's address read
+	// from the NPM package.
+	EcdsaDkgValidatorAddress string = strings.TrimSpace(ecdsaDkgValidatorAddressFileContent)
 )
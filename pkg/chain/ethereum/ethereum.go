@@ -123,17 +123,20 @@ func Connect(
 		nil
 }
 
-// ConnectBitcoinDifficulty creates Bitcoin difficulty chain handle.
-func ConnectBitcoinDifficulty(
+// ConnectMaintainer creates Bitcoin difficulty and TBTC Ethereum chain
+// handles used by maintainers. Both handles share the same base chain so
+// transactions submitted by different maintainers do not conflict.
+func ConnectMaintainer(
 	ctx context.Context,
 	config ethereum.Config,
 ) (
 	*BitcoinDifficultyChain,
+	*TbtcChain,
 	error,
 ) {
 	client, err := ethclient.Dial(config.URL)
 	if err != nil {
-		return nil, fmt.Errorf(
+		return nil, nil, fmt.Errorf(
 			"error Connecting to Ethereum Server: %s [%v]",
 			config.URL,
 			err,
@@ -142,7 +145,7 @@ func ConnectBitcoinDifficulty(
 
 	baseChain, err := newBaseChain(ctx, config, client)
 	if err != nil {
-		return nil, fmt.Errorf(
+		return nil, nil, fmt.Errorf(
 			"could not create base chain handle: [%v]",
 			err,
 		)
@@ -150,13 +153,21 @@ func ConnectBitcoinDifficulty(
 
 	bitcoinDifficultyChain, err := NewBitcoinDifficultyChain(config, baseChain)
 	if err != nil {
-		return nil, fmt.Errorf(
+		return nil, nil, fmt.Errorf(
 			"could not create Bitcoin difficulty chain handle: [%v]",
 			err,
 		)
 	}
 
-	return bitcoinDifficultyChain, nil
+	tbtcChain, err := newTbtcChain(config, baseChain)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"could not create TBTC chain handle: [%v]",
+			err,
+		)
+	}
+
+	return bitcoinDifficultyChain, tbtcChain, nil
 }
 
 func validateContractsAddresses(
@@ -1,6 +1,7 @@
 package ethereum
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
@@ -12,14 +13,17 @@ import (
 	"reflect"
 	"sort"
 
+	"github.com/btcsuite/btcd/wire"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/keep-network/keep-common/pkg/chain/ethereum"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/chain"
 	ecdsaabi "github.com/keep-network/keep-core/pkg/chain/ethereum/ecdsa/gen/abi"
 	ecdsacontract "github.com/keep-network/keep-core/pkg/chain/ethereum/ecdsa/gen/contract"
+	tbtcabi "github.com/keep-network/keep-core/pkg/chain/ethereum/tbtc/gen/abi"
 	tbtccontract "github.com/keep-network/keep-core/pkg/chain/ethereum/tbtc/gen/contract"
 	"github.com/keep-network/keep-core/pkg/internal/byteutils"
 	"github.com/keep-network/keep-core/pkg/operator"
@@ -33,8 +37,25 @@ import (
 const (
 	// TODO: The WalletRegistry address is taken from the Bridge contract.
 	//       Remove the possibility of passing it through the config.
-	WalletRegistryContractName = "WalletRegistry"
-	BridgeContractName         = "Bridge"
+	WalletRegistryContractName    = "WalletRegistry"
+	BridgeContractName            = "Bridge"
+	EcdsaDkgValidatorContractName = "EcdsaDkgValidator"
+)
+
+const (
+	// depositSweepProposalMaxSize determines the maximum number of deposits
+	// included in a single deposit sweep proposal.
+	depositSweepProposalMaxSize = 5
+	// depositSweepProposalLookBackBlocks determines the block range in which
+	// deposits for the deposit sweep proposal are searched. The value of
+	// `216000` blocks is roughly 30 days assuming 12 seconds per block.
+	depositSweepProposalLookBackBlocks = 216000
+	// dkgResultPublicKeySize is the size of the group public key in the
+	// DKG result expected by the on-chain contract.
+	dkgResultPublicKeySize = 64
+	// dkgResultSignatureSize is the size of a single member signature in the
+	// DKG result expected by the on-chain contract.
+	dkgResultSignatureSize = 65
 )
 
 // TbtcChain represents a TBTC-specific chain handle.
@@ -44,6 +65,7 @@ type TbtcChain struct {
 	bridge         *tbtccontract.Bridge
 	walletRegistry *ecdsacontract.WalletRegistry
 	sortitionPool  *ecdsacontract.EcdsaSortitionPool
+	dkgValidator   *ecdsacontract.EcdsaDkgValidator
 }
 
 // NewTbtcChain construct a new instance of the TBTC-specific Ethereum
@@ -133,11 +155,45 @@ func newTbtcChain(
 		)
 	}
 
+	// TODO: The EcdsaDkgValidator address cannot be read from the
+	//       WalletRegistry contract so it has to be passed through the config.
+	//       The address is mandatory as the client must use the group
+	//       parameters enforced by the contract.
+	dkgValidatorAddress, err := config.ContractAddress(
+		EcdsaDkgValidatorContractName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to resolve %s contract address: [%v]",
+			EcdsaDkgValidatorContractName,
+			err,
+		)
+	}
+
+	dkgValidator, err :=
+		ecdsacontract.NewEcdsaDkgValidator(
+			dkgValidatorAddress,
+			baseChain.chainID,
+			baseChain.key,
+			baseChain.client,
+			baseChain.nonceManager,
+			baseChain.miningWaiter,
+			baseChain.blockCounter,
+			baseChain.transactionMutex,
+		)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to attach to EcdsaDkgValidator contract: [%v]",
+			err,
+		)
+	}
+
 	return &TbtcChain{
 		baseChain:      baseChain,
 		bridge:         bridge,
 		walletRegistry: walletRegistry,
 		sortitionPool:  sortitionPool,
+		dkgValidator:   dkgValidator,
 	}, nil
 }
 
@@ -658,7 +714,7 @@ func convertSignaturesToChainFormat(
 		return membersIndexes[i] < membersIndexes[j]
 	})
 
-	signatureSize := 65
+	signatureSize := dkgResultSignatureSize
 
 	var signaturesSlice []byte
 
@@ -683,8 +739,10 @@ func convertSignaturesToChainFormat(
 // convertPubKeyToChainFormat takes X and Y coordinates of a signer's public key
 // and concatenates it to a 64-byte long array. If any of coordinates is shorter
 // than 32-byte it is preceded with zeros.
-func convertPubKeyToChainFormat(publicKey *ecdsa.PublicKey) ([64]byte, error) {
-	var serialized [64]byte
+func convertPubKeyToChainFormat(
+	publicKey *ecdsa.PublicKey,
+) ([dkgResultPublicKeySize]byte, error) {
+	var serialized [dkgResultPublicKeySize]byte
 
 	x, err := byteutils.LeftPadTo32Bytes(publicKey.X.Bytes())
 	if err != nil {
@@ -894,6 +952,76 @@ func (tc *TbtcChain) DKGParameters() (*tbtc.DKGParameters, error) {
 	}, nil
 }
 
+func (tc *TbtcChain) OnDKGParametersUpdated(
+	handler func(event *tbtc.DKGParametersUpdatedEvent),
+) subscription.EventSubscription {
+	onEvent := func(
+		seedTimeout *big.Int,
+		resultChallengePeriodLength *big.Int,
+		resultChallengeExtraGas *big.Int,
+		resultSubmissionTimeout *big.Int,
+		resultSubmitterPrecedencePeriodLength *big.Int,
+		blockNumber uint64,
+	) {
+		handler(&tbtc.DKGParametersUpdatedEvent{
+			BlockNumber: blockNumber,
+		})
+	}
+
+	return tc.walletRegistry.DkgParametersUpdatedEvent(nil).OnEvent(onEvent)
+}
+
+// GroupParameters gets the group parameters enforced by the EcdsaDkgValidator
+// contract used by the WalletRegistry. The function returns an error if the
+// shape of the DKG result expected by the contract differs from the shape
+// of the result assembled by this client.
+func (tc *TbtcChain) GroupParameters() (*tbtc.GroupParameters, error) {
+	publicKeyByteSize, err := tc.dkgValidator.PublicKeyByteSize()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get public key byte size: [%v]", err)
+	}
+	if publicKeyByteSize.Cmp(big.NewInt(dkgResultPublicKeySize)) != 0 {
+		return nil, fmt.Errorf(
+			"unsupported DKG result public key byte size [%v]; expected [%v]",
+			publicKeyByteSize,
+			dkgResultPublicKeySize,
+		)
+	}
+
+	signatureByteSize, err := tc.dkgValidator.SignatureByteSize()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get signature byte size: [%v]", err)
+	}
+	if signatureByteSize.Cmp(big.NewInt(dkgResultSignatureSize)) != 0 {
+		return nil, fmt.Errorf(
+			"unsupported DKG result signature byte size [%v]; expected [%v]",
+			signatureByteSize,
+			dkgResultSignatureSize,
+		)
+	}
+
+	groupSize, err := tc.dkgValidator.GroupSize()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get group size: [%v]", err)
+	}
+
+	activeThreshold, err := tc.dkgValidator.ActiveThreshold()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get active threshold: [%v]", err)
+	}
+
+	groupThreshold, err := tc.dkgValidator.GroupThreshold()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get group threshold: [%v]", err)
+	}
+
+	return &tbtc.GroupParameters{
+		GroupSize:       int(groupSize.Uint64()),
+		GroupQuorum:     int(activeThreshold.Uint64()),
+		HonestThreshold: int(groupThreshold.Uint64()),
+	}, nil
+}
+
 // OnHeartbeatRequested runs a heartbeat loop that produces a heartbeat
 // request every ~8 hours. A single heartbeat request consists of 5 messages
 // that must be signed sequentially.
@@ -996,3 +1124,935 @@ func (tc *TbtcChain) activeWalletPublicKey() ([]byte, bool, error) {
 
 	return publicKeyBytes, true, nil
 }
+
+func (tc *TbtcChain) OnRedemptionRequested(
+	handler func(event *tbtc.RedemptionRequestedEvent),
+) subscription.EventSubscription {
+	onEvent := func(
+		walletPublicKeyHash [20]byte,
+		redeemerOutputScript []byte,
+		redeemer common.Address,
+		requestedAmount uint64,
+		treasuryFee uint64,
+		txMaxFee uint64,
+		blockNumber uint64,
+	) {
+		script, err := parseLengthPrefixedScript(redeemerOutputScript)
+		if err != nil {
+			logger.Errorf(
+				"cannot parse redeemer output script of redemption "+
+					"request for wallet [0x%x] at block [%v]: [%v]",
+				walletPublicKeyHash,
+				blockNumber,
+				err,
+			)
+			return
+		}
+
+		handler(&tbtc.RedemptionRequestedEvent{
+			WalletPublicKeyHash:  walletPublicKeyHash,
+			RedeemerOutputScript: script,
+			Redeemer:             chain.Address(redeemer.Hex()),
+			RequestedAmount:      requestedAmount,
+			TreasuryFee:          treasuryFee,
+			TxMaxFee:             txMaxFee,
+			BlockNumber:          blockNumber,
+		})
+	}
+
+	return tc.bridge.
+		RedemptionRequestedEvent(nil, nil, nil).
+		OnEvent(onEvent)
+}
+
+func (tc *TbtcChain) PastRedemptionRequestedEvents(
+	filter *tbtc.RedemptionRequestedEventFilter,
+) ([]*tbtc.RedemptionRequestedEvent, error) {
+	var startBlock uint64
+	var endBlock *uint64
+	var walletPublicKeyHash [][20]byte
+	var redeemer []common.Address
+
+	if filter != nil {
+		startBlock = filter.StartBlock
+		endBlock = filter.EndBlock
+		walletPublicKeyHash = filter.WalletPublicKeyHash
+
+		for _, r := range filter.Redeemer {
+			redeemer = append(redeemer, common.HexToAddress(r.String()))
+		}
+	}
+
+	events, err := tc.bridge.PastRedemptionRequestedEvents(
+		startBlock,
+		endBlock,
+		walletPublicKeyHash,
+		redeemer,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	convertedEvents := make([]*tbtc.RedemptionRequestedEvent, 0)
+	for _, event := range events {
+		script, err := parseLengthPrefixedScript(event.RedeemerOutputScript)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot parse redeemer output script: [%v]",
+				err,
+			)
+		}
+
+		convertedEvents = append(
+			convertedEvents,
+			&tbtc.RedemptionRequestedEvent{
+				WalletPublicKeyHash:  event.WalletPubKeyHash,
+				RedeemerOutputScript: script,
+				Redeemer:             chain.Address(event.Redeemer.Hex()),
+				RequestedAmount:      event.RequestedAmount,
+				TreasuryFee:          event.TreasuryFee,
+				TxMaxFee:             event.TxMaxFee,
+				BlockNumber:          event.Raw.BlockNumber,
+			},
+		)
+	}
+
+	sort.SliceStable(convertedEvents, func(i, j int) bool {
+		return convertedEvents[i].BlockNumber < convertedEvents[j].BlockNumber
+	})
+
+	return convertedEvents, nil
+}
+
+func (tc *TbtcChain) GetPendingRedemptionRequest(
+	walletPublicKeyHash [20]byte,
+	redeemerOutputScript bitcoin.Script,
+	blockNumber uint64,
+) (*tbtc.RedemptionRequest, bool, error) {
+	redemptionKey, err := buildRedemptionKey(
+		walletPublicKeyHash,
+		redeemerOutputScript,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot build redemption key: [%v]", err)
+	}
+
+	redemptionRequest, err := tc.bridge.PendingRedemptionsAtBlock(
+		redemptionKey,
+		new(big.Int).SetUint64(blockNumber),
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf(
+			"cannot get pending redemption request: [%v]",
+			err,
+		)
+	}
+
+	// Redemption request not found.
+	if redemptionRequest.RequestedAt == 0 {
+		return nil, false, nil
+	}
+
+	return &tbtc.RedemptionRequest{
+		Redeemer:             chain.Address(redemptionRequest.Redeemer.Hex()),
+		RedeemerOutputScript: redeemerOutputScript,
+		RequestedAmount:      redemptionRequest.RequestedAmount,
+		TreasuryFee:          redemptionRequest.TreasuryFee,
+		TxMaxFee:             redemptionRequest.TxMaxFee,
+		RequestedAt:          redemptionRequest.RequestedAt,
+	}, true, nil
+}
+
+func (tc *TbtcChain) PastDepositsSweptEvents(
+	filter *tbtc.WalletTransactionEventFilter,
+) ([]*tbtc.DepositsSweptEvent, error) {
+	var startBlock uint64
+	var endBlock *uint64
+
+	if filter != nil {
+		startBlock = filter.StartBlock
+		endBlock = filter.EndBlock
+	}
+
+	events, err := tc.bridge.PastDepositsSweptEvents(startBlock, endBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	convertedEvents := make([]*tbtc.DepositsSweptEvent, 0)
+	for _, event := range events {
+		// The wallet public key hash is not an indexed field of the
+		// DepositsSwept event so, it must be filtered manually.
+		if filter != nil &&
+			!containsWalletPublicKeyHash(
+				filter.WalletPublicKeyHash,
+				event.WalletPubKeyHash,
+			) {
+			continue
+		}
+
+		convertedEvents = append(convertedEvents, &tbtc.DepositsSweptEvent{
+			WalletPublicKeyHash: event.WalletPubKeyHash,
+			SweepTxHash:         event.SweepTxHash,
+			BlockNumber:         event.Raw.BlockNumber,
+		})
+	}
+
+	sort.SliceStable(convertedEvents, func(i, j int) bool {
+		return convertedEvents[i].BlockNumber < convertedEvents[j].BlockNumber
+	})
+
+	return convertedEvents, nil
+}
+
+func (tc *TbtcChain) PastRedemptionsCompletedEvents(
+	filter *tbtc.WalletTransactionEventFilter,
+) ([]*tbtc.RedemptionsCompletedEvent, error) {
+	var startBlock uint64
+	var endBlock *uint64
+	var walletPublicKeyHash [][20]byte
+
+	if filter != nil {
+		startBlock = filter.StartBlock
+		endBlock = filter.EndBlock
+		walletPublicKeyHash = filter.WalletPublicKeyHash
+	}
+
+	events, err := tc.bridge.PastRedemptionsCompletedEvents(
+		startBlock,
+		endBlock,
+		walletPublicKeyHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	convertedEvents := make([]*tbtc.RedemptionsCompletedEvent, len(events))
+	for i, event := range events {
+		convertedEvents[i] = &tbtc.RedemptionsCompletedEvent{
+			WalletPublicKeyHash: event.WalletPubKeyHash,
+			RedemptionTxHash:    event.RedemptionTxHash,
+			BlockNumber:         event.Raw.BlockNumber,
+		}
+	}
+
+	sort.SliceStable(convertedEvents, func(i, j int) bool {
+		return convertedEvents[i].BlockNumber < convertedEvents[j].BlockNumber
+	})
+
+	return convertedEvents, nil
+}
+
+func (tc *TbtcChain) PastMovedFundsSweptEvents(
+	filter *tbtc.WalletTransactionEventFilter,
+) ([]*tbtc.MovedFundsSweptEvent, error) {
+	var startBlock uint64
+	var endBlock *uint64
+	var walletPublicKeyHash [][20]byte
+
+	if filter != nil {
+		startBlock = filter.StartBlock
+		endBlock = filter.EndBlock
+		walletPublicKeyHash = filter.WalletPublicKeyHash
+	}
+
+	events, err := tc.bridge.PastMovedFundsSweptEvents(
+		startBlock,
+		endBlock,
+		walletPublicKeyHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	convertedEvents := make([]*tbtc.MovedFundsSweptEvent, len(events))
+	for i, event := range events {
+		convertedEvents[i] = &tbtc.MovedFundsSweptEvent{
+			WalletPublicKeyHash: event.WalletPubKeyHash,
+			SweepTxHash:         event.SweepTxHash,
+			BlockNumber:         event.Raw.BlockNumber,
+		}
+	}
+
+	sort.SliceStable(convertedEvents, func(i, j int) bool {
+		return convertedEvents[i].BlockNumber < convertedEvents[j].BlockNumber
+	})
+
+	return convertedEvents, nil
+}
+
+func (tc *TbtcChain) GetWallet(
+	walletPublicKeyHash [20]byte,
+) (*tbtc.WalletChainData, error) {
+	wallet, err := tc.bridge.Wallets(walletPublicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get wallet data from Bridge: [%v]",
+			err,
+		)
+	}
+
+	// Wallet not found.
+	if wallet.EcdsaWalletID == [32]byte{} {
+		return nil, fmt.Errorf(
+			"no wallet for public key hash [0x%x]",
+			walletPublicKeyHash,
+		)
+	}
+
+	return &tbtc.WalletChainData{
+		EcdsaWalletID:                          wallet.EcdsaWalletID,
+		MainUtxoHash:                           wallet.MainUtxoHash,
+		PendingRedemptionsValue:                wallet.PendingRedemptionsValue,
+		CreatedAt:                              wallet.CreatedAt,
+		MovingFundsRequestedAt:                 wallet.MovingFundsRequestedAt,
+		ClosingStartedAt:                       wallet.ClosingStartedAt,
+		State:                                  tbtc.WalletState(wallet.State),
+		MovingFundsTargetWalletsCommitmentHash: wallet.MovingFundsTargetWalletsCommitmentHash,
+	}, nil
+}
+
+func (tc *TbtcChain) ComputeMainUtxoHash(
+	mainUtxo *bitcoin.UnspentTransactionOutput,
+) [32]byte {
+	return computeMainUtxoHash(mainUtxo)
+}
+
+func (tc *TbtcChain) ComputeMovingFundsTargetWalletsCommitmentHash(
+	targetWallets [][20]byte,
+) [32]byte {
+	return computeMovingFundsTargetWalletsCommitmentHash(targetWallets)
+}
+
+// OnDepositSweepProposalSubmitted runs a deposit sweep proposal loop that
+// produces proposals every ~4 hours. Each proposal contains revealed deposits
+// of one wallet that were not swept yet. All Bridge state used to build the
+// proposals is read at the proposal block so all clients produce the same
+// proposals. This is a temporary replacement of the on-chain deposit sweep
+// proposal mechanism that is not available yet.
+func (tc *TbtcChain) OnDepositSweepProposalSubmitted(
+	handler func(event *tbtc.DepositSweepProposalSubmittedEvent),
+) subscription.EventSubscription {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	blocksChan := tc.blockCounter.WatchBlocks(ctx)
+
+	go func() {
+		for {
+			select {
+			case block := <-blocksChan:
+				// Generate proposals every 1200 block, i.e. ~4 hours.
+				if block%1200 == 0 {
+					proposals, err := tc.depositSweepProposals(block)
+					if err != nil {
+						logger.Errorf(
+							"cannot build deposit sweep proposals: [%v]",
+							err,
+						)
+						continue
+					}
+
+					if len(proposals) == 0 {
+						logger.Infof("there are no deposits to sweep at the moment")
+						continue
+					}
+
+					for _, proposal := range proposals {
+						go handler(&tbtc.DepositSweepProposalSubmittedEvent{
+							Proposal:    proposal,
+							BlockNumber: block,
+						})
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return subscription.NewEventSubscription(func() {
+		cancelCtx()
+	})
+}
+
+// depositSweepProposals builds deposit sweep proposals using deposits
+// revealed up to the given block. There is one proposal for each wallet
+// having deposits that were not swept yet, as long as the wallet can still
+// sweep them, i.e. it is Live or MovingFunds. Each proposal contains up to
+// depositSweepProposalMaxSize oldest deposits of the given wallet. The state
+// of wallets and deposits is read at the given block so the result does not
+// depend on the moment this function is called.
+func (tc *TbtcChain) depositSweepProposals(
+	block uint64,
+) ([]*tbtc.DepositSweepProposal, error) {
+	blockNumber := new(big.Int).SetUint64(block)
+
+	startBlock := uint64(0)
+	if block > depositSweepProposalLookBackBlocks {
+		startBlock = block - depositSweepProposalLookBackBlocks
+	}
+
+	events, err := tc.PastDepositRevealedEvents(&tbtc.DepositRevealedEventFilter{
+		StartBlock: startBlock,
+		EndBlock:   &block,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get past deposit revealed events: [%v]",
+			err,
+		)
+	}
+
+	depositParameters, err := tc.bridge.DepositParametersAtBlock(blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get deposit parameters: [%v]",
+			err,
+		)
+	}
+
+	proposals := make([]*tbtc.DepositSweepProposal, 0)
+	proposalsByWallet := make(map[[20]byte]*tbtc.DepositSweepProposal)
+	// Wallets that cannot sweep deposits are remembered to not fetch
+	// their state for each of their deposits.
+	skippedWallets := make(map[[20]byte]bool)
+
+	for _, event := range events {
+		walletPublicKeyHash := event.WalletPublicKeyHash
+
+		if skippedWallets[walletPublicKeyHash] {
+			continue
+		}
+
+		proposal, ok := proposalsByWallet[walletPublicKeyHash]
+		if !ok {
+			wallet, err := tc.bridge.WalletsAtBlock(
+				walletPublicKeyHash,
+				blockNumber,
+			)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot get wallet [0x%x]: [%v]",
+					walletPublicKeyHash,
+					err,
+				)
+			}
+
+			// The Bridge accepts deposit sweeps only from Live and
+			// MovingFunds wallets.
+			state := tbtc.WalletState(wallet.State)
+			if state != tbtc.StateLive && state != tbtc.StateMovingFunds {
+				skippedWallets[walletPublicKeyHash] = true
+				continue
+			}
+
+			proposal = &tbtc.DepositSweepProposal{
+				WalletPublicKeyHash: walletPublicKeyHash,
+			}
+			proposalsByWallet[walletPublicKeyHash] = proposal
+			proposals = append(proposals, proposal)
+		}
+
+		if len(proposal.DepositsKeys) == depositSweepProposalMaxSize {
+			continue
+		}
+
+		depositRequest, err := tc.bridge.DepositsAtBlock(
+			buildDepositKey(event.FundingTxHash, event.FundingOutputIndex),
+			blockNumber,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get deposit request: [%v]",
+				err,
+			)
+		}
+
+		if depositRequest.RevealedAt == 0 || depositRequest.SweptAt != 0 {
+			continue
+		}
+
+		proposal.DepositsKeys = append(
+			proposal.DepositsKeys,
+			struct {
+				FundingTxHash      bitcoin.Hash
+				FundingOutputIndex uint32
+			}{
+				FundingTxHash:      event.FundingTxHash,
+				FundingOutputIndex: event.FundingOutputIndex,
+			},
+		)
+	}
+
+	result := make([]*tbtc.DepositSweepProposal, 0, len(proposals))
+	for _, proposal := range proposals {
+		if len(proposal.DepositsKeys) == 0 {
+			continue
+		}
+
+		// The Bridge accepts a fee that does not exceed the maximum fee
+		// per deposit multiplied by the number of deposits.
+		proposal.SweepTxFee = depositParameters.DepositTxMaxFee *
+			uint64(len(proposal.DepositsKeys))
+
+		result = append(result, proposal)
+	}
+
+	return result, nil
+}
+
+func (tc *TbtcChain) PastDepositRevealedEvents(
+	filter *tbtc.DepositRevealedEventFilter,
+) ([]*tbtc.DepositRevealedEvent, error) {
+	var startBlock uint64
+	var endBlock *uint64
+	var depositor []common.Address
+	var walletPublicKeyHash [][20]byte
+
+	if filter != nil {
+		startBlock = filter.StartBlock
+		endBlock = filter.EndBlock
+		walletPublicKeyHash = filter.WalletPublicKeyHash
+
+		for _, d := range filter.Depositor {
+			depositor = append(depositor, common.HexToAddress(d.String()))
+		}
+	}
+
+	events, err := tc.bridge.PastDepositRevealedEvents(
+		startBlock,
+		endBlock,
+		depositor,
+		walletPublicKeyHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	convertedEvents := make([]*tbtc.DepositRevealedEvent, len(events))
+	for i, event := range events {
+		var vault *chain.Address
+		if event.Vault != (common.Address{}) {
+			v := chain.Address(event.Vault.Hex())
+			vault = &v
+		}
+
+		convertedEvents[i] = &tbtc.DepositRevealedEvent{
+			FundingTxHash:       event.FundingTxHash,
+			FundingOutputIndex:  event.FundingOutputIndex,
+			Depositor:           chain.Address(event.Depositor.Hex()),
+			Amount:              event.Amount,
+			BlindingFactor:      event.BlindingFactor,
+			WalletPublicKeyHash: event.WalletPubKeyHash,
+			RefundPublicKeyHash: event.RefundPubKeyHash,
+			RefundLocktime:      event.RefundLocktime,
+			Vault:               vault,
+			BlockNumber:         event.Raw.BlockNumber,
+		}
+	}
+
+	sort.SliceStable(convertedEvents, func(i, j int) bool {
+		return convertedEvents[i].BlockNumber < convertedEvents[j].BlockNumber
+	})
+
+	return convertedEvents, nil
+}
+
+func (tc *TbtcChain) GetDepositRequest(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+) (*tbtc.DepositChainRequest, bool, error) {
+	depositKey := buildDepositKey(fundingTxHash, fundingOutputIndex)
+
+	depositRequest, err := tc.bridge.Deposits(depositKey)
+	if err != nil {
+		return nil, false, fmt.Errorf(
+			"cannot get deposit request: [%v]",
+			err,
+		)
+	}
+
+	// Deposit request not found.
+	if depositRequest.RevealedAt == 0 {
+		return nil, false, nil
+	}
+
+	var vault *chain.Address
+	if depositRequest.Vault != (common.Address{}) {
+		v := chain.Address(depositRequest.Vault.Hex())
+		vault = &v
+	}
+
+	return &tbtc.DepositChainRequest{
+		Depositor:   chain.Address(depositRequest.Depositor.Hex()),
+		Amount:      depositRequest.Amount,
+		RevealedAt:  depositRequest.RevealedAt,
+		Vault:       vault,
+		TreasuryFee: depositRequest.TreasuryFee,
+		SweptAt:     depositRequest.SweptAt,
+	}, true, nil
+}
+
+func (tc *TbtcChain) OnMovingFundsCommitmentSubmitted(
+	handler func(event *tbtc.MovingFundsCommitmentSubmittedEvent),
+) subscription.EventSubscription {
+	onEvent := func(
+		walletPublicKeyHash [20]byte,
+		targetWallets [][20]byte,
+		submitter common.Address,
+		blockNumber uint64,
+	) {
+		handler(&tbtc.MovingFundsCommitmentSubmittedEvent{
+			WalletPublicKeyHash: walletPublicKeyHash,
+			TargetWallets:       targetWallets,
+			Submitter:           chain.Address(submitter.Hex()),
+			BlockNumber:         blockNumber,
+		})
+	}
+
+	return tc.bridge.
+		MovingFundsCommitmentSubmittedEvent(nil, nil).
+		OnEvent(onEvent)
+}
+
+func (tc *TbtcChain) PastMovingFundsCommitmentSubmittedEvents(
+	filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+) ([]*tbtc.MovingFundsCommitmentSubmittedEvent, error) {
+	var startBlock uint64
+	var endBlock *uint64
+	var walletPublicKeyHash [][20]byte
+
+	if filter != nil {
+		startBlock = filter.StartBlock
+		endBlock = filter.EndBlock
+		walletPublicKeyHash = filter.WalletPublicKeyHash
+	}
+
+	events, err := tc.bridge.PastMovingFundsCommitmentSubmittedEvents(
+		startBlock,
+		endBlock,
+		walletPublicKeyHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	convertedEvents := make([]*tbtc.MovingFundsCommitmentSubmittedEvent, 0)
+	for _, event := range events {
+		convertedEvents = append(
+			convertedEvents,
+			&tbtc.MovingFundsCommitmentSubmittedEvent{
+				WalletPublicKeyHash: event.WalletPubKeyHash,
+				TargetWallets:       event.TargetWallets,
+				Submitter:           chain.Address(event.Submitter.Hex()),
+				BlockNumber:         event.Raw.BlockNumber,
+			},
+		)
+	}
+
+	sort.SliceStable(convertedEvents, func(i, j int) bool {
+		return convertedEvents[i].BlockNumber < convertedEvents[j].BlockNumber
+	})
+
+	return convertedEvents, nil
+}
+
+func (tc *TbtcChain) OnWalletClosed(
+	handler func(event *tbtc.WalletClosedEvent),
+) subscription.EventSubscription {
+	onEvent := func(
+		ecdsaWalletID [32]byte,
+		walletPublicKeyHash [20]byte,
+		blockNumber uint64,
+	) {
+		handler(&tbtc.WalletClosedEvent{
+			EcdsaWalletID:       ecdsaWalletID,
+			WalletPublicKeyHash: walletPublicKeyHash,
+			BlockNumber:         blockNumber,
+		})
+	}
+
+	return tc.bridge.
+		WalletClosedEvent(nil, nil, nil).
+		OnEvent(onEvent)
+}
+
+func (tc *TbtcChain) OnWalletTerminated(
+	handler func(event *tbtc.WalletTerminatedEvent),
+) subscription.EventSubscription {
+	onEvent := func(
+		ecdsaWalletID [32]byte,
+		walletPublicKeyHash [20]byte,
+		blockNumber uint64,
+	) {
+		handler(&tbtc.WalletTerminatedEvent{
+			EcdsaWalletID:       ecdsaWalletID,
+			WalletPublicKeyHash: walletPublicKeyHash,
+			BlockNumber:         blockNumber,
+		})
+	}
+
+	return tc.bridge.
+		WalletTerminatedEvent(nil, nil, nil).
+		OnEvent(onEvent)
+}
+
+func (tc *TbtcChain) GetMovingFundsParameters() (
+	*tbtc.MovingFundsParameters,
+	error,
+) {
+	parameters, err := tc.bridge.MovingFundsParameters()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get moving funds parameters: [%v]",
+			err,
+		)
+	}
+
+	return &tbtc.MovingFundsParameters{
+		TxMaxTotalFee: parameters.MovingFundsTxMaxTotalFee,
+		DustThreshold: parameters.MovingFundsDustThreshold,
+	}, nil
+}
+
+func (tc *TbtcChain) GetDepositParameters() (
+	*tbtc.DepositParameters,
+	error,
+) {
+	parameters, err := tc.bridge.DepositParameters()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get deposit parameters: [%v]",
+			err,
+		)
+	}
+
+	return &tbtc.DepositParameters{
+		TxMaxFee: parameters.DepositTxMaxFee,
+	}, nil
+}
+
+func (tc *TbtcChain) GetRedemptionParameters() (
+	*tbtc.RedemptionParameters,
+	error,
+) {
+	parameters, err := tc.bridge.RedemptionParameters()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get redemption parameters: [%v]",
+			err,
+		)
+	}
+
+	return &tbtc.RedemptionParameters{
+		TxMaxTotalFee: parameters.RedemptionTxMaxTotalFee,
+	}, nil
+}
+
+func (tc *TbtcChain) TxProofDifficultyFactor() (*big.Int, error) {
+	return tc.bridge.TxProofDifficultyFactor()
+}
+
+func (tc *TbtcChain) SubmitDepositSweepProof(
+	transaction *bitcoin.Transaction,
+	proof *bitcoin.SpvProof,
+	mainUtxo *bitcoin.UnspentTransactionOutput,
+	vault *chain.Address,
+) error {
+	vaultAddress := common.Address{}
+	if vault != nil {
+		vaultAddress = common.HexToAddress(vault.String())
+	}
+
+	_, err := tc.bridge.SubmitDepositSweepProof(
+		convertTransactionToAbiType(transaction),
+		convertSpvProofToAbiType(proof),
+		convertMainUtxoToAbiType(mainUtxo),
+		vaultAddress,
+	)
+
+	return err
+}
+
+func (tc *TbtcChain) SubmitRedemptionProof(
+	transaction *bitcoin.Transaction,
+	proof *bitcoin.SpvProof,
+	mainUtxo *bitcoin.UnspentTransactionOutput,
+	walletPublicKeyHash [20]byte,
+) error {
+	_, err := tc.bridge.SubmitRedemptionProof(
+		convertTransactionToAbiType(transaction),
+		convertSpvProofToAbiType(proof),
+		convertMainUtxoToAbiType(mainUtxo),
+		walletPublicKeyHash,
+	)
+
+	return err
+}
+
+func (tc *TbtcChain) SubmitMovingFundsProof(
+	transaction *bitcoin.Transaction,
+	proof *bitcoin.SpvProof,
+	mainUtxo *bitcoin.UnspentTransactionOutput,
+	walletPublicKeyHash [20]byte,
+) error {
+	_, err := tc.bridge.SubmitMovingFundsProof(
+		convertTransactionToAbiType(transaction),
+		convertSpvProofToAbiType(proof),
+		convertMainUtxoToAbiType(mainUtxo),
+		walletPublicKeyHash,
+	)
+
+	return err
+}
+
+// convertTransactionToAbiType converts the given Bitcoin transaction to
+// the format expected by the Bridge contract.
+func convertTransactionToAbiType(
+	transaction *bitcoin.Transaction,
+) tbtcabi.BitcoinTxInfo {
+	return tbtcabi.BitcoinTxInfo{
+		Version:      transaction.SerializeVersion(),
+		InputVector:  transaction.SerializeInputs(),
+		OutputVector: transaction.SerializeOutputs(),
+		Locktime:     transaction.SerializeLocktime(),
+	}
+}
+
+// convertSpvProofToAbiType converts the given SPV proof to the format
+// expected by the Bridge contract.
+func convertSpvProofToAbiType(proof *bitcoin.SpvProof) tbtcabi.BitcoinTxProof {
+	return tbtcabi.BitcoinTxProof{
+		MerkleProof:    proof.MerkleProof,
+		TxIndexInBlock: new(big.Int).SetUint64(uint64(proof.TxIndexInBlock)),
+		BitcoinHeaders: proof.BitcoinHeaders,
+	}
+}
+
+// convertMainUtxoToAbiType converts the given main UTXO to the format
+// expected by the Bridge contract. A nil main UTXO is converted to an empty
+// one as expected by the Bridge for wallets without a main UTXO.
+func convertMainUtxoToAbiType(
+	mainUtxo *bitcoin.UnspentTransactionOutput,
+) tbtcabi.BitcoinTxUTXO {
+	if mainUtxo == nil {
+		return tbtcabi.BitcoinTxUTXO{}
+	}
+
+	return tbtcabi.BitcoinTxUTXO{
+		TxHash:        mainUtxo.Outpoint.TransactionHash,
+		TxOutputIndex: mainUtxo.Outpoint.OutputIndex,
+		TxOutputValue: uint64(mainUtxo.Value),
+	}
+}
+
+// buildDepositKey computes the key identifying the deposit request in the
+// Bridge contract. The key is computed as
+// keccak256(fundingTxHash | fundingOutputIndex).
+func buildDepositKey(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+) *big.Int {
+	outputIndexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(outputIndexBytes, fundingOutputIndex)
+
+	depositKey := crypto.Keccak256Hash(fundingTxHash[:], outputIndexBytes)
+
+	return depositKey.Big()
+}
+
+// computeMainUtxoHash computes the hash of the given main UTXO in the same
+// way as the Bridge contract does, i.e.
+// keccak256(txHash | txOutputIndex | txOutputValue).
+func computeMainUtxoHash(mainUtxo *bitcoin.UnspentTransactionOutput) [32]byte {
+	outputIndexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(outputIndexBytes, mainUtxo.Outpoint.OutputIndex)
+
+	valueBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(valueBytes, uint64(mainUtxo.Value))
+
+	return crypto.Keccak256Hash(
+		mainUtxo.Outpoint.TransactionHash[:],
+		outputIndexBytes,
+		valueBytes,
+	)
+}
+
+// computeMovingFundsTargetWalletsCommitmentHash computes the hash of the
+// given target wallets in the same way as the Bridge contract does, i.e.
+// keccak256(abi.encodePacked(targetWallets)). Elements of a packed array are
+// padded to 32 bytes so each 20-byte target wallet is right-padded with zeros.
+func computeMovingFundsTargetWalletsCommitmentHash(
+	targetWallets [][20]byte,
+) [32]byte {
+	packed := make([]byte, 0, 32*len(targetWallets))
+	for _, targetWallet := range targetWallets {
+		var element [32]byte
+		copy(element[:], targetWallet[:])
+		packed = append(packed, element[:]...)
+	}
+
+	return crypto.Keccak256Hash(packed)
+}
+
+// buildRedemptionKey computes the key identifying the pending redemption
+// request in the Bridge contract. The key is computed as
+// keccak256(walletPublicKeyHash | redeemerOutputScript) where the
+// redeemerOutputScript is prepended with its length.
+func buildRedemptionKey(
+	walletPublicKeyHash [20]byte,
+	redeemerOutputScript bitcoin.Script,
+) (*big.Int, error) {
+	prefixedScript := bytes.Buffer{}
+
+	err := wire.WriteVarBytes(&prefixedScript, 0, redeemerOutputScript)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot prepend redeemer output script with its length: [%v]",
+			err,
+		)
+	}
+
+	redemptionKey := crypto.Keccak256Hash(
+		walletPublicKeyHash[:],
+		prefixedScript.Bytes(),
+	)
+
+	return redemptionKey.Big(), nil
+}
+
+// parseLengthPrefixedScript strips the length prefix from the given Bitcoin
+// script. The Bridge contract uses length-prefixed scripts while the
+// Bitcoin transaction outputs hold scripts without the prefix.
+func parseLengthPrefixedScript(prefixedScript []byte) (bitcoin.Script, error) {
+	script, err := wire.ReadVarBytes(
+		bytes.NewReader(prefixedScript),
+		0,
+		uint32(len(prefixedScript)),
+		"script",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return script, nil
+}
+
+// containsWalletPublicKeyHash checks whether the given wallet public key
+// hash is present in the given filter slice. An empty filter contains
+// all wallet public key hashes.
+func containsWalletPublicKeyHash(
+	filter [][20]byte,
+	walletPublicKeyHash [20]byte,
+) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, filterWalletPublicKeyHash := range filter {
+		if filterWalletPublicKeyHash == walletPublicKeyHash {
+			return true
+		}
+	}
+
+	return false
+}
@@ -306,3 +306,55 @@ func TestParseDkgResultValidationOutcome(t *testing.T) {
 		)
 	}
 }
+
+func TestParseLengthPrefixedScript(t *testing.T) {
+	var tests = map[string]struct {
+		prefixedScript string
+		expectedScript string
+		expectedError  bool
+	}{
+		"P2WPKH script": {
+			prefixedScript: "160014e6f9d74726b19b75f16fe1e9feaec048aa4fa1d0",
+			expectedScript: "0014e6f9d74726b19b75f16fe1e9feaec048aa4fa1d0",
+		},
+		"P2PKH script": {
+			prefixedScript: "1976a914e6f9d74726b19b75f16fe1e9feaec048aa4fa1d088ac",
+			expectedScript: "76a914e6f9d74726b19b75f16fe1e9feaec048aa4fa1d088ac",
+		},
+		"length prefix exceeding script length": {
+			prefixedScript: "170014e6f9d74726b19b75f16fe1e9feaec048aa4fa1d0",
+			expectedError:  true,
+		},
+		"empty script": {
+			prefixedScript: "",
+			expectedError:  true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			prefixedScript, err := hex.DecodeString(test.prefixedScript)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			script, err := parseLengthPrefixedScript(prefixedScript)
+			if test.expectedError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertStringsEqual(
+				t,
+				"script",
+				test.expectedScript,
+				hex.EncodeToString(script),
+			)
+		})
+	}
+}
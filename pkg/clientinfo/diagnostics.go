@@ -118,6 +118,34 @@ func (r *Registry) RegisterClientInfoSource(
 	})
 }
 
+// RegisterNetworkTrafficSource registers the diagnostics source providing
+// network traffic counters per broadcast channel and message type.
+func (r *Registry) RegisterNetworkTrafficSource(netProvider net.Provider) {
+	r.RegisterDiagnosticSource("network_traffic", func() string {
+		bytes, err := json.Marshal(netProvider.TrafficMetrics().Snapshot())
+		if err != nil {
+			logger.Error("error on serializing network traffic to JSON: [%v]", err)
+			return ""
+		}
+
+		return string(bytes)
+	})
+}
+
+// RegisterPeerScoresSource registers the diagnostics source providing
+// reputation scores of misbehaving and banned peers.
+func (r *Registry) RegisterPeerScoresSource(netProvider net.Provider) {
+	r.RegisterDiagnosticSource("peer_scores", func() string {
+		bytes, err := json.Marshal(netProvider.PeerReputation().PeerScores())
+		if err != nil {
+			logger.Error("error on serializing peer scores to JSON: [%v]", err)
+			return ""
+		}
+
+		return string(bytes)
+	})
+}
+
 // RegisterApplicationSource registers the diagnostics source providing
 // information about the application.
 func (r *Registry) RegisterApplicationSource(
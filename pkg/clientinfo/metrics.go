@@ -7,6 +7,7 @@ import (
 	"github.com/keep-network/keep-common/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/retransmission"
 )
 
 type Source func() float64
@@ -17,6 +18,21 @@ const (
 	ConnectedBootstrapCountMetricName = "connected_bootstrap_count"
 	EthConnectivityMetricName         = "eth_connectivity"
 	ClientInfoMetricName              = "client_info"
+
+	RetransmissionCacheSizeMetricName        = "retransmission_cache_size"
+	RetransmissionCacheHitRateMetricName     = "retransmission_cache_hit_rate"
+	RetransmissionCacheHitsMetricName        = "retransmission_cache_hits_count"
+	RetransmissionCacheMissesMetricName      = "retransmission_cache_misses_count"
+	RetransmissionCacheEvictionsMetricName   = "retransmission_cache_evictions_count"
+	RetransmissionCacheExpirationsMetricName = "retransmission_cache_expirations_count"
+
+	NetworkMessagesSentMetricName              = "network_messages_sent_count"
+	NetworkMessagesRetransmissionsMetricName   = "network_messages_retransmissions_count"
+	NetworkSentBytesMetricName                 = "network_sent_bytes"
+	NetworkMessagesReceivedMetricName          = "network_messages_received_count"
+	NetworkReceivedBytesMetricName             = "network_received_bytes"
+	NetworkMessagesDroppedByFilterMetricName   = "network_messages_dropped_by_filter_count"
+	NetworkMessagesUnmarshalFailuresMetricName = "network_messages_unmarshal_failures_count"
 )
 
 const (
@@ -75,6 +91,85 @@ func (r *Registry) ObserveConnectedBootstrapCount(
 	)
 }
 
+// ObserveRetransmissionCache triggers an observation process of metrics of
+// retransmission caches used to filter out retransmitted network messages.
+func (r *Registry) ObserveRetransmissionCache(
+	cacheMetrics *retransmission.CacheMetrics,
+	tick time.Duration,
+) {
+	inputs := map[string]Source{
+		RetransmissionCacheSizeMetricName: func() float64 {
+			return float64(cacheMetrics.Size())
+		},
+		RetransmissionCacheHitRateMetricName: func() float64 {
+			return cacheMetrics.HitRate()
+		},
+		RetransmissionCacheHitsMetricName: func() float64 {
+			return float64(cacheMetrics.Hits())
+		},
+		RetransmissionCacheMissesMetricName: func() float64 {
+			return float64(cacheMetrics.Misses())
+		},
+		RetransmissionCacheEvictionsMetricName: func() float64 {
+			return float64(cacheMetrics.Evictions())
+		},
+		RetransmissionCacheExpirationsMetricName: func() float64 {
+			return float64(cacheMetrics.Expirations())
+		},
+	}
+
+	for name, input := range inputs {
+		r.observe(
+			name,
+			input,
+			validateTick(tick, DefaultNetworkMetricsTick),
+		)
+	}
+}
+
+// ObserveNetworkTraffic triggers an observation process of metrics of the
+// network traffic summed over all broadcast channels and message types. The
+// breakdown per channel and message type is exposed by the network_traffic
+// diagnostics source.
+func (r *Registry) ObserveNetworkTraffic(
+	netProvider net.Provider,
+	tick time.Duration,
+) {
+	trafficMetrics := netProvider.TrafficMetrics()
+
+	inputs := map[string]Source{
+		NetworkMessagesSentMetricName: func() float64 {
+			return float64(trafficMetrics.Total().SentCount)
+		},
+		NetworkMessagesRetransmissionsMetricName: func() float64 {
+			return float64(trafficMetrics.Total().RetransmissionsCount)
+		},
+		NetworkSentBytesMetricName: func() float64 {
+			return float64(trafficMetrics.Total().SentBytes)
+		},
+		NetworkMessagesReceivedMetricName: func() float64 {
+			return float64(trafficMetrics.Total().ReceivedCount)
+		},
+		NetworkReceivedBytesMetricName: func() float64 {
+			return float64(trafficMetrics.Total().ReceivedBytes)
+		},
+		NetworkMessagesDroppedByFilterMetricName: func() float64 {
+			return float64(trafficMetrics.Total().DroppedByFilterCount)
+		},
+		NetworkMessagesUnmarshalFailuresMetricName: func() float64 {
+			return float64(trafficMetrics.Total().UnmarshalFailuresCount)
+		},
+	}
+
+	for name, input := range inputs {
+		r.observe(
+			name,
+			input,
+			validateTick(tick, DefaultNetworkMetricsTick),
+		)
+	}
+}
+
 // ObserveEthConnectivity triggers an observation process of the
 // eth_connectivity metric.
 func (r *Registry) ObserveEthConnectivity(
@@ -42,7 +42,8 @@ type ParameterPool[T any] struct {
 
 // NewParameterPool creates a new instance of ParameterPool.
 // The generateFn may return nil when the context passed to it has been
-// cancelled or timed out during computations.
+// cancelled or timed out during computations. It returns an error if the
+// parameters cannot be read from the persistence.
 func NewParameterPool[T any](
 	logger log.StandardLogger,
 	scheduler *Scheduler,
@@ -50,12 +51,15 @@ func NewParameterPool[T any](
 	poolSize int,
 	generateFn func(context.Context) *T,
 	generateDelay time.Duration,
-) *ParameterPool[T] {
+) (*ParameterPool[T], error) {
 	pool := make(chan *Persisted[T], poolSize)
 
 	all, err := persistence.ReadAll()
 	if err != nil {
-		logger.Errorf("failed to read parameters from persistence: [%w]", err)
+		return nil, fmt.Errorf(
+			"failed to read parameters from persistence: [%w]",
+			err,
+		)
 	}
 
 	logger.Debugf("read [%d] parameters from persistence", len(all))
@@ -118,7 +122,7 @@ func NewParameterPool[T any](
 	return &ParameterPool[T]{
 		persistence: persistence,
 		pool:        pool,
-	}
+	}, nil
 }
 
 // GetNow returns a new parameter from the pool. Returns ErrEmptyPool when the
@@ -194,6 +194,30 @@ func TestReadAll(t *testing.T) {
 
 }
 
+// TestReadAll_Error ensures the pool is not created if parameters cannot be
+// read from the persistence.
+func TestReadAll_Error(t *testing.T) {
+	persistence := &mockPersistence{
+		storage:    make(map[string]*big.Int),
+		readAllErr: fmt.Errorf("cannot decrypt"),
+	}
+
+	scheduler := &Scheduler{}
+	defer scheduler.stop()
+
+	_, err := NewParameterPool[big.Int](
+		logger,
+		scheduler,
+		persistence,
+		100,
+		func(context.Context) *big.Int { return big.NewInt(1) },
+		time.Duration(0), // no delay
+	)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
 // TestDelete ensures parameters fetched from the pool are deleted from the
 // persistence layer.
 func TestDelete(t *testing.T) {
@@ -244,19 +268,25 @@ func newTestPoolWithPersistence(
 
 	scheduler := &Scheduler{}
 
-	return NewParameterPool[big.Int](
+	pool, err := NewParameterPool[big.Int](
 		logger,
 		scheduler,
 		persistence,
 		targetSize,
 		generateFn,
 		time.Duration(0), // no delay
-	), scheduler
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return pool, scheduler
 }
 
 type mockPersistence struct {
-	storage map[string]*big.Int
-	mutex   sync.RWMutex
+	storage    map[string]*big.Int
+	readAllErr error
+	mutex      sync.RWMutex
 }
 
 func (mp *mockPersistence) Save(element *big.Int) (*Persisted[big.Int], error) {
@@ -280,6 +310,10 @@ func (mp *mockPersistence) ReadAll() ([]*Persisted[big.Int], error) {
 	mp.mutex.RLock()
 	defer mp.mutex.RUnlock()
 
+	if mp.readAllErr != nil {
+		return nil, mp.readAllErr
+	}
+
 	all := make([]*Persisted[big.Int], 0, len(mp.storage))
 	for _, v := range mp.storage {
 		all = append(all, &Persisted[big.Int]{*v, calcID(v)})
@@ -1,6 +1,7 @@
 package maintainer
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
@@ -11,6 +12,11 @@ var errNoBlocksSet = fmt.Errorf("blockchain does not contain any blocks")
 // localBitcoinChain represents a local Bitcoin chain.
 type localBitcoinChain struct {
 	blockHeaders map[uint]*bitcoin.BlockHeader
+
+	transactions              map[bitcoin.Hash]*bitcoin.Transaction
+	transactionsOrder         []bitcoin.Hash
+	transactionsConfirmations map[bitcoin.Hash]uint
+	transactionsMerkleProofs  map[bitcoin.Hash]*bitcoin.TransactionMerkleProof
 }
 
 // GetTransaction gets the transaction with the given transaction hash.
@@ -19,7 +25,12 @@ type localBitcoinChain struct {
 func (lc *localBitcoinChain) GetTransaction(
 	transactionHash bitcoin.Hash,
 ) (*bitcoin.Transaction, error) {
-	panic("unsupported")
+	transaction, found := lc.transactions[transactionHash]
+	if !found {
+		return nil, fmt.Errorf("transaction not found")
+	}
+
+	return transaction, nil
 }
 
 // GetTransactionConfirmations gets the number of confirmations for the
@@ -28,7 +39,12 @@ func (lc *localBitcoinChain) GetTransaction(
 func (lc *localBitcoinChain) GetTransactionConfirmations(
 	transactionHash bitcoin.Hash,
 ) (uint, error) {
-	panic("unsupported")
+	confirmations, found := lc.transactionsConfirmations[transactionHash]
+	if !found {
+		return 0, fmt.Errorf("transaction not found")
+	}
+
+	return confirmations, nil
 }
 
 // BroadcastTransaction broadcasts the given transaction over the
@@ -76,6 +92,107 @@ func (lc *localBitcoinChain) GetBlockHeader(
 	return blockHeader, nil
 }
 
+// GetBlockHeaders gets block headers of the given count of consecutive
+// blocks starting from the given block height.
+func (lc *localBitcoinChain) GetBlockHeaders(
+	startHeight uint,
+	count uint,
+) ([]*bitcoin.BlockHeader, error) {
+	blockHeaders := make([]*bitcoin.BlockHeader, 0, count)
+	for height := startHeight; height < startHeight+count; height++ {
+		blockHeader, err := lc.GetBlockHeader(height)
+		if err != nil {
+			return nil, err
+		}
+
+		blockHeaders = append(blockHeaders, blockHeader)
+	}
+
+	return blockHeaders, nil
+}
+
+// GetTransactionMerkle gets the Merkle branch proving the inclusion of
+// the transaction with the given hash in the block with the given height.
+func (lc *localBitcoinChain) GetTransactionMerkle(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	merkleProof, found := lc.transactionsMerkleProofs[transactionHash]
+	if !found || merkleProof.BlockHeight != blockHeight {
+		return nil, fmt.Errorf("transaction not found in the block")
+	}
+
+	return merkleProof, nil
+}
+
+// GetTransactionsForPublicKeyHash gets confirmed transactions related to
+// the given public key hash.
+func (lc *localBitcoinChain) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*bitcoin.Transaction, error) {
+	p2pkh, err := bitcoin.PayToPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+	p2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	isWalletScript := func(script []byte) bool {
+		return bytes.Equal(script, p2pkh) || bytes.Equal(script, p2wpkh)
+	}
+
+	// The order of addition determines the order of transactions on the chain.
+	transactions := make([]*bitcoin.Transaction, 0)
+	for _, transactionHash := range lc.transactionsOrder {
+		transaction := lc.transactions[transactionHash]
+
+		related := false
+		for _, output := range transaction.Outputs {
+			if isWalletScript(output.PublicKeyScript) {
+				related = true
+			}
+		}
+		for _, input := range transaction.Inputs {
+			previousTransaction, ok := lc.transactions[input.Outpoint.TransactionHash]
+			if ok && isWalletScript(
+				previousTransaction.Outputs[input.Outpoint.OutputIndex].PublicKeyScript,
+			) {
+				related = true
+			}
+		}
+
+		if related {
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	if limit > 0 && len(transactions) > limit {
+		transactions = transactions[len(transactions)-limit:]
+	}
+
+	return transactions, nil
+}
+
+// GetUnspentOutputsForScript gets all unspent outputs locked using the
+// given script.
+func (lc *localBitcoinChain) GetUnspentOutputsForScript(
+	script bitcoin.Script,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	panic("unsupported")
+}
+
+// EstimateSatPerVByteFee returns the estimated sat/vbyte fee for a
+// transaction to be confirmed within the given number of blocks. If the
+// fee could not be estimated, this function returns an error.
+func (lc *localBitcoinChain) EstimateSatPerVByteFee(
+	blocks uint32,
+) (int64, error) {
+	panic("unsupported")
+}
+
 // SetBlockHeaders sets internal headers for testing purposes.
 func (lc *localBitcoinChain) SetBlockHeaders(
 	blockHeaders map[uint]*bitcoin.BlockHeader,
@@ -83,8 +200,36 @@ func (lc *localBitcoinChain) SetBlockHeaders(
 	lc.blockHeaders = blockHeaders
 }
 
+// AddTransaction adds the given transaction with the given number of
+// confirmations to the chain for testing purposes.
+func (lc *localBitcoinChain) AddTransaction(
+	transaction *bitcoin.Transaction,
+	confirmations uint,
+) {
+	transactionHash := transaction.Hash()
+
+	lc.transactions[transactionHash] = transaction
+	lc.transactionsOrder = append(lc.transactionsOrder, transactionHash)
+	lc.transactionsConfirmations[transactionHash] = confirmations
+}
+
+// SetTransactionMerkleProof sets the Merkle proof of the given transaction
+// for testing purposes.
+func (lc *localBitcoinChain) SetTransactionMerkleProof(
+	transactionHash bitcoin.Hash,
+	merkleProof *bitcoin.TransactionMerkleProof,
+) {
+	lc.transactionsMerkleProofs[transactionHash] = merkleProof
+}
+
 // connectLocalBitcoinChain connects to the local Bitcoin chain and returns
 // a chain handle.
 func connectLocalBitcoinChain() *localBitcoinChain {
-	return &localBitcoinChain{}
+	return &localBitcoinChain{
+		transactions:              make(map[bitcoin.Hash]*bitcoin.Transaction),
+		transactionsConfirmations: make(map[bitcoin.Hash]uint),
+		transactionsMerkleProofs: make(
+			map[bitcoin.Hash]*bitcoin.TransactionMerkleProof,
+		),
+	}
 }
@@ -38,21 +38,16 @@ var (
 	)
 )
 
-func initializeBitcoinDifficultyMaintainer(
-	ctx context.Context,
+func newBitcoinDifficultyMaintainer(
 	btcChain bitcoin.Chain,
 	chain BitcoinDifficultyChain,
 	idleBackOffTime time.Duration,
-	restartBackOffTime time.Duration,
-) {
-	bitcoinDifficultyMaintainer := &bitcoinDifficultyMaintainer{
-		btcChain:           btcChain,
-		chain:              chain,
-		idleBackOffTime:    idleBackOffTime,
-		restartBackOffTime: restartBackOffTime,
+) *bitcoinDifficultyMaintainer {
+	return &bitcoinDifficultyMaintainer{
+		btcChain:        btcChain,
+		chain:           chain,
+		idleBackOffTime: idleBackOffTime,
 	}
-
-	go bitcoinDifficultyMaintainer.startControlLoop(ctx)
 }
 
 // bitcoinDifficultyMaintainer is the part of maintainer responsible for
@@ -61,34 +56,18 @@ type bitcoinDifficultyMaintainer struct {
 	btcChain bitcoin.Chain
 	chain    BitcoinDifficultyChain
 
-	idleBackOffTime    time.Duration
-	restartBackOffTime time.Duration
+	idleBackOffTime time.Duration
 }
 
-// startControlLoop starts the loop responsible for controlling the Bitcoin
-// difficulty maintainer.
-func (bdm *bitcoinDifficultyMaintainer) startControlLoop(ctx context.Context) {
-	logger.Info("starting Bitcoin difficulty maintainer")
-
-	defer func() {
-		logger.Info("stopping Bitcoin difficulty maintainer")
-	}()
-
-	for {
-		err := bdm.proveEpochs(ctx)
-		if err != nil {
-			logger.Errorf(
-				"error while proving Bitcoin epochs: [%v]; restarting maintainer",
-				err,
-			)
-		}
+// Name returns the name of the Bitcoin difficulty maintainer.
+func (bdm *bitcoinDifficultyMaintainer) Name() string {
+	return "bitcoin_difficulty"
+}
 
-		select {
-		case <-time.After(bdm.restartBackOffTime):
-		case <-ctx.Done():
-			return
-		}
-	}
+// Start proves Bitcoin blockchain epochs until the given context is done or
+// an error occurs.
+func (bdm *bitcoinDifficultyMaintainer) Start(ctx context.Context) error {
+	return bdm.proveEpochs(ctx)
 }
 
 // proveEpochs proves Bitcoin blockchain epochs in the Bitcoin difficulty chain.
@@ -294,22 +273,17 @@ func (bdm *bitcoinDifficultyMaintainer) getBlockHeaders(
 ) (
 	[]*bitcoin.BlockHeader, error,
 ) {
-	var headers []*bitcoin.BlockHeader
-
-	// TODO: Consider modifying the Bitcoin chain to return multiple block
-	//       headers: GetBlockHeaders(startHeight, count). Return multiple
-	//       block headers with one call instead of iterating.
-	for height := firstHeaderHeight; height <= lastHeaderHeight; height++ {
-		header, err := bdm.btcChain.GetBlockHeader(height)
-		if err != nil {
-			return []*bitcoin.BlockHeader{}, fmt.Errorf(
-				"failed to get block header at height %d: [%w]",
-				height,
-				err,
-			)
-		}
-
-		headers = append(headers, header)
+	headers, err := bdm.btcChain.GetBlockHeaders(
+		firstHeaderHeight,
+		lastHeaderHeight-firstHeaderHeight+1,
+	)
+	if err != nil {
+		return []*bitcoin.BlockHeader{}, fmt.Errorf(
+			"failed to get block headers from range [%d:%d]: [%w]",
+			firstHeaderHeight,
+			lastHeaderHeight,
+			err,
+		)
 	}
 
 	return headers, nil
@@ -56,10 +56,9 @@ func TestVerifySubmissionEligibility(t *testing.T) {
 			)
 
 			bitcoinDifficultyMaintainer := &bitcoinDifficultyMaintainer{
-				btcChain:           nil,
-				chain:              difficultyChain,
-				idleBackOffTime:    bitcoinDifficultyDefaultIdleBackOffTime,
-				restartBackOffTime: bitcoinDifficultyDefaultRestartBackoffTime,
+				btcChain:        nil,
+				chain:           difficultyChain,
+				idleBackOffTime: bitcoinDifficultyDefaultIdleBackOffTime,
 			}
 
 			err := bitcoinDifficultyMaintainer.verifySubmissionEligibility()
@@ -138,10 +137,9 @@ func TestProveNextEpoch(t *testing.T) {
 	difficultyChain.SetProofLength(3)
 
 	bitcoinDifficultyMaintainer := &bitcoinDifficultyMaintainer{
-		btcChain:           btcChain,
-		chain:              difficultyChain,
-		idleBackOffTime:    bitcoinDifficultyDefaultIdleBackOffTime,
-		restartBackOffTime: bitcoinDifficultyDefaultRestartBackoffTime,
+		btcChain:        btcChain,
+		chain:           difficultyChain,
+		idleBackOffTime: bitcoinDifficultyDefaultIdleBackOffTime,
 	}
 
 	result, err := bitcoinDifficultyMaintainer.proveNextEpoch(ctx)
@@ -238,10 +236,9 @@ func TestGetBlockHeaders(t *testing.T) {
 	btcChain.SetBlockHeaders(blockHeaders)
 
 	bitcoinDifficultyMaintainer := &bitcoinDifficultyMaintainer{
-		btcChain:           btcChain,
-		chain:              nil,
-		idleBackOffTime:    bitcoinDifficultyDefaultIdleBackOffTime,
-		restartBackOffTime: bitcoinDifficultyDefaultRestartBackoffTime,
+		btcChain:        btcChain,
+		chain:           nil,
+		idleBackOffTime: bitcoinDifficultyDefaultIdleBackOffTime,
 	}
 
 	headers, err := bitcoinDifficultyMaintainer.getBlockHeaders(700000, 700002)
@@ -269,10 +266,9 @@ func TestWaitForCurrentEpochUpdate_Successful(t *testing.T) {
 	difficultyChain.SetCurrentEpoch(currentEpoch)
 
 	bitcoinDifficultyMaintainer := &bitcoinDifficultyMaintainer{
-		btcChain:           nil,
-		chain:              difficultyChain,
-		idleBackOffTime:    2 * time.Second,
-		restartBackOffTime: 2 * time.Second,
+		btcChain:        nil,
+		chain:           difficultyChain,
+		idleBackOffTime: 2 * time.Second,
 	}
 
 	// Run function on a goroutine. The function should wait until the current
@@ -317,10 +313,9 @@ func TestWaitForCurrentEpochUpdate_Cancelled(t *testing.T) {
 	difficultyChain.SetCurrentEpoch(currentEpoch)
 
 	bitcoinDifficultyMaintainer := &bitcoinDifficultyMaintainer{
-		btcChain:           nil,
-		chain:              difficultyChain,
-		idleBackOffTime:    2 * time.Second,
-		restartBackOffTime: 2 * time.Second,
+		btcChain:        nil,
+		chain:           difficultyChain,
+		idleBackOffTime: 2 * time.Second,
 	}
 
 	// Run function on a goroutine. The function should wait until the current
@@ -359,10 +354,9 @@ func TestProveEpochs_ErrorVerifyingSubmissionEligibility(t *testing.T) {
 	difficultyChain.SetAuthorizationRequired(true)
 
 	bitcoinDifficultyMaintainer := &bitcoinDifficultyMaintainer{
-		btcChain:           nil,
-		chain:              difficultyChain,
-		idleBackOffTime:    bitcoinDifficultyDefaultIdleBackOffTime,
-		restartBackOffTime: bitcoinDifficultyDefaultRestartBackoffTime,
+		btcChain:        nil,
+		chain:           difficultyChain,
+		idleBackOffTime: bitcoinDifficultyDefaultIdleBackOffTime,
 	}
 
 	err := bitcoinDifficultyMaintainer.proveEpochs(ctx)
@@ -387,10 +381,9 @@ func TestProveEpochs_ErrorProvingSingleEpoch(t *testing.T) {
 	btcChain := connectLocalBitcoinChain()
 
 	bitcoinDifficultyMaintainer := &bitcoinDifficultyMaintainer{
-		btcChain:           btcChain,
-		chain:              difficultyChain,
-		idleBackOffTime:    bitcoinDifficultyDefaultIdleBackOffTime,
-		restartBackOffTime: bitcoinDifficultyDefaultRestartBackoffTime,
+		btcChain:        btcChain,
+		chain:           difficultyChain,
+		idleBackOffTime: bitcoinDifficultyDefaultIdleBackOffTime,
 	}
 
 	err := bitcoinDifficultyMaintainer.proveEpochs(ctx)
@@ -438,10 +431,9 @@ func TestProveEpochs_Successful(t *testing.T) {
 	btcChain.SetBlockHeaders(blockHeaders)
 
 	bitcoinDifficultyMaintainer := &bitcoinDifficultyMaintainer{
-		btcChain:           btcChain,
-		chain:              difficultyChain,
-		idleBackOffTime:    2 * time.Second,
-		restartBackOffTime: 2 * time.Second,
+		btcChain:        btcChain,
+		chain:           difficultyChain,
+		idleBackOffTime: 2 * time.Second,
 	}
 
 	// Run a goroutine that will cancel the context while the maintainer is
@@ -476,13 +468,20 @@ func TestBitcoinDifficultyMaintainer_Integration(t *testing.T) {
 	idleBackOffTime := 500 * time.Millisecond
 	restartBackOffTime := 1 * time.Second
 
-	initializeBitcoinDifficultyMaintainer(
-		ctx,
-		btcChain,
-		difficultyChain,
-		idleBackOffTime,
+	supervisor := newSupervisor(ctx)
+	defer supervisor.stop()
+
+	err := supervisor.supervise(
+		newBitcoinDifficultyMaintainer(
+			btcChain,
+			difficultyChain,
+			idleBackOffTime,
+		),
 		restartBackOffTime,
 	)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	//************ Loop restart on error ************
 	// Do not set any headers in the Bitcoin chain, so that an error is
@@ -1,8 +1,11 @@
 package maintainer
 
 import (
+	"math/big"
+
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/tbtc"
 )
 
 // BitcoinDifficultyChain is an interface that provides the ability to
@@ -39,3 +42,91 @@ type BitcoinDifficultyChain interface {
 	// retarget proof.
 	ProofLength() (uint64, error)
 }
+
+// SpvChain is an interface that provides the ability to communicate with the
+// Bridge on-chain contract in order to submit SPV proofs of Bitcoin
+// transactions performed by wallets.
+type SpvChain interface {
+	// BlockCounter returns the chain's block counter.
+	BlockCounter() (chain.BlockCounter, error)
+
+	// PastDepositRevealedEvents fetches past deposit reveal events according
+	// to the provided filter or unfiltered if the filter is nil. Returned
+	// events are sorted by the block number in the ascending order, i.e. the
+	// latest event is at the end of the slice.
+	PastDepositRevealedEvents(
+		filter *tbtc.DepositRevealedEventFilter,
+	) ([]*tbtc.DepositRevealedEvent, error)
+
+	// PastRedemptionRequestedEvents fetches past redemption requested events
+	// according to the provided filter or unfiltered if the filter is nil.
+	// Returned events are sorted by the block number in the ascending order,
+	// i.e. the latest event is at the end of the slice.
+	PastRedemptionRequestedEvents(
+		filter *tbtc.RedemptionRequestedEventFilter,
+	) ([]*tbtc.RedemptionRequestedEvent, error)
+
+	// PastMovingFundsCommitmentSubmittedEvents fetches past moving funds
+	// commitment submitted events according to the provided filter or
+	// unfiltered if the filter is nil. Returned events are sorted by the block
+	// number in the ascending order, i.e. the latest event is at the end of
+	// the slice.
+	PastMovingFundsCommitmentSubmittedEvents(
+		filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+	) ([]*tbtc.MovingFundsCommitmentSubmittedEvent, error)
+
+	// GetWallet gets the on-chain data for the given wallet. Returns an error
+	// if the wallet was not found.
+	GetWallet(walletPublicKeyHash [20]byte) (*tbtc.WalletChainData, error)
+
+	// ComputeMainUtxoHash computes the hash of the provided main UTXO
+	// according to the on-chain Bridge rules.
+	ComputeMainUtxoHash(mainUtxo *bitcoin.UnspentTransactionOutput) [32]byte
+
+	// ComputeMovingFundsTargetWalletsCommitmentHash computes the hash of the
+	// provided moving funds target wallets according to the on-chain Bridge
+	// rules.
+	ComputeMovingFundsTargetWalletsCommitmentHash(targetWallets [][20]byte) [32]byte
+
+	// GetDepositRequest gets the on-chain deposit request for the given
+	// funding transaction hash and output index. The returned bool value
+	// indicates whether the request was found or not.
+	GetDepositRequest(
+		fundingTxHash bitcoin.Hash,
+		fundingOutputIndex uint32,
+	) (*tbtc.DepositChainRequest, bool, error)
+
+	// TxProofDifficultyFactor returns the number of confirmations on the
+	// Bitcoin chain required to successfully evaluate an SPV proof.
+	TxProofDifficultyFactor() (*big.Int, error)
+
+	// SubmitDepositSweepProof submits the SPV proof of the given deposit
+	// sweep transaction. The mainUtxo argument is the wallet's main UTXO
+	// spent by the transaction or nil if the wallet did not have a main UTXO.
+	// The vault argument is the vault the swept deposits were revealed to or
+	// nil if they were revealed without a vault.
+	SubmitDepositSweepProof(
+		transaction *bitcoin.Transaction,
+		proof *bitcoin.SpvProof,
+		mainUtxo *bitcoin.UnspentTransactionOutput,
+		vault *chain.Address,
+	) error
+
+	// SubmitRedemptionProof submits the SPV proof of the given redemption
+	// transaction spending the given main UTXO of the given wallet.
+	SubmitRedemptionProof(
+		transaction *bitcoin.Transaction,
+		proof *bitcoin.SpvProof,
+		mainUtxo *bitcoin.UnspentTransactionOutput,
+		walletPublicKeyHash [20]byte,
+	) error
+
+	// SubmitMovingFundsProof submits the SPV proof of the given moving funds
+	// transaction spending the given main UTXO of the given wallet.
+	SubmitMovingFundsProof(
+		transaction *bitcoin.Transaction,
+		proof *bitcoin.SpvProof,
+		mainUtxo *bitcoin.UnspentTransactionOutput,
+		walletPublicKeyHash [20]byte,
+	) error
+}
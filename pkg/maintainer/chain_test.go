@@ -1,10 +1,16 @@
 package maintainer
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
 	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/chain/local_v1"
 	"github.com/keep-network/keep-core/pkg/operator"
+	"github.com/keep-network/keep-core/pkg/tbtc"
 )
 
 // RetargetEvent represents an invocation of the Retarget method.
@@ -130,3 +136,241 @@ func connectLocalBitcoinDifficultyChain() *localBitcoinDifficultyChain {
 		authorizedOperators: make(map[chain.Address]bool),
 	}
 }
+
+// submittedSpvProof represents an SPV proof submitted to the local SPV chain.
+type submittedSpvProof struct {
+	transaction         *bitcoin.Transaction
+	proof               *bitcoin.SpvProof
+	mainUtxo            *bitcoin.UnspentTransactionOutput
+	vault               *chain.Address
+	walletPublicKeyHash [20]byte
+}
+
+// depositKey identifies a deposit request in the local SPV chain.
+type depositKey struct {
+	fundingTxHash      bitcoin.Hash
+	fundingOutputIndex uint32
+}
+
+// localSpvChain represents a local SPV chain.
+type localSpvChain struct {
+	txProofDifficultyFactor *big.Int
+
+	wallets         map[[20]byte]*tbtc.WalletChainData
+	depositRequests map[depositKey]*tbtc.DepositChainRequest
+
+	depositSweepProofs []*submittedSpvProof
+	redemptionProofs   []*submittedSpvProof
+	movingFundsProofs  []*submittedSpvProof
+
+	depositRevealedEvents                []*tbtc.DepositRevealedEvent
+	redemptionRequestedEvents            []*tbtc.RedemptionRequestedEvent
+	movingFundsCommitmentSubmittedEvents []*tbtc.MovingFundsCommitmentSubmittedEvent
+}
+
+// BlockCounter returns the chain's block counter.
+func (lsc *localSpvChain) BlockCounter() (chain.BlockCounter, error) {
+	return local_v1.BlockCounter()
+}
+
+// PastDepositRevealedEvents fetches past deposit reveal events according
+// to the provided filter or unfiltered if the filter is nil.
+func (lsc *localSpvChain) PastDepositRevealedEvents(
+	filter *tbtc.DepositRevealedEventFilter,
+) ([]*tbtc.DepositRevealedEvent, error) {
+	events := make([]*tbtc.DepositRevealedEvent, 0)
+	for _, event := range lsc.depositRevealedEvents {
+		if filter == nil || event.BlockNumber >= filter.StartBlock {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// PastRedemptionRequestedEvents fetches past redemption requested events
+// according to the provided filter or unfiltered if the filter is nil.
+func (lsc *localSpvChain) PastRedemptionRequestedEvents(
+	filter *tbtc.RedemptionRequestedEventFilter,
+) ([]*tbtc.RedemptionRequestedEvent, error) {
+	events := make([]*tbtc.RedemptionRequestedEvent, 0)
+	for _, event := range lsc.redemptionRequestedEvents {
+		if filter == nil || event.BlockNumber >= filter.StartBlock {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// PastMovingFundsCommitmentSubmittedEvents fetches past moving funds
+// commitment submitted events according to the provided filter or
+// unfiltered if the filter is nil.
+func (lsc *localSpvChain) PastMovingFundsCommitmentSubmittedEvents(
+	filter *tbtc.MovingFundsCommitmentSubmittedEventFilter,
+) ([]*tbtc.MovingFundsCommitmentSubmittedEvent, error) {
+	events := make([]*tbtc.MovingFundsCommitmentSubmittedEvent, 0)
+	for _, event := range lsc.movingFundsCommitmentSubmittedEvents {
+		if filter == nil || event.BlockNumber >= filter.StartBlock {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// GetWallet gets the on-chain data for the given wallet. Returns an error
+// if the wallet was not found.
+func (lsc *localSpvChain) GetWallet(
+	walletPublicKeyHash [20]byte,
+) (*tbtc.WalletChainData, error) {
+	walletChainData, ok := lsc.wallets[walletPublicKeyHash]
+	if !ok {
+		return nil, fmt.Errorf("no wallet for given PKH")
+	}
+
+	return walletChainData, nil
+}
+
+// ComputeMainUtxoHash computes the hash of the provided main UTXO.
+func (lsc *localSpvChain) ComputeMainUtxoHash(
+	mainUtxo *bitcoin.UnspentTransactionOutput,
+) [32]byte {
+	outputIndexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(outputIndexBytes, mainUtxo.Outpoint.OutputIndex)
+
+	valueBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(valueBytes, uint64(mainUtxo.Value))
+
+	var data []byte
+	data = append(data, mainUtxo.Outpoint.TransactionHash[:]...)
+	data = append(data, outputIndexBytes...)
+	data = append(data, valueBytes...)
+
+	return sha256.Sum256(data)
+}
+
+// ComputeMovingFundsTargetWalletsCommitmentHash computes the hash of the
+// provided moving funds target wallets.
+func (lsc *localSpvChain) ComputeMovingFundsTargetWalletsCommitmentHash(
+	targetWallets [][20]byte,
+) [32]byte {
+	var data []byte
+	for _, targetWallet := range targetWallets {
+		var element [32]byte
+		copy(element[:], targetWallet[:])
+		data = append(data, element[:]...)
+	}
+
+	return sha256.Sum256(data)
+}
+
+// GetDepositRequest gets the on-chain deposit request for the given
+// funding transaction hash and output index.
+func (lsc *localSpvChain) GetDepositRequest(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+) (*tbtc.DepositChainRequest, bool, error) {
+	depositRequest, ok := lsc.depositRequests[depositKey{
+		fundingTxHash:      fundingTxHash,
+		fundingOutputIndex: fundingOutputIndex,
+	}]
+
+	return depositRequest, ok, nil
+}
+
+// TxProofDifficultyFactor returns the number of confirmations on the
+// Bitcoin chain required to successfully evaluate an SPV proof.
+func (lsc *localSpvChain) TxProofDifficultyFactor() (*big.Int, error) {
+	return lsc.txProofDifficultyFactor, nil
+}
+
+// SubmitDepositSweepProof submits the SPV proof of the given deposit
+// sweep transaction.
+func (lsc *localSpvChain) SubmitDepositSweepProof(
+	transaction *bitcoin.Transaction,
+	proof *bitcoin.SpvProof,
+	mainUtxo *bitcoin.UnspentTransactionOutput,
+	vault *chain.Address,
+) error {
+	lsc.depositSweepProofs = append(
+		lsc.depositSweepProofs,
+		&submittedSpvProof{
+			transaction: transaction,
+			proof:       proof,
+			mainUtxo:    mainUtxo,
+			vault:       vault,
+		},
+	)
+
+	return nil
+}
+
+// SubmitRedemptionProof submits the SPV proof of the given redemption
+// transaction.
+func (lsc *localSpvChain) SubmitRedemptionProof(
+	transaction *bitcoin.Transaction,
+	proof *bitcoin.SpvProof,
+	mainUtxo *bitcoin.UnspentTransactionOutput,
+	walletPublicKeyHash [20]byte,
+) error {
+	lsc.redemptionProofs = append(
+		lsc.redemptionProofs,
+		&submittedSpvProof{
+			transaction:         transaction,
+			proof:               proof,
+			mainUtxo:            mainUtxo,
+			walletPublicKeyHash: walletPublicKeyHash,
+		},
+	)
+
+	return nil
+}
+
+// SubmitMovingFundsProof submits the SPV proof of the given moving funds
+// transaction.
+func (lsc *localSpvChain) SubmitMovingFundsProof(
+	transaction *bitcoin.Transaction,
+	proof *bitcoin.SpvProof,
+	mainUtxo *bitcoin.UnspentTransactionOutput,
+	walletPublicKeyHash [20]byte,
+) error {
+	lsc.movingFundsProofs = append(
+		lsc.movingFundsProofs,
+		&submittedSpvProof{
+			transaction:         transaction,
+			proof:               proof,
+			mainUtxo:            mainUtxo,
+			walletPublicKeyHash: walletPublicKeyHash,
+		},
+	)
+
+	return nil
+}
+
+// SetWallet sets the on-chain data of the given wallet.
+func (lsc *localSpvChain) SetWallet(
+	walletPublicKeyHash [20]byte,
+	walletChainData *tbtc.WalletChainData,
+) {
+	lsc.wallets[walletPublicKeyHash] = walletChainData
+}
+
+// SetDepositRequest sets the on-chain deposit request of the given deposit.
+func (lsc *localSpvChain) SetDepositRequest(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+	depositRequest *tbtc.DepositChainRequest,
+) {
+	lsc.depositRequests[depositKey{
+		fundingTxHash:      fundingTxHash,
+		fundingOutputIndex: fundingOutputIndex,
+	}] = depositRequest
+}
+
+// connectLocalSpvChain connects to the local SPV chain and returns a chain
+// handle.
+func connectLocalSpvChain(txProofDifficultyFactor int64) *localSpvChain {
+	return &localSpvChain{
+		txProofDifficultyFactor: big.NewInt(txProofDifficultyFactor),
+		wallets:                 make(map[[20]byte]*tbtc.WalletChainData),
+		depositRequests:         make(map[depositKey]*tbtc.DepositChainRequest),
+	}
+}
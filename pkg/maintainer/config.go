@@ -6,5 +6,6 @@ type Config struct {
 	// should be started.
 	BitcoinDifficulty bool
 
-	// TODO: Add options for other maintainer tasks, e.g. spv
+	// Spv indicates whether the SPV maintainer should be started.
+	Spv bool
 }
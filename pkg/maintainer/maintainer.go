@@ -2,33 +2,148 @@ package maintainer
 
 import (
 	"context"
+	"time"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/clientinfo"
 )
 
+// maintainerRegistration describes a maintainer that can be launched by
+// the supervisor.
+type maintainerRegistration struct {
+	// enabled determines whether the maintainer was enabled in the config.
+	enabled func(config Config) bool
+	// newMaintainer creates a new instance of the maintainer.
+	newMaintainer func(
+		btcChain bitcoin.Chain,
+		chain BitcoinDifficultyChain,
+		spvChain SpvChain,
+	) Maintainer
+	// restartBackOffTime is the back-off time applied before the maintainer
+	// is restarted after a failure.
+	restartBackOffTime time.Duration
+	// requiresTransactionsHistory determines whether the maintainer looks up
+	// transactions history of public key hashes on the Bitcoin chain.
+	requiresTransactionsHistory bool
+	// explicitOnly determines whether the maintainer is launched only if
+	// it was enabled in the config. Such a maintainer is not launched when
+	// no maintainer was specified in the config.
+	explicitOnly bool
+}
+
+// shouldLaunch determines whether the maintainer should be launched with
+// the given config. The launchAll flag is set if none of the maintainers
+// was enabled in the config.
+func (mr maintainerRegistration) shouldLaunch(
+	config Config,
+	launchAll bool,
+) bool {
+	if mr.enabled(config) {
+		return true
+	}
+
+	return launchAll && !mr.explicitOnly
+}
+
+// registry holds all maintainers that can be launched, keyed by the config
+// flags enabling them.
+var registry = []maintainerRegistration{
+	{
+		enabled: func(config Config) bool { return config.BitcoinDifficulty },
+		newMaintainer: func(
+			btcChain bitcoin.Chain,
+			chain BitcoinDifficultyChain,
+			spvChain SpvChain,
+		) Maintainer {
+			return newBitcoinDifficultyMaintainer(
+				btcChain,
+				chain,
+				bitcoinDifficultyDefaultIdleBackOffTime,
+			)
+		},
+		restartBackOffTime: bitcoinDifficultyDefaultRestartBackoffTime,
+	},
+	{
+		enabled: func(config Config) bool { return config.Spv },
+		newMaintainer: func(
+			btcChain bitcoin.Chain,
+			chain BitcoinDifficultyChain,
+			spvChain SpvChain,
+		) Maintainer {
+			return newSpvMaintainer(
+				btcChain,
+				spvChain,
+				spvDefaultIdleBackOffTime,
+			)
+		},
+		restartBackOffTime:          spvDefaultRestartBackoffTime,
+		requiresTransactionsHistory: true,
+		// The SPV maintainer submits proofs to the chain and spends gas so,
+		// it must be enabled explicitly.
+		explicitOnly: true,
+	},
+}
+
+// Initialize launches maintainers enabled in the config under a supervisor.
+// Each maintainer runs in isolation: its errors and panics cause only that
+// maintainer to be restarted. All maintainers are stopped once the given
+// context is done or the returned stop function is called. The stop function
+// blocks until all maintainers return so it should be called on shutdown.
+// If the client info registry is not nil, the status of each maintainer is
+// exposed as metrics. Maintainers looking up transactions history are not
+// launched if the Bitcoin chain backend does not support such lookups.
 func Initialize(
 	ctx context.Context,
 	config Config,
 	btcChain bitcoin.Chain,
+	transactionsHistorySupported bool,
 	chain BitcoinDifficultyChain,
-) {
+	spvChain SpvChain,
+	clientInfo *clientinfo.Registry,
+) (stop func()) {
 	// If none of the maintainers was specified in the config (i.e. no option was
-	// provided to the `maintainer` command), all maintainers should be launched.
-	launchAll := !config.BitcoinDifficulty
-
-	if config.BitcoinDifficulty || launchAll {
-		initializeBitcoinDifficultyMaintainer(
-			ctx,
-			btcChain,
-			chain,
-			bitcoinDifficultyDefaultIdleBackOffTime,
-			bitcoinDifficultyDefaultRestartBackoffTime,
-		)
+	// provided to the `maintainer` command), all maintainers should be launched
+	// except the ones that must be enabled explicitly.
+	launchAll := true
+	for _, registration := range registry {
+		if registration.enabled(config) {
+			launchAll = false
+			break
+		}
+	}
+
+	supervisor := newSupervisor(ctx)
+
+	for _, registration := range registry {
+		if !registration.shouldLaunch(config, launchAll) {
+			continue
+		}
+
+		maintainer := registration.newMaintainer(btcChain, chain, spvChain)
+
+		if registration.requiresTransactionsHistory &&
+			!transactionsHistorySupported {
+			supervisorLogger.Warnf(
+				"not launching maintainer [%s]; the Bitcoin chain backend "+
+					"does not support transactions history lookups",
+				maintainer.Name(),
+			)
+			continue
+		}
+
+		err := supervisor.supervise(maintainer, registration.restartBackOffTime)
+		if err != nil {
+			supervisorLogger.Errorf(
+				"cannot launch maintainer [%s]: [%v]",
+				maintainer.Name(),
+				err,
+			)
+		}
+	}
+
+	if clientInfo != nil {
+		supervisor.observeMetrics(clientInfo)
 	}
 
-	// TODO: Allow for launching multiple maintainers here. Every flag
-	//       indicating a maintainer task should launch a separate maintainer.
-	//       Notice that panic on one maintainer goroutine will crush the whole
-	//       program. Consider cancelling all maintainers if one maintainer
-	//       cannot ba launched due to a configuration error.
+	return supervisor.stop
 }
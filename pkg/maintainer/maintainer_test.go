@@ -0,0 +1,61 @@
+package maintainer
+
+import (
+	"testing"
+)
+
+func TestMaintainerRegistration_ShouldLaunch(t *testing.T) {
+	registration := maintainerRegistration{
+		enabled: func(config Config) bool { return config.Spv },
+	}
+	explicitOnlyRegistration := maintainerRegistration{
+		enabled:      func(config Config) bool { return config.Spv },
+		explicitOnly: true,
+	}
+
+	var tests = map[string]struct {
+		registration   maintainerRegistration
+		config         Config
+		launchAll      bool
+		expectedLaunch bool
+	}{
+		"enabled maintainer": {
+			registration:   registration,
+			config:         Config{Spv: true},
+			expectedLaunch: true,
+		},
+		"disabled maintainer": {
+			registration:   registration,
+			config:         Config{BitcoinDifficulty: true},
+			expectedLaunch: false,
+		},
+		"maintainer launched by default": {
+			registration:   registration,
+			launchAll:      true,
+			expectedLaunch: true,
+		},
+		"enabled explicit-only maintainer": {
+			registration:   explicitOnlyRegistration,
+			config:         Config{Spv: true},
+			expectedLaunch: true,
+		},
+		"explicit-only maintainer not launched by default": {
+			registration:   explicitOnlyRegistration,
+			launchAll:      true,
+			expectedLaunch: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			launch := test.registration.shouldLaunch(test.config, test.launchAll)
+			if launch != test.expectedLaunch {
+				t.Errorf(
+					"unexpected launch decision\nexpected: [%v]\nactual:   [%v]",
+					test.expectedLaunch,
+					launch,
+				)
+			}
+		})
+	}
+}
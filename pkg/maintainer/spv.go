@@ -0,0 +1,635 @@
+package maintainer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ipfs/go-log"
+	"go.uber.org/zap"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+const (
+	// Default value for back-off time which should be applied when the SPV
+	// maintainer is restarted. It helps to avoid being flooded with error
+	// logs in case of a permanent error in the SPV maintainer.
+	spvDefaultRestartBackoffTime = 120 * time.Second
+
+	// Default value for back-off time which should be applied between
+	// consecutive rounds of looking for unproven wallet transactions.
+	spvDefaultIdleBackOffTime = 10 * time.Minute
+
+	// Default value for the depth of the host chain history, in blocks,
+	// used to determine wallets whose transactions should be proven. Wallets
+	// which received deposit reveals or redemption requests within that
+	// period are watched by the SPV maintainer. The value of 50400 blocks
+	// is roughly one week assuming 12 seconds per block.
+	spvDefaultHistoryDepth = 50400
+
+	// Default value for the number of the latest Bitcoin transactions of
+	// a wallet inspected while looking for an unproven transaction.
+	spvDefaultTransactionLimit = 20
+
+	// Number of attempts to read the latest block height and the number of
+	// transaction confirmations consistently, i.e. without a new block being
+	// mined in between.
+	spvConsistentReadAttempts = 3
+)
+
+var spvLogger = log.Logger("maintainer-spv")
+
+// spvProofType represents the type of Bitcoin transaction whose SPV proof
+// is submitted to the Bridge.
+type spvProofType int
+
+const (
+	depositSweepProof spvProofType = iota
+	redemptionProof
+	movingFundsProof
+)
+
+func (spt spvProofType) String() string {
+	switch spt {
+	case depositSweepProof:
+		return "deposit sweep"
+	case redemptionProof:
+		return "redemption"
+	case movingFundsProof:
+		return "moving funds"
+	default:
+		return "unknown"
+	}
+}
+
+// unprovenTransaction represents a wallet transaction whose SPV proof
+// was not submitted to the Bridge yet.
+type unprovenTransaction struct {
+	proofType   spvProofType
+	transaction *bitcoin.Transaction
+	// mainUtxo is the wallet's main UTXO spent by the transaction. It can be
+	// nil only for deposit sweep transactions of wallets that did not have
+	// a main UTXO.
+	mainUtxo *bitcoin.UnspentTransactionOutput
+	// vault is the vault the swept deposits were revealed to. It is set only
+	// for deposit sweep transactions.
+	vault *chain.Address
+}
+
+func newSpvMaintainer(
+	btcChain bitcoin.Chain,
+	chain SpvChain,
+	idleBackOffTime time.Duration,
+) *spvMaintainer {
+	return &spvMaintainer{
+		btcChain:         btcChain,
+		chain:            chain,
+		idleBackOffTime:  idleBackOffTime,
+		historyDepth:     spvDefaultHistoryDepth,
+		transactionLimit: spvDefaultTransactionLimit,
+	}
+}
+
+// spvMaintainer is the part of maintainer responsible for submitting SPV
+// proofs of Bitcoin transactions performed by wallets, i.e. deposit sweep,
+// redemption and moving funds transactions, to the Bridge.
+type spvMaintainer struct {
+	btcChain bitcoin.Chain
+	chain    SpvChain
+
+	idleBackOffTime time.Duration
+
+	historyDepth     uint64
+	transactionLimit int
+}
+
+// Name returns the name of the SPV maintainer.
+func (sm *spvMaintainer) Name() string {
+	return "spv"
+}
+
+// Start proves wallet transactions until the given context is done or
+// an error occurs.
+func (sm *spvMaintainer) Start(ctx context.Context) error {
+	return sm.proveTransactions(ctx)
+}
+
+// proveTransactions periodically looks for unproven wallet transactions and
+// submits their SPV proofs to the Bridge.
+func (sm *spvMaintainer) proveTransactions(ctx context.Context) error {
+	for {
+		if err := sm.proveWalletsTransactions(); err != nil {
+			return err
+		}
+
+		select {
+		case <-time.After(sm.idleBackOffTime):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// proveWalletsTransactions submits SPV proofs of unproven transactions of
+// all wallets watched by the SPV maintainer. A failure related to a single
+// wallet does not stop proving transactions of other wallets.
+func (sm *spvMaintainer) proveWalletsTransactions() error {
+	requiredConfirmations, err := sm.chain.TxProofDifficultyFactor()
+	if err != nil {
+		return fmt.Errorf(
+			"cannot get transaction proof difficulty factor: [%w]",
+			err,
+		)
+	}
+
+	walletPublicKeyHashes, err := sm.getWatchedWallets()
+	if err != nil {
+		return fmt.Errorf("cannot get watched wallets: [%w]", err)
+	}
+
+	for _, walletPublicKeyHash := range walletPublicKeyHashes {
+		err := sm.proveWalletTransaction(
+			walletPublicKeyHash,
+			uint(requiredConfirmations.Uint64()),
+		)
+		if err != nil {
+			spvLogger.Errorf(
+				"cannot prove transaction of wallet [0x%x]: [%v]",
+				walletPublicKeyHash,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// getWatchedWallets returns public key hashes of wallets which received
+// deposit reveals or redemption requests, or committed to moving funds
+// within the history depth. Wallets committed to moving funds are watched
+// as their moving funds transactions must be proven as well.
+func (sm *spvMaintainer) getWatchedWallets() ([][20]byte, error) {
+	blockCounter, err := sm.chain.BlockCounter()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get block counter: [%w]", err)
+	}
+
+	currentBlock, err := blockCounter.CurrentBlock()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get current block: [%w]", err)
+	}
+
+	startBlock := uint64(0)
+	if currentBlock > sm.historyDepth {
+		startBlock = currentBlock - sm.historyDepth
+	}
+
+	depositRevealedEvents, err := sm.chain.PastDepositRevealedEvents(
+		&tbtc.DepositRevealedEventFilter{StartBlock: startBlock},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get past deposit revealed events: [%w]",
+			err,
+		)
+	}
+
+	redemptionRequestedEvents, err := sm.chain.PastRedemptionRequestedEvents(
+		&tbtc.RedemptionRequestedEventFilter{StartBlock: startBlock},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get past redemption requested events: [%w]",
+			err,
+		)
+	}
+
+	movingFundsCommitmentSubmittedEvents, err :=
+		sm.chain.PastMovingFundsCommitmentSubmittedEvents(
+			&tbtc.MovingFundsCommitmentSubmittedEventFilter{
+				StartBlock: startBlock,
+			},
+		)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get past moving funds commitment submitted events: [%w]",
+			err,
+		)
+	}
+
+	walletPublicKeyHashes := make([][20]byte, 0)
+	seen := make(map[[20]byte]bool)
+	addWallet := func(walletPublicKeyHash [20]byte) {
+		if !seen[walletPublicKeyHash] {
+			seen[walletPublicKeyHash] = true
+			walletPublicKeyHashes = append(
+				walletPublicKeyHashes,
+				walletPublicKeyHash,
+			)
+		}
+	}
+
+	for _, event := range depositRevealedEvents {
+		addWallet(event.WalletPublicKeyHash)
+	}
+	for _, event := range redemptionRequestedEvents {
+		addWallet(event.WalletPublicKeyHash)
+	}
+	for _, event := range movingFundsCommitmentSubmittedEvents {
+		addWallet(event.WalletPublicKeyHash)
+	}
+
+	return walletPublicKeyHashes, nil
+}
+
+// proveWalletTransaction looks for an unproven transaction of the given
+// wallet and submits its SPV proof to the Bridge once the transaction has
+// the required number of confirmations. At most one transaction is proven
+// as the Bridge accepts only a proof of the transaction spending the
+// wallet's current main UTXO.
+func (sm *spvMaintainer) proveWalletTransaction(
+	walletPublicKeyHash [20]byte,
+	requiredConfirmations uint,
+) error {
+	walletChainData, err := sm.chain.GetWallet(walletPublicKeyHash)
+	if err != nil {
+		return fmt.Errorf("cannot get on-chain data of wallet: [%w]", err)
+	}
+
+	if walletChainData.State != tbtc.StateLive &&
+		walletChainData.State != tbtc.StateMovingFunds {
+		return nil
+	}
+
+	transactions, err := sm.btcChain.GetTransactionsForPublicKeyHash(
+		walletPublicKeyHash,
+		sm.transactionLimit,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot get transactions history of wallet: [%w]",
+			err,
+		)
+	}
+
+	for _, transaction := range transactions {
+		unproven, ok, err := sm.classifyTransaction(
+			walletPublicKeyHash,
+			walletChainData,
+			transaction,
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot classify transaction [%s]: [%w]",
+				transaction.Hash().Hex(bitcoin.ReversedByteOrder),
+				err,
+			)
+		}
+		if !ok {
+			continue
+		}
+
+		return sm.proveTransaction(
+			walletPublicKeyHash,
+			unproven,
+			requiredConfirmations,
+		)
+	}
+
+	return nil
+}
+
+// classifyTransaction determines whether the given transaction of the given
+// wallet is an unproven transaction and determines its proof type. The
+// second return value is false if the transaction does not require a proof.
+func (sm *spvMaintainer) classifyTransaction(
+	walletPublicKeyHash [20]byte,
+	walletChainData *tbtc.WalletChainData,
+	transaction *bitcoin.Transaction,
+) (*unprovenTransaction, bool, error) {
+	var mainUtxo *bitcoin.UnspentTransactionOutput
+	if walletChainData.MainUtxoHash != [32]byte{} {
+		var err error
+		mainUtxo, err = sm.findSpentMainUtxo(walletChainData, transaction)
+		if err != nil {
+			return nil, false, err
+		}
+
+		// The Bridge accepts only proofs of transactions spending the
+		// wallet's current main UTXO, if the wallet has one.
+		if mainUtxo == nil {
+			return nil, false, nil
+		}
+	}
+
+	var vault *chain.Address
+	unsweptDeposits := 0
+
+	for _, input := range transaction.Inputs {
+		if mainUtxo != nil && *input.Outpoint == *mainUtxo.Outpoint {
+			continue
+		}
+
+		depositRequest, found, err := sm.chain.GetDepositRequest(
+			input.Outpoint.TransactionHash,
+			input.Outpoint.OutputIndex,
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf(
+				"cannot get deposit request: [%w]",
+				err,
+			)
+		}
+
+		if found && depositRequest.SweptAt == 0 {
+			unsweptDeposits++
+			vault = depositRequest.Vault
+		}
+	}
+
+	if unsweptDeposits > 0 {
+		return &unprovenTransaction{
+			proofType:   depositSweepProof,
+			transaction: transaction,
+			mainUtxo:    mainUtxo,
+			vault:       vault,
+		}, true, nil
+	}
+
+	if mainUtxo == nil {
+		return nil, false, nil
+	}
+
+	// A wallet in the MovingFunds state can still perform redemptions so,
+	// the state alone is not enough to tell the proof type. A moving funds
+	// transaction must pay exactly to the target wallets the wallet
+	// committed to.
+	proofType := redemptionProof
+	if walletChainData.State == tbtc.StateMovingFunds &&
+		walletChainData.MovingFundsTargetWalletsCommitmentHash != [32]byte{} {
+		targetWallets, ok := outputsPublicKeyHashes(transaction)
+		if ok && sm.chain.ComputeMovingFundsTargetWalletsCommitmentHash(
+			targetWallets,
+		) == walletChainData.MovingFundsTargetWalletsCommitmentHash {
+			proofType = movingFundsProof
+		}
+	}
+
+	return &unprovenTransaction{
+		proofType:   proofType,
+		transaction: transaction,
+		mainUtxo:    mainUtxo,
+	}, true, nil
+}
+
+// findSpentMainUtxo returns the wallet's main UTXO if it is spent by the given
+// transaction or nil otherwise.
+func (sm *spvMaintainer) findSpentMainUtxo(
+	walletChainData *tbtc.WalletChainData,
+	transaction *bitcoin.Transaction,
+) (*bitcoin.UnspentTransactionOutput, error) {
+	for _, input := range transaction.Inputs {
+		previousTransaction, err := sm.btcChain.GetTransaction(
+			input.Outpoint.TransactionHash,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get previous transaction [%s]: [%w]",
+				input.Outpoint.TransactionHash.Hex(bitcoin.ReversedByteOrder),
+				err,
+			)
+		}
+
+		outputIndex := input.Outpoint.OutputIndex
+		if int(outputIndex) >= len(previousTransaction.Outputs) {
+			return nil, fmt.Errorf(
+				"previous transaction [%s] does not have output [%v]",
+				input.Outpoint.TransactionHash.Hex(bitcoin.ReversedByteOrder),
+				outputIndex,
+			)
+		}
+
+		utxo := &bitcoin.UnspentTransactionOutput{
+			Outpoint: input.Outpoint,
+			Value:    previousTransaction.Outputs[outputIndex].Value,
+		}
+
+		if sm.chain.ComputeMainUtxoHash(utxo) == walletChainData.MainUtxoHash {
+			return utxo, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// proveTransaction assembles the SPV proof of the given unproven transaction
+// and submits it to the Bridge. If the transaction does not have the required
+// number of confirmations yet, this function does nothing.
+func (sm *spvMaintainer) proveTransaction(
+	walletPublicKeyHash [20]byte,
+	unproven *unprovenTransaction,
+	requiredConfirmations uint,
+) error {
+	transactionHash := unproven.transaction.Hash()
+
+	transactionLogger := spvLogger.With(
+		zap.String("wallet", fmt.Sprintf("0x%x", walletPublicKeyHash)),
+		zap.String(
+			"transaction",
+			transactionHash.Hex(bitcoin.ReversedByteOrder),
+		),
+		zap.String("type", unproven.proofType.String()),
+	)
+
+	confirmations, err := sm.btcChain.GetTransactionConfirmations(
+		transactionHash,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot get transaction confirmations: [%w]", err)
+	}
+
+	if confirmations < requiredConfirmations {
+		transactionLogger.Infof(
+			"transaction has [%v] of [%v] required confirmations; "+
+				"postponing proof submission",
+			confirmations,
+			requiredConfirmations,
+		)
+		return nil
+	}
+
+	transaction, proof, err := assembleSpvProof(
+		transactionHash,
+		requiredConfirmations,
+		sm.btcChain,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot assemble SPV proof: [%w]", err)
+	}
+
+	switch unproven.proofType {
+	case depositSweepProof:
+		err = sm.chain.SubmitDepositSweepProof(
+			transaction,
+			proof,
+			unproven.mainUtxo,
+			unproven.vault,
+		)
+	case redemptionProof:
+		err = sm.chain.SubmitRedemptionProof(
+			transaction,
+			proof,
+			unproven.mainUtxo,
+			walletPublicKeyHash,
+		)
+	case movingFundsProof:
+		err = sm.chain.SubmitMovingFundsProof(
+			transaction,
+			proof,
+			unproven.mainUtxo,
+			walletPublicKeyHash,
+		)
+	default:
+		err = fmt.Errorf("unknown proof type [%v]", unproven.proofType)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot submit SPV proof: [%w]", err)
+	}
+
+	transactionLogger.Infof("submitted SPV proof of the transaction")
+
+	return nil
+}
+
+// assembleSpvProof assembles the SPV proof of the transaction with the given
+// hash. The proof contains the given number of block headers, starting from
+// the block containing the transaction.
+func assembleSpvProof(
+	transactionHash bitcoin.Hash,
+	requiredConfirmations uint,
+	btcChain bitcoin.Chain,
+) (*bitcoin.Transaction, *bitcoin.SpvProof, error) {
+	transaction, err := btcChain.GetTransaction(transactionHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get transaction: [%w]", err)
+	}
+
+	latestBlockHeight, confirmations, err := getConfirmationsAtHeight(
+		transactionHash,
+		btcChain,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if confirmations < requiredConfirmations {
+		return nil, nil, fmt.Errorf(
+			"transaction has [%v] of [%v] required confirmations",
+			confirmations,
+			requiredConfirmations,
+		)
+	}
+
+	transactionBlockHeight := latestBlockHeight - confirmations + 1
+
+	merkleProof, err := btcChain.GetTransactionMerkle(
+		transactionHash,
+		transactionBlockHeight,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"cannot get transaction merkle proof: [%w]",
+			err,
+		)
+	}
+
+	blockHeaders, err := btcChain.GetBlockHeaders(
+		transactionBlockHeight,
+		requiredConfirmations,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get block headers: [%w]", err)
+	}
+
+	return transaction, bitcoin.NewSpvProof(merkleProof, blockHeaders), nil
+}
+
+// getConfirmationsAtHeight returns the latest block height along with the
+// number of confirmations of the given transaction. Both values are read
+// separately so, a new block can be mined in between. The latest block height
+// is read again after reading the confirmations and the reads are retried
+// until both height reads agree, so the returned values are consistent.
+func getConfirmationsAtHeight(
+	transactionHash bitcoin.Hash,
+	btcChain bitcoin.Chain,
+) (uint, uint, error) {
+	latestBlockHeight, err := btcChain.GetLatestBlockHeight()
+	if err != nil {
+		return 0, 0, fmt.Errorf(
+			"cannot get latest block height: [%w]",
+			err,
+		)
+	}
+
+	for attempt := 1; attempt <= spvConsistentReadAttempts; attempt++ {
+		confirmations, err := btcChain.GetTransactionConfirmations(
+			transactionHash,
+		)
+		if err != nil {
+			return 0, 0, fmt.Errorf(
+				"cannot get transaction confirmations: [%w]",
+				err,
+			)
+		}
+
+		confirmedBlockHeight, err := btcChain.GetLatestBlockHeight()
+		if err != nil {
+			return 0, 0, fmt.Errorf(
+				"cannot get latest block height: [%w]",
+				err,
+			)
+		}
+
+		if confirmedBlockHeight == latestBlockHeight {
+			return latestBlockHeight, confirmations, nil
+		}
+
+		latestBlockHeight = confirmedBlockHeight
+	}
+
+	return 0, 0, fmt.Errorf(
+		"latest block height kept changing during [%v] attempts",
+		spvConsistentReadAttempts,
+	)
+}
+
+// outputsPublicKeyHashes returns the public key hashes the outputs of the
+// given transaction are locked with, in the order of outputs. The second
+// return value is false if any output is not a P2PKH or P2WPKH output.
+func outputsPublicKeyHashes(transaction *bitcoin.Transaction) ([][20]byte, bool) {
+	publicKeyHashes := make([][20]byte, 0, len(transaction.Outputs))
+
+	for _, output := range transaction.Outputs {
+		script := output.PublicKeyScript
+
+		var publicKeyHash [20]byte
+		switch {
+		// P2WPKH: OP_0 <20-byte hash>
+		case len(script) == 22 && script[0] == 0x00 && script[1] == 0x14:
+			copy(publicKeyHash[:], script[2:22])
+		// P2PKH: OP_DUP OP_HASH160 <20-byte hash> OP_EQUALVERIFY OP_CHECKSIG
+		case len(script) == 25 && script[0] == 0x76 && script[1] == 0xa9 &&
+			script[2] == 0x14 && script[23] == 0x88 && script[24] == 0xac:
+			copy(publicKeyHash[:], script[3:23])
+		default:
+			return nil, false
+		}
+
+		publicKeyHashes = append(publicKeyHashes, publicKeyHash)
+	}
+
+	return publicKeyHashes, true
+}
@@ -0,0 +1,522 @@
+package maintainer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/tbtc"
+)
+
+func TestAssembleSpvProof(t *testing.T) {
+	btcChain := connectLocalBitcoinChain()
+
+	blockHeaders := make(map[uint]*bitcoin.BlockHeader)
+	for height := uint(100); height <= 105; height++ {
+		blockHeaders[height] = &bitcoin.BlockHeader{
+			Version: 1,
+			Time:    uint32(height),
+			Nonce:   uint32(height),
+		}
+	}
+	btcChain.SetBlockHeaders(blockHeaders)
+
+	transaction := newTestTransaction(
+		[]*bitcoin.TransactionOutpoint{{TransactionHash: bitcoin.Hash{0x01}}},
+		[]*bitcoin.TransactionOutput{{Value: 1000, PublicKeyScript: []byte{0x00}}},
+	)
+	// The latest block is 105 so, the transaction is included in block 102.
+	btcChain.AddTransaction(transaction, 4)
+
+	merkleProof := &bitcoin.TransactionMerkleProof{
+		BlockHeight: 102,
+		MerkleNodes: []bitcoin.Hash{{0xaa}, {0xbb}},
+		Position:    7,
+	}
+	btcChain.SetTransactionMerkleProof(transaction.Hash(), merkleProof)
+
+	var tests = map[string]struct {
+		requiredConfirmations uint
+		expectedProof         *bitcoin.SpvProof
+		expectedError         error
+	}{
+		"enough confirmations": {
+			requiredConfirmations: 3,
+			expectedProof: bitcoin.NewSpvProof(
+				merkleProof,
+				[]*bitcoin.BlockHeader{
+					blockHeaders[102],
+					blockHeaders[103],
+					blockHeaders[104],
+				},
+			),
+		},
+		"not enough confirmations": {
+			requiredConfirmations: 5,
+			expectedError: fmt.Errorf(
+				"transaction has [4] of [5] required confirmations",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			actualTransaction, proof, err := assembleSpvProof(
+				transaction.Hash(),
+				test.requiredConfirmations,
+				btcChain,
+			)
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Fatalf(
+					"unexpected error\nexpected: %v\nactual:   %v\n",
+					test.expectedError,
+					err,
+				)
+			}
+			if test.expectedError != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(transaction, actualTransaction) {
+				t.Errorf("unexpected transaction")
+			}
+
+			if !reflect.DeepEqual(test.expectedProof, proof) {
+				t.Errorf(
+					"unexpected proof\nexpected: %v\nactual:   %v\n",
+					test.expectedProof,
+					proof,
+				)
+			}
+		})
+	}
+}
+
+// blockMiningBitcoinChain is a local Bitcoin chain whose latest block height
+// is taken from the given sequence on every read, simulating blocks being
+// mined between consecutive reads.
+type blockMiningBitcoinChain struct {
+	*localBitcoinChain
+
+	latestBlockHeights []uint
+}
+
+func (bmbc *blockMiningBitcoinChain) GetLatestBlockHeight() (uint, error) {
+	latestBlockHeight := bmbc.latestBlockHeights[0]
+	if len(bmbc.latestBlockHeights) > 1 {
+		bmbc.latestBlockHeights = bmbc.latestBlockHeights[1:]
+	}
+
+	return latestBlockHeight, nil
+}
+
+func TestAssembleSpvProof_BlockMined(t *testing.T) {
+	localChain := connectLocalBitcoinChain()
+
+	blockHeaders := make(map[uint]*bitcoin.BlockHeader)
+	for height := uint(100); height <= 106; height++ {
+		blockHeaders[height] = &bitcoin.BlockHeader{Nonce: uint32(height)}
+	}
+	localChain.SetBlockHeaders(blockHeaders)
+
+	transaction := newTestTransaction(
+		[]*bitcoin.TransactionOutpoint{{TransactionHash: bitcoin.Hash{0x01}}},
+		[]*bitcoin.TransactionOutput{{Value: 1000, PublicKeyScript: []byte{0x00}}},
+	)
+	// The transaction is included in block 102 and has 4 confirmations once
+	// the block 105 is mined.
+	localChain.AddTransaction(transaction, 4)
+
+	merkleProof := &bitcoin.TransactionMerkleProof{
+		BlockHeight: 102,
+		MerkleNodes: []bitcoin.Hash{{0xaa}},
+		Position:    1,
+	}
+	localChain.SetTransactionMerkleProof(transaction.Hash(), merkleProof)
+
+	var tests = map[string]struct {
+		latestBlockHeights []uint
+		expectedProof      *bitcoin.SpvProof
+		expectedError      error
+	}{
+		"latest block height changed between reads": {
+			latestBlockHeights: []uint{106, 105},
+			expectedProof: bitcoin.NewSpvProof(
+				merkleProof,
+				[]*bitcoin.BlockHeader{
+					blockHeaders[102],
+					blockHeaders[103],
+					blockHeaders[104],
+				},
+			),
+		},
+		"latest block height keeps changing": {
+			latestBlockHeights: []uint{104, 105, 106, 107},
+			expectedError: fmt.Errorf(
+				"latest block height kept changing during [3] attempts",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			btcChain := &blockMiningBitcoinChain{
+				localBitcoinChain:  localChain,
+				latestBlockHeights: test.latestBlockHeights,
+			}
+
+			_, proof, err := assembleSpvProof(transaction.Hash(), 3, btcChain)
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Fatalf(
+					"unexpected error\nexpected: %v\nactual:   %v\n",
+					test.expectedError,
+					err,
+				)
+			}
+			if test.expectedError != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(test.expectedProof, proof) {
+				t.Errorf(
+					"unexpected proof\nexpected: %v\nactual:   %v\n",
+					test.expectedProof,
+					proof,
+				)
+			}
+		})
+	}
+}
+
+func TestSpvMaintainer_ProveWalletTransaction(t *testing.T) {
+	walletPublicKeyHash := [20]byte{0x11}
+	walletScript, err := bitcoin.PayToWitnessPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherScript, err := bitcoin.PayToWitnessPublicKeyHash([20]byte{0x22})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vault := chain.Address("0x0102030405060708091011121314151617181920")
+
+	requiredConfirmations := uint(6)
+
+	// The transaction funding the wallet from an external source.
+	fundingTransaction := newTestTransaction(
+		[]*bitcoin.TransactionOutpoint{{TransactionHash: bitcoin.Hash{0x01}}},
+		[]*bitcoin.TransactionOutput{{Value: 100000, PublicKeyScript: otherScript}},
+	)
+
+	// The transaction holding the wallet's main UTXO being already proven.
+	mainUtxoTransaction := newTestTransaction(
+		[]*bitcoin.TransactionOutpoint{{TransactionHash: fundingTransaction.Hash()}},
+		[]*bitcoin.TransactionOutput{{Value: 100000, PublicKeyScript: walletScript}},
+	)
+	mainUtxo := &bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{
+			TransactionHash: mainUtxoTransaction.Hash(),
+			OutputIndex:     0,
+		},
+		Value: 100000,
+	}
+
+	// The deposit funding transaction.
+	depositTransaction := newTestTransaction(
+		[]*bitcoin.TransactionOutpoint{{TransactionHash: bitcoin.Hash{0x02}}},
+		[]*bitcoin.TransactionOutput{{Value: 50000, PublicKeyScript: []byte{0x00}}},
+	)
+	depositOutpoint := &bitcoin.TransactionOutpoint{
+		TransactionHash: depositTransaction.Hash(),
+		OutputIndex:     0,
+	}
+
+	var tests = map[string]struct {
+		walletState                tbtc.WalletState
+		hasMainUtxo                bool
+		movingFundsTargetWallets   [][20]byte
+		transactionInputs          []*bitcoin.TransactionOutpoint
+		transactionOutputScript    []byte
+		transactionConfirmations   uint
+		expectedDepositSweepProofs int
+		expectedRedemptionProofs   int
+		expectedMovingFundsProofs  int
+		expectedMainUtxo           *bitcoin.UnspentTransactionOutput
+		expectedDepositSweepVault  *chain.Address
+	}{
+		"deposit sweep of wallet without main UTXO": {
+			walletState:                tbtc.StateLive,
+			hasMainUtxo:                false,
+			transactionInputs:          []*bitcoin.TransactionOutpoint{depositOutpoint},
+			transactionOutputScript:    walletScript,
+			transactionConfirmations:   requiredConfirmations,
+			expectedDepositSweepProofs: 1,
+			expectedMainUtxo:           nil,
+			expectedDepositSweepVault:  &vault,
+		},
+		"deposit sweep of wallet with main UTXO": {
+			walletState: tbtc.StateLive,
+			hasMainUtxo: true,
+			transactionInputs: []*bitcoin.TransactionOutpoint{
+				mainUtxo.Outpoint,
+				depositOutpoint,
+			},
+			transactionOutputScript:    walletScript,
+			transactionConfirmations:   requiredConfirmations,
+			expectedDepositSweepProofs: 1,
+			expectedMainUtxo:           mainUtxo,
+			expectedDepositSweepVault:  &vault,
+		},
+		"redemption": {
+			walletState:              tbtc.StateLive,
+			hasMainUtxo:              true,
+			transactionInputs:        []*bitcoin.TransactionOutpoint{mainUtxo.Outpoint},
+			transactionOutputScript:  walletScript,
+			transactionConfirmations: requiredConfirmations,
+			expectedRedemptionProofs: 1,
+			expectedMainUtxo:         mainUtxo,
+		},
+		"moving funds": {
+			walletState:               tbtc.StateMovingFunds,
+			hasMainUtxo:               true,
+			movingFundsTargetWallets:  [][20]byte{{0x22}},
+			transactionInputs:         []*bitcoin.TransactionOutpoint{mainUtxo.Outpoint},
+			transactionOutputScript:   otherScript,
+			transactionConfirmations:  requiredConfirmations,
+			expectedMovingFundsProofs: 1,
+			expectedMainUtxo:          mainUtxo,
+		},
+		"redemption without change of moving funds wallet": {
+			walletState:              tbtc.StateMovingFunds,
+			hasMainUtxo:              true,
+			movingFundsTargetWallets: [][20]byte{{0x33}},
+			transactionInputs:        []*bitcoin.TransactionOutpoint{mainUtxo.Outpoint},
+			transactionOutputScript:  otherScript,
+			transactionConfirmations: requiredConfirmations,
+			expectedRedemptionProofs: 1,
+			expectedMainUtxo:         mainUtxo,
+		},
+		"transaction not spending main UTXO": {
+			walletState:              tbtc.StateLive,
+			hasMainUtxo:              true,
+			transactionInputs:        []*bitcoin.TransactionOutpoint{depositOutpoint},
+			transactionOutputScript:  walletScript,
+			transactionConfirmations: requiredConfirmations,
+		},
+		"not enough confirmations": {
+			walletState:              tbtc.StateLive,
+			hasMainUtxo:              true,
+			transactionInputs:        []*bitcoin.TransactionOutpoint{mainUtxo.Outpoint},
+			transactionOutputScript:  walletScript,
+			transactionConfirmations: requiredConfirmations - 1,
+		},
+		"closed wallet": {
+			walletState:              tbtc.StateClosed,
+			hasMainUtxo:              true,
+			transactionInputs:        []*bitcoin.TransactionOutpoint{mainUtxo.Outpoint},
+			transactionOutputScript:  walletScript,
+			transactionConfirmations: requiredConfirmations,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			btcChain := connectLocalBitcoinChain()
+			spvChain := connectLocalSpvChain(int64(requiredConfirmations))
+
+			btcChain.AddTransaction(fundingTransaction, 150)
+			btcChain.AddTransaction(mainUtxoTransaction, 100)
+			btcChain.AddTransaction(depositTransaction, 50)
+
+			transaction := newTestTransaction(
+				test.transactionInputs,
+				[]*bitcoin.TransactionOutput{{
+					Value:           140000,
+					PublicKeyScript: test.transactionOutputScript,
+				}},
+			)
+			btcChain.AddTransaction(transaction, test.transactionConfirmations)
+
+			// The latest block is 200 so, the transaction's block is
+			// determined by its number of confirmations.
+			transactionBlockHeight := 200 - test.transactionConfirmations + 1
+			blockHeaders := make(map[uint]*bitcoin.BlockHeader)
+			for height := transactionBlockHeight; height <= 200; height++ {
+				blockHeaders[height] = &bitcoin.BlockHeader{Nonce: uint32(height)}
+			}
+			btcChain.SetBlockHeaders(blockHeaders)
+			btcChain.SetTransactionMerkleProof(
+				transaction.Hash(),
+				&bitcoin.TransactionMerkleProof{
+					BlockHeight: transactionBlockHeight,
+					MerkleNodes: []bitcoin.Hash{{0xaa}},
+					Position:    1,
+				},
+			)
+
+			walletChainData := &tbtc.WalletChainData{
+				State: test.walletState,
+			}
+			if test.movingFundsTargetWallets != nil {
+				walletChainData.MovingFundsTargetWalletsCommitmentHash =
+					spvChain.ComputeMovingFundsTargetWalletsCommitmentHash(
+						test.movingFundsTargetWallets,
+					)
+			}
+			if test.hasMainUtxo {
+				walletChainData.MainUtxoHash = spvChain.ComputeMainUtxoHash(mainUtxo)
+			}
+			spvChain.SetWallet(walletPublicKeyHash, walletChainData)
+
+			spvChain.SetDepositRequest(
+				depositOutpoint.TransactionHash,
+				depositOutpoint.OutputIndex,
+				&tbtc.DepositChainRequest{
+					RevealedAt: 1,
+					Vault:      &vault,
+				},
+			)
+
+			spvMaintainer := &spvMaintainer{
+				btcChain:         btcChain,
+				chain:            spvChain,
+				transactionLimit: spvDefaultTransactionLimit,
+			}
+
+			err := spvMaintainer.proveWalletTransaction(
+				walletPublicKeyHash,
+				requiredConfirmations,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"deposit sweep proofs",
+				test.expectedDepositSweepProofs,
+				len(spvChain.depositSweepProofs),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"redemption proofs",
+				test.expectedRedemptionProofs,
+				len(spvChain.redemptionProofs),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"moving funds proofs",
+				test.expectedMovingFundsProofs,
+				len(spvChain.movingFundsProofs),
+			)
+
+			submittedProofs := append(
+				append(
+					spvChain.depositSweepProofs,
+					spvChain.redemptionProofs...,
+				),
+				spvChain.movingFundsProofs...,
+			)
+			for _, submittedProof := range submittedProofs {
+				if !reflect.DeepEqual(transaction, submittedProof.transaction) {
+					t.Errorf("unexpected proven transaction")
+				}
+				if !reflect.DeepEqual(
+					test.expectedMainUtxo,
+					submittedProof.mainUtxo,
+				) {
+					t.Errorf(
+						"unexpected main UTXO\nexpected: %v\nactual:   %v\n",
+						test.expectedMainUtxo,
+						submittedProof.mainUtxo,
+					)
+				}
+				if !reflect.DeepEqual(
+					test.expectedDepositSweepVault,
+					submittedProof.vault,
+				) {
+					t.Errorf(
+						"unexpected vault\nexpected: %v\nactual:   %v\n",
+						test.expectedDepositSweepVault,
+						submittedProof.vault,
+					)
+				}
+				testutils.AssertIntsEqual(
+					t,
+					"headers length",
+					int(requiredConfirmations)*bitcoin.BlockHeaderByteLength,
+					len(submittedProof.proof.BitcoinHeaders),
+				)
+			}
+		})
+	}
+}
+
+func TestSpvMaintainer_GetWatchedWallets(t *testing.T) {
+	depositWallet := [20]byte{0x01}
+	redemptionWallet := [20]byte{0x02}
+	movingFundsWallet := [20]byte{0x03}
+
+	spvChain := connectLocalSpvChain(6)
+	spvChain.depositRevealedEvents = []*tbtc.DepositRevealedEvent{
+		{WalletPublicKeyHash: depositWallet},
+		{WalletPublicKeyHash: redemptionWallet},
+	}
+	spvChain.redemptionRequestedEvents = []*tbtc.RedemptionRequestedEvent{
+		{WalletPublicKeyHash: redemptionWallet},
+	}
+	spvChain.movingFundsCommitmentSubmittedEvents =
+		[]*tbtc.MovingFundsCommitmentSubmittedEvent{
+			{WalletPublicKeyHash: movingFundsWallet},
+			{WalletPublicKeyHash: depositWallet},
+		}
+
+	spvMaintainer := &spvMaintainer{
+		chain:        spvChain,
+		historyDepth: spvDefaultHistoryDepth,
+	}
+
+	walletPublicKeyHashes, err := spvMaintainer.getWatchedWallets()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedWalletPublicKeyHashes := [][20]byte{
+		depositWallet,
+		redemptionWallet,
+		movingFundsWallet,
+	}
+	if !reflect.DeepEqual(
+		expectedWalletPublicKeyHashes,
+		walletPublicKeyHashes,
+	) {
+		t.Errorf(
+			"unexpected wallets\nexpected: [%v]\nactual:   [%v]",
+			expectedWalletPublicKeyHashes,
+			walletPublicKeyHashes,
+		)
+	}
+}
+
+func newTestTransaction(
+	outpoints []*bitcoin.TransactionOutpoint,
+	outputs []*bitcoin.TransactionOutput,
+) *bitcoin.Transaction {
+	inputs := make([]*bitcoin.TransactionInput, len(outpoints))
+	for i, outpoint := range outpoints {
+		inputs[i] = &bitcoin.TransactionInput{
+			Outpoint: outpoint,
+			Sequence: 0xffffffff,
+		}
+	}
+
+	return &bitcoin.Transaction{
+		Version: 1,
+		Inputs:  inputs,
+		Outputs: outputs,
+	}
+}
@@ -0,0 +1,313 @@
+package maintainer
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-core/pkg/clientinfo"
+)
+
+const (
+	// Default upper bound of the back-off time applied before restarting
+	// a maintainer. The back-off time doubles after each consecutive failure
+	// but never exceeds this value.
+	defaultMaxRestartBackOffTime = 30 * time.Minute
+
+	// Default duration of a maintainer run after which the maintainer is
+	// considered healthy. A failure occurring after a healthy run resets the
+	// back-off time to the initial value of the maintainer.
+	defaultHealthyRunTime = 10 * time.Minute
+)
+
+var supervisorLogger = log.Logger("maintainer-supervisor")
+
+// Maintainer represents a single maintainer task run under the supervisor.
+type Maintainer interface {
+	// Name returns the name of the maintainer. The name must be unique
+	// among maintainers run by the same supervisor and is used to label
+	// logs and metrics.
+	Name() string
+	// Start runs the maintainer's work. The function should block until the
+	// given context is done or an unrecoverable error occurs. In the latter
+	// case, the supervisor restarts the maintainer after a back-off time.
+	Start(ctx context.Context) error
+}
+
+// maintainerStatus holds the runtime status of a supervised maintainer.
+type maintainerStatus struct {
+	mutex sync.RWMutex
+
+	running  bool
+	restarts uint64
+	panics   uint64
+}
+
+// supervisedMaintainer is a maintainer run under the supervisor along with
+// its restart settings and runtime status.
+type supervisedMaintainer struct {
+	maintainer         Maintainer
+	restartBackOffTime time.Duration
+	status             *maintainerStatus
+}
+
+// supervisor runs maintainers in separate goroutines, restarting them with
+// an exponential back-off on errors and recovering from their panics so that
+// a failure of one maintainer does not affect other maintainers. All
+// maintainers share a common context which is cancelled on supervisor stop.
+type supervisor struct {
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+
+	maxRestartBackOffTime time.Duration
+	healthyRunTime        time.Duration
+
+	maintainersMutex sync.Mutex
+	maintainers      map[string]*supervisedMaintainer
+
+	waitGroup sync.WaitGroup
+}
+
+func newSupervisor(ctx context.Context) *supervisor {
+	supervisorCtx, cancelCtx := context.WithCancel(ctx)
+
+	return &supervisor{
+		ctx:                   supervisorCtx,
+		cancelCtx:             cancelCtx,
+		maxRestartBackOffTime: defaultMaxRestartBackOffTime,
+		healthyRunTime:        defaultHealthyRunTime,
+		maintainers:           make(map[string]*supervisedMaintainer),
+	}
+}
+
+// supervise starts the given maintainer under the supervisor. The maintainer
+// is restarted whenever it returns an error or panics. The given back-off
+// time is applied before the first restart and doubles after each consecutive
+// failure, up to the supervisor's limit. Returns an error if a maintainer with
+// the same name is already supervised.
+func (s *supervisor) supervise(
+	maintainer Maintainer,
+	restartBackOffTime time.Duration,
+) error {
+	s.maintainersMutex.Lock()
+	defer s.maintainersMutex.Unlock()
+
+	name := maintainer.Name()
+	if _, exists := s.maintainers[name]; exists {
+		return fmt.Errorf("maintainer [%s] is already supervised", name)
+	}
+
+	supervised := &supervisedMaintainer{
+		maintainer:         maintainer,
+		restartBackOffTime: restartBackOffTime,
+		status:             &maintainerStatus{},
+	}
+	s.maintainers[name] = supervised
+
+	s.waitGroup.Add(1)
+	go func() {
+		defer s.waitGroup.Done()
+		s.runControlLoop(supervised)
+	}()
+
+	return nil
+}
+
+// runControlLoop runs the given maintainer until the supervisor's context is
+// done, restarting the maintainer on errors and panics.
+func (s *supervisor) runControlLoop(supervised *supervisedMaintainer) {
+	name := supervised.maintainer.Name()
+
+	supervisorLogger.Infof("starting maintainer [%s]", name)
+
+	defer func() {
+		supervisorLogger.Infof("stopping maintainer [%s]", name)
+	}()
+
+	var backOffTime time.Duration
+
+	for {
+		startedAt := time.Now()
+
+		supervised.status.setRunning(true)
+		err := runMaintainer(s.ctx, supervised)
+		supervised.status.setRunning(false)
+
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		backOffTime = s.restartBackOffTime(
+			supervised,
+			backOffTime,
+			time.Since(startedAt),
+		)
+
+		supervised.status.recordRestart()
+
+		supervisorLogger.Errorf(
+			"maintainer [%s] failed: [%v]; restarting in [%v]",
+			name,
+			err,
+			backOffTime,
+		)
+
+		select {
+		case <-time.After(backOffTime):
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// restartBackOffTime returns the back-off time that should be applied before
+// restarting the given maintainer. The previousBackOffTime is the back-off
+// time applied before the previous restart or zero if the maintainer has not
+// been restarted yet. The runTime is the duration of the maintainer's last
+// run. The initial back-off time of the maintainer is used for the first
+// restart and after a healthy run. Otherwise, the previous back-off time is
+// doubled but capped at the supervisor's limit.
+func (s *supervisor) restartBackOffTime(
+	supervised *supervisedMaintainer,
+	previousBackOffTime time.Duration,
+	runTime time.Duration,
+) time.Duration {
+	if previousBackOffTime == 0 || runTime >= s.healthyRunTime {
+		return supervised.restartBackOffTime
+	}
+
+	backOffTime := 2 * previousBackOffTime
+	if backOffTime > s.maxRestartBackOffTime {
+		backOffTime = s.maxRestartBackOffTime
+	}
+	// The limit never lowers the maintainer's initial back-off time.
+	if backOffTime < supervised.restartBackOffTime {
+		backOffTime = supervised.restartBackOffTime
+	}
+
+	return backOffTime
+}
+
+// runMaintainer starts the given maintainer and recovers from a possible
+// panic, turning it into an error.
+func runMaintainer(
+	ctx context.Context,
+	supervised *supervisedMaintainer,
+) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			supervised.status.recordPanic()
+
+			supervisorLogger.Errorf(
+				"maintainer [%s] panicked: [%v]\n%s",
+				supervised.maintainer.Name(),
+				recovered,
+				debug.Stack(),
+			)
+
+			err = fmt.Errorf("maintainer panicked: [%v]", recovered)
+		}
+	}()
+
+	err = supervised.maintainer.Start(ctx)
+	if err == nil && ctx.Err() == nil {
+		err = fmt.Errorf("maintainer returned unexpectedly")
+	}
+
+	return err
+}
+
+// stop cancels the context shared by all supervised maintainers and waits
+// until all of them return.
+func (s *supervisor) stop() {
+	s.cancelCtx()
+	s.waitGroup.Wait()
+}
+
+// status returns the status of the supervised maintainer with the given name.
+// The second returned value is false if there is no such maintainer.
+func (s *supervisor) status(name string) (*maintainerStatus, bool) {
+	s.maintainersMutex.Lock()
+	defer s.maintainersMutex.Unlock()
+
+	supervised, ok := s.maintainers[name]
+	if !ok {
+		return nil, false
+	}
+
+	return supervised.status, true
+}
+
+// observeMetrics exposes the status of all supervised maintainers through
+// the given client info registry.
+func (s *supervisor) observeMetrics(clientInfo *clientinfo.Registry) {
+	s.maintainersMutex.Lock()
+	defer s.maintainersMutex.Unlock()
+
+	sources := make(map[string]clientinfo.Source)
+
+	for name, supervised := range s.maintainers {
+		status := supervised.status
+
+		sources[name+"_running"] = func() float64 {
+			if status.isRunning() {
+				return 1
+			}
+			return 0
+		}
+		sources[name+"_restarts_count"] = func() float64 {
+			return float64(status.restartsCount())
+		}
+		sources[name+"_panics_count"] = func() float64 {
+			return float64(status.panicsCount())
+		}
+	}
+
+	clientInfo.ObserveApplicationSource("maintainer", sources)
+}
+
+func (ms *maintainerStatus) setRunning(running bool) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	ms.running = running
+}
+
+func (ms *maintainerStatus) recordRestart() {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	ms.restarts++
+}
+
+func (ms *maintainerStatus) recordPanic() {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	ms.panics++
+}
+
+func (ms *maintainerStatus) isRunning() bool {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	return ms.running
+}
+
+func (ms *maintainerStatus) restartsCount() uint64 {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	return ms.restarts
+}
+
+func (ms *maintainerStatus) panicsCount() uint64 {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	return ms.panics
+}
@@ -0,0 +1,254 @@
+package maintainer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+type testMaintainer struct {
+	name      string
+	startFunc func(ctx context.Context, attempt int) error
+
+	mutex    sync.Mutex
+	attempts int
+}
+
+func (tm *testMaintainer) Name() string {
+	return tm.name
+}
+
+func (tm *testMaintainer) Start(ctx context.Context) error {
+	tm.mutex.Lock()
+	tm.attempts++
+	attempt := tm.attempts
+	tm.mutex.Unlock()
+
+	return tm.startFunc(ctx, attempt)
+}
+
+func (tm *testMaintainer) startAttempts() int {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	return tm.attempts
+}
+
+func runUntilDone(ctx context.Context, attempt int) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestSupervisor_RestartOnErrorAndPanic(t *testing.T) {
+	supervisor := newSupervisor(context.Background())
+	defer supervisor.stop()
+
+	failingMaintainer := &testMaintainer{
+		name: "failing",
+		startFunc: func(ctx context.Context, attempt int) error {
+			switch attempt {
+			case 1:
+				return fmt.Errorf("unexpected error")
+			case 2:
+				panic("unexpected panic")
+			default:
+				return runUntilDone(ctx, attempt)
+			}
+		},
+	}
+
+	healthyMaintainer := &testMaintainer{
+		name:      "healthy",
+		startFunc: runUntilDone,
+	}
+
+	restartBackOffTime := 50 * time.Millisecond
+
+	if err := supervisor.supervise(
+		failingMaintainer,
+		restartBackOffTime,
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := supervisor.supervise(
+		healthyMaintainer,
+		restartBackOffTime,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait long enough for the failing maintainer to be restarted twice.
+	time.Sleep(10 * restartBackOffTime)
+
+	failingStatus, ok := supervisor.status(failingMaintainer.Name())
+	if !ok {
+		t.Fatal("failing maintainer status not found")
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"failing maintainer start attempts",
+		3,
+		failingMaintainer.startAttempts(),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"failing maintainer restarts count",
+		2,
+		int(failingStatus.restartsCount()),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"failing maintainer panics count",
+		1,
+		int(failingStatus.panicsCount()),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"failing maintainer running",
+		true,
+		failingStatus.isRunning(),
+	)
+
+	healthyStatus, ok := supervisor.status(healthyMaintainer.Name())
+	if !ok {
+		t.Fatal("healthy maintainer status not found")
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"healthy maintainer start attempts",
+		1,
+		healthyMaintainer.startAttempts(),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"healthy maintainer restarts count",
+		0,
+		int(healthyStatus.restartsCount()),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"healthy maintainer running",
+		true,
+		healthyStatus.isRunning(),
+	)
+
+	supervisor.stop()
+
+	testutils.AssertBoolsEqual(
+		t,
+		"failing maintainer running",
+		false,
+		failingStatus.isRunning(),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"healthy maintainer running",
+		false,
+		healthyStatus.isRunning(),
+	)
+}
+
+func TestSupervisor_DuplicateName(t *testing.T) {
+	supervisor := newSupervisor(context.Background())
+	defer supervisor.stop()
+
+	maintainer := &testMaintainer{
+		name:      "duplicated",
+		startFunc: runUntilDone,
+	}
+
+	if err := supervisor.supervise(maintainer, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	err := supervisor.supervise(maintainer, time.Second)
+
+	expectedErr := fmt.Errorf("maintainer [duplicated] is already supervised")
+	if !reflect.DeepEqual(expectedErr, err) {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v\n",
+			expectedErr,
+			err,
+		)
+	}
+}
+
+func TestSupervisor_RestartBackOffTime(t *testing.T) {
+	supervisor := newSupervisor(context.Background())
+	defer supervisor.stop()
+
+	supervisor.maxRestartBackOffTime = 8 * time.Minute
+	supervisor.healthyRunTime = 10 * time.Minute
+
+	var tests = map[string]struct {
+		initialBackOffTime  time.Duration
+		previousBackOffTime time.Duration
+		runTime             time.Duration
+		expectedBackOffTime time.Duration
+	}{
+		"first restart": {
+			initialBackOffTime:  time.Minute,
+			previousBackOffTime: 0,
+			runTime:             time.Second,
+			expectedBackOffTime: time.Minute,
+		},
+		"consecutive failure": {
+			initialBackOffTime:  time.Minute,
+			previousBackOffTime: 2 * time.Minute,
+			runTime:             time.Second,
+			expectedBackOffTime: 4 * time.Minute,
+		},
+		"consecutive failure exceeding the limit": {
+			initialBackOffTime:  time.Minute,
+			previousBackOffTime: 6 * time.Minute,
+			runTime:             time.Second,
+			expectedBackOffTime: 8 * time.Minute,
+		},
+		"consecutive failure at the limit": {
+			initialBackOffTime:  time.Minute,
+			previousBackOffTime: 8 * time.Minute,
+			runTime:             time.Second,
+			expectedBackOffTime: 8 * time.Minute,
+		},
+		"failure after a healthy run": {
+			initialBackOffTime:  time.Minute,
+			previousBackOffTime: 8 * time.Minute,
+			runTime:             10 * time.Minute,
+			expectedBackOffTime: time.Minute,
+		},
+		"initial back-off time above the limit": {
+			initialBackOffTime:  10 * time.Minute,
+			previousBackOffTime: 10 * time.Minute,
+			runTime:             time.Second,
+			expectedBackOffTime: 10 * time.Minute,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			supervised := &supervisedMaintainer{
+				restartBackOffTime: test.initialBackOffTime,
+			}
+
+			backOffTime := supervisor.restartBackOffTime(
+				supervised,
+				test.previousBackOffTime,
+				test.runTime,
+			)
+
+			testutils.AssertIntsEqual(
+				t,
+				"restart back-off time",
+				int(test.expectedBackOffTime),
+				int(backOffTime),
+			)
+		})
+	}
+}
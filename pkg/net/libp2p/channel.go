@@ -18,6 +18,7 @@ import (
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/net/gen/pb"
 	"github.com/keep-network/keep-core/pkg/net/internal"
+	"github.com/keep-network/keep-core/pkg/net/reputation"
 	"github.com/keep-network/keep-core/pkg/net/retransmission"
 )
 
@@ -54,6 +55,11 @@ type channel struct {
 
 	name string
 
+	// cancelCtx cancels the context of the channel's message workers.
+	cancelCtx context.CancelFunc
+	// workers tracks the channel's message workers.
+	workers sync.WaitGroup
+
 	clientIdentity *identity
 	peerStore      peerstore.Peerstore
 
@@ -73,6 +79,10 @@ type channel struct {
 	unmarshalersByType map[string]func() net.TaggedUnmarshaler
 
 	retransmissionTicker *retransmission.Ticker
+
+	trafficMetrics *net.TrafficMetrics
+
+	reputation *reputation.Reputation
 }
 
 type messageHandler struct {
@@ -100,10 +110,22 @@ func (c *channel) Send(
 
 	messageProto.SequenceNumber = c.nextSeqno()
 
+	messageSize := proto.Size(messageProto)
+
 	doSend := func() error {
 		return c.publish(messageProto)
 	}
 
+	doRetransmit := func() error {
+		if err := doSend(); err != nil {
+			return err
+		}
+
+		c.trafficMetrics.MessageRetransmitted(c.name, message.Type(), messageSize)
+
+		return nil
+	}
+
 	var strategy net.RetransmissionStrategy
 	switch len(retransmissionStrategy) {
 	case 1:
@@ -116,11 +138,17 @@ func (c *channel) Send(
 		ctx,
 		logger,
 		c.retransmissionTicker,
-		doSend,
+		doRetransmit,
 		retransmission.WithStrategy(strategy),
 	)
 
-	return doSend()
+	if err := doSend(); err != nil {
+		return err
+	}
+
+	c.trafficMetrics.MessageSent(c.name, message.Type(), messageSize)
+
+	return nil
 }
 
 // TODO: The broadcast channel Recv function expects the message handler as
@@ -143,7 +171,11 @@ func (c *channel) Recv(ctx context.Context, handler func(m net.Message)) {
 	c.messageHandlers = append(c.messageHandlers, messageHandler)
 	c.messageHandlersMutex.Unlock()
 
-	handleWithRetransmissions := retransmission.WithRetransmissionSupport(handler)
+	handleWithRetransmissions := retransmission.WithRetransmissionSupport(
+		ctx,
+		c.retransmissionTicker,
+		handler,
+	)
 
 	// A separate goroutine controls the lifecycle of the handler. The message
 	// handler is removed from the channel if the context is done. This logic is
@@ -245,15 +277,45 @@ func (c *channel) publish(message *pb.BroadcastNetworkMessage) error {
 func (c *channel) handleMessages(ctx context.Context) {
 	logger.Debugf("creating [%v] subscription workers", subscriptionWorkers)
 	for i := 0; i < subscriptionWorkers; i++ {
-		go c.subscriptionWorker(ctx)
+		c.workers.Add(1)
+		go func() {
+			defer c.workers.Done()
+			c.subscriptionWorker(ctx)
+		}()
 	}
 
 	logger.Debugf("creating [%v] message workers", messageWorkers)
 	for i := 0; i < messageWorkers; i++ {
-		go c.incomingMessageWorker(ctx)
+		c.workers.Add(1)
+		go func() {
+			defer c.workers.Done()
+			c.incomingMessageWorker(ctx)
+		}()
 	}
 }
 
+// close stops handling messages of the channel, removes the channel filter
+// and removes the channel's traffic metrics. The channel must not be used
+// once it is closed.
+func (c *channel) close() {
+	c.cancelCtx()
+	c.workers.Wait()
+
+	c.validatorMutex.Lock()
+	err := c.validator.UnregisterTopicValidator(c.name)
+	c.validatorMutex.Unlock()
+	if err != nil {
+		// That error occurs when no filter was set for the channel.
+		logger.Debugf(
+			"could not unregister topic validator for channel [%v]: [%v]",
+			c.name,
+			err,
+		)
+	}
+
+	c.trafficMetrics.ChannelClosed(c.name)
+}
+
 func (c *channel) subscriptionWorker(ctx context.Context) {
 	for {
 		select {
@@ -263,7 +325,11 @@ func (c *channel) subscriptionWorker(ctx context.Context) {
 		default:
 			message, err := c.subscription.Next(ctx)
 			if err != nil {
-				logger.Error(err)
+				// The error is expected once the context is done, for
+				// example, when the channel is closed.
+				if ctx.Err() == nil {
+					logger.Error(err)
+				}
 				continue
 			}
 
@@ -292,9 +358,25 @@ func (c *channel) incomingMessageWorker(ctx context.Context) {
 func (c *channel) processPubsubMessage(pubsubMessage *pubsub.Message) error {
 	var messageProto pb.BroadcastNetworkMessage
 	if err := proto.Unmarshal(pubsubMessage.Data, &messageProto); err != nil {
+		c.trafficMetrics.MessageReceived(
+			c.name,
+			net.UnknownMessageType,
+			len(pubsubMessage.Data),
+		)
+		c.trafficMetrics.MessageUnmarshalFailed(c.name, net.UnknownMessageType)
+		c.reportMisbehavior(
+			pubsubMessage.GetFrom(),
+			net.MalformedMessageMisbehavior,
+		)
 		return err
 	}
 
+	c.trafficMetrics.MessageReceived(
+		c.name,
+		c.trafficMessageType(messageProto.Type),
+		len(pubsubMessage.Data),
+	)
+
 	return c.processContainerMessage(pubsubMessage.GetFrom(), &messageProto)
 }
 
@@ -306,16 +388,29 @@ func (c *channel) processContainerMessage(
 	// from our map of unmarshallers.
 	unmarshaled, err := c.getUnmarshalingContainerByType(string(message.Type))
 	if err != nil {
+		// Unmarshalers may not be registered yet or the sender may use
+		// a newer client version so, a message of an unknown type is not
+		// reported as a misbehavior of the sender.
+		c.trafficMetrics.MessageUnmarshalFailed(
+			c.name,
+			c.trafficMessageType(message.Type),
+		)
 		return err
 	}
 
 	if err := unmarshaled.Unmarshal(message.GetPayload()); err != nil {
+		c.trafficMetrics.MessageUnmarshalFailed(
+			c.name,
+			c.trafficMessageType(message.Type),
+		)
+		c.reportMisbehavior(proposedSender, net.MalformedMessageMisbehavior)
 		return err
 	}
 
 	// Construct an identifier from the sender.
 	senderIdentifier := &identity{}
 	if err := senderIdentifier.Unmarshal(message.Sender); err != nil {
+		c.reportMisbehavior(proposedSender, net.MalformedMessageMisbehavior)
 		return err
 	}
 
@@ -323,6 +418,7 @@ func (c *channel) processContainerMessage(
 	//     Test that the proposed sender (outer layer) matches the
 	//     sender identifier we grab from the message (inner layer).
 	if proposedSender != senderIdentifier.id {
+		c.reportMisbehavior(proposedSender, net.MalformedMessageMisbehavior)
 		return fmt.Errorf(
 			"outer layer sender [%v] does not match inner layer sender [%v]",
 			proposedSender,
@@ -354,6 +450,20 @@ func (c *channel) processContainerMessage(
 	return nil
 }
 
+// trafficMessageType returns the type under which the traffic of the received
+// message of the given type is recorded. Types without a registered
+// unmarshaler are recorded as net.UnknownMessageType.
+func (c *channel) trafficMessageType(messageType []byte) string {
+	c.unmarshalersMutex.Lock()
+	defer c.unmarshalersMutex.Unlock()
+
+	if _, found := c.unmarshalersByType[string(messageType)]; !found {
+		return net.UnknownMessageType
+	}
+
+	return string(messageType)
+}
+
 func (c *channel) getUnmarshalingContainerByType(messageType string) (net.TaggedUnmarshaler, error) {
 	c.unmarshalersMutex.Lock()
 	defer c.unmarshalersMutex.Unlock()
@@ -399,10 +509,47 @@ func (c *channel) SetFilter(filter net.BroadcastChannelFilter) error {
 		)
 	}
 
-	return c.validator.RegisterTopicValidator(c.name, createTopicValidator(filter))
+	return c.validator.RegisterTopicValidator(
+		c.name,
+		createTopicValidator(filter, c.recordDroppedByFilter),
+	)
+}
+
+// recordDroppedByFilter records the given message rejected by the channel
+// filter in the traffic metrics and lowers the reputation of its author.
+func (c *channel) recordDroppedByFilter(message *pubsub.Message) {
+	// The type of the message is determined on a best-effort basis. It is
+	// left empty, and recorded as unknown, if the message cannot be
+	// unmarshaled.
+	var messageProto pb.BroadcastNetworkMessage
+	_ = proto.Unmarshal(message.Data, &messageProto)
+
+	c.trafficMetrics.MessageDroppedByFilter(
+		c.name,
+		c.trafficMessageType(messageProto.Type),
+	)
+	c.reportMisbehavior(message.GetFrom(), net.UnauthorizedMessageMisbehavior)
 }
 
-func createTopicValidator(filter net.BroadcastChannelFilter) pubsub.Validator {
+// reportMisbehavior lowers the reputation of the given message author.
+// Messages are signed by their authors so they are accountable for them,
+// regardless of the peers relaying the messages.
+func (c *channel) reportMisbehavior(
+	author peer.ID,
+	misbehavior net.Misbehavior,
+) {
+	var self peer.ID
+	if c.clientIdentity != nil {
+		self = c.clientIdentity.id
+	}
+
+	reportMisbehavior(c.reputation, self, author, misbehavior)
+}
+
+func createTopicValidator(
+	filter net.BroadcastChannelFilter,
+	onDropped func(message *pubsub.Message),
+) pubsub.Validator {
 	return func(_ context.Context, _ peer.ID, message *pubsub.Message) bool {
 		authorPublicKey, err := extractPublicKey(message.GetFrom())
 		if err != nil {
@@ -410,9 +557,16 @@ func createTopicValidator(filter net.BroadcastChannelFilter) pubsub.Validator {
 				"could not retrieve message author public key: [%v]",
 				err,
 			)
+			onDropped(message)
 			return false
 		}
-		return filter(authorPublicKey)
+
+		if !filter(authorPublicKey) {
+			onDropped(message)
+			return false
+		}
+
+		return true
 	}
 }
 
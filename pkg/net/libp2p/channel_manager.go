@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/reputation"
 	"github.com/keep-network/keep-core/pkg/net/retransmission"
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/peerstore"
@@ -31,6 +32,10 @@ type channelManager struct {
 
 	retransmissionTicker *retransmission.Ticker
 
+	trafficMetrics *net.TrafficMetrics
+
+	reputation *reputation.Reputation
+
 	forwardersMutex sync.Mutex
 	forwarders      map[string]pubsub.RelayCancelFunc
 
@@ -43,25 +48,46 @@ func newChannelManager(
 	identity *identity,
 	p2phost host.Host,
 	retransmissionTicker *retransmission.Ticker,
+	trafficMetrics *net.TrafficMetrics,
+	peerReputation *reputation.Reputation,
 ) (*channelManager, error) {
-	floodsub, err := pubsub.NewFloodSub(
-		ctx,
-		p2phost,
+	options := []pubsub.Option{
 		pubsub.WithMessageAuthor(identity.id),
 		pubsub.WithMessageSignaturePolicy(pubsub.StrictSign),
 		pubsub.WithPeerOutboundQueueSize(libp2pPeerOutboundQueueSize),
 		pubsub.WithValidateQueueSize(libp2pValidationQueueSize),
-	)
+	}
+
+	// Misbehaving peers are scored by the reputation. The pubsub peer score
+	// is based on the reputation so pubsub stops exchanging messages with
+	// peers whose reputation is low. Messages of banned peers are dropped.
+	if peerReputation != nil {
+		options = append(
+			options,
+			pubsub.WithBlacklist(&reputationBlacklist{peerReputation}),
+			pubsub.WithPeerScore(
+				peerScoreParams(peerReputation),
+				peerScoreThresholds(peerReputation),
+			),
+		)
+	}
+
+	// Gossipsub is used as peer scoring is supported only by the gossipsub
+	// router. Gossipsub floods published messages and is compatible with
+	// peers using the floodsub router.
+	gossipsub, err := pubsub.NewGossipSub(ctx, p2phost, options...)
 	if err != nil {
 		return nil, err
 	}
 	return &channelManager{
 		channels:             make(map[string]*channel),
-		pubsub:               floodsub,
+		pubsub:               gossipsub,
 		peerStore:            p2phost.Peerstore(),
 		identity:             identity,
 		ctx:                  ctx,
 		retransmissionTicker: retransmissionTicker,
+		trafficMetrics:       trafficMetrics,
+		reputation:           peerReputation,
 		forwarders:           make(map[string]pubsub.RelayCancelFunc),
 		topics:               make(map[string]*pubsub.Topic),
 	}, nil
@@ -118,8 +144,11 @@ func (cm *channelManager) newChannel(name string) (*channel, error) {
 		)
 	}
 
+	ctx, cancelCtx := context.WithCancel(cm.ctx)
+
 	channel := &channel{
 		name:                 name,
+		cancelCtx:            cancelCtx,
 		clientIdentity:       cm.identity,
 		peerStore:            cm.peerStore,
 		validator:            cm.pubsub,
@@ -129,13 +158,30 @@ func (cm *channelManager) newChannel(name string) (*channel, error) {
 		messageHandlers:      make([]*messageHandler, 0),
 		unmarshalersByType:   make(map[string]func() net.TaggedUnmarshaler),
 		retransmissionTicker: cm.retransmissionTicker,
+		trafficMetrics:       cm.trafficMetrics,
+		reputation:           cm.reputation,
 	}
 
-	go channel.handleMessages(cm.ctx)
+	channel.handleMessages(ctx)
 
 	return channel, nil
 }
 
+func (cm *channelManager) closeChannel(name string) {
+	cm.channelsMutex.Lock()
+	channel, exists := cm.channels[name]
+	delete(cm.channels, name)
+	cm.channelsMutex.Unlock()
+
+	if !exists {
+		return
+	}
+
+	logger.Infof("closing broadcast channel [%v]", name)
+
+	channel.close()
+}
+
 func (cm *channelManager) newForwarder(name string, ttl time.Duration) error {
 	cm.forwardersMutex.Lock()
 	defer cm.forwardersMutex.Unlock()
@@ -9,9 +9,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
 	"github.com/keep-network/keep-core/pkg/operator"
 
 	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/gen/pb"
 	peer "github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
@@ -21,7 +23,7 @@ func TestRegisterAndFireHandler(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
-	channel := &channel{}
+	channel := &channel{retransmissionTicker: idleTicker()}
 
 	handlerFiredChan := make(chan struct{})
 	channel.Recv(ctx, func(msg net.Message) {
@@ -74,7 +76,7 @@ func TestUnregisterHandler(t *testing.T) {
 	for testName, test := range tests {
 		test := test
 		t.Run(testName, func(t *testing.T) {
-			channel := &channel{}
+			channel := &channel{retransmissionTicker: idleTicker()}
 
 			handlersFiredMutex := &sync.Mutex{}
 			handlersFired := []string{}
@@ -122,7 +124,7 @@ func TestUnregisterHandler(t *testing.T) {
 }
 
 func TestUnregisterWhenHandling(t *testing.T) {
-	channel := &channel{}
+	channel := &channel{retransmissionTicker: idleTicker()}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -151,7 +153,7 @@ func TestUnregisterWhenHandling(t *testing.T) {
 }
 
 func TestUnregisterWhenHandlingBlocked(t *testing.T) {
-	channel := &channel{}
+	channel := &channel{retransmissionTicker: idleTicker()}
 	receiver := make(chan interface{})
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -196,7 +198,10 @@ func TestCreateTopicValidator(t *testing.T) {
 		return isAuthorized
 	}
 
-	validator := createTopicValidator(filter)
+	droppedCount := 0
+	validator := createTopicValidator(filter, func(message *pubsub.Message) {
+		droppedCount++
+	})
 
 	expectedResults := []bool{true, false, false, true, false}
 	for i, operatorPublicKey := range operatorPublicKeys {
@@ -221,6 +226,46 @@ func TestCreateTopicValidator(t *testing.T) {
 			)
 		}
 	}
+
+	testutils.AssertIntsEqual(t, "dropped messages count", 3, droppedCount)
+}
+
+func TestProcessContainerMessage_TrafficMetrics(t *testing.T) {
+	trafficMetrics := net.NewTrafficMetrics()
+
+	channel := &channel{
+		name:               "test-channel",
+		unmarshalersByType: make(map[string]func() net.TaggedUnmarshaler),
+		trafficMetrics:     trafficMetrics,
+	}
+
+	// There is no unmarshaler registered for the message type so, it must
+	// be recorded under the unknown type.
+	err := channel.processContainerMessage(
+		peer.ID("sender"),
+		&pb.BroadcastNetworkMessage{Type: []byte("unregistered")},
+	)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expectedSnapshot := []net.MessageTraffic{
+		{
+			Channel:                "test-channel",
+			Type:                   net.UnknownMessageType,
+			UnmarshalFailuresCount: 1,
+		},
+	}
+	if snapshot := trafficMetrics.Snapshot(); !reflect.DeepEqual(
+		expectedSnapshot,
+		snapshot,
+	) {
+		t.Errorf(
+			"unexpected traffic\nexpected: %+v\nactual:   %+v",
+			expectedSnapshot,
+			snapshot,
+		)
+	}
 }
 
 func toEncodedBytes(t *testing.T, publicKey *operator.PublicKey) string {
@@ -10,7 +10,10 @@ import (
 
 	"github.com/ipfs/go-log"
 
+	"github.com/keep-network/keep-common/pkg/persistence"
+
 	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/reputation"
 	"github.com/keep-network/keep-core/pkg/net/retransmission"
 	"github.com/keep-network/keep-core/pkg/net/watchtower"
 
@@ -83,6 +86,12 @@ type provider struct {
 	channelManagerMutex     sync.Mutex
 	broadcastChannelManager *channelManager
 
+	unicastChannelManager *unicastChannelManager
+
+	trafficMetrics *net.TrafficMetrics
+
+	reputation *reputation.Reputation
+
 	identity          *identity
 	host              host.Host
 	routing           *dht.IpfsDHT
@@ -97,6 +106,44 @@ func (p *provider) BroadcastChannelFor(name string) (net.BroadcastChannel, error
 	return p.broadcastChannelManager.getChannel(name)
 }
 
+func (p *provider) CloseBroadcastChannel(name string) {
+	p.channelManagerMutex.Lock()
+	defer p.channelManagerMutex.Unlock()
+	p.broadcastChannelManager.closeChannel(name)
+}
+
+func (p *provider) UnicastChannelWith(
+	name string,
+	remotePeerID net.TransportIdentifier,
+) (net.UnicastChannel, error) {
+	peerID, err := peer.Decode(remotePeerID.String())
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to decode peer ID from [%v]: [%v]",
+			remotePeerID,
+			err,
+		)
+	}
+
+	return p.unicastChannelManager.getChannel(name, peerID)
+}
+
+func (p *provider) OnUnicastChannelOpened(
+	ctx context.Context,
+	name string,
+	handler func(channel net.UnicastChannel),
+) {
+	p.unicastChannelManager.onChannelOpened(ctx, name, handler)
+}
+
+func (p *provider) TrafficMetrics() *net.TrafficMetrics {
+	return p.trafficMetrics
+}
+
+func (p *provider) PeerReputation() net.PeerReputation {
+	return &peerReputation{p.reputation, p.identity.id}
+}
+
 func (p *provider) Type() string {
 	return "libp2p"
 }
@@ -250,6 +297,9 @@ func (cm *connectionManager) monitorConnectedPeers(ctx context.Context) {
 // ConnectOptions allows to set various options used by libp2p.
 type ConnectOptions struct {
 	RoutingTableRefreshPeriod time.Duration
+	// ReputationPersistence is used to store bans of misbehaving peers so
+	// they survive restarts. Bans are kept in memory only if it is nil.
+	ReputationPersistence persistence.BasicHandle
 }
 
 func defaultConnectOptions() *ConnectOptions {
@@ -277,6 +327,14 @@ func WithRoutingTableRefreshPeriod(period time.Duration) ConnectOption {
 	}
 }
 
+// WithReputationPersistence sets the persistence handle used to store bans
+// of misbehaving peers.
+func WithReputationPersistence(handle persistence.BasicHandle) ConnectOption {
+	return func(options *ConnectOptions) {
+		options.ReputationPersistence = handle
+	}
+}
+
 // Connect connects to a libp2p network based on the provided config. The
 // connection is managed in part by the passed context, and provides access to
 // the functionality specified in the net.Provider interface.
@@ -311,6 +369,23 @@ func Connect(
 		return nil, err
 	}
 
+	// The reputation is created before the host as it is used by the
+	// firewall to reject banned peers. Peers are disconnected only after
+	// the host is created, once messages start flowing.
+	var p2phost host.Host
+	peerReputation, err := reputation.New(
+		ctx,
+		logger,
+		reputation.DefaultParams(),
+		connectOptions.ReputationPersistence,
+		hostDisconnector(func() host.Host { return p2phost }),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	firewall = &reputationFirewall{firewall, peerReputation}
+
 	host, err := discoverAndListen(
 		ctx,
 		identity,
@@ -324,7 +399,18 @@ func Connect(
 
 	host.Network().Notify(buildNotifiee())
 
-	broadcastChannelManager, err := newChannelManager(ctx, identity, host, ticker)
+	p2phost = host
+
+	trafficMetrics := net.NewTrafficMetrics()
+
+	broadcastChannelManager, err := newChannelManager(
+		ctx,
+		identity,
+		host,
+		ticker,
+		trafficMetrics,
+		peerReputation,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -349,8 +435,16 @@ func Connect(
 		host:                    rhost.Wrap(host, router),
 		routing:                 router,
 		disseminationTime:       config.DisseminationTime,
+		trafficMetrics:          trafficMetrics,
+		reputation:              peerReputation,
 	}
 
+	provider.unicastChannelManager = newUnicastChannelManager(
+		ctx,
+		provider.host,
+		peerReputation,
+	)
+
 	if len(config.Peers) == 0 {
 		logger.Infof("bootstrap peers list is empty")
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/keep-network/keep-core/pkg/operator"
 
 	"github.com/keep-network/keep-core/pkg/firewall"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/net/retransmission"
 )
@@ -75,6 +76,61 @@ func TestProviderReturnsChannel(t *testing.T) {
 	}
 }
 
+func TestProviderClosesChannel(t *testing.T) {
+	ctx, cancel := newTestContext()
+	defer cancel()
+
+	name := "testchannel"
+
+	operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := Connect(
+		ctx,
+		generateDeterministicNetworkConfig(),
+		operatorPrivateKey,
+		firewall.Disabled,
+		idleTicker(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broadcastChannel, err := provider.BroadcastChannelFor(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := broadcastChannel.Send(
+		ctx,
+		&testMessage{Payload: "some text"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	provider.CloseBroadcastChannel(name)
+
+	if snapshot := provider.TrafficMetrics().Snapshot(); len(snapshot) != 0 {
+		t.Errorf("expected no traffic of the closed channel: [%+v]", snapshot)
+	}
+	testutils.AssertIntsEqual(
+		t,
+		"total sent count",
+		1,
+		int(provider.TrafficMetrics().Total().SentCount),
+	)
+
+	newBroadcastChannel, err := provider.BroadcastChannelFor(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newBroadcastChannel == broadcastChannel {
+		t.Errorf("expected a new channel instance")
+	}
+}
+
 func TestSendReceive(t *testing.T) {
 	ctx, cancel := newTestContext()
 	defer cancel()
@@ -149,6 +205,20 @@ func TestSendReceive(t *testing.T) {
 					testPayload.Payload,
 				)
 			}
+
+			traffic := provider.TrafficMetrics().Total()
+			if traffic.SentCount != 1 || traffic.ReceivedCount != 1 {
+				t.Errorf(
+					"unexpected traffic metrics\n"+
+						"expected: sent [1], received [1]\n"+
+						"actual:   sent [%v], received [%v]",
+					traffic.SentCount,
+					traffic.ReceivedCount,
+				)
+			}
+			if traffic.SentBytes == 0 || traffic.ReceivedBytes == 0 {
+				t.Errorf("expected non-zero traffic bytes")
+			}
 			return
 		case <-ctx.Done():
 			t.Fatal(err)
@@ -0,0 +1,182 @@
+package libp2p
+
+import (
+	"fmt"
+	"time"
+
+	libp2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/reputation"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+// peerReputation exposes the reputation of remote peers to the protocol
+// layer which identifies peers by their operator public keys.
+type peerReputation struct {
+	reputation *reputation.Reputation
+	self       peer.ID
+}
+
+func (pr *peerReputation) ReportMisbehavior(
+	operatorPublicKey []byte,
+	misbehavior net.Misbehavior,
+) {
+	// Uncompressed operator public keys are accepted by libp2p as well.
+	networkPublicKey, err := libp2pcrypto.UnmarshalSecp256k1PublicKey(
+		operatorPublicKey,
+	)
+	if err != nil {
+		logger.Warnf(
+			"could not report misbehavior [%v]: [%v]",
+			misbehavior,
+			err,
+		)
+		return
+	}
+
+	peerID, err := peer.IDFromPublicKey(networkPublicKey)
+	if err != nil {
+		logger.Warnf(
+			"could not report misbehavior [%v]: [%v]",
+			misbehavior,
+			err,
+		)
+		return
+	}
+
+	reportMisbehavior(pr.reputation, pr.self, peerID, misbehavior)
+}
+
+func (pr *peerReputation) PeerScores() []net.PeerScore {
+	return pr.reputation.PeerScores()
+}
+
+// reputationBlacklist is a pubsub blacklist rejecting peers banned for
+// misbehavior. Messages authored or relayed by banned peers are dropped by
+// pubsub before they reach the channel.
+type reputationBlacklist struct {
+	reputation *reputation.Reputation
+}
+
+func (rb *reputationBlacklist) Add(peerID peer.ID) bool {
+	rb.reputation.Ban(peerID.String())
+	return true
+}
+
+func (rb *reputationBlacklist) Contains(peerID peer.ID) bool {
+	return rb.reputation.IsBanned(peerID.String())
+}
+
+// peerScoreParams returns the pubsub peer scoring parameters based on
+// the given reputation. The pubsub score of a peer is its reputation score.
+// Topic scoring is not used as the client joins short-lived topics.
+func peerScoreParams(
+	peerReputation *reputation.Reputation,
+) *pubsub.PeerScoreParams {
+	params := peerReputation.Params()
+
+	// Pubsub does not accept decay intervals shorter than one second.
+	decayInterval := params.DecayInterval
+	if decayInterval < time.Second {
+		decayInterval = time.Second
+	}
+
+	return &pubsub.PeerScoreParams{
+		AppSpecificScore: func(peerID peer.ID) float64 {
+			return peerReputation.Score(peerID.String())
+		},
+		AppSpecificWeight: 1,
+		DecayInterval:     decayInterval,
+		DecayToZero:       params.DecayToZero,
+	}
+}
+
+// peerScoreThresholds returns the pubsub peer score thresholds based on
+// the given reputation. Messages are neither published nor gossiped to
+// peers whose score dropped below the disconnect threshold and all messages
+// of peers whose score dropped below the ban threshold are ignored.
+func peerScoreThresholds(
+	peerReputation *reputation.Reputation,
+) *pubsub.PeerScoreThresholds {
+	params := peerReputation.Params()
+
+	return &pubsub.PeerScoreThresholds{
+		GossipThreshold:   params.DisconnectThreshold,
+		PublishThreshold:  params.DisconnectThreshold,
+		GraylistThreshold: params.BanThreshold,
+	}
+}
+
+// reputationFirewall extends the firewall with a rule rejecting peers
+// banned for misbehavior. Because the firewall is also used by the
+// watchtower, banned peers are periodically disconnected, in addition to
+// having their new connections rejected.
+type reputationFirewall struct {
+	net.Firewall
+
+	reputation *reputation.Reputation
+}
+
+func (rf *reputationFirewall) Validate(
+	remotePeerPublicKey *operator.PublicKey,
+) error {
+	peerID, err := operatorPublicKeyToPeerID(remotePeerPublicKey)
+	if err != nil {
+		return err
+	}
+
+	if rf.reputation.IsBanned(peerID.String()) {
+		return fmt.Errorf("peer [%v] is banned for misbehavior", peerID)
+	}
+
+	return rf.Firewall.Validate(remotePeerPublicKey)
+}
+
+// reportMisbehavior lowers the reputation of the given remote peer. It is
+// a no-op if the reputation is not set or the peer is the client itself.
+func reportMisbehavior(
+	peerReputation *reputation.Reputation,
+	self peer.ID,
+	remotePeerID peer.ID,
+	misbehavior net.Misbehavior,
+) {
+	if peerReputation == nil || remotePeerID == self {
+		return
+	}
+
+	peerReputation.ReportMisbehavior(remotePeerID.String(), misbehavior)
+}
+
+// hostDisconnector returns a function closing all connections with
+// the given peer. The host is resolved lazily as the reputation is created
+// before the host.
+func hostDisconnector(host func() host.Host) func(peerID string) {
+	return func(peerID string) {
+		id, err := peer.Decode(peerID)
+		if err != nil {
+			logger.Errorf("failed to decode peer ID [%v]: [%v]", peerID, err)
+			return
+		}
+
+		if err := host().Network().ClosePeer(id); err != nil {
+			logger.Errorf("failed to disconnect peer [%v]: [%v]", peerID, err)
+		}
+	}
+}
+
+func operatorPublicKeyToPeerID(
+	operatorPublicKey *operator.PublicKey,
+) (peer.ID, error) {
+	networkPublicKey, err := operatorPublicKeyToNetworkPublicKey(
+		operatorPublicKey,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return peer.IDFromPublicKey(networkPublicKey)
+}
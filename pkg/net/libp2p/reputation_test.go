@@ -0,0 +1,190 @@
+package libp2p
+
+import (
+	"context"
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/firewall"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/gen/pb"
+	"github.com/keep-network/keep-core/pkg/net/reputation"
+	"github.com/keep-network/keep-core/pkg/operator"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+func TestReputationFirewall(t *testing.T) {
+	peerReputation := newTestPeerReputation(t)
+
+	_, operatorPublicKey, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	peerID, err := operatorPublicKeyToPeerID(operatorPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reputationFirewall := &reputationFirewall{firewall.Disabled, peerReputation}
+
+	if err := reputationFirewall.Validate(operatorPublicKey); err != nil {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+
+	peerReputation.Ban(peerID.String())
+
+	if err := reputationFirewall.Validate(operatorPublicKey); err == nil {
+		t.Fatal("expected error for banned peer")
+	}
+}
+
+func TestPeerReputation_ReportMisbehavior(t *testing.T) {
+	testReputation := newTestPeerReputation(t)
+
+	_, selfPublicKey, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selfID, err := operatorPublicKeyToPeerID(selfPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, remotePublicKey, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteID, err := operatorPublicKeyToPeerID(remotePublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adapter := &peerReputation{testReputation, selfID}
+
+	// Misbehaviors of the client itself are ignored.
+	adapter.ReportMisbehavior(
+		operator.MarshalUncompressed(selfPublicKey),
+		net.InvalidMembershipMisbehavior,
+	)
+	adapter.ReportMisbehavior(
+		operator.MarshalUncompressed(remotePublicKey),
+		net.InvalidMembershipMisbehavior,
+	)
+
+	scores := adapter.PeerScores()
+	testutils.AssertIntsEqual(t, "peer scores count", 1, len(scores))
+	testutils.AssertStringsEqual(
+		t,
+		"peer ID",
+		remoteID.String(),
+		scores[0].PeerID,
+	)
+}
+
+func TestPeerScoreParams(t *testing.T) {
+	peerReputation := newTestPeerReputation(t)
+
+	peerReputation.ReportMisbehavior(
+		peer.ID("peer").String(),
+		net.MalformedMessageMisbehavior,
+	)
+
+	params := peerScoreParams(peerReputation)
+
+	score := params.AppSpecificScore(peer.ID("peer"))
+	if score != -10 {
+		t.Errorf(
+			"unexpected app-specific score\nexpected: [%v]\nactual:   [%v]",
+			-10,
+			score,
+		)
+	}
+
+	thresholds := peerScoreThresholds(peerReputation)
+	reputationParams := reputation.DefaultParams()
+
+	if thresholds.PublishThreshold != reputationParams.DisconnectThreshold {
+		t.Errorf(
+			"unexpected publish threshold\nexpected: [%v]\nactual:   [%v]",
+			reputationParams.DisconnectThreshold,
+			thresholds.PublishThreshold,
+		)
+	}
+	if thresholds.GraylistThreshold != reputationParams.BanThreshold {
+		t.Errorf(
+			"unexpected graylist threshold\nexpected: [%v]\nactual:   [%v]",
+			reputationParams.BanThreshold,
+			thresholds.GraylistThreshold,
+		)
+	}
+}
+
+func TestProcessContainerMessage_ReportMisbehavior(t *testing.T) {
+	var tests = map[string]struct {
+		messageType     string
+		expectedReports int
+	}{
+		"malformed payload": {
+			messageType:     (&testMessage{}).Type(),
+			expectedReports: 1,
+		},
+		// Unmarshalers may not be registered yet so messages of unknown
+		// types are not considered a misbehavior.
+		"unknown type": {
+			messageType:     "unknown",
+			expectedReports: 0,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			peerReputation := newTestPeerReputation(t)
+
+			channel := &channel{
+				name:               "test-channel",
+				unmarshalersByType: make(map[string]func() net.TaggedUnmarshaler),
+				reputation:         peerReputation,
+			}
+			channel.SetUnmarshaler(
+				func() net.TaggedUnmarshaler { return &testMessage{} },
+			)
+
+			err := channel.processContainerMessage(
+				peer.ID("sender"),
+				&pb.BroadcastNetworkMessage{
+					Type:    []byte(test.messageType),
+					Payload: []byte("malformed"),
+				},
+			)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"peer scores count",
+				test.expectedReports,
+				len(peerReputation.PeerScores()),
+			)
+		})
+	}
+}
+
+func newTestPeerReputation(t *testing.T) *reputation.Reputation {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	peerReputation, err := reputation.New(
+		ctx,
+		&testutils.MockLogger{},
+		reputation.DefaultParams(),
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return peerReputation
+}
@@ -0,0 +1,319 @@
+package libp2p
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	libp2pnet "github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/gen/pb"
+	"github.com/keep-network/keep-core/pkg/net/internal"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+// unicastSendTimeout is the maximum time the unicast channel waits for the
+// remote peer to read the sent message if the context passed to Send has no
+// deadline.
+const unicastSendTimeout = 30 * time.Second
+
+// streamFactory opens a new stream with the given remote peer.
+type streamFactory func(
+	ctx context.Context,
+	remotePeerID peer.ID,
+) (libp2pnet.Stream, error)
+
+type unicastChannel struct {
+	// channel-scoped atomic counter for sequence numbers
+	//
+	// Must be declared at the top of the struct!
+	// See: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	counter uint64
+	// lastActivity is the Unix time in nanoseconds of the last activity on
+	// the channel. It must be accessed atomically.
+	lastActivity int64
+
+	name string
+
+	remotePeerID        peer.ID
+	remotePeerPublicKey *operator.PublicKey
+
+	streamFactory streamFactory
+
+	messageHandlersMutex sync.Mutex
+	messageHandlers      []*messageHandler
+
+	unmarshalersMutex  sync.Mutex
+	unmarshalersByType map[string]func() net.TaggedUnmarshaler
+}
+
+func newUnicastChannel(
+	name string,
+	remotePeerID peer.ID,
+	streamFactory streamFactory,
+) (*unicastChannel, error) {
+	remotePeerPublicKey, err := extractPublicKey(remotePeerID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not extract public key of peer [%v]: [%v]",
+			remotePeerID,
+			err,
+		)
+	}
+
+	return &unicastChannel{
+		lastActivity:        time.Now().UnixNano(),
+		name:                name,
+		remotePeerID:        remotePeerID,
+		remotePeerPublicKey: remotePeerPublicKey,
+		streamFactory:       streamFactory,
+		messageHandlers:     make([]*messageHandler, 0),
+		unmarshalersByType:  make(map[string]func() net.TaggedUnmarshaler),
+	}, nil
+}
+
+func (uc *unicastChannel) nextSeqno() uint64 {
+	return atomic.AddUint64(&uc.counter, 1)
+}
+
+// markActive records the channel was used at the current time.
+func (uc *unicastChannel) markActive() {
+	atomic.StoreInt64(&uc.lastActivity, time.Now().UnixNano())
+}
+
+// isIdle returns true if the channel has no message handlers and was not
+// used for longer than the given timeout.
+func (uc *unicastChannel) isIdle(now time.Time, timeout time.Duration) bool {
+	uc.messageHandlersMutex.Lock()
+	handlersCount := len(uc.messageHandlers)
+	uc.messageHandlersMutex.Unlock()
+
+	if handlersCount > 0 {
+		return false
+	}
+
+	lastActivity := time.Unix(0, atomic.LoadInt64(&uc.lastActivity))
+
+	return now.Sub(lastActivity) > timeout
+}
+
+func (uc *unicastChannel) Name() string {
+	return uc.name
+}
+
+func (uc *unicastChannel) RemotePeerID() net.TransportIdentifier {
+	return uc.remotePeerID
+}
+
+func (uc *unicastChannel) Send(
+	ctx context.Context,
+	message net.TaggedMarshaler,
+) error {
+	uc.markActive()
+
+	payloadBytes, err := message.Marshal()
+	if err != nil {
+		return err
+	}
+
+	messageBytes, err := proto.Marshal(&pb.UnicastNetworkMessage{
+		Channel:        uc.name,
+		Payload:        payloadBytes,
+		Type:           []byte(message.Type()),
+		SequenceNumber: uc.nextSeqno(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(messageBytes) > maxUnicastMessageSize {
+		return fmt.Errorf(
+			"message size [%v] exceeds the limit [%v]",
+			len(messageBytes),
+			maxUnicastMessageSize,
+		)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancelCtx context.CancelFunc
+		ctx, cancelCtx = context.WithTimeout(ctx, unicastSendTimeout)
+		defer cancelCtx()
+	}
+
+	stream, err := uc.streamFactory(ctx, uc.remotePeerID)
+	if err != nil {
+		return fmt.Errorf(
+			"could not open stream with peer [%v]: [%v]",
+			uc.remotePeerID,
+			err,
+		)
+	}
+
+	deadline, _ := ctx.Deadline()
+	if err := stream.SetDeadline(deadline); err != nil {
+		_ = stream.Reset()
+		return fmt.Errorf("could not set stream deadline: [%v]", err)
+	}
+
+	if _, err := stream.Write(messageBytes); err != nil {
+		_ = stream.Reset()
+		return fmt.Errorf(
+			"could not write message to peer [%v]: [%v]",
+			uc.remotePeerID,
+			err,
+		)
+	}
+
+	if err := stream.CloseWrite(); err != nil {
+		_ = stream.Reset()
+		return fmt.Errorf("could not close stream for writing: [%v]", err)
+	}
+
+	// The remote peer closes the stream once it read the whole message.
+	// Wait for that to make sure the message has been delivered.
+	if _, err := io.Copy(io.Discard, stream); err != nil {
+		_ = stream.Reset()
+		return fmt.Errorf(
+			"message not confirmed by peer [%v]: [%v]",
+			uc.remotePeerID,
+			err,
+		)
+	}
+
+	return stream.Close()
+}
+
+func (uc *unicastChannel) Recv(ctx context.Context, handler func(m net.Message)) {
+	messageHandler := &messageHandler{
+		ctx:     ctx,
+		channel: make(chan net.Message, messageHandlerThrottle),
+	}
+
+	uc.messageHandlersMutex.Lock()
+	uc.messageHandlers = append(uc.messageHandlers, messageHandler)
+	uc.messageHandlersMutex.Unlock()
+
+	uc.markActive()
+
+	go func() {
+		<-ctx.Done()
+		logger.Debug("context is done; removing unicast message handler")
+		uc.removeHandler(messageHandler)
+	}()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case msg := <-messageHandler.channel:
+				// The same as for the broadcast channel, this communication
+				// may be chosen even if the context is already done. Since
+				// we guarantee the handler is not called after the context
+				// is done, the context state is double-checked here.
+				if messageHandler.ctx.Err() != nil {
+					continue
+				}
+
+				handler(msg)
+			}
+		}
+	}()
+}
+
+func (uc *unicastChannel) removeHandler(handler *messageHandler) {
+	// The channel becomes idle once all handlers are removed so, count
+	// the idle time from the removal of the last handler.
+	defer uc.markActive()
+
+	uc.messageHandlersMutex.Lock()
+	defer uc.messageHandlersMutex.Unlock()
+
+	for i, h := range uc.messageHandlers {
+		if h.channel == handler.channel {
+			uc.messageHandlers[i] = uc.messageHandlers[len(uc.messageHandlers)-1]
+			uc.messageHandlers = uc.messageHandlers[:len(uc.messageHandlers)-1]
+			break
+		}
+	}
+}
+
+func (uc *unicastChannel) SetUnmarshaler(unmarshaler func() net.TaggedUnmarshaler) {
+	tpe := unmarshaler().Type()
+
+	uc.unmarshalersMutex.Lock()
+	defer uc.unmarshalersMutex.Unlock()
+
+	uc.unmarshalersByType[tpe] = unmarshaler
+}
+
+func (uc *unicastChannel) processMessage(message *pb.UnicastNetworkMessage) error {
+	uc.markActive()
+
+	unmarshaled, err := uc.getUnmarshalingContainerByType(string(message.Type))
+	if err != nil {
+		return err
+	}
+
+	if err := unmarshaled.Unmarshal(message.GetPayload()); err != nil {
+		return fmt.Errorf(
+			"%w: could not unmarshal payload: [%v]",
+			errMalformedMessage,
+			err,
+		)
+	}
+
+	// The sender is the remote peer of the authenticated connection the
+	// message was received over so there is no need to verify it.
+	netMessage := internal.BasicMessage(
+		uc.remotePeerID,
+		unmarshaled,
+		string(message.Type),
+		operator.MarshalUncompressed(uc.remotePeerPublicKey),
+		message.SequenceNumber,
+	)
+
+	uc.deliver(netMessage)
+
+	return nil
+}
+
+func (uc *unicastChannel) getUnmarshalingContainerByType(
+	messageType string,
+) (net.TaggedUnmarshaler, error) {
+	uc.unmarshalersMutex.Lock()
+	defer uc.unmarshalersMutex.Unlock()
+
+	unmarshaler, found := uc.unmarshalersByType[messageType]
+	if !found {
+		return nil, fmt.Errorf(
+			"couldn't find unmarshaler for type [%s]",
+			messageType,
+		)
+	}
+
+	return unmarshaler(), nil
+}
+
+func (uc *unicastChannel) deliver(message net.Message) {
+	uc.messageHandlersMutex.Lock()
+	snapshot := make([]*messageHandler, len(uc.messageHandlers))
+	copy(snapshot, uc.messageHandlers)
+	uc.messageHandlersMutex.Unlock()
+
+	for _, handler := range snapshot {
+		select {
+		case handler.channel <- message:
+		default:
+			logger.Warnf("unicast message handler is too slow; dropping message")
+		}
+	}
+}
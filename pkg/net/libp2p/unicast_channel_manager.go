@@ -0,0 +1,311 @@
+package libp2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	libp2pnet "github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/gen/pb"
+	"github.com/keep-network/keep-core/pkg/net/reputation"
+)
+
+// unicastProtocolID is the identifier of the protocol used to exchange
+// unicast channel messages over libp2p streams. Every message is sent over
+// a separate stream.
+const unicastProtocolID = protocol.ID("/" + protocolKeep + "/unicast/1.0.0")
+
+const (
+	// maxUnicastMessageSize is the maximum size of a unicast network message.
+	// It is the same as the default maximum size of a pubsub message.
+	maxUnicastMessageSize = 1 << 20
+	// unicastReadTimeout is the maximum time of reading a message from
+	// an incoming stream.
+	unicastReadTimeout = 30 * time.Second
+	// unicastChannelIdleTimeout is the time after which a unicast channel
+	// without message handlers and without any messages sent or received is
+	// removed by the manager.
+	unicastChannelIdleTimeout = 10 * time.Minute
+)
+
+type unicastChannelOpenedHandler struct {
+	ctx     context.Context
+	name    string
+	handler func(channel net.UnicastChannel)
+}
+
+// errMalformedMessage is returned when the incoming unicast message cannot
+// be decoded.
+var errMalformedMessage = errors.New("malformed message")
+
+type unicastChannelManager struct {
+	host       host.Host
+	reputation *reputation.Reputation
+
+	channelsMutex sync.Mutex
+	// channels are keyed by channel name and remote peer ID.
+	channels map[string]*unicastChannel
+
+	openedHandlersMutex sync.Mutex
+	openedHandlers      []*unicastChannelOpenedHandler
+}
+
+func newUnicastChannelManager(
+	ctx context.Context,
+	p2phost host.Host,
+	peerReputation *reputation.Reputation,
+) *unicastChannelManager {
+	ucm := &unicastChannelManager{
+		host:       p2phost,
+		reputation: peerReputation,
+		channels:   make(map[string]*unicastChannel),
+	}
+
+	p2phost.SetStreamHandler(unicastProtocolID, ucm.handleStream)
+
+	go func() {
+		<-ctx.Done()
+		p2phost.RemoveStreamHandler(unicastProtocolID)
+	}()
+
+	go ucm.removeIdleChannelsLoop(ctx)
+
+	return ucm
+}
+
+func (ucm *unicastChannelManager) removeIdleChannelsLoop(ctx context.Context) {
+	ticker := time.NewTicker(unicastChannelIdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			ucm.removeIdleChannels(now)
+		}
+	}
+}
+
+// removeIdleChannels removes channels idle for longer than
+// unicastChannelIdleTimeout so channels with peers the client no longer
+// communicates with do not accumulate.
+func (ucm *unicastChannelManager) removeIdleChannels(now time.Time) {
+	ucm.channelsMutex.Lock()
+	defer ucm.channelsMutex.Unlock()
+
+	for key, channel := range ucm.channels {
+		if channel.isIdle(now, unicastChannelIdleTimeout) {
+			logger.Debugf(
+				"removing idle unicast channel [%v] with peer [%v]",
+				channel.name,
+				channel.remotePeerID,
+			)
+			delete(ucm.channels, key)
+		}
+	}
+}
+
+func unicastChannelKey(name string, remotePeerID peer.ID) string {
+	return fmt.Sprintf("%s-%s", name, remotePeerID)
+}
+
+func (ucm *unicastChannelManager) getChannel(
+	name string,
+	remotePeerID peer.ID,
+) (*unicastChannel, error) {
+	channel, _, err := ucm.getOrCreateChannel(name, remotePeerID)
+	return channel, err
+}
+
+// getOrCreateChannel returns the unicast channel with the given name and
+// remote peer. The returned flag is true if the channel has been created
+// by this call.
+func (ucm *unicastChannelManager) getOrCreateChannel(
+	name string,
+	remotePeerID peer.ID,
+) (*unicastChannel, bool, error) {
+	ucm.channelsMutex.Lock()
+	defer ucm.channelsMutex.Unlock()
+
+	key := unicastChannelKey(name, remotePeerID)
+
+	if channel, exists := ucm.channels[key]; exists {
+		return channel, false, nil
+	}
+
+	channel, err := newUnicastChannel(name, remotePeerID, ucm.newStream)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ucm.channels[key] = channel
+
+	return channel, true, nil
+}
+
+func (ucm *unicastChannelManager) newStream(
+	ctx context.Context,
+	remotePeerID peer.ID,
+) (libp2pnet.Stream, error) {
+	return ucm.host.NewStream(ctx, remotePeerID, unicastProtocolID)
+}
+
+func (ucm *unicastChannelManager) onChannelOpened(
+	ctx context.Context,
+	name string,
+	handler func(channel net.UnicastChannel),
+) {
+	ucm.openedHandlersMutex.Lock()
+	defer ucm.openedHandlersMutex.Unlock()
+
+	ucm.openedHandlers = append(
+		ucm.openedHandlers,
+		&unicastChannelOpenedHandler{ctx, name, handler},
+	)
+}
+
+// activeOpenedHandlers returns handlers of the given channel name whose
+// contexts are not done yet. Handlers whose contexts are done are removed.
+func (ucm *unicastChannelManager) activeOpenedHandlers(
+	name string,
+) []*unicastChannelOpenedHandler {
+	ucm.openedHandlersMutex.Lock()
+	defer ucm.openedHandlersMutex.Unlock()
+
+	var active []*unicastChannelOpenedHandler
+	remaining := ucm.openedHandlers[:0]
+	for _, handler := range ucm.openedHandlers {
+		if handler.ctx.Err() != nil {
+			continue
+		}
+
+		remaining = append(remaining, handler)
+
+		if handler.name == name {
+			active = append(active, handler)
+		}
+	}
+	ucm.openedHandlers = remaining
+
+	return active
+}
+
+func (ucm *unicastChannelManager) handleStream(stream libp2pnet.Stream) {
+	if err := ucm.processStream(stream); err != nil {
+		remotePeerID := stream.Conn().RemotePeer()
+
+		logger.Errorf(
+			"could not process unicast message from peer [%v]: [%v]",
+			remotePeerID,
+			err,
+		)
+
+		if errors.Is(err, errMalformedMessage) {
+			reportMisbehavior(
+				ucm.reputation,
+				ucm.host.ID(),
+				remotePeerID,
+				net.MalformedMessageMisbehavior,
+			)
+		}
+
+		_ = stream.Reset()
+		return
+	}
+
+	// Closing the stream confirms the message has been read.
+	if err := stream.Close(); err != nil {
+		logger.Warnf("could not close unicast stream: [%v]", err)
+	}
+}
+
+func (ucm *unicastChannelManager) processStream(stream libp2pnet.Stream) error {
+	if err := stream.SetReadDeadline(
+		time.Now().Add(unicastReadTimeout),
+	); err != nil {
+		return fmt.Errorf("could not set read deadline: [%v]", err)
+	}
+
+	messageBytes, err := io.ReadAll(
+		io.LimitReader(stream, maxUnicastMessageSize+1),
+	)
+	if err != nil {
+		return fmt.Errorf("could not read message: [%v]", err)
+	}
+	if len(messageBytes) > maxUnicastMessageSize {
+		return fmt.Errorf(
+			"%w: size exceeds the limit [%v]",
+			errMalformedMessage,
+			maxUnicastMessageSize,
+		)
+	}
+
+	var message pb.UnicastNetworkMessage
+	if err := proto.Unmarshal(messageBytes, &message); err != nil {
+		return fmt.Errorf(
+			"%w: could not unmarshal message: [%v]",
+			errMalformedMessage,
+			err,
+		)
+	}
+
+	// The remote peer is authenticated by the transport so the channel is
+	// identified using the peer ID of the connection.
+	remotePeerID := stream.Conn().RemotePeer()
+
+	channel, err := ucm.channelForIncomingMessage(message.Channel, remotePeerID)
+	if err != nil {
+		return err
+	}
+
+	return channel.processMessage(&message)
+}
+
+// channelForIncomingMessage returns the unicast channel the incoming message
+// should be delivered to. If the channel does not exist yet, it is created
+// only if there are handlers waiting for channels with the given name. Those
+// handlers are called before the channel is returned.
+func (ucm *unicastChannelManager) channelForIncomingMessage(
+	name string,
+	remotePeerID peer.ID,
+) (*unicastChannel, error) {
+	ucm.channelsMutex.Lock()
+	channel, exists := ucm.channels[unicastChannelKey(name, remotePeerID)]
+	ucm.channelsMutex.Unlock()
+
+	if exists {
+		return channel, nil
+	}
+
+	openedHandlers := ucm.activeOpenedHandlers(name)
+	if len(openedHandlers) == 0 {
+		return nil, fmt.Errorf(
+			"no handlers for unicast channel [%v] opened by the peer",
+			name,
+		)
+	}
+
+	channel, created, err := ucm.getOrCreateChannel(name, remotePeerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		for _, openedHandler := range openedHandlers {
+			openedHandler.handler(channel)
+		}
+	}
+
+	return channel, nil
+}
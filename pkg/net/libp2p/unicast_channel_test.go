@@ -0,0 +1,194 @@
+package libp2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/firewall"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestUnicastSendReceive(t *testing.T) {
+	ctx, cancel := newTestContext()
+	defer cancel()
+
+	channelName := "unicast-test"
+	expectedPayload := "some text"
+
+	receiver, sender := connectTestProviders(ctx, t)
+
+	recvChan := make(chan net.Message, 1)
+	receiver.OnUnicastChannelOpened(
+		ctx,
+		channelName,
+		func(channel net.UnicastChannel) {
+			testutils.AssertStringsEqual(
+				t,
+				"remote peer ID",
+				sender.ID().String(),
+				channel.RemotePeerID().String(),
+			)
+
+			channel.SetUnmarshaler(
+				func() net.TaggedUnmarshaler { return &testMessage{} },
+			)
+			channel.Recv(ctx, func(msg net.Message) {
+				recvChan <- msg
+			})
+		},
+	)
+
+	channel, err := sender.UnicastChannelWith(channelName, receiver.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := channel.Send(
+		ctx,
+		&testMessage{Payload: expectedPayload},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-recvChan:
+		testPayload, ok := msg.Payload().(*testMessage)
+		if !ok {
+			t.Fatalf("unexpected payload type [%T]", msg.Payload())
+		}
+
+		testutils.AssertStringsEqual(
+			t,
+			"message payload",
+			expectedPayload,
+			testPayload.Payload,
+		)
+		testutils.AssertStringsEqual(
+			t,
+			"message sender",
+			sender.ID().String(),
+			msg.TransportSenderID().String(),
+		)
+		testutils.AssertIntsEqual(t, "message seqno", 1, int(msg.Seqno()))
+	case <-ctx.Done():
+		t.Fatal("expected message not received")
+	}
+}
+
+func TestUnicastSend_NoHandlers(t *testing.T) {
+	ctx, cancel := newTestContext()
+	defer cancel()
+
+	receiver, sender := connectTestProviders(ctx, t)
+
+	channel, err := sender.UnicastChannelWith("unicast-test", receiver.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The receiver does not expect the channel so it rejects the message.
+	err = channel.Send(ctx, &testMessage{Payload: "some text"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestUnicastChannelManager_RemoveIdleChannels(t *testing.T) {
+	ctx, cancel := newTestContext()
+	defer cancel()
+
+	_, operatorPublicKey, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remotePeerID, err := operatorPublicKeyToPeerID(operatorPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ucm := &unicastChannelManager{
+		channels: make(map[string]*unicastChannel),
+	}
+
+	idleChannel, _, err := ucm.getOrCreateChannel("idle", remotePeerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receivingChannel, _, err := ucm.getOrCreateChannel("receiving", remotePeerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receivingChannel.Recv(ctx, func(msg net.Message) {})
+
+	// Pretend both channels were used long time ago.
+	longTimeAgo := time.Now().Add(-2 * unicastChannelIdleTimeout).UnixNano()
+	idleChannel.lastActivity = longTimeAgo
+	receivingChannel.lastActivity = longTimeAgo
+
+	_, _, err = ucm.getOrCreateChannel("active", remotePeerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ucm.removeIdleChannels(time.Now())
+
+	var tests = map[string]struct {
+		expectedExists bool
+	}{
+		"idle":      {expectedExists: false},
+		"receiving": {expectedExists: true},
+		"active":    {expectedExists: true},
+	}
+
+	for channelName, test := range tests {
+		t.Run(channelName, func(t *testing.T) {
+			_, exists := ucm.channels[unicastChannelKey(channelName, remotePeerID)]
+			testutils.AssertBoolsEqual(
+				t,
+				"channel exists",
+				test.expectedExists,
+				exists,
+			)
+		})
+	}
+}
+
+// connectTestProviders connects two providers. The second provider uses the
+// first one as its bootstrap peer.
+func connectTestProviders(
+	ctx context.Context,
+	t *testing.T,
+) (net.Provider, net.Provider) {
+	connect := func(config Config) net.Provider {
+		operatorPrivateKey, _, err := operator.GenerateKeyPair(DefaultCurve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		provider, err := Connect(
+			ctx,
+			config,
+			operatorPrivateKey,
+			firewall.Disabled,
+			idleTicker(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return provider
+	}
+
+	first := connect(Config{Port: 8091})
+	second := connect(Config{
+		Port:  8092,
+		Peers: first.ConnectionManager().AddrStrings()[:1],
+	})
+
+	return first, second
+}
@@ -117,7 +117,11 @@ func (lc *localChannel) Recv(ctx context.Context, handler func(m net.Message)) {
 	lc.messageHandlers = append(lc.messageHandlers, messageHandler)
 	lc.messageHandlersMutex.Unlock()
 
-	handleWithRetransmissions := retransmission.WithRetransmissionSupport(handler)
+	handleWithRetransmissions := retransmission.WithRetransmissionSupport(
+		ctx,
+		lc.retransmissionTicker,
+		handler,
+	)
 
 	go func() {
 		for {
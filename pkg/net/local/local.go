@@ -4,6 +4,7 @@
 package local
 
 import (
+	"context"
 	"sync"
 
 	"github.com/keep-network/keep-core/pkg/operator"
@@ -25,9 +26,10 @@ type Provider interface {
 }
 
 type localProvider struct {
-	id                localIdentifier
-	operatorPublicKey *operator.PublicKey
-	connectionManager *localConnectionManager
+	id                    localIdentifier
+	operatorPublicKey     *operator.PublicKey
+	connectionManager     *localConnectionManager
+	unicastChannelManager *unicastChannelManager
 }
 
 func (lp *localProvider) ID() net.TransportIdentifier {
@@ -38,6 +40,28 @@ func (lp *localProvider) BroadcastChannelFor(name string) (net.BroadcastChannel,
 	return getBroadcastChannel(name, lp.operatorPublicKey), nil
 }
 
+// UnicastChannelWith provides a unicast channel with the local provider
+// identified by the given transport identifier. The identifier should be
+// created using CreateTransportIdentifier from the remote provider's
+// operator public key.
+func (lp *localProvider) UnicastChannelWith(
+	name string,
+	remotePeerID net.TransportIdentifier,
+) (net.UnicastChannel, error) {
+	return lp.unicastChannelManager.getChannel(
+		name,
+		localIdentifier(remotePeerID.String()),
+	), nil
+}
+
+func (lp *localProvider) OnUnicastChannelOpened(
+	ctx context.Context,
+	name string,
+	handler func(channel net.UnicastChannel),
+) {
+	lp.unicastChannelManager.onChannelOpened(ctx, name, handler)
+}
+
 func (lp *localProvider) Type() string {
 	return "local"
 }
@@ -59,6 +83,36 @@ func (lp *localProvider) BroadcastChannelForwarderFor(name string) {
 	//no-op
 }
 
+// CloseBroadcastChannel is a no-op as local broadcast channels are shared by
+// all local providers.
+func (lp *localProvider) CloseBroadcastChannel(name string) {
+	//no-op
+}
+
+// TrafficMetrics returns empty metrics as the local provider does not
+// record the network traffic.
+func (lp *localProvider) TrafficMetrics() *net.TrafficMetrics {
+	return net.NewTrafficMetrics()
+}
+
+// PeerReputation returns a reputation ignoring all reports as the local
+// provider does not disconnect peers.
+func (lp *localProvider) PeerReputation() net.PeerReputation {
+	return &localPeerReputation{}
+}
+
+type localPeerReputation struct{}
+
+func (lpr *localPeerReputation) ReportMisbehavior(
+	operatorPublicKey []byte,
+	misbehavior net.Misbehavior,
+) {
+}
+
+func (lpr *localPeerReputation) PeerScores() []net.PeerScore {
+	return []net.PeerScore{}
+}
+
 // Connect returns a local instance of a net provider that does not go over the
 // network.
 func Connect() Provider {
@@ -75,9 +129,10 @@ func Connect() Provider {
 // identify network messages.
 func ConnectWithKey(operatorPublicKey *operator.PublicKey) Provider {
 	return &localProvider{
-		id:                randomLocalIdentifier(),
-		operatorPublicKey: operatorPublicKey,
-		connectionManager: &localConnectionManager{peers: make(map[string]*operator.PublicKey)},
+		id:                    randomLocalIdentifier(),
+		operatorPublicKey:     operatorPublicKey,
+		connectionManager:     &localConnectionManager{peers: make(map[string]*operator.PublicKey)},
+		unicastChannelManager: newUnicastChannelManager(operatorPublicKey),
 	}
 }
 
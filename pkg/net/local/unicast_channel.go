@@ -0,0 +1,319 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/net/internal"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+var unicastChannelManagersMutex sync.Mutex
+
+// unicastChannelManagers holds unicast channel managers of all local
+// providers, keyed by transport identifiers of the providers.
+var unicastChannelManagers map[localIdentifier]*unicastChannelManager
+
+type unicastChannelOpenedHandler struct {
+	ctx     context.Context
+	name    string
+	handler func(channel net.UnicastChannel)
+}
+
+// unicastChannelManager mediates unicast channels of a single local provider.
+// The provider is identified by the transport identifier created from its
+// operator public key so other providers can address it using
+// CreateTransportIdentifier.
+type unicastChannelManager struct {
+	identifier        localIdentifier
+	operatorPublicKey *operator.PublicKey
+
+	channelsMutex sync.Mutex
+	channels      map[string]*localUnicastChannel
+
+	openedHandlersMutex sync.Mutex
+	openedHandlers      []*unicastChannelOpenedHandler
+}
+
+// newUnicastChannelManager creates a unicast channel manager for a local
+// provider with the given operator public key and makes it reachable by
+// other local providers.
+func newUnicastChannelManager(
+	operatorPublicKey *operator.PublicKey,
+) *unicastChannelManager {
+	identifier, err := createLocalIdentifier(operatorPublicKey)
+	if err != nil {
+		panic(err)
+	}
+
+	manager := &unicastChannelManager{
+		identifier:        identifier,
+		operatorPublicKey: operatorPublicKey,
+		channels:          make(map[string]*localUnicastChannel),
+	}
+
+	unicastChannelManagersMutex.Lock()
+	defer unicastChannelManagersMutex.Unlock()
+
+	if unicastChannelManagers == nil {
+		unicastChannelManagers = make(map[localIdentifier]*unicastChannelManager)
+	}
+	unicastChannelManagers[identifier] = manager
+
+	return manager
+}
+
+func getUnicastChannelManager(
+	identifier localIdentifier,
+) (*unicastChannelManager, bool) {
+	unicastChannelManagersMutex.Lock()
+	defer unicastChannelManagersMutex.Unlock()
+
+	manager, ok := unicastChannelManagers[identifier]
+	return manager, ok
+}
+
+func (ucm *unicastChannelManager) getChannel(
+	name string,
+	remotePeerID localIdentifier,
+) *localUnicastChannel {
+	channel, _ := ucm.getOrCreateChannel(name, remotePeerID)
+	return channel
+}
+
+func (ucm *unicastChannelManager) getOrCreateChannel(
+	name string,
+	remotePeerID localIdentifier,
+) (*localUnicastChannel, bool) {
+	ucm.channelsMutex.Lock()
+	defer ucm.channelsMutex.Unlock()
+
+	key := fmt.Sprintf("%s-%s", name, remotePeerID)
+
+	if channel, exists := ucm.channels[key]; exists {
+		return channel, false
+	}
+
+	channel := &localUnicastChannel{
+		name:               name,
+		manager:            ucm,
+		remotePeerID:       remotePeerID,
+		messageHandlers:    make([]*messageHandler, 0),
+		unmarshalersByType: make(map[string]func() net.TaggedUnmarshaler),
+	}
+	ucm.channels[key] = channel
+
+	return channel, true
+}
+
+func (ucm *unicastChannelManager) onChannelOpened(
+	ctx context.Context,
+	name string,
+	handler func(channel net.UnicastChannel),
+) {
+	ucm.openedHandlersMutex.Lock()
+	defer ucm.openedHandlersMutex.Unlock()
+
+	ucm.openedHandlers = append(
+		ucm.openedHandlers,
+		&unicastChannelOpenedHandler{ctx, name, handler},
+	)
+}
+
+// receive delivers the message sent by the given local provider over the
+// unicast channel with the given name.
+func (ucm *unicastChannelManager) receive(
+	name string,
+	sender *unicastChannelManager,
+	messageType string,
+	payload []byte,
+	seqno uint64,
+) error {
+	ucm.channelsMutex.Lock()
+	channel, exists := ucm.channels[fmt.Sprintf("%s-%s", name, sender.identifier)]
+	ucm.channelsMutex.Unlock()
+
+	if !exists {
+		var openedHandlers []*unicastChannelOpenedHandler
+		ucm.openedHandlersMutex.Lock()
+		for _, handler := range ucm.openedHandlers {
+			if handler.ctx.Err() == nil && handler.name == name {
+				openedHandlers = append(openedHandlers, handler)
+			}
+		}
+		ucm.openedHandlersMutex.Unlock()
+
+		if len(openedHandlers) == 0 {
+			return fmt.Errorf(
+				"no handlers for unicast channel [%v] opened by the peer",
+				name,
+			)
+		}
+
+		var created bool
+		channel, created = ucm.getOrCreateChannel(name, sender.identifier)
+		if created {
+			for _, openedHandler := range openedHandlers {
+				openedHandler.handler(channel)
+			}
+		}
+	}
+
+	return channel.receive(sender, messageType, payload, seqno)
+}
+
+type localUnicastChannel struct {
+	counter uint64
+
+	name         string
+	manager      *unicastChannelManager
+	remotePeerID localIdentifier
+
+	messageHandlersMutex sync.Mutex
+	messageHandlers      []*messageHandler
+
+	unmarshalersMutex  sync.Mutex
+	unmarshalersByType map[string]func() net.TaggedUnmarshaler
+}
+
+func (luc *localUnicastChannel) nextSeqno() uint64 {
+	return atomic.AddUint64(&luc.counter, 1)
+}
+
+func (luc *localUnicastChannel) Name() string {
+	return luc.name
+}
+
+func (luc *localUnicastChannel) RemotePeerID() net.TransportIdentifier {
+	return luc.remotePeerID
+}
+
+func (luc *localUnicastChannel) Send(
+	ctx context.Context,
+	message net.TaggedMarshaler,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	payload, err := message.Marshal()
+	if err != nil {
+		return err
+	}
+
+	remoteManager, ok := getUnicastChannelManager(luc.remotePeerID)
+	if !ok {
+		return fmt.Errorf("peer [%v] is not reachable", luc.remotePeerID)
+	}
+
+	return remoteManager.receive(
+		luc.name,
+		luc.manager,
+		message.Type(),
+		payload,
+		luc.nextSeqno(),
+	)
+}
+
+func (luc *localUnicastChannel) receive(
+	sender *unicastChannelManager,
+	messageType string,
+	payload []byte,
+	seqno uint64,
+) error {
+	luc.unmarshalersMutex.Lock()
+	unmarshaler, found := luc.unmarshalersByType[messageType]
+	luc.unmarshalersMutex.Unlock()
+
+	if !found {
+		return fmt.Errorf("couldn't find unmarshaler for type %s", messageType)
+	}
+
+	unmarshaled := unmarshaler()
+	if err := unmarshaled.Unmarshal(payload); err != nil {
+		return err
+	}
+
+	netMessage := internal.BasicMessage(
+		sender.identifier,
+		unmarshaled,
+		messageType,
+		operator.MarshalUncompressed(sender.operatorPublicKey),
+		seqno,
+	)
+
+	luc.messageHandlersMutex.Lock()
+	snapshot := make([]*messageHandler, len(luc.messageHandlers))
+	copy(snapshot, luc.messageHandlers)
+	luc.messageHandlersMutex.Unlock()
+
+	for _, handler := range snapshot {
+		select {
+		case handler.channel <- netMessage:
+		default:
+			logger.Warnf("handler too slow, dropping message")
+		}
+	}
+
+	return nil
+}
+
+func (luc *localUnicastChannel) Recv(
+	ctx context.Context,
+	handler func(m net.Message),
+) {
+	messageHandler := &messageHandler{
+		ctx:     ctx,
+		channel: make(chan net.Message, messageHandlerThrottle),
+	}
+
+	luc.messageHandlersMutex.Lock()
+	luc.messageHandlers = append(luc.messageHandlers, messageHandler)
+	luc.messageHandlersMutex.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Debug("context is done, removing handler")
+				luc.removeHandler(messageHandler)
+				return
+
+			case msg := <-messageHandler.channel:
+				// The handler must not be called after the context is done.
+				if messageHandler.ctx.Err() != nil {
+					continue
+				}
+
+				handler(msg)
+			}
+		}
+	}()
+}
+
+func (luc *localUnicastChannel) removeHandler(handler *messageHandler) {
+	luc.messageHandlersMutex.Lock()
+	defer luc.messageHandlersMutex.Unlock()
+
+	for i, h := range luc.messageHandlers {
+		if h.channel == handler.channel {
+			luc.messageHandlers[i] = luc.messageHandlers[len(luc.messageHandlers)-1]
+			luc.messageHandlers = luc.messageHandlers[:len(luc.messageHandlers)-1]
+			break
+		}
+	}
+}
+
+func (luc *localUnicastChannel) SetUnmarshaler(
+	unmarshaler func() net.TaggedUnmarshaler,
+) {
+	tpe := unmarshaler().Type()
+
+	luc.unmarshalersMutex.Lock()
+	defer luc.unmarshalersMutex.Unlock()
+
+	luc.unmarshalersByType[tpe] = unmarshaler
+}
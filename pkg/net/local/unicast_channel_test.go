@@ -0,0 +1,154 @@
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/operator"
+)
+
+func TestUnicastSendReceive(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	channelName := "unicast channel"
+
+	receiver, receiverID := connectTestProvider(t)
+	sender, senderID := connectTestProvider(t)
+
+	recvChan := make(chan net.Message, 1)
+	receiver.OnUnicastChannelOpened(
+		ctx,
+		channelName,
+		func(channel net.UnicastChannel) {
+			channel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+				return &mockNetMessage{}
+			})
+			channel.Recv(ctx, func(msg net.Message) {
+				recvChan <- msg
+			})
+		},
+	)
+
+	channel, err := sender.UnicastChannelWith(channelName, receiverID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := channel.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-recvChan:
+		testutils.AssertStringsEqual(
+			t,
+			"message sender",
+			senderID.String(),
+			msg.TransportSenderID().String(),
+		)
+		testutils.AssertStringsEqual(
+			t,
+			"message type",
+			mockNetMessageType,
+			msg.Type(),
+		)
+	case <-ctx.Done():
+		t.Fatal("expected message not received")
+	}
+
+	// The channel opened by the sender should be available to the receiver
+	// so it can reply.
+	reply, err := receiver.UnicastChannelWith(channelName, senderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replyChan := make(chan net.Message, 1)
+	channel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &mockNetMessage{}
+	})
+	channel.Recv(ctx, func(msg net.Message) {
+		replyChan <- msg
+	})
+
+	if err := reply.Send(ctx, &mockNetMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-replyChan:
+		testutils.AssertStringsEqual(
+			t,
+			"reply sender",
+			receiverID.String(),
+			msg.TransportSenderID().String(),
+		)
+	case <-ctx.Done():
+		t.Fatal("expected reply not received")
+	}
+}
+
+func TestUnicastSend_NoHandlers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, receiverID := connectTestProvider(t)
+	sender, _ := connectTestProvider(t)
+
+	channel, err := sender.UnicastChannelWith("unicast channel", receiverID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := channel.Send(ctx, &mockNetMessage{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestUnicastSend_UnknownPeer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	_, unknownPublicKey, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender, _ := connectTestProvider(t)
+
+	unknownID, err := sender.CreateTransportIdentifier(unknownPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel, err := sender.UnicastChannelWith("unicast channel", unknownID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := channel.Send(ctx, &mockNetMessage{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// connectTestProvider connects a new local provider and returns it along with
+// the transport identifier other providers can use to reach it.
+func connectTestProvider(t *testing.T) (Provider, net.TransportIdentifier) {
+	_, operatorPublicKey, err := operator.GenerateKeyPair(DefaultCurve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := ConnectWithKey(operatorPublicKey)
+
+	transportID, err := provider.CreateTransportIdentifier(operatorPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return provider, transportID
+}
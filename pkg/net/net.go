@@ -49,7 +49,8 @@ type TaggedMarshaler interface {
 // Provider represents an entity that can provide network access.
 //
 // Providers expose the ability to get a named BroadcastChannel, the ability to
-// return a provider type, which is an informational string indicating what type
+// get a named UnicastChannel with a remote peer, the ability to return
+// a provider type, which is an informational string indicating what type
 // of provider this is, the list of IP addresses on which it can listen, and
 // known peers from peer discovery mechanims.
 type Provider interface {
@@ -73,6 +74,45 @@ type Provider interface {
 
 	// BroadcastChannelForwarderFor creates a message relay for given channel name.
 	BroadcastChannelForwarderFor(name string)
+
+	// CloseBroadcastChannel closes the broadcast channel with the given name
+	// and releases its resources, including its traffic metrics. The closed
+	// channel instance must no longer be used. A subsequent call to
+	// BroadcastChannelFor with the same name provides a new channel.
+	CloseBroadcastChannel(name string)
+
+	// TrafficMetrics returns counters of the network traffic of broadcast
+	// channels of the provider.
+	TrafficMetrics() *TrafficMetrics
+
+	// PeerReputation returns the reputation of remote peers used to report
+	// misbehaving peers detected by the protocol layer.
+	PeerReputation() PeerReputation
+
+	// UnicastChannelWith provides a unicast channel instance with the given
+	// name, used to exchange messages with the given remote peer. Channels
+	// without message handlers that were not used for a while are released
+	// by the provider. A subsequent call provides a new channel instance
+	// then so, the channel should be obtained again before it is used
+	// after a long break.
+	UnicastChannelWith(
+		name string,
+		remotePeerID TransportIdentifier,
+	) (UnicastChannel, error)
+
+	// OnUnicastChannelOpened registers a handler called when a remote peer
+	// opens a unicast channel with the given name, that is, when the first
+	// message of a channel not yet known by this provider is received. The
+	// handler is called before the message is delivered so it can set
+	// unmarshalers and install message handlers of the channel. The handler
+	// is active for the entire lifetime of the provided context. Messages
+	// of unknown channels are dropped if no handler is active for their
+	// channel name.
+	OnUnicastChannelOpened(
+		ctx context.Context,
+		name string,
+		handler func(channel UnicastChannel),
+	)
 }
 
 // ConnectionManager is an interface which exposes peers a client is connected
@@ -139,6 +179,37 @@ type BroadcastChannel interface {
 	SetFilter(filter BroadcastChannelFilter) error
 }
 
+// UnicastChannel represents a named point-to-point channel with a single
+// remote peer. Messages sent over the channel are delivered only to the remote
+// peer and are protected by the authenticated and encrypted transport
+// connection between the peers. Contrary to BroadcastChannel, messages are
+// not retransmitted so there are no duplicates to filter out.
+type UnicastChannel interface {
+	// Name returns the name of this unicast channel.
+	Name() string
+	// RemotePeerID returns the transport identifier of the remote peer.
+	RemotePeerID() TransportIdentifier
+	// Send function sends a message to the remote peer. Message needs to
+	// conform to the marshalling interface. The function returns once the
+	// remote peer read the message or returns an error if that was not
+	// possible before the provided context is done.
+	Send(ctx context.Context, message TaggedMarshaler) error
+	// Recv installs a message handler that will receive messages from the
+	// channel for the entire lifetime of the provided context.
+	// When the context is done, handler is automatically unregistered and
+	// receives no more messages.
+	Recv(ctx context.Context, handler func(m Message))
+	// SetUnmarshaler set an unmarshaler that will unmarshal a given
+	// type to a concrete object that can be passed to and understood by any
+	// registered message handling functions. The unmarshaler should be a
+	// function that returns a fresh object of type proto.TaggedUnmarshaler,
+	// ready to read in the bytes for an object marked as tpe.
+	//
+	// The string type associated with the unmarshaler is the result of calling
+	// Type() on a raw unmarshaler.
+	SetUnmarshaler(unmarshaler func() TaggedUnmarshaler)
+}
+
 // BroadcastChannelFilter represents a filter which determine if the incoming
 // message should be processed by the receivers. It takes the message author's
 // public key as its argument and returns true if the message should be
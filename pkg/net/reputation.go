@@ -0,0 +1,57 @@
+package net
+
+import "time"
+
+// Misbehavior represents a kind of misbehavior of a remote peer lowering its
+// reputation.
+type Misbehavior int
+
+const (
+	// MalformedMessageMisbehavior denotes a message that could not be decoded
+	// or whose envelope was tampered with.
+	MalformedMessageMisbehavior Misbehavior = iota
+	// UnauthorizedMessageMisbehavior denotes a message rejected by
+	// the filter of the broadcast channel, for example, because its author
+	// is not a member of the group using the channel.
+	UnauthorizedMessageMisbehavior
+	// InvalidMembershipMisbehavior denotes a protocol message whose sender
+	// claimed a group member index not matching its operator key.
+	InvalidMembershipMisbehavior
+)
+
+func (m Misbehavior) String() string {
+	switch m {
+	case MalformedMessageMisbehavior:
+		return "malformed_message"
+	case UnauthorizedMessageMisbehavior:
+		return "unauthorized_message"
+	case InvalidMembershipMisbehavior:
+		return "invalid_membership"
+	default:
+		return "unknown"
+	}
+}
+
+// PeerScore describes the reputation of a single remote peer.
+type PeerScore struct {
+	PeerID string  `json:"peer_id"`
+	Score  float64 `json:"score"`
+	// Misbehaviors holds the decaying counters of misbehaviors of the peer,
+	// keyed by the misbehavior name.
+	Misbehaviors map[string]float64 `json:"misbehaviors,omitempty"`
+	// BannedUntil is the time the ban of the peer expires at. It is nil if
+	// the peer is not banned.
+	BannedUntil *time.Time `json:"banned_until,omitempty"`
+}
+
+// PeerReputation tracks the reputation of remote peers. Peers whose
+// reputation drops too low are disconnected and temporarily banned.
+type PeerReputation interface {
+	// ReportMisbehavior lowers the reputation of the peer with the given
+	// operator public key, in the form returned by Message.SenderPublicKey.
+	ReportMisbehavior(operatorPublicKey []byte, misbehavior Misbehavior)
+
+	// PeerScores returns the reputation of all peers with a non-zero score
+	// and all banned peers.
+	PeerScores() []PeerScore
+}
@@ -0,0 +1,496 @@
+// Package reputation scores remote peers based on their misbehaviors, and
+// disconnects and temporarily bans peers whose score drops too low.
+//
+// The scoring model follows the one of libp2p-pubsub peer scoring: every
+// misbehavior increments a per-peer counter, the score is the weighted sum
+// of squared counters and the counters decay over time so that peers can
+// recover from occasional faults.
+package reputation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-log"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/net"
+)
+
+// bansDirectory is the persistence directory bans are stored in.
+const bansDirectory = "bans"
+
+// Params holds parameters of the peer scoring.
+type Params struct {
+	// MisbehaviorWeights holds the weights of misbehaviors. The weights must
+	// be negative. Misbehaviors without a weight do not affect the score.
+	MisbehaviorWeights map[net.Misbehavior]float64
+
+	// DecayInterval is the interval at which misbehavior counters decay.
+	DecayInterval time.Duration
+	// Decay is the factor misbehavior counters are multiplied by every
+	// decay interval. It must be between 0 and 1.
+	Decay float64
+	// DecayToZero is the counter value below which the counter is
+	// considered 0.
+	DecayToZero float64
+
+	// DisconnectThreshold is the score below which the peer is disconnected.
+	// It must be negative.
+	DisconnectThreshold float64
+	// BanThreshold is the score below which the peer is disconnected and
+	// banned for BanDuration. It must not be greater than
+	// DisconnectThreshold.
+	BanThreshold float64
+	// BanDuration is the duration of a ban.
+	BanDuration time.Duration
+}
+
+// DefaultParams returns the default peer scoring parameters. With the
+// default parameters, a peer is disconnected after 4 malformed messages,
+// 10 unauthorized messages or 2 invalid membership claims in a short period
+// of time, and banned after 7, 20 or 4 of them respectively.
+func DefaultParams() Params {
+	return Params{
+		MisbehaviorWeights: map[net.Misbehavior]float64{
+			net.MalformedMessageMisbehavior:    -10,
+			net.UnauthorizedMessageMisbehavior: -1,
+			net.InvalidMembershipMisbehavior:   -25,
+		},
+		DecayInterval:       time.Minute,
+		Decay:               0.9,
+		DecayToZero:         0.1,
+		DisconnectThreshold: -100,
+		BanThreshold:        -400,
+		BanDuration:         time.Hour,
+	}
+}
+
+func (p Params) validate() error {
+	for misbehavior, weight := range p.MisbehaviorWeights {
+		if weight > 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return fmt.Errorf(
+				"invalid weight of misbehavior [%v]; "+
+					"must be negative and a valid number",
+				misbehavior,
+			)
+		}
+	}
+	if p.DecayInterval <= 0 {
+		return fmt.Errorf("invalid decay interval; must be positive")
+	}
+	if p.Decay <= 0 || p.Decay >= 1 {
+		return fmt.Errorf("invalid decay; must be between 0 and 1")
+	}
+	if p.DecayToZero <= 0 || p.DecayToZero >= 1 {
+		return fmt.Errorf("invalid decay to zero; must be between 0 and 1")
+	}
+	if p.DisconnectThreshold >= 0 {
+		return fmt.Errorf("invalid disconnect threshold; must be negative")
+	}
+	if p.BanThreshold > p.DisconnectThreshold {
+		return fmt.Errorf(
+			"invalid ban threshold; must not be greater than " +
+				"the disconnect threshold",
+		)
+	}
+	if p.BanDuration <= 0 {
+		return fmt.Errorf("invalid ban duration; must be positive")
+	}
+	return nil
+}
+
+// ban is the persisted form of a peer ban.
+type ban struct {
+	PeerID      string    `json:"peer_id"`
+	BannedUntil time.Time `json:"banned_until"`
+}
+
+// Reputation tracks misbehaviors of remote peers identified by their
+// transport identifiers. All functions are thread-safe.
+type Reputation struct {
+	logger log.StandardLogger
+	params Params
+
+	// persistence stores bans so they survive restarts. May be nil in which
+	// case bans are kept in memory only.
+	persistence persistence.BasicHandle
+	disconnect  func(peerID string)
+
+	mutex sync.Mutex
+	// counters holds misbehavior counters keyed by peer ID.
+	counters map[string]map[net.Misbehavior]float64
+	// bans holds expiration times of bans keyed by peer ID.
+	bans map[string]time.Time
+}
+
+// New creates a new instance of Reputation and loads the bans stored in
+// the given persistence handle, if any. The disconnect function is called
+// for peers whose score drops below the disconnect threshold. Counters
+// decay and expired bans are lifted for the entire lifetime of the provided
+// context.
+func New(
+	ctx context.Context,
+	logger log.StandardLogger,
+	params Params,
+	persistence persistence.BasicHandle,
+	disconnect func(peerID string),
+) (*Reputation, error) {
+	if err := params.validate(); err != nil {
+		return nil, fmt.Errorf("invalid peer scoring parameters: [%w]", err)
+	}
+
+	reputation := &Reputation{
+		logger:      logger,
+		params:      params,
+		persistence: persistence,
+		disconnect:  disconnect,
+		counters:    make(map[string]map[net.Misbehavior]float64),
+		bans:        make(map[string]time.Time),
+	}
+
+	if err := reputation.loadBans(time.Now()); err != nil {
+		return nil, fmt.Errorf("could not load peer bans: [%w]", err)
+	}
+
+	go reputation.decayLoop(ctx)
+
+	return reputation, nil
+}
+
+func (r *Reputation) loadBans(now time.Time) error {
+	if r.persistence == nil {
+		return nil
+	}
+
+	descriptors, errors := r.persistence.ReadAll()
+
+	// Two goroutines read from descriptors and errors channels and either
+	// add the ban or record the error. Both goroutines have to complete
+	// before the function returns. Malformed bans are skipped so they do
+	// not prevent the client from starting.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	var expired []string
+	var readErr error
+
+	go func() {
+		defer wg.Done()
+
+		for descriptor := range descriptors {
+			if descriptor.Directory() != bansDirectory {
+				continue
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				r.logger.Errorf(
+					"could not read ban [%v]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				continue
+			}
+
+			var b ban
+			if err := json.Unmarshal(content, &b); err != nil {
+				r.logger.Errorf(
+					"could not unmarshal ban [%v]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				continue
+			}
+
+			if !b.BannedUntil.After(now) {
+				expired = append(expired, b.PeerID)
+				continue
+			}
+
+			r.bans[b.PeerID] = b.BannedUntil
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for err := range errors {
+			readErr = err
+		}
+	}()
+
+	wg.Wait()
+
+	for _, peerID := range expired {
+		r.deleteBan(peerID)
+	}
+
+	if len(r.bans) > 0 {
+		r.logger.Infof("loaded [%v] active peer bans", len(r.bans))
+	}
+
+	return readErr
+}
+
+// ReportMisbehavior lowers the reputation of the given peer. The peer is
+// disconnected if its score drops below the disconnect threshold and
+// additionally banned if its score drops below the ban threshold.
+func (r *Reputation) ReportMisbehavior(
+	peerID string,
+	misbehavior net.Misbehavior,
+) {
+	r.report(peerID, misbehavior, time.Now())
+}
+
+func (r *Reputation) report(
+	peerID string,
+	misbehavior net.Misbehavior,
+	now time.Time,
+) {
+	r.mutex.Lock()
+
+	if r.isBanned(peerID, now) {
+		r.mutex.Unlock()
+		return
+	}
+
+	counters, ok := r.counters[peerID]
+	if !ok {
+		counters = make(map[net.Misbehavior]float64)
+		r.counters[peerID] = counters
+	}
+	counters[misbehavior]++
+
+	score := r.score(counters)
+
+	var banned bool
+	if score <= r.params.BanThreshold {
+		r.ban(peerID, now.Add(r.params.BanDuration))
+		banned = true
+	}
+
+	r.mutex.Unlock()
+
+	if score > r.params.DisconnectThreshold {
+		r.logger.Debugf(
+			"peer [%v] reported for [%v]; score is [%.2f]",
+			peerID,
+			misbehavior,
+			score,
+		)
+		return
+	}
+
+	if banned {
+		r.logger.Warnf(
+			"banning peer [%v] for [%v]; score [%.2f] dropped below "+
+				"the ban threshold after [%v]",
+			peerID,
+			r.params.BanDuration,
+			score,
+			misbehavior,
+		)
+	} else {
+		r.logger.Warnf(
+			"disconnecting peer [%v]; score [%.2f] dropped below "+
+				"the disconnect threshold after [%v]",
+			peerID,
+			score,
+			misbehavior,
+		)
+	}
+
+	if r.disconnect != nil {
+		r.disconnect(peerID)
+	}
+}
+
+// Ban bans the given peer for the ban duration.
+func (r *Reputation) Ban(peerID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.ban(peerID, time.Now().Add(r.params.BanDuration))
+}
+
+// ban must be called with the mutex held.
+func (r *Reputation) ban(peerID string, bannedUntil time.Time) {
+	r.bans[peerID] = bannedUntil
+
+	if r.persistence == nil {
+		return
+	}
+
+	content, err := json.Marshal(&ban{peerID, bannedUntil})
+	if err != nil {
+		r.logger.Errorf("could not marshal ban of peer [%v]: [%v]", peerID, err)
+		return
+	}
+
+	if err := r.persistence.Save(content, bansDirectory, peerID); err != nil {
+		r.logger.Errorf("could not persist ban of peer [%v]: [%v]", peerID, err)
+	}
+}
+
+func (r *Reputation) deleteBan(peerID string) {
+	if r.persistence == nil {
+		return
+	}
+
+	if err := r.persistence.Delete(bansDirectory, peerID); err != nil {
+		r.logger.Errorf(
+			"could not delete persisted ban of peer [%v]: [%v]",
+			peerID,
+			err,
+		)
+	}
+}
+
+// Params returns the peer scoring parameters of the reputation.
+func (r *Reputation) Params() Params {
+	return r.params
+}
+
+// Score returns the current score of the given peer. The score of a banned
+// peer is not greater than the ban threshold.
+func (r *Reputation) Score(peerID string) float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	score := r.score(r.counters[peerID])
+	if r.isBanned(peerID, time.Now()) {
+		score = math.Min(score, r.params.BanThreshold)
+	}
+
+	return score
+}
+
+// IsBanned returns true if the given peer is currently banned.
+func (r *Reputation) IsBanned(peerID string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.isBanned(peerID, time.Now())
+}
+
+// isBanned must be called with the mutex held.
+func (r *Reputation) isBanned(peerID string, now time.Time) bool {
+	bannedUntil, ok := r.bans[peerID]
+	return ok && bannedUntil.After(now)
+}
+
+// score must be called with the mutex held.
+func (r *Reputation) score(counters map[net.Misbehavior]float64) float64 {
+	score := 0.0
+	for misbehavior, counter := range counters {
+		score += r.params.MisbehaviorWeights[misbehavior] * counter * counter
+	}
+	return score
+}
+
+func (r *Reputation) decayLoop(ctx context.Context) {
+	ticker := time.NewTicker(r.params.DecayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.refresh(now)
+		}
+	}
+}
+
+// refresh decays misbehavior counters and lifts expired bans.
+func (r *Reputation) refresh(now time.Time) {
+	r.mutex.Lock()
+
+	for peerID, counters := range r.counters {
+		for misbehavior, counter := range counters {
+			counter *= r.params.Decay
+			if counter < r.params.DecayToZero {
+				delete(counters, misbehavior)
+				continue
+			}
+			counters[misbehavior] = counter
+		}
+
+		if len(counters) == 0 {
+			delete(r.counters, peerID)
+		}
+	}
+
+	var expired []string
+	for peerID, bannedUntil := range r.bans {
+		if !bannedUntil.After(now) {
+			delete(r.bans, peerID)
+			expired = append(expired, peerID)
+		}
+	}
+
+	r.mutex.Unlock()
+
+	for _, peerID := range expired {
+		r.logger.Infof("ban of peer [%v] expired", peerID)
+		r.deleteBan(peerID)
+	}
+}
+
+// PeerScores returns the reputation of all peers with non-zero misbehavior
+// counters and all banned peers, the lowest scores first.
+func (r *Reputation) PeerScores() []net.PeerScore {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+
+	scores := make(map[string]*net.PeerScore)
+	getScore := func(peerID string) *net.PeerScore {
+		score, ok := scores[peerID]
+		if !ok {
+			score = &net.PeerScore{PeerID: peerID}
+			scores[peerID] = score
+		}
+		return score
+	}
+
+	for peerID, counters := range r.counters {
+		score := getScore(peerID)
+		score.Score = r.score(counters)
+		score.Misbehaviors = make(map[string]float64, len(counters))
+		for misbehavior, counter := range counters {
+			score.Misbehaviors[misbehavior.String()] = counter
+		}
+	}
+
+	for peerID, bannedUntil := range r.bans {
+		if !bannedUntil.After(now) {
+			continue
+		}
+
+		bannedUntil := bannedUntil
+		getScore(peerID).BannedUntil = &bannedUntil
+	}
+
+	result := make([]net.PeerScore, 0, len(scores))
+	for _, score := range scores {
+		result = append(result, *score)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score < result[j].Score
+		}
+		return result[i].PeerID < result[j].PeerID
+	})
+
+	return result
+}
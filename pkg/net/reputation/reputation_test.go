@@ -0,0 +1,421 @@
+package reputation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/net"
+)
+
+func TestReputation_ReportMisbehavior(t *testing.T) {
+	var tests = map[string]struct {
+		misbehavior          net.Misbehavior
+		reports              int
+		expectedScore        float64
+		expectedDisconnected bool
+		expectedBanned       bool
+	}{
+		"score above the disconnect threshold": {
+			misbehavior:   net.MalformedMessageMisbehavior,
+			reports:       3,
+			expectedScore: -90,
+		},
+		"score below the disconnect threshold": {
+			misbehavior:          net.MalformedMessageMisbehavior,
+			reports:              4,
+			expectedScore:        -160,
+			expectedDisconnected: true,
+		},
+		"score below the ban threshold": {
+			misbehavior:          net.InvalidMembershipMisbehavior,
+			reports:              4,
+			expectedScore:        -400,
+			expectedDisconnected: true,
+			expectedBanned:       true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			reputation, disconnected := newTestReputation(t, nil)
+
+			for i := 0; i < test.reports; i++ {
+				reputation.ReportMisbehavior("peer-1", test.misbehavior)
+			}
+
+			scores := reputation.PeerScores()
+			testutils.AssertIntsEqual(t, "peer scores count", 1, len(scores))
+			testutils.AssertStringsEqual(
+				t,
+				"peer ID",
+				"peer-1",
+				scores[0].PeerID,
+			)
+			if test.expectedScore != scores[0].Score {
+				t.Errorf(
+					"unexpected score\nexpected: [%v]\nactual:   [%v]",
+					test.expectedScore,
+					scores[0].Score,
+				)
+			}
+
+			testutils.AssertBoolsEqual(
+				t,
+				"disconnected",
+				test.expectedDisconnected,
+				disconnected.contains("peer-1"),
+			)
+			testutils.AssertBoolsEqual(
+				t,
+				"banned",
+				test.expectedBanned,
+				reputation.IsBanned("peer-1"),
+			)
+			testutils.AssertBoolsEqual(
+				t,
+				"banned until set",
+				test.expectedBanned,
+				scores[0].BannedUntil != nil,
+			)
+		})
+	}
+}
+
+func TestReputation_Score(t *testing.T) {
+	reputation, _ := newTestReputation(t, nil)
+
+	reputation.ReportMisbehavior("peer-1", net.MalformedMessageMisbehavior)
+	reputation.ReportMisbehavior("peer-1", net.MalformedMessageMisbehavior)
+	reputation.Ban("peer-2")
+
+	var tests = map[string]struct {
+		peerID        string
+		expectedScore float64
+	}{
+		"reported peer": {
+			peerID:        "peer-1",
+			expectedScore: -40,
+		},
+		"banned peer": {
+			peerID:        "peer-2",
+			expectedScore: DefaultParams().BanThreshold,
+		},
+		"unknown peer": {
+			peerID:        "peer-3",
+			expectedScore: 0,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			score := reputation.Score(test.peerID)
+			if test.expectedScore != score {
+				t.Errorf(
+					"unexpected score\nexpected: [%v]\nactual:   [%v]",
+					test.expectedScore,
+					score,
+				)
+			}
+		})
+	}
+}
+
+func TestReputation_Refresh(t *testing.T) {
+	reputation, _ := newTestReputation(t, nil)
+
+	now := time.Now()
+
+	reputation.report("peer-1", net.MalformedMessageMisbehavior, now)
+	reputation.report("peer-2", net.InvalidMembershipMisbehavior, now)
+	reputation.Ban("peer-3")
+
+	// Counters are 0.9 after the first decay, so the score of the first peer
+	// is -10 * 0.9^2.
+	reputation.refresh(now)
+
+	scores := reputation.PeerScores()
+	testutils.AssertIntsEqual(t, "peer scores count", 3, len(scores))
+	testutils.AssertStringsEqual(t, "first peer", "peer-2", scores[0].PeerID)
+	testutils.AssertStringsEqual(t, "second peer", "peer-1", scores[1].PeerID)
+	testutils.AssertStringsEqual(t, "third peer", "peer-3", scores[2].PeerID)
+
+	expectedMisbehaviors := map[string]float64{"malformed_message": 0.9}
+	if !reflect.DeepEqual(expectedMisbehaviors, scores[1].Misbehaviors) {
+		t.Errorf(
+			"unexpected misbehaviors\nexpected: [%v]\nactual:   [%v]",
+			expectedMisbehaviors,
+			scores[1].Misbehaviors,
+		)
+	}
+
+	// Counters decay to zero and the ban expires.
+	for i := 0; i < 30; i++ {
+		reputation.refresh(now.Add(2 * time.Hour))
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"peer scores count",
+		0,
+		len(reputation.PeerScores()),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"banned",
+		false,
+		reputation.IsBanned("peer-3"),
+	)
+}
+
+func TestReputation_BannedPeerNotScored(t *testing.T) {
+	reputation, disconnected := newTestReputation(t, nil)
+
+	reputation.Ban("peer-1")
+	reputation.ReportMisbehavior("peer-1", net.InvalidMembershipMisbehavior)
+
+	scores := reputation.PeerScores()
+	testutils.AssertIntsEqual(t, "peer scores count", 1, len(scores))
+	if scores[0].Score != 0 {
+		t.Errorf("unexpected score [%v]", scores[0].Score)
+	}
+	testutils.AssertBoolsEqual(
+		t,
+		"disconnected",
+		false,
+		disconnected.contains("peer-1"),
+	)
+}
+
+func TestReputation_PersistedBans(t *testing.T) {
+	handle := newMockPersistenceHandle()
+
+	reputation, _ := newTestReputation(t, handle)
+
+	for i := 0; i < 4; i++ {
+		reputation.ReportMisbehavior("peer-1", net.InvalidMembershipMisbehavior)
+	}
+	reputation.Ban("peer-2")
+
+	testutils.AssertIntsEqual(t, "persisted bans count", 2, handle.count())
+
+	// Bans survive the restart.
+	restarted, _ := newTestReputation(t, handle)
+
+	testutils.AssertBoolsEqual(
+		t,
+		"peer 1 banned",
+		true,
+		restarted.IsBanned("peer-1"),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"peer 2 banned",
+		true,
+		restarted.IsBanned("peer-2"),
+	)
+
+	// Expired bans are removed from the persistence.
+	restarted.refresh(time.Now().Add(2 * time.Hour))
+
+	testutils.AssertIntsEqual(t, "persisted bans count", 0, handle.count())
+}
+
+func TestReputation_PersistedBans_Expired(t *testing.T) {
+	handle := newMockPersistenceHandle()
+
+	handle.storage[bansDirectory+"/peer-1"] = []byte(fmt.Sprintf(
+		`{"peer_id":"peer-1","banned_until":"%s"}`,
+		time.Now().Add(-time.Minute).Format(time.RFC3339),
+	))
+	handle.storage[bansDirectory+"/peer-2"] = []byte("malformed")
+
+	reputation, _ := newTestReputation(t, handle)
+
+	testutils.AssertBoolsEqual(
+		t,
+		"peer 1 banned",
+		false,
+		reputation.IsBanned("peer-1"),
+	)
+	// The expired ban is removed, the malformed one is skipped.
+	testutils.AssertIntsEqual(t, "persisted bans count", 1, handle.count())
+}
+
+func TestNew_InvalidParams(t *testing.T) {
+	var tests = map[string]struct {
+		updateFn      func(params *Params)
+		expectedError string
+	}{
+		"positive weight": {
+			updateFn: func(params *Params) {
+				params.MisbehaviorWeights[net.MalformedMessageMisbehavior] = 1
+			},
+			expectedError: "invalid weight of misbehavior [malformed_message]",
+		},
+		"decay out of range": {
+			updateFn: func(params *Params) {
+				params.Decay = 1
+			},
+			expectedError: "invalid decay",
+		},
+		"positive disconnect threshold": {
+			updateFn: func(params *Params) {
+				params.DisconnectThreshold = 1
+			},
+			expectedError: "invalid disconnect threshold",
+		},
+		"ban threshold above the disconnect threshold": {
+			updateFn: func(params *Params) {
+				params.BanThreshold = params.DisconnectThreshold + 1
+			},
+			expectedError: "invalid ban threshold",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			params := DefaultParams()
+			test.updateFn(&params)
+
+			_, err := New(
+				context.Background(),
+				&testutils.MockLogger{},
+				params,
+				nil,
+				nil,
+			)
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func newTestReputation(
+	t *testing.T,
+	handle persistence.BasicHandle,
+) (*Reputation, *disconnectedPeers) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	disconnected := &disconnectedPeers{peers: make(map[string]bool)}
+
+	reputation, err := New(
+		ctx,
+		&testutils.MockLogger{},
+		DefaultParams(),
+		handle,
+		disconnected.disconnect,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return reputation, disconnected
+}
+
+type disconnectedPeers struct {
+	mutex sync.Mutex
+	peers map[string]bool
+}
+
+func (dp *disconnectedPeers) disconnect(peerID string) {
+	dp.mutex.Lock()
+	defer dp.mutex.Unlock()
+
+	dp.peers[peerID] = true
+}
+
+func (dp *disconnectedPeers) contains(peerID string) bool {
+	dp.mutex.Lock()
+	defer dp.mutex.Unlock()
+
+	return dp.peers[peerID]
+}
+
+type mockPersistenceHandle struct {
+	mutex   sync.Mutex
+	storage map[string][]byte
+}
+
+func newMockPersistenceHandle() *mockPersistenceHandle {
+	return &mockPersistenceHandle{storage: make(map[string][]byte)}
+}
+
+func (mph *mockPersistenceHandle) Save(
+	data []byte,
+	directory string,
+	name string,
+) error {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	mph.storage[directory+"/"+name] = data
+	return nil
+}
+
+func (mph *mockPersistenceHandle) Delete(directory string, name string) error {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	delete(mph.storage, directory+"/"+name)
+	return nil
+}
+
+func (mph *mockPersistenceHandle) ReadAll() (
+	<-chan persistence.DataDescriptor,
+	<-chan error,
+) {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	descriptors := make(chan persistence.DataDescriptor, len(mph.storage))
+	errors := make(chan error)
+
+	for path, content := range mph.storage {
+		elements := strings.SplitN(path, "/", 2)
+		descriptors <- &mockDataDescriptor{elements[0], elements[1], content}
+	}
+
+	close(descriptors)
+	close(errors)
+
+	return descriptors, errors
+}
+
+func (mph *mockPersistenceHandle) count() int {
+	mph.mutex.Lock()
+	defer mph.mutex.Unlock()
+
+	return len(mph.storage)
+}
+
+type mockDataDescriptor struct {
+	directory string
+	name      string
+	content   []byte
+}
+
+func (mdd *mockDataDescriptor) Name() string {
+	return mdd.name
+}
+
+func (mdd *mockDataDescriptor) Directory() string {
+	return mdd.directory
+}
+
+func (mdd *mockDataDescriptor) Content() ([]byte, error) {
+	return mdd.content, nil
+}
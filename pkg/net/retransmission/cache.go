@@ -0,0 +1,233 @@
+package retransmission
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultCacheCapacity is the maximum number of messages remembered by
+// a single handler created with WithRetransmissionSupport. When the capacity
+// is exceeded, the least recently received message is forgotten.
+const DefaultCacheCapacity = 8192
+
+// CacheMetrics aggregates statistics of all retransmission caches sharing
+// the same Ticker. All functions are thread-safe.
+type CacheMetrics struct {
+	// Counters are updated atomically and must be declared at the top of
+	// the struct!
+	// See: https://golang.org/pkg/sync/atomic/#pkg-note-BUG
+	hits        uint64
+	misses      uint64
+	evictions   uint64
+	expirations uint64
+	size        int64
+}
+
+// Hits returns the number of received messages recognized as
+// retransmissions of already received messages.
+func (cm *CacheMetrics) Hits() uint64 {
+	return atomic.LoadUint64(&cm.hits)
+}
+
+// Misses returns the number of received messages that were not seen before.
+func (cm *CacheMetrics) Misses() uint64 {
+	return atomic.LoadUint64(&cm.misses)
+}
+
+// HitRate returns the ratio of hits to all received messages. Returns zero
+// if no messages were received yet.
+func (cm *CacheMetrics) HitRate() float64 {
+	hits := cm.Hits()
+	total := hits + cm.Misses()
+	if total == 0 {
+		return 0
+	}
+
+	return float64(hits) / float64(total)
+}
+
+// Evictions returns the number of messages forgotten before their
+// retransmission window ended because a cache reached its capacity.
+func (cm *CacheMetrics) Evictions() uint64 {
+	return atomic.LoadUint64(&cm.evictions)
+}
+
+// Expirations returns the number of messages forgotten because their
+// retransmission window ended.
+func (cm *CacheMetrics) Expirations() uint64 {
+	return atomic.LoadUint64(&cm.expirations)
+}
+
+// Size returns the number of messages currently remembered by all caches.
+func (cm *CacheMetrics) Size() int64 {
+	return atomic.LoadInt64(&cm.size)
+}
+
+// messageKey identifies a received message by its sender and sequence number.
+type messageKey struct {
+	senderID string
+	seqno    uint64
+}
+
+// cacheEntry holds information about a single received message.
+type cacheEntry struct {
+	key           messageKey
+	firstSeenTick uint64
+	lastSeenTick  uint64
+}
+
+// cache remembers received messages for the duration of their
+// retransmission windows. The cache is bounded by its capacity; if it is
+// exceeded, the least recently received message is forgotten. The window of
+// a message ended when all the given strategies report so, as the receiver
+// does not know which strategy is used by the sender. The cache is
+// thread-safe.
+//
+// Senders may keep retransmitting a message after its window ended or after
+// it was evicted. Sequence numbers of messages sent by the given sender
+// increase so, for each sender, the cache remembers the highest sequence
+// number of forgotten messages. Messages of the sender having the same or
+// lower sequence number are considered retransmissions of already received
+// messages.
+type cache struct {
+	mutex sync.Mutex
+
+	capacity   int
+	strategies []Strategy
+	metrics    *CacheMetrics
+
+	currentTick uint64
+	closed      bool
+
+	entries map[messageKey]*list.Element
+	// order holds entries sorted from the most to the least recently seen.
+	order *list.List
+	// forgottenSeqnos holds the highest sequence number of forgotten
+	// messages, keyed by the sender ID.
+	forgottenSeqnos map[string]uint64
+}
+
+func newCache(
+	capacity int,
+	strategies []Strategy,
+	metrics *CacheMetrics,
+) *cache {
+	return &cache{
+		capacity:        capacity,
+		strategies:      strategies,
+		metrics:         metrics,
+		entries:         make(map[messageKey]*list.Element),
+		order:           list.New(),
+		forgottenSeqnos: make(map[string]uint64),
+	}
+}
+
+// seen records the message with the given sender ID and sequence number as
+// received and returns true if the message was already received before.
+// That is the case if the message is remembered or if the sender's message
+// with the same or higher sequence number was already forgotten.
+func (c *cache) seen(senderID string, seqno uint64) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := messageKey{senderID: senderID, seqno: seqno}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*cacheEntry).lastSeenTick = c.currentTick
+		c.order.MoveToFront(element)
+
+		atomic.AddUint64(&c.metrics.hits, 1)
+		return true
+	}
+
+	if forgottenSeqno, ok := c.forgottenSeqnos[senderID]; ok &&
+		seqno <= forgottenSeqno {
+		atomic.AddUint64(&c.metrics.hits, 1)
+		return true
+	}
+
+	atomic.AddUint64(&c.metrics.misses, 1)
+
+	// Messages received after the cache was closed are not remembered
+	// so they do not affect the size metric.
+	if c.closed {
+		return false
+	}
+
+	if c.order.Len() >= c.capacity {
+		c.forget(c.order.Back())
+		atomic.AddUint64(&c.metrics.evictions, 1)
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{
+		key:           key,
+		firstSeenTick: c.currentTick,
+		lastSeenTick:  c.currentTick,
+	})
+	atomic.AddInt64(&c.metrics.size, 1)
+
+	return false
+}
+
+// tick advances the cache clock and forgets messages whose retransmission
+// windows ended.
+func (c *cache) tick() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.currentTick++
+
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+
+		if c.windowEnded(element.Value.(*cacheEntry)) {
+			c.forget(element)
+			atomic.AddUint64(&c.metrics.expirations, 1)
+		}
+
+		element = next
+	}
+}
+
+// windowEnded returns true if the retransmission window of the message
+// ended for all strategies.
+func (c *cache) windowEnded(entry *cacheEntry) bool {
+	for _, strategy := range c.strategies {
+		if !strategy.WindowEnded(
+			c.currentTick-entry.firstSeenTick,
+			c.currentTick-entry.lastSeenTick,
+		) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// close forgets all messages and makes the cache stop remembering new ones.
+func (c *cache) close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	atomic.AddInt64(&c.metrics.size, -int64(c.order.Len()))
+
+	c.closed = true
+	c.entries = make(map[messageKey]*list.Element)
+	c.order.Init()
+	c.forgottenSeqnos = make(map[string]uint64)
+}
+
+// forget removes the given entry from the cache and records its sequence
+// number as forgotten for the sender.
+func (c *cache) forget(element *list.Element) {
+	key := element.Value.(*cacheEntry).key
+
+	c.order.Remove(element)
+	delete(c.entries, key)
+	atomic.AddInt64(&c.metrics.size, -1)
+
+	if key.seqno > c.forgottenSeqnos[key.senderID] {
+		c.forgottenSeqnos[key.senderID] = key.seqno
+	}
+}
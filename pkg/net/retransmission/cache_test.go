@@ -0,0 +1,78 @@
+package retransmission
+
+import (
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+func TestCacheEviction(t *testing.T) {
+	metrics := &CacheMetrics{}
+	cache := newCache(2, []Strategy{WithStandardStrategy()}, metrics)
+
+	cache.seen("a", 1)
+	cache.seen("a", 2)
+	// Refresh "a-1" so "a-2" becomes the least recently seen message.
+	cache.seen("a", 1)
+	cache.seen("a", 3)
+
+	testutils.AssertBoolsEqual(t, "a-1 seen", true, cache.seen("a", 1))
+	testutils.AssertBoolsEqual(t, "a-3 seen", true, cache.seen("a", 3))
+	// The evicted message must still be recognized as seen.
+	testutils.AssertBoolsEqual(t, "a-2 seen", true, cache.seen("a", 2))
+	// Messages of other senders are not affected by the eviction.
+	testutils.AssertBoolsEqual(t, "b-1 seen", false, cache.seen("b", 1))
+
+	testutils.AssertIntsEqual(t, "size", 2, int(metrics.Size()))
+	testutils.AssertIntsEqual(t, "evictions", 2, int(metrics.Evictions()))
+	testutils.AssertIntsEqual(t, "hits", 4, int(metrics.Hits()))
+	testutils.AssertIntsEqual(t, "misses", 4, int(metrics.Misses()))
+
+	if metrics.HitRate() != 0.5 {
+		t.Errorf("unexpected hit rate: [%v]", metrics.HitRate())
+	}
+}
+
+func TestCacheExpiration(t *testing.T) {
+	metrics := &CacheMetrics{}
+	cache := newCache(
+		DefaultCacheCapacity,
+		[]Strategy{WithStandardStrategy()},
+		metrics,
+	)
+
+	cache.seen("a", 1)
+	cache.seen("b", 1)
+
+	for i := 0; i < windowEndTolerance; i++ {
+		cache.tick()
+		// Keep "b-1" alive by receiving its retransmissions.
+		cache.seen("b", 1)
+	}
+
+	cache.tick()
+
+	testutils.AssertIntsEqual(t, "size", 1, int(metrics.Size()))
+	testutils.AssertIntsEqual(t, "expirations", 1, int(metrics.Expirations()))
+	testutils.AssertBoolsEqual(t, "b-1 seen", true, cache.seen("b", 1))
+	// The expired message must still be recognized as seen.
+	testutils.AssertBoolsEqual(t, "a-1 seen", true, cache.seen("a", 1))
+	// Newer messages of the sender must not be affected by the expiration.
+	testutils.AssertBoolsEqual(t, "a-2 seen", false, cache.seen("a", 2))
+}
+
+func TestCacheClose(t *testing.T) {
+	metrics := &CacheMetrics{}
+	cache := newCache(
+		DefaultCacheCapacity,
+		[]Strategy{WithStandardStrategy()},
+		metrics,
+	)
+
+	cache.seen("a", 1)
+	cache.close()
+	cache.seen("b", 1)
+
+	testutils.AssertIntsEqual(t, "size", 0, int(metrics.Size()))
+	testutils.AssertBoolsEqual(t, "b-1 seen", false, cache.seen("b", 1))
+}
@@ -6,8 +6,6 @@ package retransmission
 
 import (
 	"context"
-	"fmt"
-	"sync"
 
 	"github.com/ipfs/go-log"
 
@@ -51,25 +49,39 @@ func ScheduleRetransmissions(
 // number. Two messages with the same sender ID and sequence number are
 // considered the same. Handler can not be reused between channels if sequence
 // number of message is local for channel.
-func WithRetransmissionSupport(delegate func(m net.Message)) func(m net.Message) {
-	mutex := &sync.Mutex{}
-	cache := make(map[string]bool)
+//
+// Received messages are remembered in a cache of DefaultCacheCapacity
+// entries until their retransmission windows end. Windows are measured
+// in ticks of the provided Ticker. Retransmissions received once the message
+// was forgotten are still filtered out as sequence numbers of messages sent
+// by the given sender increase. When the provided context is done, the
+// cache is released. Metrics of the cache are aggregated by the Ticker.
+func WithRetransmissionSupport(
+	ctx context.Context,
+	ticker *Ticker,
+	delegate func(m net.Message),
+) func(m net.Message) {
+	cache := newCache(
+		DefaultCacheCapacity,
+		// The receiver does not know the strategy used by the sender so
+		// messages are remembered as long as any strategy may retransmit
+		// them.
+		[]Strategy{WithStandardStrategy(), WithBackoffStrategy()},
+		ticker.cacheMetrics,
+	)
+
+	ticker.onTick(ctx, cache.tick)
+
+	go func() {
+		<-ctx.Done()
+		cache.close()
+	}()
 
 	return func(message net.Message) {
-		messageID := fmt.Sprintf(
-			"%v-%v",
+		if !cache.seen(
 			message.TransportSenderID().String(),
 			message.Seqno(),
-		)
-
-		mutex.Lock()
-		_, seen := cache[messageID]
-		if !seen {
-			cache[messageID] = true
-		}
-		mutex.Unlock()
-
-		if !seen {
+		) {
 			delegate(message)
 		}
 	}
@@ -36,9 +36,16 @@ func TestRetransmitExpectedNumberOfTimes(t *testing.T) {
 func TestHandlerReceiveUniqueMessages(t *testing.T) {
 	var received []net.Message
 
-	handler := WithRetransmissionSupport(func(message net.Message) {
-		received = append(received, message)
-	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handler := WithRetransmissionSupport(
+		ctx,
+		NewTicker(make(chan uint64)),
+		func(message net.Message) {
+			received = append(received, message)
+		},
+	)
 
 	handler(&mockNetworkMessage{senderID: "a", seqno: 1})
 	handler(&mockNetworkMessage{senderID: "a", seqno: 2})
@@ -57,9 +64,16 @@ func TestHandlerReceiveUniqueMessages(t *testing.T) {
 func TestHandlerReceiveRetransmissions(t *testing.T) {
 	var received []net.Message
 
-	handler := WithRetransmissionSupport(func(message net.Message) {
-		received = append(received, message)
-	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handler := WithRetransmissionSupport(
+		ctx,
+		NewTicker(make(chan uint64)),
+		func(message net.Message) {
+			received = append(received, message)
+		},
+	)
 
 	handler(&mockNetworkMessage{senderID: "a", seqno: 1})
 	handler(&mockNetworkMessage{senderID: "a", seqno: 2})
@@ -77,6 +91,99 @@ func TestHandlerReceiveRetransmissions(t *testing.T) {
 	}
 }
 
+func TestHandlerReceiveRetransmissionsAfterWindowEnded(t *testing.T) {
+	var received []net.Message
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticks := make(chan uint64)
+	ticker := NewTicker(ticks)
+
+	handler := WithRetransmissionSupport(
+		ctx,
+		ticker,
+		func(message net.Message) {
+			received = append(received, message)
+		},
+	)
+
+	handler(&mockNetworkMessage{senderID: "a", seqno: 1})
+	handler(&mockNetworkMessage{senderID: "a", seqno: 1})
+
+	// The window of the backoff strategy ends after the message has not
+	// been received for more than the tolerance.
+	for i := uint64(1); i <= windowEndTolerance+1; i++ {
+		ticks <- i
+	}
+	// Make sure the last tick has been processed.
+	ticks <- windowEndTolerance + 2
+
+	testutils.AssertIntsEqual(
+		t,
+		"cache size",
+		0,
+		int(ticker.CacheMetrics().Size()),
+	)
+
+	// Retransmissions are not delivered once the window ended while
+	// newer messages of the sender are.
+	handler(&mockNetworkMessage{senderID: "a", seqno: 1})
+	handler(&mockNetworkMessage{senderID: "a", seqno: 2})
+
+	testutils.AssertIntsEqual(t, "received messages", 2, len(received))
+	testutils.AssertIntsEqual(
+		t,
+		"received message seqno",
+		2,
+		int(received[1].Seqno()),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"cache hits",
+		2,
+		int(ticker.CacheMetrics().Hits()),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"cache expirations",
+		1,
+		int(ticker.CacheMetrics().Expirations()),
+	)
+}
+
+func TestHandlerReleaseCacheWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ticker := NewTicker(make(chan uint64))
+
+	handler := WithRetransmissionSupport(
+		ctx,
+		ticker,
+		func(message net.Message) {},
+	)
+
+	handler(&mockNetworkMessage{senderID: "a", seqno: 1})
+	handler(&mockNetworkMessage{senderID: "a", seqno: 2})
+
+	testutils.AssertIntsEqual(
+		t,
+		"cache size",
+		2,
+		int(ticker.CacheMetrics().Size()),
+	)
+
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+
+	testutils.AssertIntsEqual(
+		t,
+		"cache size",
+		0,
+		int(ticker.CacheMetrics().Size()),
+	)
+}
+
 type mockNetworkMessage struct {
 	senderID string
 	seqno    uint64
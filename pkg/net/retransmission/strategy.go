@@ -8,8 +8,21 @@ type Strategy interface {
 	// The strategy uses their internal state and logic to decide whether to
 	// call the retransmission function or not.
 	Tick(retransmitFn RetransmitFn) error
+
+	// WindowEnded is used on the receiver side and reports whether the
+	// retransmission window of a message sent using the strategy ended,
+	// given the number of ticks elapsed since the message was received for
+	// the first time and since it was received for the last time. Once the
+	// window ended, no more retransmissions of the message are expected.
+	WindowEnded(ticksSinceFirstSeen uint64, ticksSinceLastSeen uint64) bool
 }
 
+// windowEndTolerance is the number of ticks a receiver waits for another
+// retransmission of a message on top of the delay following from the
+// strategy. The tolerance accounts for ticks of the sender and the receiver
+// not being perfectly synced and for message propagation delays.
+const windowEndTolerance = 5
+
 // WithStrategy is a strategy factory function that returns the requested
 // strategy instance.
 func WithStrategy(strategy net.RetransmissionStrategy) Strategy {
@@ -38,6 +51,16 @@ func (ss *StandardStrategy) Tick(retransmitFn RetransmitFn) error {
 	return retransmitFn()
 }
 
+// WindowEnded implements the Strategy.WindowEnded function. The message is
+// retransmitted on every tick so the window ended if the message has not been
+// received for more ticks than the tolerance.
+func (ss *StandardStrategy) WindowEnded(
+	ticksSinceFirstSeen uint64,
+	ticksSinceLastSeen uint64,
+) bool {
+	return ticksSinceLastSeen > windowEndTolerance
+}
+
 // BackoffStrategy is a retransmission strategy that triggers the retransmission
 // routine with an exponentially increasing delay. That is, the delay between
 // first and second retransmission is 1 tick, between second and third is 2
@@ -72,3 +95,20 @@ func (bos *BackoffStrategy) Tick(retransmitFn RetransmitFn) error {
 
 	return nil
 }
+
+// WindowEnded implements the Strategy.WindowEnded function. The delay between
+// retransmissions is at most one tick longer than the number of ticks elapsed
+// since the first transmission. The window ended if the message has not been
+// received for longer than the period between its first and last receipt,
+// plus the tolerance.
+func (bos *BackoffStrategy) WindowEnded(
+	ticksSinceFirstSeen uint64,
+	ticksSinceLastSeen uint64,
+) bool {
+	if ticksSinceLastSeen > ticksSinceFirstSeen {
+		return true
+	}
+
+	return ticksSinceLastSeen >
+		ticksSinceFirstSeen-ticksSinceLastSeen+windowEndTolerance
+}
@@ -77,3 +77,95 @@ func TestBackoffStrategy(t *testing.T) {
 		)
 	}
 }
+
+func TestStrategiesWindowNotEndedBetweenRetransmissions(t *testing.T) {
+	var tests = map[string]struct {
+		strategy Strategy
+	}{
+		"standard strategy": {
+			strategy: WithStandardStrategy(),
+		},
+		"backoff strategy": {
+			strategy: WithBackoffStrategy(),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			// The receiver gets the message for the first time at tick 0,
+			// when the message is sent.
+			lastSeenTick := uint64(0)
+
+			for tick := uint64(1); tick <= 100; tick++ {
+				if test.strategy.WindowEnded(tick, tick-lastSeenTick) {
+					t.Fatalf("window ended before retransmission at tick [%v]", tick)
+				}
+
+				err := test.strategy.Tick(func() error {
+					lastSeenTick = tick
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestStrategiesWindowEnded(t *testing.T) {
+	var tests = map[string]struct {
+		strategy            Strategy
+		ticksSinceFirstSeen uint64
+		ticksSinceLastSeen  uint64
+		expectedEnded       bool
+	}{
+		"standard strategy - within tolerance": {
+			strategy:            WithStandardStrategy(),
+			ticksSinceFirstSeen: 50,
+			ticksSinceLastSeen:  windowEndTolerance,
+			expectedEnded:       false,
+		},
+		"standard strategy - beyond tolerance": {
+			strategy:            WithStandardStrategy(),
+			ticksSinceFirstSeen: 50,
+			ticksSinceLastSeen:  windowEndTolerance + 1,
+			expectedEnded:       true,
+		},
+		"backoff strategy - within expected delay": {
+			strategy:            WithBackoffStrategy(),
+			ticksSinceFirstSeen: 70,
+			ticksSinceLastSeen:  35,
+			expectedEnded:       false,
+		},
+		"backoff strategy - beyond expected delay": {
+			strategy:            WithBackoffStrategy(),
+			ticksSinceFirstSeen: 60,
+			ticksSinceLastSeen:  40,
+			expectedEnded:       true,
+		},
+		"backoff strategy - seen once": {
+			strategy:            WithBackoffStrategy(),
+			ticksSinceFirstSeen: windowEndTolerance + 1,
+			ticksSinceLastSeen:  windowEndTolerance + 1,
+			expectedEnded:       true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ended := test.strategy.WindowEnded(
+				test.ticksSinceFirstSeen,
+				test.ticksSinceLastSeen,
+			)
+
+			if ended != test.expectedEnded {
+				t.Errorf(
+					"unexpected result\nexpected: [%v]\nactual:   [%v]",
+					test.expectedEnded,
+					ended,
+				)
+			}
+		})
+	}
+}
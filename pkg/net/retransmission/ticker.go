@@ -13,6 +13,10 @@ type Ticker struct {
 	handlersMutex sync.Mutex
 	handlers      map[uint64]*handler
 	nextHandlerId uint64
+
+	// cacheMetrics aggregates metrics of all retransmission caches driven
+	// by the ticker.
+	cacheMetrics *CacheMetrics
 }
 
 type handler struct {
@@ -25,8 +29,9 @@ type handler struct {
 // are unregistered and ticker is stopped when the provided channel gets closed.
 func NewTicker(ticks <-chan uint64) *Ticker {
 	ticker := &Ticker{
-		ticks:    ticks,
-		handlers: make(map[uint64]*handler),
+		ticks:        ticks,
+		handlers:     make(map[uint64]*handler),
+		cacheMetrics: &CacheMetrics{},
 	}
 
 	go ticker.start()
@@ -59,6 +64,12 @@ func NewTimeTicker(ctx context.Context, duration time.Duration) *Ticker {
 	return NewTicker(ticks)
 }
 
+// CacheMetrics returns aggregated metrics of all retransmission caches of
+// handlers created by WithRetransmissionSupport with this ticker.
+func (t *Ticker) CacheMetrics() *CacheMetrics {
+	return t.cacheMetrics
+}
+
 func (t *Ticker) start() {
 	for range t.ticks {
 		t.handlersMutex.Lock()
@@ -75,9 +86,11 @@ func (t *Ticker) start() {
 		t.handlersMutex.Unlock()
 	}
 
+	t.handlersMutex.Lock()
 	for ctx := range t.handlers {
 		delete(t.handlers, ctx)
 	}
+	t.handlersMutex.Unlock()
 }
 
 func (t *Ticker) onTick(ctx context.Context, fn func()) {
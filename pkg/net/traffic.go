@@ -0,0 +1,209 @@
+package net
+
+import (
+	"sort"
+	"sync"
+)
+
+// UnknownMessageType is the message type under which the traffic of received
+// messages is recorded if the type is not registered in the channel or could
+// not be determined. The type of received messages is controlled by remote
+// peers so it cannot be used directly to avoid an unbounded number of
+// recorded counters.
+const UnknownMessageType = "unknown"
+
+// MessageTraffic holds counters of the network traffic of messages of a single
+// type exchanged over a single broadcast channel.
+type MessageTraffic struct {
+	Channel string `json:"channel"`
+	Type    string `json:"type"`
+
+	// SentCount is the number of messages sent by the client, excluding
+	// retransmissions.
+	SentCount uint64 `json:"sent_count"`
+	// RetransmissionsCount is the number of retransmissions of messages sent
+	// by the client.
+	RetransmissionsCount uint64 `json:"retransmissions_count"`
+	// SentBytes is the number of bytes sent by the client, including
+	// retransmissions.
+	SentBytes uint64 `json:"sent_bytes"`
+
+	// ReceivedCount is the number of received messages, including
+	// retransmissions and messages that could not be unmarshaled.
+	ReceivedCount uint64 `json:"received_count"`
+	// ReceivedBytes is the number of bytes of received messages.
+	ReceivedBytes uint64 `json:"received_bytes"`
+
+	// DroppedByFilterCount is the number of messages rejected by the
+	// channel filter.
+	DroppedByFilterCount uint64 `json:"dropped_by_filter_count"`
+	// UnmarshalFailuresCount is the number of received messages that could
+	// not be unmarshaled.
+	UnmarshalFailuresCount uint64 `json:"unmarshal_failures_count"`
+}
+
+type messageTrafficKey struct {
+	channel     string
+	messageType string
+}
+
+// TrafficMetrics collects counters of the network traffic per broadcast
+// channel and message type. All functions are thread-safe. Recording
+// functions are no-ops for a nil TrafficMetrics so network components can be
+// used without metrics.
+type TrafficMetrics struct {
+	mutex   sync.Mutex
+	traffic map[messageTrafficKey]*MessageTraffic
+
+	// closedChannelsTotal holds counters of channels that were already
+	// closed. They are no longer reported per channel but are still part of
+	// the total so total counters never decrease.
+	closedChannelsTotal MessageTraffic
+}
+
+// NewTrafficMetrics creates a new instance of TrafficMetrics.
+func NewTrafficMetrics() *TrafficMetrics {
+	return &TrafficMetrics{
+		traffic: make(map[messageTrafficKey]*MessageTraffic),
+	}
+}
+
+// MessageSent records a message of the given type and size sent over the
+// given channel.
+func (tm *TrafficMetrics) MessageSent(channel, messageType string, bytes int) {
+	tm.update(channel, messageType, func(traffic *MessageTraffic) {
+		traffic.SentCount++
+		traffic.SentBytes += uint64(bytes)
+	})
+}
+
+// MessageRetransmitted records a retransmission of a message of the given
+// type and size sent over the given channel.
+func (tm *TrafficMetrics) MessageRetransmitted(
+	channel,
+	messageType string,
+	bytes int,
+) {
+	tm.update(channel, messageType, func(traffic *MessageTraffic) {
+		traffic.RetransmissionsCount++
+		traffic.SentBytes += uint64(bytes)
+	})
+}
+
+// MessageReceived records a message of the given type and size received over
+// the given channel.
+func (tm *TrafficMetrics) MessageReceived(
+	channel,
+	messageType string,
+	bytes int,
+) {
+	tm.update(channel, messageType, func(traffic *MessageTraffic) {
+		traffic.ReceivedCount++
+		traffic.ReceivedBytes += uint64(bytes)
+	})
+}
+
+// MessageDroppedByFilter records a message of the given type rejected by
+// the filter of the given channel.
+func (tm *TrafficMetrics) MessageDroppedByFilter(channel, messageType string) {
+	tm.update(channel, messageType, func(traffic *MessageTraffic) {
+		traffic.DroppedByFilterCount++
+	})
+}
+
+// MessageUnmarshalFailed records a message of the given type received over
+// the given channel that could not be unmarshaled.
+func (tm *TrafficMetrics) MessageUnmarshalFailed(channel, messageType string) {
+	tm.update(channel, messageType, func(traffic *MessageTraffic) {
+		traffic.UnmarshalFailuresCount++
+	})
+}
+
+// ChannelClosed removes counters of the given channel. Removed counters are
+// still taken into account by Total.
+func (tm *TrafficMetrics) ChannelClosed(channel string) {
+	if tm == nil {
+		return
+	}
+
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	for key, traffic := range tm.traffic {
+		if key.channel != channel {
+			continue
+		}
+
+		tm.closedChannelsTotal.add(traffic)
+		delete(tm.traffic, key)
+	}
+}
+
+func (tm *TrafficMetrics) update(
+	channel string,
+	messageType string,
+	updateFn func(traffic *MessageTraffic),
+) {
+	if tm == nil {
+		return
+	}
+
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	key := messageTrafficKey{channel, messageType}
+
+	traffic, ok := tm.traffic[key]
+	if !ok {
+		traffic = &MessageTraffic{Channel: channel, Type: messageType}
+		tm.traffic[key] = traffic
+	}
+
+	updateFn(traffic)
+}
+
+// Snapshot returns copies of counters of all channels and message types,
+// sorted by channel name and message type.
+func (tm *TrafficMetrics) Snapshot() []MessageTraffic {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	snapshot := make([]MessageTraffic, 0, len(tm.traffic))
+	for _, traffic := range tm.traffic {
+		snapshot = append(snapshot, *traffic)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Channel != snapshot[j].Channel {
+			return snapshot[i].Channel < snapshot[j].Channel
+		}
+		return snapshot[i].Type < snapshot[j].Type
+	})
+
+	return snapshot
+}
+
+// Total returns counters summed over all channels and message types. Channel
+// and type of the returned value are empty.
+func (tm *TrafficMetrics) Total() MessageTraffic {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	total := tm.closedChannelsTotal
+	for _, traffic := range tm.traffic {
+		total.add(traffic)
+	}
+
+	return total
+}
+
+// add adds counters of the other traffic to this traffic.
+func (mt *MessageTraffic) add(other *MessageTraffic) {
+	mt.SentCount += other.SentCount
+	mt.RetransmissionsCount += other.RetransmissionsCount
+	mt.SentBytes += other.SentBytes
+	mt.ReceivedCount += other.ReceivedCount
+	mt.ReceivedBytes += other.ReceivedBytes
+	mt.DroppedByFilterCount += other.DroppedByFilterCount
+	mt.UnmarshalFailuresCount += other.UnmarshalFailuresCount
+}
@@ -0,0 +1,118 @@
+package net
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrafficMetrics(t *testing.T) {
+	metrics := NewTrafficMetrics()
+
+	metrics.MessageSent("channel-b", "type-1", 100)
+	metrics.MessageRetransmitted("channel-b", "type-1", 100)
+	metrics.MessageReceived("channel-b", "type-1", 120)
+	metrics.MessageReceived("channel-a", "type-2", 50)
+	metrics.MessageUnmarshalFailed("channel-a", "type-2")
+	metrics.MessageDroppedByFilter("channel-a", "")
+
+	expectedSnapshot := []MessageTraffic{
+		{
+			Channel:              "channel-a",
+			Type:                 "",
+			DroppedByFilterCount: 1,
+		},
+		{
+			Channel:                "channel-a",
+			Type:                   "type-2",
+			ReceivedCount:          1,
+			ReceivedBytes:          50,
+			UnmarshalFailuresCount: 1,
+		},
+		{
+			Channel:              "channel-b",
+			Type:                 "type-1",
+			SentCount:            1,
+			RetransmissionsCount: 1,
+			SentBytes:            200,
+			ReceivedCount:        1,
+			ReceivedBytes:        120,
+		},
+	}
+	if snapshot := metrics.Snapshot(); !reflect.DeepEqual(expectedSnapshot, snapshot) {
+		t.Errorf(
+			"unexpected snapshot\nexpected: %+v\nactual:   %+v",
+			expectedSnapshot,
+			snapshot,
+		)
+	}
+
+	expectedTotal := MessageTraffic{
+		SentCount:              1,
+		RetransmissionsCount:   1,
+		SentBytes:              200,
+		ReceivedCount:          2,
+		ReceivedBytes:          170,
+		DroppedByFilterCount:   1,
+		UnmarshalFailuresCount: 1,
+	}
+	if total := metrics.Total(); !reflect.DeepEqual(expectedTotal, total) {
+		t.Errorf(
+			"unexpected total\nexpected: %+v\nactual:   %+v",
+			expectedTotal,
+			total,
+		)
+	}
+}
+
+func TestTrafficMetrics_ChannelClosed(t *testing.T) {
+	metrics := NewTrafficMetrics()
+
+	metrics.MessageSent("channel-a", "type-1", 100)
+	metrics.MessageReceived("channel-a", "type-2", 50)
+	metrics.MessageSent("channel-b", "type-1", 70)
+
+	metrics.ChannelClosed("channel-a")
+
+	expectedSnapshot := []MessageTraffic{
+		{
+			Channel:   "channel-b",
+			Type:      "type-1",
+			SentCount: 1,
+			SentBytes: 70,
+		},
+	}
+	if snapshot := metrics.Snapshot(); !reflect.DeepEqual(expectedSnapshot, snapshot) {
+		t.Errorf(
+			"unexpected snapshot\nexpected: %+v\nactual:   %+v",
+			expectedSnapshot,
+			snapshot,
+		)
+	}
+
+	// Counters of the closed channel must still be part of the total.
+	expectedTotal := MessageTraffic{
+		SentCount:     2,
+		SentBytes:     170,
+		ReceivedCount: 1,
+		ReceivedBytes: 50,
+	}
+	if total := metrics.Total(); !reflect.DeepEqual(expectedTotal, total) {
+		t.Errorf(
+			"unexpected total\nexpected: %+v\nactual:   %+v",
+			expectedTotal,
+			total,
+		)
+	}
+}
+
+func TestTrafficMetrics_Nil(t *testing.T) {
+	var metrics *TrafficMetrics
+
+	// Recording functions must not panic for nil metrics.
+	metrics.MessageSent("channel", "type", 100)
+	metrics.MessageRetransmitted("channel", "type", 100)
+	metrics.MessageReceived("channel", "type", 100)
+	metrics.MessageDroppedByFilter("channel", "type")
+	metrics.MessageUnmarshalFailed("channel", "type")
+	metrics.ChannelClosed("channel")
+}
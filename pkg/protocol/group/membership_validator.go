@@ -3,6 +3,7 @@ package group
 import (
 	"github.com/ipfs/go-log/v2"
 	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/operator"
 )
 
@@ -17,6 +18,8 @@ type MembershipValidator struct {
 	logger  log.StandardLogger
 	members map[string][]int // operator address -> operator positions in group
 	signing chain.Signing
+
+	peerReputation net.PeerReputation
 }
 
 // NewMembershipValidator creates a validator for the provided group selection
@@ -45,6 +48,16 @@ func NewMembershipValidator(
 	}
 }
 
+// SetPeerReputation sets the reputation invalid membership claims are
+// reported to. A party selected to the group but claiming a position not
+// assigned to it is considered misbehaving. The reputation must be set
+// before the validator is used.
+func (mv *MembershipValidator) SetPeerReputation(
+	peerReputation net.PeerReputation,
+) {
+	mv.peerReputation = peerReputation
+}
+
 // IsInGroup returns true if party with the given public key has been
 // selected to the group. Otherwise, function returns false.
 func (mv *MembershipValidator) IsInGroup(
@@ -84,5 +97,12 @@ func (mv *MembershipValidator) IsValidMembership(
 		}
 	}
 
+	if mv.peerReputation != nil {
+		mv.peerReputation.ReportMisbehavior(
+			publicKey,
+			net.InvalidMembershipMisbehavior,
+		)
+	}
+
 	return false
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/chain/local_v1"
+	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/operator"
 )
 
@@ -78,6 +79,59 @@ func TestIsValidMembership(t *testing.T) {
 	}
 }
 
+func TestIsValidMembership_ReportMisbehavior(t *testing.T) {
+	localChain := local_v1.Connect(3, 3)
+	signing := localChain.Signing()
+
+	publicKey1 := generatePublicKeyBytes(t)
+	publicKey2 := generatePublicKeyBytes(t)
+	publicKey3 := generatePublicKeyBytes(t)
+
+	address1 := signing.PublicKeyBytesToAddress(publicKey1)
+	address2 := signing.PublicKeyBytesToAddress(publicKey2)
+
+	validator := NewMembershipValidator(
+		&testutils.MockLogger{},
+		[]chain.Address{address1, address2},
+		signing,
+	)
+
+	peerReputation := &mockPeerReputation{}
+	validator.SetPeerReputation(peerReputation)
+
+	validator.IsValidMembership(1, publicKey1)
+	// The operator is in the group but claims a position of another member.
+	validator.IsValidMembership(1, publicKey2)
+	// The operator is not in the group at all. Such messages are rejected
+	// by the broadcast channel filter.
+	validator.IsValidMembership(1, publicKey3)
+
+	testutils.AssertIntsEqual(
+		t,
+		"reported misbehaviors count",
+		1,
+		len(peerReputation.reported),
+	)
+	testutils.AssertBytesEqual(t, publicKey2, peerReputation.reported[0])
+}
+
+type mockPeerReputation struct {
+	reported [][]byte
+}
+
+func (mpr *mockPeerReputation) ReportMisbehavior(
+	operatorPublicKey []byte,
+	misbehavior net.Misbehavior,
+) {
+	if misbehavior == net.InvalidMembershipMisbehavior {
+		mpr.reported = append(mpr.reported, operatorPublicKey)
+	}
+}
+
+func (mpr *mockPeerReputation) PeerScores() []net.PeerScore {
+	return nil
+}
+
 func generatePublicKey(t *testing.T) *operator.PublicKey {
 	_, operatorPublicKey, err := operator.GenerateKeyPair(local_v1.DefaultCurve)
 	if err != nil {
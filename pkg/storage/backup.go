@@ -0,0 +1,600 @@
+package storage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/keep-network/keep-common/pkg/encryption"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/keep-network/keep-core/build"
+)
+
+const (
+	// backupManifestName is the name of the backup archive entry holding
+	// the backup manifest.
+	backupManifestName = "manifest.json"
+	// keyStoreCurrentDirName is the name of the directory holding current
+	// data of a keystore persistence.
+	keyStoreCurrentDirName = "current"
+	// backupArchiveMagic opens the header of a backup archive and identifies
+	// the archive format.
+	backupArchiveMagic = "KEEPBAK1"
+	// backupSaltSize is the size in bytes of the random salt held in the
+	// header of a backup archive and used to derive the archive encryption
+	// key from the backup password.
+	backupSaltSize = 32
+	// backupScryptN, backupScryptR, and backupScryptP are the scrypt
+	// parameters used to derive the archive encryption key from the backup
+	// password. Values follow the scrypt recommendation for interactive
+	// logins.
+	backupScryptN = 1 << 15
+	backupScryptR = 8
+	backupScryptP = 1
+)
+
+// BackupManifest describes the content of a storage backup archive.
+type BackupManifest struct {
+	// ClientVersion is the version of the client that created the backup.
+	ClientVersion string `json:"clientVersion"`
+	// ClientRevision is the revision of the client that created the backup.
+	ClientRevision string `json:"clientRevision"`
+	// CreatedAt is the time the backup was created at.
+	CreatedAt time.Time `json:"createdAt"`
+	// KeyStore lists directories held in the current data of keystore
+	// persistences, keyed by the persistence name. For example, directories
+	// of the `tbtc` persistence correspond to tBTC wallets and directories
+	// of the `beacon` persistence correspond to beacon groups.
+	KeyStore map[string][]string `json:"keyStore"`
+	// Work counts files held in work persistences, keyed by the persistence
+	// name and the directory name. For example, files in the `preparams`
+	// directory of the `tbtc` persistence are tECDSA DKG pre-parameters.
+	Work map[string]map[string]int `json:"work"`
+	// Quarantine is the number of files held in the quarantine directory,
+	// i.e. files that failed validation when they were loaded by the client.
+	Quarantine int `json:"quarantine"`
+	// FilesCount is the total number of storage files in the backup.
+	FilesCount int `json:"filesCount"`
+}
+
+// backupFile is a single storage file held in a backup archive.
+type backupFile struct {
+	// path is the file path relative to the storage root directory, using
+	// forward slashes as separators.
+	path    string
+	modTime time.Time
+	content []byte
+}
+
+// Backup writes an archive with all data held in the keystore, work and
+// quarantine directories of the storage to the given writer. The archive contains
+// a manifest describing its content and is encrypted with the given
+// password. Storage files are put into the archive as they are, i.e.
+// encrypted with the storage encryption password. The backup is refused if
+// the storage is used by another process, e.g. a running client, as files
+// could change while they are read. Returns the manifest of the written
+// archive.
+func (s *Storage) Backup(writer io.Writer, password string) (
+	*BackupManifest,
+	error,
+) {
+	lockFile, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer lockFile.Close()
+
+	if err := s.checkPasswordRotation(); err != nil {
+		return nil, err
+	}
+
+	files, err := s.readBackupFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := newBackupManifest(files)
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal backup manifest: [%w]", err)
+	}
+
+	var archive bytes.Buffer
+	gzipWriter := gzip.NewWriter(&archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	writeEntry := func(name string, modTime time.Time, content []byte) error {
+		if err := tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0600,
+			Size:     int64(len(content)),
+			ModTime:  modTime,
+			Typeflag: tar.TypeReg,
+			// Use PAX format to preserve sub-second modification times
+			// compared while restoring.
+			Format: tar.FormatPAX,
+		}); err != nil {
+			return err
+		}
+
+		_, err := tarWriter.Write(content)
+		return err
+	}
+
+	if err := writeEntry(
+		backupManifestName,
+		manifest.CreatedAt,
+		manifestBytes,
+	); err != nil {
+		return nil, fmt.Errorf("cannot archive backup manifest: [%w]", err)
+	}
+
+	for _, file := range files {
+		if err := writeEntry(file.path, file.modTime, file.content); err != nil {
+			return nil, fmt.Errorf(
+				"cannot archive file [%s]: [%w]",
+				file.path,
+				err,
+			)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, fmt.Errorf("cannot close backup archive: [%w]", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("cannot compress backup archive: [%w]", err)
+	}
+
+	salt := make([]byte, backupSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("cannot generate backup archive salt: [%w]", err)
+	}
+
+	backupBox, err := newBackupBox(password, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedArchive, err := backupBox.Encrypt(archive.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot encrypt backup archive: [%w]", err)
+	}
+
+	header := append([]byte(backupArchiveMagic), salt...)
+
+	if _, err := writer.Write(
+		append(header, encryptedArchive...),
+	); err != nil {
+		return nil, fmt.Errorf("cannot write backup archive: [%w]", err)
+	}
+
+	return manifest, nil
+}
+
+// Restore restores storage files from the backup archive read from the
+// given reader. The archive is decrypted with the given password. The
+// restore is refused if the storage is used by another process, e.g.
+// a running client. Before anything is written, the archive is validated:
+//   - all storage files must be decryptable with the storage encryption
+//     password,
+//   - files of keystore persistences for which a validator is given must
+//     pass that validator; validators are keyed by the persistence name
+//     and receive decrypted file content,
+//   - files already present in the storage must not be newer than their
+//     counterparts in the archive.
+//
+// Files already present in the storage with the same content are left
+// untouched. Returns the manifest of the restored archive.
+func (s *Storage) Restore(
+	reader io.Reader,
+	password string,
+	validators map[string]func(content []byte) error,
+) (*BackupManifest, error) {
+	lockFile, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer lockFile.Close()
+
+	if err := s.checkPasswordRotation(); err != nil {
+		return nil, err
+	}
+
+	manifest, files, err := readBackupArchive(reader, password)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *multierror.Error
+
+	storageBox := newPasswordBox(s.encryptionPassword)
+
+	for _, file := range files {
+		if err := s.validateBackupFile(
+			file,
+			storageBox,
+			validators,
+		); err != nil {
+			result = multierror.Append(
+				result,
+				fmt.Errorf("invalid file [%s]: [%w]", file.path, err),
+			)
+		}
+	}
+
+	if err := result.ErrorOrNil(); err != nil {
+		return nil, fmt.Errorf("backup archive validation failed: %w", err)
+	}
+
+	for _, file := range files {
+		if err := s.restoreBackupFile(file); err != nil {
+			return nil, fmt.Errorf(
+				"cannot restore file [%s]: [%w]",
+				file.path,
+				err,
+			)
+		}
+	}
+
+	return manifest, nil
+}
+
+// readBackupFiles reads all files held in the keystore, work and quarantine
+// directories of the storage.
+func (s *Storage) readBackupFiles() ([]*backupFile, error) {
+	dirs, err := s.dataDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*backupFile
+
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(
+			filePath string,
+			info os.FileInfo,
+			err error,
+		) error {
+			if err != nil {
+				return err
+			}
+
+			if !info.Mode().IsRegular() ||
+				strings.Contains(info.Name(), tempFileMarker) {
+				return nil
+			}
+
+			relativePath, err := filepath.Rel(s.rootDir, filePath)
+			if err != nil {
+				return err
+			}
+
+			// #nosec G304 (file path provided as taint input)
+			// The path is taken from the storage directory walk.
+			content, err := ioutil.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+
+			files = append(files, &backupFile{
+				path:    filepath.ToSlash(relativePath),
+				modTime: info.ModTime(),
+				content: content,
+			})
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot read storage directory [%s]: [%w]",
+				dir,
+				err,
+			)
+		}
+	}
+
+	return files, nil
+}
+
+// validateBackupFile validates the given backup file before it is restored.
+func (s *Storage) validateBackupFile(
+	file *backupFile,
+	storageBox encryption.Box,
+	validators map[string]func(content []byte) error,
+) error {
+	content, err := storageBox.Decrypt(file.content)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot decrypt with the storage encryption password: [%w]",
+			err,
+		)
+	}
+
+	pathElements := strings.Split(file.path, "/")
+	if pathElements[0] == keyStoreDirName && len(pathElements) > 1 {
+		if validate, ok := validators[pathElements[1]]; ok {
+			if err := validate(content); err != nil {
+				return err
+			}
+		}
+	}
+
+	// #nosec G304 (file path provided as taint input)
+	// The path is validated while reading the backup archive.
+	existingContent, err := ioutil.ReadFile(s.backupFilePath(file))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read existing file: [%w]", err)
+	}
+
+	if bytes.Equal(existingContent, file.content) {
+		return nil
+	}
+
+	existingInfo, err := os.Stat(s.backupFilePath(file))
+	if err != nil {
+		return fmt.Errorf("cannot stat existing file: [%w]", err)
+	}
+
+	if existingInfo.ModTime().After(file.modTime) {
+		return fmt.Errorf(
+			"existing file modified at [%v] is newer than the backup "+
+				"file modified at [%v]",
+			existingInfo.ModTime(),
+			file.modTime,
+		)
+	}
+
+	return nil
+}
+
+// restoreBackupFile writes the given backup file to the storage unless
+// the storage already holds the same file.
+func (s *Storage) restoreBackupFile(file *backupFile) error {
+	targetPath := s.backupFilePath(file)
+
+	// #nosec G304 (file path provided as taint input)
+	// The path is validated while reading the backup archive.
+	existingContent, err := ioutil.ReadFile(targetPath)
+	if err == nil && bytes.Equal(existingContent, file.content) {
+		return nil
+	}
+
+	return writeFileAtomically(targetPath, file.content, file.modTime)
+}
+
+// backupFilePath returns the path the given backup file is restored to.
+func (s *Storage) backupFilePath(file *backupFile) string {
+	return filepath.Join(s.rootDir, filepath.FromSlash(file.path))
+}
+
+// readBackupArchive decrypts and reads the backup archive from the given
+// reader.
+func readBackupArchive(reader io.Reader, password string) (
+	*BackupManifest,
+	[]*backupFile,
+	error,
+) {
+	archiveBytes, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read backup archive: [%w]", err)
+	}
+
+	headerSize := len(backupArchiveMagic) + backupSaltSize
+	if len(archiveBytes) < headerSize ||
+		string(archiveBytes[:len(backupArchiveMagic)]) != backupArchiveMagic {
+		return nil, nil, fmt.Errorf("backup archive has invalid header")
+	}
+
+	salt := archiveBytes[len(backupArchiveMagic):headerSize]
+	encryptedArchive := archiveBytes[headerSize:]
+
+	backupBox, err := newBackupBox(password, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	archive, err := backupBox.Decrypt(encryptedArchive)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"cannot decrypt backup archive; make sure the password "+
+				"is correct: [%w]",
+			err,
+		)
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"cannot decompress backup archive: [%w]",
+			err,
+		)
+	}
+
+	var manifest *BackupManifest
+	var files []*backupFile
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"cannot read backup archive entry: [%w]",
+				err,
+			)
+		}
+
+		content, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"cannot read backup archive entry [%s]: [%w]",
+				header.Name,
+				err,
+			)
+		}
+
+		if header.Name == backupManifestName {
+			manifest = &BackupManifest{}
+			if err := json.Unmarshal(content, manifest); err != nil {
+				return nil, nil, fmt.Errorf(
+					"cannot unmarshal backup manifest: [%w]",
+					err,
+				)
+			}
+			continue
+		}
+
+		if err := validateBackupFilePath(header.Name); err != nil {
+			return nil, nil, err
+		}
+
+		files = append(files, &backupFile{
+			path:    header.Name,
+			modTime: header.ModTime,
+			content: content,
+		})
+	}
+
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("backup archive has no manifest")
+	}
+
+	if manifest.FilesCount != len(files) {
+		return nil, nil, fmt.Errorf(
+			"backup archive has [%v] files while its manifest declares [%v]",
+			len(files),
+			manifest.FilesCount,
+		)
+	}
+
+	return manifest, files, nil
+}
+
+// validateBackupFilePath makes sure the given backup archive entry path
+// points to a file within the keystore, work or quarantine directory of the
+// storage.
+func validateBackupFilePath(filePath string) error {
+	cleanPath := path.Clean(filePath)
+
+	isStoragePath := strings.HasPrefix(cleanPath, keyStoreDirName+"/") ||
+		strings.HasPrefix(cleanPath, workDirName+"/") ||
+		strings.HasPrefix(cleanPath, quarantineDirName+"/")
+
+	if cleanPath != filePath || !isStoragePath {
+		return fmt.Errorf(
+			"backup archive entry [%s] points outside the storage",
+			filePath,
+		)
+	}
+
+	return nil
+}
+
+// newBackupManifest creates a manifest describing the given backup files.
+func newBackupManifest(files []*backupFile) *BackupManifest {
+	manifest := &BackupManifest{
+		ClientVersion:  build.Version,
+		ClientRevision: build.Revision,
+		CreatedAt:      time.Now(),
+		KeyStore:       make(map[string][]string),
+		Work:           make(map[string]map[string]int),
+		FilesCount:     len(files),
+	}
+
+	keyStoreDirs := make(map[string]map[string]bool)
+
+	for _, file := range files {
+		pathElements := strings.Split(file.path, "/")
+
+		switch {
+		// Keystore files are placed in
+		// keystore/<persistence>/current/<directory>/<file>.
+		case pathElements[0] == keyStoreDirName &&
+			len(pathElements) == 5 &&
+			pathElements[2] == keyStoreCurrentDirName:
+			persistenceName, dir := pathElements[1], pathElements[3]
+
+			if keyStoreDirs[persistenceName] == nil {
+				keyStoreDirs[persistenceName] = make(map[string]bool)
+			}
+
+			if !keyStoreDirs[persistenceName][dir] {
+				keyStoreDirs[persistenceName][dir] = true
+				manifest.KeyStore[persistenceName] = append(
+					manifest.KeyStore[persistenceName],
+					dir,
+				)
+			}
+		// Work files are placed in work/<persistence>/<directory>/<file>.
+		case pathElements[0] == workDirName && len(pathElements) == 4:
+			persistenceName, dir := pathElements[1], pathElements[2]
+
+			if manifest.Work[persistenceName] == nil {
+				manifest.Work[persistenceName] = make(map[string]int)
+			}
+
+			manifest.Work[persistenceName][dir]++
+		case pathElements[0] == quarantineDirName:
+			manifest.Quarantine++
+		}
+	}
+
+	for _, dirs := range manifest.KeyStore {
+		sort.Strings(dirs)
+	}
+
+	return manifest
+}
+
+// newBackupBox creates an encryption box for a backup archive using the key
+// derived from the given backup password and the salt held in the archive
+// header. The key is derived with scrypt to make brute-forcing the password
+// of a leaked archive expensive.
+func newBackupBox(password string, salt []byte) (encryption.Box, error) {
+	key, err := scrypt.Key(
+		[]byte(password),
+		salt,
+		backupScryptN,
+		backupScryptR,
+		backupScryptP,
+		encryption.KeyLength,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot derive backup archive encryption key: [%w]",
+			err,
+		)
+	}
+
+	var keyBytes [encryption.KeyLength]byte
+	copy(keyBytes[:], key)
+
+	return encryption.NewBox(keyBytes), nil
+}
+
+// newPasswordBox creates an encryption box using the key derived from the
+// given password the same way the encrypted persistence derives it.
+func newPasswordBox(password string) encryption.Box {
+	return encryption.NewBox(sha256.Sum256([]byte(password)))
+}
@@ -0,0 +1,413 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+const (
+	testStoragePassword = "storage-password"
+	testBackupPassword  = "backup-password"
+)
+
+func TestBackupRestore(t *testing.T) {
+	sourceStorage := initializeTestStorage(t, testStoragePassword)
+
+	keyStorePersistence, err := sourceStorage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"membership_1", "membership_2"} {
+		if err := keyStorePersistence.Save(
+			[]byte("signer"),
+			"wallet_1",
+			name,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	workPersistence, err := sourceStorage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := workPersistence.Save([]byte("pre-params"), "preparams", "pp_1"); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	backupManifest, err := sourceStorage.Backup(&archive, testBackupPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "files count", 3, backupManifest.FilesCount)
+	expectedKeyStore := map[string][]string{"tbtc": {"wallet_1"}}
+	if !reflect.DeepEqual(expectedKeyStore, backupManifest.KeyStore) {
+		t.Errorf(
+			"unexpected keystore manifest\nexpected: %v\nactual:   %v\n",
+			expectedKeyStore,
+			backupManifest.KeyStore,
+		)
+	}
+	expectedWork := map[string]map[string]int{"tbtc": {"preparams": 1}}
+	if !reflect.DeepEqual(expectedWork, backupManifest.Work) {
+		t.Errorf(
+			"unexpected work manifest\nexpected: %v\nactual:   %v\n",
+			expectedWork,
+			backupManifest.Work,
+		)
+	}
+
+	targetStorage := initializeTestStorage(t, testStoragePassword)
+
+	var validated []string
+	restoreManifest, err := targetStorage.Restore(
+		bytes.NewReader(archive.Bytes()),
+		testBackupPassword,
+		map[string]func(content []byte) error{
+			"tbtc": func(content []byte) error {
+				validated = append(validated, string(content))
+				return nil
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"validated keystore files count",
+		2,
+		len(validated),
+	)
+
+	if restoreManifest.CreatedAt.UnixNano() !=
+		backupManifest.CreatedAt.UnixNano() {
+		t.Errorf("restored manifest differs from the backup one")
+	}
+
+	restoredPersistence, err := targetStorage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	descriptors, errs := restoredPersistence.ReadAll()
+	go func() {
+		for err := range errs {
+			t.Errorf("unexpected error: [%v]", err)
+		}
+	}()
+
+	var restoredContents []string
+	for descriptor := range descriptors {
+		content, err := descriptor.Content()
+		if err != nil {
+			t.Fatal(err)
+		}
+		restoredContents = append(restoredContents, string(content))
+	}
+
+	if !reflect.DeepEqual([]string{"pre-params"}, restoredContents) {
+		t.Errorf("unexpected restored work data: [%v]", restoredContents)
+	}
+
+	// Restoring the same archive again should leave identical files
+	// untouched.
+	_, err = targetStorage.Restore(
+		bytes.NewReader(archive.Bytes()),
+		testBackupPassword,
+		nil,
+	)
+	if err != nil {
+		t.Errorf("unexpected error on repeated restore: [%v]", err)
+	}
+}
+
+func TestBackupRestore_Quarantine(t *testing.T) {
+	sourceStorage := initializeTestStorage(t, testStoragePassword)
+	populateTestStorage(t, sourceStorage)
+
+	quarantinedPath := "keystore/tbtc/current/wallet_1/membership_1"
+	if err := sourceStorage.quarantineFile(quarantinedPath); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	manifest, err := sourceStorage.Backup(&archive, testBackupPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "files count", 3, manifest.FilesCount)
+	testutils.AssertIntsEqual(t, "quarantined files", 1, manifest.Quarantine)
+
+	targetStorage := initializeTestStorage(t, testStoragePassword)
+
+	// Quarantined files must not be passed to keystore validators.
+	_, err = targetStorage.Restore(
+		&archive,
+		testBackupPassword,
+		map[string]func(content []byte) error{
+			"tbtc": func(content []byte) error {
+				if string(content) == "membership_1" {
+					return fmt.Errorf("invalid signer")
+				}
+				return nil
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFileExists(
+		t,
+		targetStorage.rootDir,
+		quarantineDirName+"/"+quarantinedPath,
+		true,
+	)
+	assertFileExists(t, targetStorage.rootDir, quarantinedPath, false)
+}
+
+func TestRestore_Validation(t *testing.T) {
+	var tests = map[string]struct {
+		targetStoragePassword string
+		backupPassword        string
+		validators            map[string]func(content []byte) error
+		modifyTargetFn        func(t *testing.T, rootDir string)
+		expectedError         string
+	}{
+		"wrong backup password": {
+			targetStoragePassword: testStoragePassword,
+			backupPassword:        "wrong-password",
+			expectedError:         "cannot decrypt backup archive",
+		},
+		"different storage password": {
+			targetStoragePassword: "other-storage-password",
+			backupPassword:        testBackupPassword,
+			expectedError: "cannot decrypt with the storage " +
+				"encryption password",
+		},
+		"keystore file not passing validation": {
+			targetStoragePassword: testStoragePassword,
+			backupPassword:        testBackupPassword,
+			validators: map[string]func(content []byte) error{
+				"tbtc": func(content []byte) error {
+					return fmt.Errorf("invalid signer")
+				},
+			},
+			expectedError: "invalid signer",
+		},
+		"newer file in the storage": {
+			targetStoragePassword: testStoragePassword,
+			backupPassword:        testBackupPassword,
+			modifyTargetFn: func(t *testing.T, rootDir string) {
+				filePath := filepath.Join(
+					rootDir,
+					keyStoreDirName,
+					"tbtc",
+					"current",
+					"wallet_1",
+					"membership_1",
+				)
+				if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(filePath, []byte("newer"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				future := time.Now().Add(time.Hour)
+				if err := os.Chtimes(filePath, future, future); err != nil {
+					t.Fatal(err)
+				}
+			},
+			expectedError: "is newer than the backup file",
+		},
+	}
+
+	sourceStorage := initializeTestStorage(t, testStoragePassword)
+
+	keyStorePersistence, err := sourceStorage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStorePersistence.Save(
+		[]byte("signer"),
+		"wallet_1",
+		"membership_1",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	if _, err := sourceStorage.Backup(&archive, testBackupPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			targetStorage := initializeTestStorage(
+				t,
+				test.targetStoragePassword,
+			)
+
+			if test.modifyTargetFn != nil {
+				test.modifyTargetFn(t, targetStorage.rootDir)
+			}
+
+			_, err := targetStorage.Restore(
+				bytes.NewReader(archive.Bytes()),
+				test.backupPassword,
+				test.validators,
+			)
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Fatalf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+
+			// Nothing should be written if the validation fails.
+			if test.modifyTargetFn == nil {
+				files, err := targetStorage.readBackupFiles()
+				if err != nil {
+					t.Fatal(err)
+				}
+				testutils.AssertIntsEqual(t, "restored files", 0, len(files))
+			}
+		})
+	}
+}
+
+func TestBackup_ArchiveHeader(t *testing.T) {
+	storage := initializeTestStorage(t, testStoragePassword)
+
+	var firstArchive, secondArchive bytes.Buffer
+	if _, err := storage.Backup(&firstArchive, testBackupPassword); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.Backup(&secondArchive, testBackupPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	headerSize := len(backupArchiveMagic) + backupSaltSize
+	firstHeader := firstArchive.Bytes()[:headerSize]
+	secondHeader := secondArchive.Bytes()[:headerSize]
+
+	if string(firstHeader[:len(backupArchiveMagic)]) != backupArchiveMagic {
+		t.Errorf("backup archive does not start with the magic")
+	}
+
+	// Each archive must use its own random salt.
+	if bytes.Equal(firstHeader, secondHeader) {
+		t.Errorf("backup archives use the same salt")
+	}
+
+	_, _, err := readBackupArchive(
+		bytes.NewReader(firstArchive.Bytes()[len(backupArchiveMagic):]),
+		testBackupPassword,
+	)
+	if err == nil || !strings.Contains(err.Error(), "invalid header") {
+		t.Errorf("unexpected error for archive without header: [%v]", err)
+	}
+}
+
+func TestBackup_StorageLocked(t *testing.T) {
+	storage := initializeTestStorage(t, testStoragePassword)
+
+	// Simulate a running client holding the lock.
+	lockFile, err := storage.lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lockFile.Close()
+
+	var archive bytes.Buffer
+	_, err = storage.Backup(&archive, testBackupPassword)
+	testutils.AssertErrorsSame(t, ErrStorageLocked, err)
+}
+
+func TestRestore_StorageLocked(t *testing.T) {
+	storage := initializeTestStorage(t, testStoragePassword)
+
+	var archive bytes.Buffer
+	if _, err := storage.Backup(&archive, testBackupPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a running client holding the lock.
+	lockFile, err := storage.lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lockFile.Close()
+
+	_, err = storage.Restore(&archive, testBackupPassword, nil)
+	testutils.AssertErrorsSame(t, ErrStorageLocked, err)
+}
+
+func TestValidateBackupFilePath(t *testing.T) {
+	var tests = map[string]struct {
+		path        string
+		expectError bool
+	}{
+		"keystore file": {
+			path: "keystore/tbtc/current/wallet_1/membership_1",
+		},
+		"work file": {
+			path: "work/tbtc/preparams/pp_1",
+		},
+		"quarantined file": {
+			path: "quarantine/work/tbtc/preparams/pp_1",
+		},
+		"file outside storage directories": {
+			path:        "storage.lock",
+			expectError: true,
+		},
+		"absolute path": {
+			path:        "/etc/passwd",
+			expectError: true,
+		},
+		"path traversal": {
+			path:        "keystore/../../etc/passwd",
+			expectError: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := validateBackupFilePath(test.path)
+
+			testutils.AssertBoolsEqual(
+				t,
+				"error presence",
+				test.expectError,
+				err != nil,
+			)
+		})
+	}
+}
+
+func initializeTestStorage(t *testing.T, password string) *Storage {
+	storage, err := Initialize(Config{
+		Dir:                t.TempDir(),
+		EncryptionPassword: password,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &storage
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// lockFileName is the name of the file in the storage root directory used
+// to make sure only one process uses the storage at a time.
+const lockFileName = "storage.lock"
+
+// ErrStorageLocked indicates that the storage is used by another process,
+// most probably a running client.
+var ErrStorageLocked = fmt.Errorf("storage is used by another process")
+
+// processLocks holds lock files of locks acquired for the process lifetime.
+// Lock files must stay referenced as closing a file, which also happens
+// when the file is garbage collected, releases the lock.
+var processLocks = struct {
+	mutex sync.Mutex
+	files []*os.File
+}{}
+
+// Lock acquires an exclusive lock on the storage. The lock is held until
+// the process exits, so it should be acquired by the client for its whole
+// lifetime. The lock is released by the operating system once the process
+// holding it exits, even if the process crashed. Returns ErrStorageLocked if
+// the lock is held by another process.
+func (s *Storage) Lock() error {
+	lockFile, err := s.lock()
+	if err != nil {
+		return err
+	}
+
+	processLocks.mutex.Lock()
+	defer processLocks.mutex.Unlock()
+
+	processLocks.files = append(processLocks.files, lockFile)
+
+	return nil
+}
+
+// lock acquires an exclusive lock on the storage and returns the lock file.
+// The lock is released once the lock file is closed.
+func (s *Storage) lock() (*os.File, error) {
+	lockFile, err := os.OpenFile(
+		filepath.Join(s.rootDir, lockFileName),
+		os.O_CREATE|os.O_RDWR,
+		0600,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open storage lock file: [%w]", err)
+	}
+
+	err = syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		_ = lockFile.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrStorageLocked
+		}
+
+		return nil, fmt.Errorf("cannot lock storage: [%w]", err)
+	}
+
+	return lockFile, nil
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+)
+
+// quarantineDirName is the name of the storage directory holding files that
+// failed validation when they were loaded by the client. The directory is
+// kept outside the keystore and work directories so quarantined files are
+// never read by the client again. Quarantined files keep their paths
+// relative to the storage root directory.
+const quarantineDirName = "quarantine"
+
+// dataDirs returns directories of the storage holding data files: the
+// keystore and work directories and, if any file has been quarantined, the
+// quarantine directory.
+func (s *Storage) dataDirs() ([]string, error) {
+	dirs := []string{s.keystoreDir, s.workDir}
+
+	quarantineDir := filepath.Join(s.rootDir, quarantineDirName)
+
+	_, err := os.Stat(quarantineDir)
+	if err == nil {
+		dirs = append(dirs, quarantineDir)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("cannot check quarantine directory: [%w]", err)
+	}
+
+	return dirs, nil
+}
+
+// quarantinableBasicHandle is a basic persistence handle that can move files
+// failing validation to the quarantine directory of the storage.
+type quarantinableBasicHandle struct {
+	persistence.BasicHandle
+
+	storage *Storage
+	// relativeDir is the directory of the handle relative to the storage
+	// root directory.
+	relativeDir string
+}
+
+// Quarantine moves the file with the given name in the provided directory to
+// the quarantine directory of the storage.
+func (qbh *quarantinableBasicHandle) Quarantine(
+	directory string,
+	name string,
+) error {
+	return qbh.storage.quarantineFile(
+		filepath.Join(qbh.relativeDir, directory, name),
+	)
+}
+
+// quarantinableProtectedHandle is a protected persistence handle that can
+// move files failing validation to the quarantine directory of the storage.
+type quarantinableProtectedHandle struct {
+	persistence.ProtectedHandle
+
+	storage *Storage
+	// relativeDir is the directory holding current data of the handle
+	// relative to the storage root directory.
+	relativeDir string
+}
+
+// Quarantine moves the file with the given name in the provided directory to
+// the quarantine directory of the storage.
+func (qph *quarantinableProtectedHandle) Quarantine(
+	directory string,
+	name string,
+) error {
+	return qph.storage.quarantineFile(
+		filepath.Join(qph.relativeDir, directory, name),
+	)
+}
+
+// quarantineFile moves the file with the given path relative to the storage
+// root directory to the quarantine directory. If a file with the same path
+// has already been quarantined, the time of the quarantine is appended to
+// the name of the moved file.
+func (s *Storage) quarantineFile(relativePath string) error {
+	quarantinePath := filepath.Join(s.rootDir, quarantineDirName, relativePath)
+
+	if err := os.MkdirAll(filepath.Dir(quarantinePath), 0700); err != nil {
+		return fmt.Errorf("cannot create quarantine directory: [%w]", err)
+	}
+
+	if _, err := os.Stat(quarantinePath); err == nil {
+		quarantinePath = fmt.Sprintf(
+			"%s_%d",
+			quarantinePath,
+			time.Now().UnixMilli(),
+		)
+	}
+
+	if err := os.Rename(
+		filepath.Join(s.rootDir, relativePath),
+		quarantinePath,
+	); err != nil {
+		return fmt.Errorf(
+			"cannot move file [%s] to quarantine: [%w]",
+			relativePath,
+			err,
+		)
+	}
+
+	return nil
+}
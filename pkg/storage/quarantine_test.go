@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+type quarantiner interface {
+	Quarantine(directory string, name string) error
+}
+
+func TestQuarantine(t *testing.T) {
+	storage := initializeTestStorage(t, testStoragePassword)
+	populateTestStorage(t, storage)
+
+	keyStorePersistence, err := storage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	workPersistence, err := storage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = map[string]struct {
+		handle         interface{}
+		directory      string
+		name           string
+		sourcePath     string
+		quarantinePath string
+	}{
+		"keystore file": {
+			handle:         keyStorePersistence,
+			directory:      "wallet_1",
+			name:           "membership_1",
+			sourcePath:     "keystore/tbtc/current/wallet_1/membership_1",
+			quarantinePath: "quarantine/keystore/tbtc/current/wallet_1/membership_1",
+		},
+		"work file": {
+			handle:         workPersistence,
+			directory:      "preparams",
+			name:           "pp_1",
+			sourcePath:     "work/tbtc/preparams/pp_1",
+			quarantinePath: "quarantine/work/tbtc/preparams/pp_1",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			handle, ok := test.handle.(quarantiner)
+			if !ok {
+				t.Fatal("handle does not support quarantine")
+			}
+
+			if err := handle.Quarantine(test.directory, test.name); err != nil {
+				t.Fatal(err)
+			}
+
+			assertFileExists(t, storage.rootDir, test.sourcePath, false)
+			assertFileExists(t, storage.rootDir, test.quarantinePath, true)
+		})
+	}
+
+	// The quarantined file must no longer be read by the handle.
+	descriptorsChan, errorsChan := keyStorePersistence.ReadAll()
+	go func() {
+		for err := range errorsChan {
+			t.Error(err)
+		}
+	}()
+	var names []string
+	for descriptor := range descriptorsChan {
+		names = append(names, descriptor.Name())
+	}
+	testutils.AssertStringsEqual(
+		t,
+		"remaining keystore files",
+		"membership_2",
+		strings.Join(names, ","),
+	)
+}
+
+func TestQuarantine_AlreadyQuarantined(t *testing.T) {
+	storage := initializeTestStorage(t, testStoragePassword)
+
+	workPersistence, err := storage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle, ok := workPersistence.(quarantiner)
+	if !ok {
+		t.Fatal("handle does not support quarantine")
+	}
+
+	// Quarantine a file with the same name twice.
+	for i := 0; i < 2; i++ {
+		if err := workPersistence.Save(
+			[]byte("pp_1"),
+			"preparams",
+			"pp_1",
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := handle.Quarantine("preparams", "pp_1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	quarantineDir := filepath.Join(
+		storage.rootDir,
+		quarantineDirName,
+		"work",
+		"tbtc",
+		"preparams",
+	)
+	entries, err := os.ReadDir(quarantineDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "quarantined files", 2, len(entries))
+	testutils.AssertStringsEqual(
+		t,
+		"first quarantined file",
+		"pp_1",
+		entries[0].Name(),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"second quarantined file has a timestamp suffix",
+		true,
+		strings.HasPrefix(entries[1].Name(), "pp_1_"),
+	)
+}
+
+func TestQuarantine_MissingFile(t *testing.T) {
+	storage := initializeTestStorage(t, testStoragePassword)
+
+	workPersistence, err := storage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle, ok := workPersistence.(quarantiner)
+	if !ok {
+		t.Fatal("handle does not support quarantine")
+	}
+
+	err = handle.Quarantine("preparams", "pp_1")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func assertFileExists(
+	t *testing.T,
+	rootDir string,
+	relativePath string,
+	expected bool,
+) {
+	_, err := os.Stat(filepath.Join(rootDir, relativePath))
+	testutils.AssertBoolsEqual(
+		t,
+		"existence of file ["+relativePath+"]",
+		expected,
+		err == nil,
+	)
+}
@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/encryption"
+)
+
+const (
+	// passwordRotationFileName is the name of the file in the storage root
+	// directory that exists while the storage password rotation is in
+	// progress.
+	passwordRotationFileName = "password_rotation.json"
+	// passwordRotationCheck is the plaintext encrypted with the old and new
+	// passwords and kept in the password rotation file. It is used to make
+	// sure an interrupted rotation is resumed with the same passwords.
+	passwordRotationCheck = "keep-storage-password-rotation"
+)
+
+// ErrPasswordRotationInProgress indicates that the storage password rotation
+// was interrupted and must be completed before the storage can be used.
+var ErrPasswordRotationInProgress = fmt.Errorf(
+	"storage password rotation is in progress; " +
+		"complete it before using the storage",
+)
+
+// passwordRotation is the state of the storage password rotation persisted
+// in the password rotation file.
+type passwordRotation struct {
+	StartedAt time.Time `json:"startedAt"`
+	// OldPasswordCheck is the rotation check encrypted with the old password.
+	OldPasswordCheck []byte `json:"oldPasswordCheck"`
+	// NewPasswordCheck is the rotation check encrypted with the new password.
+	NewPasswordCheck []byte `json:"newPasswordCheck"`
+}
+
+// PasswordRotationProgress describes the progress of the storage password
+// rotation.
+type PasswordRotationProgress struct {
+	// TotalFiles is the number of all storage files.
+	TotalFiles int
+	// RotatedFiles is the number of files re-encrypted so far.
+	RotatedFiles int
+	// SkippedFiles is the number of files already encrypted with the new
+	// password by an interrupted rotation.
+	SkippedFiles int
+}
+
+// RotatePassword re-encrypts all files held in the keystore, work and
+// quarantine directories of the storage with the new password. The rotation is refused
+// if the storage is used by another process, e.g. a running client.
+//
+// The rotation is recorded in the storage before any file is re-encrypted
+// and the storage cannot be used until the rotation completes. Each file is
+// replaced atomically so an interrupted rotation leaves every file encrypted
+// with either the old or the new password. An interrupted rotation is
+// resumed by calling this function again with the same old and new
+// passwords; files already encrypted with the new password are skipped.
+//
+// The optional progress function is called after each processed file.
+// Once the rotation completes, the storage uses the new password.
+func (s *Storage) RotatePassword(
+	newPassword string,
+	progressFn func(progress PasswordRotationProgress),
+) (*PasswordRotationProgress, error) {
+	if newPassword == s.encryptionPassword {
+		return nil, fmt.Errorf("new password must differ from the old one")
+	}
+
+	lockFile, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer lockFile.Close()
+
+	oldBox := newPasswordBox(s.encryptionPassword)
+	newBox := newPasswordBox(newPassword)
+
+	if err := s.startPasswordRotation(oldBox, newBox); err != nil {
+		return nil, err
+	}
+
+	if err := s.removeTempFiles(); err != nil {
+		return nil, fmt.Errorf("cannot remove temporary files: [%w]", err)
+	}
+
+	files, err := s.readBackupFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &PasswordRotationProgress{TotalFiles: len(files)}
+
+	for _, file := range files {
+		if _, err := newBox.Decrypt(file.content); err == nil {
+			progress.SkippedFiles++
+		} else {
+			if err := s.rotateFile(file, oldBox, newBox); err != nil {
+				return nil, fmt.Errorf(
+					"cannot rotate password of file [%s]: [%w]",
+					file.path,
+					err,
+				)
+			}
+
+			progress.RotatedFiles++
+		}
+
+		if progressFn != nil {
+			progressFn(*progress)
+		}
+	}
+
+	if err := os.Remove(s.passwordRotationFilePath()); err != nil {
+		return nil, fmt.Errorf(
+			"cannot complete password rotation: [%w]",
+			err,
+		)
+	}
+
+	s.encryptionPassword = newPassword
+
+	return progress, nil
+}
+
+// startPasswordRotation records the password rotation in the storage. If
+// a rotation is already recorded, makes sure it is resumed with the same
+// passwords.
+func (s *Storage) startPasswordRotation(oldBox, newBox encryption.Box) error {
+	rotationBytes, err := ioutil.ReadFile(s.passwordRotationFilePath())
+	if err == nil {
+		rotation := &passwordRotation{}
+		if err := json.Unmarshal(rotationBytes, rotation); err != nil {
+			return fmt.Errorf(
+				"cannot unmarshal password rotation state: [%w]",
+				err,
+			)
+		}
+
+		if _, err := oldBox.Decrypt(rotation.OldPasswordCheck); err != nil {
+			return fmt.Errorf(
+				"old password does not match the one of the interrupted " +
+					"password rotation",
+			)
+		}
+
+		if _, err := newBox.Decrypt(rotation.NewPasswordCheck); err != nil {
+			return fmt.Errorf(
+				"new password does not match the one of the interrupted " +
+					"password rotation",
+			)
+		}
+
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return fmt.Errorf("cannot read password rotation state: [%w]", err)
+	}
+
+	oldPasswordCheck, err := oldBox.Encrypt([]byte(passwordRotationCheck))
+	if err != nil {
+		return err
+	}
+
+	newPasswordCheck, err := newBox.Encrypt([]byte(passwordRotationCheck))
+	if err != nil {
+		return err
+	}
+
+	rotationBytes, err = json.Marshal(&passwordRotation{
+		StartedAt:        time.Now(),
+		OldPasswordCheck: oldPasswordCheck,
+		NewPasswordCheck: newPasswordCheck,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot marshal password rotation state: [%w]", err)
+	}
+
+	if err := writeFileAtomically(
+		s.passwordRotationFilePath(),
+		rotationBytes,
+		time.Now(),
+	); err != nil {
+		return fmt.Errorf("cannot save password rotation state: [%w]", err)
+	}
+
+	return nil
+}
+
+// rotateFile re-encrypts the given storage file with the new password.
+func (s *Storage) rotateFile(
+	file *backupFile,
+	oldBox, newBox encryption.Box,
+) error {
+	content, err := oldBox.Decrypt(file.content)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot decrypt with either the old or the new password: [%w]",
+			err,
+		)
+	}
+
+	encryptedContent, err := newBox.Encrypt(content)
+	if err != nil {
+		return fmt.Errorf("cannot encrypt with the new password: [%w]", err)
+	}
+
+	return writeFileAtomically(
+		s.backupFilePath(file),
+		encryptedContent,
+		file.modTime,
+	)
+}
+
+// removeTempFiles removes temporary files left in the data directories of
+// the storage by an interrupted operation.
+func (s *Storage) removeTempFiles() error {
+	dirs, err := s.dataDirs()
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(
+			filePath string,
+			info os.FileInfo,
+			err error,
+		) error {
+			if err != nil {
+				return err
+			}
+
+			if info.Mode().IsRegular() &&
+				strings.Contains(info.Name(), tempFileMarker) {
+				return os.Remove(filePath)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkPasswordRotation returns ErrPasswordRotationInProgress if the storage
+// password rotation was interrupted.
+func (s *Storage) checkPasswordRotation() error {
+	_, err := os.Stat(s.passwordRotationFilePath())
+	if err == nil {
+		return ErrPasswordRotationInProgress
+	}
+	if !os.IsNotExist(err) {
+		return fmt.Errorf("cannot check password rotation state: [%w]", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) passwordRotationFilePath() string {
+	return filepath.Join(s.rootDir, passwordRotationFileName)
+}
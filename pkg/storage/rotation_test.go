@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+func TestRotatePassword(t *testing.T) {
+	storage := initializeTestStorage(t, testStoragePassword)
+	populateTestStorage(t, storage)
+
+	var progressCalls int
+	progress, err := storage.RotatePassword(
+		"new-password",
+		func(progress PasswordRotationProgress) {
+			progressCalls++
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "total files", 3, progress.TotalFiles)
+	testutils.AssertIntsEqual(t, "rotated files", 3, progress.RotatedFiles)
+	testutils.AssertIntsEqual(t, "skipped files", 0, progress.SkippedFiles)
+	testutils.AssertIntsEqual(t, "progress calls", 3, progressCalls)
+
+	assertTestStorageContent(t, storage.rootDir, "new-password")
+
+	// The old password must no longer work.
+	assertTestStorageUnreadable(t, storage, testStoragePassword)
+}
+
+func TestRotatePassword_Resume(t *testing.T) {
+	storage := initializeTestStorage(t, testStoragePassword)
+	populateTestStorage(t, storage)
+
+	// Simulate a rotation interrupted after the first file was re-encrypted
+	// and a temporary file was left behind.
+	if err := storage.startPasswordRotation(
+		newPasswordBox(testStoragePassword),
+		newPasswordBox("new-password"),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := storage.readBackupFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.rotateFile(
+		files[0],
+		newPasswordBox(testStoragePassword),
+		newPasswordBox("new-password"),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tempFilePath := filepath.Join(
+		filepath.Dir(storage.backupFilePath(files[1])),
+		"."+filepath.Base(files[1].path)+tempFileMarker+"123",
+	)
+	if err := os.WriteFile(tempFilePath, []byte("partial"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The storage must not be used until the rotation completes.
+	_, err = storage.InitializeKeyStorePersistence("tbtc")
+	testutils.AssertErrorsSame(t, ErrPasswordRotationInProgress, err)
+
+	// Resuming with different passwords must be refused.
+	_, err = storage.RotatePassword("other-password", nil)
+	if err == nil || !strings.Contains(err.Error(), "new password does not match") {
+		t.Fatalf("unexpected error: [%v]", err)
+	}
+
+	progress, err := storage.RotatePassword("new-password", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "total files", 3, progress.TotalFiles)
+	testutils.AssertIntsEqual(t, "rotated files", 2, progress.RotatedFiles)
+	testutils.AssertIntsEqual(t, "skipped files", 1, progress.SkippedFiles)
+
+	if _, err := os.Stat(tempFilePath); !os.IsNotExist(err) {
+		t.Errorf("temporary file was not removed")
+	}
+
+	assertTestStorageContent(t, storage.rootDir, "new-password")
+}
+
+func TestRotatePassword_Quarantine(t *testing.T) {
+	storage := initializeTestStorage(t, testStoragePassword)
+	populateTestStorage(t, storage)
+
+	if err := storage.quarantineFile("work/tbtc/preparams/pp_1"); err != nil {
+		t.Fatal(err)
+	}
+
+	progress, err := storage.RotatePassword("new-password", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "total files", 3, progress.TotalFiles)
+	testutils.AssertIntsEqual(t, "rotated files", 3, progress.RotatedFiles)
+
+	// The quarantined file must be readable with the new password.
+	content, err := os.ReadFile(
+		filepath.Join(storage.rootDir, "quarantine/work/tbtc/preparams/pp_1"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newPasswordBox("new-password").Decrypt(content); err != nil {
+		t.Errorf("quarantined file is not readable with the new password")
+	}
+}
+
+func TestRotatePassword_SamePassword(t *testing.T) {
+	storage := initializeTestStorage(t, testStoragePassword)
+
+	_, err := storage.RotatePassword(testStoragePassword, nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRotatePassword_StorageLocked(t *testing.T) {
+	storage := initializeTestStorage(t, testStoragePassword)
+
+	lockFile, err := storage.lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lockFile.Close()
+
+	_, err = storage.RotatePassword("new-password", nil)
+	testutils.AssertErrorsSame(t, ErrStorageLocked, err)
+}
+
+// populateTestStorage saves two keystore files and one work file in the
+// given storage.
+func populateTestStorage(t *testing.T, storage *Storage) {
+	keyStorePersistence, err := storage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"membership_1", "membership_2"} {
+		if err := keyStorePersistence.Save(
+			[]byte(name),
+			"wallet_1",
+			name,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	workPersistence, err := storage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := workPersistence.Save([]byte("pp_1"), "preparams", "pp_1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// assertTestStorageContent asserts the storage with the given root directory
+// holds files saved by populateTestStorage, readable with the given password.
+func assertTestStorageContent(t *testing.T, rootDir string, password string) {
+	storage, err := Initialize(Config{
+		Dir:                rootDir,
+		EncryptionPassword: password,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyStorePersistence, err := storage.InitializeKeyStorePersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	workPersistence, err := storage.InitializeWorkPersistence("tbtc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var contents []string
+
+	keyStoreDescriptors, keyStoreErrors := keyStorePersistence.ReadAll()
+	workDescriptors, workErrors := workPersistence.ReadAll()
+	go func() {
+		for err := range keyStoreErrors {
+			t.Errorf("unexpected error: [%v]", err)
+		}
+	}()
+	go func() {
+		for err := range workErrors {
+			t.Errorf("unexpected error: [%v]", err)
+		}
+	}()
+
+	for descriptor := range keyStoreDescriptors {
+		content, err := descriptor.Content()
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, string(content))
+	}
+	for descriptor := range workDescriptors {
+		content, err := descriptor.Content()
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, string(content))
+	}
+
+	sort.Strings(contents)
+
+	expectedContents := []string{"membership_1", "membership_2", "pp_1"}
+	if !reflect.DeepEqual(expectedContents, contents) {
+		t.Errorf(
+			"unexpected storage content\nexpected: %v\nactual:   %v\n",
+			expectedContents,
+			contents,
+		)
+	}
+}
+
+// assertTestStorageUnreadable asserts no file of the given storage can be
+// decrypted with the given password.
+func assertTestStorageUnreadable(
+	t *testing.T,
+	storage *Storage,
+	password string,
+) {
+	files, err := storage.readBackupFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	box := newPasswordBox(password)
+	for _, file := range files {
+		if _, err := box.Decrypt(file.content); err == nil {
+			t.Errorf("file [%s] is readable with the password", file.path)
+		}
+	}
+}
@@ -2,8 +2,11 @@ package storage
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/keep-network/keep-common/pkg/persistence"
 )
@@ -12,6 +15,9 @@ import (
 type Config struct {
 	// Path to the persistent storage directory on disk.
 	Dir string
+	// Password used to encrypt data persisted in the storage. If not set,
+	// the Ethereum account key file password is used.
+	EncryptionPassword string
 }
 
 const (
@@ -25,22 +31,27 @@ const (
 	// lead to losing rewards as a result of inactivity but is not
 	// a protocol violation.
 	workDirName = "work"
+	// tempFileMarker is part of names of temporary files created while
+	// storage files are replaced.
+	tempFileMarker = ".tmp-"
 )
 
 // Storage is a disk persistent storage for the client.
 type Storage struct {
+	rootDir            string
 	keystoreDir        string
 	workDir            string
 	encryptionPassword string
 }
 
 // Initialize initializes a disk storage with `keystore` and `work` directories.
-// The provided `encryptionPassword` will be used to encrypt the work persisted
-// to the storage.
-func Initialize(config Config, encryptionPassword string) (Storage, error) {
+// The encryption password from the config will be used to encrypt the work
+// persisted to the storage.
+func Initialize(config Config) (Storage, error) {
 	storage := Storage{}
 
 	storageRootDir := filepath.Clean(config.Dir)
+	storage.rootDir = storageRootDir
 
 	if err := persistence.EnsureDirectoryExists(
 		storageRootDir,
@@ -64,24 +75,40 @@ func Initialize(config Config, encryptionPassword string) (Storage, error) {
 	}
 	storage.workDir = filepath.Join(storageRootDir, workDirName)
 
-	storage.encryptionPassword = encryptionPassword
+	storage.encryptionPassword = config.EncryptionPassword
 
 	return storage, nil
 }
 
 // InitializeKeyStorePersistence initializes a disk persistence under keystore parent.
+// Files of the returned handle failing validation can be moved to the
+// quarantine directory of the storage with the handle's Quarantine method.
+// It returns ErrPasswordRotationInProgress if the storage password rotation
+// was interrupted.
 func (s *Storage) InitializeKeyStorePersistence(dir string) (
 	persistence.ProtectedHandle,
 	error,
 ) {
+	if err := s.checkPasswordRotation(); err != nil {
+		return nil, err
+	}
+
 	return s.initializeKeyStorePersistence(s.keystoreDir, dir)
 }
 
 // InitializeWorkPersistence initializes a disk persistence under work parent.
+// Files of the returned handle failing validation can be moved to the
+// quarantine directory of the storage with the handle's Quarantine method.
+// It returns ErrPasswordRotationInProgress if the storage password rotation
+// was interrupted.
 func (s *Storage) InitializeWorkPersistence(dir string) (
 	persistence.BasicHandle,
 	error,
 ) {
+	if err := s.checkPasswordRotation(); err != nil {
+		return nil, err
+	}
+
 	return s.initializeWorkPersistence(s.workDir, dir)
 }
 
@@ -107,10 +134,18 @@ func (s *Storage) initializeKeyStorePersistence(parentDir string, dir string) (
 		return nil, fmt.Errorf("cannot create [%s] disk handle: [%w]", path, err)
 	}
 
-	return persistence.NewEncryptedProtectedPersistence(
-		diskHandle,
-		s.encryptionPassword,
-	), nil
+	return &quarantinableProtectedHandle{
+		ProtectedHandle: persistence.NewEncryptedProtectedPersistence(
+			diskHandle,
+			s.encryptionPassword,
+		),
+		storage: s,
+		relativeDir: filepath.Join(
+			keyStoreDirName,
+			dir,
+			keyStoreCurrentDirName,
+		),
+	}, nil
 }
 
 // initializeWorkPersistence creates a persistent directory under a parent directory.
@@ -135,8 +170,55 @@ func (s *Storage) initializeWorkPersistence(parentDir string, dir string) (
 		return nil, fmt.Errorf("cannot create [%s] disk handle: [%w]", path, err)
 	}
 
-	return persistence.NewEncryptedBasicPersistence(
-		diskHandle,
-		s.encryptionPassword,
-	), nil
+	return &quarantinableBasicHandle{
+		BasicHandle: persistence.NewEncryptedBasicPersistence(
+			diskHandle,
+			s.encryptionPassword,
+		),
+		storage:     s,
+		relativeDir: filepath.Join(workDirName, dir),
+	}, nil
+}
+
+// writeFileAtomically writes the given content to the file with the given
+// path. The content is written to a temporary file first and then moved to
+// the target path so the target file is never left partially written.
+// The modification time of the written file is set to the given one.
+func writeFileAtomically(
+	filePath string,
+	content []byte,
+	modTime time.Time,
+) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	tempFile, err := ioutil.TempFile(
+		filepath.Dir(filePath),
+		"."+filepath.Base(filePath)+tempFileMarker,
+	)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(content); err != nil {
+		_ = tempFile.Close()
+		return err
+	}
+
+	if err := tempFile.Sync(); err != nil {
+		_ = tempFile.Close()
+		return err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chtimes(tempFile.Name(), modTime, modTime); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), filePath)
 }
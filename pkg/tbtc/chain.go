@@ -2,8 +2,10 @@ package tbtc
 
 import (
 	"crypto/ecdsa"
+	"fmt"
 	"math/big"
 
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/operator"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
@@ -116,6 +118,16 @@ type DistributedKeyGenerationChain interface {
 
 	// DKGParameters gets the current value of DKG-specific control parameters.
 	DKGParameters() (*DKGParameters, error)
+
+	// OnDKGParametersUpdated registers a callback that is invoked when an
+	// on-chain notification of the DKG parameters update is seen.
+	OnDKGParametersUpdated(
+		func(event *DKGParametersUpdatedEvent),
+	) subscription.EventSubscription
+
+	// GroupParameters gets the current value of group parameters used to
+	// generate new groups.
+	GroupParameters() (*GroupParameters, error)
 }
 
 // DKGChainResultHash represents a hash of the DKGChainResult. The algorithm
@@ -172,6 +184,11 @@ type DKGResultApprovedEvent struct {
 	BlockNumber uint64
 }
 
+// DKGParametersUpdatedEvent represents a DKG parameters update event.
+type DKGParametersUpdatedEvent struct {
+	BlockNumber uint64
+}
+
 // DKGParameters contains values of DKG-specific control parameters.
 type DKGParameters struct {
 	SubmissionTimeoutBlocks       uint64
@@ -187,6 +204,123 @@ type BridgeChain interface {
 	OnHeartbeatRequested(
 		func(event *HeartbeatRequestedEvent),
 	) subscription.EventSubscription
+
+	// OnRedemptionRequested registers a callback that is invoked when an
+	// on-chain notification of a new redemption request is seen.
+	OnRedemptionRequested(
+		func(event *RedemptionRequestedEvent),
+	) subscription.EventSubscription
+
+	// PastRedemptionRequestedEvents fetches past redemption requested events
+	// according to the provided filter or unfiltered if the filter is nil.
+	// Returned events are sorted by the block number in the ascending order,
+	// i.e. the latest event is at the end of the slice.
+	PastRedemptionRequestedEvents(
+		filter *RedemptionRequestedEventFilter,
+	) ([]*RedemptionRequestedEvent, error)
+
+	// GetPendingRedemptionRequest gets the on-chain pending redemption request
+	// for the given wallet public key hash and redeemer output script, as of
+	// the given block. The returned bool value indicates whether the request
+	// was pending at that block or not.
+	GetPendingRedemptionRequest(
+		walletPublicKeyHash [20]byte,
+		redeemerOutputScript bitcoin.Script,
+		blockNumber uint64,
+	) (*RedemptionRequest, bool, error)
+
+	// PastDepositsSweptEvents fetches past deposits swept events according
+	// to the provided filter or unfiltered if the filter is nil. Returned
+	// events are sorted by the block number in the ascending order, i.e. the
+	// latest event is at the end of the slice.
+	PastDepositsSweptEvents(
+		filter *WalletTransactionEventFilter,
+	) ([]*DepositsSweptEvent, error)
+
+	// PastRedemptionsCompletedEvents fetches past redemptions completed events
+	// according to the provided filter or unfiltered if the filter is nil.
+	// Returned events are sorted by the block number in the ascending order,
+	// i.e. the latest event is at the end of the slice.
+	PastRedemptionsCompletedEvents(
+		filter *WalletTransactionEventFilter,
+	) ([]*RedemptionsCompletedEvent, error)
+
+	// PastMovedFundsSweptEvents fetches past moved funds swept events
+	// according to the provided filter or unfiltered if the filter is nil.
+	// Returned events are sorted by the block number in the ascending order,
+	// i.e. the latest event is at the end of the slice.
+	PastMovedFundsSweptEvents(
+		filter *WalletTransactionEventFilter,
+	) ([]*MovedFundsSweptEvent, error)
+
+	// GetWallet gets the on-chain data for the given wallet. Returns an error
+	// if the wallet was not found.
+	GetWallet(walletPublicKeyHash [20]byte) (*WalletChainData, error)
+
+	// ComputeMainUtxoHash computes the hash of the provided main UTXO
+	// according to the on-chain Bridge rules.
+	ComputeMainUtxoHash(mainUtxo *bitcoin.UnspentTransactionOutput) [32]byte
+
+	// ComputeMovingFundsTargetWalletsCommitmentHash computes the hash of
+	// the provided target wallets of a moving funds commitment according to
+	// the on-chain Bridge rules.
+	ComputeMovingFundsTargetWalletsCommitmentHash(
+		targetWallets [][20]byte,
+	) [32]byte
+
+	// OnDepositSweepProposalSubmitted registers a callback that is invoked
+	// when an on-chain notification of the deposit sweep proposal submission
+	// is seen.
+	OnDepositSweepProposalSubmitted(
+		func(event *DepositSweepProposalSubmittedEvent),
+	) subscription.EventSubscription
+
+	// PastDepositRevealedEvents fetches past deposit reveal events according
+	// to the provided filter or unfiltered if the filter is nil. Returned
+	// events are sorted by the block number in the ascending order, i.e. the
+	// latest event is at the end of the slice.
+	PastDepositRevealedEvents(
+		filter *DepositRevealedEventFilter,
+	) ([]*DepositRevealedEvent, error)
+
+	// GetDepositRequest gets the on-chain deposit request for the given
+	// funding transaction hash and output index. The returned bool value
+	// indicates whether the request was found or not.
+	GetDepositRequest(
+		fundingTxHash bitcoin.Hash,
+		fundingOutputIndex uint32,
+	) (*DepositChainRequest, bool, error)
+
+	// OnMovingFundsCommitmentSubmitted registers a callback that is invoked
+	// when an on-chain notification of the moving funds commitment
+	// submission is seen.
+	OnMovingFundsCommitmentSubmitted(
+		func(event *MovingFundsCommitmentSubmittedEvent),
+	) subscription.EventSubscription
+
+	// OnWalletClosed registers a callback that is invoked when an on-chain
+	// notification of the wallet closure is seen.
+	OnWalletClosed(
+		func(event *WalletClosedEvent),
+	) subscription.EventSubscription
+
+	// OnWalletTerminated registers a callback that is invoked when an
+	// on-chain notification of the wallet termination is seen.
+	OnWalletTerminated(
+		func(event *WalletTerminatedEvent),
+	) subscription.EventSubscription
+
+	// GetMovingFundsParameters gets the current value of parameters
+	// relevant for the moving funds process.
+	GetMovingFundsParameters() (*MovingFundsParameters, error)
+
+	// GetDepositParameters gets the current value of parameters relevant
+	// for the deposit process.
+	GetDepositParameters() (*DepositParameters, error)
+
+	// GetRedemptionParameters gets the current value of parameters relevant
+	// for the redemption process.
+	GetRedemptionParameters() (*RedemptionParameters, error)
 }
 
 // HeartbeatRequestedEvent represents a Bridge heartbeat request event.
@@ -196,6 +330,244 @@ type HeartbeatRequestedEvent struct {
 	BlockNumber     uint64
 }
 
+// RedemptionRequestedEvent represents a redemption request event.
+type RedemptionRequestedEvent struct {
+	WalletPublicKeyHash  [20]byte
+	RedeemerOutputScript bitcoin.Script
+	Redeemer             chain.Address
+	RequestedAmount      uint64
+	TreasuryFee          uint64
+	TxMaxFee             uint64
+	BlockNumber          uint64
+}
+
+// RedemptionRequestedEventFilter is a component allowing to filter
+// RedemptionRequestedEvent.
+type RedemptionRequestedEventFilter struct {
+	StartBlock          uint64
+	EndBlock            *uint64
+	WalletPublicKeyHash [][20]byte
+	Redeemer            []chain.Address
+}
+
+// RedemptionRequest represents a pending redemption request registered
+// on-chain.
+type RedemptionRequest struct {
+	// Redeemer is the redeemer's address on the host chain.
+	Redeemer chain.Address
+	// RedeemerOutputScript is the output script the redeemed Bitcoin funds are
+	// locked to. The script is not prepended with its length.
+	RedeemerOutputScript bitcoin.Script
+	// RequestedAmount is the TBTC amount in satoshi requested for redemption.
+	RequestedAmount uint64
+	// TreasuryFee is the treasury TBTC fee in satoshi at the moment of request
+	// creation.
+	TreasuryFee uint64
+	// TxMaxFee is the maximum value of the per-redemption BTC tx fee in satoshi
+	// that can be incurred by this request, determined at the moment of
+	// request creation.
+	TxMaxFee uint64
+	// RequestedAt is the UNIX timestamp at which the request was created.
+	RequestedAt uint32
+}
+
+// WalletTransactionEventFilter is a component allowing to filter events
+// informing about Bitcoin transactions performed by the given wallets.
+type WalletTransactionEventFilter struct {
+	StartBlock          uint64
+	EndBlock            *uint64
+	WalletPublicKeyHash [][20]byte
+}
+
+// DepositsSweptEvent represents a deposits swept event. It is emitted
+// after the deposit sweep transaction proof is accepted by the chain.
+type DepositsSweptEvent struct {
+	WalletPublicKeyHash [20]byte
+	SweepTxHash         bitcoin.Hash
+	BlockNumber         uint64
+}
+
+// RedemptionsCompletedEvent represents a redemptions completed event. It is
+// emitted after the redemption transaction proof is accepted by the chain.
+type RedemptionsCompletedEvent struct {
+	WalletPublicKeyHash [20]byte
+	RedemptionTxHash    bitcoin.Hash
+	BlockNumber         uint64
+}
+
+// MovedFundsSweptEvent represents a moved funds swept event. It is emitted
+// after the moved funds sweep transaction proof is accepted by the chain.
+type MovedFundsSweptEvent struct {
+	WalletPublicKeyHash [20]byte
+	SweepTxHash         bitcoin.Hash
+	BlockNumber         uint64
+}
+
+// WalletChainData represents wallet data stored on-chain.
+type WalletChainData struct {
+	EcdsaWalletID                          [32]byte
+	MainUtxoHash                           [32]byte
+	PendingRedemptionsValue                uint64
+	CreatedAt                              uint32
+	MovingFundsRequestedAt                 uint32
+	ClosingStartedAt                       uint32
+	State                                  WalletState
+	MovingFundsTargetWalletsCommitmentHash [32]byte
+}
+
+// WalletState represents the state of a wallet in the Bridge.
+type WalletState uint8
+
+const (
+	// StateUnknown denotes a wallet that is not registered in the Bridge.
+	StateUnknown WalletState = iota
+	// StateLive denotes a wallet that can perform all actions.
+	StateLive
+	// StateMovingFunds denotes a wallet that must move its funds to other
+	// wallets.
+	StateMovingFunds
+	// StateClosing denotes a wallet that moved its funds and awaits the end
+	// of the closing period.
+	StateClosing
+	// StateClosed denotes a wallet that is closed and does not perform any
+	// actions anymore.
+	StateClosed
+	// StateTerminated denotes a wallet that was terminated as a result of
+	// misbehavior and does not perform any actions anymore.
+	StateTerminated
+)
+
+func (ws WalletState) String() string {
+	switch ws {
+	case StateUnknown:
+		return "Unknown"
+	case StateLive:
+		return "Live"
+	case StateMovingFunds:
+		return "MovingFunds"
+	case StateClosing:
+		return "Closing"
+	case StateClosed:
+		return "Closed"
+	case StateTerminated:
+		return "Terminated"
+	default:
+		return fmt.Sprintf("Unrecognized(%v)", uint8(ws))
+	}
+}
+
+// MovingFundsCommitmentSubmittedEvent represents a moving funds commitment
+// submission event. It is emitted once the target wallets of the moving
+// funds process are committed on-chain.
+type MovingFundsCommitmentSubmittedEvent struct {
+	WalletPublicKeyHash [20]byte
+	TargetWallets       [][20]byte
+	Submitter           chain.Address
+	BlockNumber         uint64
+}
+
+// MovingFundsCommitmentSubmittedEventFilter is a component allowing to
+// filter MovingFundsCommitmentSubmittedEvent.
+type MovingFundsCommitmentSubmittedEventFilter struct {
+	StartBlock          uint64
+	EndBlock            *uint64
+	WalletPublicKeyHash [][20]byte
+}
+
+// WalletClosedEvent represents a wallet closure event.
+type WalletClosedEvent struct {
+	EcdsaWalletID       [32]byte
+	WalletPublicKeyHash [20]byte
+	BlockNumber         uint64
+}
+
+// WalletTerminatedEvent represents a wallet termination event.
+type WalletTerminatedEvent struct {
+	EcdsaWalletID       [32]byte
+	WalletPublicKeyHash [20]byte
+	BlockNumber         uint64
+}
+
+// DepositParameters contains values of parameters relevant for the deposit
+// process.
+type DepositParameters struct {
+	// TxMaxFee is the maximum value of the per-deposit BTC tx fee in satoshi
+	// that can be incurred by the deposit sweep transaction.
+	TxMaxFee uint64
+}
+
+// RedemptionParameters contains values of parameters relevant for the
+// redemption process.
+type RedemptionParameters struct {
+	// TxMaxTotalFee is the maximum total fee in satoshi that can be
+	// incurred by the redemption transaction, regardless of the number
+	// of redemption requests it handles.
+	TxMaxTotalFee uint64
+}
+
+// MovingFundsParameters contains values of parameters relevant for the
+// moving funds process.
+type MovingFundsParameters struct {
+	// TxMaxTotalFee is the maximum total fee in satoshi that can be
+	// incurred by the moving funds transaction.
+	TxMaxTotalFee uint64
+	// DustThreshold is the minimal main UTXO value in satoshi that must be
+	// moved. Wallets holding less are closed without moving funds.
+	DustThreshold uint64
+}
+
+// DepositSweepProposal represents a deposit sweep proposal submitted to
+// the chain.
+type DepositSweepProposal struct {
+	WalletPublicKeyHash [20]byte
+	DepositsKeys        []struct {
+		FundingTxHash      bitcoin.Hash
+		FundingOutputIndex uint32
+	}
+	SweepTxFee uint64
+}
+
+// DepositSweepProposalSubmittedEvent represents a deposit sweep proposal
+// submission event.
+type DepositSweepProposalSubmittedEvent struct {
+	Proposal          *DepositSweepProposal
+	ProposalSubmitter chain.Address
+	BlockNumber       uint64
+}
+
+// DepositRevealedEvent represents a deposit reveal event.
+type DepositRevealedEvent struct {
+	FundingTxHash       bitcoin.Hash
+	FundingOutputIndex  uint32
+	Depositor           chain.Address
+	Amount              uint64
+	BlindingFactor      [8]byte
+	WalletPublicKeyHash [20]byte
+	RefundPublicKeyHash [20]byte
+	RefundLocktime      [4]byte
+	Vault               *chain.Address
+	BlockNumber         uint64
+}
+
+// DepositRevealedEventFilter is a component allowing to filter
+// DepositRevealedEvent.
+type DepositRevealedEventFilter struct {
+	StartBlock          uint64
+	EndBlock            *uint64
+	Depositor           []chain.Address
+	WalletPublicKeyHash [][20]byte
+}
+
+// DepositChainRequest represents a deposit request stored on-chain.
+type DepositChainRequest struct {
+	Depositor   chain.Address
+	Amount      uint64
+	RevealedAt  uint32
+	Vault       *chain.Address
+	TreasuryFee uint64
+	SweptAt     uint32
+}
+
 // Chain represents the interface that the TBTC module expects to interact
 // with the anchoring blockchain on.
 type Chain interface {
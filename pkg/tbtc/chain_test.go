@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"sync"
 
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/chain/local_v1"
 	"github.com/keep-network/keep-core/pkg/operator"
@@ -38,6 +39,23 @@ type localChain struct {
 	dkgResult      *DKGChainResult
 	dkgResultValid bool
 
+	groupParametersMutex sync.Mutex
+	groupParameters      *GroupParameters
+
+	walletsMutex               sync.Mutex
+	wallets                    map[[20]byte]*WalletChainData
+	depositsSweptEvents        []*DepositsSweptEvent
+	redemptionsCompletedEvents []*RedemptionsCompletedEvent
+	movedFundsSweptEvents      []*MovedFundsSweptEvent
+
+	depositsMutex         sync.Mutex
+	depositRequests       map[string]*DepositChainRequest
+	depositRevealedEvents []*DepositRevealedEvent
+
+	redemptionsMutex          sync.Mutex
+	redemptionRequestedEvents []*RedemptionRequestedEvent
+	redemptionRequests        map[string]*localRedemptionRequest
+
 	blockCounter       chain.BlockCounter
 	operatorPrivateKey *operator.PrivateKey
 }
@@ -412,12 +430,413 @@ func (lc *localChain) DKGParameters() (*DKGParameters, error) {
 	}, nil
 }
 
+func (lc *localChain) OnDKGParametersUpdated(
+	handler func(event *DKGParametersUpdatedEvent),
+) subscription.EventSubscription {
+	panic("unsupported")
+}
+
+func (lc *localChain) GroupParameters() (*GroupParameters, error) {
+	lc.groupParametersMutex.Lock()
+	defer lc.groupParametersMutex.Unlock()
+
+	if lc.groupParameters == nil {
+		return nil, fmt.Errorf("group parameters not set")
+	}
+
+	return lc.groupParameters, nil
+}
+
+func (lc *localChain) setGroupParameters(groupParameters *GroupParameters) {
+	lc.groupParametersMutex.Lock()
+	defer lc.groupParametersMutex.Unlock()
+
+	lc.groupParameters = groupParameters
+}
+
 func (lc *localChain) OnHeartbeatRequested(
 	handler func(event *HeartbeatRequestedEvent),
 ) subscription.EventSubscription {
 	panic("unsupported")
 }
 
+func (lc *localChain) OnRedemptionRequested(
+	handler func(event *RedemptionRequestedEvent),
+) subscription.EventSubscription {
+	panic("unsupported")
+}
+
+// localRedemptionRequest is a redemption request registered on the local
+// chain along with the block range it is pending in.
+type localRedemptionRequest struct {
+	request        *RedemptionRequest
+	requestedBlock uint64
+	// handledBlock is the block the request stopped being pending at or
+	// zero if the request is still pending.
+	handledBlock uint64
+}
+
+func (lc *localChain) PastRedemptionRequestedEvents(
+	filter *RedemptionRequestedEventFilter,
+) ([]*RedemptionRequestedEvent, error) {
+	lc.redemptionsMutex.Lock()
+	defer lc.redemptionsMutex.Unlock()
+
+	events := make([]*RedemptionRequestedEvent, 0)
+	for _, event := range lc.redemptionRequestedEvents {
+		transactionFilter := &WalletTransactionEventFilter{
+			StartBlock:          filter.StartBlock,
+			EndBlock:            filter.EndBlock,
+			WalletPublicKeyHash: filter.WalletPublicKeyHash,
+		}
+
+		if transactionFilter.matches(
+			event.WalletPublicKeyHash,
+			event.BlockNumber,
+		) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (lc *localChain) GetPendingRedemptionRequest(
+	walletPublicKeyHash [20]byte,
+	redeemerOutputScript bitcoin.Script,
+	blockNumber uint64,
+) (*RedemptionRequest, bool, error) {
+	lc.redemptionsMutex.Lock()
+	defer lc.redemptionsMutex.Unlock()
+
+	redemptionRequest, ok := lc.redemptionRequests[buildRedemptionRequestKey(
+		walletPublicKeyHash,
+		redeemerOutputScript,
+	)]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if blockNumber < redemptionRequest.requestedBlock {
+		return nil, false, nil
+	}
+
+	if redemptionRequest.handledBlock != 0 &&
+		blockNumber >= redemptionRequest.handledBlock {
+		return nil, false, nil
+	}
+
+	return redemptionRequest.request, true, nil
+}
+
+// addRedemptionRequest registers the redemption request described by the
+// given event. The request is pending from the event block until the given
+// handled block. Zero handled block means the request is still pending.
+func (lc *localChain) addRedemptionRequest(
+	event *RedemptionRequestedEvent,
+	handledBlock uint64,
+) {
+	lc.redemptionsMutex.Lock()
+	defer lc.redemptionsMutex.Unlock()
+
+	lc.redemptionRequestedEvents = append(lc.redemptionRequestedEvents, event)
+
+	lc.redemptionRequests[buildRedemptionRequestKey(
+		event.WalletPublicKeyHash,
+		event.RedeemerOutputScript,
+	)] = &localRedemptionRequest{
+		request: &RedemptionRequest{
+			Redeemer:             event.Redeemer,
+			RedeemerOutputScript: event.RedeemerOutputScript,
+			RequestedAmount:      event.RequestedAmount,
+			TreasuryFee:          event.TreasuryFee,
+			TxMaxFee:             event.TxMaxFee,
+		},
+		requestedBlock: event.BlockNumber,
+		handledBlock:   handledBlock,
+	}
+}
+
+func buildRedemptionRequestKey(
+	walletPublicKeyHash [20]byte,
+	redeemerOutputScript bitcoin.Script,
+) string {
+	return fmt.Sprintf("%x:%x", walletPublicKeyHash, redeemerOutputScript)
+}
+
+func (lc *localChain) PastDepositsSweptEvents(
+	filter *WalletTransactionEventFilter,
+) ([]*DepositsSweptEvent, error) {
+	lc.walletsMutex.Lock()
+	defer lc.walletsMutex.Unlock()
+
+	events := make([]*DepositsSweptEvent, 0)
+	for _, event := range lc.depositsSweptEvents {
+		if filter.matches(event.WalletPublicKeyHash, event.BlockNumber) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (lc *localChain) PastRedemptionsCompletedEvents(
+	filter *WalletTransactionEventFilter,
+) ([]*RedemptionsCompletedEvent, error) {
+	lc.walletsMutex.Lock()
+	defer lc.walletsMutex.Unlock()
+
+	events := make([]*RedemptionsCompletedEvent, 0)
+	for _, event := range lc.redemptionsCompletedEvents {
+		if filter.matches(event.WalletPublicKeyHash, event.BlockNumber) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (lc *localChain) PastMovedFundsSweptEvents(
+	filter *WalletTransactionEventFilter,
+) ([]*MovedFundsSweptEvent, error) {
+	lc.walletsMutex.Lock()
+	defer lc.walletsMutex.Unlock()
+
+	events := make([]*MovedFundsSweptEvent, 0)
+	for _, event := range lc.movedFundsSweptEvents {
+		if filter.matches(event.WalletPublicKeyHash, event.BlockNumber) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (lc *localChain) GetWallet(
+	walletPublicKeyHash [20]byte,
+) (*WalletChainData, error) {
+	lc.walletsMutex.Lock()
+	defer lc.walletsMutex.Unlock()
+
+	walletChainData, ok := lc.wallets[walletPublicKeyHash]
+	if !ok {
+		return nil, fmt.Errorf("no wallet for given PKH")
+	}
+
+	return walletChainData, nil
+}
+
+func (lc *localChain) setWallet(
+	walletPublicKeyHash [20]byte,
+	walletChainData *WalletChainData,
+) {
+	lc.walletsMutex.Lock()
+	defer lc.walletsMutex.Unlock()
+
+	lc.wallets[walletPublicKeyHash] = walletChainData
+}
+
+func (lc *localChain) addDepositsSweptEvent(event *DepositsSweptEvent) {
+	lc.walletsMutex.Lock()
+	defer lc.walletsMutex.Unlock()
+
+	lc.depositsSweptEvents = append(lc.depositsSweptEvents, event)
+}
+
+func (lc *localChain) addRedemptionsCompletedEvent(
+	event *RedemptionsCompletedEvent,
+) {
+	lc.walletsMutex.Lock()
+	defer lc.walletsMutex.Unlock()
+
+	lc.redemptionsCompletedEvents = append(
+		lc.redemptionsCompletedEvents,
+		event,
+	)
+}
+
+func (lc *localChain) addMovedFundsSweptEvent(event *MovedFundsSweptEvent) {
+	lc.walletsMutex.Lock()
+	defer lc.walletsMutex.Unlock()
+
+	lc.movedFundsSweptEvents = append(lc.movedFundsSweptEvents, event)
+}
+
+func (lc *localChain) ComputeMainUtxoHash(
+	mainUtxo *bitcoin.UnspentTransactionOutput,
+) [32]byte {
+	outputIndexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(
+		outputIndexBytes,
+		mainUtxo.Outpoint.OutputIndex,
+	)
+
+	valueBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(valueBytes, uint64(mainUtxo.Value))
+
+	preimage := append(mainUtxo.Outpoint.TransactionHash[:], outputIndexBytes...)
+	preimage = append(preimage, valueBytes...)
+
+	return sha3.Sum256(preimage)
+}
+
+func (lc *localChain) ComputeMovingFundsTargetWalletsCommitmentHash(
+	targetWallets [][20]byte,
+) [32]byte {
+	packed := make([]byte, 0, 32*len(targetWallets))
+	for _, targetWallet := range targetWallets {
+		var element [32]byte
+		copy(element[:], targetWallet[:])
+		packed = append(packed, element[:]...)
+	}
+
+	return sha3.Sum256(packed)
+}
+
+func (lc *localChain) OnDepositSweepProposalSubmitted(
+	func(event *DepositSweepProposalSubmittedEvent),
+) subscription.EventSubscription {
+	panic("unsupported")
+}
+
+func (lc *localChain) PastDepositRevealedEvents(
+	filter *DepositRevealedEventFilter,
+) ([]*DepositRevealedEvent, error) {
+	lc.depositsMutex.Lock()
+	defer lc.depositsMutex.Unlock()
+
+	events := make([]*DepositRevealedEvent, 0)
+	for _, event := range lc.depositRevealedEvents {
+		transactionFilter := &WalletTransactionEventFilter{
+			StartBlock:          filter.StartBlock,
+			EndBlock:            filter.EndBlock,
+			WalletPublicKeyHash: filter.WalletPublicKeyHash,
+		}
+
+		if transactionFilter.matches(
+			event.WalletPublicKeyHash,
+			event.BlockNumber,
+		) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (lc *localChain) addDepositRevealedEvent(event *DepositRevealedEvent) {
+	lc.depositsMutex.Lock()
+	defer lc.depositsMutex.Unlock()
+
+	lc.depositRevealedEvents = append(lc.depositRevealedEvents, event)
+}
+
+func (lc *localChain) GetDepositRequest(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+) (*DepositChainRequest, bool, error) {
+	lc.depositsMutex.Lock()
+	defer lc.depositsMutex.Unlock()
+
+	request, ok := lc.depositRequests[buildDepositRequestKey(
+		fundingTxHash,
+		fundingOutputIndex,
+	)]
+
+	return request, ok, nil
+}
+
+func (lc *localChain) setDepositRequest(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+	request *DepositChainRequest,
+) {
+	lc.depositsMutex.Lock()
+	defer lc.depositsMutex.Unlock()
+
+	lc.depositRequests[buildDepositRequestKey(
+		fundingTxHash,
+		fundingOutputIndex,
+	)] = request
+}
+
+func (lc *localChain) OnMovingFundsCommitmentSubmitted(
+	func(event *MovingFundsCommitmentSubmittedEvent),
+) subscription.EventSubscription {
+	panic("unsupported")
+}
+
+func (lc *localChain) OnWalletClosed(
+	func(event *WalletClosedEvent),
+) subscription.EventSubscription {
+	panic("unsupported")
+}
+
+func (lc *localChain) OnWalletTerminated(
+	func(event *WalletTerminatedEvent),
+) subscription.EventSubscription {
+	panic("unsupported")
+}
+
+func (lc *localChain) GetMovingFundsParameters() (
+	*MovingFundsParameters,
+	error,
+) {
+	panic("unsupported")
+}
+
+func (lc *localChain) GetDepositParameters() (
+	*DepositParameters,
+	error,
+) {
+	panic("unsupported")
+}
+
+func (lc *localChain) GetRedemptionParameters() (
+	*RedemptionParameters,
+	error,
+) {
+	panic("unsupported")
+}
+
+func buildDepositRequestKey(
+	fundingTxHash bitcoin.Hash,
+	fundingOutputIndex uint32,
+) string {
+	return fmt.Sprintf("%s:%v", fundingTxHash.String(), fundingOutputIndex)
+}
+
+// matches checks whether the given wallet and block number match the filter.
+// A nil filter matches everything.
+func (wtef *WalletTransactionEventFilter) matches(
+	walletPublicKeyHash [20]byte,
+	blockNumber uint64,
+) bool {
+	if wtef == nil {
+		return true
+	}
+
+	if blockNumber < wtef.StartBlock {
+		return false
+	}
+
+	if wtef.EndBlock != nil && blockNumber > *wtef.EndBlock {
+		return false
+	}
+
+	if len(wtef.WalletPublicKeyHash) == 0 {
+		return true
+	}
+
+	for _, filterWalletPublicKeyHash := range wtef.WalletPublicKeyHash {
+		if filterWalletPublicKeyHash == walletPublicKeyHash {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (lc *localChain) operatorAddress() (chain.Address, error) {
 	_, operatorPublicKey, err := lc.OperatorKeyPair()
 	if err != nil {
@@ -452,8 +871,17 @@ func ConnectWithKey(operatorPrivateKey *operator.PrivateKey) *localChain {
 		dkgResultChallengeHandlers: make(
 			map[int]func(submission *DKGResultChallengedEvent),
 		),
+		wallets:            make(map[[20]byte]*WalletChainData),
+		depositRequests:    make(map[string]*DepositChainRequest),
+		redemptionRequests: make(map[string]*localRedemptionRequest),
 		blockCounter:       blockCounter,
 		operatorPrivateKey: operatorPrivateKey,
+		// Group parameters can be changed using setGroupParameters.
+		groupParameters: &GroupParameters{
+			GroupSize:       5,
+			GroupQuorum:     4,
+			HonestThreshold: 3,
+		},
 	}
 
 	return localChain
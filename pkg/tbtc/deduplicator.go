@@ -16,6 +16,9 @@ const (
 	// DKGResultHashCachePeriod is the time period the cache maintains
 	// the given DKG result hash.
 	DKGResultHashCachePeriod = 7 * 24 * time.Hour
+	// RedemptionRequestCachePeriod is the time period the cache maintains
+	// the given redemption request.
+	RedemptionRequestCachePeriod = 7 * 24 * time.Hour
 )
 
 // deduplicator decides whether the given event should be handled by the
@@ -31,15 +34,18 @@ const (
 // Those events are supported:
 // - DKG started
 // - DKG result submitted
+// - Redemption requested
 type deduplicator struct {
-	dkgSeedCache       *cache.TimeCache
-	dkgResultHashCache *cache.TimeCache
+	dkgSeedCache           *cache.TimeCache
+	dkgResultHashCache     *cache.TimeCache
+	redemptionRequestCache *cache.TimeCache
 }
 
 func newDeduplicator() *deduplicator {
 	return &deduplicator{
-		dkgSeedCache:       cache.NewTimeCache(DKGSeedCachePeriod),
-		dkgResultHashCache: cache.NewTimeCache(DKGResultHashCachePeriod),
+		dkgSeedCache:           cache.NewTimeCache(DKGSeedCachePeriod),
+		dkgResultHashCache:     cache.NewTimeCache(DKGResultHashCachePeriod),
+		redemptionRequestCache: cache.NewTimeCache(RedemptionRequestCachePeriod),
 	}
 }
 
@@ -90,3 +96,30 @@ func (d *deduplicator) notifyDKGResultSubmitted(
 	// proceed with the execution.
 	return false
 }
+
+// notifyRedemptionRequested notifies the client wants to handle the given
+// redemption request upon receiving an event. It returns boolean indicating
+// whether the client should proceed with the execution or ignore the event
+// as a duplicate.
+func (d *deduplicator) notifyRedemptionRequested(
+	walletPublicKeyHash [20]byte,
+	redeemerOutputScript []byte,
+	requestBlock uint64,
+) bool {
+	d.redemptionRequestCache.Sweep()
+
+	cacheKey := hex.EncodeToString(walletPublicKeyHash[:]) +
+		hex.EncodeToString(redeemerOutputScript) +
+		strconv.Itoa(int(requestBlock))
+
+	// If the key is not in the cache, that means the request was not handled
+	// yet and the client should proceed with the execution.
+	if !d.redemptionRequestCache.Has(cacheKey) {
+		d.redemptionRequestCache.Add(cacheKey)
+		return true
+	}
+
+	// Otherwise, the redemption request is a duplicate and the client should
+	// not proceed with the execution.
+	return false
+}
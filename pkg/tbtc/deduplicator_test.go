@@ -11,6 +11,7 @@ import (
 
 const testDKGSeedCachePeriod = 1 * time.Second
 const testDKGResultHashCachePeriod = 1 * time.Second
+const testRedemptionRequestCachePeriod = 1 * time.Second
 
 func TestNotifyDKGStarted(t *testing.T) {
 	deduplicator := deduplicator{
@@ -112,3 +113,55 @@ func TestNotifyDKGResultSubmitted(t *testing.T) {
 		t.Fatal("should be allowed to process")
 	}
 }
+
+func TestNotifyRedemptionRequested(t *testing.T) {
+	deduplicator := deduplicator{
+		redemptionRequestCache: cache.NewTimeCache(
+			testRedemptionRequestCachePeriod,
+		),
+	}
+
+	wallet1 := [20]byte{1}
+	wallet2 := [20]byte{2}
+	script1 := []byte{0x00, 0x14, 0x01}
+	script2 := []byte{0x00, 0x14, 0x02}
+
+	// Add the original parameters.
+	canProcess := deduplicator.notifyRedemptionRequested(wallet1, script1, 500)
+	if !canProcess {
+		t.Fatal("should be allowed to process")
+	}
+
+	// Add with different wallet.
+	canProcess = deduplicator.notifyRedemptionRequested(wallet2, script1, 500)
+	if !canProcess {
+		t.Fatal("should be allowed to process")
+	}
+
+	// Add with different redeemer output script.
+	canProcess = deduplicator.notifyRedemptionRequested(wallet1, script2, 500)
+	if !canProcess {
+		t.Fatal("should be allowed to process")
+	}
+
+	// Add with different request block.
+	canProcess = deduplicator.notifyRedemptionRequested(wallet1, script1, 501)
+	if !canProcess {
+		t.Fatal("should be allowed to process")
+	}
+
+	// Add the original parameters before caching period elapses.
+	canProcess = deduplicator.notifyRedemptionRequested(wallet1, script1, 500)
+	if canProcess {
+		t.Fatal("should not be allowed to process")
+	}
+
+	// Wait until caching period elapses.
+	time.Sleep(testRedemptionRequestCachePeriod)
+
+	// Add the original parameters again.
+	canProcess = deduplicator.notifyRedemptionRequested(wallet1, script1, 500)
+	if !canProcess {
+		t.Fatal("should be allowed to process")
+	}
+}
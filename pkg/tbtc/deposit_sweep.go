@@ -1,9 +1,31 @@
 package tbtc
 
 import (
+	"bytes"
+	"context"
 	"crypto/ecdsa"
+	"encoding/hex"
 	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
 	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+)
+
+const (
+	// depositSweepProposalConfirmationBlocks determines the block length of
+	// the confirmation period that is preserved after a deposit sweep
+	// proposal submission. Once the period elapses, the proposal is validated
+	// and executed by the wallet.
+	depositSweepProposalConfirmationBlocks = 20
+
+	// depositRevealedLookBackBlocks determines the block range the node
+	// looks back into while searching for reveal events of deposits being
+	// subject of a deposit sweep proposal. The value of `216000` blocks is
+	// roughly 30 days assuming 12 seconds per block.
+	depositRevealedLookBackBlocks = 216000
 )
 
 // assembleDepositSweepTransaction constructs an unsigned deposit sweep Bitcoin
@@ -76,3 +98,449 @@ func assembleDepositSweepTransaction(
 
 	return builder, nil
 }
+
+// depositSweepAction is a wallet action sweeping deposits according to
+// the given deposit sweep proposal.
+type depositSweepAction struct {
+	node            *node
+	executingWallet wallet
+	event           *DepositSweepProposalSubmittedEvent
+}
+
+func (dsa *depositSweepAction) execute(ctx context.Context) error {
+	return dsa.node.handleDepositSweepProposal(
+		ctx,
+		dsa.executingWallet,
+		dsa.event,
+	)
+}
+
+func (dsa *depositSweepAction) wallet() wallet {
+	return dsa.executingWallet
+}
+
+func (dsa *depositSweepAction) actionType() walletActionType {
+	return actionDepositSweep
+}
+
+func (dsa *depositSweepAction) startBlock() uint64 {
+	return dsa.event.BlockNumber + depositSweepProposalConfirmationBlocks
+}
+
+// handleDepositSweepProposal handles the given deposit sweep proposal
+// submitted on-chain for the given wallet controlled by this node. This
+// function validates the proposed deposits against the host chain and the
+// Bitcoin chain, assembles a deposit sweep transaction, signs it, and
+// broadcasts it over the Bitcoin network. This function blocks until the
+// sweep transaction is broadcast or an error occurs.
+func (n *node) handleDepositSweepProposal(
+	ctx context.Context,
+	wallet wallet,
+	event *DepositSweepProposalSubmittedEvent,
+) error {
+	proposal := event.Proposal
+
+	executor, ok, err := n.getSigningExecutor(wallet.publicKey)
+	if err != nil {
+		return fmt.Errorf("cannot get signing executor: [%v]", err)
+	}
+	if !ok {
+		return fmt.Errorf("node does not control signers of the wallet")
+	}
+
+	depositSweepLogger := logger.With(
+		zap.String("wallet", fmt.Sprintf("0x%x", proposal.WalletPublicKeyHash)),
+		zap.Uint64("proposalBlock", event.BlockNumber),
+	)
+
+	confirmationBlock := event.BlockNumber +
+		depositSweepProposalConfirmationBlocks
+
+	depositSweepLogger.Infof(
+		"observed deposit sweep proposal with [%v] deposits; "+
+			"waiting for block [%v] to confirm",
+		len(proposal.DepositsKeys),
+		confirmationBlock,
+	)
+
+	err = n.waitForBlockHeight(ctx, confirmationBlock)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to wait for the confirmation block: [%v]",
+			err,
+		)
+	}
+
+	deposits, err := validateDepositSweepProposal(
+		proposal,
+		confirmationBlock,
+		n.chain,
+		n.btcChain,
+	)
+	if err != nil {
+		return fmt.Errorf("deposit sweep proposal is invalid: [%v]", err)
+	}
+
+	depositSweepLogger.Infof("deposit sweep proposal is valid")
+
+	walletMainUtxo, err := determineWalletMainUtxo(
+		proposal.WalletPublicKeyHash,
+		n.chain,
+		n.btcChain,
+		n.walletMainUtxoCache,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot determine wallet main UTXO: [%v]", err)
+	}
+
+	totalInputsValue := int64(0)
+	if walletMainUtxo != nil {
+		totalInputsValue += walletMainUtxo.Value
+	}
+	for _, deposit := range deposits {
+		totalInputsValue += deposit.utxo.Value
+	}
+
+	depositParameters, err := n.chain.GetDepositParameters()
+	if err != nil {
+		return fmt.Errorf("cannot get deposit parameters: [%v]", err)
+	}
+
+	fee, err := depositSweepTxFee(
+		proposal.SweepTxFee,
+		len(deposits),
+		depositParameters.TxMaxFee,
+	)
+	if err != nil {
+		return fmt.Errorf("deposit sweep proposal is invalid: [%v]", err)
+	}
+	if fee <= 0 || fee >= totalInputsValue {
+		return fmt.Errorf(
+			"sweep transaction fee [%v] is not in the range (0, %v)",
+			fee,
+			totalInputsValue,
+		)
+	}
+
+	unsignedTx, err := assembleDepositSweepTransaction(
+		n.btcChain,
+		wallet.publicKey,
+		walletMainUtxo,
+		deposits,
+		fee,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot assemble deposit sweep transaction: [%v]",
+			err,
+		)
+	}
+
+	virtualSize, err := unsignedTx.EstimateVirtualSize()
+	if err != nil {
+		return fmt.Errorf(
+			"cannot estimate deposit sweep transaction virtual size: [%v]",
+			err,
+		)
+	}
+
+	if err := validateWalletTransactionFee(virtualSize, fee); err != nil {
+		return fmt.Errorf("deposit sweep proposal is invalid: [%v]", err)
+	}
+
+	depositSweepLogger.Infof(
+		"assembled deposit sweep transaction sweeping [%v] deposits "+
+			"with fee [%v]",
+		len(deposits),
+		fee,
+	)
+
+	transactionExecutor := newWalletTransactionExecutor(
+		n.btcChain,
+		wallet,
+		executor,
+	)
+
+	// The signing must start no sooner than the current block. The
+	// confirmation block is a good synchronization point as it is common for
+	// all wallet signers observing the given proposal.
+	sweepTx, err := transactionExecutor.signTransaction(
+		ctx,
+		unsignedTx,
+		confirmationBlock,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot sign deposit sweep transaction: [%w]", err)
+	}
+
+	depositSweepLogger.Infof(
+		"signed deposit sweep transaction [%s]",
+		sweepTx.Hash().Hex(bitcoin.ReversedByteOrder),
+	)
+
+	err = transactionExecutor.broadcastTransaction(ctx, sweepTx)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot broadcast deposit sweep transaction: [%v]",
+			err,
+		)
+	}
+
+	depositSweepLogger.Infof(
+		"broadcast deposit sweep transaction [%s]",
+		sweepTx.Hash().Hex(bitcoin.ReversedByteOrder),
+	)
+
+	return nil
+}
+
+// validateDepositSweepProposal checks whether the given deposit sweep
+// proposal can be executed by the wallet. Each deposit being subject of the
+// proposal must be revealed to the Bridge, must not be swept yet, and must
+// target the proposal's wallet. The deposit funding transaction is fetched
+// from the Bitcoin chain to confirm the funding output is locked using the
+// expected deposit script and holds the amount known to the Bridge. Reveal
+// events are searched up to the given end block. If the proposal is valid,
+// this function returns the proposed deposits in the proposal's order.
+func validateDepositSweepProposal(
+	proposal *DepositSweepProposal,
+	endBlock uint64,
+	bridgeChain BridgeChain,
+	btcChain bitcoin.Chain,
+) ([]*deposit, error) {
+	if len(proposal.DepositsKeys) == 0 {
+		return nil, fmt.Errorf("proposal does not contain any deposits")
+	}
+
+	startBlock := uint64(0)
+	if endBlock > depositRevealedLookBackBlocks {
+		startBlock = endBlock - depositRevealedLookBackBlocks
+	}
+
+	revealedEvents, err := bridgeChain.PastDepositRevealedEvents(
+		&DepositRevealedEventFilter{
+			StartBlock:          startBlock,
+			EndBlock:            &endBlock,
+			WalletPublicKeyHash: [][20]byte{proposal.WalletPublicKeyHash},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get past deposit revealed events: [%v]",
+			err,
+		)
+	}
+
+	revealedEventsByOutpoint := make(map[string]*DepositRevealedEvent)
+	for _, event := range revealedEvents {
+		outpointKey := fmt.Sprintf(
+			"%s:%v",
+			event.FundingTxHash.Hex(bitcoin.InternalByteOrder),
+			event.FundingOutputIndex,
+		)
+		revealedEventsByOutpoint[outpointKey] = event
+	}
+
+	deposits := make([]*deposit, len(proposal.DepositsKeys))
+	processedOutpoints := make(map[string]bool)
+
+	for i, depositKey := range proposal.DepositsKeys {
+		outpointKey := fmt.Sprintf(
+			"%s:%v",
+			depositKey.FundingTxHash.Hex(bitcoin.InternalByteOrder),
+			depositKey.FundingOutputIndex,
+		)
+
+		if processedOutpoints[outpointKey] {
+			return nil, fmt.Errorf("deposit [%v] is duplicated", i)
+		}
+		processedOutpoints[outpointKey] = true
+
+		depositRequest, found, err := bridgeChain.GetDepositRequest(
+			depositKey.FundingTxHash,
+			depositKey.FundingOutputIndex,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get request of deposit [%v]: [%v]",
+				i,
+				err,
+			)
+		}
+
+		if !found {
+			return nil, fmt.Errorf("deposit [%v] is not revealed", i)
+		}
+
+		if depositRequest.SweptAt != 0 {
+			return nil, fmt.Errorf("deposit [%v] is already swept", i)
+		}
+
+		revealedEvent, ok := revealedEventsByOutpoint[outpointKey]
+		if !ok {
+			return nil, fmt.Errorf(
+				"reveal event of deposit [%v] was not found",
+				i,
+			)
+		}
+
+		d, err := depositFromRevealedEvent(revealedEvent, btcChain)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot reconstruct deposit [%v]: [%v]",
+				i,
+				err,
+			)
+		}
+
+		if d.utxo.Value != int64(depositRequest.Amount) {
+			return nil, fmt.Errorf(
+				"funding output value [%v] of deposit [%v] does not "+
+					"match the revealed amount [%v]",
+				d.utxo.Value,
+				i,
+				depositRequest.Amount,
+			)
+		}
+
+		deposits[i] = d
+	}
+
+	return deposits, nil
+}
+
+// depositSweepTxFee returns the fee of the deposit sweep transaction sweeping
+// the given number of deposits. The fee is taken from the proposal and must
+// not exceed the maximum the Bridge accepts, i.e. the per-deposit maximum fee
+// multiplied by the number of deposits. Both values are read from the chain
+// so all wallet signers assemble the same transaction. If the proposed fee
+// exceeds the maximum, this function returns an error as the Bridge would
+// not accept the proof of the transaction.
+func depositSweepTxFee(
+	proposedFee uint64,
+	depositsCount int,
+	depositTxMaxFee uint64,
+) (int64, error) {
+	maxFee := depositTxMaxFee * uint64(depositsCount)
+	if proposedFee > maxFee {
+		return 0, fmt.Errorf(
+			"proposed sweep transaction fee [%v] exceeds the maximum "+
+				"fee [%v]",
+			proposedFee,
+			maxFee,
+		)
+	}
+
+	return int64(proposedFee), nil
+}
+
+// depositFromRevealedEvent reconstructs the deposit based on the given reveal
+// event and the deposit funding transaction fetched from the Bitcoin chain.
+// This function makes sure the funding output is locked using the deposit
+// script built from the revealed parameters and is still unspent.
+func depositFromRevealedEvent(
+	event *DepositRevealedEvent,
+	btcChain bitcoin.Chain,
+) (*deposit, error) {
+	depositor, err := hex.DecodeString(
+		strings.TrimPrefix(event.Depositor.String(), "0x"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode depositor address: [%v]", err)
+	}
+
+	if len(depositor) != 20 {
+		return nil, fmt.Errorf(
+			"depositor address has wrong length [%v]",
+			len(depositor),
+		)
+	}
+
+	fundingTx, err := btcChain.GetTransaction(event.FundingTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get funding transaction: [%v]", err)
+	}
+
+	if int(event.FundingOutputIndex) >= len(fundingTx.Outputs) {
+		return nil, fmt.Errorf(
+			"funding transaction does not have output [%v]",
+			event.FundingOutputIndex,
+		)
+	}
+
+	fundingOutput := fundingTx.Outputs[event.FundingOutputIndex]
+
+	var vault chain.Address
+	if event.Vault != nil {
+		vault = *event.Vault
+	}
+
+	d := &deposit{
+		utxo: &bitcoin.UnspentTransactionOutput{
+			Outpoint: &bitcoin.TransactionOutpoint{
+				TransactionHash: event.FundingTxHash,
+				OutputIndex:     event.FundingOutputIndex,
+			},
+			Value: fundingOutput.Value,
+		},
+		blindingFactor:      event.BlindingFactor,
+		walletPublicKeyHash: event.WalletPublicKeyHash,
+		refundPublicKeyHash: event.RefundPublicKeyHash,
+		refundLocktime:      event.RefundLocktime,
+		vault:               vault,
+	}
+	copy(d.depositor[:], depositor)
+
+	depositScript, err := d.script()
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute deposit script: [%v]", err)
+	}
+
+	p2shScript, err := bitcoin.PayToScriptHash(
+		bitcoin.ScriptHash(depositScript),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute P2SH script: [%v]", err)
+	}
+
+	p2wshScript, err := bitcoin.PayToWitnessScriptHash(
+		bitcoin.WitnessScriptHash(depositScript),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute P2WSH script: [%v]", err)
+	}
+
+	if !bytes.Equal(fundingOutput.PublicKeyScript, p2shScript) &&
+		!bytes.Equal(fundingOutput.PublicKeyScript, p2wshScript) {
+		return nil, fmt.Errorf(
+			"funding output script does not match the deposit script",
+		)
+	}
+
+	// The deposit could have been refunded to the depositor once the
+	// refund locktime elapsed. Make sure the funding output is still
+	// unspent.
+	unspentOutputs, err := btcChain.GetUnspentOutputsForScript(
+		fundingOutput.PublicKeyScript,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get unspent outputs of funding output script: [%v]",
+			err,
+		)
+	}
+
+	isUnspent := false
+	for _, unspentOutput := range unspentOutputs {
+		if *unspentOutput.Outpoint == *d.utxo.Outpoint {
+			isUnspent = true
+			break
+		}
+	}
+
+	if !isUnspent {
+		return nil, fmt.Errorf("funding output is already spent")
+	}
+
+	return d, nil
+}
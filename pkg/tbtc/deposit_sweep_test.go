@@ -1,8 +1,10 @@
 package tbtc
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/keep-network/keep-core/pkg/bitcoin"
@@ -94,8 +96,287 @@ func TestAssembleDepositSweepTransaction(t *testing.T) {
 	}
 }
 
+func TestValidateDepositSweepProposal(t *testing.T) {
+	scenarios, err := tbtctest.LoadDepositSweepTestScenarios()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Take the scenario with one P2SH and one P2WSH deposit.
+	scenario := scenarios[1]
+
+	var tests = map[string]struct {
+		modifyProposal func(proposal *DepositSweepProposal)
+		modifyEvent    func(event *DepositRevealedEvent)
+		modifyRequest  func(request *DepositChainRequest)
+		// spendDeposit determines whether the funding output of the first
+		// deposit should be spent on the Bitcoin chain.
+		spendDeposit  bool
+		expectedError error
+	}{
+		"valid proposal": {},
+		"no deposits": {
+			modifyProposal: func(proposal *DepositSweepProposal) {
+				proposal.DepositsKeys = proposal.DepositsKeys[:0]
+			},
+			expectedError: fmt.Errorf("proposal does not contain any deposits"),
+		},
+		"duplicated deposit": {
+			modifyProposal: func(proposal *DepositSweepProposal) {
+				proposal.DepositsKeys = append(
+					proposal.DepositsKeys,
+					proposal.DepositsKeys[0],
+				)
+			},
+			expectedError: fmt.Errorf("deposit [2] is duplicated"),
+		},
+		"already swept deposit": {
+			modifyRequest: func(request *DepositChainRequest) {
+				request.SweptAt = 1
+			},
+			expectedError: fmt.Errorf("deposit [0] is already swept"),
+		},
+		"amount mismatch": {
+			modifyRequest: func(request *DepositChainRequest) {
+				request.Amount++
+			},
+			expectedError: fmt.Errorf(
+				"funding output value [%v] of deposit [0] does not "+
+					"match the revealed amount [%v]",
+				scenario.Deposits[0].Utxo.Value,
+				scenario.Deposits[0].Utxo.Value+1,
+			),
+		},
+		"script mismatch": {
+			modifyEvent: func(event *DepositRevealedEvent) {
+				event.BlindingFactor = [8]byte{}
+			},
+			expectedError: fmt.Errorf(
+				"cannot reconstruct deposit [0]: [funding output script " +
+					"does not match the deposit script]",
+			),
+		},
+		"spent deposit": {
+			spendDeposit: true,
+			expectedError: fmt.Errorf(
+				"cannot reconstruct deposit [0]: [funding output is " +
+					"already spent]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			localChain := Connect()
+			bitcoinChain := newMockBitcoinChain()
+
+			for _, transaction := range scenario.InputTransactions {
+				err := bitcoinChain.addTransaction(transaction)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if test.spendDeposit {
+				err := bitcoinChain.addTransaction(&bitcoin.Transaction{
+					Version: 1,
+					Inputs: []*bitcoin.TransactionInput{
+						{
+							Outpoint: scenario.Deposits[0].Utxo.Outpoint,
+							Sequence: 0xffffffff,
+						},
+					},
+					Outputs: []*bitcoin.TransactionOutput{
+						{
+							Value:           scenario.Deposits[0].Utxo.Value,
+							PublicKeyScript: []byte{0x00},
+						},
+					},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			proposal := &DepositSweepProposal{
+				WalletPublicKeyHash: bitcoin.PublicKeyHash(
+					scenario.WalletPublicKey,
+				),
+				SweepTxFee: uint64(scenario.Fee),
+			}
+
+			for i, d := range scenario.Deposits {
+				outpoint := d.Utxo.Outpoint
+
+				proposal.DepositsKeys = append(
+					proposal.DepositsKeys,
+					struct {
+						FundingTxHash      bitcoin.Hash
+						FundingOutputIndex uint32
+					}{
+						FundingTxHash:      outpoint.TransactionHash,
+						FundingOutputIndex: outpoint.OutputIndex,
+					},
+				)
+
+				vault := chain.Address(hex.EncodeToString(d.Vault[:]))
+
+				event := &DepositRevealedEvent{
+					FundingTxHash:       outpoint.TransactionHash,
+					FundingOutputIndex:  outpoint.OutputIndex,
+					Depositor:           chain.Address(hex.EncodeToString(d.Depositor[:])),
+					Amount:              uint64(d.Utxo.Value),
+					BlindingFactor:      d.BlindingFactor,
+					WalletPublicKeyHash: d.WalletPublicKeyHash,
+					RefundPublicKeyHash: d.RefundPublicKeyHash,
+					RefundLocktime:      d.RefundLocktime,
+					Vault:               &vault,
+					BlockNumber:         100,
+				}
+				request := &DepositChainRequest{
+					Depositor:  event.Depositor,
+					Amount:     event.Amount,
+					RevealedAt: 1,
+					Vault:      &vault,
+				}
+
+				// Apply modifications to the first deposit only.
+				if i == 0 {
+					if test.modifyEvent != nil {
+						test.modifyEvent(event)
+					}
+					if test.modifyRequest != nil {
+						test.modifyRequest(request)
+					}
+				}
+
+				localChain.addDepositRevealedEvent(event)
+				localChain.setDepositRequest(
+					outpoint.TransactionHash,
+					outpoint.OutputIndex,
+					request,
+				)
+			}
+
+			if test.modifyProposal != nil {
+				test.modifyProposal(proposal)
+			}
+
+			deposits, err := validateDepositSweepProposal(
+				proposal,
+				200,
+				localChain,
+				bitcoinChain,
+			)
+
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected error\n"+
+						"expected: [%+v]\n"+
+						"actual:   [%+v]",
+					test.expectedError,
+					err,
+				)
+			}
+
+			if test.expectedError != nil {
+				return
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"deposits count",
+				len(scenario.Deposits),
+				len(deposits),
+			)
+
+			for i, d := range deposits {
+				expected := scenario.Deposits[i]
+
+				if !reflect.DeepEqual(expected.Utxo, d.utxo) {
+					t.Errorf(
+						"unexpected UTXO of deposit [%v]\n"+
+							"expected: [%+v]\n"+
+							"actual:   [%+v]",
+						i,
+						expected.Utxo,
+						d.utxo,
+					)
+				}
+
+				testutils.AssertBytesEqual(
+					t,
+					expected.Depositor[:],
+					d.depositor[:],
+				)
+			}
+		})
+	}
+}
+
+func TestDepositSweepTxFee(t *testing.T) {
+	var tests = map[string]struct {
+		proposedFee     uint64
+		depositsCount   int
+		depositTxMaxFee uint64
+		expectedFee     int64
+		expectedError   error
+	}{
+		"proposed fee below maximum": {
+			proposedFee:     5000,
+			depositsCount:   3,
+			depositTxMaxFee: 10000,
+			expectedFee:     5000,
+		},
+		"proposed fee equal to maximum": {
+			proposedFee:     30000,
+			depositsCount:   3,
+			depositTxMaxFee: 10000,
+			expectedFee:     30000,
+		},
+		"proposed fee above maximum": {
+			proposedFee:     40000,
+			depositsCount:   3,
+			depositTxMaxFee: 10000,
+			expectedError: fmt.Errorf(
+				"proposed sweep transaction fee [40000] exceeds the " +
+					"maximum fee [30000]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			fee, err := depositSweepTxFee(
+				test.proposedFee,
+				test.depositsCount,
+				test.depositTxMaxFee,
+			)
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected error\n"+
+						"expected: [%v]\n"+
+						"actual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"sweep transaction fee",
+				int(test.expectedFee),
+				int(fee),
+			)
+		})
+	}
+}
+
 type mockBitcoinChain struct {
 	transactions map[bitcoin.Hash]*bitcoin.Transaction
+	// transactionsOrder holds hashes of transactions in the order they were
+	// added. This order is treated as the order of transactions in the chain.
+	transactionsOrder []bitcoin.Hash
 }
 
 func newMockBitcoinChain() *mockBitcoinChain {
@@ -136,6 +417,95 @@ func (mbc *mockBitcoinChain) GetBlockHeader(
 	panic("not implemented")
 }
 
+func (mbc *mockBitcoinChain) GetBlockHeaders(
+	startHeight uint,
+	count uint,
+) ([]*bitcoin.BlockHeader, error) {
+	panic("not implemented")
+}
+
+func (mbc *mockBitcoinChain) GetTransactionMerkle(
+	transactionHash bitcoin.Hash,
+	blockHeight uint,
+) (*bitcoin.TransactionMerkleProof, error) {
+	panic("not implemented")
+}
+
+func (mbc *mockBitcoinChain) GetTransactionsForPublicKeyHash(
+	publicKeyHash [20]byte,
+	limit int,
+) ([]*bitcoin.Transaction, error) {
+	p2pkh, err := bitcoin.PayToPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+	p2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(publicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*bitcoin.Transaction, 0)
+	for _, transactionHash := range mbc.transactionsOrder {
+		transaction := mbc.transactions[transactionHash]
+
+		for _, output := range transaction.Outputs {
+			if bytes.Equal(output.PublicKeyScript, p2pkh) ||
+				bytes.Equal(output.PublicKeyScript, p2wpkh) {
+				transactions = append(transactions, transaction)
+				break
+			}
+		}
+	}
+
+	if limit > 0 && len(transactions) > limit {
+		transactions = transactions[len(transactions)-limit:]
+	}
+
+	return transactions, nil
+}
+
+func (mbc *mockBitcoinChain) GetUnspentOutputsForScript(
+	script bitcoin.Script,
+) ([]*bitcoin.UnspentTransactionOutput, error) {
+	spentOutpoints := make(map[bitcoin.TransactionOutpoint]bool)
+	for _, transaction := range mbc.transactions {
+		for _, input := range transaction.Inputs {
+			spentOutpoints[*input.Outpoint] = true
+		}
+	}
+
+	unspentOutputs := make([]*bitcoin.UnspentTransactionOutput, 0)
+	for _, transactionHash := range mbc.transactionsOrder {
+		transaction := mbc.transactions[transactionHash]
+
+		for outputIndex, output := range transaction.Outputs {
+			outpoint := &bitcoin.TransactionOutpoint{
+				TransactionHash: transactionHash,
+				OutputIndex:     uint32(outputIndex),
+			}
+
+			if bytes.Equal(output.PublicKeyScript, script) &&
+				!spentOutpoints[*outpoint] {
+				unspentOutputs = append(
+					unspentOutputs,
+					&bitcoin.UnspentTransactionOutput{
+						Outpoint: outpoint,
+						Value:    output.Value,
+					},
+				)
+			}
+		}
+	}
+
+	return unspentOutputs, nil
+}
+
+func (mbc *mockBitcoinChain) EstimateSatPerVByteFee(
+	blocks uint32,
+) (int64, error) {
+	panic("unsupported")
+}
+
 func (mbc *mockBitcoinChain) addTransaction(
 	transaction *bitcoin.Transaction,
 ) error {
@@ -146,6 +516,7 @@ func (mbc *mockBitcoinChain) addTransaction(
 	}
 
 	mbc.transactions[transactionHash] = transaction
+	mbc.transactionsOrder = append(mbc.transactionsOrder, transactionHash)
 
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 	"sort"
+	"sync"
 
 	"go.uber.org/zap"
 
@@ -19,34 +20,17 @@ import (
 	"github.com/keep-network/keep-core/pkg/tecdsa/dkg"
 )
 
-const (
-	// dkgStartedConfirmationBlocks determines the block length of the
-	// confirmation period that is preserved after a DKG start. Once the period
-	// elapses, the DKG state is checked to confirm the protocol can be started.
-	dkgStartedConfirmationBlocks = 20
-	// dkgResultSubmissionDelayStep determines the delay step in blocks that
-	// is used to calculate the submission delay period that should be respected
-	// by the given member to avoid all members submitting the same DKG result
-	// at the same time.
-	dkgResultSubmissionDelayStepBlocks = 15
-	// dkgResultApprovalDelayStepBlocks determines the delay step in blocks
-	// that is used to calculate the approval delay period that should be
-	// respected by the given member to avoid all members approving the same
-	// DKG result at the same time.
-	dkgResultApprovalDelayStepBlocks = 15
-	// dkgResultChallengeConfirmationBlocks determines the block length of
-	// the confirmation period that is preserved after a DKG result challenge
-	// submission. Once the period elapses, the DKG state is checked to confirm
-	// the challenge was accepted successfully.
-	dkgResultChallengeConfirmationBlocks = 20
-)
-
 // dkgExecutor is a component responsible for the full execution of ECDSA
 // Distributed Key Generation: determining members selected to the signing
 // group, executing off-chain protocol, and publishing the result to the chain.
 type dkgExecutor struct {
+	groupParametersMutex sync.RWMutex
+	// groupParameters are the group parameters used by new DKGs. They are
+	// refreshed once the group parameters are updated on-chain.
 	groupParameters *GroupParameters
 
+	protocolTiming *ProtocolTiming
+
 	operatorIDFn    func() (chain.OperatorID, error)
 	operatorAddress chain.Address
 
@@ -59,12 +43,15 @@ type dkgExecutor struct {
 	waitForBlockFn waitForBlockFn
 
 	tecdsaExecutor *dkg.Executor
+
+	attemptJournal *attemptJournal
 }
 
 // newDkgExecutor creates a new instance of dkgExecutor struct. There should
 // be only one instance of dkgExecutor.
 func newDkgExecutor(
 	groupParameters *GroupParameters,
+	protocolTiming *ProtocolTiming,
 	operatorIDFn func() (chain.OperatorID, error),
 	operatorAddress chain.Address,
 	chain Chain,
@@ -73,22 +60,27 @@ func newDkgExecutor(
 	protocolLatch *generator.ProtocolLatch,
 	config Config,
 	workPersistence persistence.BasicHandle,
+	preParamsGenerator dkg.PreParamsGenerator,
 	scheduler *generator.Scheduler,
 	waitForBlockFn waitForBlockFn,
-) *dkgExecutor {
-	tecdsaExecutor := dkg.NewExecutor(
+	attemptJournal *attemptJournal,
+) (*dkgExecutor, error) {
+	tecdsaExecutor, err := dkg.NewExecutor(
 		logger,
 		scheduler,
 		workPersistence,
 		config.PreParamsPoolSize,
-		config.PreParamsGenerationTimeout,
+		preParamsGenerator,
 		config.PreParamsGenerationDelay,
-		config.PreParamsGenerationConcurrency,
 		config.KeyGenerationConcurrency,
 	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot set up DKG executor: [%v]", err)
+	}
 
 	return &dkgExecutor{
 		groupParameters: groupParameters,
+		protocolTiming:  protocolTiming,
 		operatorIDFn:    operatorIDFn,
 		operatorAddress: operatorAddress,
 		chain:           chain,
@@ -97,7 +89,95 @@ func newDkgExecutor(
 		protocolLatch:   protocolLatch,
 		tecdsaExecutor:  tecdsaExecutor,
 		waitForBlockFn:  waitForBlockFn,
+		attemptJournal:  attemptJournal,
+	}, nil
+}
+
+// newPreParamsGenerator creates the backend producing ECDSA DKG
+// pre-parameters. If the pre-params worker address is configured, the
+// pre-parameters are received from external worker processes and the
+// receiver's endpoint is started. Otherwise, the pre-parameters are generated
+// in-process.
+func newPreParamsGenerator(config Config) (dkg.PreParamsGenerator, error) {
+	if config.PreParamsWorkerAddress == "" {
+		logger.Infof(
+			"TSS pre-parameters are generated in-process; generation "+
+				"timeout is [%s] and concurrency level is [%d]",
+			config.PreParamsGenerationTimeout,
+			config.PreParamsGenerationConcurrency,
+		)
+
+		return dkg.NewLocalPreParamsGenerator(
+			config.PreParamsGenerationTimeout,
+			config.PreParamsGenerationConcurrency,
+		), nil
+	}
+
+	if config.PreParamsWorkerSecret == "" {
+		return nil, fmt.Errorf(
+			"pre-params worker secret must be set when pre-params " +
+				"worker address is configured",
+		)
 	}
+
+	receiver := dkg.NewPreParamsReceiver(
+		logger,
+		config.PreParamsWorkerSecret,
+		dkg.DefaultPreParamsDeliveryTimeout,
+	)
+
+	if err := receiver.Listen(config.PreParamsWorkerAddress); err != nil {
+		return nil, err
+	}
+
+	logger.Infof(
+		"TSS pre-parameters are received from external workers on [%s]",
+		config.PreParamsWorkerAddress,
+	)
+
+	return receiver, nil
+}
+
+// currentGroupParameters returns the group parameters that should be used
+// by a new DKG.
+func (de *dkgExecutor) currentGroupParameters() *GroupParameters {
+	de.groupParametersMutex.RLock()
+	defer de.groupParametersMutex.RUnlock()
+
+	return de.groupParameters
+}
+
+// refreshGroupParameters fetches the group parameters from the chain and,
+// if they are valid, applies them to subsequent DKGs. DKGs already in
+// progress and wallets created by previous DKGs keep using the parameters
+// they were started with.
+func (de *dkgExecutor) refreshGroupParameters() error {
+	groupParameters, err := de.chain.GroupParameters()
+	if err != nil {
+		return fmt.Errorf("cannot get group parameters: [%w]", err)
+	}
+
+	if err := groupParameters.validate(); err != nil {
+		return fmt.Errorf("invalid group parameters: [%w]", err)
+	}
+
+	de.groupParametersMutex.Lock()
+	defer de.groupParametersMutex.Unlock()
+
+	if *groupParameters == *de.groupParameters {
+		return nil
+	}
+
+	logger.Infof(
+		"group parameters updated from [%+v] to [%+v]; "+
+			"new values will be used by subsequent DKGs",
+		*de.groupParameters,
+		*groupParameters,
+	)
+
+	de.groupParameters = groupParameters
+
+	return nil
 }
 
 // preParamsCount returns the current count of the ECDSA DKG pre-parameters.
@@ -105,6 +185,13 @@ func (de *dkgExecutor) preParamsCount() int {
 	return de.tecdsaExecutor.PreParamsCount()
 }
 
+// quarantinedPreParamsCount returns the number of stored pre-parameters
+// that failed the validation when the pool was loaded and were moved to the
+// quarantine.
+func (de *dkgExecutor) quarantinedPreParamsCount() int {
+	return de.tecdsaExecutor.QuarantinedPreParamsCount()
+}
+
 // executeDkgIfEligible is the main function of dkgExecutor. It performs the
 // full execution of ECDSA Distributed Key Generation: determining members
 // selected to the signing group, executing off-chain protocol, and publishing
@@ -121,9 +208,14 @@ func (de *dkgExecutor) executeDkgIfEligible(
 		zap.String("seed", fmt.Sprintf("0x%x", seed)),
 	)
 
+	// Group parameters are captured once so the whole DKG uses the same
+	// values even if they are updated in the meantime.
+	groupParameters := de.currentGroupParameters()
+
 	dkgLogger.Info("checking eligibility for DKG")
 	memberIndexes, groupSelectionResult, err := de.checkEligibility(
 		dkgLogger,
+		groupParameters,
 	)
 	if err != nil {
 		dkgLogger.Errorf("could not check eligibility for DKG: [%v]", err)
@@ -152,6 +244,7 @@ func (de *dkgExecutor) executeDkgIfEligible(
 			seed,
 			memberIndexes,
 			groupSelectionResult,
+			groupParameters,
 			startBlock,
 			delayBlocks,
 		)
@@ -170,6 +263,7 @@ func (de *dkgExecutor) executeDkgIfEligible(
 //   selected operators.
 func (de *dkgExecutor) checkEligibility(
 	dkgLogger log.StandardLogger,
+	groupParameters *GroupParameters,
 ) ([]uint8, *GroupSelectionResult, error) {
 	groupSelectionResult, err := de.chain.SelectGroup()
 	if err != nil {
@@ -181,7 +275,7 @@ func (de *dkgExecutor) checkEligibility(
 		groupSelectionResult.OperatorsAddresses,
 	)
 
-	if len(groupSelectionResult.OperatorsAddresses) > de.groupParameters.GroupSize {
+	if len(groupSelectionResult.OperatorsAddresses) > groupParameters.GroupSize {
 		return nil, nil, fmt.Errorf(
 			"group size larger than supported: [%v]",
 			len(groupSelectionResult.OperatorsAddresses),
@@ -244,6 +338,7 @@ func (de *dkgExecutor) generateSigningGroup(
 	seed *big.Int,
 	memberIndexes []uint8,
 	groupSelectionResult *GroupSelectionResult,
+	groupParameters *GroupParameters,
 	startBlock uint64,
 	delayBlocks uint64,
 ) {
@@ -252,6 +347,7 @@ func (de *dkgExecutor) generateSigningGroup(
 		groupSelectionResult.OperatorsAddresses,
 		de.chain.Signing(),
 	)
+	membershipValidator.SetPeerReputation(de.netProvider.PeerReputation())
 
 	broadcastChannel, err := de.setupBroadcastChannel(seed, membershipValidator)
 	if err != nil {
@@ -262,16 +358,28 @@ func (de *dkgExecutor) generateSigningGroup(
 	dkgParameters, err := de.chain.DKGParameters()
 	if err != nil {
 		dkgLogger.Errorf("cannot get DKG parameters: [%v]", err)
+		de.netProvider.CloseBroadcastChannel(broadcastChannel.Name())
 		return
 	}
 
 	dkgTimeoutBlock := startBlock + dkgParameters.SubmissionTimeoutBlocks
 
+	// The broadcast channel is temporary so close it once all members
+	// controlled by this node are done.
+	membersWg := &sync.WaitGroup{}
+	membersWg.Add(len(memberIndexes))
+	go func() {
+		membersWg.Wait()
+		de.netProvider.CloseBroadcastChannel(broadcastChannel.Name())
+	}()
+
 	for _, index := range memberIndexes {
 		// Capture the member index for the goroutine.
 		memberIndex := index
 
 		go func() {
+			defer membersWg.Done()
+
 			de.protocolLatch.Lock()
 			defer de.protocolLatch.Unlock()
 
@@ -308,14 +416,24 @@ func (de *dkgExecutor) generateSigningGroup(
 				membershipValidator,
 			)
 
+			journalSession := de.attemptJournal.startSession(
+				DkgJournalProtocol,
+				seed.Text(16),
+				"",
+				memberIndex,
+				startBlock+delayBlocks,
+			)
+
 			retryLoop := newDkgRetryLoop(
 				dkgLogger,
 				seed,
 				startBlock+delayBlocks,
 				memberIndex,
 				groupSelectionResult.OperatorsAddresses,
-				de.groupParameters,
+				groupParameters,
+				de.protocolTiming,
 				announcer,
+				journalSession,
 			)
 
 			result, err := retryLoop.start(
@@ -332,7 +450,7 @@ func (de *dkgExecutor) generateSigningGroup(
 						"[member:%v] scheduled dkg attempt "+
 							"with [%v] group members (excluded: [%v])",
 						memberIndex,
-						de.groupParameters.GroupSize-len(attempt.excludedMembersIndexes),
+						groupParameters.GroupSize-len(attempt.excludedMembersIndexes),
 						attempt.excludedMembersIndexes,
 					)
 
@@ -356,8 +474,8 @@ func (de *dkgExecutor) generateSigningGroup(
 						seed,
 						sessionID,
 						memberIndex,
-						de.groupParameters.GroupSize,
-						de.groupParameters.DishonestThreshold(),
+						groupParameters.GroupSize,
+						groupParameters.DishonestThreshold(),
 						attempt.excludedMembersIndexes,
 						broadcastChannel,
 						membershipValidator,
@@ -375,6 +493,7 @@ func (de *dkgExecutor) generateSigningGroup(
 					return result, nil
 				},
 			)
+			journalSession.finish(err)
 			if err != nil {
 				if errors.Is(err, context.Canceled) {
 					dkgLogger.Infof(
@@ -397,6 +516,7 @@ func (de *dkgExecutor) generateSigningGroup(
 				result,
 				memberIndex,
 				groupSelectionResult.OperatorsAddresses,
+				groupParameters,
 			)
 			if err != nil {
 				dkgLogger.Errorf(
@@ -417,6 +537,7 @@ func (de *dkgExecutor) generateSigningGroup(
 				membershipValidator,
 				result,
 				groupSelectionResult,
+				groupParameters,
 				startBlock,
 			)
 			if err != nil {
@@ -448,6 +569,7 @@ func (de *dkgExecutor) registerSigner(
 	result *dkg.Result,
 	memberIndex group.MemberIndex,
 	selectedSigningGroupOperators chain.Addresses,
+	groupParameters *GroupParameters,
 ) (*signer, error) {
 	// Final signing group may differ from the original DKG
 	// group outputted by the sortition protocol. One need to
@@ -458,7 +580,7 @@ func (de *dkgExecutor) registerSigner(
 		finalSigningGroup(
 			selectedSigningGroupOperators,
 			operatingMemberIndexes,
-			de.groupParameters,
+			groupParameters,
 		)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve final signing group members")
@@ -479,6 +601,7 @@ func (de *dkgExecutor) registerSigner(
 	signer := newSigner(
 		result.PrivateKeyShare.PublicKey(),
 		finalSigningGroupOperators,
+		groupParameters,
 		finalSigningGroupMemberIndex,
 		result.PrivateKeyShare,
 	)
@@ -505,6 +628,7 @@ func (de *dkgExecutor) publishDkgResult(
 	membershipValidator *group.MembershipValidator,
 	dkgResult *dkg.Result,
 	groupSelectionResult *GroupSelectionResult,
+	groupParameters *GroupParameters,
 	startBlock uint64,
 ) error {
 	return dkg.Publish(
@@ -518,7 +642,8 @@ func (de *dkgExecutor) publishDkgResult(
 		newDkgResultSubmitter(
 			dkgLogger,
 			de.chain,
-			de.groupParameters,
+			groupParameters,
+			de.protocolTiming,
 			groupSelectionResult,
 			de.waitForBlockFn,
 		),
@@ -575,7 +700,7 @@ func (de *dkgExecutor) executeDkgValidation(
 			}
 
 			confirmationBlock := submissionBlock +
-				(i * dkgResultChallengeConfirmationBlocks)
+				(i * de.protocolTiming.DkgResultChallengeConfirmationBlocks)
 
 			dkgLogger.Infof(
 				"challenging invalid DKG result; waiting for "+
@@ -669,7 +794,8 @@ func (de *dkgExecutor) executeDkgValidation(
 				// Everyone else must approve after the precedence period ends.
 				// Each member preserves a delay according to their index
 				// to avoid simultaneous approval.
-				delayBlocks := uint64(memberIndex-1) * dkgResultApprovalDelayStepBlocks
+				delayBlocks := uint64(memberIndex-1) *
+					de.protocolTiming.DkgResultApprovalDelayStepBlocks
 				approveBlock = approvePeriodStartBlock + delayBlocks
 			}
 
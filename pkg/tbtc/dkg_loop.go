@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ipfs/go-log/v2"
 	"github.com/keep-network/keep-core/pkg/chain"
@@ -15,32 +16,6 @@ import (
 	"golang.org/x/exp/slices"
 )
 
-const (
-	// dkgAttemptAnnouncementDelayBlocks determines the duration of the
-	// announcement phase delay that is preserved before starting the
-	// announcement phase.
-	dkgAttemptAnnouncementDelayBlocks = 1
-	// dkgAttemptAnnouncementActiveBlocks determines the duration of the
-	// announcement phase that is performed at the beginning of each DKG
-	// attempt.
-	dkgAttemptAnnouncementActiveBlocks = 5
-	// dkgAttemptProtocolBlocks determines the maximum block duration of the
-	// actual protocol computations.
-	dkgAttemptMaximumProtocolBlocks = 150
-	// dkgAttemptCoolDownBlocks determines the duration of the cool down
-	// period that is preserved between subsequent DKG attempts.
-	dkgAttemptCoolDownBlocks = 5
-)
-
-// dkgAttemptMaximumBlocks returns the maximum block duration of a single
-// DKG attempt.
-func dkgAttemptMaximumBlocks() uint {
-	return dkgAttemptAnnouncementDelayBlocks +
-		dkgAttemptAnnouncementActiveBlocks +
-		dkgAttemptMaximumProtocolBlocks +
-		dkgAttemptCoolDownBlocks
-}
-
 // dkgAnnouncer represents a component responsible for exchanging readiness
 // announcements for the given DKG attempt for the given seed.
 type dkgAnnouncer interface {
@@ -63,6 +38,7 @@ type dkgRetryLoop struct {
 	selectedOperators chain.Addresses
 
 	groupParameters *GroupParameters
+	protocolTiming  *ProtocolTiming
 
 	announcer dkgAnnouncer
 
@@ -72,6 +48,9 @@ type dkgRetryLoop struct {
 	// Used for the random operator selection. It never changes.
 	attemptSeed        int64
 	attemptDelayBlocks uint64
+
+	// journal records the attempts of the loop. It may be nil.
+	journal *attemptJournalSession
 }
 
 func newDkgRetryLoop(
@@ -81,7 +60,9 @@ func newDkgRetryLoop(
 	memberIndex group.MemberIndex,
 	selectedOperators chain.Addresses,
 	groupParameters *GroupParameters,
+	protocolTiming *ProtocolTiming,
 	announcer dkgAnnouncer,
+	journal *attemptJournalSession,
 ) *dkgRetryLoop {
 	// Compute the 8-byte seed needed for the random retry algorithm. We take
 	// the first 8 bytes of the hash of the DKG seed. This allows us to not
@@ -96,11 +77,13 @@ func newDkgRetryLoop(
 		memberIndex:        memberIndex,
 		selectedOperators:  selectedOperators,
 		groupParameters:    groupParameters,
+		protocolTiming:     protocolTiming,
 		announcer:          announcer,
 		attemptCounter:     0,
 		attemptStartBlock:  initialStartBlock,
 		attemptSeed:        attemptSeed,
 		attemptDelayBlocks: 5,
+		journal:            journal,
 	}
 }
 
@@ -136,17 +119,18 @@ func (drl *dkgRetryLoop) start(
 		// by some additional delay blocks. We need a small cool down in
 		// order to mitigate all corner cases where the actual attempt duration
 		// was slightly longer than the expected duration determined by the
-		// dkgAttemptMaximumProtocolBlocks constant.
+		// DkgAttemptMaximumProtocolBlocks protocol timing value.
 		//
 		// For example, the attempt may fail at the end of the protocol but the
 		// error is returned after some time and more blocks than expected are
 		// mined in the meantime.
 		if drl.attemptCounter > 1 {
 			drl.attemptStartBlock = drl.attemptStartBlock +
-				uint64(dkgAttemptMaximumBlocks())
+				drl.protocolTiming.dkgAttemptMaximumBlocks()
 		}
 
-		announcementStartBlock := drl.attemptStartBlock + dkgAttemptAnnouncementDelayBlocks
+		announcementStartBlock := drl.attemptStartBlock +
+			drl.protocolTiming.DkgAttemptAnnouncementDelayBlocks
 		err := waitForBlockFn(ctx, announcementStartBlock)
 		if err != nil {
 			return nil, fmt.Errorf(
@@ -158,9 +142,16 @@ func (drl *dkgRetryLoop) start(
 			)
 		}
 
+		attemptEntry := &AttemptJournalEntry{
+			Number:     drl.attemptCounter,
+			StartBlock: drl.attemptStartBlock,
+			StartedAt:  time.Now(),
+		}
+
 		// Set up the announcement phase stop signal.
 		announceCtx, cancelAnnounceCtx := context.WithCancel(ctx)
-		announcementEndBlock := announcementStartBlock + dkgAttemptAnnouncementActiveBlocks
+		announcementEndBlock := announcementStartBlock +
+			drl.protocolTiming.DkgAttemptAnnouncementActiveBlocks
 		go func() {
 			defer cancelAnnounceCtx()
 
@@ -195,6 +186,7 @@ func (drl *dkgRetryLoop) start(
 				drl.attemptCounter,
 				err,
 			)
+			drl.journal.recordAttempt(attemptEntry, AttemptAnnouncementFailed, err)
 			continue
 		}
 
@@ -203,6 +195,8 @@ func (drl *dkgRetryLoop) start(
 			return nil, ctx.Err()
 		}
 
+		attemptEntry.ReadyMembers = readyMembersIndexes
+
 		if len(readyMembersIndexes) >= drl.groupParameters.GroupQuorum {
 			drl.logger.Infof(
 				"[member:%v] completed announcement phase for attempt [%v] "+
@@ -220,6 +214,11 @@ func (drl *dkgRetryLoop) start(
 				drl.attemptCounter,
 				len(readyMembersIndexes),
 			)
+			drl.journal.recordAttempt(
+				attemptEntry,
+				AttemptNotEnoughReadyMembers,
+				nil,
+			)
 			continue
 		}
 
@@ -234,12 +233,15 @@ func (drl *dkgRetryLoop) start(
 			)
 		}
 
+		attemptEntry.ExcludedMembers = excludedMembersIndexes
+
 		attemptSkipped := slices.Contains(
 			excludedMembersIndexes,
 			drl.memberIndex,
 		)
 
-		timeoutBlock := announcementEndBlock + dkgAttemptMaximumProtocolBlocks
+		timeoutBlock := announcementEndBlock +
+			drl.protocolTiming.DkgAttemptMaximumProtocolBlocks
 
 		var result *dkg.Result
 		var attemptErr error
@@ -259,10 +261,18 @@ func (drl *dkgRetryLoop) start(
 			)
 		}
 
-		if attemptSkipped || attemptErr != nil {
+		if attemptSkipped {
+			drl.journal.recordAttempt(attemptEntry, AttemptSkipped, nil)
 			continue
 		}
 
+		if attemptErr != nil {
+			drl.journal.recordAttempt(attemptEntry, AttemptFailed, attemptErr)
+			continue
+		}
+
+		drl.journal.recordAttempt(attemptEntry, AttemptSucceeded, nil)
+
 		return result, nil
 	}
 }
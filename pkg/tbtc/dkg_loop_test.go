@@ -250,7 +250,9 @@ func TestDkgRetryLoop(t *testing.T) {
 				test.memberIndex,
 				selectedOperators,
 				groupParameters,
+				&MainnetProtocolTiming,
 				announcer,
+				nil,
 			)
 
 			ctx, cancelCtx := test.ctxFn()
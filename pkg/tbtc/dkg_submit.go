@@ -80,6 +80,7 @@ type dkgResultSubmitter struct {
 
 	chain                Chain
 	groupParameters      *GroupParameters
+	protocolTiming       *ProtocolTiming
 	groupSelectionResult *GroupSelectionResult
 
 	waitForBlockFn waitForBlockFn
@@ -89,6 +90,7 @@ func newDkgResultSubmitter(
 	dkgLogger log.StandardLogger,
 	chain Chain,
 	groupParameters *GroupParameters,
+	protocolTiming *ProtocolTiming,
 	groupSelectionResult *GroupSelectionResult,
 	waitForBlockFn waitForBlockFn,
 ) *dkgResultSubmitter {
@@ -97,6 +99,7 @@ func newDkgResultSubmitter(
 		chain:                chain,
 		groupSelectionResult: groupSelectionResult,
 		groupParameters:      groupParameters,
+		protocolTiming:       protocolTiming,
 		waitForBlockFn:       waitForBlockFn,
 	}
 }
@@ -174,7 +177,8 @@ func (drs *dkgResultSubmitter) SubmitResult(
 	if err != nil {
 		return fmt.Errorf("cannot get current block: [%v]", err)
 	}
-	delayBlocks := uint64(memberIndex-1) * dkgResultSubmissionDelayStepBlocks
+	delayBlocks := uint64(memberIndex-1) *
+		drs.protocolTiming.DkgResultSubmissionDelayStepBlocks
 	submissionBlock := currentBlock + delayBlocks
 
 	drs.dkgLogger.Infof(
@@ -270,6 +270,7 @@ func TestSubmitResult_MemberSubmitsResult(t *testing.T) {
 		&testutils.MockLogger{},
 		localChain,
 		groupParameters,
+		&MainnetProtocolTiming,
 		groupSelectionResult,
 		testWaitForBlockFn(localChain),
 	)
@@ -363,6 +364,7 @@ func TestSubmitResult_AnotherMemberSubmitsResult(t *testing.T) {
 		&testutils.MockLogger{},
 		localChain,
 		groupParameters,
+		&MainnetProtocolTiming,
 		groupSelectionResult,
 		testWaitForBlockFn(localChain),
 	)
@@ -497,6 +499,7 @@ func TestSubmitResult_InvalidResult(t *testing.T) {
 		&testutils.MockLogger{},
 		localChain,
 		groupParameters,
+		&MainnetProtocolTiming,
 		groupSelectionResult,
 		testWaitForBlockFn(localChain),
 	)
@@ -583,6 +586,7 @@ func TestSubmitResult_ContextCancelled(t *testing.T) {
 		&testutils.MockLogger{},
 		localChain,
 		groupParameters,
+		&MainnetProtocolTiming,
 		groupSelectionResult,
 		testWaitForBlockFn(localChain),
 	)
@@ -665,6 +669,7 @@ func TestSubmitResult_TooFewSignatures(t *testing.T) {
 		&testutils.MockLogger{},
 		localChain,
 		groupParameters,
+		&MainnetProtocolTiming,
 		groupSelectionResult,
 		testWaitForBlockFn(localChain),
 	)
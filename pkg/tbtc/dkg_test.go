@@ -88,7 +88,10 @@ func TestDkgExecutor_RegisterSigner(t *testing.T) {
 	for testName, test := range tests {
 		t.Run(testName, func(t *testing.T) {
 			persistenceHandle := &mockPersistenceHandle{}
-			walletRegistry := newWalletRegistry(persistenceHandle)
+			walletRegistry, err := newWalletRegistry(persistenceHandle)
+			if err != nil {
+				t.Fatal(err)
+			}
 
 			dkgExecutor := &dkgExecutor{
 				// setting only the fields really needed for this test
@@ -110,7 +113,12 @@ func TestDkgExecutor_RegisterSigner(t *testing.T) {
 				PrivateKeyShare: tecdsa.NewPrivateKeyShare(testData[0]),
 			}
 
-			signer, err := dkgExecutor.registerSigner(result, test.memberIndex, selectedOperators)
+			signer, err := dkgExecutor.registerSigner(
+				result,
+				test.memberIndex,
+				selectedOperators,
+				groupParameters,
+			)
 
 			if !reflect.DeepEqual(test.expectedError, err) {
 				t.Errorf(
@@ -151,6 +159,16 @@ func TestDkgExecutor_RegisterSigner(t *testing.T) {
 				)
 			}
 
+			if !reflect.DeepEqual(groupParameters, signer.wallet.groupParameters) {
+				t.Errorf(
+					"unexpected wallet group parameters\n"+
+						"expected: %+v\n"+
+						"actual:   %+v\n",
+					groupParameters,
+					signer.wallet.groupParameters,
+				)
+			}
+
 			registeredSigners := walletRegistry.getSigners(
 				result.PrivateKeyShare.PublicKey(),
 			)
@@ -165,6 +183,83 @@ func TestDkgExecutor_RegisterSigner(t *testing.T) {
 	}
 }
 
+func TestDkgExecutor_RefreshGroupParameters(t *testing.T) {
+	initialGroupParameters := &GroupParameters{
+		GroupSize:       100,
+		GroupQuorum:     90,
+		HonestThreshold: 51,
+	}
+
+	var tests = map[string]struct {
+		chainGroupParameters    *GroupParameters
+		expectedError           error
+		expectedGroupParameters *GroupParameters
+	}{
+		"parameters updated": {
+			chainGroupParameters:    &GroupParameters{64, 60, 33},
+			expectedGroupParameters: &GroupParameters{64, 60, 33},
+		},
+		"parameters not changed": {
+			chainGroupParameters:    &GroupParameters{100, 90, 51},
+			expectedGroupParameters: initialGroupParameters,
+		},
+		"invalid parameters": {
+			chainGroupParameters: &GroupParameters{100, 101, 51},
+			expectedError: fmt.Errorf(
+				"invalid group parameters: [%w]",
+				fmt.Errorf("group quorum [101] must be in range [1, 100]"),
+			),
+			expectedGroupParameters: initialGroupParameters,
+		},
+		"parameters not available": {
+			chainGroupParameters: nil,
+			expectedError: fmt.Errorf(
+				"cannot get group parameters: [%w]",
+				fmt.Errorf("group parameters not set"),
+			),
+			expectedGroupParameters: initialGroupParameters,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			localChain := Connect()
+			localChain.setGroupParameters(test.chainGroupParameters)
+
+			dkgExecutor := &dkgExecutor{
+				// setting only the fields really needed for this test
+				groupParameters: initialGroupParameters,
+				chain:           localChain,
+			}
+
+			err := dkgExecutor.refreshGroupParameters()
+
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected error\n"+
+						"expected: %v\n"+
+						"actual:   %v\n",
+					test.expectedError,
+					err,
+				)
+			}
+
+			if !reflect.DeepEqual(
+				test.expectedGroupParameters,
+				dkgExecutor.currentGroupParameters(),
+			) {
+				t.Errorf(
+					"unexpected group parameters\n"+
+						"expected: %+v\n"+
+						"actual:   %+v\n",
+					test.expectedGroupParameters,
+					dkgExecutor.currentGroupParameters(),
+				)
+			}
+		})
+	}
+}
+
 func TestDkgExecutor_ExecuteDkgValidation(t *testing.T) {
 	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(1)
 	if err != nil {
@@ -318,6 +413,7 @@ func TestDkgExecutor_ExecuteDkgValidation(t *testing.T) {
 			// Setting only the fields really needed for this test.
 			dkgExecutor := &dkgExecutor{
 				groupParameters: groupParameters,
+				protocolTiming:  &MainnetProtocolTiming,
 				operatorIDFn: func() (chain.OperatorID, error) {
 					return operatorID, nil
 				},
@@ -99,6 +99,77 @@ func (x *SigningDoneMessage) GetEndBlock() uint64 {
 	return 0
 }
 
+type PresigningDoneMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SenderID       uint32   `protobuf:"varint,1,opt,name=senderID,proto3" json:"senderID,omitempty"`
+	SessionBlock   uint64   `protobuf:"varint,2,opt,name=sessionBlock,proto3" json:"sessionBlock,omitempty"`
+	MembersIndexes []uint32 `protobuf:"varint,3,rep,packed,name=membersIndexes,proto3" json:"membersIndexes,omitempty"`
+	PublicNonce    []byte   `protobuf:"bytes,4,opt,name=publicNonce,proto3" json:"publicNonce,omitempty"`
+}
+
+func (x *PresigningDoneMessage) Reset() {
+	*x = PresigningDoneMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tbtc_gen_pb_message_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PresigningDoneMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresigningDoneMessage) ProtoMessage() {}
+
+func (x *PresigningDoneMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tbtc_gen_pb_message_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresigningDoneMessage.ProtoReflect.Descriptor instead.
+func (*PresigningDoneMessage) Descriptor() ([]byte, []int) {
+	return file_pkg_tbtc_gen_pb_message_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PresigningDoneMessage) GetSenderID() uint32 {
+	if x != nil {
+		return x.SenderID
+	}
+	return 0
+}
+
+func (x *PresigningDoneMessage) GetSessionBlock() uint64 {
+	if x != nil {
+		return x.SessionBlock
+	}
+	return 0
+}
+
+func (x *PresigningDoneMessage) GetMembersIndexes() []uint32 {
+	if x != nil {
+		return x.MembersIndexes
+	}
+	return nil
+}
+
+func (x *PresigningDoneMessage) GetPublicNonce() []byte {
+	if x != nil {
+		return x.PublicNonce
+	}
+	return nil
+}
+
 var File_pkg_tbtc_gen_pb_message_proto protoreflect.FileDescriptor
 
 var file_pkg_tbtc_gen_pb_message_proto_rawDesc = []byte{
@@ -115,8 +186,18 @@ var file_pkg_tbtc_gen_pb_message_proto_rawDesc = []byte{
 	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67,
 	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f,
 	0x63, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x42, 0x6c, 0x6f,
-	0x63, 0x6b, 0x42, 0x06, 0x5a, 0x04, 0x2e, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x63, 0x6b, 0x22, 0xa1, 0x01, 0x0a, 0x15, 0x50, 0x72, 0x65, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e,
+	0x67, 0x44, 0x6f, 0x6e, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x26, 0x0a, 0x0e,
+	0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0d, 0x52, 0x0e, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4e, 0x6f,
+	0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x75, 0x62, 0x6c, 0x69,
+	0x63, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x42, 0x06, 0x5a, 0x04, 0x2e, 0x2f, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -131,9 +212,10 @@ func file_pkg_tbtc_gen_pb_message_proto_rawDescGZIP() []byte {
 	return file_pkg_tbtc_gen_pb_message_proto_rawDescData
 }
 
-var file_pkg_tbtc_gen_pb_message_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_pkg_tbtc_gen_pb_message_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_pkg_tbtc_gen_pb_message_proto_goTypes = []interface{}{
-	(*SigningDoneMessage)(nil), // 0: tbtc.SigningDoneMessage
+	(*SigningDoneMessage)(nil),    // 0: tbtc.SigningDoneMessage
+	(*PresigningDoneMessage)(nil), // 1: tbtc.PresigningDoneMessage
 }
 var file_pkg_tbtc_gen_pb_message_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -161,6 +243,18 @@ func file_pkg_tbtc_gen_pb_message_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_tbtc_gen_pb_message_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PresigningDoneMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -168,7 +262,7 @@ func file_pkg_tbtc_gen_pb_message_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_tbtc_gen_pb_message_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,46 @@
+package tbtc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// heartbeatAction is a wallet action signing the given heartbeat messages.
+type heartbeatAction struct {
+	executingWallet   wallet
+	signingExecutor   *signingExecutor
+	messages          []*big.Int
+	signingStartBlock uint64
+}
+
+func (ha *heartbeatAction) execute(ctx context.Context) error {
+	signatures, err := ha.signingExecutor.signBatch(
+		ctx,
+		ha.messages,
+		ha.signingStartBlock,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot sign batch: [%v]", err)
+	}
+
+	logger.Infof(
+		"generated [%v] signatures for heartbeat requested at block [%v]",
+		len(signatures),
+		ha.signingStartBlock,
+	)
+
+	return nil
+}
+
+func (ha *heartbeatAction) wallet() wallet {
+	return ha.executingWallet
+}
+
+func (ha *heartbeatAction) actionType() walletActionType {
+	return actionHeartbeat
+}
+
+func (ha *heartbeatAction) startBlock() uint64 {
+	return ha.signingStartBlock
+}
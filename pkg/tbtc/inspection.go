@@ -0,0 +1,158 @@
+package tbtc
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+// StoredWallet is a read-only view of a wallet whose signers are held in
+// the wallet storage of the node. It is meant to be used by operator tools
+// inspecting the storage outside the running client.
+type StoredWallet struct {
+	storageKey string
+	wallet     wallet
+	// signers are sorted by their signing group member indexes.
+	signers []*signer
+}
+
+// ReadStoredWallets reads all wallets held in the given wallet storage
+// persistence handle. Wallets are sorted by their storage keys. Along with
+// the wallets, the function returns errors that occurred for stored signer
+// files that could not be read.
+func ReadStoredWallets(
+	persistence persistence.ProtectedHandle,
+) ([]*StoredWallet, []error) {
+	signersByWallet, errs := newWalletStorage(persistence).readSigners()
+
+	storedWallets := make([]*StoredWallet, 0, len(signersByWallet))
+	for storageKey, signers := range signersByWallet {
+		sort.Slice(signers, func(i, j int) bool {
+			return signers[i].signingGroupMemberIndex <
+				signers[j].signingGroupMemberIndex
+		})
+
+		storedWallets = append(storedWallets, &StoredWallet{
+			storageKey: storageKey,
+			// All signers belong to one wallet. Take that wallet from the
+			// first signer.
+			wallet:  signers[0].wallet,
+			signers: signers,
+		})
+	}
+
+	sort.Slice(storedWallets, func(i, j int) bool {
+		return storedWallets[i].storageKey < storedWallets[j].storageKey
+	})
+
+	return storedWallets, errs
+}
+
+// StorageKey returns the key identifying the wallet in the storage. It is
+// also the name of the storage directory holding the wallet's signers.
+func (sw *StoredWallet) StorageKey() string {
+	return sw.storageKey
+}
+
+// PublicKey returns the ECDSA public key of the wallet.
+func (sw *StoredWallet) PublicKey() *ecdsa.PublicKey {
+	return sw.wallet.publicKey
+}
+
+// PublicKeyHash returns the 20-byte wallet public key hash, i.e. the
+// SHA-256+RIPEMD-160 hash computed over the compressed wallet public key.
+func (sw *StoredWallet) PublicKeyHash() [20]byte {
+	return bitcoin.PublicKeyHash(sw.wallet.publicKey)
+}
+
+// SigningGroupOperators returns addresses of operators forming the wallet's
+// signing group.
+func (sw *StoredWallet) SigningGroupOperators() []chain.Address {
+	return sw.wallet.signingGroupOperators
+}
+
+// MemberIndexes returns signing group member indexes of signers held in the
+// storage, in ascending order.
+func (sw *StoredWallet) MemberIndexes() []group.MemberIndex {
+	memberIndexes := make([]group.MemberIndex, len(sw.signers))
+	for i, signer := range sw.signers {
+		memberIndexes[i] = signer.signingGroupMemberIndex
+	}
+
+	return memberIndexes
+}
+
+// VerifyShares verifies private key shares of all signers held in the
+// storage. Each share must be internally consistent and must correspond to
+// the declared wallet public key. Each signer must also point to the same
+// signing group and occupy a seat within that group. Returns an error
+// describing all inconsistencies found or nil if all shares are valid.
+func (sw *StoredWallet) VerifyShares() error {
+	var result *multierror.Error
+
+	for _, signer := range sw.signers {
+		if err := sw.verifySigner(signer); err != nil {
+			result = multierror.Append(result, fmt.Errorf(
+				"invalid signer with index [%v]: [%w]",
+				signer.signingGroupMemberIndex,
+				err,
+			))
+		}
+	}
+
+	return result.ErrorOrNil()
+}
+
+func (sw *StoredWallet) verifySigner(signer *signer) error {
+	if len(signer.wallet.signingGroupOperators) !=
+		len(sw.wallet.signingGroupOperators) {
+		return fmt.Errorf("signing group differs from other signers")
+	}
+	for i, operator := range signer.wallet.signingGroupOperators {
+		if operator != sw.wallet.signingGroupOperators[i] {
+			return fmt.Errorf("signing group differs from other signers")
+		}
+	}
+
+	return signer.validate()
+}
+
+// Export saves signers of the wallet using the given persistence handle.
+// The signers are saved in the same layout as in the wallet storage of the
+// node so the exported files can be used to restore the wallet. The caller
+// is responsible for providing a handle that encrypts the saved data.
+func (sw *StoredWallet) Export(persistence persistence.ProtectedHandle) error {
+	exportStorage := newWalletStorage(persistence)
+
+	for _, signer := range sw.signers {
+		if err := exportStorage.saveSigner(signer); err != nil {
+			return fmt.Errorf(
+				"cannot export signer with index [%v]: [%w]",
+				signer.signingGroupMemberIndex,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// ValidateStoredSigner checks whether the given content of a file held in
+// the wallet storage can be unmarshaled to a valid signer. It is meant to be
+// used to validate wallet storage files before they are restored from
+// a backup.
+func ValidateStoredSigner(content []byte) error {
+	signer := &signer{}
+	if err := signer.Unmarshal(content); err != nil {
+		return err
+	}
+
+	return signer.validate()
+}
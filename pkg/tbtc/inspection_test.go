@@ -0,0 +1,274 @@
+package tbtc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/internal/tecdsatest"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa"
+)
+
+func TestReadStoredWallets(t *testing.T) {
+	signers := createMockWalletSigners(t, 3)
+
+	persistenceHandle := &mockPersistenceHandle{
+		saved: []persistence.DataDescriptor{
+			// Store signers in reverse order to make sure they are sorted.
+			createMockSignerDescriptor(t, signers[2]),
+			createMockSignerDescriptor(t, signers[0]),
+			&mockDescriptor{
+				name:      "membership_2",
+				directory: getWalletStorageKey(signers[0].wallet.publicKey),
+				content:   []byte{0x01, 0x02},
+			},
+		},
+	}
+
+	storedWallets, errs := ReadStoredWallets(persistenceHandle)
+
+	testutils.AssertIntsEqual(t, "errors count", 1, len(errs))
+	testutils.AssertIntsEqual(t, "wallets count", 1, len(storedWallets))
+
+	storedWallet := storedWallets[0]
+
+	testutils.AssertStringsEqual(
+		t,
+		"storage key",
+		getWalletStorageKey(signers[0].wallet.publicKey),
+		storedWallet.StorageKey(),
+	)
+
+	if !storedWallet.PublicKey().Equal(signers[0].wallet.publicKey) {
+		t.Errorf("unexpected wallet public key")
+	}
+
+	expectedMemberIndexes := []group.MemberIndex{1, 3}
+	if !reflect.DeepEqual(
+		expectedMemberIndexes,
+		storedWallet.MemberIndexes(),
+	) {
+		t.Errorf(
+			"unexpected member indexes\nexpected: %v\nactual:   %v\n",
+			expectedMemberIndexes,
+			storedWallet.MemberIndexes(),
+		)
+	}
+
+	if !reflect.DeepEqual(
+		signers[0].wallet.signingGroupOperators,
+		storedWallet.SigningGroupOperators(),
+	) {
+		t.Errorf("unexpected signing group operators")
+	}
+}
+
+func TestStoredWallet_VerifyShares(t *testing.T) {
+	var tests = map[string]struct {
+		modifySignersFn func(signers []*signer) []*signer
+		expectedErrors  []string
+	}{
+		"all shares valid": {
+			modifySignersFn: func(signers []*signer) []*signer {
+				return signers
+			},
+		},
+		"inconsistent private key share": {
+			modifySignersFn: func(signers []*signer) []*signer {
+				data := signers[1].privateKeyShare.Data()
+				data.ECDSAPub = data.BigXj[0]
+				signers[1].privateKeyShare = tecdsa.NewPrivateKeyShare(data)
+
+				return signers
+			},
+			expectedErrors: []string{
+				"invalid signer with index [2]: [inconsistent private key share: " +
+					"[public shares do not reconstruct the public key]]",
+			},
+		},
+		"shares not matching wallet public key": {
+			modifySignersFn: func(signers []*signer) []*signer {
+				otherPublicKey := signers[0].privateKeyShare.Data().BigXj[0]
+				for _, signer := range signers {
+					signer.wallet.publicKey = otherPublicKey.ToECDSAPubKey()
+				}
+
+				return signers
+			},
+			expectedErrors: []string{
+				"invalid signer with index [1]: " +
+					"[private key share does not reconstruct the wallet public key]",
+				"invalid signer with index [2]: " +
+					"[private key share does not reconstruct the wallet public key]",
+				"invalid signer with index [3]: " +
+					"[private key share does not reconstruct the wallet public key]",
+			},
+		},
+		"member index out of range": {
+			modifySignersFn: func(signers []*signer) []*signer {
+				signers[2].signingGroupMemberIndex = 6
+				return signers
+			},
+			expectedErrors: []string{
+				"invalid signer with index [6]: " +
+					"[member index out of the signing group range [1, 5]]",
+			},
+		},
+		"different signing group": {
+			modifySignersFn: func(signers []*signer) []*signer {
+				signers[1].wallet.signingGroupOperators = []chain.Address{
+					"address-1",
+				}
+				return signers
+			},
+			expectedErrors: []string{
+				"invalid signer with index [2]: " +
+					"[signing group differs from other signers]",
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			signers := test.modifySignersFn(createMockWalletSigners(t, 3))
+
+			storedWallet := &StoredWallet{
+				storageKey: getWalletStorageKey(signers[0].wallet.publicKey),
+				wallet:     signers[0].wallet,
+				signers:    signers,
+			}
+
+			err := storedWallet.VerifyShares()
+
+			if len(test.expectedErrors) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: [%v]", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			for _, expectedError := range test.expectedErrors {
+				if !strings.Contains(err.Error(), expectedError) {
+					t.Errorf(
+						"error [%v] does not contain [%v]",
+						err,
+						expectedError,
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestStoredWallet_Export(t *testing.T) {
+	signers := createMockWalletSigners(t, 2)
+
+	storedWallet := &StoredWallet{
+		storageKey: getWalletStorageKey(signers[0].wallet.publicKey),
+		wallet:     signers[0].wallet,
+		signers:    signers,
+	}
+
+	exportHandle := &mockPersistenceHandle{}
+
+	err := storedWallet.Export(exportHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"exported signers count",
+		2,
+		len(exportHandle.saved),
+	)
+
+	for i, descriptor := range exportHandle.saved {
+		testutils.AssertStringsEqual(
+			t,
+			fmt.Sprintf("directory of exported signer [%v]", i),
+			storedWallet.StorageKey(),
+			descriptor.Directory(),
+		)
+		testutils.AssertStringsEqual(
+			t,
+			fmt.Sprintf("name of exported signer [%v]", i),
+			fmt.Sprintf("/membership_%v", i+1),
+			descriptor.Name(),
+		)
+	}
+
+	// Exported signers must be readable back as the same wallet.
+	importedWallets, errs := ReadStoredWallets(exportHandle)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: [%v]", errs)
+	}
+
+	if !reflect.DeepEqual([]*StoredWallet{storedWallet}, importedWallets) {
+		t.Errorf("imported wallet differs from the exported one")
+	}
+}
+
+// createMockWalletSigners creates the given number of signers of the same
+// wallet, using consecutive private key share test fixtures. The signers
+// occupy consecutive seats of a signing group of five, starting from the
+// first one.
+func createMockWalletSigners(t *testing.T, count int) []*signer {
+	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(count)
+	if err != nil {
+		t.Fatalf("failed to load test data: [%v]", err)
+	}
+
+	signingGroupOperators := []chain.Address{
+		"address-1",
+		"address-2",
+		"address-3",
+		"address-4",
+		"address-5",
+	}
+
+	signers := make([]*signer, count)
+	for i := range signers {
+		privateKeyShare := tecdsa.NewPrivateKeyShare(testData[i])
+
+		signers[i] = newSigner(
+			privateKeyShare.PublicKey(),
+			signingGroupOperators,
+			&GroupParameters{
+				GroupSize:       5,
+				GroupQuorum:     4,
+				HonestThreshold: 3,
+			},
+			group.MemberIndex(i+1),
+			privateKeyShare,
+		)
+	}
+
+	return signers
+}
+
+func createMockSignerDescriptor(
+	t *testing.T,
+	signer *signer,
+) persistence.DataDescriptor {
+	signerBytes, err := signer.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &mockDescriptor{
+		name:      fmt.Sprintf("membership_%v", signer.signingGroupMemberIndex),
+		directory: getWalletStorageKey(signer.wallet.publicKey),
+		content:   signerBytes,
+	}
+}
@@ -0,0 +1,428 @@
+package tbtc
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-log/v2"
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+// attemptJournalDirectory is the directory of the tbtc work persistence
+// holding the attempt journal. Each session is stored in a separate file.
+const attemptJournalDirectory = "journal"
+
+const (
+	// DkgJournalProtocol denotes journal sessions of DKG executions.
+	DkgJournalProtocol = "dkg"
+	// SigningJournalProtocol denotes journal sessions of signing executions.
+	SigningJournalProtocol = "signing"
+)
+
+// Outcomes of journal sessions and attempts.
+const (
+	// AttemptSucceeded denotes a successful attempt or session.
+	AttemptSucceeded = "succeeded"
+	// AttemptFailed denotes an attempt or session that failed with an error.
+	AttemptFailed = "failed"
+	// AttemptAnnouncementFailed denotes an attempt whose announcement phase
+	// failed.
+	AttemptAnnouncementFailed = "announcement_failed"
+	// AttemptNotEnoughReadyMembers denotes an attempt abandoned because not
+	// enough members announced their readiness.
+	AttemptNotEnoughReadyMembers = "not_enough_ready_members"
+	// AttemptSkipped denotes an attempt the member was excluded from.
+	AttemptSkipped = "skipped"
+)
+
+// AttemptJournalEntry describes a single attempt of a DKG or signing
+// session from the perspective of one member.
+type AttemptJournalEntry struct {
+	Number     uint   `json:"number"`
+	StartBlock uint64 `json:"start_block"`
+	// ReadyMembers are the members that announced their readiness for
+	// the attempt.
+	ReadyMembers []group.MemberIndex `json:"ready_members"`
+	// ExcludedMembers are the members excluded from the attempt by the
+	// member selection. Empty if the attempt did not reach the selection.
+	ExcludedMembers []group.MemberIndex `json:"excluded_members,omitempty"`
+	Outcome         string              `json:"outcome"`
+	FailureReason   string              `json:"failure_reason,omitempty"`
+	StartedAt       time.Time           `json:"started_at"`
+	Duration        time.Duration       `json:"duration"`
+}
+
+// AttemptJournalSession is the journal of a single DKG or signing session
+// executed by one member controlled by the node.
+type AttemptJournalSession struct {
+	Protocol string `json:"protocol"`
+	// Key is the hex-encoded DKG seed or signed message.
+	Key string `json:"key"`
+	// Wallet is the hex-encoded public key of the signing wallet. Empty for
+	// DKG sessions.
+	Wallet      string            `json:"wallet,omitempty"`
+	MemberIndex group.MemberIndex `json:"member_index"`
+	StartBlock  uint64            `json:"start_block"`
+	StartedAt   time.Time         `json:"started_at"`
+	// Outcome is empty as long as the session is in progress.
+	Outcome       string                 `json:"outcome,omitempty"`
+	FailureReason string                 `json:"failure_reason,omitempty"`
+	Duration      time.Duration          `json:"duration,omitempty"`
+	Attempts      []*AttemptJournalEntry `json:"attempts"`
+}
+
+// name returns the name of the file holding the session.
+func (ajs *AttemptJournalSession) name() string {
+	return fmt.Sprintf(
+		"%s-%s-%d-%d",
+		ajs.Protocol,
+		ajs.Key,
+		ajs.StartBlock,
+		ajs.MemberIndex,
+	)
+}
+
+// attemptJournal is a persistent journal of DKG and signing attempts
+// executed by the node. The journal keeps at most maxSessions sessions
+// not older than the retention period; the oldest sessions are removed
+// once the limits are exceeded. A non-positive limit disables it.
+type attemptJournal struct {
+	logger      log.StandardLogger
+	persistence persistence.BasicHandle
+
+	maxSessions int
+	retention   time.Duration
+
+	mutex sync.Mutex
+	// sessions are keyed by the names of the files holding them.
+	sessions map[string]*AttemptJournalSession
+}
+
+func newAttemptJournal(
+	logger log.StandardLogger,
+	persistence persistence.BasicHandle,
+	maxSessions int,
+	retention time.Duration,
+) *attemptJournal {
+	sessions, errs := readAttemptJournal(persistence)
+	for _, err := range errs {
+		logger.Errorf("could not load attempt journal: [%v]", err)
+	}
+
+	aj := &attemptJournal{
+		logger:      logger,
+		persistence: persistence,
+		maxSessions: maxSessions,
+		retention:   retention,
+		sessions:    make(map[string]*AttemptJournalSession),
+	}
+
+	for _, session := range sessions {
+		aj.sessions[session.name()] = session
+	}
+
+	aj.prune(time.Now())
+
+	return aj
+}
+
+// startSession records the start of a new session and returns a handle
+// used to record its attempts. Returns a nil handle for a nil journal.
+func (aj *attemptJournal) startSession(
+	protocol string,
+	key string,
+	wallet string,
+	memberIndex group.MemberIndex,
+	startBlock uint64,
+) *attemptJournalSession {
+	if aj == nil {
+		return nil
+	}
+
+	now := time.Now()
+
+	session := &AttemptJournalSession{
+		Protocol:    protocol,
+		Key:         key,
+		Wallet:      wallet,
+		MemberIndex: memberIndex,
+		StartBlock:  startBlock,
+		StartedAt:   now,
+		Attempts:    make([]*AttemptJournalEntry, 0),
+	}
+
+	aj.mutex.Lock()
+	defer aj.mutex.Unlock()
+
+	aj.sessions[session.name()] = session
+	aj.save(session)
+	aj.pruneLocked(now)
+
+	return &attemptJournalSession{journal: aj, name: session.name()}
+}
+
+// recentSessions returns at most limit most recently started sessions,
+// from the newest to the oldest one.
+func (aj *attemptJournal) recentSessions(limit int) []*AttemptJournalSession {
+	aj.mutex.Lock()
+	defer aj.mutex.Unlock()
+
+	sessions := aj.sortedSessionsLocked()
+
+	// Reverse the order so the newest sessions come first.
+	for i, j := 0, len(sessions)-1; i < j; i, j = i+1, j-1 {
+		sessions[i], sessions[j] = sessions[j], sessions[i]
+	}
+
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+
+	return sessions
+}
+
+// update applies the given function to the session with the given name and
+// persists the session. It is a no-op if the session has been already
+// removed from the journal.
+func (aj *attemptJournal) update(
+	name string,
+	updateFn func(session *AttemptJournalSession),
+) {
+	aj.mutex.Lock()
+	defer aj.mutex.Unlock()
+
+	session, ok := aj.sessions[name]
+	if !ok {
+		return
+	}
+
+	updateFn(session)
+	aj.save(session)
+}
+
+func (aj *attemptJournal) save(session *AttemptJournalSession) {
+	content, err := json.Marshal(session)
+	if err != nil {
+		aj.logger.Errorf(
+			"could not marshal attempt journal session [%v]: [%v]",
+			session.name(),
+			err,
+		)
+		return
+	}
+
+	err = aj.persistence.Save(content, attemptJournalDirectory, session.name())
+	if err != nil {
+		aj.logger.Errorf(
+			"could not save attempt journal session [%v]: [%v]",
+			session.name(),
+			err,
+		)
+	}
+}
+
+func (aj *attemptJournal) prune(now time.Time) {
+	aj.mutex.Lock()
+	defer aj.mutex.Unlock()
+
+	aj.pruneLocked(now)
+}
+
+// pruneLocked removes sessions exceeding the retention limits. Must be
+// called with the mutex held.
+func (aj *attemptJournal) pruneLocked(now time.Time) {
+	sessions := aj.sortedSessionsLocked()
+
+	for i, session := range sessions {
+		expired := aj.retention > 0 &&
+			now.Sub(session.StartedAt) > aj.retention
+		excessive := aj.maxSessions > 0 &&
+			len(sessions)-i > aj.maxSessions
+
+		if !expired && !excessive {
+			// Sessions are sorted from the oldest one so the remaining
+			// ones are within the limits as well.
+			break
+		}
+
+		delete(aj.sessions, session.name())
+
+		err := aj.persistence.Delete(attemptJournalDirectory, session.name())
+		if err != nil {
+			aj.logger.Errorf(
+				"could not delete attempt journal session [%v]: [%v]",
+				session.name(),
+				err,
+			)
+		}
+	}
+}
+
+// sortedSessionsLocked returns copies of all sessions sorted from the
+// oldest to the newest one. Must be called with the mutex held.
+func (aj *attemptJournal) sortedSessionsLocked() []*AttemptJournalSession {
+	sessions := make([]*AttemptJournalSession, 0, len(aj.sessions))
+	for _, session := range aj.sessions {
+		sessionCopy := *session
+		sessionCopy.Attempts = append(
+			[]*AttemptJournalEntry{},
+			session.Attempts...,
+		)
+		sessions = append(sessions, &sessionCopy)
+	}
+
+	sortAttemptJournalSessions(sessions)
+
+	return sessions
+}
+
+// attemptJournalSession is a handle of a single session of the attempt
+// journal. All its methods are no-ops for a nil handle.
+type attemptJournalSession struct {
+	journal *attemptJournal
+	name    string
+}
+
+// recordAttempt records the given attempt of the session with the given
+// outcome. The duration of the attempt is measured since the start time of
+// the entry. A non-nil error is recorded as the failure reason.
+func (ajs *attemptJournalSession) recordAttempt(
+	entry *AttemptJournalEntry,
+	outcome string,
+	err error,
+) {
+	if ajs == nil {
+		return
+	}
+
+	entry.Outcome = outcome
+	entry.Duration = time.Since(entry.StartedAt)
+	if err != nil {
+		entry.FailureReason = err.Error()
+	}
+
+	ajs.journal.update(ajs.name, func(session *AttemptJournalSession) {
+		session.Attempts = append(session.Attempts, entry)
+	})
+}
+
+// finish records the outcome of the session. A nil error denotes
+// a successful session.
+func (ajs *attemptJournalSession) finish(err error) {
+	if ajs == nil {
+		return
+	}
+
+	ajs.journal.update(ajs.name, func(session *AttemptJournalSession) {
+		session.Duration = time.Since(session.StartedAt)
+
+		if err != nil {
+			session.Outcome = AttemptFailed
+			session.FailureReason = err.Error()
+		} else {
+			session.Outcome = AttemptSucceeded
+		}
+	})
+}
+
+// ReadAttemptJournal reads all sessions held in the attempt journal of
+// the given tbtc work persistence handle. Sessions are sorted from the oldest
+// to the newest one. Along with the sessions, the function returns errors
+// that occurred for journal files that could not be read.
+func ReadAttemptJournal(
+	persistence persistence.BasicHandle,
+) ([]*AttemptJournalSession, []error) {
+	sessions, errs := readAttemptJournal(persistence)
+	sortAttemptJournalSessions(sessions)
+
+	return sessions, errs
+}
+
+func readAttemptJournal(
+	persistence persistence.BasicHandle,
+) ([]*AttemptJournalSession, []error) {
+	sessions := make([]*AttemptJournalSession, 0)
+	errs := make([]error, 0)
+
+	descriptorsChan, errorsChan := persistence.ReadAll()
+
+	// Two goroutines read from descriptors and errors channels and collect
+	// the results. The reason for using two goroutines at the same time - one
+	// for descriptors and one for errors - is that channels do not have to be
+	// buffered, and we do not know in what order the information is written to
+	// channels.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	var descriptorsErrs []error
+
+	go func() {
+		defer wg.Done()
+
+		for descriptor := range descriptorsChan {
+			// Read only the files located in the journal directory.
+			if descriptor.Directory() != attemptJournalDirectory {
+				continue
+			}
+
+			session, err := readAttemptJournalSession(descriptor)
+			if err != nil {
+				descriptorsErrs = append(descriptorsErrs, err)
+				continue
+			}
+
+			sessions = append(sessions, session)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for err := range errorsChan {
+			errs = append(errs, err)
+		}
+	}()
+
+	wg.Wait()
+
+	return sessions, append(errs, descriptorsErrs...)
+}
+
+func readAttemptJournalSession(
+	descriptor persistence.DataDescriptor,
+) (*AttemptJournalSession, error) {
+	content, err := descriptor.Content()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot read attempt journal file [%v]: [%w]",
+			descriptor.Name(),
+			err,
+		)
+	}
+
+	session := &AttemptJournalSession{}
+	if err := json.Unmarshal(content, session); err != nil {
+		return nil, fmt.Errorf(
+			"cannot unmarshal attempt journal file [%v]: [%w]",
+			descriptor.Name(),
+			err,
+		)
+	}
+
+	return session, nil
+}
+
+func sortAttemptJournalSessions(sessions []*AttemptJournalSession) {
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].StartedAt.Equal(sessions[j].StartedAt) {
+			return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+		}
+
+		return sessions[i].name() < sessions[j].name()
+	})
+}
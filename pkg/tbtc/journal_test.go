@@ -0,0 +1,373 @@
+package tbtc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/chain"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa/dkg"
+)
+
+func TestAttemptJournal(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	journal := newAttemptJournal(&testutils.MockLogger{}, persistenceHandle, 0, 0)
+
+	session := journal.startSession(DkgJournalProtocol, "ff", "", 3, 100)
+
+	session.recordAttempt(
+		&AttemptJournalEntry{
+			Number:       1,
+			StartBlock:   100,
+			ReadyMembers: []group.MemberIndex{1, 2, 3},
+			StartedAt:    time.Now(),
+		},
+		AttemptNotEnoughReadyMembers,
+		nil,
+	)
+	session.recordAttempt(
+		&AttemptJournalEntry{
+			Number:          2,
+			StartBlock:      261,
+			ReadyMembers:    []group.MemberIndex{1, 2, 3, 4},
+			ExcludedMembers: []group.MemberIndex{4},
+			StartedAt:       time.Now(),
+		},
+		AttemptFailed,
+		fmt.Errorf("invalid data"),
+	)
+	session.finish(context.Canceled)
+
+	testutils.AssertIntsEqual(
+		t,
+		"persisted sessions count",
+		1,
+		len(persistenceHandle.saved),
+	)
+	testutils.AssertStringsEqual(
+		t,
+		"persisted session directory",
+		attemptJournalDirectory,
+		persistenceHandle.saved[0].Directory(),
+	)
+
+	// The journal is read from the persistence the same way by the
+	// restarted client and the CLI.
+	sessions, errs := ReadAttemptJournal(persistenceHandle)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: [%v]", errs)
+	}
+
+	testutils.AssertIntsEqual(t, "sessions count", 1, len(sessions))
+
+	readSession := sessions[0]
+	testutils.AssertStringsEqual(t, "protocol", DkgJournalProtocol, readSession.Protocol)
+	testutils.AssertStringsEqual(t, "key", "ff", readSession.Key)
+	testutils.AssertIntsEqual(t, "member index", 3, int(readSession.MemberIndex))
+	testutils.AssertStringsEqual(t, "outcome", AttemptFailed, readSession.Outcome)
+	testutils.AssertStringsEqual(
+		t,
+		"failure reason",
+		context.Canceled.Error(),
+		readSession.FailureReason,
+	)
+	testutils.AssertIntsEqual(t, "attempts count", 2, len(readSession.Attempts))
+
+	attempt := readSession.Attempts[1]
+	testutils.AssertIntsEqual(t, "attempt number", 2, int(attempt.Number))
+	testutils.AssertStringsEqual(t, "attempt outcome", AttemptFailed, attempt.Outcome)
+	testutils.AssertStringsEqual(
+		t,
+		"attempt failure reason",
+		"invalid data",
+		attempt.FailureReason,
+	)
+	if !reflect.DeepEqual([]group.MemberIndex{4}, attempt.ExcludedMembers) {
+		t.Errorf("unexpected excluded members: [%v]", attempt.ExcludedMembers)
+	}
+
+	restartedJournal := newAttemptJournal(
+		&testutils.MockLogger{},
+		persistenceHandle,
+		0,
+		0,
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"restarted journal sessions count",
+		1,
+		len(restartedJournal.recentSessions(10)),
+	)
+}
+
+func TestAttemptJournal_Retention(t *testing.T) {
+	now := time.Now()
+
+	var tests = map[string]struct {
+		maxSessions  int
+		retention    time.Duration
+		expectedKeys []string
+	}{
+		"no limits": {
+			expectedKeys: []string{"4", "3", "2", "1"},
+		},
+		"max sessions limit": {
+			maxSessions:  2,
+			expectedKeys: []string{"4", "3"},
+		},
+		"retention limit": {
+			retention:    90 * time.Minute,
+			expectedKeys: []string{"4", "3"},
+		},
+		"both limits": {
+			maxSessions:  1,
+			retention:    90 * time.Minute,
+			expectedKeys: []string{"4"},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			persistenceHandle := &mockPersistenceHandle{}
+
+			// Sessions started 3, 2, and 1 hours ago.
+			loader := &attemptJournal{
+				logger:      &testutils.MockLogger{},
+				persistence: persistenceHandle,
+				sessions:    make(map[string]*AttemptJournalSession),
+			}
+			for i := 1; i <= 3; i++ {
+				loader.save(&AttemptJournalSession{
+					Protocol:  SigningJournalProtocol,
+					Key:       fmt.Sprintf("%d", i),
+					StartedAt: now.Add(-time.Duration(4-i) * time.Hour),
+				})
+			}
+
+			journal := newAttemptJournal(
+				&testutils.MockLogger{},
+				persistenceHandle,
+				test.maxSessions,
+				test.retention,
+			)
+
+			journal.startSession(SigningJournalProtocol, "4", "aa", 1, 100)
+
+			sessions := journal.recentSessions(10)
+
+			keys := make([]string, len(sessions))
+			for i, session := range sessions {
+				keys[i] = session.Key
+			}
+
+			if !reflect.DeepEqual(test.expectedKeys, keys) {
+				t.Errorf(
+					"unexpected sessions\nexpected: [%v]\nactual:   [%v]",
+					test.expectedKeys,
+					keys,
+				)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"persisted sessions count",
+				len(test.expectedKeys),
+				len(persistenceHandle.saved),
+			)
+		})
+	}
+}
+
+func TestAttemptJournal_Nil(t *testing.T) {
+	var journal *attemptJournal
+
+	session := journal.startSession(DkgJournalProtocol, "ff", "", 1, 100)
+	if session != nil {
+		t.Fatal("expected nil session")
+	}
+
+	// Recording on a nil session must not panic.
+	session.recordAttempt(&AttemptJournalEntry{}, AttemptSucceeded, nil)
+	session.finish(nil)
+}
+
+func TestDkgRetryLoop_AttemptJournal(t *testing.T) {
+	seed := big.NewInt(100)
+
+	groupParameters := &GroupParameters{
+		GroupSize:       10,
+		GroupQuorum:     8,
+		HonestThreshold: 6,
+	}
+
+	selectedOperators := chain.Addresses{
+		"address-1",
+		"address-2",
+		"address-8",
+		"address-4",
+		"address-2",
+		"address-6",
+		"address-7",
+		"address-8",
+		"address-9",
+		"address-8",
+	}
+
+	membersIndexes := make([]group.MemberIndex, 0)
+	for i := range selectedOperators {
+		membersIndexes = append(membersIndexes, group.MemberIndex(i+1))
+	}
+
+	type expectedAttempt struct {
+		outcome         string
+		excludedMembers []group.MemberIndex
+	}
+
+	var tests = map[string]struct {
+		memberIndex      group.MemberIndex
+		dkgAttemptFn     dkgAttemptFn
+		expectedAttempts []expectedAttempt
+	}{
+		"DKG error on initial attempt": {
+			memberIndex: 1,
+			dkgAttemptFn: func(attempt *dkgAttemptParams) (*dkg.Result, error) {
+				if attempt.number == 1 {
+					return nil, fmt.Errorf("invalid data")
+				}
+
+				return &dkg.Result{}, nil
+			},
+			expectedAttempts: []expectedAttempt{
+				{AttemptFailed, []group.MemberIndex{}},
+				{AttemptSucceeded, []group.MemberIndex{2, 5}},
+			},
+		},
+		// The announcement of the first attempt fails and member 5 is excluded
+		// from the second attempt.
+		"executing member excluded": {
+			memberIndex: 5,
+			dkgAttemptFn: func(attempt *dkgAttemptParams) (*dkg.Result, error) {
+				return &dkg.Result{}, nil
+			},
+			expectedAttempts: []expectedAttempt{
+				{AttemptAnnouncementFailed, nil},
+				{AttemptSkipped, []group.MemberIndex{2, 5}},
+				{AttemptSucceeded, []group.MemberIndex{9}},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			announcer := &mockDkgAnnouncer{
+				outgoingAnnouncements: make(map[string]group.MemberIndex),
+				incomingAnnouncementsFn: func(
+					sessionID string,
+				) ([]group.MemberIndex, error) {
+					if test.memberIndex == 5 &&
+						sessionID == fmt.Sprintf("%v-%v", seed, 1) {
+						return nil, fmt.Errorf("unexpected error")
+					}
+
+					return membersIndexes, nil
+				},
+			}
+
+			journal := newAttemptJournal(
+				&testutils.MockLogger{},
+				&mockPersistenceHandle{},
+				0,
+				0,
+			)
+
+			journalSession := journal.startSession(
+				DkgJournalProtocol,
+				seed.Text(16),
+				"",
+				test.memberIndex,
+				200,
+			)
+
+			retryLoop := newDkgRetryLoop(
+				&testutils.MockLogger{},
+				seed,
+				200,
+				test.memberIndex,
+				selectedOperators,
+				groupParameters,
+				&MainnetProtocolTiming,
+				announcer,
+				journalSession,
+			)
+
+			ctx, cancelCtx := context.WithTimeout(
+				context.Background(),
+				10*time.Second,
+			)
+			defer cancelCtx()
+
+			_, err := retryLoop.start(
+				ctx,
+				func(ctx context.Context, attemptStartBlock uint64) error {
+					return nil
+				},
+				test.dkgAttemptFn,
+			)
+			journalSession.finish(err)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sessions := journal.recentSessions(1)
+			testutils.AssertIntsEqual(t, "sessions count", 1, len(sessions))
+			testutils.AssertStringsEqual(
+				t,
+				"session outcome",
+				AttemptSucceeded,
+				sessions[0].Outcome,
+			)
+
+			attempts := sessions[0].Attempts
+			testutils.AssertIntsEqual(
+				t,
+				"attempts count",
+				len(test.expectedAttempts),
+				len(attempts),
+			)
+
+			for i, expected := range test.expectedAttempts {
+				testutils.AssertIntsEqual(
+					t,
+					fmt.Sprintf("attempt [%v] number", i),
+					i+1,
+					int(attempts[i].Number),
+				)
+				testutils.AssertStringsEqual(
+					t,
+					fmt.Sprintf("attempt [%v] outcome", i),
+					expected.outcome,
+					attempts[i].Outcome,
+				)
+				if !reflect.DeepEqual(
+					expected.excludedMembers,
+					attempts[i].ExcludedMembers,
+				) {
+					t.Errorf(
+						"unexpected excluded members of attempt [%v]\n"+
+							"expected: [%v]\n"+
+							"actual:   [%v]",
+						i,
+						expected.excludedMembers,
+						attempts[i].ExcludedMembers,
+					)
+				}
+			}
+		})
+	}
+}
@@ -37,6 +37,9 @@ func (s *signer) Marshal() ([]byte, error) {
 	pbWallet := &pb.Wallet{
 		PublicKey:             walletPublicKey,
 		SigningGroupOperators: walletSigningGroupOperators,
+		GroupSize:             uint32(s.wallet.groupParameters.GroupSize),
+		GroupQuorum:           uint32(s.wallet.groupParameters.GroupQuorum),
+		HonestThreshold:       uint32(s.wallet.groupParameters.HonestThreshold),
 	}
 
 	privateKeyShare, err := s.privateKeyShare.Marshal()
@@ -74,9 +77,21 @@ func (s *signer) Unmarshal(bytes []byte) error {
 		return fmt.Errorf("cannot unmarshal private key share: [%w]", err)
 	}
 
+	// Wallets persisted before the group parameters were stored along
+	// with them were created with the legacy group parameters.
+	walletGroupParameters := legacyGroupParameters
+	if pbSigner.Wallet.GroupSize != 0 {
+		walletGroupParameters = &GroupParameters{
+			GroupSize:       int(pbSigner.Wallet.GroupSize),
+			GroupQuorum:     int(pbSigner.Wallet.GroupQuorum),
+			HonestThreshold: int(pbSigner.Wallet.HonestThreshold),
+		}
+	}
+
 	s.wallet = wallet{
 		publicKey:             walletPublicKey,
 		signingGroupOperators: walletSigningGroupOperators,
+		groupParameters:       walletGroupParameters,
 	}
 	s.signingGroupMemberIndex = group.MemberIndex(pbSigner.SigningGroupMemberIndex)
 	s.privateKeyShare = privateKeyShare
@@ -125,6 +140,51 @@ func (sdm *signingDoneMessage) Unmarshal(bytes []byte) error {
 	return nil
 }
 
+// Marshal converts the presigningDoneMessage to a byte array.
+func (pdm *presigningDoneMessage) Marshal() ([]byte, error) {
+	membersIndexes := make([]uint32, len(pdm.membersIndexes))
+	for i, memberIndex := range pdm.membersIndexes {
+		membersIndexes[i] = uint32(memberIndex)
+	}
+
+	return proto.Marshal(&pb.PresigningDoneMessage{
+		SenderID:       uint32(pdm.senderID),
+		SessionBlock:   pdm.sessionBlock,
+		MembersIndexes: membersIndexes,
+		PublicNonce:    pdm.publicNonce,
+	})
+}
+
+// Unmarshal converts a byte array back to the presigningDoneMessage.
+func (pdm *presigningDoneMessage) Unmarshal(bytes []byte) error {
+	pbMsg := pb.PresigningDoneMessage{}
+	if err := proto.Unmarshal(bytes, &pbMsg); err != nil {
+		return fmt.Errorf(
+			"failed to unmarshal PresigningDoneMessage: [%v]",
+			err,
+		)
+	}
+
+	if err := validateMemberIndex(pbMsg.SenderID); err != nil {
+		return err
+	}
+
+	membersIndexes := make([]group.MemberIndex, len(pbMsg.MembersIndexes))
+	for i, memberIndex := range pbMsg.MembersIndexes {
+		if err := validateMemberIndex(memberIndex); err != nil {
+			return err
+		}
+		membersIndexes[i] = group.MemberIndex(memberIndex)
+	}
+
+	pdm.senderID = group.MemberIndex(pbMsg.SenderID)
+	pdm.sessionBlock = pbMsg.SessionBlock
+	pdm.membersIndexes = membersIndexes
+	pdm.publicNonce = pbMsg.PublicNonce
+
+	return nil
+}
+
 // marshalPublicKey converts an ECDSA public key to a byte
 // array (uncompressed).
 func marshalPublicKey(publicKey *ecdsa.PublicKey) ([]byte, error) {
@@ -26,6 +26,31 @@ func TestSignerMarshalling(t *testing.T) {
 	}
 }
 
+func TestSignerMarshalling_LegacyWallet(t *testing.T) {
+	marshaled := createMockSigner(t)
+	// Wallets persisted before the group parameters were stored along with
+	// them have all group parameters unset.
+	marshaled.wallet.groupParameters = &GroupParameters{}
+
+	unmarshaled := &signer{}
+
+	if err := pbutils.RoundTrip(marshaled, unmarshaled); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(
+		legacyGroupParameters,
+		unmarshaled.wallet.groupParameters,
+	) {
+		t.Errorf(
+			"unexpected wallet group parameters\n"+
+				"expected: %+v\n"+
+				"actual:   %+v\n",
+			legacyGroupParameters,
+			unmarshaled.wallet.groupParameters,
+		)
+	}
+}
+
 func TestSignerMarshalling_NonTECDSAKey(t *testing.T) {
 	signer := createMockSigner(t)
 
@@ -102,3 +127,55 @@ func TestFuzzSigningDoneMessage_MarshalingRoundtrip(t *testing.T) {
 func TestFuzzSigningDoneMessage_Unmarshaler(t *testing.T) {
 	pbutils.FuzzUnmarshaler(&signingDoneMessage{})
 }
+
+func TestPresigningDoneMessage_MarshalingRoundtrip(t *testing.T) {
+	msg := &presigningDoneMessage{
+		senderID:       group.MemberIndex(10),
+		sessionBlock:   4500,
+		membersIndexes: []group.MemberIndex{1, 3, 10},
+		publicNonce:    []byte{0x04, 0x01, 0x02},
+	}
+	unmarshaled := &presigningDoneMessage{}
+
+	err := pbutils.RoundTrip(msg, unmarshaled)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(msg, unmarshaled) {
+		t.Fatalf("unexpected content of unmarshaled message")
+	}
+}
+
+func TestFuzzPresigningDoneMessage_MarshalingRoundtrip(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		var (
+			senderID       group.MemberIndex
+			sessionBlock   uint64
+			membersIndexes []group.MemberIndex
+			publicNonce    []byte
+		)
+
+		f := fuzz.New().NilChance(0.1).
+			NumElements(0, 512).
+			Funcs(pbutils.FuzzFuncs()...)
+
+		f.Fuzz(&senderID)
+		f.Fuzz(&sessionBlock)
+		f.Fuzz(&membersIndexes)
+		f.Fuzz(&publicNonce)
+
+		doneMessage := &presigningDoneMessage{
+			senderID:       senderID,
+			sessionBlock:   sessionBlock,
+			membersIndexes: membersIndexes,
+			publicNonce:    publicNonce,
+		}
+
+		_ = pbutils.RoundTrip(doneMessage, &presigningDoneMessage{})
+	}
+}
+
+func TestFuzzPresigningDoneMessage_Unmarshaler(t *testing.T) {
+	pbutils.FuzzUnmarshaler(&presigningDoneMessage{})
+}
@@ -0,0 +1,299 @@
+package tbtc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+// movingFundsCommitmentConfirmationBlocks determines the block length of the
+// confirmation period that is preserved after a moving funds commitment
+// submission. Once the period elapses, the wallet moves its funds to the
+// committed target wallets.
+const movingFundsCommitmentConfirmationBlocks = 20
+
+// assembleMovingFundsTransaction constructs an unsigned moving funds Bitcoin
+// transaction.
+//
+// Regarding input arguments, the walletMainUtxo parameter is mandatory as
+// the wallet must have BTC to move. The targetWallets slice must contain
+// at least one element. The main UTXO value, minus the fee, is split equally
+// among all target wallets. If the value cannot be divided equally, the
+// remainder is transferred to the last target wallet. The fee argument is not
+// validated anyway so must be chosen with respect to the system limitations.
+//
+// The resulting bitcoin.TransactionBuilder instance holds all the data
+// necessary to sign the transaction and obtain a bitcoin.Transaction instance
+// ready to be spread across the Bitcoin network.
+func assembleMovingFundsTransaction(
+	bitcoinChain bitcoin.Chain,
+	walletPublicKey *ecdsa.PublicKey,
+	walletMainUtxo *bitcoin.UnspentTransactionOutput,
+	targetWallets [][20]byte,
+	fee int64,
+) (*bitcoin.TransactionBuilder, error) {
+	if walletMainUtxo == nil {
+		return nil, fmt.Errorf("wallet main UTXO is required")
+	}
+
+	if len(targetWallets) < 1 {
+		return nil, fmt.Errorf("at least one target wallet is required")
+	}
+
+	builder := bitcoin.NewTransactionBuilder(bitcoinChain)
+
+	err := builder.AddPublicKeyHashInput(walletMainUtxo)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot add input pointing to wallet main UTXO: [%v]",
+			err,
+		)
+	}
+
+	totalValue := builder.TotalInputsValue() - fee
+	targetWalletsCount := int64(len(targetWallets))
+	valuePerTargetWallet := totalValue / targetWalletsCount
+	remainder := totalValue % targetWalletsCount
+
+	if valuePerTargetWallet <= 0 {
+		return nil, fmt.Errorf(
+			"value per target wallet [%v] is not positive",
+			valuePerTargetWallet,
+		)
+	}
+
+	for i, targetWallet := range targetWallets {
+		outputScript, err := bitcoin.PayToWitnessPublicKeyHash(targetWallet)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot compute output script for target wallet [%v]: [%v]",
+				i,
+				err,
+			)
+		}
+
+		outputValue := valuePerTargetWallet
+		if i == len(targetWallets)-1 {
+			outputValue += remainder
+		}
+
+		builder.AddOutput(&bitcoin.TransactionOutput{
+			Value:           outputValue,
+			PublicKeyScript: outputScript,
+		})
+	}
+
+	return builder, nil
+}
+
+// movingFundsAction is a wallet action moving the wallet's funds to the
+// target wallets committed on-chain.
+type movingFundsAction struct {
+	node            *node
+	executingWallet wallet
+	event           *MovingFundsCommitmentSubmittedEvent
+}
+
+func (mfa *movingFundsAction) execute(ctx context.Context) error {
+	return mfa.node.handleMovingFundsCommitmentSubmitted(
+		ctx,
+		mfa.executingWallet,
+		mfa.event,
+	)
+}
+
+func (mfa *movingFundsAction) wallet() wallet {
+	return mfa.executingWallet
+}
+
+func (mfa *movingFundsAction) actionType() walletActionType {
+	return actionMovingFunds
+}
+
+func (mfa *movingFundsAction) startBlock() uint64 {
+	return mfa.event.BlockNumber + movingFundsCommitmentConfirmationBlocks
+}
+
+// handleMovingFundsCommitmentSubmitted handles the given moving funds
+// commitment submitted on-chain for the given wallet controlled by this node.
+// This function assembles a moving funds transaction transferring the
+// wallet's main UTXO to the committed target wallets, signs it, and
+// broadcasts it over the Bitcoin network. This function blocks until the
+// moving funds transaction is broadcast or an error occurs.
+func (n *node) handleMovingFundsCommitmentSubmitted(
+	ctx context.Context,
+	wallet wallet,
+	event *MovingFundsCommitmentSubmittedEvent,
+) error {
+	executor, ok, err := n.getSigningExecutor(wallet.publicKey)
+	if err != nil {
+		return fmt.Errorf("cannot get signing executor: [%v]", err)
+	}
+	if !ok {
+		return fmt.Errorf("node does not control signers of the wallet")
+	}
+
+	movingFundsLogger := logger.With(
+		zap.String("wallet", fmt.Sprintf("0x%x", event.WalletPublicKeyHash)),
+	)
+
+	confirmationBlock := event.BlockNumber +
+		movingFundsCommitmentConfirmationBlocks
+
+	movingFundsLogger.Infof(
+		"observed moving funds commitment with [%v] target wallets; "+
+			"waiting for block [%v] to confirm",
+		len(event.TargetWallets),
+		confirmationBlock,
+	)
+
+	err = n.waitForBlockHeight(ctx, confirmationBlock)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to wait for the confirmation block: [%v]",
+			err,
+		)
+	}
+
+	walletChainData, err := n.chain.GetWallet(event.WalletPublicKeyHash)
+	if err != nil {
+		return fmt.Errorf("cannot get on-chain data of wallet: [%v]", err)
+	}
+
+	if walletChainData.State != StateMovingFunds {
+		return fmt.Errorf(
+			"wallet is in state [%v] instead of [%v]",
+			walletChainData.State,
+			StateMovingFunds,
+		)
+	}
+
+	// Make sure the target wallets from the event are the ones the wallet
+	// committed to on-chain. The Bridge accepts only proofs of moving funds
+	// transactions transferring funds to the committed target wallets.
+	targetWalletsCommitmentHash :=
+		n.chain.ComputeMovingFundsTargetWalletsCommitmentHash(
+			event.TargetWallets,
+		)
+	if targetWalletsCommitmentHash !=
+		walletChainData.MovingFundsTargetWalletsCommitmentHash {
+		return fmt.Errorf(
+			"target wallets do not match the on-chain commitment",
+		)
+	}
+
+	walletMainUtxo, err := determineWalletMainUtxo(
+		event.WalletPublicKeyHash,
+		n.chain,
+		n.btcChain,
+		n.walletMainUtxoCache,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot determine wallet main UTXO: [%v]", err)
+	}
+
+	if walletMainUtxo == nil {
+		return fmt.Errorf("wallet does not have a main UTXO")
+	}
+
+	movingFundsParameters, err := n.chain.GetMovingFundsParameters()
+	if err != nil {
+		return fmt.Errorf("cannot get moving funds parameters: [%v]", err)
+	}
+
+	// The fee does not affect the virtual size of the transaction so,
+	// the transaction is assembled without fee to determine it first.
+	unsignedTx, err := assembleMovingFundsTransaction(
+		n.btcChain,
+		wallet.publicKey,
+		walletMainUtxo,
+		event.TargetWallets,
+		0,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot assemble moving funds transaction: [%v]",
+			err,
+		)
+	}
+
+	virtualSize, err := unsignedTx.EstimateVirtualSize()
+	if err != nil {
+		return fmt.Errorf(
+			"cannot estimate moving funds transaction virtual size: [%v]",
+			err,
+		)
+	}
+
+	fee := walletTransactionFee(
+		virtualSize,
+		int64(movingFundsParameters.TxMaxTotalFee),
+	)
+
+	if err := validateWalletTransactionFee(virtualSize, fee); err != nil {
+		return fmt.Errorf("cannot determine moving funds fee: [%v]", err)
+	}
+
+	unsignedTx, err = assembleMovingFundsTransaction(
+		n.btcChain,
+		wallet.publicKey,
+		walletMainUtxo,
+		event.TargetWallets,
+		fee,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot assemble moving funds transaction: [%v]",
+			err,
+		)
+	}
+
+	movingFundsLogger.Infof(
+		"assembled moving funds transaction transferring funds to "+
+			"[%v] target wallets with fee [%v]",
+		len(event.TargetWallets),
+		fee,
+	)
+
+	transactionExecutor := newWalletTransactionExecutor(
+		n.btcChain,
+		wallet,
+		executor,
+	)
+
+	// The signing must start no sooner than the current block. The
+	// confirmation block is a good synchronization point as it is common for
+	// all wallet signers observing the given commitment.
+	movingFundsTx, err := transactionExecutor.signTransaction(
+		ctx,
+		unsignedTx,
+		confirmationBlock,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot sign moving funds transaction: [%w]", err)
+	}
+
+	movingFundsLogger.Infof(
+		"signed moving funds transaction [%s]",
+		movingFundsTx.Hash().Hex(bitcoin.ReversedByteOrder),
+	)
+
+	err = transactionExecutor.broadcastTransaction(ctx, movingFundsTx)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot broadcast moving funds transaction: [%v]",
+			err,
+		)
+	}
+
+	movingFundsLogger.Infof(
+		"broadcast moving funds transaction [%s]",
+		movingFundsTx.Hash().Hex(bitcoin.ReversedByteOrder),
+	)
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package tbtc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/generator"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/net/local"
+)
+
+func TestAssembleMovingFundsTransaction(t *testing.T) {
+	walletPrivateKey, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	walletPublicKey := &walletPrivateKey.PublicKey
+	walletPublicKeyHash := bitcoin.PublicKeyHash(walletPublicKey)
+
+	walletScript, err := bitcoin.PayToWitnessPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bitcoinChain := newMockBitcoinChain()
+
+	mainUtxoTransaction := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: bitcoin.Hash{0x01},
+					OutputIndex:     0,
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{
+				Value:           1000000,
+				PublicKeyScript: walletScript,
+			},
+		},
+	}
+
+	err = bitcoinChain.addTransaction(mainUtxoTransaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walletMainUtxo := &bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{
+			TransactionHash: mainUtxoTransaction.Hash(),
+			OutputIndex:     0,
+		},
+		Value: 1000000,
+	}
+
+	var tests = map[string]struct {
+		walletMainUtxo        *bitcoin.UnspentTransactionOutput
+		targetWallets         [][20]byte
+		fee                   int64
+		expectedOutputsValues []int64
+		expectedError         error
+	}{
+		"single target wallet": {
+			walletMainUtxo:        walletMainUtxo,
+			targetWallets:         [][20]byte{{0xaa}},
+			fee:                   2000,
+			expectedOutputsValues: []int64{998000},
+		},
+		"three target wallets with remainder": {
+			walletMainUtxo: walletMainUtxo,
+			targetWallets:  [][20]byte{{0xaa}, {0xbb}, {0xcc}},
+			fee:            3000,
+			// The last target wallet receives the division remainder, i.e.
+			// 997000 % 3 = 1.
+			expectedOutputsValues: []int64{332333, 332333, 332334},
+		},
+		"missing main UTXO": {
+			walletMainUtxo: nil,
+			targetWallets:  [][20]byte{{0xaa}},
+			fee:            2000,
+			expectedError:  fmt.Errorf("wallet main UTXO is required"),
+		},
+		"no target wallets": {
+			walletMainUtxo: walletMainUtxo,
+			targetWallets:  [][20]byte{},
+			fee:            2000,
+			expectedError: fmt.Errorf(
+				"at least one target wallet is required",
+			),
+		},
+		"fee consuming whole main UTXO": {
+			walletMainUtxo: walletMainUtxo,
+			targetWallets:  [][20]byte{{0xaa}},
+			fee:            1000000,
+			expectedError: fmt.Errorf(
+				"value per target wallet [0] is not positive",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			builder, err := assembleMovingFundsTransaction(
+				bitcoinChain,
+				walletPublicKey,
+				test.walletMainUtxo,
+				test.targetWallets,
+				test.fee,
+			)
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected error\nexpected: %v\nactual:   %v\n",
+					test.expectedError,
+					err,
+				)
+			}
+			if test.expectedError != nil {
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sigHashes, err := builder.ComputeSignatureHashes()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signatures := make([]*bitcoin.SignatureContainer, len(sigHashes))
+			for i, sigHash := range sigHashes {
+				r, s, err := ecdsa.Sign(
+					rand.Reader,
+					walletPrivateKey,
+					sigHash.Bytes(),
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				signatures[i] = &bitcoin.SignatureContainer{
+					R:         r,
+					S:         s,
+					PublicKey: walletPublicKey,
+				}
+			}
+
+			transaction, err := builder.AddSignatures(signatures)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"inputs count",
+				1,
+				len(transaction.Inputs),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"outputs count",
+				len(test.expectedOutputsValues),
+				len(transaction.Outputs),
+			)
+
+			for i, expectedValue := range test.expectedOutputsValues {
+				testutils.AssertIntsEqual(
+					t,
+					fmt.Sprintf("value of output [%v]", i),
+					int(expectedValue),
+					int(transaction.Outputs[i].Value),
+				)
+			}
+
+			for i, targetWallet := range test.targetWallets {
+				targetWalletScript, err := bitcoin.PayToWitnessPublicKeyHash(
+					targetWallet,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				testutils.AssertBytesEqual(
+					t,
+					targetWalletScript,
+					transaction.Outputs[i].PublicKeyScript,
+				)
+			}
+		})
+	}
+}
+
+func TestHandleMovingFundsCommitmentSubmitted_TargetWalletsMismatch(t *testing.T) {
+	localChain := Connect()
+
+	signer := createMockSigner(t)
+	walletPublicKeyHash := bitcoin.PublicKeyHash(signer.wallet.publicKey)
+
+	localChain.setWallet(walletPublicKeyHash, &WalletChainData{
+		State: StateMovingFunds,
+		MovingFundsTargetWalletsCommitmentHash: localChain.ComputeMovingFundsTargetWalletsCommitmentHash(
+			[][20]byte{{0x01}, {0x02}},
+		),
+	})
+
+	node, err := newNode(
+		&GroupParameters{
+			GroupSize:       5,
+			GroupQuorum:     4,
+			HonestThreshold: 3,
+		},
+		localChain,
+		newMockBitcoinChain(),
+		local.Connect(),
+		createMockKeyStorePersistence(t, signer),
+		&mockPersistenceHandle{},
+		generator.StartScheduler(),
+		Config{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stopPreParamsGenerationOnCleanup(t, node)
+
+	// Use an already cancelled context to not wait for the confirmation block.
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	cancelCtx()
+
+	err = node.handleMovingFundsCommitmentSubmitted(
+		ctx,
+		signer.wallet,
+		&MovingFundsCommitmentSubmittedEvent{
+			WalletPublicKeyHash: walletPublicKeyHash,
+			TargetWallets:       [][20]byte{{0x01}, {0x03}},
+		},
+	)
+
+	expectedErr := fmt.Errorf(
+		"target wallets do not match the on-chain commitment",
+	)
+	if !reflect.DeepEqual(expectedErr, err) {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v\n",
+			expectedErr,
+			err,
+		)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"sync"
 
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/chain"
 
 	"go.uber.org/zap"
@@ -41,15 +42,33 @@ const (
 
 // node represents the current state of an ECDSA node.
 type node struct {
-	groupParameters *GroupParameters
-
 	chain          Chain
+	btcChain       bitcoin.Chain
 	netProvider    net.Provider
 	walletRegistry *walletRegistry
 	protocolLatch  *generator.ProtocolLatch
 
+	// protocolTiming determines the block durations of the DKG and signing
+	// protocol phases executed by this node.
+	protocolTiming *ProtocolTiming
+
 	dkgExecutor *dkgExecutor
 
+	// walletDispatcher makes sure wallets controlled by this node execute
+	// only one action at a time.
+	walletDispatcher *walletDispatcher
+
+	// attemptJournal records DKG and signing attempts executed by this node.
+	attemptJournal *attemptJournal
+
+	// presignaturePool holds presignatures computed by signers controlled
+	// by this node. It is nil if presignatures are not computed.
+	presignaturePool *presignaturePool
+
+	// walletMainUtxoCache holds main UTXOs of wallets determined while
+	// executing wallet actions.
+	walletMainUtxoCache *walletMainUtxoCache
+
 	signingExecutorsMutex sync.Mutex
 	// signingExecutors is the cache holding signing executors for specific wallets.
 	// The cache key is the uncompressed public key (with 04 prefix) of the wallet.
@@ -59,24 +78,60 @@ type node struct {
 func newNode(
 	groupParameters *GroupParameters,
 	chain Chain,
+	btcChain bitcoin.Chain,
 	netProvider net.Provider,
 	keyStorePersistance persistence.ProtectedHandle,
 	workPersistence persistence.BasicHandle,
 	scheduler *generator.Scheduler,
 	config Config,
 ) (*node, error) {
-	walletRegistry := newWalletRegistry(keyStorePersistance)
+	walletRegistry, err := newWalletRegistry(keyStorePersistance)
+	if err != nil {
+		return nil, fmt.Errorf("cannot set up wallet registry: [%v]", err)
+	}
 
 	latch := generator.NewProtocolLatch()
 	scheduler.RegisterProtocol(latch)
 
+	protocolTiming := config.ProtocolTiming.WithDefaults(MainnetProtocolTiming)
+
+	blockCounter, err := chain.BlockCounter()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get block counter: [%v]", err)
+	}
+
+	// Signers can join the first signing attempt of a wallet action until
+	// the end of the attempt's announcement phase.
+	walletDispatcher := newWalletDispatcher(
+		blockCounter.CurrentBlock,
+		protocolTiming.SigningAttemptAnnouncementDelayBlocks+
+			protocolTiming.SigningAttemptAnnouncementActiveBlocks,
+	)
+
 	node := &node{
-		groupParameters:  groupParameters,
 		chain:            chain,
+		btcChain:         btcChain,
 		netProvider:      netProvider,
 		walletRegistry:   walletRegistry,
 		protocolLatch:    latch,
-		signingExecutors: make(map[string]*signingExecutor),
+		protocolTiming:   &protocolTiming,
+		walletDispatcher: walletDispatcher,
+		attemptJournal: newAttemptJournal(
+			logger,
+			workPersistence,
+			config.AttemptJournalMaxSessions,
+			config.AttemptJournalRetention,
+		),
+		walletMainUtxoCache: newWalletMainUtxoCache(),
+		signingExecutors:    make(map[string]*signingExecutor),
+	}
+
+	if protocolTiming.PresigningIntervalBlocks != 0 {
+		node.presignaturePool = newPresignaturePool(
+			logger,
+			workPersistence,
+			config.PresignaturesPoolSize,
+		)
 	}
 
 	// Only the operator address is known at this point and can be pre-fetched.
@@ -87,10 +142,19 @@ func newNode(
 		return nil, fmt.Errorf("cannot get node's operator adress: [%v]", err)
 	}
 
+	preParamsGenerator, err := newPreParamsGenerator(config)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot set up pre-params generator: [%v]",
+			err,
+		)
+	}
+
 	// TODO: This chicken and egg problem should be solved when
 	// waitForBlockHeight becomes a part of BlockHeightWaiter interface.
-	node.dkgExecutor = newDkgExecutor(
-		node.groupParameters,
+	node.dkgExecutor, err = newDkgExecutor(
+		groupParameters,
+		node.protocolTiming,
 		node.operatorID,
 		operatorAddress,
 		chain,
@@ -99,9 +163,14 @@ func newNode(
 		latch,
 		config,
 		workPersistence,
+		preParamsGenerator,
 		scheduler,
 		node.waitForBlockHeight,
+		node.attemptJournal,
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	return node, nil
 }
@@ -205,13 +274,9 @@ func (n *node) getSigningExecutor(
 	// first signer.
 	wallet := signers[0].wallet
 
-	channelName := fmt.Sprintf(
-		"%s-%s",
-		ProtocolName,
-		hex.EncodeToString(walletPublicKeyBytes),
+	broadcastChannel, err := n.netProvider.BroadcastChannelFor(
+		walletChannelName(walletPublicKeyBytes),
 	)
-
-	broadcastChannel, err := n.netProvider.BroadcastChannelFor(channelName)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to get broadcast channel: [%v]", err)
 	}
@@ -223,6 +288,7 @@ func (n *node) getSigningExecutor(
 		wallet.signingGroupOperators,
 		n.chain.Signing(),
 	)
+	membershipValidator.SetPeerReputation(n.netProvider.PeerReputation())
 
 	err = broadcastChannel.SetFilter(membershipValidator.IsInGroup)
 	if err != nil {
@@ -250,11 +316,13 @@ func (n *node) getSigningExecutor(
 		signers,
 		broadcastChannel,
 		membershipValidator,
-		n.groupParameters,
 		n.protocolLatch,
 		blockCounter.CurrentBlock,
 		n.waitForBlockHeight,
 		signingAttemptsLimit,
+		n.protocolTiming,
+		n.attemptJournal,
+		n.presignaturePool,
 	)
 
 	n.signingExecutors[executorKey] = executor
@@ -262,6 +330,45 @@ func (n *node) getSigningExecutor(
 	return executor, true, nil
 }
 
+// archiveWallet archives the given wallet whose part is controlled by this
+// node. Signers of the wallet are archived in the wallet registry and the
+// wallet's signing executor is removed from the cache so the node no longer
+// participates in signing for the given wallet.
+func (n *node) archiveWallet(walletPublicKeyHash [20]byte) error {
+	wallet, ok := n.walletRegistry.getWalletByPublicKeyHash(walletPublicKeyHash)
+	if !ok {
+		return fmt.Errorf("node does not control signers of the wallet")
+	}
+
+	walletPublicKeyBytes, err := marshalPublicKey(wallet.publicKey)
+	if err != nil {
+		return fmt.Errorf("cannot marshal wallet public key: [%v]", err)
+	}
+
+	err = n.walletRegistry.archiveWallet(walletPublicKeyHash)
+	if err != nil {
+		return fmt.Errorf("cannot archive wallet: [%v]", err)
+	}
+
+	n.signingExecutorsMutex.Lock()
+	delete(n.signingExecutors, hex.EncodeToString(walletPublicKeyBytes))
+	n.signingExecutorsMutex.Unlock()
+
+	n.netProvider.CloseBroadcastChannel(walletChannelName(walletPublicKeyBytes))
+
+	return nil
+}
+
+// walletChannelName returns the name of the broadcast channel used for
+// signing by the wallet with the given marshaled public key.
+func walletChannelName(walletPublicKeyBytes []byte) string {
+	return fmt.Sprintf(
+		"%s-%s",
+		ProtocolName,
+		hex.EncodeToString(walletPublicKeyBytes),
+	)
+}
+
 // waitForBlockFn represents a function blocking the execution until the given
 // block height.
 type waitForBlockFn func(context.Context, uint64) error
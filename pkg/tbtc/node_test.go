@@ -35,6 +35,7 @@ func TestNode_GetSigningExecutor(t *testing.T) {
 	node, err := newNode(
 		groupParameters,
 		localChain,
+		newMockBitcoinChain(),
 		localProvider,
 		keyStorePersistence,
 		&mockPersistenceHandle{},
@@ -45,6 +46,8 @@ func TestNode_GetSigningExecutor(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	stopPreParamsGenerationOnCleanup(t, node)
+
 	walletPublicKey := signer.wallet.publicKey
 	walletPublicKeyBytes, err := marshalPublicKey(walletPublicKey)
 	if err != nil {
@@ -155,6 +158,11 @@ func createMockSigner(t *testing.T) *signer {
 		wallet: wallet{
 			publicKey:             privateKeyShare.PublicKey(),
 			signingGroupOperators: signingGroupOperators,
+			groupParameters: &GroupParameters{
+				GroupSize:       5,
+				GroupQuorum:     4,
+				HonestThreshold: 3,
+			},
 		},
 		signingGroupMemberIndex: group.MemberIndex(1),
 		privateKeyShare:         privateKeyShare,
@@ -205,3 +213,13 @@ func createMockKeyStorePersistence(
 		saved: descriptors,
 	}
 }
+
+// stopPreParamsGenerationOnCleanup stops the pre-parameters generation of
+// the given test node once the test completes. The node's scheduler keeps
+// generating pre-parameters in the background otherwise and slows down
+// tests executed later, especially the ones performing the actual signing.
+func stopPreParamsGenerationOnCleanup(t *testing.T, node *node) {
+	// Holding the protocol latch stops the computations of the scheduler
+	// the latch is registered in.
+	t.Cleanup(node.protocolLatch.Lock)
+}
@@ -0,0 +1,251 @@
+package tbtc
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ipfs/go-log/v2"
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa/signing"
+)
+
+// presignaturePoolDirectory is the directory of the tbtc work persistence
+// holding the presignature pool. Each pool entry is stored in a separate file.
+const presignaturePoolDirectory = "presignatures"
+
+// presignaturePoolEntry is the outcome of a single presigning session from
+// the perspective of one signer. All signers of the wallet record the entry,
+// including the ones that did not compute the presignature. This way, all
+// signers share the same view of the presignatures available for the wallet
+// and agree on the presignature that should be used for the next signing.
+type presignaturePoolEntry struct {
+	// Wallet is the hex-encoded public key of the wallet.
+	Wallet       string            `json:"wallet"`
+	MemberIndex  group.MemberIndex `json:"member_index"`
+	SessionBlock uint64            `json:"session_block"`
+	// MembersIndexes are the members that computed the presignature.
+	MembersIndexes []group.MemberIndex `json:"members_indexes"`
+	// Presignature is the marshaled presignature of the member. Empty if the
+	// member did not compute the presignature.
+	Presignature []byte `json:"presignature,omitempty"`
+}
+
+// name returns the name of the file holding the entry.
+func (ppe *presignaturePoolEntry) name() string {
+	return fmt.Sprintf("%s-%d-%d", ppe.Wallet, ppe.MemberIndex, ppe.SessionBlock)
+}
+
+// presignature returns the unmarshaled presignature of the member. Returns
+// nil if the member did not compute the presignature.
+func (ppe *presignaturePoolEntry) presignature() (*signing.Presignature, error) {
+	if len(ppe.Presignature) == 0 {
+		return nil, nil
+	}
+
+	presignature := &signing.Presignature{}
+	if err := presignature.Unmarshal(ppe.Presignature); err != nil {
+		return nil, err
+	}
+
+	return presignature, nil
+}
+
+// presignaturePool is a persistent pool of presignatures computed by signers
+// controlled by the node. The pool keeps at most capacity entries for each
+// signer; the oldest entries are removed once the limit is exceeded. Entries
+// are taken from the newest one and are removed from the persistence before
+// being returned so a presignature is never used twice, even if the node is
+// restarted in the middle of a signing.
+type presignaturePool struct {
+	logger      log.StandardLogger
+	persistence persistence.BasicHandle
+
+	capacity int
+
+	mutex sync.Mutex
+	// entries are keyed by the names of the files holding them.
+	entries map[string]*presignaturePoolEntry
+}
+
+func newPresignaturePool(
+	logger log.StandardLogger,
+	persistence persistence.BasicHandle,
+	capacity int,
+) *presignaturePool {
+	pp := &presignaturePool{
+		logger:      logger,
+		persistence: persistence,
+		capacity:    capacity,
+		entries:     make(map[string]*presignaturePoolEntry),
+	}
+
+	descriptorsChan, errorsChan := persistence.ReadAll()
+
+	// Two goroutines read from descriptors and errors channels the same
+	// way as the attempt journal does.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for descriptor := range descriptorsChan {
+			// Read only the files located in the pool directory.
+			if descriptor.Directory() != presignaturePoolDirectory {
+				continue
+			}
+
+			content, err := descriptor.Content()
+			if err != nil {
+				logger.Errorf(
+					"cannot read presignature pool file [%v]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				continue
+			}
+
+			entry := &presignaturePoolEntry{}
+			if err := json.Unmarshal(content, entry); err != nil {
+				logger.Errorf(
+					"cannot unmarshal presignature pool file [%v]: [%v]",
+					descriptor.Name(),
+					err,
+				)
+				continue
+			}
+
+			pp.entries[entry.name()] = entry
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for err := range errorsChan {
+			logger.Errorf("could not load presignature pool: [%v]", err)
+		}
+	}()
+
+	wg.Wait()
+
+	return pp
+}
+
+// isFull returns true if the pool holds the maximum number of entries of
+// the given member of the given wallet. Entries recorded for presignatures
+// computed by other members count as well so all signers of the wallet
+// using the same capacity stop computing presignatures at the same time.
+func (pp *presignaturePool) isFull(
+	wallet string,
+	memberIndex group.MemberIndex,
+) bool {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	return len(pp.memberEntriesLocked(wallet, memberIndex)) >= pp.capacity
+}
+
+// add persists the given entry in the pool and removes the oldest entries of
+// the same member if the pool capacity is exceeded.
+func (pp *presignaturePool) add(entry *presignaturePoolEntry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("cannot marshal presignature pool entry: [%w]", err)
+	}
+
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	err = pp.persistence.Save(content, presignaturePoolDirectory, entry.name())
+	if err != nil {
+		return fmt.Errorf("cannot save presignature pool entry: [%w]", err)
+	}
+
+	pp.entries[entry.name()] = entry
+
+	entries := pp.memberEntriesLocked(entry.Wallet, entry.MemberIndex)
+	for i := 0; i < len(entries)-pp.capacity; i++ {
+		if err := pp.deleteLocked(entries[i]); err != nil {
+			pp.logger.Errorf(
+				"could not delete presignature pool entry [%v]: [%v]",
+				entries[i].name(),
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// take removes the newest entry of the given member of the given wallet
+// whose presigning session started not later than the given block, and
+// returns it. Older entries remain in the pool. Returns nil if there is no
+// such entry or the entry could not be removed from the persistence.
+func (pp *presignaturePool) take(
+	wallet string,
+	memberIndex group.MemberIndex,
+	latestSessionBlock uint64,
+) *presignaturePoolEntry {
+	if pp == nil {
+		return nil
+	}
+
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	entries := pp.memberEntriesLocked(wallet, memberIndex)
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.SessionBlock > latestSessionBlock {
+			continue
+		}
+
+		// The entry must not be used if it cannot be removed from the
+		// persistence as it would be loaded again once the node restarts.
+		if err := pp.deleteLocked(entry); err != nil {
+			pp.logger.Errorf(
+				"could not delete presignature pool entry [%v]: [%v]",
+				entry.name(),
+				err,
+			)
+			return nil
+		}
+
+		return entry
+	}
+
+	return nil
+}
+
+// deleteLocked removes the given entry from the pool and the persistence.
+// Must be called with the mutex held.
+func (pp *presignaturePool) deleteLocked(entry *presignaturePoolEntry) error {
+	delete(pp.entries, entry.name())
+	return pp.persistence.Delete(presignaturePoolDirectory, entry.name())
+}
+
+// memberEntriesLocked returns entries of the given member of the given wallet
+// sorted from the oldest to the newest one. Must be called with the mutex
+// held.
+func (pp *presignaturePool) memberEntriesLocked(
+	wallet string,
+	memberIndex group.MemberIndex,
+) []*presignaturePoolEntry {
+	entries := make([]*presignaturePoolEntry, 0)
+	for _, entry := range pp.entries {
+		if entry.Wallet == wallet && entry.MemberIndex == memberIndex {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].SessionBlock < entries[j].SessionBlock
+	})
+
+	return entries
+}
@@ -0,0 +1,113 @@
+package tbtc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+func TestPresignaturePool(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	pool := newPresignaturePool(&testutils.MockLogger{}, persistenceHandle, 2)
+
+	newEntry := func(
+		memberIndex group.MemberIndex,
+		sessionBlock uint64,
+	) *presignaturePoolEntry {
+		return &presignaturePoolEntry{
+			Wallet:         "ff",
+			MemberIndex:    memberIndex,
+			SessionBlock:   sessionBlock,
+			MembersIndexes: []group.MemberIndex{1, 2},
+			Presignature:   []byte{byte(sessionBlock)},
+		}
+	}
+
+	for _, entry := range []*presignaturePoolEntry{
+		newEntry(1, 100),
+		newEntry(1, 200),
+		newEntry(2, 100),
+		newEntry(1, 300),
+	} {
+		if err := pool.add(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The oldest entry of member 1 exceeds the capacity and is removed.
+	testutils.AssertIntsEqual(
+		t,
+		"persisted entries count",
+		3,
+		len(persistenceHandle.saved),
+	)
+	for _, descriptor := range persistenceHandle.saved {
+		testutils.AssertStringsEqual(
+			t,
+			"persisted entry directory",
+			presignaturePoolDirectory,
+			descriptor.Directory(),
+		)
+	}
+
+	if !pool.isFull("ff", 1) {
+		t.Errorf("pool of member 1 should be full")
+	}
+	if pool.isFull("ff", 2) {
+		t.Errorf("pool of member 2 should not be full")
+	}
+
+	// The pool is read from the persistence once the node restarts.
+	restoredPool := newPresignaturePool(
+		&testutils.MockLogger{},
+		persistenceHandle,
+		2,
+	)
+
+	// The newest entry of the session that started before the given block
+	// is taken.
+	entry := restoredPool.take("ff", 1, 250)
+	if !reflect.DeepEqual(newEntry(1, 200), entry) {
+		t.Errorf(
+			"unexpected entry\nexpected: [%+v]\nactual:   [%+v]",
+			newEntry(1, 200),
+			entry,
+		)
+	}
+
+	// Taken entries are removed from the persistence.
+	testutils.AssertIntsEqual(
+		t,
+		"persisted entries count",
+		2,
+		len(persistenceHandle.saved),
+	)
+
+	if entry := restoredPool.take("ff", 1, 250); entry != nil {
+		t.Errorf("unexpected entry: [%+v]", entry)
+	}
+
+	entry = restoredPool.take("ff", 1, 300)
+	if !reflect.DeepEqual(newEntry(1, 300), entry) {
+		t.Errorf(
+			"unexpected entry\nexpected: [%+v]\nactual:   [%+v]",
+			newEntry(1, 300),
+			entry,
+		)
+	}
+
+	if entry := restoredPool.take("ee", 2, 300); entry != nil {
+		t.Errorf("unexpected entry of another wallet: [%+v]", entry)
+	}
+}
+
+func TestPresignaturePool_Nil(t *testing.T) {
+	var pool *presignaturePool
+
+	if entry := pool.take("ff", 1, 100); entry != nil {
+		t.Errorf("unexpected entry: [%+v]", entry)
+	}
+}
@@ -0,0 +1,525 @@
+package tbtc
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/protocol/announcer"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa/signing"
+)
+
+// presigningDoneReceiveBuffer is a buffer for messages received from the
+// broadcast channel needed when the presigning done's consumer is
+// temporarily too slow to handle them.
+const presigningDoneReceiveBuffer = 512
+
+// presigningDoneMessage is a message used to signal a successful presignature
+// calculation across all signing group members.
+type presigningDoneMessage struct {
+	senderID       group.MemberIndex
+	sessionBlock   uint64
+	membersIndexes []group.MemberIndex
+	publicNonce    []byte
+}
+
+func (pdm *presigningDoneMessage) Type() string {
+	return "tbtc/presigning_done_message"
+}
+
+// presigningDoneCheck is a component that collects presigning done messages
+// of the given presigning session. Messages are collected by all signers of
+// the wallet, including the ones that did not compute the presignature, so
+// all of them learn about the presignature computed in the session.
+type presigningDoneCheck struct {
+	broadcastChannel    net.BroadcastChannel
+	membershipValidator *group.MembershipValidator
+
+	doneMembersMutex sync.Mutex
+	doneMembers      map[group.MemberIndex]*presigningDoneMessage
+}
+
+func newPresigningDoneCheck(
+	broadcastChannel net.BroadcastChannel,
+	membershipValidator *group.MembershipValidator,
+) *presigningDoneCheck {
+	broadcastChannel.SetUnmarshaler(func() net.TaggedUnmarshaler {
+		return &presigningDoneMessage{}
+	})
+
+	return &presigningDoneCheck{
+		broadcastChannel:    broadcastChannel,
+		membershipValidator: membershipValidator,
+		doneMembers:         make(map[group.MemberIndex]*presigningDoneMessage),
+	}
+}
+
+// listen collects presigning done messages of the presigning session
+// starting at the given block until the ctx is done. Only one message can be
+// sent by the given signing group member.
+func (pdc *presigningDoneCheck) listen(ctx context.Context, sessionBlock uint64) {
+	messagesChan := make(chan net.Message, presigningDoneReceiveBuffer)
+	pdc.broadcastChannel.Recv(ctx, func(message net.Message) {
+		messagesChan <- message
+	})
+
+	go func() {
+		for {
+			select {
+			case netMessage := <-messagesChan:
+				doneMessage, ok := netMessage.Payload().(*presigningDoneMessage)
+				if !ok {
+					continue
+				}
+
+				if doneMessage.sessionBlock != sessionBlock {
+					continue
+				}
+
+				if !pdc.membershipValidator.IsValidMembership(
+					doneMessage.senderID,
+					netMessage.SenderPublicKey(),
+				) {
+					continue
+				}
+
+				pdc.doneMembersMutex.Lock()
+				if _, ok := pdc.doneMembers[doneMessage.senderID]; !ok {
+					pdc.doneMembers[doneMessage.senderID] = doneMessage
+				}
+				pdc.doneMembersMutex.Unlock()
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// signalDone broadcasts the presigning done check of the given member until
+// the ctx is done.
+func (pdc *presigningDoneCheck) signalDone(
+	ctx context.Context,
+	memberIndex group.MemberIndex,
+	sessionBlock uint64,
+	presignature *signing.Presignature,
+) error {
+	return pdc.broadcastChannel.Send(ctx, &presigningDoneMessage{
+		senderID:       memberIndex,
+		sessionBlock:   sessionBlock,
+		membersIndexes: presignature.MembersIndexes(),
+		publicNonce:    presignature.PublicNonce(),
+	}, net.BackoffRetransmissionStrategy)
+}
+
+// outcome returns the indexes of the members that computed the presignature
+// and the public nonce of the presignature. The presigning is considered
+// successful only if all members that computed the presignature sent
+// consistent done messages. Otherwise, the function returns an error.
+func (pdc *presigningDoneCheck) outcome() (
+	[]group.MemberIndex,
+	[]byte,
+	error,
+) {
+	pdc.doneMembersMutex.Lock()
+	defer pdc.doneMembersMutex.Unlock()
+
+	if len(pdc.doneMembers) == 0 {
+		return nil, nil, fmt.Errorf("no presigning done messages received")
+	}
+
+	var membersIndexes []group.MemberIndex
+	var publicNonce []byte
+
+	for _, doneMessage := range pdc.doneMembers {
+		if membersIndexes == nil {
+			membersIndexes = doneMessage.membersIndexes
+			publicNonce = doneMessage.publicNonce
+			continue
+		}
+
+		if !slices.Equal(membersIndexes, doneMessage.membersIndexes) ||
+			!bytes.Equal(publicNonce, doneMessage.publicNonce) {
+			return nil, nil, fmt.Errorf(
+				"not matching presigning done messages detected",
+			)
+		}
+	}
+
+	if len(pdc.doneMembers) != len(membersIndexes) {
+		return nil, nil, fmt.Errorf(
+			"received presigning done messages from [%v] members; "+
+				"expected [%v]",
+			len(pdc.doneMembers),
+			len(membersIndexes),
+		)
+	}
+
+	for _, memberIndex := range membersIndexes {
+		if _, ok := pdc.doneMembers[memberIndex]; !ok {
+			return nil, nil, fmt.Errorf(
+				"missing presigning done message of member [%v]",
+				memberIndex,
+			)
+		}
+	}
+
+	return membersIndexes, publicNonce, nil
+}
+
+// runPresigningSessions triggers a presigning session for each wallet
+// controlled by the node at every block being a multiple of the presigning
+// interval. Does nothing if the presigning interval is not set. The function
+// blocks until the ctx is done.
+func (n *node) runPresigningSessions(ctx context.Context) {
+	interval := n.protocolTiming.PresigningIntervalBlocks
+	if interval == 0 {
+		return
+	}
+
+	blockCounter, err := n.chain.BlockCounter()
+	if err != nil {
+		logger.Errorf("cannot get block counter: [%v]", err)
+		return
+	}
+
+	for block := range blockCounter.WatchBlocks(ctx) {
+		if block%interval != 0 {
+			continue
+		}
+
+		for _, walletPublicKeyHash := range n.walletRegistry.getWalletsPublicKeyHashes() {
+			wallet, ok := n.walletRegistry.getWalletByPublicKeyHash(
+				walletPublicKeyHash,
+			)
+			if !ok {
+				continue
+			}
+
+			executor, ok, err := n.getSigningExecutor(wallet.publicKey)
+			if err != nil {
+				logger.Errorf(
+					"cannot get signing executor for wallet [0x%x]: [%v]",
+					walletPublicKeyHash,
+					err,
+				)
+				continue
+			}
+			if !ok {
+				continue
+			}
+
+			go executor.presign(ctx, block)
+		}
+	}
+}
+
+// presign executes the presigning session starting at the given block.
+// Signers whose presignature pools are not full announce their readiness
+// and the honest threshold of ready members computes one presignature.
+// All signers of the wallet wait until the session ends and record its
+// outcome in their presignature pools. The session does not take the
+// signing executor lock so it neither blocks signing requested while it is
+// in progress nor depends on whether the given signer is signing at the
+// moment. That way, all signers of the wallet take part in the same
+// sessions.
+func (se *signingExecutor) presign(ctx context.Context, sessionBlock uint64) {
+	if se.presignaturePool == nil {
+		return
+	}
+
+	wallet := se.wallet()
+
+	walletPublicKeyBytes, err := marshalPublicKey(wallet.publicKey)
+	if err != nil {
+		logger.Errorf("cannot marshal wallet public key: [%v]", err)
+		return
+	}
+
+	walletKey := hex.EncodeToString(walletPublicKeyBytes)
+
+	presigningLogger := logger.With(
+		zap.String("wallet", fmt.Sprintf("0x%x", walletPublicKeyBytes)),
+		zap.Uint64("presigningSessionBlock", sessionBlock),
+	)
+
+	announcementStartBlock := sessionBlock +
+		se.protocolTiming.SigningAttemptAnnouncementDelayBlocks
+	announcementEndBlock := announcementStartBlock +
+		se.protocolTiming.SigningAttemptAnnouncementActiveBlocks
+	timeoutBlock := announcementEndBlock +
+		se.protocolTiming.SigningAttemptMaximumProtocolBlocks
+
+	// The session context is active until the timeout even if the protocol
+	// completed successfully earlier. This is needed to ensure all signers
+	// have a chance to receive presigningDoneMessage.
+	sessionCtx, cancelSessionCtx := withCancelOnBlock(
+		ctx,
+		timeoutBlock,
+		se.waitForBlockFn,
+	)
+	defer cancelSessionCtx()
+
+	doneCheck := newPresigningDoneCheck(
+		se.broadcastChannel,
+		se.membershipValidator,
+	)
+	doneCheck.listen(sessionCtx, sessionBlock)
+
+	presignatures := make(map[group.MemberIndex]*signing.Presignature)
+	presignaturesMutex := sync.Mutex{}
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(se.signers))
+
+	for _, currentSigner := range se.signers {
+		go func(signer *signer) {
+			defer wg.Done()
+
+			if se.presignaturePool.isFull(
+				walletKey,
+				signer.signingGroupMemberIndex,
+			) {
+				return
+			}
+
+			presignature, err := se.presignMember(
+				sessionCtx,
+				presigningLogger,
+				signer,
+				sessionBlock,
+				announcementStartBlock,
+				announcementEndBlock,
+			)
+			if err != nil {
+				presigningLogger.Warnf(
+					"[member:%v] presigning failed: [%v]",
+					signer.signingGroupMemberIndex,
+					err,
+				)
+				return
+			}
+			if presignature == nil {
+				return
+			}
+
+			presignaturesMutex.Lock()
+			presignatures[signer.signingGroupMemberIndex] = presignature
+			presignaturesMutex.Unlock()
+
+			err = doneCheck.signalDone(
+				sessionCtx,
+				signer.signingGroupMemberIndex,
+				sessionBlock,
+				presignature,
+			)
+			if err != nil {
+				presigningLogger.Warnf(
+					"[member:%v] cannot send presigning done signal: [%v]",
+					signer.signingGroupMemberIndex,
+					err,
+				)
+			}
+		}(currentSigner)
+	}
+
+	wg.Wait()
+
+	// Wait until the session ends to receive done messages of all members
+	// that computed the presignature.
+	<-sessionCtx.Done()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	membersIndexes, publicNonce, err := doneCheck.outcome()
+	if err != nil {
+		presigningLogger.Infof("no presignature computed: [%v]", err)
+		return
+	}
+
+	for _, signer := range se.signers {
+		entry := &presignaturePoolEntry{
+			Wallet:         walletKey,
+			MemberIndex:    signer.signingGroupMemberIndex,
+			SessionBlock:   sessionBlock,
+			MembersIndexes: membersIndexes,
+		}
+
+		if slices.Contains(membersIndexes, signer.signingGroupMemberIndex) {
+			presignature, ok := presignatures[signer.signingGroupMemberIndex]
+			if !ok || !bytes.Equal(presignature.PublicNonce(), publicNonce) {
+				// This should never happen as the member's own done
+				// message is a part of the outcome.
+				presigningLogger.Errorf(
+					"[member:%v] presignature does not match the "+
+						"presigning outcome",
+					signer.signingGroupMemberIndex,
+				)
+				continue
+			}
+
+			entry.Presignature, err = presignature.Marshal()
+			if err != nil {
+				presigningLogger.Errorf(
+					"[member:%v] cannot marshal presignature: [%v]",
+					signer.signingGroupMemberIndex,
+					err,
+				)
+				continue
+			}
+		}
+
+		if err := se.presignaturePool.add(entry); err != nil {
+			presigningLogger.Errorf(
+				"[member:%v] cannot add presignature to the pool: [%v]",
+				signer.signingGroupMemberIndex,
+				err,
+			)
+			continue
+		}
+	}
+
+	presigningLogger.Infof(
+		"presignature computed by members [%v]",
+		membersIndexes,
+	)
+}
+
+// presignMember executes the presigning session on behalf of the given
+// signer. Returns a nil presignature if not enough members were ready or the
+// signer was not selected to compute the presignature.
+func (se *signingExecutor) presignMember(
+	ctx context.Context,
+	presigningLogger *zap.SugaredLogger,
+	signer *signer,
+	sessionBlock uint64,
+	announcementStartBlock uint64,
+	announcementEndBlock uint64,
+) (*signing.Presignature, error) {
+	se.protocolLatch.Lock()
+	defer se.protocolLatch.Unlock()
+
+	wallet := signer.wallet
+
+	if err := se.waitForBlockFn(ctx, announcementStartBlock); err != nil {
+		return nil, fmt.Errorf(
+			"failed waiting for announcement start block: [%v]",
+			err,
+		)
+	}
+
+	announceCtx, _ := withCancelOnBlock(
+		ctx,
+		announcementEndBlock,
+		se.waitForBlockFn,
+	)
+
+	announcer := announcer.New(
+		fmt.Sprintf("%v-%v", ProtocolName, "presigning"),
+		se.broadcastChannel,
+		se.membershipValidator,
+	)
+
+	sessionID := fmt.Sprintf("presign-%v", sessionBlock)
+
+	readyMembersIndexes, err := announcer.Announce(
+		announceCtx,
+		signer.signingGroupMemberIndex,
+		sessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("announcement failed: [%v]", err)
+	}
+
+	if len(readyMembersIndexes) < wallet.groupParameters.HonestThreshold {
+		presigningLogger.Infof(
+			"[member:%v] only [%v] members ready to presign",
+			signer.signingGroupMemberIndex,
+			len(readyMembersIndexes),
+		)
+		return nil, nil
+	}
+
+	membersIndexes := selectPresigningMembers(
+		readyMembersIndexes,
+		wallet.groupParameters.HonestThreshold,
+		sessionBlock,
+	)
+	if !slices.Contains(membersIndexes, signer.signingGroupMemberIndex) {
+		return nil, nil
+	}
+
+	excludedMembersIndexes := make([]group.MemberIndex, 0)
+	for i := range wallet.signingGroupOperators {
+		memberIndex := group.MemberIndex(i + 1)
+		if !slices.Contains(membersIndexes, memberIndex) {
+			excludedMembersIndexes = append(excludedMembersIndexes, memberIndex)
+		}
+	}
+
+	presigningLogger.Infof(
+		"[member:%v] starting presigning protocol with members [%v]",
+		signer.signingGroupMemberIndex,
+		membersIndexes,
+	)
+
+	return signing.ExecutePresigning(
+		ctx,
+		presigningLogger,
+		sessionID,
+		signer.signingGroupMemberIndex,
+		signer.privateKeyShare,
+		wallet.groupSize(),
+		wallet.groupDishonestThreshold(),
+		excludedMembersIndexes,
+		se.broadcastChannel,
+		se.membershipValidator,
+	)
+}
+
+// selectPresigningMembers selects the given count of members computing
+// the presignature in the presigning session starting at the given block.
+// The members are selected randomly from the ready members, using the
+// session block as the seed, so all ready members select the same ones.
+// The returned indexes are sorted in ascending order.
+func selectPresigningMembers(
+	readyMembersIndexes []group.MemberIndex,
+	count int,
+	sessionBlock uint64,
+) []group.MemberIndex {
+	membersIndexes := make([]group.MemberIndex, len(readyMembersIndexes))
+	copy(membersIndexes, readyMembersIndexes)
+
+	sort.Slice(membersIndexes, func(i, j int) bool {
+		return membersIndexes[i] < membersIndexes[j]
+	})
+
+	// #nosec G404 (insecure random number source (rand))
+	// Shuffling does not require secure randomness.
+	rng := rand.New(rand.NewSource(int64(sessionBlock)))
+	rng.Shuffle(len(membersIndexes), func(i, j int) {
+		membersIndexes[i], membersIndexes[j] =
+			membersIndexes[j], membersIndexes[i]
+	})
+
+	if len(membersIndexes) > count {
+		membersIndexes = membersIndexes[:count]
+	}
+
+	sort.Slice(membersIndexes, func(i, j int) bool {
+		return membersIndexes[i] < membersIndexes[j]
+	})
+
+	return membersIndexes
+}
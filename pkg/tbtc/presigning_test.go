@@ -0,0 +1,239 @@
+package tbtc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+func TestSigningExecutor_SignWithPresignature(t *testing.T) {
+	executor := setupSigningExecutor(t)
+	executor.presignaturePool = newPresignaturePool(
+		&testutils.MockLogger{},
+		&mockPersistenceHandle{},
+		2,
+	)
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	currentBlock, err := executor.currentBlockFn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionBlock := currentBlock + 1
+
+	executor.presign(ctx, sessionBlock)
+
+	walletPublicKeyBytes, err := marshalPublicKey(executor.wallet().publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	walletKey := hex.EncodeToString(walletPublicKeyBytes)
+
+	// All signers record the presignature while only the honest threshold
+	// of them computes it.
+	var publicNonce []byte
+	presignaturesCount := 0
+	for _, signer := range executor.signers {
+		executor.presignaturePool.mutex.Lock()
+		entries := executor.presignaturePool.memberEntriesLocked(
+			walletKey,
+			signer.signingGroupMemberIndex,
+		)
+		executor.presignaturePool.mutex.Unlock()
+
+		testutils.AssertIntsEqual(
+			t,
+			fmt.Sprintf("entries count of member [%v]", signer.signingGroupMemberIndex),
+			1,
+			len(entries),
+		)
+
+		presignature, err := entries[0].presignature()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if presignature != nil {
+			presignaturesCount++
+			publicNonce = presignature.PublicNonce()
+		}
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"presignatures count",
+		executor.wallet().groupParameters.HonestThreshold,
+		presignaturesCount,
+	)
+
+	message := big.NewInt(100)
+	startBlock := sessionBlock +
+		executor.protocolTiming.signingAttemptMaximumBlocks() + 1
+
+	signature, _, err := executor.sign(ctx, message, startBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ecdsa.Verify(
+		executor.wallet().publicKey,
+		message.Bytes(),
+		signature.R,
+		signature.S,
+	) {
+		t.Errorf("invalid signature: [%+v]", signature)
+	}
+
+	// The signature uses the nonce of the presignature. The public nonce is
+	// an uncompressed point so the X coordinate takes 32 bytes after
+	// the prefix.
+	expectedR := new(big.Int).SetBytes(publicNonce[1:33])
+	if expectedR.Cmp(signature.R) != 0 {
+		t.Errorf("signature does not use the presignature")
+	}
+
+	for _, signer := range executor.signers {
+		if executor.presignaturePool.isFull(walletKey, signer.signingGroupMemberIndex) {
+			t.Errorf(
+				"presignature of member [%v] has not been taken",
+				signer.signingGroupMemberIndex,
+			)
+		}
+	}
+}
+
+func TestSelectPresigningMembers(t *testing.T) {
+	readyMembersIndexes := []group.MemberIndex{7, 1, 2, 3, 5, 6}
+
+	membersIndexes := selectPresigningMembers(readyMembersIndexes, 4, 100)
+
+	testutils.AssertIntsEqual(t, "members count", 4, len(membersIndexes))
+
+	for i, memberIndex := range membersIndexes {
+		if i > 0 && membersIndexes[i-1] >= memberIndex {
+			t.Errorf("members are not sorted: [%v]", membersIndexes)
+		}
+	}
+
+	// The order of ready members does not matter.
+	otherMembersIndexes := selectPresigningMembers(
+		[]group.MemberIndex{1, 2, 3, 5, 6, 7},
+		4,
+		100,
+	)
+	if !reflect.DeepEqual(membersIndexes, otherMembersIndexes) {
+		t.Errorf(
+			"unexpected members\nexpected: [%v]\nactual:   [%v]",
+			membersIndexes,
+			otherMembersIndexes,
+		)
+	}
+
+	// The input is not modified.
+	if !reflect.DeepEqual(
+		[]group.MemberIndex{7, 1, 2, 3, 5, 6},
+		readyMembersIndexes,
+	) {
+		t.Errorf("ready members modified: [%v]", readyMembersIndexes)
+	}
+}
+
+func TestPresigningDoneCheck_Outcome(t *testing.T) {
+	membersIndexes := []group.MemberIndex{1, 3, 4}
+	publicNonce := []byte{0x04, 0x01}
+
+	doneMessage := func(
+		senderID group.MemberIndex,
+		publicNonce []byte,
+	) *presigningDoneMessage {
+		return &presigningDoneMessage{
+			senderID:       senderID,
+			sessionBlock:   100,
+			membersIndexes: membersIndexes,
+			publicNonce:    publicNonce,
+		}
+	}
+
+	var tests = map[string]struct {
+		doneMessages   []*presigningDoneMessage
+		expectedResult bool
+	}{
+		"all members done": {
+			doneMessages: []*presigningDoneMessage{
+				doneMessage(1, publicNonce),
+				doneMessage(3, publicNonce),
+				doneMessage(4, publicNonce),
+			},
+			expectedResult: true,
+		},
+		"missing member": {
+			doneMessages: []*presigningDoneMessage{
+				doneMessage(1, publicNonce),
+				doneMessage(3, publicNonce),
+			},
+			expectedResult: false,
+		},
+		"unexpected member": {
+			doneMessages: []*presigningDoneMessage{
+				doneMessage(1, publicNonce),
+				doneMessage(2, publicNonce),
+				doneMessage(3, publicNonce),
+			},
+			expectedResult: false,
+		},
+		"not matching public nonce": {
+			doneMessages: []*presigningDoneMessage{
+				doneMessage(1, publicNonce),
+				doneMessage(3, publicNonce),
+				doneMessage(4, []byte{0x04, 0x02}),
+			},
+			expectedResult: false,
+		},
+		"no messages": {
+			expectedResult: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			doneCheck := &presigningDoneCheck{
+				doneMembers: make(map[group.MemberIndex]*presigningDoneMessage),
+			}
+			for _, message := range test.doneMessages {
+				doneCheck.doneMembers[message.senderID] = message
+			}
+
+			actualMembersIndexes, actualPublicNonce, err := doneCheck.outcome()
+
+			if !test.expectedResult {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(membersIndexes, actualMembersIndexes) {
+				t.Errorf(
+					"unexpected members\nexpected: [%v]\nactual:   [%v]",
+					membersIndexes,
+					actualMembersIndexes,
+				)
+			}
+
+			testutils.AssertBytesEqual(t, publicNonce, actualPublicNonce)
+		})
+	}
+}
@@ -0,0 +1,473 @@
+package tbtc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+const (
+	// redemptionRequestConfirmationBlocks determines the block length of the
+	// confirmation period that is preserved after a redemption request is
+	// observed. Only requests confirmed before the start of the redemption
+	// round are handled in that round.
+	redemptionRequestConfirmationBlocks = 20
+
+	// redemptionRoundBlocks determines the block length of redemption rounds.
+	// Redemption rounds start at blocks being multiples of this value. Once
+	// a round starts, the node gathers all confirmed pending redemption
+	// requests of the given wallet and handles them in a single redemption
+	// transaction whose signing starts at the round's start block. Rounds
+	// make all wallet signers agree on the requests and the signing start
+	// block regardless of the moment they observed the requests. They also
+	// allow to batch requests submitted in a short time window.
+	redemptionRoundBlocks = 50
+
+	// redemptionRequestsLookBackBlocks determines the block range the node
+	// looks back into while gathering redemption requests of the given wallet.
+	// The value should be greater than the on-chain redemption timeout as
+	// requests older than that are no longer handled by the wallet. The value
+	// of `43200` blocks is roughly 6 days assuming 12 seconds per block.
+	redemptionRequestsLookBackBlocks = 43200
+)
+
+// assembleRedemptionTransaction constructs an unsigned redemption Bitcoin
+// transaction.
+//
+// Regarding input arguments, the walletMainUtxo parameter is mandatory as
+// the wallet must have BTC to pay out redemptions. The redemptionRequests
+// slice must contain at least one element. The fee argument is the total
+// transaction fee that is distributed equally among all redemption requests.
+// If the fee cannot be divided equally, the remainder is incurred by the last
+// request. The fee share of each request must not exceed the request's
+// TxMaxFee though the fee is not validated against other system limitations.
+//
+// The resulting bitcoin.TransactionBuilder instance holds all the data
+// necessary to sign the transaction and obtain a bitcoin.Transaction instance
+// ready to be spread across the Bitcoin network.
+func assembleRedemptionTransaction(
+	bitcoinChain bitcoin.Chain,
+	walletPublicKey *ecdsa.PublicKey,
+	walletMainUtxo *bitcoin.UnspentTransactionOutput,
+	redemptionRequests []*RedemptionRequest,
+	fee int64,
+) (*bitcoin.TransactionBuilder, error) {
+	if walletMainUtxo == nil {
+		return nil, fmt.Errorf("wallet main UTXO is required")
+	}
+
+	if len(redemptionRequests) < 1 {
+		return nil, fmt.Errorf("at least one redemption request is required")
+	}
+
+	builder := bitcoin.NewTransactionBuilder(bitcoinChain)
+
+	err := builder.AddPublicKeyHashInput(walletMainUtxo)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot add input pointing to wallet main UTXO: [%v]",
+			err,
+		)
+	}
+
+	requestsCount := int64(len(redemptionRequests))
+	feeShares := make([]int64, requestsCount)
+	for i := range feeShares {
+		feeShares[i] = fee / requestsCount
+	}
+	feeShares[requestsCount-1] += fee % requestsCount
+
+	totalRedeemableAmount := int64(0)
+
+	for i, request := range redemptionRequests {
+		if feeShares[i] > int64(request.TxMaxFee) {
+			return nil, fmt.Errorf(
+				"fee share [%v] exceeds the maximum fee [%v] "+
+					"of redemption request [%v]",
+				feeShares[i],
+				request.TxMaxFee,
+				i,
+			)
+		}
+
+		redeemableAmount := int64(request.RequestedAmount) -
+			int64(request.TreasuryFee)
+		outputValue := redeemableAmount - feeShares[i]
+
+		if outputValue <= 0 {
+			return nil, fmt.Errorf(
+				"output value of redemption request [%v] is not positive",
+				i,
+			)
+		}
+
+		builder.AddOutput(&bitcoin.TransactionOutput{
+			Value:           outputValue,
+			PublicKeyScript: request.RedeemerOutputScript,
+		})
+
+		totalRedeemableAmount += redeemableAmount
+	}
+
+	// The change is computed as the difference between the main UTXO value
+	// and the total redeemable amount. The transaction fee is already
+	// subtracted from the redemption outputs so, it does not affect the
+	// change value.
+	changeValue := builder.TotalInputsValue() - totalRedeemableAmount
+	if changeValue < 0 {
+		return nil, fmt.Errorf(
+			"wallet main UTXO value [%v] is not enough to cover "+
+				"the total redeemable amount [%v]",
+			builder.TotalInputsValue(),
+			totalRedeemableAmount,
+		)
+	}
+
+	if changeValue > 0 {
+		walletPublicKeyHash := bitcoin.PublicKeyHash(walletPublicKey)
+		changeScript, err := bitcoin.PayToWitnessPublicKeyHash(walletPublicKeyHash)
+		if err != nil {
+			return nil, fmt.Errorf("cannot compute change script: [%v]", err)
+		}
+
+		builder.AddOutput(&bitcoin.TransactionOutput{
+			Value:           changeValue,
+			PublicKeyScript: changeScript,
+		})
+	}
+
+	return builder, nil
+}
+
+// redemptionAction is a wallet action handling pending redemption requests
+// of the wallet in the given redemption round. Redemption actions of the same
+// wallet and round waiting for execution or being executed are coalesced into
+// one as a single round handles all pending requests of the wallet.
+type redemptionAction struct {
+	node            *node
+	executingWallet wallet
+	// roundStartBlock is the start block of the redemption round handled
+	// by this action.
+	roundStartBlock uint64
+}
+
+func (ra *redemptionAction) execute(ctx context.Context) error {
+	return ra.node.handleRedemptionRound(
+		ctx,
+		ra.executingWallet,
+		ra.roundStartBlock,
+	)
+}
+
+func (ra *redemptionAction) wallet() wallet {
+	return ra.executingWallet
+}
+
+func (ra *redemptionAction) actionType() walletActionType {
+	return actionRedemption
+}
+
+func (ra *redemptionAction) startBlock() uint64 {
+	return ra.roundStartBlock
+}
+
+// coalesce merges the given redemption action into this one if both handle
+// the same redemption round. The round handles all pending requests of the
+// wallet confirmed before its start, including the ones that triggered the
+// given action. This holds even if this action is already being executed as
+// the pending requests are gathered from the chain state at the round start
+// block. Actions of different rounds are never coalesced so every
+// wallet signer handles the same rounds regardless of the state of its
+// action queue.
+func (ra *redemptionAction) coalesce(other walletAction) bool {
+	otherRedemption, ok := other.(*redemptionAction)
+	if !ok {
+		return false
+	}
+
+	return otherRedemption.roundStartBlock == ra.roundStartBlock
+}
+
+// redemptionRoundStartBlock determines the start block of the redemption
+// round handling the redemption request made at the given block. The round
+// is the first one starting once the request is confirmed. The start block
+// depends only on the request block so all wallet signers determine the same
+// start block regardless of the moment they handle the request.
+func redemptionRoundStartBlock(requestBlock uint64) uint64 {
+	earliestStartBlock := requestBlock + redemptionRequestConfirmationBlocks
+
+	// Round up to the nearest multiple of the round length.
+	return ((earliestStartBlock + redemptionRoundBlocks - 1) /
+		redemptionRoundBlocks) * redemptionRoundBlocks
+}
+
+// handleRedemptionRound handles the redemption round starting at the given
+// block for the given wallet controlled by this node. This function waits
+// for the round's start block, gathers all pending redemption requests of
+// that wallet confirmed before that block, assembles a redemption transaction
+// paying all of them out, signs it, and broadcasts it over the Bitcoin
+// network. This function blocks until the redemption transaction is
+// broadcast or an error occurs.
+func (n *node) handleRedemptionRound(
+	ctx context.Context,
+	wallet wallet,
+	roundStartBlock uint64,
+) error {
+	executor, ok, err := n.getSigningExecutor(wallet.publicKey)
+	if err != nil {
+		return fmt.Errorf("cannot get signing executor: [%v]", err)
+	}
+	if !ok {
+		return fmt.Errorf("node does not control signers of the wallet")
+	}
+
+	walletPublicKeyHash := bitcoin.PublicKeyHash(wallet.publicKey)
+
+	redemptionLogger := logger.With(
+		zap.String("wallet", fmt.Sprintf("0x%x", walletPublicKeyHash)),
+	)
+
+	redemptionLogger.Infof(
+		"waiting for block [%v] to start redemption round",
+		roundStartBlock,
+	)
+
+	err = n.waitForBlockHeight(ctx, roundStartBlock)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to wait for the round start block: [%v]",
+			err,
+		)
+	}
+
+	redemptionRequests, err := n.getPendingRedemptionRequests(
+		walletPublicKeyHash,
+		roundStartBlock,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot get pending redemption requests: [%v]", err)
+	}
+
+	if len(redemptionRequests) == 0 {
+		redemptionLogger.Infof("no pending redemption requests to handle")
+		return nil
+	}
+
+	walletMainUtxo, err := determineWalletMainUtxo(
+		walletPublicKeyHash,
+		n.chain,
+		n.btcChain,
+		n.walletMainUtxoCache,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot determine wallet main UTXO: [%v]", err)
+	}
+
+	if walletMainUtxo == nil {
+		return fmt.Errorf("wallet does not have a main UTXO")
+	}
+
+	redemptionParameters, err := n.chain.GetRedemptionParameters()
+	if err != nil {
+		return fmt.Errorf("cannot get redemption parameters: [%v]", err)
+	}
+
+	// The fee does not affect the virtual size of the transaction so,
+	// the transaction is assembled without fee to determine it first.
+	unsignedTx, err := assembleRedemptionTransaction(
+		n.btcChain,
+		wallet.publicKey,
+		walletMainUtxo,
+		redemptionRequests,
+		0,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot assemble redemption transaction: [%v]", err)
+	}
+
+	virtualSize, err := unsignedTx.EstimateVirtualSize()
+	if err != nil {
+		return fmt.Errorf(
+			"cannot estimate redemption transaction virtual size: [%v]",
+			err,
+		)
+	}
+
+	fee := redemptionTxFee(
+		virtualSize,
+		redemptionRequests,
+		redemptionParameters.TxMaxTotalFee,
+	)
+
+	if err := validateWalletTransactionFee(virtualSize, fee); err != nil {
+		return fmt.Errorf("cannot determine redemption fee: [%v]", err)
+	}
+
+	unsignedTx, err = assembleRedemptionTransaction(
+		n.btcChain,
+		wallet.publicKey,
+		walletMainUtxo,
+		redemptionRequests,
+		fee,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot assemble redemption transaction: [%v]", err)
+	}
+
+	redemptionLogger.Infof(
+		"assembled redemption transaction handling [%v] requests "+
+			"with fee [%v]",
+		len(redemptionRequests),
+		fee,
+	)
+
+	transactionExecutor := newWalletTransactionExecutor(
+		n.btcChain,
+		wallet,
+		executor,
+	)
+
+	// The signing must start no sooner than the current block. The round
+	// start block is a good synchronization point as it is common for all
+	// wallet signers handling the given redemption round.
+	redemptionTx, err := transactionExecutor.signTransaction(
+		ctx,
+		unsignedTx,
+		roundStartBlock,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot sign redemption transaction: [%w]", err)
+	}
+
+	redemptionLogger.Infof(
+		"signed redemption transaction [%s]",
+		redemptionTx.Hash().Hex(bitcoin.ReversedByteOrder),
+	)
+
+	err = transactionExecutor.broadcastTransaction(ctx, redemptionTx)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot broadcast redemption transaction: [%v]",
+			err,
+		)
+	}
+
+	redemptionLogger.Infof(
+		"broadcast redemption transaction [%s]",
+		redemptionTx.Hash().Hex(bitcoin.ReversedByteOrder),
+	)
+
+	return nil
+}
+
+// getPendingRedemptionRequests gets all redemption requests of the given
+// wallet handled by the redemption round starting at the given block. The
+// requests are gathered from redemption requested events confirmed before
+// the round start and are checked to be pending on-chain at the round start
+// block. Both depend only on the round start block so all wallet signers
+// gather the same requests. The returned requests are ordered by their
+// creation time.
+func (n *node) getPendingRedemptionRequests(
+	walletPublicKeyHash [20]byte,
+	roundStartBlock uint64,
+) ([]*RedemptionRequest, error) {
+	endBlock := roundStartBlock - redemptionRequestConfirmationBlocks
+
+	startBlock := uint64(0)
+	if endBlock > redemptionRequestsLookBackBlocks {
+		startBlock = endBlock - redemptionRequestsLookBackBlocks
+	}
+
+	events, err := n.chain.PastRedemptionRequestedEvents(
+		&RedemptionRequestedEventFilter{
+			StartBlock:          startBlock,
+			EndBlock:            &endBlock,
+			WalletPublicKeyHash: [][20]byte{walletPublicKeyHash},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get past redemption requested events: [%v]",
+			err,
+		)
+	}
+
+	redemptionRequests := make([]*RedemptionRequest, 0)
+	// The same redeemer output script can be used by subsequent requests
+	// once the previous request is handled. Make sure each script is
+	// checked only once as the on-chain pending request is unique per
+	// wallet and script.
+	processedScripts := make(map[string]bool)
+
+	for _, event := range events {
+		scriptKey := hex.EncodeToString(event.RedeemerOutputScript)
+		if processedScripts[scriptKey] {
+			continue
+		}
+		processedScripts[scriptKey] = true
+
+		request, found, err := n.chain.GetPendingRedemptionRequest(
+			walletPublicKeyHash,
+			event.RedeemerOutputScript,
+			roundStartBlock,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get pending redemption request for "+
+					"redeemer output script [0x%s]: [%v]",
+				scriptKey,
+				err,
+			)
+		}
+
+		if !found {
+			// The request was already handled or timed out.
+			continue
+		}
+
+		redemptionRequests = append(redemptionRequests, request)
+	}
+
+	return redemptionRequests, nil
+}
+
+// redemptionTxFee computes the total fee of the redemption transaction
+// having the given virtual size and handling the given redemption requests.
+// The fee is computed from the virtual size and clamped to the maximum fee
+// accepted by the Bridge. The fee is equally distributed among all requests
+// so, the maximum fee is determined by the lowest maximum fee of all given
+// requests and is further clamped to the given maximum total fee of the
+// redemption transaction. The fee is rounded down to a multiple of the
+// requests count so no request incurs a remainder exceeding its maximum fee.
+// The virtual size and the maximum fees do not depend on the wallet signer
+// so all of them assemble the same transaction.
+func redemptionTxFee(
+	virtualSize int64,
+	redemptionRequests []*RedemptionRequest,
+	txMaxTotalFee uint64,
+) int64 {
+	if len(redemptionRequests) == 0 {
+		return 0
+	}
+
+	minTxMaxFee := redemptionRequests[0].TxMaxFee
+	for _, request := range redemptionRequests[1:] {
+		if request.TxMaxFee < minTxMaxFee {
+			minTxMaxFee = request.TxMaxFee
+		}
+	}
+
+	maxFee := minTxMaxFee * uint64(len(redemptionRequests))
+	if maxFee > txMaxTotalFee {
+		maxFee = txMaxTotalFee
+	}
+
+	fee := walletTransactionFee(virtualSize, int64(maxFee))
+
+	return fee - fee%int64(len(redemptionRequests))
+}
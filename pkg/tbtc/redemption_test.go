@@ -0,0 +1,466 @@
+package tbtc
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+func TestAssembleRedemptionTransaction(t *testing.T) {
+	walletPrivateKey, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	walletPublicKey := &walletPrivateKey.PublicKey
+	walletPublicKeyHash := bitcoin.PublicKeyHash(walletPublicKey)
+
+	walletScript, err := bitcoin.PayToWitnessPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bitcoinChain := newMockBitcoinChain()
+
+	mainUtxoTransaction := &bitcoin.Transaction{
+		Version: 1,
+		Inputs: []*bitcoin.TransactionInput{
+			{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: bitcoin.Hash{0x01},
+					OutputIndex:     0,
+				},
+				Sequence: 0xffffffff,
+			},
+		},
+		Outputs: []*bitcoin.TransactionOutput{
+			{
+				Value:           1000000,
+				PublicKeyScript: walletScript,
+			},
+		},
+	}
+
+	err = bitcoinChain.addTransaction(mainUtxoTransaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walletMainUtxo := &bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{
+			TransactionHash: mainUtxoTransaction.Hash(),
+			OutputIndex:     0,
+		},
+		Value: 1000000,
+	}
+
+	p2pkhScript, err := bitcoin.PayToPublicKeyHash([20]byte{0xaa})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2wpkhScript, err := bitcoin.PayToWitnessPublicKeyHash([20]byte{0xbb})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = map[string]struct {
+		walletMainUtxo        *bitcoin.UnspentTransactionOutput
+		redemptionRequests    []*RedemptionRequest
+		fee                   int64
+		expectedOutputsValues []int64
+		expectedError         error
+	}{
+		"two requests with change": {
+			walletMainUtxo: walletMainUtxo,
+			redemptionRequests: []*RedemptionRequest{
+				{
+					RedeemerOutputScript: p2pkhScript,
+					RequestedAmount:      300000,
+					TreasuryFee:          1000,
+					TxMaxFee:             2000,
+				},
+				{
+					RedeemerOutputScript: p2wpkhScript,
+					RequestedAmount:      200000,
+					TreasuryFee:          500,
+					TxMaxFee:             2000,
+				},
+			},
+			fee: 3001,
+			// The second request incurs the fee remainder. The change is
+			// 1000000 - (300000 - 1000) - (200000 - 500).
+			expectedOutputsValues: []int64{297500, 197999, 501500},
+		},
+		"single request consuming whole main UTXO": {
+			walletMainUtxo: walletMainUtxo,
+			redemptionRequests: []*RedemptionRequest{
+				{
+					RedeemerOutputScript: p2pkhScript,
+					RequestedAmount:      1001000,
+					TreasuryFee:          1000,
+					TxMaxFee:             2000,
+				},
+			},
+			fee:                   1500,
+			expectedOutputsValues: []int64{998500},
+		},
+		"missing main UTXO": {
+			walletMainUtxo: nil,
+			redemptionRequests: []*RedemptionRequest{
+				{
+					RedeemerOutputScript: p2pkhScript,
+					RequestedAmount:      300000,
+					TxMaxFee:             2000,
+				},
+			},
+			fee:           1000,
+			expectedError: fmt.Errorf("wallet main UTXO is required"),
+		},
+		"no redemption requests": {
+			walletMainUtxo:     walletMainUtxo,
+			redemptionRequests: []*RedemptionRequest{},
+			fee:                1000,
+			expectedError: fmt.Errorf(
+				"at least one redemption request is required",
+			),
+		},
+		"fee share exceeding maximum fee": {
+			walletMainUtxo: walletMainUtxo,
+			redemptionRequests: []*RedemptionRequest{
+				{
+					RedeemerOutputScript: p2pkhScript,
+					RequestedAmount:      300000,
+					TxMaxFee:             1000,
+				},
+			},
+			fee: 1001,
+			expectedError: fmt.Errorf(
+				"fee share [1001] exceeds the maximum fee [1000] " +
+					"of redemption request [0]",
+			),
+		},
+		"main UTXO value too low": {
+			walletMainUtxo: walletMainUtxo,
+			redemptionRequests: []*RedemptionRequest{
+				{
+					RedeemerOutputScript: p2pkhScript,
+					RequestedAmount:      1000001,
+					TxMaxFee:             1000,
+				},
+			},
+			fee: 1000,
+			expectedError: fmt.Errorf(
+				"wallet main UTXO value [1000000] is not enough to cover " +
+					"the total redeemable amount [1000001]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			builder, err := assembleRedemptionTransaction(
+				bitcoinChain,
+				walletPublicKey,
+				test.walletMainUtxo,
+				test.redemptionRequests,
+				test.fee,
+			)
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected error\nexpected: %v\nactual:   %v\n",
+					test.expectedError,
+					err,
+				)
+			}
+			if test.expectedError != nil {
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sigHashes, err := builder.ComputeSignatureHashes()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signatures := make([]*bitcoin.SignatureContainer, len(sigHashes))
+			for i, sigHash := range sigHashes {
+				r, s, err := ecdsa.Sign(
+					rand.Reader,
+					walletPrivateKey,
+					sigHash.Bytes(),
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				signatures[i] = &bitcoin.SignatureContainer{
+					R:         r,
+					S:         s,
+					PublicKey: walletPublicKey,
+				}
+			}
+
+			transaction, err := builder.AddSignatures(signatures)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"inputs count",
+				1,
+				len(transaction.Inputs),
+			)
+			testutils.AssertIntsEqual(
+				t,
+				"outputs count",
+				len(test.expectedOutputsValues),
+				len(transaction.Outputs),
+			)
+
+			for i, expectedValue := range test.expectedOutputsValues {
+				testutils.AssertIntsEqual(
+					t,
+					fmt.Sprintf("value of output [%v]", i),
+					int(expectedValue),
+					int(transaction.Outputs[i].Value),
+				)
+			}
+
+			for i, request := range test.redemptionRequests {
+				testutils.AssertBytesEqual(
+					t,
+					request.RedeemerOutputScript,
+					transaction.Outputs[i].PublicKeyScript,
+				)
+			}
+
+			// If there is a change output, it must be the last one and be
+			// locked to the wallet's P2WPKH script.
+			if len(transaction.Outputs) > len(test.redemptionRequests) {
+				testutils.AssertBytesEqual(
+					t,
+					walletScript,
+					transaction.Outputs[len(transaction.Outputs)-1].PublicKeyScript,
+				)
+			}
+
+			totalOutputsValue := int64(0)
+			for _, output := range transaction.Outputs {
+				totalOutputsValue += output.Value
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"transaction fee",
+				int(test.fee),
+				int(test.walletMainUtxo.Value-totalOutputsValue),
+			)
+		})
+	}
+}
+
+func TestRedemptionTxFee(t *testing.T) {
+	txMaxTotalFee := uint64(10000)
+
+	// Enough requests to make the sum of their maximum fees exceed the
+	// maximum total fee of the redemption transaction.
+	manyRedemptionRequests := make([]*RedemptionRequest, 25)
+	for i := range manyRedemptionRequests {
+		manyRedemptionRequests[i] = &RedemptionRequest{TxMaxFee: 1000}
+	}
+
+	var tests = map[string]struct {
+		virtualSize        int64
+		redemptionRequests []*RedemptionRequest
+		expectedFee        int64
+	}{
+		"no requests": {
+			virtualSize:        100,
+			redemptionRequests: []*RedemptionRequest{},
+			expectedFee:        0,
+		},
+		"single request": {
+			virtualSize: 30,
+			redemptionRequests: []*RedemptionRequest{
+				{TxMaxFee: 1000},
+			},
+			expectedFee: 30 * transactionSatPerVByteFee,
+		},
+		"single request exceeding maximum fee": {
+			virtualSize: 100,
+			redemptionRequests: []*RedemptionRequest{
+				{TxMaxFee: 1000},
+			},
+			expectedFee: 1000,
+		},
+		"multiple requests": {
+			virtualSize: 50,
+			redemptionRequests: []*RedemptionRequest{
+				{TxMaxFee: 1000},
+				{TxMaxFee: 700},
+				{TxMaxFee: 1200},
+			},
+			// The fee is rounded down to a multiple of the requests count.
+			expectedFee: 50*transactionSatPerVByteFee -
+				50*transactionSatPerVByteFee%3,
+		},
+		"multiple requests exceeding maximum fee": {
+			virtualSize: 500,
+			redemptionRequests: []*RedemptionRequest{
+				{TxMaxFee: 1000},
+				{TxMaxFee: 700},
+				{TxMaxFee: 1200},
+			},
+			expectedFee: 2100,
+		},
+		"requests exceeding maximum total fee": {
+			virtualSize:        1000,
+			redemptionRequests: manyRedemptionRequests,
+			expectedFee:        int64(txMaxTotalFee),
+		},
+		"requests exceeding maximum total fee not divisible by count": {
+			virtualSize: 1000,
+			redemptionRequests: []*RedemptionRequest{
+				{TxMaxFee: 4000},
+				{TxMaxFee: 4000},
+				{TxMaxFee: 4000},
+			},
+			expectedFee: 9999,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			testutils.AssertIntsEqual(
+				t,
+				"redemption transaction fee",
+				int(test.expectedFee),
+				int(redemptionTxFee(
+					test.virtualSize,
+					test.redemptionRequests,
+					txMaxTotalFee,
+				)),
+			)
+		})
+	}
+}
+
+func TestRedemptionRoundStartBlock(t *testing.T) {
+	var tests = map[string]struct {
+		requestBlock       uint64
+		expectedStartBlock uint64
+	}{
+		"request confirmed before the round start": {
+			requestBlock:       1010,
+			expectedStartBlock: 1050,
+		},
+		"request confirmed at the round start": {
+			requestBlock:       1030,
+			expectedStartBlock: 1050,
+		},
+		"request confirmed right after the round start": {
+			requestBlock:       1031,
+			expectedStartBlock: 1100,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			testutils.AssertIntsEqual(
+				t,
+				"round start block",
+				int(test.expectedStartBlock),
+				int(redemptionRoundStartBlock(test.requestBlock)),
+			)
+		})
+	}
+}
+
+func TestRedemptionAction_Coalesce(t *testing.T) {
+	action := &redemptionAction{roundStartBlock: 1050}
+
+	if !action.coalesce(&redemptionAction{roundStartBlock: 1050}) {
+		t.Fatal("expected redemption action of the same round to be coalesced")
+	}
+
+	if action.coalesce(&redemptionAction{roundStartBlock: 1100}) {
+		t.Fatal("expected redemption action of another round not to be coalesced")
+	}
+	testutils.AssertIntsEqual(
+		t,
+		"round start block",
+		1050,
+		int(action.roundStartBlock),
+	)
+
+	if action.coalesce(&mockWalletAction{}) {
+		t.Fatal("expected other action not to be coalesced")
+	}
+}
+
+func TestNode_GetPendingRedemptionRequests(t *testing.T) {
+	walletPublicKeyHash := [20]byte{0x01}
+
+	localChain := Connect()
+
+	newEvent := func(script byte, blockNumber uint64) *RedemptionRequestedEvent {
+		return &RedemptionRequestedEvent{
+			WalletPublicKeyHash:  walletPublicKeyHash,
+			RedeemerOutputScript: bitcoin.Script{0x00, 0x14, script},
+			RequestedAmount:      100000,
+			TreasuryFee:          1000,
+			TxMaxFee:             2000,
+			BlockNumber:          blockNumber,
+		}
+	}
+
+	// Pending at the round start block.
+	pendingEvent := newEvent(0xaa, 1000)
+	localChain.addRedemptionRequest(pendingEvent, 0)
+	// Handled before the round start block.
+	handledEvent := newEvent(0xbb, 1010)
+	localChain.addRedemptionRequest(handledEvent, 1040)
+	// Handled after the round start block so, it is still pending at the
+	// round start block.
+	handledLaterEvent := newEvent(0xcc, 1020)
+	localChain.addRedemptionRequest(handledLaterEvent, 1060)
+	// Not confirmed before the round start block.
+	unconfirmedEvent := newEvent(0xdd, 1031)
+	localChain.addRedemptionRequest(unconfirmedEvent, 0)
+
+	node := &node{chain: localChain}
+
+	redemptionRequests, err := node.getPendingRedemptionRequests(
+		walletPublicKeyHash,
+		1050,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedScripts := []bitcoin.Script{
+		pendingEvent.RedeemerOutputScript,
+		handledLaterEvent.RedeemerOutputScript,
+	}
+	actualScripts := make([]bitcoin.Script, len(redemptionRequests))
+	for i, request := range redemptionRequests {
+		actualScripts[i] = request.RedeemerOutputScript
+	}
+
+	if !reflect.DeepEqual(expectedScripts, actualScripts) {
+		t.Errorf(
+			"unexpected redemption requests\nexpected: %v\nactual:   %v\n",
+			expectedScripts,
+			actualScripts,
+		)
+	}
+}
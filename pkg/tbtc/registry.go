@@ -4,10 +4,12 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"sync"
 
 	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 )
 
 // walletRegistry is the component that holds the data of the wallets managed
@@ -25,14 +27,27 @@ type walletRegistry struct {
 	// walletStorage is the handle to the wallet storage responsible for
 	// wallet persistence.
 	walletStorage *walletStorage
+
+	// quarantinedSignersCount is the number of signers that failed the
+	// validation when the registry was loaded from the wallet storage and
+	// were moved to the quarantine.
+	quarantinedSignersCount int
 }
 
-// newWalletRegistry creates a new instance of the walletRegistry.
-func newWalletRegistry(persistence persistence.ProtectedHandle) *walletRegistry {
+// newWalletRegistry creates a new instance of the walletRegistry. It returns
+// an error if content of any stored signer cannot be read, e.g. because it
+// cannot be decrypted with the configured storage password.
+func newWalletRegistry(
+	persistence persistence.ProtectedHandle,
+) (*walletRegistry, error) {
 	walletStorage := newWalletStorage(persistence)
 
 	// Pre-populate the wallet cache using the wallet storage.
-	walletCache := walletStorage.loadSigners()
+	walletCache, quarantinedSignersCount, err := walletStorage.loadSigners()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load signers: [%w]", err)
+	}
+
 	if len(walletCache) > 0 {
 		for walletStorageKey, signers := range walletCache {
 			logger.Infof(
@@ -47,9 +62,19 @@ func newWalletRegistry(persistence persistence.ProtectedHandle) *walletRegistry
 	}
 
 	return &walletRegistry{
-		walletCache:   walletCache,
-		walletStorage: walletStorage,
-	}
+		walletCache:             walletCache,
+		walletStorage:           walletStorage,
+		quarantinedSignersCount: quarantinedSignersCount,
+	}, nil
+}
+
+// getQuarantinedSignersCount returns the number of signers that failed the
+// validation when the registry was loaded and were moved to the quarantine.
+func (wr *walletRegistry) getQuarantinedSignersCount() int {
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+
+	return wr.quarantinedSignersCount
 }
 
 // registerSigner registers the given signer using in the walletRegistry.
@@ -82,6 +107,84 @@ func (wr *walletRegistry) getSigners(
 	return wr.walletCache[getWalletStorageKey(walletPublicKey)]
 }
 
+// getWalletByPublicKeyHash gets the given wallet by its 20-byte wallet
+// public key hash. Second boolean return value denotes whether the wallet
+// was found in the registry or not.
+func (wr *walletRegistry) getWalletByPublicKeyHash(
+	walletPublicKeyHash [20]byte,
+) (wallet, bool) {
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+
+	for _, signers := range wr.walletCache {
+		// All signers belong to one wallet. Take that wallet from the
+		// first signer.
+		wallet := signers[0].wallet
+		if bitcoin.PublicKeyHash(wallet.publicKey) == walletPublicKeyHash {
+			return wallet, true
+		}
+	}
+
+	return wallet{}, false
+}
+
+// getWalletsPublicKeyHashes returns the 20-byte public key hashes of all
+// wallets held by the walletRegistry.
+func (wr *walletRegistry) getWalletsPublicKeyHashes() [][20]byte {
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+
+	walletPublicKeyHashes := make([][20]byte, 0, len(wr.walletCache))
+	for _, signers := range wr.walletCache {
+		// All signers belong to one wallet. Take that wallet from the
+		// first signer.
+		wallet := signers[0].wallet
+		walletPublicKeyHashes = append(
+			walletPublicKeyHashes,
+			bitcoin.PublicKeyHash(wallet.publicKey),
+		)
+	}
+
+	return walletPublicKeyHashes
+}
+
+// archiveWallet archives the given wallet whose signers are held by the
+// walletRegistry. The signers are moved to the archive directory of the
+// underlying persistence layer and are removed from the in-memory cache.
+// This function should be used once the wallet is no longer expected to
+// sign anything, e.g. when it was closed or terminated.
+func (wr *walletRegistry) archiveWallet(walletPublicKeyHash [20]byte) error {
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+
+	var walletStorageKey string
+	for storageKey, signers := range wr.walletCache {
+		// All signers belong to one wallet. Take that wallet from the
+		// first signer.
+		wallet := signers[0].wallet
+		if bitcoin.PublicKeyHash(wallet.publicKey) == walletPublicKeyHash {
+			walletStorageKey = storageKey
+			break
+		}
+	}
+
+	if len(walletStorageKey) == 0 {
+		return fmt.Errorf(
+			"wallet [0x%x] not found in the registry",
+			walletPublicKeyHash,
+		)
+	}
+
+	err := wr.walletStorage.archiveWallet(walletStorageKey)
+	if err != nil {
+		return fmt.Errorf("cannot archive wallet in the storage: [%w]", err)
+	}
+
+	delete(wr.walletCache, walletStorageKey)
+
+	return nil
+}
+
 // walletStorage is the component that persists data of the wallets managed
 // by the given node using the underlying persistence layer. It should be
 // used directly only by the walletRegistry.
@@ -120,45 +223,168 @@ func (ws *walletStorage) saveSigner(signer *signer) error {
 	return nil
 }
 
+// archiveWallet archives all signers of the wallet identified by the given
+// storage key using the underlying persistence layer. It does not remove the
+// signers from any in-memory cache and should not be called from any other
+// place than walletRegistry.
+func (ws *walletStorage) archiveWallet(walletStorageKey string) error {
+	err := ws.persistence.Archive(walletStorageKey)
+	if err != nil {
+		return fmt.Errorf(
+			"could not archive wallet using the "+
+				"underlying persistence layer: [%w]",
+			err,
+		)
+	}
+
+	return nil
+}
+
 // loadSigners loads all signers stored using the underlying persistence layer.
+// Signers that cannot be loaded are skipped and the reason is logged. Files
+// of signers that are corrupted or fail the validation are moved to the
+// quarantine if the underlying persistence layer supports it. Along with the
+// loaded signers, the function returns the number of quarantined files.
+//
+// Files whose content cannot be read are never quarantined. Such a failure
+// is usually caused by a wrong storage password, e.g. after the password
+// was changed, and not by a corrupted file. In that case, the function
+// returns an error as the client must not run without its key shares.
 // This function should not be called from any other place than walletRegistry.
-func (ws *walletStorage) loadSigners() map[string][]*signer {
+func (ws *walletStorage) loadSigners() (map[string][]*signer, int, error) {
+	signersByWallet, errs := ws.readSigners()
+
+	var contentErrs []error
+	quarantinedCount := 0
+	for _, err := range errs {
+		logger.Errorf("could not load signer from disk: [%v]", err)
+
+		var contentErr *signerContentError
+		if errors.As(err, &contentErr) {
+			contentErrs = append(contentErrs, err)
+			continue
+		}
+
+		var fileErr *signerFileError
+		if errors.As(err, &fileErr) && ws.quarantine(fileErr) {
+			quarantinedCount++
+		}
+	}
+
+	if len(contentErrs) > 0 {
+		return nil, quarantinedCount, fmt.Errorf(
+			"content of [%v] signer files could not be read; "+
+				"make sure the storage password is correct; "+
+				"first error: [%w]",
+			len(contentErrs),
+			contentErrs[0],
+		)
+	}
+
+	return signersByWallet, quarantinedCount, nil
+}
+
+// quarantiner is implemented by persistence handles able to move files that
+// failed validation out of the directories read by the client.
+type quarantiner interface {
+	Quarantine(directory string, name string) error
+}
+
+// quarantine moves the signer file described by the given error to the
+// quarantine if the underlying persistence layer supports it. Returns true
+// if the file was moved.
+func (ws *walletStorage) quarantine(fileErr *signerFileError) bool {
+	quarantiner, ok := ws.persistence.(quarantiner)
+	if !ok {
+		return false
+	}
+
+	if err := quarantiner.Quarantine(fileErr.directory, fileErr.name); err != nil {
+		logger.Errorf(
+			"could not quarantine signer file [%v] in directory [%v]: [%v]",
+			fileErr.name,
+			fileErr.directory,
+			err,
+		)
+		return false
+	}
+
+	logger.Warnf(
+		"signer file [%v] in directory [%v] moved to quarantine",
+		fileErr.name,
+		fileErr.directory,
+	)
+
+	return true
+}
+
+// signerFileError describes a stored signer file that could not be loaded.
+type signerFileError struct {
+	directory string
+	name      string
+	err       error
+}
+
+func (sfe *signerFileError) Error() string {
+	return sfe.err.Error()
+}
+
+func (sfe *signerFileError) Unwrap() error {
+	return sfe.err
+}
+
+// signerContentError describes a stored signer file whose content could not
+// be read, e.g. because it could not be decrypted. The file itself is not
+// necessarily corrupted so it must not be quarantined.
+type signerContentError struct {
+	err error
+}
+
+func (sce *signerContentError) Error() string {
+	return sce.err.Error()
+}
+
+func (sce *signerContentError) Unwrap() error {
+	return sce.err
+}
+
+// readSigners reads all signers stored using the underlying persistence
+// layer and validates them. Along with valid signers grouped by wallet
+// storage keys, the function returns errors that occurred for signers that
+// could not be read. Errors related to particular signer files are of the
+// *signerFileError type.
+func (ws *walletStorage) readSigners() (map[string][]*signer, []error) {
 	signersByWallet := make(map[string][]*signer)
+	var errs []error
 
 	descriptorsChan, errorsChan := ws.persistence.ReadAll()
 
 	// Two goroutines read from descriptors and errors channels and either
-	// add the signer to the result map or outputs a log error.
+	// add the signer to the result map or collect the error.
 	// The reason for using two goroutines at the same time - one for
 	// descriptors and one for errors - is that channels do not have to be
 	// buffered, and we do not know in what order the information is written to
 	// channels.
+	var errsMutex sync.Mutex
+	appendErr := func(err error) {
+		errsMutex.Lock()
+		defer errsMutex.Unlock()
+
+		errs = append(errs, err)
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(2)
 
 	go func() {
 		for descriptor := range descriptorsChan {
-			content, err := descriptor.Content()
+			signer, err := readSigner(descriptor)
 			if err != nil {
-				logger.Errorf(
-					"could not get content from file [%v] "+
-						"in directory [%v]: [%v]",
-					descriptor.Name(),
-					descriptor.Directory(),
-					err,
-				)
-				continue
-			}
-
-			signer := &signer{}
-			if err := signer.Unmarshal(content); err != nil {
-				logger.Errorf(
-					"could not unmarshal signer from file [%v] "+
-						"in directory [%v]: [%v]",
-					descriptor.Name(),
-					descriptor.Directory(),
-					err,
-				)
+				appendErr(&signerFileError{
+					directory: descriptor.Directory(),
+					name:      descriptor.Name(),
+					err:       err,
+				})
 				continue
 			}
 
@@ -175,10 +401,7 @@ func (ws *walletStorage) loadSigners() map[string][]*signer {
 
 	go func() {
 		for err := range errorsChan {
-			logger.Errorf(
-				"could not load signer from disk: [%v]",
-				err,
-			)
+			appendErr(err)
 		}
 
 		wg.Done()
@@ -186,7 +409,45 @@ func (ws *walletStorage) loadSigners() map[string][]*signer {
 
 	wg.Wait()
 
-	return signersByWallet
+	return signersByWallet, errs
+}
+
+// readSigner reads the signer from the given descriptor and validates it.
+func readSigner(descriptor persistence.DataDescriptor) (*signer, error) {
+	content, err := descriptor.Content()
+	if err != nil {
+		return nil, &signerContentError{
+			err: fmt.Errorf(
+				"could not get content from file [%v] "+
+					"in directory [%v]: [%w]",
+				descriptor.Name(),
+				descriptor.Directory(),
+				err,
+			),
+		}
+	}
+
+	signer := &signer{}
+	if err := signer.Unmarshal(content); err != nil {
+		return nil, fmt.Errorf(
+			"could not unmarshal signer from file [%v] "+
+				"in directory [%v]: [%w]",
+			descriptor.Name(),
+			descriptor.Directory(),
+			err,
+		)
+	}
+
+	if err := signer.validate(); err != nil {
+		return nil, fmt.Errorf(
+			"invalid signer in file [%v] in directory [%v]: [%w]",
+			descriptor.Name(),
+			descriptor.Directory(),
+			err,
+		)
+	}
+
+	return signer, nil
 }
 
 // getWalletStorageKey compute the wallet storage key that is used to identify
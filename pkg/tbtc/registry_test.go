@@ -1,23 +1,30 @@
 package tbtc
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
 )
 
 func TestWalletRegistry_RegisterSigner(t *testing.T) {
 	persistenceHandle := &mockPersistenceHandle{}
 
-	walletRegistry := newWalletRegistry(persistenceHandle)
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	signer := createMockSigner(t)
 
 	walletStorageKey := getWalletStorageKey(signer.wallet.publicKey)
 
-	err := walletRegistry.registerSigner(signer)
+	err = walletRegistry.registerSigner(signer)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -51,11 +58,14 @@ func TestWalletRegistry_RegisterSigner(t *testing.T) {
 func TestWalletRegistry_GetSigners(t *testing.T) {
 	persistenceHandle := &mockPersistenceHandle{}
 
-	walletRegistry := newWalletRegistry(persistenceHandle)
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	signer := createMockSigner(t)
 
-	err := walletRegistry.registerSigner(signer)
+	err = walletRegistry.registerSigner(signer)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -74,6 +84,128 @@ func TestWalletRegistry_GetSigners(t *testing.T) {
 	}
 }
 
+func TestWalletRegistry_GetWalletByPublicKeyHash(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := createMockSigner(t)
+
+	err = walletRegistry.registerSigner(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walletPublicKeyHash := bitcoin.PublicKeyHash(signer.wallet.publicKey)
+
+	wallet, ok := walletRegistry.getWalletByPublicKeyHash(walletPublicKeyHash)
+	if !ok {
+		t.Fatal("wallet should be found")
+	}
+
+	if !reflect.DeepEqual(signer.wallet, wallet) {
+		t.Errorf("fetched wallet differs from the original one")
+	}
+
+	_, ok = walletRegistry.getWalletByPublicKeyHash([20]byte{0x01})
+	if ok {
+		t.Errorf("wallet should not be found")
+	}
+}
+
+func TestWalletRegistry_GetWalletsPublicKeyHashes(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"wallets count",
+		0,
+		len(walletRegistry.getWalletsPublicKeyHashes()),
+	)
+
+	signer := createMockSigner(t)
+
+	err = walletRegistry.registerSigner(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedWalletPublicKeyHashes := [][20]byte{
+		bitcoin.PublicKeyHash(signer.wallet.publicKey),
+	}
+	walletPublicKeyHashes := walletRegistry.getWalletsPublicKeyHashes()
+	if !reflect.DeepEqual(expectedWalletPublicKeyHashes, walletPublicKeyHashes) {
+		t.Errorf(
+			"unexpected wallet public key hashes\nexpected: %v\nactual:   %v\n",
+			expectedWalletPublicKeyHashes,
+			walletPublicKeyHashes,
+		)
+	}
+}
+
+func TestWalletRegistry_ArchiveWallet(t *testing.T) {
+	persistenceHandle := &mockPersistenceHandle{}
+
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := createMockSigner(t)
+
+	err = walletRegistry.registerSigner(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walletPublicKeyHash := bitcoin.PublicKeyHash(signer.wallet.publicKey)
+
+	err = walletRegistry.archiveWallet(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"registered wallets count",
+		0,
+		len(walletRegistry.walletCache),
+	)
+
+	testutils.AssertStringsEqual(
+		t,
+		"archived directories",
+		fmt.Sprintf("%v", []string{getWalletStorageKey(signer.wallet.publicKey)}),
+		fmt.Sprintf("%v", persistenceHandle.archived),
+	)
+
+	_, ok := walletRegistry.getWalletByPublicKeyHash(walletPublicKeyHash)
+	if ok {
+		t.Errorf("archived wallet should not be found")
+	}
+
+	err = walletRegistry.archiveWallet(walletPublicKeyHash)
+	expectedErr := fmt.Errorf(
+		"wallet [0x%x] not found in the registry",
+		walletPublicKeyHash,
+	)
+	if !reflect.DeepEqual(expectedErr, err) {
+		t.Errorf(
+			"unexpected error\nexpected: %v\nactual:   %v\n",
+			expectedErr,
+			err,
+		)
+	}
+}
+
 func TestWalletRegistry_PrePopulateWalletCache(t *testing.T) {
 	signer := createMockSigner(t)
 	signerBytes, err := signer.Marshal()
@@ -94,7 +226,10 @@ func TestWalletRegistry_PrePopulateWalletCache(t *testing.T) {
 	}
 
 	// Cache pre-population happens within newWalletRegistry.
-	walletRegistry := newWalletRegistry(persistenceHandle)
+	walletRegistry, err := newWalletRegistry(persistenceHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	testutils.AssertIntsEqual(
 		t,
@@ -156,7 +291,10 @@ func TestWalletStorage_LoadSigners(t *testing.T) {
 
 	walletStorage := newWalletStorage(persistenceHandle)
 
-	signersByWallet := walletStorage.loadSigners()
+	signersByWallet, quarantinedCount, err := walletStorage.loadSigners()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	testutils.AssertIntsEqual(
 		t,
@@ -175,10 +313,133 @@ func TestWalletStorage_LoadSigners(t *testing.T) {
 	if !reflect.DeepEqual(signer, signersByWallet[walletStorageKey][0]) {
 		t.Errorf("loaded wallet signer differs from the original one")
 	}
+
+	testutils.AssertIntsEqual(t, "quarantined signers count", 0, quarantinedCount)
+}
+
+func TestWalletStorage_LoadSigners_Quarantine(t *testing.T) {
+	signer := createMockSigner(t)
+	signerBytes, err := signer.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Make the signer inconsistent with its group.
+	invalidSigner := createMockSigner(t)
+	invalidSigner.signingGroupMemberIndex = group.MemberIndex(
+		len(invalidSigner.wallet.signingGroupOperators) + 1,
+	)
+	invalidSignerBytes, err := invalidSigner.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walletStorageKey := getWalletStorageKey(signer.wallet.publicKey)
+
+	persistenceHandle := &mockQuarantinablePersistenceHandle{
+		mockPersistenceHandle: mockPersistenceHandle{
+			saved: []persistence.DataDescriptor{
+				&mockDescriptor{
+					name:      "membership_1",
+					directory: "wallet_1",
+					content:   signerBytes,
+				},
+				&mockDescriptor{
+					name:      "membership_2",
+					directory: "wallet_1",
+					content:   invalidSignerBytes,
+				},
+				&mockDescriptor{
+					name:      "membership_3",
+					directory: "wallet_1",
+					content:   []byte{0x01, 0x02},
+				},
+			},
+		},
+	}
+
+	walletStorage := newWalletStorage(persistenceHandle)
+
+	signersByWallet, quarantinedCount, err := walletStorage.loadSigners()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"loaded wallet signers count",
+		1,
+		len(signersByWallet[walletStorageKey]),
+	)
+	testutils.AssertIntsEqual(t, "quarantined signers count", 2, quarantinedCount)
+
+	sort.Strings(persistenceHandle.quarantined)
+	expectedQuarantined := []string{
+		"wallet_1/membership_2",
+		"wallet_1/membership_3",
+	}
+	if !reflect.DeepEqual(expectedQuarantined, persistenceHandle.quarantined) {
+		t.Errorf(
+			"unexpected quarantined files\nexpected: %v\nactual:   %v",
+			expectedQuarantined,
+			persistenceHandle.quarantined,
+		)
+	}
+}
+
+func TestWalletStorage_LoadSigners_UnreadableContent(t *testing.T) {
+	signer := createMockSigner(t)
+	signerBytes, err := signer.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	persistenceHandle := &mockQuarantinablePersistenceHandle{
+		mockPersistenceHandle: mockPersistenceHandle{
+			saved: []persistence.DataDescriptor{
+				&mockDescriptor{
+					name:      "membership_1",
+					directory: "wallet_1",
+					content:   signerBytes,
+				},
+				&mockDescriptor{
+					name:       "membership_2",
+					directory:  "wallet_1",
+					contentErr: fmt.Errorf("cannot decrypt"),
+				},
+				&mockDescriptor{
+					name:      "membership_3",
+					directory: "wallet_1",
+					content:   []byte{0x01, 0x02},
+				},
+			},
+		},
+	}
+
+	walletStorage := newWalletStorage(persistenceHandle)
+
+	_, quarantinedCount, err := walletStorage.loadSigners()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	// Only the corrupted file is quarantined; the one that could not be
+	// decrypted is left in place.
+	testutils.AssertIntsEqual(t, "quarantined signers count", 1, quarantinedCount)
+
+	expectedQuarantined := []string{"wallet_1/membership_3"}
+	if !reflect.DeepEqual(expectedQuarantined, persistenceHandle.quarantined) {
+		t.Errorf(
+			"unexpected quarantined files\nexpected: %v\nactual:   %v",
+			expectedQuarantined,
+			persistenceHandle.quarantined,
+		)
+	}
 }
 
 type mockPersistenceHandle struct {
-	saved []persistence.DataDescriptor
+	saved    []persistence.DataDescriptor
+	archived []string
 }
 
 func (mph *mockPersistenceHandle) Save(
@@ -186,11 +447,21 @@ func (mph *mockPersistenceHandle) Save(
 	directory string,
 	name string,
 ) error {
-	mph.saved = append(mph.saved, &mockDescriptor{
+	descriptor := &mockDescriptor{
 		name:      name,
 		directory: directory,
 		content:   data,
-	})
+	}
+
+	// Saving a file with the same name overwrites it.
+	for i, saved := range mph.saved {
+		if saved.Directory() == directory && saved.Name() == name {
+			mph.saved[i] = descriptor
+			return nil
+		}
+	}
+
+	mph.saved = append(mph.saved, descriptor)
 
 	return nil
 }
@@ -221,17 +492,41 @@ func (mph *mockPersistenceHandle) ReadAll() (
 }
 
 func (mph *mockPersistenceHandle) Archive(directory string) error {
-	panic("not implemented")
+	mph.archived = append(mph.archived, directory)
+
+	return nil
 }
 
 func (mph *mockPersistenceHandle) Delete(directory string, name string) error {
-	panic("not implemented")
+	for i, saved := range mph.saved {
+		if saved.Directory() == directory && saved.Name() == name {
+			mph.saved = append(mph.saved[:i], mph.saved[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+type mockQuarantinablePersistenceHandle struct {
+	mockPersistenceHandle
+	quarantined []string
+}
+
+func (mqph *mockQuarantinablePersistenceHandle) Quarantine(
+	directory string,
+	name string,
+) error {
+	mqph.quarantined = append(mqph.quarantined, directory+"/"+name)
+
+	return nil
 }
 
 type mockDescriptor struct {
-	name      string
-	directory string
-	content   []byte
+	name       string
+	directory  string
+	content    []byte
+	contentErr error
 }
 
 func (md *mockDescriptor) Name() string {
@@ -243,5 +538,9 @@ func (md *mockDescriptor) Directory() string {
 }
 
 func (md *mockDescriptor) Content() ([]byte, error) {
+	if md.contentErr != nil {
+		return nil, md.contentErr
+	}
+
 	return md.content, nil
 }
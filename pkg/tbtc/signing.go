@@ -2,11 +2,13 @@ package tbtc
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"math/big"
 	"strings"
 	"sync"
 
+	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/generator"
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/protocol/announcer"
@@ -17,23 +19,6 @@ import (
 	"golang.org/x/sync/semaphore"
 )
 
-const (
-	// signingBatchInterludeBlocks determines the block duration of the
-	// interlude preserved between subsequent signings in a signing batch.
-	// If the signing of the previous message completed at block X, the signing
-	// of the next message starts at `X + signingBatchInterludeBlocks`.
-	// This is the additional time signers have to realize that the signing is
-	// done by receiving the signingDoneMessage. Note that the end block of the
-	// previous signing used to establish the start block of the next signing
-	// comes from signingDoneMessage received and there is no guarantee all
-	// signing group members received signingDoneMessage before the highest
-	// endBlock is reached on the chain. The interlude is an additional time for
-	// the broadcast channel to spread information about signing successfully
-	// completed by the slowest signing group member (the one who sends the
-	// signingDoneMessage as the last one).
-	signingBatchInterludeBlocks = 2
-)
-
 // errSigningExecutorBusy is an error returned when the signing executor
 // cannot execute the requested signature due to an ongoing signing.
 var errSigningExecutorBusy = fmt.Errorf("signing executor is busy")
@@ -46,7 +31,6 @@ type signingExecutor struct {
 	signers             []*signer
 	broadcastChannel    net.BroadcastChannel
 	membershipValidator *group.MembershipValidator
-	groupParameters     *GroupParameters
 	protocolLatch       *generator.ProtocolLatch
 
 	// currentBlockFn is a function used to get the current block.
@@ -58,37 +42,50 @@ type signingExecutor struct {
 	// be made by a single signer for the given message. Once the attempts
 	// limit is hit the signer gives up.
 	signingAttemptsLimit uint
+
+	protocolTiming *ProtocolTiming
+
+	attemptJournal *attemptJournal
+
+	// presignaturePool holds presignatures used to speed up signing. It is
+	// nil if presignatures are not computed.
+	presignaturePool *presignaturePool
 }
 
 func newSigningExecutor(
 	signers []*signer,
 	broadcastChannel net.BroadcastChannel,
 	membershipValidator *group.MembershipValidator,
-	groupParameters *GroupParameters,
 	protocolLatch *generator.ProtocolLatch,
 	currentBlockFn func() (uint64, error),
 	waitForBlockFn waitForBlockFn,
 	signingAttemptsLimit uint,
+	protocolTiming *ProtocolTiming,
+	attemptJournal *attemptJournal,
+	presignaturePool *presignaturePool,
 ) *signingExecutor {
 	return &signingExecutor{
 		lock:                 semaphore.NewWeighted(1),
 		signers:              signers,
 		broadcastChannel:     broadcastChannel,
 		membershipValidator:  membershipValidator,
-		groupParameters:      groupParameters,
 		protocolLatch:        protocolLatch,
 		currentBlockFn:       currentBlockFn,
 		waitForBlockFn:       waitForBlockFn,
 		signingAttemptsLimit: signingAttemptsLimit,
+		protocolTiming:       protocolTiming,
+		attemptJournal:       attemptJournal,
+		presignaturePool:     presignaturePool,
 	}
 }
 
 // signBatch performs the signing process for each message from the given
-// messages batch, one after another. If at least one message cannot be signed,
-// this function returns an error. If all messages were signed successfully,
-// a slice of signatures is returned. Order of the returned signatures matches
-// the order of the messages in the batch, i.e. the first signature corresponds
-// to the first message, and so on.
+// messages batch, one after another or concurrently, depending on the
+// signing batch concurrency of the executor. If at least one message cannot
+// be signed, this function returns an error. If all messages were signed
+// successfully, a slice of signatures is returned. Order of the returned
+// signatures matches the order of the messages in the batch, i.e. the first
+// signature corresponds to the first message, and so on.
 func (se *signingExecutor) signBatch(
 	ctx context.Context,
 	messages []*big.Int,
@@ -129,6 +126,16 @@ func (se *signingExecutor) signBatch(
 		zap.String("messages", strings.Join(messagesDigests, ", ")),
 	)
 
+	if concurrency := se.batchConcurrency(messages); concurrency > 1 {
+		return se.signBatchConcurrently(
+			ctx,
+			signingBatchLogger,
+			messages,
+			startBlock,
+			concurrency,
+		)
+	}
+
 	signingStartBlock := startBlock // start block for the first signing
 	signatures := make([]*tecdsa.Signature, len(messages))
 	endBlocks := make([]uint64, len(messages))
@@ -142,7 +149,8 @@ func (se *signingExecutor) signBatch(
 		signingBatchMessageLogger.Infof("generating signature for message")
 
 		if i > 0 {
-			signingStartBlock = endBlocks[i-1] + signingBatchInterludeBlocks
+			signingStartBlock = endBlocks[i-1] +
+				se.protocolTiming.SigningBatchInterludeBlocks
 		}
 
 		signature, endBlock, err := se.sign(ctx, message, signingStartBlock)
@@ -163,6 +171,139 @@ func (se *signingExecutor) signBatch(
 	return signatures, nil
 }
 
+// batchConcurrency returns the number of messages of the given batch that
+// can be signed concurrently. The concurrency determines the start blocks of
+// the signings so it must be the same for all signers of the wallet. That is
+// why it is computed only from the batch, the wallet data, and the protocol
+// timing shared by all signers. The number is bounded by the signing batch
+// concurrency limit, the batch size, and the number of distinct operators
+// of the wallet's signing group. Each operator runs all its seats in every
+// concurrent signing so, a group controlled by a few operators signs fewer
+// messages at once. Concurrent signings are distinguished by session
+// identifiers derived from the signed messages so a batch holding duplicated
+// messages is always signed sequentially.
+func (se *signingExecutor) batchConcurrency(messages []*big.Int) int {
+	concurrency := len(messages)
+	if uint64(concurrency) > se.protocolTiming.SigningBatchConcurrencyLimit {
+		concurrency = int(se.protocolTiming.SigningBatchConcurrencyLimit)
+	}
+	wallet := se.wallet()
+	operatorsCount := len(chain.Addresses(wallet.signingGroupOperators).Set())
+	if concurrency > operatorsCount {
+		concurrency = operatorsCount
+	}
+
+	if concurrency < 2 {
+		return 1
+	}
+
+	uniqueMessages := make(map[string]bool, len(messages))
+	for _, message := range messages {
+		key := message.Text(16)
+		if uniqueMessages[key] {
+			return 1
+		}
+		uniqueMessages[key] = true
+	}
+
+	return concurrency
+}
+
+// signBatchConcurrently performs the signing process for the given messages
+// batch using the given number of concurrent signing slots. The i-th message
+// of the batch is assigned to the slot i mod concurrency. Each slot signs its
+// messages one after another, the same way as the sequential batch signing
+// does, so the start block of each signing is common for all wallet signers.
+// Signings executed by different slots are multiplexed on the wallet's
+// broadcast channel and distinguished by their session identifiers. The whole
+// batch is aborted as soon as one of the messages cannot be signed.
+func (se *signingExecutor) signBatchConcurrently(
+	ctx context.Context,
+	signingBatchLogger *zap.SugaredLogger,
+	messages []*big.Int,
+	startBlock uint64,
+	concurrency int,
+) ([]*tecdsa.Signature, error) {
+	if lockAcquired := se.lock.TryAcquire(1); !lockAcquired {
+		return nil, errSigningExecutorBusy
+	}
+	defer se.lock.Release(1)
+
+	signingBatchLogger.Infof(
+		"signing batch using [%v] concurrent signing slots",
+		concurrency,
+	)
+
+	batchCtx, cancelBatchCtx := context.WithCancel(ctx)
+	defer cancelBatchCtx()
+
+	signatures := make([]*tecdsa.Signature, len(messages))
+
+	var batchErr error
+	batchErrMutex := sync.Mutex{}
+
+	wg := sync.WaitGroup{}
+	wg.Add(concurrency)
+
+	for slot := 0; slot < concurrency; slot++ {
+		go func(slot int) {
+			defer wg.Done()
+
+			signingStartBlock := startBlock // start block for the first signing
+
+			for i := slot; i < len(messages); i += concurrency {
+				signingBatchMessageLogger := signingBatchLogger.With(
+					zap.String("message", fmt.Sprintf("0x%x", messages[i])),
+					zap.String("index", fmt.Sprintf("%v/%v", i+1, len(messages))),
+					zap.Int("slot", slot),
+				)
+
+				signingBatchMessageLogger.Infof("generating signature for message")
+
+				// Presignatures are not used by concurrent signings as
+				// signers could take them from their pools in different
+				// order.
+				signature, endBlock, err := se.signMessage(
+					batchCtx,
+					messages[i],
+					signingStartBlock,
+					false,
+				)
+				if err != nil {
+					// Record only the first error. Signings of other slots
+					// fail due to the batch context cancellation.
+					batchErrMutex.Lock()
+					if batchErr == nil {
+						batchErr = err
+					}
+					batchErrMutex.Unlock()
+
+					cancelBatchCtx()
+					return
+				}
+
+				signingBatchMessageLogger.Infof(
+					"generated signature [%v] for message at block [%v]",
+					signature,
+					endBlock,
+				)
+
+				signatures[i] = signature
+				signingStartBlock = endBlock +
+					se.protocolTiming.SigningBatchInterludeBlocks
+			}
+		}(slot)
+	}
+
+	wg.Wait()
+
+	if batchErr != nil {
+		return nil, batchErr
+	}
+
+	return signatures, nil
+}
+
 // sign performs the signing process for the given message. The process is
 // triggered according to the given start block. If the message cannot be signed
 // within a limited time window, an error is returned. If the message was
@@ -179,6 +320,21 @@ func (se *signingExecutor) sign(
 	}
 	defer se.lock.Release(1)
 
+	return se.signMessage(ctx, message, startBlock, true)
+}
+
+// signMessage performs the signing process for the given message, the same
+// way as sign does, but without acquiring the executor's lock. The caller
+// is responsible for holding the lock. If usePresignature is set, each signer
+// takes the newest presignature from its pool and the first signing attempt
+// uses it. The presignature is removed from the pool regardless of the
+// signing outcome.
+func (se *signingExecutor) signMessage(
+	ctx context.Context,
+	message *big.Int,
+	startBlock uint64,
+	usePresignature bool,
+) (*tecdsa.Signature, uint64, error) {
 	wallet := se.wallet()
 
 	walletPublicKeyBytes, err := marshalPublicKey(wallet.publicKey)
@@ -187,7 +343,8 @@ func (se *signingExecutor) sign(
 	}
 
 	loopTimeoutBlock := startBlock +
-		uint64(se.signingAttemptsLimit*signingAttemptMaximumBlocks())
+		uint64(se.signingAttemptsLimit)*
+			se.protocolTiming.signingAttemptMaximumBlocks()
 
 	signingLogger := logger.With(
 		zap.String("wallet", fmt.Sprintf("0x%x", walletPublicKeyBytes)),
@@ -219,20 +376,40 @@ func (se *signingExecutor) sign(
 			)
 
 			doneCheck := newSigningDoneCheck(
-				se.groupParameters.GroupSize,
+				wallet.groupParameters.GroupSize,
 				se.broadcastChannel,
 				se.membershipValidator,
 			)
 
+			var presignatureEntry *presignaturePoolEntry
+			if usePresignature {
+				presignatureEntry = se.takePresignature(
+					hex.EncodeToString(walletPublicKeyBytes),
+					signer.signingGroupMemberIndex,
+					startBlock,
+				)
+			}
+
+			journalSession := se.attemptJournal.startSession(
+				SigningJournalProtocol,
+				message.Text(16),
+				hex.EncodeToString(walletPublicKeyBytes),
+				signer.signingGroupMemberIndex,
+				startBlock,
+			)
+
 			retryLoop := newSigningRetryLoop(
 				signingLogger,
 				message,
 				startBlock,
 				signer.signingGroupMemberIndex,
 				wallet.signingGroupOperators,
-				se.groupParameters,
+				wallet.groupParameters,
+				se.protocolTiming,
 				announcer,
 				doneCheck,
+				journalSession,
+				presignatureEntry,
 			)
 
 			// Set up the loop timeout signal. This context is associated with
@@ -291,21 +468,38 @@ func (se *signingExecutor) sign(
 						attempt.number,
 					)
 
-					result, err := signing.Execute(
-						attemptCtx,
-						signingAttemptLogger,
-						message,
-						sessionID,
-						signer.signingGroupMemberIndex,
-						signer.privateKeyShare,
-						wallet.groupSize(),
-						wallet.groupDishonestThreshold(
-							se.groupParameters.HonestThreshold,
-						),
-						attempt.excludedMembersIndexes,
-						se.broadcastChannel,
-						se.membershipValidator,
-					)
+					var result *signing.Result
+					var err error
+
+					if attempt.presignature != nil {
+						result, err = signing.ExecuteWithPresignature(
+							attemptCtx,
+							signingAttemptLogger,
+							message,
+							sessionID+"-p",
+							signer.signingGroupMemberIndex,
+							signer.privateKeyShare,
+							attempt.presignature,
+							wallet.groupSize(),
+							wallet.groupDishonestThreshold(),
+							se.broadcastChannel,
+							se.membershipValidator,
+						)
+					} else {
+						result, err = signing.Execute(
+							attemptCtx,
+							signingAttemptLogger,
+							message,
+							sessionID,
+							signer.signingGroupMemberIndex,
+							signer.privateKeyShare,
+							wallet.groupSize(),
+							wallet.groupDishonestThreshold(),
+							attempt.excludedMembersIndexes,
+							se.broadcastChannel,
+							se.membershipValidator,
+						)
+					}
 					if err != nil {
 						return nil, 0, err
 					}
@@ -318,6 +512,7 @@ func (se *signingExecutor) sign(
 					return result, endBlock, nil
 				},
 			)
+			journalSession.finish(err)
 			if err != nil {
 				// Signer failed so there is no point to hold the loopCtx.
 				// Cancel it regardless of their timeout.
@@ -388,6 +583,29 @@ func (se *signingExecutor) sign(
 	}
 }
 
+// takePresignature takes the presignature the given member should use to
+// sign the message whose signing starts at the given block. Only
+// presignatures computed in sessions that ended before the signing start are
+// taken as all signers of the wallet must have recorded them already.
+// Returns nil if presignatures are not computed or there is no presignature
+// available.
+func (se *signingExecutor) takePresignature(
+	wallet string,
+	memberIndex group.MemberIndex,
+	startBlock uint64,
+) *presignaturePoolEntry {
+	sessionBlocks := se.protocolTiming.signingAttemptMaximumBlocks()
+	if startBlock <= sessionBlocks {
+		return nil
+	}
+
+	return se.presignaturePool.take(
+		wallet,
+		memberIndex,
+		startBlock-sessionBlocks-1,
+	)
+}
+
 func (se *signingExecutor) wallet() wallet {
 	// All signers belong to one wallet. Take that wallet from the
 	// first signer.
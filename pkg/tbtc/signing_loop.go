@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"math/rand"
 	"sort"
+	"time"
 
 	"github.com/ipfs/go-log/v2"
 	"github.com/keep-network/keep-core/pkg/chain"
@@ -17,32 +18,6 @@ import (
 	"golang.org/x/exp/slices"
 )
 
-const (
-	// signingAttemptAnnouncementDelayBlocks determines the duration of the
-	// announcement phase delay that is preserved before starting the
-	// announcement phase.
-	signingAttemptAnnouncementDelayBlocks = 1
-	// signingAttemptAnnouncementActiveBlocks determines the duration of the
-	// announcement phase that is performed at the beginning of each signing
-	// attempt.
-	signingAttemptAnnouncementActiveBlocks = 5
-	// signingAttemptProtocolBlocks determines the maximum block duration of the
-	// actual protocol computations.
-	signingAttemptMaximumProtocolBlocks = 30
-	// signingAttemptCoolDownBlocks determines the duration of the cool down
-	// period that is preserved between subsequent signing attempts.
-	signingAttemptCoolDownBlocks = 5
-)
-
-// signingAttemptMaximumBlocks returns the maximum block duration of a single
-// signing attempt.
-func signingAttemptMaximumBlocks() uint {
-	return signingAttemptAnnouncementDelayBlocks +
-		signingAttemptAnnouncementActiveBlocks +
-		signingAttemptMaximumProtocolBlocks +
-		signingAttemptCoolDownBlocks
-}
-
 // signingAnnouncer represents a component responsible for exchanging readiness
 // announcements for the given signing attempt of the given message.
 type signingAnnouncer interface {
@@ -86,6 +61,7 @@ type signingRetryLoop struct {
 	signingGroupOperators   chain.Addresses
 
 	groupParameters *GroupParameters
+	protocolTiming  *ProtocolTiming
 
 	announcer signingAnnouncer
 
@@ -94,6 +70,13 @@ type signingRetryLoop struct {
 	attemptSeed       int64
 
 	doneCheck signingDoneCheckStrategy
+
+	// journal records the attempts of the loop. It may be nil.
+	journal *attemptJournalSession
+
+	// presignatureEntry is the presignature pool entry used by the first
+	// attempt. It may be nil.
+	presignatureEntry *presignaturePoolEntry
 }
 
 func newSigningRetryLoop(
@@ -103,8 +86,11 @@ func newSigningRetryLoop(
 	signingGroupMemberIndex group.MemberIndex,
 	signingGroupOperators chain.Addresses,
 	groupParameters *GroupParameters,
+	protocolTiming *ProtocolTiming,
 	announcer signingAnnouncer,
 	doneCheck signingDoneCheckStrategy,
+	journal *attemptJournalSession,
+	presignatureEntry *presignaturePoolEntry,
 ) *signingRetryLoop {
 	// Compute the 8-byte seed needed for the random retry algorithm. We take
 	// the first 8 bytes of the hash of the signed message. This allows us to
@@ -119,11 +105,14 @@ func newSigningRetryLoop(
 		signingGroupMemberIndex: signingGroupMemberIndex,
 		signingGroupOperators:   signingGroupOperators,
 		groupParameters:         groupParameters,
+		protocolTiming:          protocolTiming,
 		announcer:               announcer,
 		attemptCounter:          0,
 		attemptStartBlock:       initialStartBlock,
 		attemptSeed:             attemptSeed,
 		doneCheck:               doneCheck,
+		journal:                 journal,
+		presignatureEntry:       presignatureEntry,
 	}
 }
 
@@ -133,6 +122,9 @@ type signingAttemptParams struct {
 	startBlock             uint64
 	timeoutBlock           uint64
 	excludedMembersIndexes []group.MemberIndex
+	// presignature is the presignature the attempt should be executed with.
+	// If it is nil, the attempt executes the full signing protocol.
+	presignature *signing.Presignature
 }
 
 // signingAttemptFn represents a function performing a signing attempt.
@@ -156,6 +148,10 @@ type signingRetryLoopResult struct {
 // parameter is done, whatever comes first. The signing result is produced
 // only if all signers who participated in signing confirmed they are done
 // by sending a valid `signingDoneMessage` during the signing done check phase.
+// If the loop holds a presignature pool entry, the first attempt is executed
+// using the presignature by exactly the members that computed it, provided
+// all of them are ready. Otherwise, the first attempt selects members the
+// same way as the next attempts do.
 func (srl *signingRetryLoop) start(
 	ctx context.Context,
 	waitForBlockFn waitForBlockFn,
@@ -174,14 +170,14 @@ func (srl *signingRetryLoop) start(
 		// by some additional delay blocks. We need a small cool down in
 		// order to mitigate all corner cases where the actual attempt duration
 		// was slightly longer than the expected duration determined by the
-		// signingAttemptMaximumProtocolBlocks constant.
+		// SigningAttemptMaximumProtocolBlocks protocol timing value.
 		//
 		// For example, the attempt may fail at the end of the protocol but the
 		// error is returned after some time and more blocks than expected are
 		// mined in the meantime.
 		if srl.attemptCounter > 1 {
 			srl.attemptStartBlock = srl.attemptStartBlock +
-				uint64(signingAttemptMaximumBlocks())
+				srl.protocolTiming.signingAttemptMaximumBlocks()
 		}
 
 		srl.logger.Infof(
@@ -190,8 +186,16 @@ func (srl *signingRetryLoop) start(
 			srl.attemptCounter,
 		)
 
-		announcementStartBlock := srl.attemptStartBlock + signingAttemptAnnouncementDelayBlocks
+		announcementStartBlock := srl.attemptStartBlock +
+			srl.protocolTiming.SigningAttemptAnnouncementDelayBlocks
 		err := waitForBlockFn(ctx, announcementStartBlock)
+
+		attemptEntry := &AttemptJournalEntry{
+			Number:     srl.attemptCounter,
+			StartBlock: srl.attemptStartBlock,
+			StartedAt:  time.Now(),
+		}
+
 		if err != nil {
 			srl.logger.Errorf(
 				"[member:%v] failed waiting for announcement start "+
@@ -201,11 +205,13 @@ func (srl *signingRetryLoop) start(
 				srl.attemptCounter,
 				err,
 			)
+			srl.journal.recordAttempt(attemptEntry, AttemptFailed, err)
 			continue
 		}
 
 		// Set up the announcement phase stop signal.
-		announcementEndBlock := announcementStartBlock + signingAttemptAnnouncementActiveBlocks
+		announcementEndBlock := announcementStartBlock +
+			srl.protocolTiming.SigningAttemptAnnouncementActiveBlocks
 		announceCtx, _ := withCancelOnBlock(ctx, announcementEndBlock, waitForBlockFn)
 
 		srl.logger.Infof(
@@ -214,10 +220,30 @@ func (srl *signingRetryLoop) start(
 			srl.attemptCounter,
 		)
 
+		// The first attempt is executed with the presignature all members
+		// took from their pools, if any. Members that took different
+		// presignatures or none announce in different sessions so they
+		// never consider each other ready.
+		usePresignature := srl.attemptCounter == 1 &&
+			srl.presignatureEntry != nil
+
+		announcementSessionID := fmt.Sprintf(
+			"%v-%v",
+			srl.message,
+			srl.attemptCounter,
+		)
+		if usePresignature {
+			announcementSessionID = fmt.Sprintf(
+				"%v-p%v",
+				announcementSessionID,
+				srl.presignatureEntry.SessionBlock,
+			)
+		}
+
 		readyMembersIndexes, err := srl.announcer.Announce(
 			announceCtx,
 			srl.signingGroupMemberIndex,
-			fmt.Sprintf("%v-%v", srl.message, srl.attemptCounter),
+			announcementSessionID,
 		)
 		if err != nil {
 			srl.logger.Warnf(
@@ -227,6 +253,7 @@ func (srl *signingRetryLoop) start(
 				srl.attemptCounter,
 				err,
 			)
+			srl.journal.recordAttempt(attemptEntry, AttemptAnnouncementFailed, err)
 			continue
 		}
 
@@ -235,6 +262,8 @@ func (srl *signingRetryLoop) start(
 			return nil, ctx.Err()
 		}
 
+		attemptEntry.ReadyMembers = readyMembersIndexes
+
 		if len(readyMembersIndexes) >= srl.groupParameters.HonestThreshold {
 			srl.logger.Infof(
 				"[member:%v] completed announcement phase for attempt [%v] "+
@@ -252,20 +281,47 @@ func (srl *signingRetryLoop) start(
 				srl.attemptCounter,
 				len(readyMembersIndexes),
 			)
+			srl.journal.recordAttempt(
+				attemptEntry,
+				AttemptNotEnoughReadyMembers,
+				nil,
+			)
 			continue
 		}
 
-		excludedMembersIndexes, err := srl.performMembersSelection(
-			readyMembersIndexes,
-		)
-		if err != nil {
-			return nil, fmt.Errorf(
-				"cannot select members for attempt [%v]: [%w]",
-				srl.attemptCounter,
-				err,
+		var excludedMembersIndexes []group.MemberIndex
+		var presignature *signing.Presignature
+		presignatureUnusable := false
+
+		if usePresignature && srl.isPresignatureUsable(readyMembersIndexes) {
+			excludedMembersIndexes = srl.presignatureExcludedMembersIndexes()
+
+			presignature, err = srl.presignatureEntry.presignature()
+			if err != nil {
+				srl.logger.Errorf(
+					"[member:%v] cannot use presignature for attempt [%v]: "+
+						"[%v]; skipping attempt",
+					srl.signingGroupMemberIndex,
+					srl.attemptCounter,
+					err,
+				)
+				presignatureUnusable = true
+			}
+		} else {
+			excludedMembersIndexes, err = srl.performMembersSelection(
+				readyMembersIndexes,
 			)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot select members for attempt [%v]: [%w]",
+					srl.attemptCounter,
+					err,
+				)
+			}
 		}
 
+		attemptEntry.ExcludedMembers = excludedMembersIndexes
+
 		includedMembersIndexes := make([]group.MemberIndex, 0)
 		for i := range srl.signingGroupOperators {
 			memberIndex := group.MemberIndex(i + 1)
@@ -274,12 +330,13 @@ func (srl *signingRetryLoop) start(
 			}
 		}
 
-		attemptSkipped := slices.Contains(
+		attemptSkipped := presignatureUnusable || slices.Contains(
 			excludedMembersIndexes,
 			srl.signingGroupMemberIndex,
 		)
 
-		timeoutBlock := announcementEndBlock + signingAttemptMaximumProtocolBlocks
+		timeoutBlock := announcementEndBlock +
+			srl.protocolTiming.SigningAttemptMaximumProtocolBlocks
 
 		// doneCheckTimeoutCtx is active until the timeout even if the protocol
 		// completed successfully earlier. This is needed to ensure all protocol
@@ -306,6 +363,7 @@ func (srl *signingRetryLoop) start(
 				startBlock:             announcementEndBlock,
 				timeoutBlock:           timeoutBlock,
 				excludedMembersIndexes: excludedMembersIndexes,
+				presignature:           presignature,
 			})
 			if err != nil {
 				srl.logger.Warnf(
@@ -315,6 +373,7 @@ func (srl *signingRetryLoop) start(
 					srl.attemptCounter,
 					err,
 				)
+				srl.journal.recordAttempt(attemptEntry, AttemptFailed, err)
 				continue
 			}
 
@@ -340,6 +399,7 @@ func (srl *signingRetryLoop) start(
 					srl.attemptCounter,
 					err,
 				)
+				srl.journal.recordAttempt(attemptEntry, AttemptFailed, err)
 				continue
 			}
 		} else {
@@ -360,9 +420,19 @@ func (srl *signingRetryLoop) start(
 				srl.attemptCounter,
 				err,
 			)
+
+			// The member excluded from the attempt did not fail on its own
+			// so the attempt is recorded as skipped.
+			if attemptSkipped {
+				srl.journal.recordAttempt(attemptEntry, AttemptSkipped, err)
+			} else {
+				srl.journal.recordAttempt(attemptEntry, AttemptFailed, err)
+			}
 			continue
 		}
 
+		srl.journal.recordAttempt(attemptEntry, AttemptSucceeded, nil)
+
 		return &signingRetryLoopResult{
 			result:              result,
 			latestEndBlock:      latestEndBlock,
@@ -371,6 +441,36 @@ func (srl *signingRetryLoop) start(
 	}
 }
 
+// isPresignatureUsable returns true if all members that computed the
+// presignature taken for the first attempt are ready to sign.
+func (srl *signingRetryLoop) isPresignatureUsable(
+	readyMembersIndexes []group.MemberIndex,
+) bool {
+	for _, memberIndex := range srl.presignatureEntry.MembersIndexes {
+		if !slices.Contains(readyMembersIndexes, memberIndex) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// presignatureExcludedMembersIndexes returns a list of members' indexes that
+// did not compute the presignature taken for the first attempt. Those members
+// are excluded from the attempt as the presignature can be used only by
+// exactly the same set of members that computed it.
+func (srl *signingRetryLoop) presignatureExcludedMembersIndexes() []group.MemberIndex {
+	excludedMembersIndexes := make([]group.MemberIndex, 0)
+	for i := range srl.signingGroupOperators {
+		memberIndex := group.MemberIndex(i + 1)
+		if !slices.Contains(srl.presignatureEntry.MembersIndexes, memberIndex) {
+			excludedMembersIndexes = append(excludedMembersIndexes, memberIndex)
+		}
+	}
+
+	return excludedMembersIndexes
+}
+
 // performMembersSelection runs the member selection process whose result
 // is a list of members' indexes that should be excluded by the client
 // for the given signing attempt.
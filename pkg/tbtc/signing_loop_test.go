@@ -13,6 +13,8 @@ import (
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 	"github.com/keep-network/keep-core/pkg/tecdsa"
 	"github.com/keep-network/keep-core/pkg/tecdsa/signing"
+	signingpb "github.com/keep-network/keep-core/pkg/tecdsa/signing/gen/pb"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestSigningRetryLoop(t *testing.T) {
@@ -52,8 +54,35 @@ func TestSigningRetryLoop(t *testing.T) {
 		},
 	}
 
+	presignatureMembersIndexes := []group.MemberIndex{1, 2, 4, 5, 6, 9}
+
+	presignatureBytes, err := proto.Marshal(&signingpb.Presignature{
+		MembersIndexes: []uint32{1, 2, 4, 5, 6, 9},
+		K:              big.NewInt(500).Bytes(),
+		Sigma:          big.NewInt(600).Bytes(),
+		BigRX:          tecdsa.Curve.Params().Gx.Bytes(),
+		BigRY:          tecdsa.Curve.Params().Gy.Bytes(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	presignatureEntry := &presignaturePoolEntry{
+		Wallet:         "ff",
+		MemberIndex:    1,
+		SessionBlock:   150,
+		MembersIndexes: presignatureMembersIndexes,
+		Presignature:   presignatureBytes,
+	}
+
+	presignature, err := presignatureEntry.presignature()
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	var tests = map[string]struct {
 		signingGroupMemberIndex     group.MemberIndex
+		presignatureEntry           *presignaturePoolEntry
 		ctxFn                       func() (context.Context, context.CancelFunc)
 		incomingAnnouncementsFn     func(sessionID string) ([]group.MemberIndex, error)
 		signingAttemptFn            signingAttemptFn
@@ -110,6 +139,147 @@ func TestSigningRetryLoop(t *testing.T) {
 			},
 			outgoingAnnouncementsCount: 1,
 		},
+		"success on initial attempt with presignature": {
+			signingGroupMemberIndex: 1,
+			presignatureEntry:       presignatureEntry,
+			ctxFn: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Second)
+			},
+			incomingAnnouncementsFn: func(
+				sessionID string,
+			) ([]group.MemberIndex, error) {
+				if sessionID == fmt.Sprintf("%v-%v-p%v", message, 1, 150) {
+					return signingGroupMembersIndexes, nil
+				}
+
+				return nil, fmt.Errorf("unexpected session [%v]", sessionID)
+			},
+			signingAttemptFn: func(
+				attempt *signingAttemptParams,
+			) (*signing.Result, uint64, error) {
+				return testResult, 215, nil // an arbitrary end block
+			},
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+				// Simulate that the done check phase determines the same
+				// end block as the executing signer.
+				return testResult, 215, nil
+			},
+			expectedOutgoingDoneChecks: []*signingDoneMessage{
+				{
+					senderID:      1,
+					message:       message,
+					attemptNumber: 1,
+					signature:     testResult.Signature,
+					endBlock:      215,
+				},
+			},
+			expectedErr: nil,
+			expectedResult: &signingRetryLoopResult{
+				result:              testResult,
+				latestEndBlock:      215, // the end block resolved by the done check phase
+				attemptTimeoutBlock: 236, // start block of the first attempt + 30
+			},
+			// All members that computed the presignature are ready so
+			// exactly those members are selected for the attempt.
+			expectedLastExecutedAttempt: &signingAttemptParams{
+				number:                 1,
+				startBlock:             206,
+				timeoutBlock:           236, // start block of the first attempt + 30
+				excludedMembersIndexes: []group.MemberIndex{3, 7, 8, 10},
+				presignature:           presignature,
+			},
+			outgoingAnnouncementsCount: 1,
+		},
+		"initial attempt skipped by member without presignature": {
+			signingGroupMemberIndex: 3,
+			presignatureEntry: &presignaturePoolEntry{
+				Wallet:         "ff",
+				MemberIndex:    3,
+				SessionBlock:   150,
+				MembersIndexes: presignatureMembersIndexes,
+			},
+			ctxFn: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Second)
+			},
+			incomingAnnouncementsFn: func(
+				sessionID string,
+			) ([]group.MemberIndex, error) {
+				if sessionID == fmt.Sprintf("%v-%v-p%v", message, 1, 150) {
+					return signingGroupMembersIndexes, nil
+				}
+
+				return nil, fmt.Errorf("unexpected session [%v]", sessionID)
+			},
+			signingAttemptFn: func(
+				attempt *signingAttemptParams,
+			) (*signing.Result, uint64, error) {
+				panic("undefined behavior")
+			},
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+				return testResult, 215, nil
+			},
+			expectedOutgoingDoneChecks: nil,
+			expectedErr:                nil,
+			expectedResult: &signingRetryLoopResult{
+				result:              testResult,
+				latestEndBlock:      215, // the end block resolved by the done check phase
+				attemptTimeoutBlock: 236, // start block of the first attempt + 30
+			},
+			expectedLastExecutedAttempt: nil,
+		},
+		"presignature members not ready on initial attempt": {
+			signingGroupMemberIndex: 1,
+			presignatureEntry:       presignatureEntry,
+			ctxFn: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Second)
+			},
+			incomingAnnouncementsFn: func(
+				sessionID string,
+			) ([]group.MemberIndex, error) {
+				if sessionID == fmt.Sprintf("%v-%v-p%v", message, 1, 150) {
+					// Member 9 that computed the presignature is not ready.
+					return []group.MemberIndex{1, 2, 3, 4, 5, 6, 7, 8}, nil
+				}
+
+				return nil, fmt.Errorf("unexpected session [%v]", sessionID)
+			},
+			signingAttemptFn: func(
+				attempt *signingAttemptParams,
+			) (*signing.Result, uint64, error) {
+				return testResult, 215, nil // an arbitrary end block
+			},
+			waitUntilAllDoneOutcomeFn: func(attemptNumber uint64) (*signing.Result, uint64, error) {
+				// Simulate that the done check phase determines the same
+				// end block as the executing signer.
+				return testResult, 215, nil
+			},
+			expectedOutgoingDoneChecks: []*signingDoneMessage{
+				{
+					senderID:      1,
+					message:       message,
+					attemptNumber: 1,
+					signature:     testResult.Signature,
+					endBlock:      215,
+				},
+			},
+			expectedErr: nil,
+			expectedResult: &signingRetryLoopResult{
+				result:              testResult,
+				latestEndBlock:      215, // the end block resolved by the done check phase
+				attemptTimeoutBlock: 236, // start block of the first attempt + 30
+			},
+			// The presignature cannot be used so the members are selected
+			// from the ready ones the same way as without the presignature.
+			// Not ready members 9 and 10 are excluded along with members
+			// 6 and 7 trimmed by the random selection.
+			expectedLastExecutedAttempt: &signingAttemptParams{
+				number:                 1,
+				startBlock:             206,
+				timeoutBlock:           236, // start block of the first attempt + 30
+				excludedMembersIndexes: []group.MemberIndex{6, 7, 9, 10},
+			},
+			outgoingAnnouncementsCount: 1,
+		},
 		"success on initial attempt with missing announcements and honest majority": {
 			signingGroupMemberIndex: 1,
 			ctxFn: func() (context.Context, context.CancelFunc) {
@@ -472,8 +642,11 @@ func TestSigningRetryLoop(t *testing.T) {
 				test.signingGroupMemberIndex,
 				signingGroupOperators,
 				groupParameters,
+				&MainnetProtocolTiming,
 				announcer,
 				doneCheck,
+				nil,
+				test.presignatureEntry,
 			)
 
 			ctx, cancelCtx := test.ctxFn()
@@ -3,6 +3,7 @@ package tbtc
 import (
 	"context"
 	"crypto/ecdsa"
+	"fmt"
 	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/chain/local_v1"
 	"github.com/keep-network/keep-core/pkg/generator"
@@ -105,6 +106,168 @@ func TestSigningExecutor_SignBatch(t *testing.T) {
 	}
 }
 
+func TestSigningExecutor_SignBatch_Concurrent(t *testing.T) {
+	executor := setupSigningExecutor(t)
+	executor.protocolTiming.SigningBatchConcurrencyLimit = 2
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	messages := []*big.Int{
+		big.NewInt(1000),
+		big.NewInt(2000),
+		big.NewInt(3000),
+	}
+	startBlock := uint64(0)
+
+	signatures, err := executor.signBatch(ctx, messages, startBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"signatures count",
+		len(messages),
+		len(signatures),
+	)
+
+	walletPublicKey := executor.wallet().publicKey
+
+	for i, signature := range signatures {
+		if !ecdsa.Verify(
+			walletPublicKey,
+			messages[i].Bytes(),
+			signature.R,
+			signature.S,
+		) {
+			t.Errorf("invalid signature [%v]: [%+v]", i, signature)
+		}
+	}
+}
+
+func TestSigningBatchConcurrency(t *testing.T) {
+	var tests = map[string]struct {
+		signingBatchConcurrencyLimit uint64
+		groupSize                    int
+		operatorsCount               int
+		messages                     []*big.Int
+		expectedConcurrency          int
+	}{
+		"concurrency not set": {
+			signingBatchConcurrencyLimit: 0,
+			groupSize:                    10,
+			messages:                     []*big.Int{big.NewInt(1), big.NewInt(2)},
+			expectedConcurrency:          1,
+		},
+		"sequential signing": {
+			signingBatchConcurrencyLimit: 1,
+			groupSize:                    10,
+			messages:                     []*big.Int{big.NewInt(1), big.NewInt(2)},
+			expectedConcurrency:          1,
+		},
+		"concurrency lower than batch size": {
+			signingBatchConcurrencyLimit: 2,
+			groupSize:                    10,
+			messages: []*big.Int{
+				big.NewInt(1),
+				big.NewInt(2),
+				big.NewInt(3),
+			},
+			expectedConcurrency: 2,
+		},
+		"concurrency greater than batch size": {
+			signingBatchConcurrencyLimit: 5,
+			groupSize:                    10,
+			messages:                     []*big.Int{big.NewInt(1), big.NewInt(2)},
+			expectedConcurrency:          2,
+		},
+		"single message batch": {
+			signingBatchConcurrencyLimit: 5,
+			groupSize:                    10,
+			messages:                     []*big.Int{big.NewInt(1)},
+			expectedConcurrency:          1,
+		},
+		"concurrency greater than group size": {
+			signingBatchConcurrencyLimit: 5,
+			groupSize:                    2,
+			messages: []*big.Int{
+				big.NewInt(1),
+				big.NewInt(2),
+				big.NewInt(3),
+			},
+			expectedConcurrency: 2,
+		},
+		"concurrency greater than operators count": {
+			signingBatchConcurrencyLimit: 5,
+			groupSize:                    10,
+			operatorsCount:               3,
+			messages: []*big.Int{
+				big.NewInt(1),
+				big.NewInt(2),
+				big.NewInt(3),
+				big.NewInt(4),
+			},
+			expectedConcurrency: 3,
+		},
+		"single operator": {
+			signingBatchConcurrencyLimit: 5,
+			groupSize:                    10,
+			operatorsCount:               1,
+			messages:                     []*big.Int{big.NewInt(1), big.NewInt(2)},
+			expectedConcurrency:          1,
+		},
+		"duplicated messages": {
+			signingBatchConcurrencyLimit: 5,
+			groupSize:                    10,
+			messages: []*big.Int{
+				big.NewInt(1),
+				big.NewInt(2),
+				big.NewInt(1),
+			},
+			expectedConcurrency: 1,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			// Seats are distributed among the given number of operators.
+			// Each seat is controlled by a separate operator by default.
+			operatorsCount := test.operatorsCount
+			if operatorsCount == 0 {
+				operatorsCount = test.groupSize
+			}
+
+			signingGroupOperators := make([]chain.Address, test.groupSize)
+			for i := range signingGroupOperators {
+				signingGroupOperators[i] = chain.Address(
+					fmt.Sprintf("0x%040x", i%operatorsCount),
+				)
+			}
+
+			executor := &signingExecutor{
+				signers: []*signer{
+					{
+						wallet: wallet{
+							signingGroupOperators: signingGroupOperators,
+						},
+					},
+				},
+				protocolTiming: &ProtocolTiming{
+					SigningBatchConcurrencyLimit: test.signingBatchConcurrencyLimit,
+				},
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"batch concurrency",
+				test.expectedConcurrency,
+				executor.batchConcurrency(test.messages),
+			)
+		})
+	}
+}
+
 // setupSigningExecutor sets up an instance of the signing executor ready
 // to perform test signing.
 func setupSigningExecutor(t *testing.T) *signingExecutor {
@@ -152,6 +315,7 @@ func setupSigningExecutor(t *testing.T) *signingExecutor {
 			wallet: wallet{
 				publicKey:             privateKeyShare.PublicKey(),
 				signingGroupOperators: operators,
+				groupParameters:       groupParameters,
 			},
 			signingGroupMemberIndex: group.MemberIndex(i + 1),
 			privateKeyShare:         privateKeyShare,
@@ -163,6 +327,7 @@ func setupSigningExecutor(t *testing.T) *signingExecutor {
 	node, err := newNode(
 		groupParameters,
 		localChain,
+		newMockBitcoinChain(),
 		localProvider,
 		keyStorePersistence,
 		&mockPersistenceHandle{},
@@ -173,6 +338,8 @@ func setupSigningExecutor(t *testing.T) *signingExecutor {
 		t.Fatal(err)
 	}
 
+	stopPreParamsGenerationOnCleanup(t, node)
+
 	executor, ok, err := node.getSigningExecutor(signers[0].wallet.publicKey)
 	if err != nil {
 		t.Fatal(err)
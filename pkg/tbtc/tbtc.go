@@ -10,14 +10,20 @@ import (
 	"github.com/ipfs/go-log"
 
 	"github.com/keep-network/keep-common/pkg/persistence"
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/clientinfo"
 	"github.com/keep-network/keep-core/pkg/generator"
 	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
 	"github.com/keep-network/keep-core/pkg/sortition"
 )
 
 // TODO: Unit tests for `tbtc.go`.
 
+// attemptJournalDiagnosticsSessions is the number of the most recent attempt
+// journal sessions exposed by the diagnostics endpoint.
+const attemptJournalDiagnosticsSessions = 50
+
 var logger = log.Logger("keep-tbtc")
 
 // ProtocolName denotes the name of the protocol defined by this package.
@@ -43,11 +49,55 @@ func (gp *GroupParameters) DishonestThreshold() int {
 	return gp.GroupSize - gp.HonestThreshold
 }
 
+// validate checks whether the group parameters can be used to generate
+// a group. The group size must fit the member index type used in the DKG
+// result and the thresholds must satisfy
+// 0 < HonestThreshold <= GroupQuorum <= GroupSize.
+func (gp *GroupParameters) validate() error {
+	if gp.GroupSize <= 0 || gp.GroupSize > group.MaxMemberIndex {
+		return fmt.Errorf(
+			"group size [%v] must be in range [1, %v]",
+			gp.GroupSize,
+			group.MaxMemberIndex,
+		)
+	}
+
+	if gp.GroupQuorum <= 0 || gp.GroupQuorum > gp.GroupSize {
+		return fmt.Errorf(
+			"group quorum [%v] must be in range [1, %v]",
+			gp.GroupQuorum,
+			gp.GroupSize,
+		)
+	}
+
+	if gp.HonestThreshold <= 0 || gp.HonestThreshold > gp.GroupQuorum {
+		return fmt.Errorf(
+			"honest threshold [%v] must be in range [1, %v]",
+			gp.HonestThreshold,
+			gp.GroupQuorum,
+		)
+	}
+
+	return nil
+}
+
+// legacyGroupParameters are the group parameters of wallets persisted
+// before the group parameters were read from the chain and stored along
+// with the wallet.
+var legacyGroupParameters = &GroupParameters{
+	GroupSize:       100,
+	GroupQuorum:     90,
+	HonestThreshold: 51,
+}
+
 const (
 	DefaultPreParamsPoolSize              = 1000
 	DefaultPreParamsGenerationTimeout     = 2 * time.Minute
 	DefaultPreParamsGenerationDelay       = 10 * time.Second
 	DefaultPreParamsGenerationConcurrency = 1
+	DefaultAttemptJournalMaxSessions      = 1000
+	DefaultAttemptJournalRetention        = 30 * 24 * time.Hour
+	DefaultPresignaturesPoolSize          = 10
 )
 
 var DefaultKeyGenerationConcurrency = runtime.GOMAXPROCS(0)
@@ -64,6 +114,27 @@ type Config struct {
 	PreParamsGenerationConcurrency int
 	// Concurrency level for key-generation for tECDSA.
 	KeyGenerationConcurrency int
+	// Address the node listens on for pre-parameters for tECDSA delivered
+	// by external workers. If set, pre-parameters are not generated
+	// in-process.
+	PreParamsWorkerAddress string
+	// Secret shared with external pre-parameters workers.
+	PreParamsWorkerSecret string
+	// Maximum number of DKG and signing sessions kept in the attempt
+	// journal.
+	AttemptJournalMaxSessions int
+	// Retention period of DKG and signing sessions kept in the attempt
+	// journal.
+	AttemptJournalRetention time.Duration
+	// Maximum number of presignatures kept for each signer of the wallets
+	// controlled by the node. Presignatures are computed only if the
+	// presigning interval of the protocol timing is set.
+	PresignaturesPoolSize int
+	// Block durations of the DKG and signing protocol phases and the signing
+	// batch concurrency limit. Unset values are taken from the protocol
+	// timing profile of the Ethereum network the client is connected to.
+	// Values explicitly set to zero are kept.
+	ProtocolTiming ProtocolTimingConfig
 }
 
 // Initialize kicks off the TBTC by initializing internal state, ensuring
@@ -72,6 +143,7 @@ type Config struct {
 func Initialize(
 	ctx context.Context,
 	chain Chain,
+	btcChain bitcoin.Chain,
 	netProvider net.Provider,
 	keyStorePersistence persistence.ProtectedHandle,
 	workPersistence persistence.BasicHandle,
@@ -79,15 +151,27 @@ func Initialize(
 	config Config,
 	clientInfo *clientinfo.Registry,
 ) error {
-	groupParameters := &GroupParameters{
-		GroupSize:       100,
-		GroupQuorum:     90,
-		HonestThreshold: 51,
+	groupParameters, err := chain.GroupParameters()
+	if err != nil {
+		return fmt.Errorf("cannot get group parameters: [%v]", err)
+	}
+
+	if err := groupParameters.validate(); err != nil {
+		return fmt.Errorf("invalid group parameters: [%v]", err)
 	}
 
+	logger.Infof(
+		"using group parameters: group size [%v], group quorum [%v], "+
+			"honest threshold [%v]",
+		groupParameters.GroupSize,
+		groupParameters.GroupQuorum,
+		groupParameters.HonestThreshold,
+	)
+
 	node, err := newNode(
 		groupParameters,
 		chain,
+		btcChain,
 		netProvider,
 		keyStorePersistence,
 		workPersistence,
@@ -98,16 +182,61 @@ func Initialize(
 		return fmt.Errorf("cannot set up TBTC node: [%v]", err)
 	}
 
+	dkgParameters, err := chain.DKGParameters()
+	if err != nil {
+		return fmt.Errorf("cannot get DKG parameters: [%v]", err)
+	}
+
+	if err := node.protocolTiming.validate(dkgParameters); err != nil {
+		return fmt.Errorf("invalid protocol timing: [%v]", err)
+	}
+
 	deduplicator := newDeduplicator()
 
 	if clientInfo != nil {
 		// only if client info endpoint is configured
-		clientInfo.ObserveApplicationSource(
+		sources := map[string]clientinfo.Source{
+			"pre_params_count": func() float64 {
+				return float64(node.dkgExecutor.preParamsCount())
+			},
+			"quarantined_pre_params_count": func() float64 {
+				return float64(node.dkgExecutor.quarantinedPreParamsCount())
+			},
+			"quarantined_signers_count": func() float64 {
+				return float64(
+					node.walletRegistry.getQuarantinedSignersCount(),
+				)
+			},
+			"wallet_actions_queued_count": func() float64 {
+				return float64(node.walletDispatcher.queuedActionsCount())
+			},
+		}
+
+		for _, actionType := range walletActionTypes {
+			actionType := actionType
+
+			sources[actionType.String()+"_succeeded_count"] = func() float64 {
+				return float64(
+					node.walletDispatcher.actionsCount(actionType, true),
+				)
+			}
+			sources[actionType.String()+"_failed_count"] = func() float64 {
+				return float64(
+					node.walletDispatcher.actionsCount(actionType, false),
+				)
+			}
+		}
+
+		clientInfo.ObserveApplicationSource("tbtc", sources)
+
+		clientInfo.RegisterApplicationSource(
 			"tbtc",
-			map[string]clientinfo.Source{
-				"pre_params_count": func() float64 {
-					return float64(node.dkgExecutor.preParamsCount())
-				},
+			func() clientinfo.ApplicationInfo {
+				return clientinfo.ApplicationInfo{
+					"attempt_journal": node.attemptJournal.recentSessions(
+						attemptJournalDiagnosticsSessions,
+					),
+				}
 			},
 		)
 	}
@@ -132,6 +261,37 @@ func Initialize(
 		)
 	}
 
+	_ = chain.OnDKGParametersUpdated(func(event *DKGParametersUpdatedEvent) {
+		go func() {
+			logger.Infof(
+				"observed DKG parameters update at block [%v]; "+
+					"refreshing group parameters",
+				event.BlockNumber,
+			)
+
+			if err := node.dkgExecutor.refreshGroupParameters(); err != nil {
+				logger.Errorf("failed to refresh group parameters: [%v]", err)
+			}
+
+			// The protocol timing cannot be changed without a restart
+			// but operators must know when it no longer fits the
+			// updated DKG parameters.
+			dkgParameters, err := chain.DKGParameters()
+			if err != nil {
+				logger.Errorf("failed to get DKG parameters: [%v]", err)
+				return
+			}
+
+			if err := node.protocolTiming.validate(dkgParameters); err != nil {
+				logger.Errorf(
+					"protocol timing does not fit updated DKG "+
+						"parameters: [%v]",
+					err,
+				)
+			}
+		}()
+	})
+
 	_ = chain.OnDKGStarted(func(event *DKGStartedEvent) {
 		go func() {
 			if ok := deduplicator.notifyDKGStarted(
@@ -145,7 +305,8 @@ func Initialize(
 				return
 			}
 
-			confirmationBlock := event.BlockNumber + dkgStartedConfirmationBlocks
+			confirmationBlock := event.BlockNumber +
+				node.protocolTiming.DkgStartedConfirmationBlocks
 
 			logger.Infof(
 				"observed DKG started event with seed [0x%x] and "+
@@ -175,7 +336,8 @@ func Initialize(
 				// we received.
 				pastEvents, err := chain.PastDKGStartedEvents(
 					&DKGStartedEventFilter{
-						StartBlock: event.BlockNumber - dkgStartedConfirmationBlocks,
+						StartBlock: event.BlockNumber -
+							node.protocolTiming.DkgStartedConfirmationBlocks,
 					},
 				)
 				if err != nil {
@@ -207,7 +369,7 @@ func Initialize(
 				node.joinDKGIfEligible(
 					lastEvent.Seed,
 					lastEvent.BlockNumber,
-					dkgStartedConfirmationBlocks,
+					node.protocolTiming.DkgStartedConfirmationBlocks,
 				)
 			} else {
 				logger.Infof(
@@ -291,30 +453,238 @@ func Initialize(
 				return
 			}
 
-			signatures, err := executor.signBatch(
-				context.TODO(),
-				event.Messages,
+			err = node.walletDispatcher.dispatch(ctx, &heartbeatAction{
+				executingWallet:   executor.wallet(),
+				signingExecutor:   executor,
+				messages:          event.Messages,
+				signingStartBlock: event.BlockNumber,
+			})
+			if err != nil {
+				logger.Errorf(
+					"cannot dispatch heartbeat action for wallet "+
+						"with public key [0x%x]: [%v]",
+					event.WalletPublicKey,
+					err,
+				)
+				return
+			}
+		}()
+	})
+
+	_ = chain.OnDepositSweepProposalSubmitted(
+		func(event *DepositSweepProposalSubmittedEvent) {
+			go func() {
+				// There is no need to deduplicate. Proposals are unique
+				// per wallet and block.
+				wallet, ok := node.walletRegistry.getWalletByPublicKeyHash(
+					event.Proposal.WalletPublicKeyHash,
+				)
+				if !ok {
+					logger.Infof(
+						"node does not control signers of wallet "+
+							"with public key hash [0x%x]",
+						event.Proposal.WalletPublicKeyHash,
+					)
+					return
+				}
+
+				logger.Infof(
+					"deposit sweep proposal for wallet [0x%x] "+
+						"submitted at block [%v]",
+					event.Proposal.WalletPublicKeyHash,
+					event.BlockNumber,
+				)
+
+				err := node.walletDispatcher.dispatch(
+					ctx,
+					&depositSweepAction{
+						node:            node,
+						executingWallet: wallet,
+						event:           event,
+					},
+				)
+				if err != nil {
+					logger.Errorf(
+						"cannot dispatch deposit sweep action for "+
+							"wallet [0x%x]: [%v]",
+						event.Proposal.WalletPublicKeyHash,
+						err,
+					)
+					return
+				}
+			}()
+		},
+	)
+
+	_ = chain.OnRedemptionRequested(func(event *RedemptionRequestedEvent) {
+		go func() {
+			if ok := deduplicator.notifyRedemptionRequested(
+				event.WalletPublicKeyHash,
+				event.RedeemerOutputScript,
 				event.BlockNumber,
+			); !ok {
+				logger.Infof(
+					"redemption request for wallet [0x%x] and redeemer "+
+						"output script [0x%x] observed at block [%v] has "+
+						"been already processed",
+					event.WalletPublicKeyHash,
+					event.RedeemerOutputScript,
+					event.BlockNumber,
+				)
+				return
+			}
+
+			wallet, ok := node.walletRegistry.getWalletByPublicKeyHash(
+				event.WalletPublicKeyHash,
 			)
-			if err != nil {
-				logger.Errorf("cannot sign batch: [%v]", err)
+			if !ok {
+				logger.Infof(
+					"node does not control signers of wallet "+
+						"with public key hash [0x%x]",
+					event.WalletPublicKeyHash,
+				)
 				return
 			}
 
 			logger.Infof(
-				"generated [%v] signatures for heartbeat [%s] as "+
-					"requested from wallet [0x%x] at block [%v]",
-				len(signatures),
-				strings.Join(messagesDigests, ", "),
-				event.WalletPublicKey,
+				"redemption requested from wallet [0x%x] to redeemer "+
+					"output script [0x%x] at block [%v]",
+				event.WalletPublicKeyHash,
+				event.RedeemerOutputScript,
 				event.BlockNumber,
 			)
+
+			err := node.walletDispatcher.dispatch(ctx, &redemptionAction{
+				node:            node,
+				executingWallet: wallet,
+				roundStartBlock: redemptionRoundStartBlock(event.BlockNumber),
+			})
+			if err != nil {
+				logger.Errorf(
+					"cannot dispatch redemption action for wallet "+
+						"[0x%x]: [%v]",
+					event.WalletPublicKeyHash,
+					err,
+				)
+				return
+			}
 		}()
 	})
 
+	_ = chain.OnMovingFundsCommitmentSubmitted(
+		func(event *MovingFundsCommitmentSubmittedEvent) {
+			go func() {
+				// There is no need to deduplicate. The commitment can be
+				// submitted only once per wallet.
+				wallet, ok := node.walletRegistry.getWalletByPublicKeyHash(
+					event.WalletPublicKeyHash,
+				)
+				if !ok {
+					logger.Infof(
+						"node does not control signers of wallet "+
+							"with public key hash [0x%x]",
+						event.WalletPublicKeyHash,
+					)
+					return
+				}
+
+				logger.Infof(
+					"moving funds commitment for wallet [0x%x] "+
+						"submitted at block [%v]",
+					event.WalletPublicKeyHash,
+					event.BlockNumber,
+				)
+
+				err := node.walletDispatcher.dispatch(
+					ctx,
+					&movingFundsAction{
+						node:            node,
+						executingWallet: wallet,
+						event:           event,
+					},
+				)
+				if err != nil {
+					logger.Errorf(
+						"cannot dispatch moving funds action for "+
+							"wallet [0x%x]: [%v]",
+						event.WalletPublicKeyHash,
+						err,
+					)
+					return
+				}
+			}()
+		},
+	)
+
+	_ = chain.OnWalletClosed(func(event *WalletClosedEvent) {
+		go handleWalletClosure(node, event.WalletPublicKeyHash, "closed")
+	})
+
+	_ = chain.OnWalletTerminated(func(event *WalletTerminatedEvent) {
+		go handleWalletClosure(node, event.WalletPublicKeyHash, "terminated")
+	})
+
+	go node.runPresigningSessions(ctx)
+
+	// Wallets could have been closed or terminated while the client was
+	// offline. Archive them as live events will never be received for them.
+	go archiveInactiveWallets(chain, node)
+
 	return nil
 }
 
+// archiveInactiveWallets archives signers of all wallets controlled by the
+// node that are already closed or terminated on-chain.
+func archiveInactiveWallets(chain Chain, node *node) {
+	for _, walletPublicKeyHash := range node.walletRegistry.getWalletsPublicKeyHashes() {
+		walletChainData, err := chain.GetWallet(walletPublicKeyHash)
+		if err != nil {
+			logger.Errorf(
+				"cannot get on-chain data of wallet [0x%x]: [%v]",
+				walletPublicKeyHash,
+				err,
+			)
+			continue
+		}
+
+		switch walletChainData.State {
+		case StateClosed:
+			handleWalletClosure(node, walletPublicKeyHash, "closed")
+		case StateTerminated:
+			handleWalletClosure(node, walletPublicKeyHash, "terminated")
+		}
+	}
+}
+
+// handleWalletClosure archives signers of the given wallet if the wallet
+// is controlled by the node. The reason argument describes why the wallet
+// is no longer active and is used for logging purposes only.
+func handleWalletClosure(node *node, walletPublicKeyHash [20]byte, reason string) {
+	if _, ok := node.walletRegistry.getWalletByPublicKeyHash(
+		walletPublicKeyHash,
+	); !ok {
+		return
+	}
+
+	logger.Infof(
+		"wallet [0x%x] has been %s; archiving its signers",
+		walletPublicKeyHash,
+		reason,
+	)
+
+	err := node.archiveWallet(walletPublicKeyHash)
+	if err != nil {
+		logger.Errorf(
+			"cannot archive wallet [0x%x]: [%v]",
+			walletPublicKeyHash,
+			err,
+		)
+		return
+	}
+
+	logger.Infof("wallet [0x%x] has been archived", walletPublicKeyHash)
+}
+
 // enoughPreParamsInPoolPolicy is a policy that enforces the sufficient size
 // of the DKG pre-parameters pool before joining the sortition pool.
 type enoughPreParamsInPoolPolicy struct {
@@ -0,0 +1,135 @@
+package tbtc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/generator"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/net/local"
+)
+
+func TestGroupParameters_Validate(t *testing.T) {
+	var tests = map[string]struct {
+		groupParameters *GroupParameters
+		expectedError   error
+	}{
+		"valid parameters": {
+			groupParameters: &GroupParameters{100, 90, 51},
+		},
+		"all parameters equal": {
+			groupParameters: &GroupParameters{3, 3, 3},
+		},
+		"zero group size": {
+			groupParameters: &GroupParameters{0, 0, 0},
+			expectedError:   fmt.Errorf("group size [0] must be in range [1, 255]"),
+		},
+		"group size exceeding member index": {
+			groupParameters: &GroupParameters{256, 90, 51},
+			expectedError:   fmt.Errorf("group size [256] must be in range [1, 255]"),
+		},
+		"group quorum exceeding group size": {
+			groupParameters: &GroupParameters{100, 101, 51},
+			expectedError:   fmt.Errorf("group quorum [101] must be in range [1, 100]"),
+		},
+		"zero honest threshold": {
+			groupParameters: &GroupParameters{100, 90, 0},
+			expectedError:   fmt.Errorf("honest threshold [0] must be in range [1, 90]"),
+		},
+		"honest threshold exceeding group quorum": {
+			groupParameters: &GroupParameters{100, 90, 91},
+			expectedError:   fmt.Errorf("honest threshold [91] must be in range [1, 90]"),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := test.groupParameters.validate()
+
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected error\n"+
+						"expected: %v\n"+
+						"actual:   %v\n",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func TestArchiveInactiveWallets(t *testing.T) {
+	var tests = map[string]struct {
+		walletChainData  *WalletChainData
+		expectedArchived bool
+	}{
+		"live wallet": {
+			walletChainData:  &WalletChainData{State: StateLive},
+			expectedArchived: false,
+		},
+		"moving funds wallet": {
+			walletChainData:  &WalletChainData{State: StateMovingFunds},
+			expectedArchived: false,
+		},
+		"closed wallet": {
+			walletChainData:  &WalletChainData{State: StateClosed},
+			expectedArchived: true,
+		},
+		"terminated wallet": {
+			walletChainData:  &WalletChainData{State: StateTerminated},
+			expectedArchived: true,
+		},
+		"wallet without on-chain data": {
+			walletChainData:  nil,
+			expectedArchived: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			localChain := Connect()
+
+			signer := createMockSigner(t)
+			walletPublicKeyHash := bitcoin.PublicKeyHash(signer.wallet.publicKey)
+
+			if test.walletChainData != nil {
+				localChain.setWallet(walletPublicKeyHash, test.walletChainData)
+			}
+
+			node, err := newNode(
+				&GroupParameters{
+					GroupSize:       5,
+					GroupQuorum:     4,
+					HonestThreshold: 3,
+				},
+				localChain,
+				newMockBitcoinChain(),
+				local.Connect(),
+				createMockKeyStorePersistence(t, signer),
+				&mockPersistenceHandle{},
+				generator.StartScheduler(),
+				Config{},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			stopPreParamsGenerationOnCleanup(t, node)
+
+			archiveInactiveWallets(localChain, node)
+
+			_, ok := node.walletRegistry.getWalletByPublicKeyHash(
+				walletPublicKeyHash,
+			)
+			testutils.AssertBoolsEqual(
+				t,
+				"wallet archived",
+				test.expectedArchived,
+				!ok,
+			)
+		})
+	}
+}
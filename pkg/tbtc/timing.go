@@ -0,0 +1,332 @@
+package tbtc
+
+import (
+	"fmt"
+)
+
+// ProtocolTiming determines the block durations of the DKG and signing
+// protocol phases along with the concurrency of signing batches. The
+// durations are expressed in blocks while the protocols need a roughly
+// constant amount of time so networks with different block times need
+// different values. All wallet signers must use the same values.
+type ProtocolTiming struct {
+	// DkgStartedConfirmationBlocks determines the block length of the
+	// confirmation period that is preserved after a DKG start. Once the period
+	// elapses, the DKG state is checked to confirm the protocol can be started.
+	DkgStartedConfirmationBlocks uint64
+	// DkgResultSubmissionDelayStepBlocks determines the delay step in blocks
+	// that is used to calculate the submission delay period that should be
+	// respected by the given member to avoid all members submitting the same
+	// DKG result at the same time.
+	DkgResultSubmissionDelayStepBlocks uint64
+	// DkgResultApprovalDelayStepBlocks determines the delay step in blocks
+	// that is used to calculate the approval delay period that should be
+	// respected by the given member to avoid all members approving the same
+	// DKG result at the same time.
+	DkgResultApprovalDelayStepBlocks uint64
+	// DkgResultChallengeConfirmationBlocks determines the block length of
+	// the confirmation period that is preserved after a DKG result challenge
+	// submission. Once the period elapses, the DKG state is checked to confirm
+	// the challenge was accepted successfully.
+	DkgResultChallengeConfirmationBlocks uint64
+	// DkgAttemptAnnouncementDelayBlocks determines the duration of the
+	// announcement phase delay that is preserved before starting the
+	// announcement phase.
+	DkgAttemptAnnouncementDelayBlocks uint64
+	// DkgAttemptAnnouncementActiveBlocks determines the duration of the
+	// announcement phase that is performed at the beginning of each DKG
+	// attempt.
+	DkgAttemptAnnouncementActiveBlocks uint64
+	// DkgAttemptMaximumProtocolBlocks determines the maximum block duration
+	// of the actual protocol computations.
+	DkgAttemptMaximumProtocolBlocks uint64
+	// DkgAttemptCoolDownBlocks determines the duration of the cool down
+	// period that is preserved between subsequent DKG attempts.
+	DkgAttemptCoolDownBlocks uint64
+	// SigningAttemptAnnouncementDelayBlocks determines the duration of the
+	// announcement phase delay that is preserved before starting the
+	// announcement phase.
+	SigningAttemptAnnouncementDelayBlocks uint64
+	// SigningAttemptAnnouncementActiveBlocks determines the duration of the
+	// announcement phase that is performed at the beginning of each signing
+	// attempt.
+	SigningAttemptAnnouncementActiveBlocks uint64
+	// SigningAttemptMaximumProtocolBlocks determines the maximum block
+	// duration of the actual protocol computations.
+	SigningAttemptMaximumProtocolBlocks uint64
+	// SigningAttemptCoolDownBlocks determines the duration of the cool down
+	// period that is preserved between subsequent signing attempts.
+	SigningAttemptCoolDownBlocks uint64
+	// SigningBatchInterludeBlocks determines the block duration of the
+	// interlude preserved between subsequent signings in a signing batch.
+	// If the signing of the previous message completed at block X, the signing
+	// of the next message starts at `X + SigningBatchInterludeBlocks`.
+	// This is the additional time signers have to realize that the signing is
+	// done by receiving the signingDoneMessage. Note that the end block of the
+	// previous signing used to establish the start block of the next signing
+	// comes from signingDoneMessage received and there is no guarantee all
+	// signing group members received signingDoneMessage before the highest
+	// endBlock is reached on the chain. The interlude is an additional time for
+	// the broadcast channel to spread information about signing successfully
+	// completed by the slowest signing group member (the one who sends the
+	// signingDoneMessage as the last one).
+	SigningBatchInterludeBlocks uint64
+	// SigningBatchConcurrencyLimit determines the maximum number of messages
+	// of a signing batch that are signed concurrently. The number of
+	// concurrent signings determines the start block of each signing so all
+	// wallet signers must use the same value. If it is set to 1, messages of
+	// a batch are signed one after another. This is the default for all
+	// networks; concurrent signing must be enabled explicitly.
+	SigningBatchConcurrencyLimit uint64
+	// PresigningIntervalBlocks determines the frequency of presigning
+	// sessions. A presigning session computing one presignature for each
+	// wallet controlled by the node starts at every block being a multiple
+	// of the interval. A session lasts as long as a single signing attempt
+	// so the interval must be longer than that. If it is not set,
+	// presignatures are not computed.
+	PresigningIntervalBlocks uint64
+}
+
+var (
+	// MainnetProtocolTiming is the protocol timing profile for the Ethereum
+	// mainnet with a block time of around 12 seconds.
+	MainnetProtocolTiming = ProtocolTiming{
+		DkgStartedConfirmationBlocks:           20,
+		DkgResultSubmissionDelayStepBlocks:     15,
+		DkgResultApprovalDelayStepBlocks:       15,
+		DkgResultChallengeConfirmationBlocks:   20,
+		DkgAttemptAnnouncementDelayBlocks:      1,
+		DkgAttemptAnnouncementActiveBlocks:     5,
+		DkgAttemptMaximumProtocolBlocks:        150,
+		DkgAttemptCoolDownBlocks:               5,
+		SigningAttemptAnnouncementDelayBlocks:  1,
+		SigningAttemptAnnouncementActiveBlocks: 5,
+		SigningAttemptMaximumProtocolBlocks:    30,
+		SigningAttemptCoolDownBlocks:           5,
+		SigningBatchInterludeBlocks:            2,
+		SigningBatchConcurrencyLimit:           1,
+	}
+
+	// TestnetProtocolTiming is the protocol timing profile for Ethereum
+	// testnets. The block time is the same as on the mainnet but chain
+	// reorganizations are shallow so the confirmation periods are shorter.
+	TestnetProtocolTiming = ProtocolTiming{
+		DkgStartedConfirmationBlocks:           10,
+		DkgResultSubmissionDelayStepBlocks:     15,
+		DkgResultApprovalDelayStepBlocks:       15,
+		DkgResultChallengeConfirmationBlocks:   10,
+		DkgAttemptAnnouncementDelayBlocks:      1,
+		DkgAttemptAnnouncementActiveBlocks:     5,
+		DkgAttemptMaximumProtocolBlocks:        150,
+		DkgAttemptCoolDownBlocks:               5,
+		SigningAttemptAnnouncementDelayBlocks:  1,
+		SigningAttemptAnnouncementActiveBlocks: 5,
+		SigningAttemptMaximumProtocolBlocks:    30,
+		SigningAttemptCoolDownBlocks:           5,
+		SigningBatchInterludeBlocks:            2,
+		SigningBatchConcurrencyLimit:           1,
+	}
+
+	// DeveloperProtocolTiming is the protocol timing profile for local
+	// development networks that usually mine a block every second or so.
+	// The protocol phases span more blocks to give the protocols a comparable
+	// amount of time while the confirmation periods are short as chain
+	// reorganizations do not happen.
+	DeveloperProtocolTiming = ProtocolTiming{
+		DkgStartedConfirmationBlocks:           5,
+		DkgResultSubmissionDelayStepBlocks:     5,
+		DkgResultApprovalDelayStepBlocks:       5,
+		DkgResultChallengeConfirmationBlocks:   5,
+		DkgAttemptAnnouncementDelayBlocks:      2,
+		DkgAttemptAnnouncementActiveBlocks:     10,
+		DkgAttemptMaximumProtocolBlocks:        400,
+		DkgAttemptCoolDownBlocks:               10,
+		SigningAttemptAnnouncementDelayBlocks:  2,
+		SigningAttemptAnnouncementActiveBlocks: 10,
+		SigningAttemptMaximumProtocolBlocks:    150,
+		SigningAttemptCoolDownBlocks:           10,
+		SigningBatchInterludeBlocks:            5,
+		SigningBatchConcurrencyLimit:           1,
+	}
+)
+
+// ProtocolTimingConfig holds the protocol timing values configured by the
+// operator. Each field corresponds to the ProtocolTiming field of the same
+// name. A nil field is not set and its value is taken from the protocol
+// timing profile, while a field explicitly set to zero is kept as zero.
+type ProtocolTimingConfig struct {
+	DkgStartedConfirmationBlocks           *uint64
+	DkgResultSubmissionDelayStepBlocks     *uint64
+	DkgResultApprovalDelayStepBlocks       *uint64
+	DkgResultChallengeConfirmationBlocks   *uint64
+	DkgAttemptAnnouncementDelayBlocks      *uint64
+	DkgAttemptAnnouncementActiveBlocks     *uint64
+	DkgAttemptMaximumProtocolBlocks        *uint64
+	DkgAttemptCoolDownBlocks               *uint64
+	SigningAttemptAnnouncementDelayBlocks  *uint64
+	SigningAttemptAnnouncementActiveBlocks *uint64
+	SigningAttemptMaximumProtocolBlocks    *uint64
+	SigningAttemptCoolDownBlocks           *uint64
+	SigningBatchInterludeBlocks            *uint64
+	SigningBatchConcurrencyLimit           *uint64
+	PresigningIntervalBlocks               *uint64
+}
+
+// WithDefaults returns the protocol timing using the configured values and
+// taking all unset values from the given defaults.
+func (ptc ProtocolTimingConfig) WithDefaults(
+	defaults ProtocolTiming,
+) ProtocolTiming {
+	orDefault := func(value *uint64, defaultValue uint64) uint64 {
+		if value == nil {
+			return defaultValue
+		}
+		return *value
+	}
+
+	return ProtocolTiming{
+		DkgStartedConfirmationBlocks: orDefault(
+			ptc.DkgStartedConfirmationBlocks,
+			defaults.DkgStartedConfirmationBlocks,
+		),
+		DkgResultSubmissionDelayStepBlocks: orDefault(
+			ptc.DkgResultSubmissionDelayStepBlocks,
+			defaults.DkgResultSubmissionDelayStepBlocks,
+		),
+		DkgResultApprovalDelayStepBlocks: orDefault(
+			ptc.DkgResultApprovalDelayStepBlocks,
+			defaults.DkgResultApprovalDelayStepBlocks,
+		),
+		DkgResultChallengeConfirmationBlocks: orDefault(
+			ptc.DkgResultChallengeConfirmationBlocks,
+			defaults.DkgResultChallengeConfirmationBlocks,
+		),
+		DkgAttemptAnnouncementDelayBlocks: orDefault(
+			ptc.DkgAttemptAnnouncementDelayBlocks,
+			defaults.DkgAttemptAnnouncementDelayBlocks,
+		),
+		DkgAttemptAnnouncementActiveBlocks: orDefault(
+			ptc.DkgAttemptAnnouncementActiveBlocks,
+			defaults.DkgAttemptAnnouncementActiveBlocks,
+		),
+		DkgAttemptMaximumProtocolBlocks: orDefault(
+			ptc.DkgAttemptMaximumProtocolBlocks,
+			defaults.DkgAttemptMaximumProtocolBlocks,
+		),
+		DkgAttemptCoolDownBlocks: orDefault(
+			ptc.DkgAttemptCoolDownBlocks,
+			defaults.DkgAttemptCoolDownBlocks,
+		),
+		SigningAttemptAnnouncementDelayBlocks: orDefault(
+			ptc.SigningAttemptAnnouncementDelayBlocks,
+			defaults.SigningAttemptAnnouncementDelayBlocks,
+		),
+		SigningAttemptAnnouncementActiveBlocks: orDefault(
+			ptc.SigningAttemptAnnouncementActiveBlocks,
+			defaults.SigningAttemptAnnouncementActiveBlocks,
+		),
+		SigningAttemptMaximumProtocolBlocks: orDefault(
+			ptc.SigningAttemptMaximumProtocolBlocks,
+			defaults.SigningAttemptMaximumProtocolBlocks,
+		),
+		SigningAttemptCoolDownBlocks: orDefault(
+			ptc.SigningAttemptCoolDownBlocks,
+			defaults.SigningAttemptCoolDownBlocks,
+		),
+		SigningBatchInterludeBlocks: orDefault(
+			ptc.SigningBatchInterludeBlocks,
+			defaults.SigningBatchInterludeBlocks,
+		),
+		SigningBatchConcurrencyLimit: orDefault(
+			ptc.SigningBatchConcurrencyLimit,
+			defaults.SigningBatchConcurrencyLimit,
+		),
+		PresigningIntervalBlocks: orDefault(
+			ptc.PresigningIntervalBlocks,
+			defaults.PresigningIntervalBlocks,
+		),
+	}
+}
+
+// Config returns the protocol timing config with all values set to the
+// values of the protocol timing.
+func (pt ProtocolTiming) Config() ProtocolTimingConfig {
+	return ProtocolTimingConfig{
+		DkgStartedConfirmationBlocks:           &pt.DkgStartedConfirmationBlocks,
+		DkgResultSubmissionDelayStepBlocks:     &pt.DkgResultSubmissionDelayStepBlocks,
+		DkgResultApprovalDelayStepBlocks:       &pt.DkgResultApprovalDelayStepBlocks,
+		DkgResultChallengeConfirmationBlocks:   &pt.DkgResultChallengeConfirmationBlocks,
+		DkgAttemptAnnouncementDelayBlocks:      &pt.DkgAttemptAnnouncementDelayBlocks,
+		DkgAttemptAnnouncementActiveBlocks:     &pt.DkgAttemptAnnouncementActiveBlocks,
+		DkgAttemptMaximumProtocolBlocks:        &pt.DkgAttemptMaximumProtocolBlocks,
+		DkgAttemptCoolDownBlocks:               &pt.DkgAttemptCoolDownBlocks,
+		SigningAttemptAnnouncementDelayBlocks:  &pt.SigningAttemptAnnouncementDelayBlocks,
+		SigningAttemptAnnouncementActiveBlocks: &pt.SigningAttemptAnnouncementActiveBlocks,
+		SigningAttemptMaximumProtocolBlocks:    &pt.SigningAttemptMaximumProtocolBlocks,
+		SigningAttemptCoolDownBlocks:           &pt.SigningAttemptCoolDownBlocks,
+		SigningBatchInterludeBlocks:            &pt.SigningBatchInterludeBlocks,
+		SigningBatchConcurrencyLimit:           &pt.SigningBatchConcurrencyLimit,
+		PresigningIntervalBlocks:               &pt.PresigningIntervalBlocks,
+	}
+}
+
+// dkgAttemptMaximumBlocks returns the maximum block duration of a single
+// DKG attempt.
+func (pt *ProtocolTiming) dkgAttemptMaximumBlocks() uint64 {
+	return pt.DkgAttemptAnnouncementDelayBlocks +
+		pt.DkgAttemptAnnouncementActiveBlocks +
+		pt.DkgAttemptMaximumProtocolBlocks +
+		pt.DkgAttemptCoolDownBlocks
+}
+
+// signingAttemptMaximumBlocks returns the maximum block duration of a single
+// signing attempt.
+func (pt *ProtocolTiming) signingAttemptMaximumBlocks() uint64 {
+	return pt.SigningAttemptAnnouncementDelayBlocks +
+		pt.SigningAttemptAnnouncementActiveBlocks +
+		pt.SigningAttemptMaximumProtocolBlocks +
+		pt.SigningAttemptCoolDownBlocks
+}
+
+// validate checks whether the protocol timing can be used with the given
+// on-chain DKG parameters. The DKG started confirmation period along with
+// at least one full DKG attempt must fit before the DKG result submission
+// timeout.
+func (pt *ProtocolTiming) validate(dkgParameters *DKGParameters) error {
+	if pt.DkgAttemptAnnouncementActiveBlocks == 0 ||
+		pt.DkgAttemptMaximumProtocolBlocks == 0 {
+		return fmt.Errorf(
+			"DKG attempt announcement and protocol phases must not be empty",
+		)
+	}
+
+	if pt.SigningAttemptAnnouncementActiveBlocks == 0 ||
+		pt.SigningAttemptMaximumProtocolBlocks == 0 {
+		return fmt.Errorf(
+			"signing attempt announcement and protocol phases must not be empty",
+		)
+	}
+
+	if pt.PresigningIntervalBlocks != 0 &&
+		pt.PresigningIntervalBlocks <= pt.signingAttemptMaximumBlocks() {
+		return fmt.Errorf(
+			"presigning interval of [%v] blocks must be longer than "+
+				"a single signing attempt taking [%v] blocks",
+			pt.PresigningIntervalBlocks,
+			pt.signingAttemptMaximumBlocks(),
+		)
+	}
+
+	dkgBlocks := pt.DkgStartedConfirmationBlocks + pt.dkgAttemptMaximumBlocks()
+	if dkgBlocks > dkgParameters.SubmissionTimeoutBlocks {
+		return fmt.Errorf(
+			"DKG started confirmation and a single DKG attempt take [%v] "+
+				"blocks which exceeds the DKG result submission timeout "+
+				"of [%v] blocks",
+			dkgBlocks,
+			dkgParameters.SubmissionTimeoutBlocks,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package tbtc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+func TestProtocolTimingConfig_WithDefaults(t *testing.T) {
+	dkgStartedConfirmationBlocks := uint64(3)
+	signingAttemptMaximumProtocolBlocks := uint64(7)
+	signingBatchInterludeBlocks := uint64(0)
+
+	timing := ProtocolTimingConfig{
+		DkgStartedConfirmationBlocks:        &dkgStartedConfirmationBlocks,
+		SigningAttemptMaximumProtocolBlocks: &signingAttemptMaximumProtocolBlocks,
+		// Values explicitly set to zero are kept.
+		SigningBatchInterludeBlocks: &signingBatchInterludeBlocks,
+	}
+
+	expectedTiming := MainnetProtocolTiming
+	expectedTiming.DkgStartedConfirmationBlocks = 3
+	expectedTiming.SigningAttemptMaximumProtocolBlocks = 7
+	expectedTiming.SigningBatchInterludeBlocks = 0
+
+	actualTiming := timing.WithDefaults(MainnetProtocolTiming)
+
+	if !reflect.DeepEqual(expectedTiming, actualTiming) {
+		t.Errorf(
+			"unexpected protocol timing\n"+
+				"expected: %+v\n"+
+				"actual:   %+v\n",
+			expectedTiming,
+			actualTiming,
+		)
+	}
+}
+
+func TestProtocolTiming_Config(t *testing.T) {
+	// All values are set in the config so the defaults are not used.
+	actualTiming := DeveloperProtocolTiming.Config().WithDefaults(
+		MainnetProtocolTiming,
+	)
+
+	if !reflect.DeepEqual(DeveloperProtocolTiming, actualTiming) {
+		t.Errorf(
+			"unexpected protocol timing\n"+
+				"expected: %+v\n"+
+				"actual:   %+v\n",
+			DeveloperProtocolTiming,
+			actualTiming,
+		)
+	}
+}
+
+func TestProtocolTiming_AttemptMaximumBlocks(t *testing.T) {
+	testutils.AssertIntsEqual(
+		t,
+		"DKG attempt maximum blocks",
+		161,
+		int(MainnetProtocolTiming.dkgAttemptMaximumBlocks()),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"signing attempt maximum blocks",
+		41,
+		int(MainnetProtocolTiming.signingAttemptMaximumBlocks()),
+	)
+}
+
+func TestProtocolTiming_Validate(t *testing.T) {
+	emptyDkgProtocolTiming := MainnetProtocolTiming
+	emptyDkgProtocolTiming.DkgAttemptMaximumProtocolBlocks = 0
+
+	emptySigningAnnouncementTiming := MainnetProtocolTiming
+	emptySigningAnnouncementTiming.SigningAttemptAnnouncementActiveBlocks = 0
+
+	presigningTiming := MainnetProtocolTiming
+	presigningTiming.PresigningIntervalBlocks = 42
+
+	tooShortPresigningTiming := MainnetProtocolTiming
+	tooShortPresigningTiming.PresigningIntervalBlocks = 41
+
+	var tests = map[string]struct {
+		protocolTiming          ProtocolTiming
+		submissionTimeoutBlocks uint64
+		expectedError           error
+	}{
+		"mainnet profile": {
+			protocolTiming:          MainnetProtocolTiming,
+			submissionTimeoutBlocks: 536,
+		},
+		"testnet profile": {
+			protocolTiming:          TestnetProtocolTiming,
+			submissionTimeoutBlocks: 536,
+		},
+		"developer profile": {
+			protocolTiming:          DeveloperProtocolTiming,
+			submissionTimeoutBlocks: 536,
+		},
+		"DKG attempt exactly fitting submission timeout": {
+			protocolTiming:          MainnetProtocolTiming,
+			submissionTimeoutBlocks: 181,
+		},
+		"DKG attempt exceeding submission timeout": {
+			protocolTiming:          MainnetProtocolTiming,
+			submissionTimeoutBlocks: 180,
+			expectedError: fmt.Errorf(
+				"DKG started confirmation and a single DKG attempt take " +
+					"[181] blocks which exceeds the DKG result submission " +
+					"timeout of [180] blocks",
+			),
+		},
+		"empty DKG protocol phase": {
+			protocolTiming:          emptyDkgProtocolTiming,
+			submissionTimeoutBlocks: 536,
+			expectedError: fmt.Errorf(
+				"DKG attempt announcement and protocol phases must not be empty",
+			),
+		},
+		"empty signing announcement phase": {
+			protocolTiming:          emptySigningAnnouncementTiming,
+			submissionTimeoutBlocks: 536,
+			expectedError: fmt.Errorf(
+				"signing attempt announcement and protocol phases must not be empty",
+			),
+		},
+		"presigning interval longer than signing attempt": {
+			protocolTiming:          presigningTiming,
+			submissionTimeoutBlocks: 536,
+		},
+		"presigning interval not longer than signing attempt": {
+			protocolTiming:          tooShortPresigningTiming,
+			submissionTimeoutBlocks: 536,
+			expectedError: fmt.Errorf(
+				"presigning interval of [41] blocks must be longer than " +
+					"a single signing attempt taking [41] blocks",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := test.protocolTiming.validate(&DKGParameters{
+				SubmissionTimeoutBlocks: test.submissionTimeoutBlocks,
+			})
+
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected error\n"+
+						"expected: %v\n"+
+						"actual:   %v\n",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
@@ -1,14 +1,39 @@
 package tbtc
 
 import (
+	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
 	"github.com/keep-network/keep-core/pkg/chain"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 	"github.com/keep-network/keep-core/pkg/tecdsa"
 )
 
+const (
+	// transactionBroadcastAttempts determines the maximum number of attempts
+	// made to broadcast a signed wallet transaction over the Bitcoin network.
+	transactionBroadcastAttempts = 5
+	// transactionBroadcastBackoff determines the delay between subsequent
+	// attempts to broadcast a signed wallet transaction.
+	transactionBroadcastBackoff = 10 * time.Second
+	// transactionSatPerVByteFee determines the sat/vbyte fee rate of wallet
+	// transactions whose fee is not set by a proposal. All wallet signers
+	// must assemble the very same transaction so, they cannot use fee rates
+	// estimated by their own Bitcoin backends.
+	transactionSatPerVByteFee = 20
+	// transactionMinSatPerVByteFee determines the minimum sat/vbyte fee rate
+	// of wallet transactions. Transactions paying less are not relayed by
+	// Bitcoin nodes using the default policy.
+	transactionMinSatPerVByteFee = 1
+)
+
 // wallet represents a tBTC wallet. A wallet is one of the basic building
 // blocks of the system that takes BTC under custody during the deposit
 // process and gives that BTC back during redemptions.
@@ -30,6 +55,10 @@ type wallet struct {
 	// incremented by one (e.g. element with index 0 has the group.MemberIndex
 	// equal to 1 and so on).
 	signingGroupOperators []chain.Address
+	// groupParameters are the group parameters the wallet was created with.
+	// They are not affected by on-chain updates of the group parameters that
+	// apply only to wallets created by subsequent DKGs.
+	groupParameters *GroupParameters
 }
 
 // groupSize returns the actual size of the wallet's signing group. This
@@ -41,9 +70,9 @@ func (w *wallet) groupSize() int {
 
 // groupDishonestThreshold returns the dishonest threshold for the wallet's
 // signing group. The returned value is computed using the wallet's actual
-// signing group size for the given honest threshold provided as argument.
-func (w *wallet) groupDishonestThreshold(honestThreshold int) int {
-	return w.groupSize() - honestThreshold
+// signing group size and the honest threshold the wallet was created with.
+func (w *wallet) groupDishonestThreshold() int {
+	return w.groupSize() - w.groupParameters.HonestThreshold
 }
 
 func (w *wallet) String() string {
@@ -85,12 +114,14 @@ type signer struct {
 func newSigner(
 	walletPublicKey *ecdsa.PublicKey,
 	walletSigningGroupOperators []chain.Address,
+	walletGroupParameters *GroupParameters,
 	signingGroupMemberIndex group.MemberIndex,
 	privateKeyShare *tecdsa.PrivateKeyShare,
 ) *signer {
 	wallet := wallet{
 		publicKey:             walletPublicKey,
 		signingGroupOperators: walletSigningGroupOperators,
+		groupParameters:       walletGroupParameters,
 	}
 
 	return &signer{
@@ -107,3 +138,401 @@ func (s *signer) String() string {
 		&s.wallet,
 	)
 }
+
+// validate checks whether the signer is consistent with its wallet. The
+// signer must occupy a seat within the wallet's signing group and its private
+// key share must be internally consistent and correspond to the wallet
+// public key.
+func (s *signer) validate() error {
+	memberIndex := int(s.signingGroupMemberIndex)
+	if memberIndex < 1 || memberIndex > s.wallet.groupSize() {
+		return fmt.Errorf(
+			"member index out of the signing group range [1, %v]",
+			s.wallet.groupSize(),
+		)
+	}
+
+	if err := s.privateKeyShare.Validate(); err != nil {
+		return fmt.Errorf("inconsistent private key share: [%w]", err)
+	}
+
+	if !s.privateKeyShare.PublicKey().Equal(s.wallet.publicKey) {
+		return fmt.Errorf(
+			"private key share does not reconstruct the wallet public key",
+		)
+	}
+
+	return nil
+}
+
+// walletTransactionExecutor is a component allowing to sign and broadcast
+// Bitcoin transactions performed by the given wallet.
+type walletTransactionExecutor struct {
+	btcChain        bitcoin.Chain
+	executingWallet wallet
+	signingExecutor *signingExecutor
+}
+
+func newWalletTransactionExecutor(
+	btcChain bitcoin.Chain,
+	executingWallet wallet,
+	signingExecutor *signingExecutor,
+) *walletTransactionExecutor {
+	return &walletTransactionExecutor{
+		btcChain:        btcChain,
+		executingWallet: executingWallet,
+		signingExecutor: signingExecutor,
+	}
+}
+
+// signTransaction signs all inputs of the given unsigned transaction using
+// the wallet's signing executor. The signing process starts at the given
+// block. If all inputs were signed successfully, the signed transaction is
+// returned.
+func (wte *walletTransactionExecutor) signTransaction(
+	ctx context.Context,
+	unsignedTx *bitcoin.TransactionBuilder,
+	signingStartBlock uint64,
+) (*bitcoin.Transaction, error) {
+	sigHashes, err := unsignedTx.ComputeSignatureHashes()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot compute transaction's sighashes: [%v]",
+			err,
+		)
+	}
+
+	signatures, err := wte.signingExecutor.signBatch(
+		ctx,
+		sigHashes,
+		signingStartBlock,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign transaction's sighashes: [%w]", err)
+	}
+
+	containers := make([]*bitcoin.SignatureContainer, len(signatures))
+	for i, signature := range signatures {
+		containers[i] = &bitcoin.SignatureContainer{
+			R:         signature.R,
+			S:         signature.S,
+			PublicKey: wte.executingWallet.publicKey,
+		}
+	}
+
+	transaction, err := unsignedTx.AddSignatures(containers)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot add signatures to the transaction: [%v]",
+			err,
+		)
+	}
+
+	return transaction, nil
+}
+
+// broadcastTransaction broadcasts the given signed transaction over the
+// Bitcoin network. The broadcast is retried a limited number of times in
+// case of failures.
+func (wte *walletTransactionExecutor) broadcastTransaction(
+	ctx context.Context,
+	transaction *bitcoin.Transaction,
+) error {
+	var lastErr error
+
+	for attempt := 1; attempt <= transactionBroadcastAttempts; attempt++ {
+		lastErr = wte.btcChain.BroadcastTransaction(transaction)
+		if lastErr == nil {
+			return nil
+		}
+
+		logger.Warnf(
+			"broadcast attempt [%v/%v] of transaction [%s] failed: [%v]",
+			attempt,
+			transactionBroadcastAttempts,
+			transaction.Hash().Hex(bitcoin.ReversedByteOrder),
+			lastErr,
+		)
+
+		select {
+		case <-time.After(transactionBroadcastBackoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf(
+		"all broadcast attempts failed; last error: [%v]",
+		lastErr,
+	)
+}
+
+// walletTransactionFee computes the fee of a wallet transaction having the
+// given virtual size, using the fixed transactionSatPerVByteFee fee rate.
+// The fee is clamped to the given maximum fee that is determined by the
+// Bridge parameters. The virtual size is computed from the transaction
+// itself so all wallet signers get the same fee.
+func walletTransactionFee(virtualSize int64, maxFee int64) int64 {
+	fee := virtualSize * transactionSatPerVByteFee
+	if fee > maxFee {
+		return maxFee
+	}
+
+	return fee
+}
+
+// validateWalletTransactionFee checks whether the given fee of a wallet
+// transaction having the given virtual size pays at least the minimum
+// fee rate relayed by Bitcoin nodes.
+func validateWalletTransactionFee(virtualSize int64, fee int64) error {
+	minFee := virtualSize * transactionMinSatPerVByteFee
+	if fee < minFee {
+		return fmt.Errorf(
+			"transaction fee [%v] is below the minimum relay fee [%v] "+
+				"for virtual size [%v]",
+			fee,
+			minFee,
+			virtualSize,
+		)
+	}
+
+	return nil
+}
+
+// determineWalletMainUtxo determines the plain-text wallet main UTXO
+// currently registered in the Bridge on-chain contract. The returned
+// main UTXO can be nil if the wallet does not have a main UTXO registered
+// in the Bridge at the moment.
+//
+// The main UTXO is determined by walking back through Bitcoin transactions
+// whose proofs were accepted by the Bridge, starting from the most recent
+// one. Those are deposit sweep, redemption, and moved funds sweep
+// transactions as only they can produce a new main UTXO of the wallet.
+// Each output locked to the wallet's public key hash is compared against
+// the main UTXO hash stored on-chain. Relying on the Bridge events makes
+// the lookup immune to arbitrary transfers sent to the wallet's address.
+//
+// The determined main UTXO is kept in the given cache. If the on-chain main
+// UTXO hash did not change since then, the cached main UTXO is returned
+// without looking at the Bridge events. Otherwise, only transactions proven
+// since the cached one are taken into account. The cache can be nil.
+func determineWalletMainUtxo(
+	walletPublicKeyHash [20]byte,
+	bridgeChain BridgeChain,
+	btcChain bitcoin.Chain,
+	cache *walletMainUtxoCache,
+) (*bitcoin.UnspentTransactionOutput, error) {
+	walletChainData, err := bridgeChain.GetWallet(walletPublicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get on-chain data of wallet: [%v]", err)
+	}
+
+	// Valid case when the wallet doesn't have a main UTXO registered into
+	// the Bridge.
+	if walletChainData.MainUtxoHash == [32]byte{} {
+		return nil, nil
+	}
+
+	startBlock := uint64(0)
+	if cached := cache.get(walletPublicKeyHash); cached != nil {
+		if cached.mainUtxoHash == walletChainData.MainUtxoHash {
+			return cached.mainUtxo, nil
+		}
+
+		// A new main UTXO can be produced only by a transaction proven
+		// no earlier than the one producing the cached main UTXO.
+		startBlock = cached.proofBlock
+	}
+
+	transactions, err := getWalletTransactionsProvenByBridge(
+		walletPublicKeyHash,
+		startBlock,
+		bridgeChain,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p2pkh, err := bitcoin.PayToPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute P2PKH script: [%v]", err)
+	}
+
+	p2wpkh, err := bitcoin.PayToWitnessPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute P2WPKH script: [%v]", err)
+	}
+
+	// The most recent transaction is the most likely to hold the current
+	// main UTXO so, start from the end.
+	for i := len(transactions) - 1; i >= 0; i-- {
+		transactionHash := transactions[i].hash
+
+		transaction, err := btcChain.GetTransaction(transactionHash)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot get transaction [%s]: [%v]",
+				transactionHash.Hex(bitcoin.ReversedByteOrder),
+				err,
+			)
+		}
+
+		for outputIndex, output := range transaction.Outputs {
+			script := output.PublicKeyScript
+
+			if !bytes.Equal(script, p2pkh) && !bytes.Equal(script, p2wpkh) {
+				continue
+			}
+
+			utxo := &bitcoin.UnspentTransactionOutput{
+				Outpoint: &bitcoin.TransactionOutpoint{
+					TransactionHash: transactionHash,
+					OutputIndex:     uint32(outputIndex),
+				},
+				Value: output.Value,
+			}
+
+			if bridgeChain.ComputeMainUtxoHash(utxo) ==
+				walletChainData.MainUtxoHash {
+				cache.put(walletPublicKeyHash, &cachedWalletMainUtxo{
+					mainUtxoHash: walletChainData.MainUtxoHash,
+					mainUtxo:     utxo,
+					proofBlock:   transactions[i].blockNumber,
+				})
+
+				return utxo, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"main UTXO not found among outputs of [%v] wallet transactions",
+		len(transactions),
+	)
+}
+
+// walletTransaction is a Bitcoin transaction of a wallet whose proof was
+// accepted by the Bridge at the given block.
+type walletTransaction struct {
+	hash        bitcoin.Hash
+	blockNumber uint64
+}
+
+// walletMainUtxoCache holds the last main UTXO determined for each wallet.
+// Methods of the cache are safe to call on a nil cache, which holds nothing.
+type walletMainUtxoCache struct {
+	mutex   sync.Mutex
+	entries map[[20]byte]*cachedWalletMainUtxo
+}
+
+// cachedWalletMainUtxo is the main UTXO of a wallet held in the cache.
+type cachedWalletMainUtxo struct {
+	// mainUtxoHash is the main UTXO hash registered in the Bridge.
+	mainUtxoHash [32]byte
+	mainUtxo     *bitcoin.UnspentTransactionOutput
+	// proofBlock is the block at which the Bridge accepted the proof of the
+	// transaction producing the main UTXO.
+	proofBlock uint64
+}
+
+func newWalletMainUtxoCache() *walletMainUtxoCache {
+	return &walletMainUtxoCache{
+		entries: make(map[[20]byte]*cachedWalletMainUtxo),
+	}
+}
+
+// get returns the cached main UTXO of the given wallet or nil if there is
+// none.
+func (wmuc *walletMainUtxoCache) get(
+	walletPublicKeyHash [20]byte,
+) *cachedWalletMainUtxo {
+	if wmuc == nil {
+		return nil
+	}
+
+	wmuc.mutex.Lock()
+	defer wmuc.mutex.Unlock()
+
+	return wmuc.entries[walletPublicKeyHash]
+}
+
+// put caches the main UTXO of the given wallet.
+func (wmuc *walletMainUtxoCache) put(
+	walletPublicKeyHash [20]byte,
+	entry *cachedWalletMainUtxo,
+) {
+	if wmuc == nil {
+		return
+	}
+
+	wmuc.mutex.Lock()
+	defer wmuc.mutex.Unlock()
+
+	wmuc.entries[walletPublicKeyHash] = entry
+}
+
+// getWalletTransactionsProvenByBridge returns deposit sweep, redemption, and
+// moved funds sweep transactions of the given wallet whose proofs were
+// accepted by the Bridge at or after the given start block. Returned
+// transactions are sorted by the block number of the proof in the ascending
+// order, i.e. the latest transaction is at the end of the slice.
+func getWalletTransactionsProvenByBridge(
+	walletPublicKeyHash [20]byte,
+	startBlock uint64,
+	bridgeChain BridgeChain,
+) ([]*walletTransaction, error) {
+	filter := &WalletTransactionEventFilter{
+		StartBlock:          startBlock,
+		WalletPublicKeyHash: [][20]byte{walletPublicKeyHash},
+	}
+
+	sweepEvents, err := bridgeChain.PastDepositsSweptEvents(filter)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get past deposits swept events: [%v]",
+			err,
+		)
+	}
+
+	redemptionEvents, err := bridgeChain.PastRedemptionsCompletedEvents(filter)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get past redemptions completed events: [%v]",
+			err,
+		)
+	}
+
+	movedFundsSweepEvents, err := bridgeChain.PastMovedFundsSweptEvents(filter)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot get past moved funds swept events: [%v]",
+			err,
+		)
+	}
+
+	transactions := make([]*walletTransaction, 0)
+	for _, event := range sweepEvents {
+		transactions = append(transactions, &walletTransaction{
+			hash:        event.SweepTxHash,
+			blockNumber: event.BlockNumber,
+		})
+	}
+	for _, event := range redemptionEvents {
+		transactions = append(transactions, &walletTransaction{
+			hash:        event.RedemptionTxHash,
+			blockNumber: event.BlockNumber,
+		})
+	}
+	for _, event := range movedFundsSweepEvents {
+		transactions = append(transactions, &walletTransaction{
+			hash:        event.SweepTxHash,
+			blockNumber: event.BlockNumber,
+		})
+	}
+
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].blockNumber < transactions[j].blockNumber
+	})
+
+	return transactions, nil
+}
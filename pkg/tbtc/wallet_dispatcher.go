@@ -0,0 +1,420 @@
+package tbtc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+)
+
+// walletActionType represents the type of action that can be performed
+// by a wallet.
+type walletActionType uint8
+
+const (
+	actionHeartbeat walletActionType = iota
+	actionDepositSweep
+	actionRedemption
+	actionMovingFunds
+)
+
+// walletActionTypes holds all supported wallet action types.
+var walletActionTypes = []walletActionType{
+	actionHeartbeat,
+	actionDepositSweep,
+	actionRedemption,
+	actionMovingFunds,
+}
+
+func (wat walletActionType) String() string {
+	switch wat {
+	case actionHeartbeat:
+		return "heartbeat"
+	case actionDepositSweep:
+		return "deposit_sweep"
+	case actionRedemption:
+		return "redemption"
+	case actionMovingFunds:
+		return "moving_funds"
+	default:
+		return fmt.Sprintf("unknown_%v", uint8(wat))
+	}
+}
+
+const (
+	// walletActionQueueCapacity determines the maximum number of actions
+	// that can wait for execution by a single wallet. Actions dispatched
+	// once the queue is full are rejected.
+	walletActionQueueCapacity = 32
+
+	// heartbeatActionTimeout is the maximum time a heartbeat action can take.
+	// A heartbeat consists of several messages signed one after another so,
+	// the timeout must give enough time to retry signing of some of them.
+	heartbeatActionTimeout = 4 * time.Hour
+	// depositSweepActionTimeout is the maximum time a deposit sweep action
+	// can take. It covers the proposal confirmation period and signing of
+	// all sweep transaction inputs.
+	depositSweepActionTimeout = 5 * time.Hour
+	// redemptionActionTimeout is the maximum time a redemption action can
+	// take. It covers waiting for the redemption round start and signing of
+	// the single redemption transaction input.
+	redemptionActionTimeout = 90 * time.Minute
+	// movingFundsActionTimeout is the maximum time a moving funds action can
+	// take. It covers the confirmation period and signing of the single
+	// moving funds transaction input.
+	movingFundsActionTimeout = 90 * time.Minute
+)
+
+// errWalletActionQueueFull is an error returned when the wallet action
+// cannot be dispatched because the wallet's action queue is full.
+var errWalletActionQueueFull = fmt.Errorf("wallet action queue is full")
+
+// walletAction represents an action that can be performed by a wallet.
+type walletAction interface {
+	// execute executes the action. The given context is cancelled once the
+	// action's timeout elapses.
+	execute(ctx context.Context) error
+
+	// wallet returns the wallet performing the action.
+	wallet() wallet
+
+	// actionType returns the type of the action.
+	actionType() walletActionType
+
+	// startBlock returns the block at which signers of the wallet start
+	// executing the action. All signers must start at the same block so,
+	// an action picked from the queue too long after its start block is
+	// dropped instead of being executed.
+	startBlock() uint64
+}
+
+// coalescingWalletAction is a wallet action that can absorb subsequently
+// dispatched actions of the same wallet while it is waiting for execution
+// or is being executed.
+type coalescingWalletAction interface {
+	walletAction
+
+	// coalesce merges the given action into this one. It returns true if the
+	// given action was merged and does not need to be executed separately.
+	// It is called while this action waits in the queue or is being
+	// executed so, it must be safe to call concurrently with execute and
+	// must return true only if this action handles the given one even if
+	// its execution has already started.
+	coalesce(other walletAction) bool
+}
+
+// walletActionOutcome represents the outcome of an executed wallet action.
+type walletActionOutcome struct {
+	actionType walletActionType
+	startedAt  time.Time
+	finishedAt time.Time
+	// err is the error returned by the action or nil if the action
+	// completed successfully.
+	err error
+}
+
+// queuedWalletAction is a wallet action waiting for execution along with
+// the context it was dispatched with.
+type queuedWalletAction struct {
+	ctx    context.Context
+	action walletAction
+}
+
+// walletDispatcher is a component responsible for executing wallet actions.
+// The dispatcher maintains a queue of actions for each wallet and makes sure
+// only one action is executed at a time by the given wallet. Actions of
+// the given wallet are executed in the order they were dispatched. Actions
+// of different wallets are executed concurrently.
+type walletDispatcher struct {
+	mutex sync.Mutex
+	// queues holds actions waiting for execution, per wallet.
+	queues map[[20]byte][]*queuedWalletAction
+	// running holds actions being currently executed, per wallet. A wallet
+	// has an entry only if its worker is active. The entry is nil while
+	// the worker does not execute any action.
+	running map[[20]byte]walletAction
+	// lastOutcomes holds the outcome of the last action executed, per wallet.
+	lastOutcomes map[[20]byte]*walletActionOutcome
+	// succeededCounts and failedCounts hold the number of executed actions,
+	// per action type.
+	succeededCounts map[walletActionType]uint
+	failedCounts    map[walletActionType]uint
+
+	// actionTimeouts holds the maximum execution time, per action type.
+	actionTimeouts map[walletActionType]time.Duration
+
+	// currentBlockFn is a function used to get the current block.
+	currentBlockFn func() (uint64, error)
+	// startToleranceBlocks is the number of blocks an action can still be
+	// started after its start block. Signers starting later would miss
+	// the first signing attempt announced by the rest of the wallet.
+	startToleranceBlocks uint64
+}
+
+func newWalletDispatcher(
+	currentBlockFn func() (uint64, error),
+	startToleranceBlocks uint64,
+) *walletDispatcher {
+	return &walletDispatcher{
+		queues:          make(map[[20]byte][]*queuedWalletAction),
+		running:         make(map[[20]byte]walletAction),
+		lastOutcomes:    make(map[[20]byte]*walletActionOutcome),
+		succeededCounts: make(map[walletActionType]uint),
+		failedCounts:    make(map[walletActionType]uint),
+		actionTimeouts: map[walletActionType]time.Duration{
+			actionHeartbeat:    heartbeatActionTimeout,
+			actionDepositSweep: depositSweepActionTimeout,
+			actionRedemption:   redemptionActionTimeout,
+			actionMovingFunds:  movingFundsActionTimeout,
+		},
+		currentBlockFn:       currentBlockFn,
+		startToleranceBlocks: startToleranceBlocks,
+	}
+}
+
+// dispatch enqueues the given action for execution by the action's wallet.
+// The action is executed once all actions previously dispatched for the
+// same wallet are done. If the action being executed by the wallet or an
+// action waiting in the wallet's queue can coalesce the given action, the
+// given action is merged into it and is not enqueued. This function does
+// not block until the action is executed.
+// It returns errWalletActionQueueFull if the wallet's queue is full.
+func (wd *walletDispatcher) dispatch(
+	ctx context.Context,
+	action walletAction,
+) error {
+	walletPublicKeyHash := bitcoin.PublicKeyHash(action.wallet().publicKey)
+
+	wd.mutex.Lock()
+	defer wd.mutex.Unlock()
+
+	coalesce := func(target walletAction, targetState string) bool {
+		coalescing, ok := target.(coalescingWalletAction)
+		if !ok || !coalescing.coalesce(action) {
+			return false
+		}
+
+		logger.Infof(
+			"coalesced [%s] action for wallet [0x%x] with "+
+				"an action %s",
+			action.actionType(),
+			walletPublicKeyHash,
+			targetState,
+		)
+
+		return true
+	}
+
+	if running := wd.running[walletPublicKeyHash]; running != nil &&
+		coalesce(running, "being executed") {
+		return nil
+	}
+
+	for _, queued := range wd.queues[walletPublicKeyHash] {
+		if coalesce(queued.action, "waiting in the queue") {
+			return nil
+		}
+	}
+
+	if len(wd.queues[walletPublicKeyHash]) >= walletActionQueueCapacity {
+		return errWalletActionQueueFull
+	}
+
+	wd.queues[walletPublicKeyHash] = append(
+		wd.queues[walletPublicKeyHash],
+		&queuedWalletAction{ctx, action},
+	)
+
+	logger.Infof(
+		"dispatched [%s] action for wallet [0x%x]; "+
+			"[%v] action(s) waiting in the queue",
+		action.actionType(),
+		walletPublicKeyHash,
+		len(wd.queues[walletPublicKeyHash]),
+	)
+
+	if _, active := wd.running[walletPublicKeyHash]; !active {
+		// Mark the worker as active right away so subsequent dispatches
+		// do not start another one.
+		wd.running[walletPublicKeyHash] = nil
+		go wd.work(walletPublicKeyHash)
+	}
+
+	return nil
+}
+
+// work executes queued actions of the given wallet one by one until the
+// wallet's queue is empty.
+func (wd *walletDispatcher) work(walletPublicKeyHash [20]byte) {
+	for {
+		wd.mutex.Lock()
+		queue := wd.queues[walletPublicKeyHash]
+		if len(queue) == 0 {
+			delete(wd.queues, walletPublicKeyHash)
+			delete(wd.running, walletPublicKeyHash)
+			wd.mutex.Unlock()
+			return
+		}
+		next := queue[0]
+		wd.queues[walletPublicKeyHash] = queue[1:]
+		wd.running[walletPublicKeyHash] = next.action
+		timeout := wd.actionTimeouts[next.action.actionType()]
+		wd.mutex.Unlock()
+
+		outcome := wd.execute(next.ctx, next.action, timeout)
+
+		wd.record(walletPublicKeyHash, outcome)
+	}
+}
+
+// execute executes the given action within the given timeout and returns
+// the action's outcome.
+func (wd *walletDispatcher) execute(
+	ctx context.Context,
+	action walletAction,
+	timeout time.Duration,
+) *walletActionOutcome {
+	actionLogger := logger.With(
+		zap.String(
+			"wallet",
+			fmt.Sprintf(
+				"0x%x",
+				bitcoin.PublicKeyHash(action.wallet().publicKey),
+			),
+		),
+		zap.String("action", action.actionType().String()),
+	)
+
+	actionCtx, cancelActionCtx := context.WithTimeout(ctx, timeout)
+	defer cancelActionCtx()
+
+	outcome := &walletActionOutcome{
+		actionType: action.actionType(),
+		startedAt:  time.Now(),
+	}
+
+	if err := wd.checkStartBlock(action); err != nil {
+		outcome.err = err
+		outcome.finishedAt = outcome.startedAt
+
+		actionLogger.Errorf("action dropped: [%v]", outcome.err)
+
+		return outcome
+	}
+
+	actionLogger.Infof("starting action with timeout [%v]", timeout)
+
+	outcome.err = action.execute(actionCtx)
+	outcome.finishedAt = time.Now()
+
+	// The action may ignore the context so, make sure the timeout is
+	// reflected in the outcome.
+	if outcome.err == nil && actionCtx.Err() == context.DeadlineExceeded {
+		outcome.err = fmt.Errorf("action timed out after [%v]", timeout)
+	}
+
+	duration := outcome.finishedAt.Sub(outcome.startedAt)
+
+	if outcome.err != nil {
+		actionLogger.Errorf(
+			"action failed after [%v]: [%v]",
+			duration,
+			outcome.err,
+		)
+	} else {
+		actionLogger.Infof("action completed after [%v]", duration)
+	}
+
+	return outcome
+}
+
+// checkStartBlock returns an error if the given action can no longer be
+// started in sync with other signers of the wallet, i.e. the current block
+// is past the action's start block by more than the start tolerance. This
+// happens when the action waited in the queue behind long-running actions.
+func (wd *walletDispatcher) checkStartBlock(action walletAction) error {
+	currentBlock, err := wd.currentBlockFn()
+	if err != nil {
+		return fmt.Errorf("cannot get current block: [%v]", err)
+	}
+
+	latestStartBlock := action.startBlock() + wd.startToleranceBlocks
+	if currentBlock > latestStartBlock {
+		return fmt.Errorf(
+			"start block [%v] has already passed; current block is [%v]",
+			action.startBlock(),
+			currentBlock,
+		)
+	}
+
+	return nil
+}
+
+// record saves the outcome of the action executed by the given wallet.
+// The action is no longer considered as being executed so, it does not
+// coalesce subsequently dispatched actions.
+func (wd *walletDispatcher) record(
+	walletPublicKeyHash [20]byte,
+	outcome *walletActionOutcome,
+) {
+	wd.mutex.Lock()
+	defer wd.mutex.Unlock()
+
+	wd.running[walletPublicKeyHash] = nil
+
+	wd.lastOutcomes[walletPublicKeyHash] = outcome
+
+	if outcome.err != nil {
+		wd.failedCounts[outcome.actionType]++
+	} else {
+		wd.succeededCounts[outcome.actionType]++
+	}
+}
+
+// lastOutcome returns the outcome of the last action executed by the given
+// wallet. The returned bool value is false if the wallet has not executed
+// any action yet.
+func (wd *walletDispatcher) lastOutcome(
+	walletPublicKeyHash [20]byte,
+) (*walletActionOutcome, bool) {
+	wd.mutex.Lock()
+	defer wd.mutex.Unlock()
+
+	outcome, ok := wd.lastOutcomes[walletPublicKeyHash]
+	return outcome, ok
+}
+
+// actionsCount returns the number of executed actions of the given type.
+// The succeeded flag determines whether successful or failed actions are
+// counted.
+func (wd *walletDispatcher) actionsCount(
+	actionType walletActionType,
+	succeeded bool,
+) uint {
+	wd.mutex.Lock()
+	defer wd.mutex.Unlock()
+
+	if succeeded {
+		return wd.succeededCounts[actionType]
+	}
+
+	return wd.failedCounts[actionType]
+}
+
+// queuedActionsCount returns the total number of actions waiting for
+// execution across all wallets.
+func (wd *walletDispatcher) queuedActionsCount() int {
+	wd.mutex.Lock()
+	defer wd.mutex.Unlock()
+
+	count := 0
+	for _, queue := range wd.queues {
+		count += len(queue)
+	}
+
+	return count
+}
@@ -0,0 +1,540 @@
+package tbtc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+func TestWalletDispatcher_SerializesActionsOfSameWallet(t *testing.T) {
+	dispatcher := newTestWalletDispatcher(0)
+	testWallet := generateTestWallet(t)
+
+	recorder := &mockActionRecorder{}
+
+	actionsCount := 5
+	for i := 0; i < actionsCount; i++ {
+		err := dispatcher.dispatch(
+			context.Background(),
+			&mockWalletAction{
+				id:              i,
+				executingWallet: testWallet,
+				recorder:        recorder,
+				duration:        20 * time.Millisecond,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waitForActions(t, dispatcher, actionHeartbeat, actionsCount)
+
+	testutils.AssertIntsEqual(
+		t,
+		"maximum concurrent actions",
+		1,
+		recorder.maxConcurrent,
+	)
+
+	for i, id := range recorder.executedIDs {
+		testutils.AssertIntsEqual(
+			t,
+			fmt.Sprintf("ID of action executed as [%v]", i),
+			i,
+			id,
+		)
+	}
+}
+
+func TestWalletDispatcher_RunsActionsOfDifferentWalletsConcurrently(
+	t *testing.T,
+) {
+	dispatcher := newTestWalletDispatcher(0)
+	// An action blocked on the barrier fails once the timeout elapses.
+	dispatcher.actionTimeouts[actionHeartbeat] = 5 * time.Second
+
+	recorder := &mockActionRecorder{}
+
+	walletsCount := 3
+
+	// Generate wallets upfront so the dispatch loop below is fast.
+	wallets := make([]wallet, walletsCount)
+	for i := range wallets {
+		wallets[i] = generateTestWallet(t)
+	}
+
+	// Each action completes only once all actions are running so all
+	// actions succeed only if they are executed concurrently.
+	barrier := &sync.WaitGroup{}
+	barrier.Add(walletsCount)
+
+	for i, wallet := range wallets {
+		err := dispatcher.dispatch(
+			context.Background(),
+			&mockWalletAction{
+				id:              i,
+				executingWallet: wallet,
+				recorder:        recorder,
+				barrier:         barrier,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waitForActions(t, dispatcher, actionHeartbeat, walletsCount)
+
+	testutils.AssertIntsEqual(
+		t,
+		"succeeded actions",
+		walletsCount,
+		int(dispatcher.actionsCount(actionHeartbeat, true)),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"maximum concurrent actions",
+		walletsCount,
+		recorder.maxConcurrent,
+	)
+}
+
+func TestWalletDispatcher_EnforcesActionTimeout(t *testing.T) {
+	dispatcher := newTestWalletDispatcher(0)
+	dispatcher.actionTimeouts[actionHeartbeat] = 50 * time.Millisecond
+
+	testWallet := generateTestWallet(t)
+
+	err := dispatcher.dispatch(
+		context.Background(),
+		&mockWalletAction{
+			executingWallet: testWallet,
+			recorder:        &mockActionRecorder{},
+			// The action takes longer than the timeout and respects
+			// the context cancellation.
+			duration: time.Minute,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitForActions(t, dispatcher, actionHeartbeat, 1)
+
+	testutils.AssertIntsEqual(
+		t,
+		"succeeded actions",
+		0,
+		int(dispatcher.actionsCount(actionHeartbeat, true)),
+	)
+
+	outcome, ok := dispatcher.lastOutcome(
+		bitcoin.PublicKeyHash(testWallet.publicKey),
+	)
+	if !ok {
+		t.Fatal("expected action outcome")
+	}
+
+	testutils.AssertAnyErrorInChainMatchesTarget(
+		t,
+		context.DeadlineExceeded,
+		outcome.err,
+	)
+}
+
+func TestWalletDispatcher_RejectsActionsWhenQueueFull(t *testing.T) {
+	dispatcher := newTestWalletDispatcher(0)
+	testWallet := generateTestWallet(t)
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	// The first action is picked up by the worker right away so, the queue
+	// can hold walletActionQueueCapacity actions more.
+	for i := 0; i <= walletActionQueueCapacity; i++ {
+		err := dispatcher.dispatch(
+			ctx,
+			&mockWalletAction{
+				id:              i,
+				executingWallet: testWallet,
+				recorder:        &mockActionRecorder{},
+				duration:        time.Minute,
+			},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error for action [%v]: [%v]", i, err)
+		}
+
+		if i == 0 {
+			// Make sure the first action is already running.
+			waitForEmptyQueue(t, dispatcher)
+		}
+	}
+
+	err := dispatcher.dispatch(
+		ctx,
+		&mockWalletAction{
+			executingWallet: testWallet,
+			recorder:        &mockActionRecorder{},
+		},
+	)
+	testutils.AssertErrorsSame(t, errWalletActionQueueFull, err)
+}
+
+func TestWalletDispatcher_CoalescesQueuedActions(t *testing.T) {
+	dispatcher := newTestWalletDispatcher(0)
+	testWallet := generateTestWallet(t)
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	// The first action blocks the wallet so the subsequent ones wait in
+	// the queue.
+	err := dispatcher.dispatch(
+		ctx,
+		&mockWalletAction{
+			executingWallet: testWallet,
+			recorder:        &mockActionRecorder{},
+			duration:        time.Minute,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitForEmptyQueue(t, dispatcher)
+
+	newCoalescingAction := func(id int) *mockCoalescingWalletAction {
+		return &mockCoalescingWalletAction{
+			mockWalletAction: mockWalletAction{
+				id:              id,
+				executingWallet: testWallet,
+				recorder:        &mockActionRecorder{},
+			},
+		}
+	}
+
+	queuedAction := newCoalescingAction(1)
+
+	for _, action := range []walletAction{
+		queuedAction,
+		newCoalescingAction(2),
+		&mockWalletAction{
+			id:              3,
+			executingWallet: testWallet,
+			recorder:        &mockActionRecorder{},
+		},
+		newCoalescingAction(4),
+	} {
+		if err := dispatcher.dispatch(ctx, action); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"queued actions",
+		2,
+		dispatcher.queuedActionsCount(),
+	)
+
+	if !reflect.DeepEqual([]int{2, 4}, queuedAction.coalescedIDs) {
+		t.Errorf(
+			"unexpected coalesced actions\nexpected: %v\nactual:   %v",
+			[]int{2, 4},
+			queuedAction.coalescedIDs,
+		)
+	}
+}
+
+func TestWalletDispatcher_CoalescesRunningAction(t *testing.T) {
+	dispatcher := newTestWalletDispatcher(0)
+	testWallet := generateTestWallet(t)
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	recorder := &mockActionRecorder{}
+
+	newCoalescingAction := func(
+		id int,
+		duration time.Duration,
+	) *mockCoalescingWalletAction {
+		return &mockCoalescingWalletAction{
+			mockWalletAction: mockWalletAction{
+				id:              id,
+				executingWallet: testWallet,
+				recorder:        recorder,
+				duration:        duration,
+			},
+		}
+	}
+
+	runningAction := newCoalescingAction(1, 500*time.Millisecond)
+
+	if err := dispatcher.dispatch(ctx, runningAction); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForEmptyQueue(t, dispatcher)
+
+	// The action is dispatched while the first one is being executed.
+	if err := dispatcher.dispatch(ctx, newCoalescingAction(2, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"queued actions",
+		0,
+		dispatcher.queuedActionsCount(),
+	)
+
+	if !reflect.DeepEqual([]int{2}, runningAction.coalescedIDs) {
+		t.Errorf(
+			"unexpected coalesced actions\nexpected: %v\nactual:   %v",
+			[]int{2},
+			runningAction.coalescedIDs,
+		)
+	}
+
+	waitForActions(t, dispatcher, actionHeartbeat, 1)
+
+	// The first action completed so it no longer coalesces actions.
+	if err := dispatcher.dispatch(ctx, newCoalescingAction(3, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForActions(t, dispatcher, actionHeartbeat, 2)
+
+	if !reflect.DeepEqual([]int{2}, runningAction.coalescedIDs) {
+		t.Errorf(
+			"unexpected coalesced actions\nexpected: %v\nactual:   %v",
+			[]int{2},
+			runningAction.coalescedIDs,
+		)
+	}
+
+	if !reflect.DeepEqual([]int{1, 3}, recorder.executedIDs) {
+		t.Errorf(
+			"unexpected executed actions\nexpected: %v\nactual:   %v",
+			[]int{1, 3},
+			recorder.executedIDs,
+		)
+	}
+}
+
+func TestWalletDispatcher_DropsActionsPastStartBlock(t *testing.T) {
+	dispatcher := newTestWalletDispatcher(100)
+	testWallet := generateTestWallet(t)
+
+	recorder := &mockActionRecorder{}
+
+	// The tolerance is 5 blocks so, the action starting at block 94
+	// cannot be started at block 100 anymore.
+	for i, startBlock := range []uint64{94, 95, 110} {
+		err := dispatcher.dispatch(
+			context.Background(),
+			&mockWalletAction{
+				id:                i,
+				executingWallet:   testWallet,
+				recorder:          recorder,
+				signingStartBlock: startBlock,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waitForActions(t, dispatcher, actionHeartbeat, 3)
+
+	testutils.AssertIntsEqual(
+		t,
+		"failed actions",
+		1,
+		int(dispatcher.actionsCount(actionHeartbeat, false)),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"succeeded actions",
+		2,
+		int(dispatcher.actionsCount(actionHeartbeat, true)),
+	)
+
+	if !reflect.DeepEqual([]int{1, 2}, recorder.executedIDs) {
+		t.Errorf(
+			"unexpected executed actions\nexpected: %v\nactual:   %v",
+			[]int{1, 2},
+			recorder.executedIDs,
+		)
+	}
+}
+
+// newTestWalletDispatcher creates a wallet dispatcher observing the given
+// constant current block and tolerating actions started up to 5 blocks
+// after their start block.
+func newTestWalletDispatcher(currentBlock uint64) *walletDispatcher {
+	return newWalletDispatcher(
+		func() (uint64, error) {
+			return currentBlock, nil
+		},
+		5,
+	)
+}
+
+// waitForEmptyQueue waits until the dispatcher picks up all queued actions.
+func waitForEmptyQueue(t *testing.T, dispatcher *walletDispatcher) {
+	timeout := time.After(10 * time.Second)
+
+	for dispatcher.queuedActionsCount() > 0 {
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("timeout while waiting for empty queue")
+		}
+	}
+}
+
+// waitForActions waits until the given number of actions of the given type
+// are executed by the dispatcher.
+func waitForActions(
+	t *testing.T,
+	dispatcher *walletDispatcher,
+	actionType walletActionType,
+	count int,
+) {
+	timeout := time.After(10 * time.Second)
+
+	for {
+		executed := dispatcher.actionsCount(actionType, true) +
+			dispatcher.actionsCount(actionType, false)
+		if int(executed) >= count {
+			return
+		}
+
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatalf(
+				"timeout while waiting for actions; executed [%v] of [%v]",
+				executed,
+				count,
+			)
+		}
+	}
+}
+
+func generateTestWallet(t *testing.T) wallet {
+	privateKey, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return wallet{publicKey: &privateKey.PublicKey}
+}
+
+type mockActionRecorder struct {
+	mutex         sync.Mutex
+	current       int
+	maxConcurrent int
+	executedIDs   []int
+}
+
+func (mar *mockActionRecorder) start(id int) {
+	mar.mutex.Lock()
+	defer mar.mutex.Unlock()
+
+	mar.current++
+	if mar.current > mar.maxConcurrent {
+		mar.maxConcurrent = mar.current
+	}
+	mar.executedIDs = append(mar.executedIDs, id)
+}
+
+func (mar *mockActionRecorder) finish() {
+	mar.mutex.Lock()
+	defer mar.mutex.Unlock()
+
+	mar.current--
+}
+
+type mockWalletAction struct {
+	id              int
+	executingWallet wallet
+	recorder        *mockActionRecorder
+	duration        time.Duration
+	// signingStartBlock is the start block returned by the action.
+	signingStartBlock uint64
+	// barrier, if set, makes the action complete only once all actions
+	// sharing the barrier are running. The duration is ignored then.
+	barrier *sync.WaitGroup
+}
+
+func (mwa *mockWalletAction) execute(ctx context.Context) error {
+	mwa.recorder.start(mwa.id)
+	defer mwa.recorder.finish()
+
+	if mwa.barrier != nil {
+		mwa.barrier.Done()
+
+		allRunning := make(chan struct{})
+		go func() {
+			mwa.barrier.Wait()
+			close(allRunning)
+		}()
+
+		select {
+		case <-allRunning:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	select {
+	case <-time.After(mwa.duration):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (mwa *mockWalletAction) wallet() wallet {
+	return mwa.executingWallet
+}
+
+func (mwa *mockWalletAction) actionType() walletActionType {
+	return actionHeartbeat
+}
+
+func (mwa *mockWalletAction) startBlock() uint64 {
+	return mwa.signingStartBlock
+}
+
+type mockCoalescingWalletAction struct {
+	mockWalletAction
+	coalescedIDs []int
+}
+
+func (mcwa *mockCoalescingWalletAction) coalesce(other walletAction) bool {
+	otherAction, ok := other.(*mockCoalescingWalletAction)
+	if !ok {
+		return false
+	}
+
+	mcwa.coalescedIDs = append(mcwa.coalescedIDs, otherAction.id)
+
+	return true
+}
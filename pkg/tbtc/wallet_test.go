@@ -0,0 +1,341 @@
+package tbtc
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+
+	"github.com/keep-network/keep-core/pkg/bitcoin"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+func TestDetermineWalletMainUtxo(t *testing.T) {
+	walletPrivateKey, err := ecdsa.GenerateKey(btcec.S256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	walletPublicKeyHash := bitcoin.PublicKeyHash(&walletPrivateKey.PublicKey)
+
+	walletScript, err := bitcoin.PayToWitnessPublicKeyHash(walletPublicKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherScript, err := bitcoin.PayToWitnessPublicKeyHash([20]byte{0xcc})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newTransaction := func(outputs ...*bitcoin.TransactionOutput) *bitcoin.Transaction {
+		return &bitcoin.Transaction{
+			Version: 1,
+			Inputs: []*bitcoin.TransactionInput{
+				{
+					Outpoint: &bitcoin.TransactionOutpoint{
+						TransactionHash: bitcoin.Hash{byte(len(outputs))},
+						OutputIndex:     0,
+					},
+					Sequence: 0xffffffff,
+				},
+			},
+			Outputs: outputs,
+		}
+	}
+
+	// Sweep transaction with a single output.
+	sweepTx := newTransaction(
+		&bitcoin.TransactionOutput{Value: 500000, PublicKeyScript: walletScript},
+	)
+	// Redemption transaction with a redeemer output and a change.
+	redemptionTx := newTransaction(
+		&bitcoin.TransactionOutput{Value: 100000, PublicKeyScript: otherScript},
+		&bitcoin.TransactionOutput{Value: 398000, PublicKeyScript: walletScript},
+	)
+	// Moved funds sweep transaction with a single output.
+	movedFundsSweepTx := newTransaction(
+		&bitcoin.TransactionOutput{Value: 700000, PublicKeyScript: walletScript},
+	)
+	// Arbitrary transfers to the wallet's address that are not wallet
+	// actions and must not be taken into account.
+	dustTransfers := make([]*bitcoin.Transaction, 0)
+	for i := 0; i < 10; i++ {
+		dustTransfers = append(
+			dustTransfers,
+			&bitcoin.Transaction{
+				Version: 1,
+				Inputs: []*bitcoin.TransactionInput{
+					{
+						Outpoint: &bitcoin.TransactionOutpoint{
+							TransactionHash: bitcoin.Hash{0xdd, byte(i)},
+							OutputIndex:     0,
+						},
+						Sequence: 0xffffffff,
+					},
+				},
+				Outputs: []*bitcoin.TransactionOutput{
+					{Value: 546, PublicKeyScript: walletScript},
+				},
+			},
+		)
+	}
+
+	bitcoinChain := newMockBitcoinChain()
+	for _, transaction := range append(
+		[]*bitcoin.Transaction{sweepTx, redemptionTx, movedFundsSweepTx},
+		dustTransfers...,
+	) {
+		if err := bitcoinChain.addTransaction(transaction); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sweepUtxo := &bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{
+			TransactionHash: sweepTx.Hash(),
+			OutputIndex:     0,
+		},
+		Value: 500000,
+	}
+	redemptionChangeUtxo := &bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{
+			TransactionHash: redemptionTx.Hash(),
+			OutputIndex:     1,
+		},
+		Value: 398000,
+	}
+	movedFundsSweepUtxo := &bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{
+			TransactionHash: movedFundsSweepTx.Hash(),
+			OutputIndex:     0,
+		},
+		Value: 700000,
+	}
+	dustUtxo := &bitcoin.UnspentTransactionOutput{
+		Outpoint: &bitcoin.TransactionOutpoint{
+			TransactionHash: dustTransfers[0].Hash(),
+			OutputIndex:     0,
+		},
+		Value: 546,
+	}
+
+	localChain := Connect()
+	localChain.addDepositsSweptEvent(&DepositsSweptEvent{
+		WalletPublicKeyHash: walletPublicKeyHash,
+		SweepTxHash:         sweepTx.Hash(),
+		BlockNumber:         100,
+	})
+	localChain.addRedemptionsCompletedEvent(&RedemptionsCompletedEvent{
+		WalletPublicKeyHash: walletPublicKeyHash,
+		RedemptionTxHash:    redemptionTx.Hash(),
+		BlockNumber:         200,
+	})
+	localChain.addMovedFundsSweptEvent(&MovedFundsSweptEvent{
+		WalletPublicKeyHash: walletPublicKeyHash,
+		SweepTxHash:         movedFundsSweepTx.Hash(),
+		BlockNumber:         300,
+	})
+
+	var tests = map[string]struct {
+		mainUtxoHash       [32]byte
+		cachedMainUtxo     *cachedWalletMainUtxo
+		expectedMainUtxo   *bitcoin.UnspentTransactionOutput
+		expectedProofBlock uint64
+		expectError        bool
+	}{
+		"no main UTXO": {
+			mainUtxoHash:     [32]byte{},
+			expectedMainUtxo: nil,
+		},
+		"main UTXO from the latest transaction": {
+			mainUtxoHash:       localChain.ComputeMainUtxoHash(movedFundsSweepUtxo),
+			expectedMainUtxo:   movedFundsSweepUtxo,
+			expectedProofBlock: 300,
+		},
+		"main UTXO from a redemption transaction": {
+			mainUtxoHash:       localChain.ComputeMainUtxoHash(redemptionChangeUtxo),
+			expectedMainUtxo:   redemptionChangeUtxo,
+			expectedProofBlock: 200,
+		},
+		"main UTXO from a deposit sweep transaction": {
+			mainUtxoHash:       localChain.ComputeMainUtxoHash(sweepUtxo),
+			expectedMainUtxo:   sweepUtxo,
+			expectedProofBlock: 100,
+		},
+		"main UTXO from the cache": {
+			mainUtxoHash: localChain.ComputeMainUtxoHash(dustUtxo),
+			cachedMainUtxo: &cachedWalletMainUtxo{
+				mainUtxoHash: localChain.ComputeMainUtxoHash(dustUtxo),
+				mainUtxo:     dustUtxo,
+				proofBlock:   50,
+			},
+			expectedMainUtxo:   dustUtxo,
+			expectedProofBlock: 50,
+		},
+		"main UTXO proven after the cached one": {
+			mainUtxoHash: localChain.ComputeMainUtxoHash(movedFundsSweepUtxo),
+			cachedMainUtxo: &cachedWalletMainUtxo{
+				mainUtxoHash: localChain.ComputeMainUtxoHash(redemptionChangeUtxo),
+				mainUtxo:     redemptionChangeUtxo,
+				proofBlock:   200,
+			},
+			expectedMainUtxo:   movedFundsSweepUtxo,
+			expectedProofBlock: 300,
+		},
+		"main UTXO proven before the cached one": {
+			mainUtxoHash: localChain.ComputeMainUtxoHash(sweepUtxo),
+			cachedMainUtxo: &cachedWalletMainUtxo{
+				mainUtxoHash: localChain.ComputeMainUtxoHash(redemptionChangeUtxo),
+				mainUtxo:     redemptionChangeUtxo,
+				proofBlock:   200,
+			},
+			expectError: true,
+		},
+		"main UTXO not produced by a wallet action": {
+			mainUtxoHash: localChain.ComputeMainUtxoHash(dustUtxo),
+			expectError:  true,
+		},
+		"main UTXO not found": {
+			mainUtxoHash: [32]byte{0x01},
+			expectError:  true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			localChain.setWallet(walletPublicKeyHash, &WalletChainData{
+				MainUtxoHash: test.mainUtxoHash,
+			})
+
+			cache := newWalletMainUtxoCache()
+			if test.cachedMainUtxo != nil {
+				cache.put(walletPublicKeyHash, test.cachedMainUtxo)
+			}
+
+			mainUtxo, err := determineWalletMainUtxo(
+				walletPublicKeyHash,
+				localChain,
+				bitcoinChain,
+				cache,
+			)
+
+			if test.expectError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(test.expectedMainUtxo, mainUtxo) {
+				t.Errorf(
+					"unexpected main UTXO\nexpected: %v\nactual:   %v\n",
+					test.expectedMainUtxo,
+					mainUtxo,
+				)
+			}
+
+			if test.expectedMainUtxo != nil {
+				cached := cache.get(walletPublicKeyHash)
+				if cached == nil {
+					t.Fatal("main UTXO is not cached")
+				}
+
+				if !reflect.DeepEqual(test.expectedMainUtxo, cached.mainUtxo) {
+					t.Errorf(
+						"unexpected cached main UTXO\nexpected: %v\nactual:   %v\n",
+						test.expectedMainUtxo,
+						cached.mainUtxo,
+					)
+				}
+				testutils.AssertIntsEqual(
+					t,
+					"cached main UTXO proof block",
+					int(test.expectedProofBlock),
+					int(cached.proofBlock),
+				)
+			}
+		})
+	}
+}
+
+func TestWalletTransactionFee(t *testing.T) {
+	var tests = map[string]struct {
+		virtualSize int64
+		maxFee      int64
+		expectedFee int64
+	}{
+		"fee below maximum": {
+			virtualSize: 200,
+			maxFee:      10000,
+			expectedFee: 200 * transactionSatPerVByteFee,
+		},
+		"fee equal to maximum": {
+			virtualSize: 200,
+			maxFee:      200 * transactionSatPerVByteFee,
+			expectedFee: 200 * transactionSatPerVByteFee,
+		},
+		"fee above maximum": {
+			virtualSize: 200,
+			maxFee:      1000,
+			expectedFee: 1000,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			fee := walletTransactionFee(test.virtualSize, test.maxFee)
+
+			testutils.AssertIntsEqual(
+				t,
+				"transaction fee",
+				int(test.expectedFee),
+				int(fee),
+			)
+		})
+	}
+}
+
+func TestValidateWalletTransactionFee(t *testing.T) {
+	var tests = map[string]struct {
+		virtualSize   int64
+		fee           int64
+		expectedError error
+	}{
+		"fee above minimum relay fee": {
+			virtualSize: 200,
+			fee:         1000,
+		},
+		"fee equal to minimum relay fee": {
+			virtualSize: 200,
+			fee:         200 * transactionMinSatPerVByteFee,
+		},
+		"fee below minimum relay fee": {
+			virtualSize: 200,
+			fee:         100,
+			expectedError: fmt.Errorf(
+				"transaction fee [100] is below the minimum relay fee " +
+					"[200] for virtual size [200]",
+			),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := validateWalletTransactionFee(test.virtualSize, test.fee)
+			if !reflect.DeepEqual(test.expectedError, err) {
+				t.Errorf(
+					"unexpected error\n"+
+						"expected: [%v]\n"+
+						"actual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
@@ -21,33 +21,41 @@ type Executor struct {
 	keyGenerationConcurrency int
 }
 
-// NewExecutor creates a new Executor instance.
+// NewExecutor creates a new Executor instance. It returns an error if the
+// stored TSS pre-parameters cannot be read.
 func NewExecutor(
 	logger log.StandardLogger,
 	scheduler *generator.Scheduler,
 	persistence persistence.BasicHandle,
 	preParamsPoolSize int,
-	preParamsGenerationTimeout time.Duration,
+	preParamsGenerator PreParamsGenerator,
 	preParamsGenerationDelay time.Duration,
-	preParamsGenerationConcurrency int,
 	keyGenerationConcurrency int,
-) *Executor {
+) (*Executor, error) {
 	logger.Infof(
 		"ECDSA key generation concurrency level is [%d]",
 		keyGenerationConcurrency,
 	)
+
+	tssPreParamsPool, err := newTssPreParamsPool(
+		logger,
+		scheduler,
+		persistence,
+		preParamsPoolSize,
+		preParamsGenerator,
+		preParamsGenerationDelay,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot set up TSS pre-parameters pool: [%w]",
+			err,
+		)
+	}
+
 	return &Executor{
-		tssPreParamsPool: newTssPreParamsPool(
-			logger,
-			scheduler,
-			persistence,
-			preParamsPoolSize,
-			preParamsGenerationTimeout,
-			preParamsGenerationDelay,
-			preParamsGenerationConcurrency,
-		),
+		tssPreParamsPool:         tssPreParamsPool,
 		keyGenerationConcurrency: keyGenerationConcurrency,
-	}
+	}, nil
 }
 
 // Execute runs the tECDSA distributed key generation protocol, given a
@@ -118,6 +126,13 @@ func (e *Executor) PreParamsCount() int {
 	return e.tssPreParamsPool.ParametersCount()
 }
 
+// QuarantinedPreParamsCount returns the number of DKG pre-parameters that
+// failed the validation when they were loaded from the storage and were
+// moved to the quarantine.
+func (e *Executor) QuarantinedPreParamsCount() int {
+	return e.tssPreParamsPool.QuarantinedCount()
+}
+
 // SignedResult represents information pertaining to the process of signing
 // a DKG result: the public key used during signing, the resulting signature and
 // the hash of the DKG result that was used during signing.
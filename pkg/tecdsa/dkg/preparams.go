@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"runtime"
 	"sort"
 	"sync"
 	"time"
@@ -36,71 +38,72 @@ func newPreParams(data *keygen.LocalPreParams) *PreParams {
 // will generate a new entry.
 type tssPreParamsPool struct {
 	*generator.ParameterPool[PreParams]
-	logger log.StandardLogger
+	storage *preParamsStorage
+	logger  log.StandardLogger
 }
 
-// newTssPreParamsPool initializes a new TSS pre-parameters pool.
+// newTssPreParamsPool initializes a new TSS pre-parameters pool. The pool
+// is filled with pre-parameters produced by the given generator. It returns
+// an error if the stored pre-parameters cannot be read.
 func newTssPreParamsPool(
 	logger log.StandardLogger,
 	scheduler *generator.Scheduler,
 	persistence persistence.BasicHandle,
 	poolSize int,
-	generationTimeout time.Duration,
+	preParamsGenerator PreParamsGenerator,
 	generationDelay time.Duration,
-	generationConcurrency int,
-) *tssPreParamsPool {
+) (*tssPreParamsPool, error) {
 	logger.Infof(
-		"TSS pre-parameters target pool size is [%d], generation timeout is [%s] "+
-			"generation delay is [%v], and concurrency level is [%d]",
+		"TSS pre-parameters target pool size is [%d] and generation delay is [%v]",
 		poolSize,
-		generationTimeout,
 		generationDelay,
-		generationConcurrency,
 	)
 
 	newPreParamsFn := func(ctx context.Context) *PreParams {
-		timingOutCtx, cancel := context.WithTimeout(ctx, generationTimeout)
-		defer cancel()
-
-		preParams, err := keygen.GeneratePreParamsWithContext(
-			timingOutCtx,
-			generationConcurrency,
-		)
+		preParams, err := preParamsGenerator.GeneratePreParams(ctx)
 		// tss-lib returns generic errors saying "timeout or error while ...".
 		// There are three possibilities:
 		// 1. Pool canceled the parent `ctx`. This is normal and we should not
 		//    log anything in this case.
-		// 2. `timingOutCtx` timed out. It means the machine is not fast enough
+		// 2. Generation timed out. It means the machine is not fast enough
 		//    or that it was just unlucky. We should log a warning.
-		// 3. There is some error from tss-lib generator. We log it as a warning
+		// 3. There is some error from the generator. We log it as a warning
 		//    because we'll re-attempt to generate parameters again.
 		if err != nil && ctx.Err() == nil {
 			logger.Warnf("failed to generate TSS pre-params: [%v]", err)
 		}
 
-		// If the context is done, GeneratePreParamsWithContext that got
-		// interrupted returns nil result. We do not want to wrap nil inside
-		// PreParams structure, so we return nil here.
-		if preParams == nil {
-			return nil
-		}
-
-		return newPreParams(preParams)
+		// If the generation failed, e.g. because the context is done, the
+		// result is nil. Return nil and let the pool try again.
+		return preParams
 	}
 
 	tssPreParamsPersistance := newPreParamsStorage(persistence, logger)
 
-	return &tssPreParamsPool{
-		generator.NewParameterPool[PreParams](
-			logger,
-			scheduler,
-			&tssPreParamsPersistance,
-			poolSize,
-			newPreParamsFn,
-			generationDelay,
-		),
+	parameterPool, err := generator.NewParameterPool[PreParams](
 		logger,
+		scheduler,
+		&tssPreParamsPersistance,
+		poolSize,
+		newPreParamsFn,
+		generationDelay,
+	)
+	if err != nil {
+		return nil, err
 	}
+
+	return &tssPreParamsPool{
+		parameterPool,
+		&tssPreParamsPersistance,
+		logger,
+	}, nil
+}
+
+// QuarantinedCount returns the number of pre-parameters that failed the
+// validation when the pool was loaded from the storage and were moved to
+// the quarantine.
+func (tppp *tssPreParamsPool) QuarantinedCount() int {
+	return tppp.storage.QuarantinedCount()
 }
 
 const (
@@ -118,6 +121,9 @@ type preParamsStorage struct {
 
 	persistence persistence.BasicHandle
 	logger      log.StandardLogger
+
+	// quarantinedCount is the number of PreParams moved to the quarantine.
+	quarantinedCount int
 }
 
 func newPreParamsStorage(
@@ -173,19 +179,125 @@ func (p *preParamsStorage) Delete(pp *PersistedPreParams) error {
 }
 
 // ReadAll reads all the PreParams stored in the storage and returns them as a
-// slice.
+// slice. Entries that are corrupted or fail the validation are logged and
+// moved to the quarantine if the underlying persistence layer supports it.
+// Entries whose content cannot be read are never quarantined as the failure
+// is usually caused by a wrong storage password and not by a corrupted file.
+// The function returns an error if there is at least one such entry.
 func (p *preParamsStorage) ReadAll() ([]*PersistedPreParams, error) {
+	entries, errs := p.readEntries()
+
+	var contentErrs []error
+	allPreParams := make([]*PersistedPreParams, 0, len(entries))
+	for _, entry := range entries {
+		if entry.err != nil {
+			p.logger.Errorf(
+				"could not load PreParams from file [%s] in directory [%s]: [%v]",
+				entry.id,
+				dirName,
+				entry.err,
+			)
+
+			var contentErr *preParamsContentError
+			if errors.As(entry.err, &contentErr) {
+				contentErrs = append(contentErrs, entry.err)
+				continue
+			}
+
+			p.quarantine(entry.id)
+
+			continue
+		}
+
+		allPreParams = append(
+			allPreParams,
+			&PersistedPreParams{Data: *entry.preParams, ID: entry.id},
+		)
+	}
+
+	for _, err := range errs {
+		p.logger.Errorf(
+			"could not load preparams from disk: [%v]",
+			err,
+		)
+	}
+
+	if len(contentErrs) > 0 {
+		return nil, fmt.Errorf(
+			"content of [%d] PreParams could not be read; "+
+				"make sure the storage password is correct; "+
+				"first error: [%w]",
+			len(contentErrs),
+			contentErrs[0],
+		)
+	}
+
+	return allPreParams, nil
+}
+
+// quarantiner is implemented by persistence handles able to move files that
+// failed validation out of the directories read by the client.
+type quarantiner interface {
+	Quarantine(directory string, name string) error
+}
+
+// quarantine moves the PreParams with the given ID to the quarantine if the
+// underlying persistence layer supports it.
+func (p *preParamsStorage) quarantine(id string) {
+	quarantiner, ok := p.persistence.(quarantiner)
+	if !ok {
+		return
+	}
+
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	allPreParams := make([]*PersistedPreParams, 0)
+	if err := quarantiner.Quarantine(dirName, id); err != nil {
+		p.logger.Errorf("could not quarantine PreParams [%s]: [%v]", id, err)
+		return
+	}
+
+	p.logger.Warnf("PreParams [%s] moved to quarantine", id)
+
+	p.quarantinedCount++
+}
+
+// QuarantinedCount returns the number of PreParams moved to the quarantine.
+func (p *preParamsStorage) QuarantinedCount() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.quarantinedCount
+}
+
+// preParamsEntry is a single entry read from the pre-parameters storage.
+type preParamsEntry struct {
+	id         string
+	descriptor persistence.DataDescriptor
+	// preParams is nil if the entry cannot be used.
+	preParams *PreParams
+	// err describes why the entry cannot be used; nil for valid entries.
+	err error
+}
+
+// readEntries reads all entries held in the storage, including the ones
+// that cannot be used because they are corrupted or fail the validation.
+// Valid entries are sorted by their creation timestamps and precede invalid
+// entries sorted by their IDs. Along with the entries, the function returns
+// errors that occurred while reading the storage and could not be attributed
+// to any entry.
+func (p *preParamsStorage) readEntries() ([]*preParamsEntry, []error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	entries := make([]*preParamsEntry, 0)
+	errs := make([]error, 0)
 
 	descriptorsChan, errorsChan := p.persistence.ReadAll()
 
-	// Two goroutines read from descriptors and errors channels and either
-	// add the PreParams to the result slice or outputs a log error.
-	// The reason for using two goroutines at the same time - one for
-	// descriptors and one for errors - is that channels do not have to be
+	// Two goroutines read from descriptors and errors channels and collect
+	// the results. The reason for using two goroutines at the same time - one
+	// for descriptors and one for errors - is that channels do not have to be
 	// buffered, and we do not know in what order the information is written to
 	// channels.
 	var wg sync.WaitGroup
@@ -198,58 +310,18 @@ func (p *preParamsStorage) ReadAll() ([]*PersistedPreParams, error) {
 				continue
 			}
 
-			content, err := descriptor.Content()
-			if err != nil {
-				p.logger.Errorf(
-					"could not read PreParams from file [%s] in directory [%s]: [%v]",
-					descriptor.Name(),
-					descriptor.Directory(),
-					err,
-				)
-				continue
-			}
-
-			persistedPreParams := &PersistedPreParams{}
-			if err = persistedPreParams.Data.Unmarshal(content); err != nil {
-				p.logger.Errorf(
-					"could not unmarshal PreParams from file [%s] in directory [%s]: [%v]",
-					descriptor.Name(),
-					descriptor.Directory(),
-					err,
-				)
-				continue
-			}
-			// Validate recovered PreParams with the same function that is used
-			// in tss-lib and causes panic if the PreParams fail the validation.
-			// Ref: https://github.com/bnb-chain/tss-lib/blob/cbfa6cf63f18f471429eaab0a5f51cf72b7e9df8/ecdsa/keygen/local_party.go#L71-L73
-			if !persistedPreParams.Data.data.ValidateWithProof() {
-				p.logger.Errorf(
-					"PreParams recovered from file [%s] in directory [%s] failed validation",
-					descriptor.Name(),
-					descriptor.Directory(),
-				)
-				continue
-			}
-
-			persistedPreParams.ID = descriptor.Name()
-
-			allPreParams = append(allPreParams, persistedPreParams)
+			entries = append(entries, &preParamsEntry{
+				id:         descriptor.Name(),
+				descriptor: descriptor,
+			})
 		}
 
-		sort.Slice(allPreParams, func(i, j int) bool {
-			return allPreParams[i].Data.creationTimestamp.
-				Before(allPreParams[j].Data.creationTimestamp)
-		})
-
 		wg.Done()
 	}()
 
 	go func() {
 		for err := range errorsChan {
-			p.logger.Errorf(
-				"could not load preparams from disk: [%v]",
-				err,
-			)
+			errs = append(errs, err)
 		}
 
 		wg.Done()
@@ -257,5 +329,76 @@ func (p *preParamsStorage) ReadAll() ([]*PersistedPreParams, error) {
 
 	wg.Wait()
 
-	return allPreParams, nil
+	// The validation is computationally expensive so entries are read and
+	// validated concurrently.
+	semaphore := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var validationWg sync.WaitGroup
+	for _, entry := range entries {
+		semaphore <- struct{}{}
+		validationWg.Add(1)
+
+		go func(entry *preParamsEntry) {
+			defer validationWg.Done()
+			defer func() { <-semaphore }()
+
+			entry.preParams, entry.err = readPreParams(entry.descriptor)
+		}(entry)
+	}
+	validationWg.Wait()
+
+	sort.Slice(entries, func(i, j int) bool {
+		iValid, jValid := entries[i].err == nil, entries[j].err == nil
+		if iValid != jValid {
+			return iValid
+		}
+		if !iValid {
+			return entries[i].id < entries[j].id
+		}
+
+		return entries[i].preParams.creationTimestamp.
+			Before(entries[j].preParams.creationTimestamp)
+	})
+
+	return entries, errs
+}
+
+// preParamsContentError describes a stored PreParams entry whose content
+// could not be read, e.g. because it could not be decrypted. The entry is
+// not necessarily corrupted so it must not be quarantined nor purged.
+type preParamsContentError struct {
+	err error
+}
+
+func (pce *preParamsContentError) Error() string {
+	return fmt.Sprintf("could not read content: [%v]", pce.err)
+}
+
+func (pce *preParamsContentError) Unwrap() error {
+	return pce.err
+}
+
+// readPreParams reads PreParams from the given descriptor and validates them.
+func readPreParams(descriptor persistence.DataDescriptor) (*PreParams, error) {
+	content, err := descriptor.Content()
+	if err != nil {
+		return nil, &preParamsContentError{err}
+	}
+
+	preParams := &PreParams{}
+	if err := preParams.Unmarshal(content); err != nil {
+		return nil, fmt.Errorf("could not unmarshal: [%w]", err)
+	}
+
+	// tss-lib only checks whether all fields are set and panics if not.
+	// Validate all relations between the fields so corrupted PreParams do
+	// not make the DKG protocol fail late.
+	// Ref: https://github.com/bnb-chain/tss-lib/blob/cbfa6cf63f18f471429eaab0a5f51cf72b7e9df8/ecdsa/keygen/local_party.go#L71-L73
+	if err := validatePreParams(
+		preParams.data,
+		storedPrimalityTestRounds,
+	); err != nil {
+		return nil, fmt.Errorf("failed validation: [%w]", err)
+	}
+
+	return preParams, nil
 }
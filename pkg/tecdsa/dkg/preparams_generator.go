@@ -0,0 +1,210 @@
+package dkg
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+)
+
+const (
+	// minModulusBitLength is the minimum bit length of the Paillier and
+	// NTilde moduli. The moduli are products of two 1024-bit safe primes so
+	// they can be one bit shorter than 2048 bits.
+	minModulusBitLength = 2047
+	// receivedPrimalityTestRounds is the number of Miller-Rabin rounds used
+	// to check the primes of pre-parameters received from external workers.
+	// It is the same value that is used by tss-lib when the primes are
+	// generated.
+	receivedPrimalityTestRounds = 30
+	// storedPrimalityTestRounds is the number of Miller-Rabin rounds used to
+	// check the primes of pre-parameters loaded from the storage. Stored
+	// pre-parameters were validated or generated by the client before they
+	// were saved, so the check is meant to detect corrupted data rather than
+	// forged primes. The Baillie-PSW test that is always applied is enough
+	// for that purpose and keeps the validation of the whole pool fast.
+	storedPrimalityTestRounds = 0
+)
+
+// PreParamsGenerator is a backend producing pre-parameters for the
+// pre-parameters pool.
+type PreParamsGenerator interface {
+	// GeneratePreParams produces new pre-parameters. Returns an error if the
+	// pre-parameters could not be produced, e.g. because the context is done.
+	GeneratePreParams(ctx context.Context) (*PreParams, error)
+}
+
+// localPreParamsGenerator generates pre-parameters in-process.
+type localPreParamsGenerator struct {
+	generationTimeout     time.Duration
+	generationConcurrency int
+}
+
+// NewLocalPreParamsGenerator creates a generator producing pre-parameters
+// in-process, with the given timeout and concurrency level.
+func NewLocalPreParamsGenerator(
+	generationTimeout time.Duration,
+	generationConcurrency int,
+) PreParamsGenerator {
+	return &localPreParamsGenerator{
+		generationTimeout:     generationTimeout,
+		generationConcurrency: generationConcurrency,
+	}
+}
+
+func (lppg *localPreParamsGenerator) GeneratePreParams(
+	ctx context.Context,
+) (*PreParams, error) {
+	timingOutCtx, cancel := context.WithTimeout(ctx, lppg.generationTimeout)
+	defer cancel()
+
+	preParams, err := keygen.GeneratePreParamsWithContext(
+		timingOutCtx,
+		lppg.generationConcurrency,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPreParams(preParams), nil
+}
+
+// validatePreParams checks whether the given pre-parameters are well-formed.
+// It verifies the relations that hold for pre-parameters generated by
+// tss-lib. Primes are checked with the given number of Miller-Rabin rounds.
+func validatePreParams(
+	data *keygen.LocalPreParams,
+	primalityTestRounds int,
+) error {
+	if data == nil || !data.ValidateWithProof() {
+		return fmt.Errorf("missing pre-parameters fields")
+	}
+
+	if err := validatePaillierKey(data); err != nil {
+		return fmt.Errorf("invalid Paillier key: [%w]", err)
+	}
+
+	if err := validateRangeProofParameters(
+		data,
+		primalityTestRounds,
+	); err != nil {
+		return fmt.Errorf("invalid range proof parameters: [%w]", err)
+	}
+
+	return nil
+}
+
+// validatePaillierKey checks the Paillier private key of the given
+// pre-parameters.
+func validatePaillierKey(data *keygen.LocalPreParams) error {
+	privateKey := data.PaillierSK
+	if privateKey.N == nil || privateKey.LambdaN == nil || privateKey.PhiN == nil {
+		return fmt.Errorf("missing key fields")
+	}
+
+	if privateKey.N.BitLen() < minModulusBitLength {
+		return fmt.Errorf(
+			"modulus has [%v] bits; expected at least [%v]",
+			privateKey.N.BitLen(),
+			minModulusBitLength,
+		)
+	}
+
+	// For N = P*Q, phi(N) = (P-1)(Q-1) is smaller than N and lambda(N) =
+	// lcm(P-1, Q-1) divides phi(N).
+	if privateKey.LambdaN.Sign() <= 0 ||
+		privateKey.PhiN.Cmp(privateKey.N) >= 0 ||
+		new(big.Int).Mod(privateKey.PhiN, privateKey.LambdaN).Sign() != 0 {
+		return fmt.Errorf("inconsistent totient values")
+	}
+
+	if new(big.Int).GCD(nil, nil, privateKey.N, privateKey.PhiN).Cmp(big.NewInt(1)) != 0 {
+		return fmt.Errorf("modulus is not coprime with its totient")
+	}
+
+	// Make sure the private key decrypts what the public key encrypts.
+	message, err := rand.Int(rand.Reader, privateKey.N)
+	if err != nil {
+		return fmt.Errorf("cannot generate test message: [%w]", err)
+	}
+	ciphertext, err := privateKey.PublicKey.Encrypt(message)
+	if err != nil {
+		return fmt.Errorf("cannot encrypt test message: [%w]", err)
+	}
+	decrypted, err := privateKey.Decrypt(ciphertext)
+	if err != nil {
+		return fmt.Errorf("cannot decrypt test message: [%w]", err)
+	}
+	if decrypted.Cmp(message) != 0 {
+		return fmt.Errorf("private key does not match public key")
+	}
+
+	return nil
+}
+
+// validateRangeProofParameters checks the safe primes and the NTilde, h1,
+// h2 parameters of the given pre-parameters used in the range proofs.
+func validateRangeProofParameters(
+	data *keygen.LocalPreParams,
+	primalityTestRounds int,
+) error {
+	one := big.NewInt(1)
+
+	// P and Q hold Sophie Germain primes p and q such that 2p+1 and 2q+1
+	// are safe primes whose product is NTilde.
+	if data.P.Cmp(data.Q) == 0 {
+		return fmt.Errorf("primes are equal")
+	}
+
+	safePrimes := make([]*big.Int, 2)
+	for i, prime := range []*big.Int{data.P, data.Q} {
+		if !prime.ProbablyPrime(primalityTestRounds) {
+			return fmt.Errorf("not a prime")
+		}
+
+		safePrimes[i] = new(big.Int).Lsh(prime, 1)
+		safePrimes[i].Add(safePrimes[i], one)
+		if !safePrimes[i].ProbablyPrime(primalityTestRounds) {
+			return fmt.Errorf("not a safe prime")
+		}
+	}
+
+	if new(big.Int).Mul(safePrimes[0], safePrimes[1]).Cmp(data.NTildei) != 0 {
+		return fmt.Errorf("NTilde is not a product of the safe primes")
+	}
+
+	if data.NTildei.BitLen() < minModulusBitLength {
+		return fmt.Errorf(
+			"NTilde has [%v] bits; expected at least [%v]",
+			data.NTildei.BitLen(),
+			minModulusBitLength,
+		)
+	}
+
+	for _, h := range []*big.Int{data.H1i, data.H2i} {
+		if h.Cmp(one) <= 0 || h.Cmp(data.NTildei) >= 0 {
+			return fmt.Errorf("h1 or h2 out of range")
+		}
+	}
+
+	if data.H1i.Cmp(data.H2i) == 0 {
+		return fmt.Errorf("h1 and h2 are equal")
+	}
+
+	// h2 = h1^alpha mod NTilde and beta is the inverse of alpha mod pq so
+	// that h1 = h2^beta mod NTilde.
+	if new(big.Int).Exp(data.H1i, data.Alpha, data.NTildei).Cmp(data.H2i) != 0 {
+		return fmt.Errorf("h2 does not match h1 and alpha")
+	}
+
+	pq := new(big.Int).Mul(data.P, data.Q)
+	alphaBeta := new(big.Int).Mul(data.Alpha, data.Beta)
+	if alphaBeta.Mod(alphaBeta, pq).Cmp(one) != 0 {
+		return fmt.Errorf("beta is not the inverse of alpha")
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package dkg
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+
+	"github.com/keep-network/keep-core/pkg/internal/tecdsatest"
+)
+
+func TestValidatePreParams(t *testing.T) {
+	var tests = map[string]struct {
+		modifyFn      func(data *keygen.LocalPreParams)
+		expectedError string
+	}{
+		"valid pre-params": {
+			modifyFn: func(data *keygen.LocalPreParams) {},
+		},
+		"missing field": {
+			modifyFn: func(data *keygen.LocalPreParams) {
+				data.Alpha = nil
+			},
+			expectedError: "missing pre-parameters fields",
+		},
+		"short Paillier modulus": {
+			modifyFn: func(data *keygen.LocalPreParams) {
+				data.PaillierSK.N = new(big.Int).Rsh(data.PaillierSK.N, 512)
+			},
+			expectedError: "modulus has [1536] bits",
+		},
+		"inconsistent Paillier totient": {
+			modifyFn: func(data *keygen.LocalPreParams) {
+				data.PaillierSK.LambdaN = new(big.Int).Add(
+					data.PaillierSK.LambdaN,
+					big.NewInt(1),
+				)
+			},
+			expectedError: "inconsistent totient values",
+		},
+		"Paillier private key not matching public key": {
+			modifyFn: func(data *keygen.LocalPreParams) {
+				data.PaillierSK.PhiN = new(big.Int).Sub(
+					data.PaillierSK.PhiN,
+					big.NewInt(2),
+				)
+				data.PaillierSK.LambdaN = data.PaillierSK.PhiN
+			},
+			expectedError: "private key does not match public key",
+		},
+		"not a prime": {
+			modifyFn: func(data *keygen.LocalPreParams) {
+				data.P = new(big.Int).Add(data.P, big.NewInt(2))
+			},
+			expectedError: "not a prime",
+		},
+		"equal primes": {
+			modifyFn: func(data *keygen.LocalPreParams) {
+				data.Q = data.P
+			},
+			expectedError: "primes are equal",
+		},
+		"NTilde not matching safe primes": {
+			modifyFn: func(data *keygen.LocalPreParams) {
+				data.NTildei = new(big.Int).Add(data.NTildei, big.NewInt(2))
+			},
+			expectedError: "NTilde is not a product of the safe primes",
+		},
+		"h2 not matching h1 and alpha": {
+			modifyFn: func(data *keygen.LocalPreParams) {
+				data.H2i = new(big.Int).Add(data.H2i, big.NewInt(1))
+			},
+			expectedError: "h2 does not match h1 and alpha",
+		},
+		"h1 out of range": {
+			modifyFn: func(data *keygen.LocalPreParams) {
+				data.H1i = big.NewInt(1)
+			},
+			expectedError: "h1 or h2 out of range",
+		},
+		"beta not inverse of alpha": {
+			modifyFn: func(data *keygen.LocalPreParams) {
+				data.Beta = new(big.Int).Add(data.Beta, big.NewInt(1))
+			},
+			expectedError: "beta is not the inverse of alpha",
+		},
+	}
+
+	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(1)
+	if err != nil {
+		t.Fatalf("failed to load test data: [%v]", err)
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			data := copyLocalPreParams(&testData[0].LocalPreParams)
+			test.modifyFn(data)
+
+			err := validatePreParams(data, receivedPrimalityTestRounds)
+
+			if test.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: [%v]", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Fatalf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func copyLocalPreParams(data *keygen.LocalPreParams) *keygen.LocalPreParams {
+	copyBigInt := func(value *big.Int) *big.Int {
+		return new(big.Int).Set(value)
+	}
+
+	return &keygen.LocalPreParams{
+		PaillierSK: &paillier.PrivateKey{
+			PublicKey: paillier.PublicKey{
+				N: copyBigInt(data.PaillierSK.N),
+			},
+			LambdaN: copyBigInt(data.PaillierSK.LambdaN),
+			PhiN:    copyBigInt(data.PaillierSK.PhiN),
+		},
+		NTildei: copyBigInt(data.NTildei),
+		H1i:     copyBigInt(data.H1i),
+		H2i:     copyBigInt(data.H2i),
+		Alpha:   copyBigInt(data.Alpha),
+		Beta:    copyBigInt(data.Beta),
+		P:       copyBigInt(data.P),
+		Q:       copyBigInt(data.Q),
+	}
+}
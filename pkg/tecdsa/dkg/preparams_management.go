@@ -0,0 +1,168 @@
+package dkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ipfs/go-log/v2"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+)
+
+// StoredPreParams describes an entry held in the pre-parameters storage. It
+// is meant to be used by operator tools managing the pre-parameters pool
+// outside the running client.
+type StoredPreParams struct {
+	// ID identifies the entry in the storage. It is also the name of the file
+	// holding the entry.
+	ID string
+	// CreationTimestamp is the time the pre-parameters were generated. It is
+	// zero for invalid entries.
+	CreationTimestamp time.Time
+	// Err describes why the entry cannot be used by the DKG protocol, e.g.
+	// because it is corrupted or fails the validation. It is nil for valid
+	// entries.
+	Err error
+}
+
+// ReadStoredPreParams reads all entries held in the pre-parameters storage
+// of the given persistence handle, including the invalid ones. Valid entries
+// are sorted from the oldest to the newest one and precede invalid entries.
+// Along with the entries, the function returns errors that occurred while
+// reading the storage and could not be attributed to any entry.
+func ReadStoredPreParams(
+	persistence persistence.BasicHandle,
+) ([]*StoredPreParams, []error) {
+	storage := newPreParamsStorage(persistence, nil)
+
+	entries, errs := storage.readEntries()
+
+	storedPreParams := make([]*StoredPreParams, len(entries))
+	for i, entry := range entries {
+		storedPreParams[i] = newStoredPreParams(entry.id, entry.preParams, entry.err)
+	}
+
+	return storedPreParams, errs
+}
+
+// GeneratePreParams generates the given number of pre-parameters and saves
+// them in the pre-parameters storage of the given persistence handle so they
+// are loaded to the pool once the client starts. Each pre-parameters are
+// generated with the given timeout and concurrency level. The optional
+// progress function is called after each saved entry. Returns the number of
+// saved entries; the generation stops at the first failure or once the
+// context is done.
+func GeneratePreParams(
+	ctx context.Context,
+	logger log.StandardLogger,
+	persistence persistence.BasicHandle,
+	count int,
+	generationTimeout time.Duration,
+	generationConcurrency int,
+	progressFn func(entry *StoredPreParams, took time.Duration),
+) (int, error) {
+	storage := newPreParamsStorage(persistence, logger)
+	preParamsGenerator := NewLocalPreParamsGenerator(
+		generationTimeout,
+		generationConcurrency,
+	)
+
+	for generated := 0; generated < count; generated++ {
+		start := time.Now()
+
+		preParams, err := preParamsGenerator.GeneratePreParams(ctx)
+		if err != nil {
+			return generated, fmt.Errorf(
+				"failed to generate TSS pre-params: [%w]",
+				err,
+			)
+		}
+
+		persisted, err := storage.Save(preParams)
+		if err != nil {
+			return generated, err
+		}
+
+		if progressFn != nil {
+			progressFn(
+				newStoredPreParams(persisted.ID, &persisted.Data, nil),
+				time.Since(start),
+			)
+		}
+	}
+
+	return count, nil
+}
+
+// PurgeStoredPreParams deletes invalid entries from the pre-parameters
+// storage of the given persistence handle. If the maximum age is greater
+// than zero, valid entries older than that age are deleted as well. Returns
+// the deleted entries. Nothing is deleted if content of any entry cannot be
+// read as it usually means the storage password is wrong.
+func PurgeStoredPreParams(
+	logger log.StandardLogger,
+	persistence persistence.BasicHandle,
+	maxAge time.Duration,
+) ([]*StoredPreParams, error) {
+	storage := newPreParamsStorage(persistence, logger)
+
+	entries, errs := storage.readEntries()
+	if len(errs) > 0 {
+		return nil, fmt.Errorf(
+			"cannot read pre-params storage: [%w]",
+			errs[0],
+		)
+	}
+
+	for _, entry := range entries {
+		var contentErr *preParamsContentError
+		if errors.As(entry.err, &contentErr) {
+			return nil, fmt.Errorf(
+				"cannot read pre-params [%s]; make sure the storage "+
+					"password is correct: [%w]",
+				entry.id,
+				entry.err,
+			)
+		}
+	}
+
+	purged := make([]*StoredPreParams, 0)
+	for _, entry := range entries {
+		stale := entry.err == nil &&
+			maxAge > 0 &&
+			time.Since(entry.preParams.creationTimestamp) > maxAge
+		if entry.err == nil && !stale {
+			continue
+		}
+
+		if err := storage.Delete(&PersistedPreParams{ID: entry.id}); err != nil {
+			return purged, fmt.Errorf(
+				"cannot delete pre-params [%s]: [%w]",
+				entry.id,
+				err,
+			)
+		}
+
+		purged = append(
+			purged,
+			newStoredPreParams(entry.id, entry.preParams, entry.err),
+		)
+	}
+
+	return purged, nil
+}
+
+func newStoredPreParams(
+	id string,
+	preParams *PreParams,
+	err error,
+) *StoredPreParams {
+	storedPreParams := &StoredPreParams{ID: id, Err: err}
+	if preParams != nil {
+		storedPreParams.CreationTimestamp = preParams.creationTimestamp
+	}
+
+	return storedPreParams
+}
@@ -0,0 +1,179 @@
+package dkg
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/internal/tecdsatest"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+func TestReadStoredPreParams(t *testing.T) {
+	handle, oldID, newID := initializeTestPreParamsPersistence(t)
+
+	storedPreParams, errs := ReadStoredPreParams(handle)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: [%v]", errs)
+	}
+
+	var ids []string
+	var valid []bool
+	for _, entry := range storedPreParams {
+		ids = append(ids, entry.ID)
+		valid = append(valid, entry.Err == nil)
+	}
+
+	expectedIDs := []string{oldID, newID, "pp_corrupted"}
+	if !reflect.DeepEqual(expectedIDs, ids) {
+		t.Errorf(
+			"unexpected entries\nexpected: %v\nactual:   %v\n",
+			expectedIDs,
+			ids,
+		)
+	}
+	expectedValid := []bool{true, true, false}
+	if !reflect.DeepEqual(expectedValid, valid) {
+		t.Errorf(
+			"unexpected entries validity\nexpected: %v\nactual:   %v\n",
+			expectedValid,
+			valid,
+		)
+	}
+
+	if !storedPreParams[2].CreationTimestamp.IsZero() {
+		t.Errorf("expected zero creation timestamp of the invalid entry")
+	}
+}
+
+func TestPurgeStoredPreParams(t *testing.T) {
+	var tests = map[string]struct {
+		maxAge              time.Duration
+		expectedRemainingFn func(oldID, newID string) []string
+	}{
+		"invalid entries only": {
+			maxAge: 0,
+			expectedRemainingFn: func(oldID, newID string) []string {
+				return []string{oldID, newID}
+			},
+		},
+		"invalid and stale entries": {
+			maxAge: 7 * 24 * time.Hour,
+			expectedRemainingFn: func(oldID, newID string) []string {
+				return []string{newID}
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			handle, oldID, newID := initializeTestPreParamsPersistence(t)
+
+			purged, err := PurgeStoredPreParams(
+				&testutils.MockLogger{},
+				handle,
+				test.maxAge,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			storedPreParams, errs := ReadStoredPreParams(handle)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: [%v]", errs)
+			}
+
+			var remaining []string
+			for _, entry := range storedPreParams {
+				remaining = append(remaining, entry.ID)
+			}
+
+			expectedRemaining := test.expectedRemainingFn(oldID, newID)
+			if !reflect.DeepEqual(expectedRemaining, remaining) {
+				t.Errorf(
+					"unexpected remaining entries\nexpected: %v\nactual:   %v\n",
+					expectedRemaining,
+					remaining,
+				)
+			}
+
+			testutils.AssertIntsEqual(
+				t,
+				"purged entries count",
+				3-len(expectedRemaining),
+				len(purged),
+			)
+		})
+	}
+}
+
+func TestGeneratePreParams_ContextDone(t *testing.T) {
+	handle, err := persistence.NewBasicDiskHandle(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	generated, err := GeneratePreParams(
+		ctx,
+		&testutils.MockLogger{},
+		handle,
+		1,
+		time.Minute,
+		1,
+		nil,
+	)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	testutils.AssertIntsEqual(t, "generated count", 0, generated)
+}
+
+// initializeTestPreParamsPersistence creates a persistence handle holding
+// one pre-params entry generated ten days ago, one entry generated now,
+// one corrupted entry, and one file from outside the pre-params directory.
+// Returns IDs of the valid entries.
+func initializeTestPreParamsPersistence(
+	t *testing.T,
+) (persistence.BasicHandle, string, string) {
+	handle, err := persistence.NewBasicDiskHandle(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(2)
+	if err != nil {
+		t.Fatalf("failed to load test data: [%v]", err)
+	}
+
+	storage := newPreParamsStorage(handle, &testutils.MockLogger{})
+
+	oldPreParams := newPreParams(&testData[0].LocalPreParams)
+	oldPreParams.creationTimestamp = oldPreParams.creationTimestamp.
+		Add(-10 * 24 * time.Hour)
+	oldPersisted, err := storage.Save(oldPreParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newPersisted, err := storage.Save(newPreParams(&testData[1].LocalPreParams))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := handle.Save([]byte{0x01, 0x02}, dirName, "pp_corrupted"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := handle.Save([]byte{0x01}, "other", "pp_other"); err != nil {
+		t.Fatal(err)
+	}
+
+	return handle, oldPersisted.ID, newPersisted.ID
+}
@@ -0,0 +1,136 @@
+package dkg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-common/pkg/persistence"
+
+	"github.com/keep-network/keep-core/pkg/internal/tecdsatest"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+func TestPreParamsStorage_ReadAll_Quarantine(t *testing.T) {
+	handle, oldID, newID := initializeTestPreParamsPersistence(t)
+
+	quarantinableHandle := &mockQuarantinableHandle{BasicHandle: handle}
+
+	storage := newPreParamsStorage(
+		quarantinableHandle,
+		&testutils.MockLogger{},
+	)
+
+	allPreParams, err := storage.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, persistedPreParams := range allPreParams {
+		ids = append(ids, persistedPreParams.ID)
+	}
+
+	expectedIDs := []string{oldID, newID}
+	if !reflect.DeepEqual(expectedIDs, ids) {
+		t.Errorf(
+			"unexpected entries\nexpected: %v\nactual:   %v\n",
+			expectedIDs,
+			ids,
+		)
+	}
+
+	expectedQuarantined := []string{dirName + "/pp_corrupted"}
+	if !reflect.DeepEqual(expectedQuarantined, quarantinableHandle.quarantined) {
+		t.Errorf(
+			"unexpected quarantined entries\nexpected: %v\nactual:   %v\n",
+			expectedQuarantined,
+			quarantinableHandle.quarantined,
+		)
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"quarantined count",
+		1,
+		storage.QuarantinedCount(),
+	)
+}
+
+func TestPreParamsStorage_ReadAll_QuarantineNotSupported(t *testing.T) {
+	handle, _, _ := initializeTestPreParamsPersistence(t)
+
+	storage := newPreParamsStorage(handle, &testutils.MockLogger{})
+
+	allPreParams, err := storage.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "entries count", 2, len(allPreParams))
+	testutils.AssertIntsEqual(
+		t,
+		"quarantined count",
+		0,
+		storage.QuarantinedCount(),
+	)
+}
+
+func TestPreParamsStorage_ReadAll_UnreadableContent(t *testing.T) {
+	diskHandle, err := persistence.NewBasicDiskHandle(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(1)
+	if err != nil {
+		t.Fatalf("failed to load test data: [%v]", err)
+	}
+
+	oldPasswordStorage := newPreParamsStorage(
+		persistence.NewEncryptedBasicPersistence(diskHandle, "old password"),
+		&testutils.MockLogger{},
+	)
+	if _, err := oldPasswordStorage.Save(
+		newPreParams(&testData[0].LocalPreParams),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	quarantinableHandle := &mockQuarantinableHandle{
+		BasicHandle: persistence.NewEncryptedBasicPersistence(
+			diskHandle,
+			"new password",
+		),
+	}
+
+	storage := newPreParamsStorage(
+		quarantinableHandle,
+		&testutils.MockLogger{},
+	)
+
+	_, err = storage.ReadAll()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"quarantined count",
+		0,
+		len(quarantinableHandle.quarantined),
+	)
+}
+
+type mockQuarantinableHandle struct {
+	persistence.BasicHandle
+	quarantined []string
+}
+
+func (mqh *mockQuarantinableHandle) Quarantine(
+	directory string,
+	name string,
+) error {
+	mqh.quarantined = append(mqh.quarantined, directory+"/"+name)
+
+	return mqh.BasicHandle.Delete(directory, name)
+}
@@ -0,0 +1,417 @@
+package dkg
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-log/v2"
+
+	"github.com/keep-network/keep-common/pkg/encryption"
+)
+
+const (
+	// PreParamsReceiverPath is the path of the HTTP endpoint the
+	// pre-parameters receiver accepts pre-parameters on.
+	PreParamsReceiverPath = "/preparams"
+	// maxPreParamsMessageSize is the maximum size of a pre-parameters
+	// message accepted by the receiver. Marshaled pre-parameters take a few
+	// kilobytes.
+	maxPreParamsMessageSize = 64 * 1024
+	// DefaultPreParamsDeliveryTimeout is the default time the receiver waits
+	// for the pool to accept the delivered pre-parameters before it responds
+	// that the pool is not accepting pre-parameters at the moment.
+	DefaultPreParamsDeliveryTimeout = 30 * time.Second
+	// DefaultPreParamsDeliveryRetryDelay is the default time the worker waits
+	// before delivering the pre-parameters again if they were not accepted
+	// by the receiver.
+	DefaultPreParamsDeliveryRetryDelay = 10 * time.Second
+	// maxReceivedPreParamsHashes is the maximum number of hashes of received
+	// pre-parameters remembered by the receiver to reject replays. Once the
+	// limit is reached, the least recently received hashes are forgotten.
+	// The limit is well above the size of the pre-parameters pool so
+	// pre-parameters still waiting in the pool are always remembered.
+	maxReceivedPreParamsHashes = 10000
+)
+
+// ErrPreParamsWorkerUnauthorized is returned by the pre-parameters worker if
+// the receiver could not authenticate the delivered pre-parameters, e.g.
+// because the worker and the receiver use different secrets.
+var ErrPreParamsWorkerUnauthorized = fmt.Errorf(
+	"pre-parameters receiver could not authenticate the worker",
+)
+
+// newPreParamsBox creates the box used to encrypt and authenticate
+// pre-parameters exchanged between the worker and the receiver.
+func newPreParamsBox(secret string) encryption.Box {
+	return encryption.NewBox(sha256.Sum256([]byte(secret)))
+}
+
+// PreParamsReceiver is a pre-parameters generator backend that does not
+// generate pre-parameters itself but receives them from external worker
+// processes over HTTP. Pre-parameters are exchanged encrypted with a secret
+// shared by the receiver and the workers so the endpoint authenticates the
+// workers and keeps the pre-parameters confidential even if served over
+// plain HTTP. Received pre-parameters are validated before they are accepted.
+type PreParamsReceiver struct {
+	logger log.StandardLogger
+	box    encryption.Box
+
+	deliveryTimeout time.Duration
+	preParamsChan   chan *PreParams
+
+	// receivedMutex guards received and receivedOrder.
+	receivedMutex sync.Mutex
+	// received holds hashes of the most recently received pre-parameters so
+	// that replayed pre-parameters are not accepted twice. Values are
+	// elements of receivedOrder.
+	received map[[sha256.Size]byte]*list.Element
+	// receivedOrder holds hashes of received pre-parameters in the order
+	// they were received, the oldest at the front.
+	receivedOrder *list.List
+}
+
+// NewPreParamsReceiver creates a new pre-parameters receiver authenticating
+// workers with the given secret.
+func NewPreParamsReceiver(
+	logger log.StandardLogger,
+	secret string,
+	deliveryTimeout time.Duration,
+) *PreParamsReceiver {
+	return &PreParamsReceiver{
+		logger:          logger,
+		box:             newPreParamsBox(secret),
+		deliveryTimeout: deliveryTimeout,
+		preParamsChan:   make(chan *PreParams),
+		received:        make(map[[sha256.Size]byte]*list.Element),
+		receivedOrder:   list.New(),
+	}
+}
+
+// GeneratePreParams waits for pre-parameters delivered by a worker. Returns
+// an error if the context is done before any pre-parameters are delivered.
+func (ppr *PreParamsReceiver) GeneratePreParams(
+	ctx context.Context,
+) (*PreParams, error) {
+	select {
+	case preParams := <-ppr.preParamsChan:
+		return preParams, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Listen starts serving the receiver's HTTP endpoint on the given address in
+// the background. Returns an error if the address cannot be listened on.
+func (ppr *PreParamsReceiver) Listen(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("cannot listen on [%s]: [%w]", address, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(PreParamsReceiverPath, ppr)
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			ppr.logger.Errorf(
+				"pre-params receiver stopped serving: [%v]",
+				err,
+			)
+		}
+	}()
+
+	ppr.logger.Infof(
+		"pre-params receiver listening on [%s]",
+		listener.Addr(),
+	)
+
+	return nil
+}
+
+// ServeHTTP handles pre-parameters delivered by a worker.
+func (ppr *PreParamsReceiver) ServeHTTP(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	message, err := ioutil.ReadAll(
+		io.LimitReader(r.Body, maxPreParamsMessageSize+1),
+	)
+	if err != nil {
+		http.Error(w, "cannot read request", http.StatusBadRequest)
+		return
+	}
+	if len(message) > maxPreParamsMessageSize {
+		http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	content, err := ppr.box.Decrypt(message)
+	if err != nil {
+		ppr.logger.Warnf(
+			"rejected unauthenticated pre-params from [%s]",
+			r.RemoteAddr,
+		)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	preParams := &PreParams{}
+	if err := preParams.Unmarshal(content); err != nil {
+		http.Error(w, "cannot unmarshal pre-params", http.StatusBadRequest)
+		return
+	}
+
+	if err := validatePreParams(
+		preParams.data,
+		receivedPrimalityTestRounds,
+	); err != nil {
+		ppr.logger.Warnf(
+			"rejected invalid pre-params from [%s]: [%v]",
+			r.RemoteAddr,
+			err,
+		)
+		http.Error(
+			w,
+			fmt.Sprintf("invalid pre-params: %v", err),
+			http.StatusBadRequest,
+		)
+		return
+	}
+
+	hash := sha256.Sum256(content)
+	if !ppr.markReceived(hash) {
+		http.Error(w, "pre-params already received", http.StatusConflict)
+		return
+	}
+
+	deliveryTimer := time.NewTimer(ppr.deliveryTimeout)
+	defer deliveryTimer.Stop()
+
+	select {
+	case ppr.preParamsChan <- preParams:
+		ppr.logger.Infof("received pre-params from [%s]", r.RemoteAddr)
+		w.WriteHeader(http.StatusAccepted)
+	case <-deliveryTimer.C:
+		ppr.unmarkReceived(hash)
+		http.Error(
+			w,
+			"pool is not accepting pre-params",
+			http.StatusServiceUnavailable,
+		)
+	case <-r.Context().Done():
+		ppr.unmarkReceived(hash)
+	}
+}
+
+// markReceived marks the pre-parameters with the given hash as received.
+// Returns false if they have already been received. If the number of
+// remembered hashes exceeds the limit, the oldest hash is forgotten.
+func (ppr *PreParamsReceiver) markReceived(hash [sha256.Size]byte) bool {
+	ppr.receivedMutex.Lock()
+	defer ppr.receivedMutex.Unlock()
+
+	if _, ok := ppr.received[hash]; ok {
+		return false
+	}
+
+	ppr.received[hash] = ppr.receivedOrder.PushBack(hash)
+
+	if ppr.receivedOrder.Len() > maxReceivedPreParamsHashes {
+		oldest := ppr.receivedOrder.Front()
+		ppr.receivedOrder.Remove(oldest)
+		delete(ppr.received, oldest.Value.([sha256.Size]byte))
+	}
+
+	return true
+}
+
+// unmarkReceived allows the pre-parameters with the given hash to be
+// delivered again after they could not be accepted by the pool.
+func (ppr *PreParamsReceiver) unmarkReceived(hash [sha256.Size]byte) {
+	ppr.receivedMutex.Lock()
+	defer ppr.receivedMutex.Unlock()
+
+	if element, ok := ppr.received[hash]; ok {
+		ppr.receivedOrder.Remove(element)
+		delete(ppr.received, hash)
+	}
+}
+
+// PreParamsWorker generates pre-parameters and delivers them to the
+// pre-parameters receiver of a node. It is meant to be run as a separate
+// process, possibly on another machine, so the generation does not compete
+// for CPU with the node's protocols.
+type PreParamsWorker struct {
+	logger      log.StandardLogger
+	generator   PreParamsGenerator
+	box         encryption.Box
+	receiverURL string
+	httpClient  *http.Client
+	retryDelay  time.Duration
+}
+
+// NewPreParamsWorker creates a new pre-parameters worker delivering
+// pre-parameters produced by the given generator to the node's receiver
+// available under the given URL. The worker authenticates with the given
+// secret that must be the same as the receiver's one.
+func NewPreParamsWorker(
+	logger log.StandardLogger,
+	generator PreParamsGenerator,
+	nodeURL string,
+	secret string,
+	retryDelay time.Duration,
+) *PreParamsWorker {
+	return &PreParamsWorker{
+		logger:      logger,
+		generator:   generator,
+		box:         newPreParamsBox(secret),
+		receiverURL: strings.TrimSuffix(nodeURL, "/") + PreParamsReceiverPath,
+		httpClient:  &http.Client{Timeout: 2 * DefaultPreParamsDeliveryTimeout},
+		retryDelay:  retryDelay,
+	}
+}
+
+// Run generates pre-parameters and delivers them to the node until the
+// given number of pre-parameters is delivered or the context is done. If the
+// count is not greater than zero, the worker runs until the context is done.
+// The optional delivered function is called after each delivery. Returns the
+// number of delivered pre-parameters.
+func (ppw *PreParamsWorker) Run(
+	ctx context.Context,
+	count int,
+	deliveredFn func(took time.Duration),
+) (int, error) {
+	delivered := 0
+
+	for count <= 0 || delivered < count {
+		start := time.Now()
+
+		preParams, err := ppw.generator.GeneratePreParams(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return delivered, nil
+			}
+
+			ppw.logger.Warnf("failed to generate TSS pre-params: [%v]", err)
+			continue
+		}
+
+		if err := ppw.deliver(ctx, preParams); err != nil {
+			if ctx.Err() != nil {
+				return delivered, nil
+			}
+
+			return delivered, err
+		}
+
+		delivered++
+
+		if deliveredFn != nil {
+			deliveredFn(time.Since(start))
+		}
+	}
+
+	return delivered, nil
+}
+
+// deliver delivers the given pre-parameters to the node. If the node is not
+// reachable or does not accept pre-parameters at the moment, the delivery is
+// retried until the context is done.
+func (ppw *PreParamsWorker) deliver(
+	ctx context.Context,
+	preParams *PreParams,
+) error {
+	content, err := preParams.Marshal()
+	if err != nil {
+		return fmt.Errorf("cannot marshal pre-params: [%w]", err)
+	}
+
+	message, err := ppw.box.Encrypt(content)
+	if err != nil {
+		return fmt.Errorf("cannot encrypt pre-params: [%w]", err)
+	}
+
+	for {
+		retry, err := ppw.post(ctx, message)
+		if !retry {
+			return err
+		}
+
+		ppw.logger.Warnf(
+			"pre-params not delivered; retrying in [%v]: [%v]",
+			ppw.retryDelay,
+			err,
+		)
+
+		select {
+		case <-time.After(ppw.retryDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// post sends the given message to the receiver. Returns whether the delivery
+// should be retried along with the delivery error.
+func (ppw *PreParamsWorker) post(
+	ctx context.Context,
+	message []byte,
+) (bool, error) {
+	request, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		ppw.receiverURL,
+		bytes.NewReader(message),
+	)
+	if err != nil {
+		return false, fmt.Errorf("cannot create request: [%w]", err)
+	}
+	request.Header.Set("Content-Type", "application/octet-stream")
+
+	response, err := ppw.httpClient.Do(request)
+	if err != nil {
+		return true, err
+	}
+	defer response.Body.Close()
+
+	responseBody, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+
+	switch {
+	case response.StatusCode == http.StatusAccepted:
+		return false, nil
+	case response.StatusCode == http.StatusUnauthorized:
+		return false, ErrPreParamsWorkerUnauthorized
+	default:
+		// Server-side errors, including the pool not accepting pre-params
+		// at the moment, are temporary. Other errors mean the pre-params
+		// were rejected.
+		return response.StatusCode >= http.StatusInternalServerError,
+			fmt.Errorf(
+				"unexpected response status [%s]: [%s]",
+				response.Status,
+				strings.TrimSpace(string(responseBody)),
+			)
+	}
+}
@@ -0,0 +1,341 @@
+package dkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+
+	"github.com/keep-network/keep-core/pkg/internal/tecdsatest"
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+)
+
+const testPreParamsWorkerSecret = "worker-secret"
+
+func TestPreParamsWorker_Run(t *testing.T) {
+	localPreParams := loadTestLocalPreParams(t)
+
+	receiver, receiverURL := startTestPreParamsReceiver(t, time.Second)
+
+	receivedChan := make(chan *PreParams, 2)
+	go func() {
+		for i := 0; i < 2; i++ {
+			preParams, err := receiver.GeneratePreParams(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			receivedChan <- preParams
+		}
+	}()
+
+	worker := NewPreParamsWorker(
+		&testutils.MockLogger{},
+		&mockPreParamsGenerator{localPreParams},
+		receiverURL,
+		testPreParamsWorkerSecret,
+		10*time.Millisecond,
+	)
+
+	delivered, err := worker.Run(context.Background(), 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutils.AssertIntsEqual(t, "delivered pre-params", 2, delivered)
+
+	for i := 0; i < 2; i++ {
+		preParams := <-receivedChan
+		if !reflect.DeepEqual(localPreParams, preParams.data) {
+			t.Errorf("unexpected content of received pre-params")
+		}
+	}
+}
+
+func TestPreParamsWorker_Run_WrongSecret(t *testing.T) {
+	_, receiverURL := startTestPreParamsReceiver(t, time.Second)
+
+	worker := NewPreParamsWorker(
+		&testutils.MockLogger{},
+		&mockPreParamsGenerator{loadTestLocalPreParams(t)},
+		receiverURL,
+		"wrong-secret",
+		10*time.Millisecond,
+	)
+
+	delivered, err := worker.Run(context.Background(), 1, nil)
+
+	testutils.AssertErrorsSame(t, ErrPreParamsWorkerUnauthorized, err)
+	testutils.AssertIntsEqual(t, "delivered pre-params", 0, delivered)
+}
+
+func TestPreParamsReceiver_ServeHTTP(t *testing.T) {
+	localPreParams := loadTestLocalPreParams(t)
+
+	validContent, err := newPreParams(localPreParams).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalidPreParams := copyLocalPreParams(localPreParams)
+	invalidPreParams.Beta.Add(invalidPreParams.Beta, invalidPreParams.Beta)
+	invalidContent, err := newPreParams(invalidPreParams).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = map[string]struct {
+		method         string
+		secret         string
+		content        []byte
+		consume        bool
+		expectedStatus int
+	}{
+		"valid pre-params": {
+			method:         http.MethodPost,
+			secret:         testPreParamsWorkerSecret,
+			content:        validContent,
+			consume:        true,
+			expectedStatus: http.StatusAccepted,
+		},
+		"pool not accepting pre-params": {
+			method:         http.MethodPost,
+			secret:         testPreParamsWorkerSecret,
+			content:        validContent,
+			consume:        false,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		"wrong secret": {
+			method:         http.MethodPost,
+			secret:         "wrong-secret",
+			content:        validContent,
+			consume:        true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"invalid pre-params": {
+			method:         http.MethodPost,
+			secret:         testPreParamsWorkerSecret,
+			content:        invalidContent,
+			consume:        true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"malformed pre-params": {
+			method:         http.MethodPost,
+			secret:         testPreParamsWorkerSecret,
+			content:        []byte{0xFF, 0xFF},
+			consume:        true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"wrong method": {
+			method:         http.MethodGet,
+			secret:         testPreParamsWorkerSecret,
+			content:        validContent,
+			consume:        true,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			receiver, receiverURL := startTestPreParamsReceiver(
+				t,
+				100*time.Millisecond,
+			)
+
+			if test.consume {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				go func() {
+					_, _ = receiver.GeneratePreParams(ctx)
+				}()
+			}
+
+			status := postTestPreParams(
+				t,
+				test.method,
+				receiverURL,
+				test.secret,
+				test.content,
+			)
+
+			testutils.AssertIntsEqual(
+				t,
+				"response status",
+				test.expectedStatus,
+				status,
+			)
+		})
+	}
+}
+
+func TestPreParamsReceiver_ServeHTTP_Replay(t *testing.T) {
+	receiver, receiverURL := startTestPreParamsReceiver(t, 100*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		for {
+			if _, err := receiver.GeneratePreParams(ctx); err != nil {
+				return
+			}
+		}
+	}()
+
+	content, err := newPreParams(loadTestLocalPreParams(t)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expectedStatus := range []int{
+		http.StatusAccepted,
+		http.StatusConflict,
+	} {
+		status := postTestPreParams(
+			t,
+			http.MethodPost,
+			receiverURL,
+			testPreParamsWorkerSecret,
+			content,
+		)
+
+		testutils.AssertIntsEqual(t, "response status", expectedStatus, status)
+	}
+}
+
+func TestPreParamsReceiver_MarkReceived_Limit(t *testing.T) {
+	receiver := NewPreParamsReceiver(
+		&testutils.MockLogger{},
+		testPreParamsWorkerSecret,
+		time.Second,
+	)
+
+	hash := func(i int) [sha256.Size]byte {
+		return sha256.Sum256([]byte(fmt.Sprintf("pre-params-%d", i)))
+	}
+
+	for i := 0; i < maxReceivedPreParamsHashes+1; i++ {
+		if !receiver.markReceived(hash(i)) {
+			t.Fatalf("pre-params [%d] should not be marked as received", i)
+		}
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"remembered hashes count",
+		maxReceivedPreParamsHashes,
+		len(receiver.received),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"ordered hashes count",
+		maxReceivedPreParamsHashes,
+		receiver.receivedOrder.Len(),
+	)
+
+	// The oldest hash should be forgotten while the newest one should be
+	// still remembered.
+	testutils.AssertBoolsEqual(
+		t,
+		"oldest pre-params marked",
+		true,
+		receiver.markReceived(hash(0)),
+	)
+	testutils.AssertBoolsEqual(
+		t,
+		"newest pre-params marked",
+		false,
+		receiver.markReceived(hash(maxReceivedPreParamsHashes)),
+	)
+
+	receiver.unmarkReceived(hash(maxReceivedPreParamsHashes))
+
+	testutils.AssertIntsEqual(
+		t,
+		"remembered hashes count",
+		maxReceivedPreParamsHashes-1,
+		len(receiver.received),
+	)
+	testutils.AssertIntsEqual(
+		t,
+		"ordered hashes count",
+		maxReceivedPreParamsHashes-1,
+		receiver.receivedOrder.Len(),
+	)
+}
+
+type mockPreParamsGenerator struct {
+	data *keygen.LocalPreParams
+}
+
+func (mppg *mockPreParamsGenerator) GeneratePreParams(
+	ctx context.Context,
+) (*PreParams, error) {
+	return newPreParams(mppg.data), nil
+}
+
+func loadTestLocalPreParams(t *testing.T) *keygen.LocalPreParams {
+	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(1)
+	if err != nil {
+		t.Fatalf("failed to load test data: [%v]", err)
+	}
+
+	return &testData[0].LocalPreParams
+}
+
+func startTestPreParamsReceiver(
+	t *testing.T,
+	deliveryTimeout time.Duration,
+) (*PreParamsReceiver, string) {
+	receiver := NewPreParamsReceiver(
+		&testutils.MockLogger{},
+		testPreParamsWorkerSecret,
+		deliveryTimeout,
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle(PreParamsReceiverPath, receiver)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return receiver, server.URL
+}
+
+func postTestPreParams(
+	t *testing.T,
+	method string,
+	receiverURL string,
+	secret string,
+	content []byte,
+) int {
+	message, err := newPreParamsBox(secret).Encrypt(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request, err := http.NewRequest(
+		method,
+		receiverURL+PreParamsReceiverPath,
+		bytes.NewReader(message),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	return response.StatusCode
+}
@@ -2,7 +2,10 @@ package tecdsa
 
 import (
 	"crypto/ecdsa"
+	"fmt"
+	"math/big"
 
+	"github.com/bnb-chain/tss-lib/crypto"
 	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
 	"github.com/bnb-chain/tss-lib/tss"
 )
@@ -44,3 +47,98 @@ func (pks *PrivateKeyShare) PublicKey() *ecdsa.PublicKey {
 func (pks *PrivateKeyShare) Data() keygen.LocalPartySaveData {
 	return pks.data
 }
+
+// Validate checks the internal consistency of the private key share. It
+// verifies that the secret share corresponds to the public share declared
+// for this party and that public shares of all signing group members
+// reconstruct the group's ECDSA public key. A share passing the validation
+// can be used to produce signatures verifiable against PublicKey.
+func (pks *PrivateKeyShare) Validate() error {
+	data := pks.data
+
+	if data.LocalSecrets.Xi == nil || data.LocalSecrets.ShareID == nil {
+		return fmt.Errorf("missing secret share")
+	}
+
+	if data.ECDSAPub == nil {
+		return fmt.Errorf("missing public key")
+	}
+
+	if len(data.Ks) == 0 || len(data.Ks) != len(data.BigXj) {
+		return fmt.Errorf(
+			"inconsistent number of share IDs [%v] and public shares [%v]",
+			len(data.Ks),
+			len(data.BigXj),
+		)
+	}
+
+	for j := range data.Ks {
+		if data.Ks[j] == nil {
+			return fmt.Errorf("missing share ID at index [%v]", j)
+		}
+		if data.BigXj[j] == nil {
+			return fmt.Errorf("missing public share at index [%v]", j)
+		}
+	}
+
+	ownIndex := -1
+	for j, kj := range data.Ks {
+		if kj.Cmp(data.ShareID) == 0 {
+			ownIndex = j
+			break
+		}
+	}
+	if ownIndex < 0 {
+		return fmt.Errorf("own share ID not found among share IDs")
+	}
+
+	if !crypto.ScalarBaseMult(Curve, data.Xi).Equals(data.BigXj[ownIndex]) {
+		return fmt.Errorf("secret share does not match own public share")
+	}
+
+	// Interpolate the public shares at zero to reconstruct the public key.
+	// The public shares are points of a polynomial in the exponent whose
+	// free coefficient is the public key. The shares of all members are
+	// used which is valid as long as their number exceeds the polynomial
+	// degree, i.e. the dishonest threshold.
+	order := Curve.Params().N
+
+	var publicKey *crypto.ECPoint
+	for j, kj := range data.Ks {
+		coefficient := big.NewInt(1)
+		for m, km := range data.Ks {
+			if m == j {
+				continue
+			}
+
+			denominator := new(big.Int).Sub(km, kj)
+			denominator.Mod(denominator, order)
+			if denominator.Sign() == 0 {
+				return fmt.Errorf("duplicate share ID at index [%v]", m)
+			}
+
+			coefficient.Mul(coefficient, km)
+			coefficient.Mul(coefficient, new(big.Int).ModInverse(denominator, order))
+			coefficient.Mod(coefficient, order)
+		}
+
+		term := data.BigXj[j].ScalarMult(coefficient)
+
+		if publicKey == nil {
+			publicKey = term
+			continue
+		}
+
+		var err error
+		publicKey, err = publicKey.Add(term)
+		if err != nil {
+			return fmt.Errorf("cannot interpolate public shares: [%w]", err)
+		}
+	}
+
+	if !publicKey.Equals(data.ECDSAPub) {
+		return fmt.Errorf("public shares do not reconstruct the public key")
+	}
+
+	return nil
+}
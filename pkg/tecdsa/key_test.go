@@ -0,0 +1,91 @@
+package tecdsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+
+	"github.com/keep-network/keep-core/pkg/internal/tecdsatest"
+)
+
+func TestPrivateKeyShare_Validate(t *testing.T) {
+	testData, err := tecdsatest.LoadPrivateKeyShareTestFixtures(1)
+	if err != nil {
+		t.Fatalf("failed to load test data: [%v]", err)
+	}
+
+	var tests = map[string]struct {
+		modifyFn      func(data *keygen.LocalPartySaveData)
+		expectedError string
+	}{
+		"valid share": {
+			modifyFn: func(data *keygen.LocalPartySaveData) {},
+		},
+		"secret share not matching own public share": {
+			modifyFn: func(data *keygen.LocalPartySaveData) {
+				data.Xi = new(big.Int).Add(data.Xi, big.NewInt(1))
+			},
+			expectedError: "secret share does not match own public share",
+		},
+		"public shares not reconstructing public key": {
+			modifyFn: func(data *keygen.LocalPartySaveData) {
+				data.ECDSAPub = crypto.ScalarBaseMult(Curve, big.NewInt(7))
+			},
+			expectedError: "public shares do not reconstruct the public key",
+		},
+		"own share ID missing": {
+			modifyFn: func(data *keygen.LocalPartySaveData) {
+				data.ShareID = big.NewInt(1)
+			},
+			expectedError: "own share ID not found among share IDs",
+		},
+		"inconsistent public shares": {
+			modifyFn: func(data *keygen.LocalPartySaveData) {
+				data.BigXj = data.BigXj[1:]
+			},
+			expectedError: "inconsistent number of share IDs [5] and public shares [4]",
+		},
+		"share ID missing": {
+			modifyFn: func(data *keygen.LocalPartySaveData) {
+				data.Ks[3] = nil
+			},
+			expectedError: "missing share ID at index [3]",
+		},
+		"public share missing": {
+			modifyFn: func(data *keygen.LocalPartySaveData) {
+				data.BigXj[2] = nil
+			},
+			expectedError: "missing public share at index [2]",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			// Copy slices to not affect other test cases.
+			data := testData[0]
+			data.Ks = append([]*big.Int{}, data.Ks...)
+			data.BigXj = append([]*crypto.ECPoint{}, data.BigXj...)
+
+			test.modifyFn(&data)
+
+			err := NewPrivateKeyShare(data).Validate()
+
+			if test.expectedError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: [%v]", err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != test.expectedError {
+				t.Errorf(
+					"unexpected error\nexpected: [%v]\nactual:   [%v]",
+					test.expectedError,
+					err,
+				)
+			}
+		})
+	}
+}
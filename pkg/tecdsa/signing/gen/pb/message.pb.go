@@ -658,6 +658,85 @@ func (x *TSSRoundNineMessage) GetSessionID() string {
 	return ""
 }
 
+type Presignature struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MembersIndexes []uint32 `protobuf:"varint,1,rep,packed,name=membersIndexes,proto3" json:"membersIndexes,omitempty"`
+	K              []byte   `protobuf:"bytes,2,opt,name=k,proto3" json:"k,omitempty"`
+	Sigma          []byte   `protobuf:"bytes,3,opt,name=sigma,proto3" json:"sigma,omitempty"`
+	BigRX          []byte   `protobuf:"bytes,4,opt,name=bigRX,proto3" json:"bigRX,omitempty"`
+	BigRY          []byte   `protobuf:"bytes,5,opt,name=bigRY,proto3" json:"bigRY,omitempty"`
+}
+
+func (x *Presignature) Reset() {
+	*x = Presignature{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_tecdsa_signing_gen_pb_message_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Presignature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Presignature) ProtoMessage() {}
+
+func (x *Presignature) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_tecdsa_signing_gen_pb_message_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Presignature.ProtoReflect.Descriptor instead.
+func (*Presignature) Descriptor() ([]byte, []int) {
+	return file_pkg_tecdsa_signing_gen_pb_message_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Presignature) GetMembersIndexes() []uint32 {
+	if x != nil {
+		return x.MembersIndexes
+	}
+	return nil
+}
+
+func (x *Presignature) GetK() []byte {
+	if x != nil {
+		return x.K
+	}
+	return nil
+}
+
+func (x *Presignature) GetSigma() []byte {
+	if x != nil {
+		return x.Sigma
+	}
+	return nil
+}
+
+func (x *Presignature) GetBigRX() []byte {
+	if x != nil {
+		return x.BigRX
+	}
+	return nil
+}
+
+func (x *Presignature) GetBigRY() []byte {
+	if x != nil {
+		return x.BigRY
+	}
+	return nil
+}
+
 var File_pkg_tecdsa_signing_gen_pb_message_proto protoreflect.FileDescriptor
 
 var file_pkg_tecdsa_signing_gen_pb_message_proto_rawDesc = []byte{
@@ -767,8 +846,16 @@ var file_pkg_tecdsa_signing_gen_pb_message_proto_rawDesc = []byte{
 	0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x50,
 	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
 	0x6e, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69,
-	0x6f, 0x6e, 0x49, 0x44, 0x42, 0x06, 0x5a, 0x04, 0x2e, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x6e, 0x49, 0x44, 0x22, 0x86, 0x01, 0x0a, 0x0c, 0x50, 0x72, 0x65, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0e, 0x6d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x12, 0x0c, 0x0a,
+	0x01, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x01, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x69, 0x67, 0x6d, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x73, 0x69, 0x67, 0x6d,
+	0x61, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x69, 0x67, 0x52, 0x58, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x62, 0x69, 0x67, 0x52, 0x58, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x69, 0x67, 0x52, 0x59,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x62, 0x69, 0x67, 0x52, 0x59, 0x42, 0x06, 0x5a,
+	0x04, 0x2e, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -783,7 +870,7 @@ func file_pkg_tecdsa_signing_gen_pb_message_proto_rawDescGZIP() []byte {
 	return file_pkg_tecdsa_signing_gen_pb_message_proto_rawDescData
 }
 
-var file_pkg_tecdsa_signing_gen_pb_message_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_pkg_tecdsa_signing_gen_pb_message_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_pkg_tecdsa_signing_gen_pb_message_proto_goTypes = []interface{}{
 	(*EphemeralPublicKeyMessage)(nil), // 0: signing.EphemeralPublicKeyMessage
 	(*TSSRoundOneMessage)(nil),        // 1: signing.TSSRoundOneMessage
@@ -795,14 +882,15 @@ var file_pkg_tecdsa_signing_gen_pb_message_proto_goTypes = []interface{}{
 	(*TSSRoundSevenMessage)(nil),      // 7: signing.TSSRoundSevenMessage
 	(*TSSRoundEightMessage)(nil),      // 8: signing.TSSRoundEightMessage
 	(*TSSRoundNineMessage)(nil),       // 9: signing.TSSRoundNineMessage
-	nil,                               // 10: signing.EphemeralPublicKeyMessage.EphemeralPublicKeysEntry
-	nil,                               // 11: signing.TSSRoundOneMessage.PeersPayloadEntry
-	nil,                               // 12: signing.TSSRoundTwoMessage.PeersPayloadEntry
+	(*Presignature)(nil),              // 10: signing.Presignature
+	nil,                               // 11: signing.EphemeralPublicKeyMessage.EphemeralPublicKeysEntry
+	nil,                               // 12: signing.TSSRoundOneMessage.PeersPayloadEntry
+	nil,                               // 13: signing.TSSRoundTwoMessage.PeersPayloadEntry
 }
 var file_pkg_tecdsa_signing_gen_pb_message_proto_depIdxs = []int32{
-	10, // 0: signing.EphemeralPublicKeyMessage.ephemeralPublicKeys:type_name -> signing.EphemeralPublicKeyMessage.EphemeralPublicKeysEntry
-	11, // 1: signing.TSSRoundOneMessage.peersPayload:type_name -> signing.TSSRoundOneMessage.PeersPayloadEntry
-	12, // 2: signing.TSSRoundTwoMessage.peersPayload:type_name -> signing.TSSRoundTwoMessage.PeersPayloadEntry
+	11, // 0: signing.EphemeralPublicKeyMessage.ephemeralPublicKeys:type_name -> signing.EphemeralPublicKeyMessage.EphemeralPublicKeysEntry
+	12, // 1: signing.TSSRoundOneMessage.peersPayload:type_name -> signing.TSSRoundOneMessage.PeersPayloadEntry
+	13, // 2: signing.TSSRoundTwoMessage.peersPayload:type_name -> signing.TSSRoundTwoMessage.PeersPayloadEntry
 	3,  // [3:3] is the sub-list for method output_type
 	3,  // [3:3] is the sub-list for method input_type
 	3,  // [3:3] is the sub-list for extension type_name
@@ -936,6 +1024,18 @@ func file_pkg_tecdsa_signing_gen_pb_message_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_tecdsa_signing_gen_pb_message_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Presignature); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -943,7 +1043,7 @@ func file_pkg_tecdsa_signing_gen_pb_message_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_tecdsa_signing_gen_pb_message_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
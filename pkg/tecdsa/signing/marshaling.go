@@ -2,11 +2,14 @@ package signing
 
 import (
 	"fmt"
+	"math/big"
 
+	"github.com/bnb-chain/tss-lib/crypto"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/keep-network/keep-core/pkg/crypto/ephemeral"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa"
 	"github.com/keep-network/keep-core/pkg/tecdsa/signing/gen/pb"
 )
 
@@ -331,6 +334,70 @@ func (trnm *tssRoundNineMessage) Unmarshal(bytes []byte) error {
 	return nil
 }
 
+// Marshal converts this Presignature to a byte array suitable for storing
+// it. A consumed presignature cannot be marshaled.
+func (p *Presignature) Marshal() ([]byte, error) {
+	p.consumedMutex.Lock()
+	defer p.consumedMutex.Unlock()
+
+	if p.k == nil {
+		return nil, errPresignatureConsumed
+	}
+
+	membersIndexes := make([]uint32, len(p.membersIndexes))
+	for i, memberIndex := range p.membersIndexes {
+		membersIndexes[i] = uint32(memberIndex)
+	}
+
+	return proto.Marshal(&pb.Presignature{
+		MembersIndexes: membersIndexes,
+		K:              p.k.Bytes(),
+		Sigma:          p.sigma.Bytes(),
+		BigRX:          p.bigR.X().Bytes(),
+		BigRY:          p.bigR.Y().Bytes(),
+	})
+}
+
+// Unmarshal converts a byte array produced by Marshal to a Presignature.
+func (p *Presignature) Unmarshal(bytes []byte) error {
+	pbPresignature := pb.Presignature{}
+	if err := proto.Unmarshal(bytes, &pbPresignature); err != nil {
+		return err
+	}
+
+	if len(pbPresignature.MembersIndexes) == 0 {
+		return fmt.Errorf("presignature has no members")
+	}
+
+	if len(pbPresignature.K) == 0 || len(pbPresignature.Sigma) == 0 {
+		return fmt.Errorf("presignature has no secret shares")
+	}
+
+	membersIndexes := make([]group.MemberIndex, len(pbPresignature.MembersIndexes))
+	for i, memberIndex := range pbPresignature.MembersIndexes {
+		if err := validateMemberIndex(memberIndex); err != nil {
+			return err
+		}
+		membersIndexes[i] = group.MemberIndex(memberIndex)
+	}
+
+	bigR, err := crypto.NewECPoint(
+		tecdsa.Curve,
+		new(big.Int).SetBytes(pbPresignature.BigRX),
+		new(big.Int).SetBytes(pbPresignature.BigRY),
+	)
+	if err != nil {
+		return fmt.Errorf("invalid signature nonce point: [%w]", err)
+	}
+
+	p.membersIndexes = membersIndexes
+	p.k = new(big.Int).SetBytes(pbPresignature.K)
+	p.sigma = new(big.Int).SetBytes(pbPresignature.Sigma)
+	p.bigR = bigR
+
+	return nil
+}
+
 func validateMemberIndex(protoIndex uint32) error {
 	// Protobuf does not have uint8 type, so we are using uint32. When
 	// unmarshalling message, we need to make sure we do not overflow.
@@ -1,10 +1,13 @@
 package signing
 
 import (
+	"github.com/bnb-chain/tss-lib/crypto"
 	fuzz "github.com/google/gofuzz"
 	"github.com/keep-network/keep-core/pkg/crypto/ephemeral"
 	"github.com/keep-network/keep-core/pkg/internal/pbutils"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa"
+	"math/big"
 	"reflect"
 	"testing"
 )
@@ -512,3 +515,49 @@ func TestFuzzTssRoundNineMessage_MarshalingRoundtrip(t *testing.T) {
 func TestFuzzTssRoundNineMessage_Unmarshaler(t *testing.T) {
 	pbutils.FuzzUnmarshaler(&tssRoundNineMessage{})
 }
+
+func TestPresignature_MarshalingRoundtrip(t *testing.T) {
+	presignature := &Presignature{
+		membersIndexes: []group.MemberIndex{1, 3, 5},
+		k:              big.NewInt(100),
+		sigma:          big.NewInt(200),
+		bigR:           crypto.ScalarBaseMult(tecdsa.Curve, big.NewInt(300)),
+	}
+	unmarshaled := &Presignature{}
+
+	bytes, err := presignature.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = unmarshaled.Unmarshal(bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(presignature, unmarshaled) {
+		t.Fatalf("unexpected content of unmarshaled presignature")
+	}
+}
+
+func TestPresignature_MarshalingConsumed(t *testing.T) {
+	presignature := &Presignature{
+		membersIndexes: []group.MemberIndex{1, 3, 5},
+		k:              big.NewInt(100),
+		sigma:          big.NewInt(200),
+		bigR:           crypto.ScalarBaseMult(tecdsa.Curve, big.NewInt(300)),
+	}
+
+	if _, _, err := presignature.consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := presignature.Marshal()
+	if err != errPresignatureConsumed {
+		t.Fatalf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			errPresignatureConsumed,
+			err,
+		)
+	}
+}
@@ -34,6 +34,9 @@ type member struct {
 	privateKeyShare *tecdsa.PrivateKeyShare
 	// Instance of the member identity converter.
 	identityConverter *identityConverter
+	// Indicates whether the member computes a presignature instead of
+	// signing the message.
+	presigning bool
 }
 
 // newMember creates a new member in an initial state
@@ -0,0 +1,78 @@
+package signing
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa"
+)
+
+// errPresignatureConsumed is returned when a presignature that has been
+// already used is used again.
+var errPresignatureConsumed = fmt.Errorf("presignature has been already consumed")
+
+// Presignature is the message-independent part of a tECDSA signature
+// computed in advance by a signing group member together with other members
+// of the signing group. A presignature allows signing a message using only
+// the message-dependent rounds of the signing protocol. It can be used to
+// sign exactly one message and only together with exactly the same set of
+// members that computed it. Using one presignature to sign two different
+// messages reveals the private key so the presignature is erased once it
+// is used, regardless of the signing outcome.
+type Presignature struct {
+	// Indexes of the signing group members that computed the presignature.
+	membersIndexes []group.MemberIndex
+	// Member's share of the signature nonce k.
+	k *big.Int
+	// Member's share of the product of the signature nonce k and the
+	// private key.
+	sigma *big.Int
+	// Signature nonce point R = g^(k^-1), common for all members that
+	// computed the presignature.
+	bigR *crypto.ECPoint
+
+	consumedMutex sync.Mutex
+}
+
+// MembersIndexes returns the indexes of the signing group members that
+// computed the presignature and must take part in the signing using it.
+func (p *Presignature) MembersIndexes() []group.MemberIndex {
+	membersIndexes := make([]group.MemberIndex, len(p.membersIndexes))
+	copy(membersIndexes, p.membersIndexes)
+	return membersIndexes
+}
+
+// PublicNonce returns the signature nonce point R in the uncompressed form.
+// The point is common for all members that computed the presignature so it
+// can be used to check whether all of them computed the same presignature.
+func (p *Presignature) PublicNonce() []byte {
+	return elliptic.Marshal(tecdsa.Curve, p.bigR.X(), p.bigR.Y())
+}
+
+// IsConsumed returns true if the presignature has been already used.
+func (p *Presignature) IsConsumed() bool {
+	p.consumedMutex.Lock()
+	defer p.consumedMutex.Unlock()
+
+	return p.k == nil
+}
+
+// consume returns the member's secret shares of the presignature and erases
+// them from the presignature so it can never be used again.
+func (p *Presignature) consume() (*big.Int, *big.Int, error) {
+	p.consumedMutex.Lock()
+	defer p.consumedMutex.Unlock()
+
+	if p.k == nil {
+		return nil, nil, errPresignatureConsumed
+	}
+
+	k, sigma := p.k, p.sigma
+	p.k, p.sigma = nil, nil
+
+	return k, sigma, nil
+}
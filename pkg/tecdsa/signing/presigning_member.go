@@ -0,0 +1,167 @@
+package signing
+
+import (
+	"math/big"
+
+	tsslibcommon "github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/keep-network/keep-core/pkg/tecdsa"
+	"github.com/keep-network/keep-core/pkg/tecdsa/common"
+)
+
+// The TSS library executes all rounds of the GG18 signing protocol in one
+// party and does not allow to persist the state of an unfinished signing.
+// That is why the presigning and presigned members execute the respective
+// rounds of the protocol on their own, using the TSS library primitives and
+// message formats. Rounds one to four together with the computation of the
+// signature nonce point R do not depend on the signed message and are
+// executed by presigning members. Rounds five to nine and the signature
+// finalization depend on the message and are executed by presigned members.
+
+// initializePresigning returns a member to compute a presignature.
+func (skgm *symmetricKeyGeneratingMember) initializePresigning() *presigningMember {
+	tssParameters := newTssParameters(skgm.member)
+	partiesCount := len(tssParameters.Parties().IDs())
+
+	// Restrict the key share data to the operating members. Data of the
+	// resulting key share are ordered the same way as sorted TSS parties.
+	keyData := keygen.BuildLocalSaveDataSubset(
+		skgm.privateKeyShare.Data(),
+		tssParameters.Parties().IDs(),
+	)
+
+	w, bigWs := signing.PrepareForSigning(
+		tssParameters.EC(),
+		tssParameters.PartyID().Index,
+		partiesCount,
+		keyData.Xi,
+		keyData.Ks,
+		keyData.BigXj,
+	)
+
+	return &presigningMember{
+		symmetricKeyGeneratingMember: skgm,
+		tssParameters:                tssParameters,
+		keyData:                      keyData,
+		w:                            w,
+		bigWs:                        bigWs,
+		cis:                          make([]*big.Int, partiesCount),
+		betas:                        make([]*big.Int, partiesCount),
+		vs:                           make([]*big.Int, partiesCount),
+		bigGammaCommitments:          make([]*big.Int, partiesCount),
+	}
+}
+
+// presigningMember represents one member of a signing group computing
+// a presignature together with other operating members of the group.
+type presigningMember struct {
+	*symmetricKeyGeneratingMember
+
+	tssParameters *tss.Parameters
+	keyData       keygen.LocalPartySaveData
+
+	// Additive share of the private key, and the points corresponding to
+	// additive shares of all operating members.
+	w     *big.Int
+	bigWs []*crypto.ECPoint
+
+	k            *big.Int
+	gamma        *big.Int
+	bigGamma     *crypto.ECPoint
+	bigGammaDe   commitments.HashDeCommitment
+	theta        *big.Int
+	sigma        *big.Int
+	thetaInverse *big.Int
+
+	// Values exchanged with other operating members, indexed by TSS party
+	// indexes.
+	cis                 []*big.Int
+	betas               []*big.Int
+	vs                  []*big.Int
+	bigGammaCommitments []*big.Int
+
+	presignature *Presignature
+}
+
+// Presignature returns the computed presignature.
+func (pm *presigningMember) Presignature() *Presignature {
+	return pm.presignature
+}
+
+// initializePresignedSigning returns a member to sign the message using the
+// given presignature.
+func (m *member) initializePresignedSigning(
+	k *big.Int,
+	sigma *big.Int,
+	bigR *crypto.ECPoint,
+) *presignedMember {
+	tssParameters := newTssParameters(m)
+	partiesCount := len(tssParameters.Parties().IDs())
+
+	return &presignedMember{
+		member:          m,
+		tssParameters:   tssParameters,
+		k:               k,
+		sigma:           sigma,
+		bigR:            bigR,
+		viaiCommitments: make([]*big.Int, partiesCount),
+		uitiCommitments: make([]*big.Int, partiesCount),
+	}
+}
+
+// presignedMember represents one member of a signing group signing the
+// message using a presignature together with other operating members of
+// the group.
+type presignedMember struct {
+	*member
+
+	tssParameters *tss.Parameters
+
+	// Presignature shares.
+	k     *big.Int
+	sigma *big.Int
+	bigR  *crypto.ECPoint
+
+	si     *big.Int
+	li     *big.Int
+	roi    *big.Int
+	bigVi  *crypto.ECPoint
+	bigAi  *crypto.ECPoint
+	bigUi  *crypto.ECPoint
+	bigTi  *crypto.ECPoint
+	viaiDe commitments.HashDeCommitment
+	uitiDe commitments.HashDeCommitment
+
+	// Commitments of other operating members, indexed by TSS party indexes.
+	viaiCommitments []*big.Int
+	uitiCommitments []*big.Int
+
+	tssResult *tsslibcommon.SignatureData
+}
+
+// Result is a successful computation of the tECDSA signature.
+func (pm *presignedMember) Result() *Result {
+	return &Result{Signature: tecdsa.NewSignature(pm.tssResult)}
+}
+
+// newTssParameters returns the TSS parameters for the operating members
+// of the given member's group.
+func newTssParameters(m *member) *tss.Parameters {
+	tssPartyID, groupTssPartiesIDs := common.GenerateTssPartiesIDs(
+		m.id,
+		m.group.OperatingMemberIndexes(),
+		m.identityConverter,
+	)
+
+	return tss.NewParameters(
+		tecdsa.Curve,
+		tss.NewPeerContext(tss.SortPartyIDs(groupTssPartiesIDs)),
+		tssPartyID,
+		len(groupTssPartiesIDs),
+		m.group.HonestThreshold()-1,
+	)
+}
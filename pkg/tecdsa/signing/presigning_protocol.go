@@ -0,0 +1,1041 @@
+package signing
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sync"
+
+	tsslibcommon "github.com/bnb-chain/tss-lib/common"
+	"github.com/bnb-chain/tss-lib/crypto"
+	"github.com/bnb-chain/tss-lib/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/crypto/mta"
+	"github.com/bnb-chain/tss-lib/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/tss"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/tecdsa/common"
+)
+
+// presigningRoundOne starts the presigning process by executing the first
+// round of the TSS signing. The member generates its shares of the signature
+// nonce k and the blinding factor gamma, commits to the gamma point and
+// initializes the multiplicative-to-additive share conversion of k with every
+// other operating member.
+func (pm *presigningMember) presigningRoundOne() (*tssRoundOneMessage, error) {
+	curve := pm.tssParameters.EC()
+	partyID := pm.tssParameters.PartyID()
+	i := partyID.Index
+
+	pm.k = tsslibcommon.GetRandomPositiveInt(curve.Params().N)
+	pm.gamma = tsslibcommon.GetRandomPositiveInt(curve.Params().N)
+	pm.bigGamma = crypto.ScalarBaseMult(curve, pm.gamma)
+
+	bigGammaCommitment := commitments.NewHashCommitment(
+		pm.bigGamma.X(),
+		pm.bigGamma.Y(),
+	)
+	pm.bigGammaDe = bigGammaCommitment.D
+
+	var tssMessages []tss.Message
+	for j, otherPartyID := range pm.tssParameters.Parties().IDs() {
+		if j == i {
+			continue
+		}
+
+		cA, rangeProof, err := mta.AliceInit(
+			curve,
+			pm.keyData.PaillierPKs[i],
+			pm.k,
+			pm.keyData.NTildej[j],
+			pm.keyData.H1j[j],
+			pm.keyData.H2j[j],
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot initialize MtA with member [%v]: [%v]",
+				pm.identityConverter.TssPartyIDToMemberIndex(otherPartyID),
+				err,
+			)
+		}
+
+		pm.cis[j] = cA
+		tssMessages = append(
+			tssMessages,
+			signing.NewSignRound1Message1(otherPartyID, partyID, cA, rangeProof),
+		)
+	}
+
+	tssMessages = append(
+		tssMessages,
+		signing.NewSignRound1Message2(partyID, bigGammaCommitment.C),
+	)
+
+	broadcastPayload, peersPayload, err := common.AggregateTssMessages(
+		tssMessages,
+		pm.symmetricKeys,
+		pm.identityConverter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot aggregate presigning round one messages: [%w]",
+			err,
+		)
+	}
+
+	return &tssRoundOneMessage{
+		senderID:         pm.id,
+		broadcastPayload: broadcastPayload,
+		peersPayload:     peersPayload,
+		sessionID:        pm.sessionID,
+	}, nil
+}
+
+// presigningRoundTwo performs the second round of the TSS signing. The member
+// verifies the range proofs of other members and computes its side of the
+// share conversions of both k*gamma and k*w products.
+func (pm *presigningMember) presigningRoundTwo(
+	tssRoundOneMessages []*tssRoundOneMessage,
+) (*tssRoundTwoMessage, error) {
+	curve := pm.tssParameters.EC()
+	partyID := pm.tssParameters.PartyID()
+	partiesIDs := pm.tssParameters.Parties().IDs()
+	i := partyID.Index
+
+	aliceMessages := make([]*signing.SignRound1Message1, len(partiesIDs))
+	for _, tssRoundOneMessage := range tssRoundOneMessages {
+		senderID := tssRoundOneMessage.SenderID()
+
+		senderPartyID, commitmentMessage, err :=
+			parseTssMessage[*signing.SignRound1Message2](
+				pm.tssParameters,
+				pm.identityConverter,
+				senderID,
+				tssRoundOneMessage.broadcastPayload,
+				true,
+			)
+		if err != nil {
+			return nil, err
+		}
+
+		peerPayload, err := pm.decryptPeerPayload(
+			senderID,
+			tssRoundOneMessage.peersPayload,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		_, aliceMessage, err := parseTssMessage[*signing.SignRound1Message1](
+			pm.tssParameters,
+			pm.identityConverter,
+			senderID,
+			peerPayload,
+			false,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		j := senderPartyID.Index
+		pm.bigGammaCommitments[j] = commitmentMessage.UnmarshalCommitment()
+		aliceMessages[j] = aliceMessage
+	}
+
+	// All messages are validated before the share conversions start so no
+	// conversion is left running if one of the messages is invalid.
+	rangeProofs := make([]*mta.RangeProofAlice, len(partiesIDs))
+	cAs := make([]*big.Int, len(partiesIDs))
+	for j := range partiesIDs {
+		if j == i {
+			continue
+		}
+
+		if aliceMessages[j] == nil {
+			return nil, fmt.Errorf(
+				"no presigning round one message from member [%v]",
+				pm.identityConverter.TssPartyIDToMemberIndex(partiesIDs[j]),
+			)
+		}
+
+		rangeProof, err := aliceMessages[j].UnmarshalRangeProofAlice()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot unmarshal range proof of member [%v]: [%v]",
+				pm.identityConverter.TssPartyIDToMemberIndex(partiesIDs[j]),
+				err,
+			)
+		}
+
+		rangeProofs[j] = rangeProof
+		cAs[j] = aliceMessages[j].UnmarshalC()
+	}
+
+	c1jis := make([]*big.Int, len(partiesIDs))
+	pi1jis := make([]*mta.ProofBob, len(partiesIDs))
+	c2jis := make([]*big.Int, len(partiesIDs))
+	pi2jis := make([]*mta.ProofBobWC, len(partiesIDs))
+
+	// Both share conversions are expensive so they are computed concurrently
+	// for all other members, the same way the TSS library does it.
+	errs := make([]error, 2*len(partiesIDs))
+	wg := sync.WaitGroup{}
+	for j := range partiesIDs {
+		if j == i {
+			continue
+		}
+
+		wg.Add(2)
+		go func(j int) {
+			defer wg.Done()
+			pm.betas[j], c1jis[j], _, pi1jis[j], errs[2*j] = mta.BobMid(
+				curve,
+				pm.keyData.PaillierPKs[j],
+				rangeProofs[j],
+				pm.gamma,
+				cAs[j],
+				pm.keyData.NTildej[j],
+				pm.keyData.H1j[j],
+				pm.keyData.H2j[j],
+				pm.keyData.NTildej[i],
+				pm.keyData.H1j[i],
+				pm.keyData.H2j[i],
+			)
+		}(j)
+		go func(j int) {
+			defer wg.Done()
+			pm.vs[j], c2jis[j], _, pi2jis[j], errs[2*j+1] = mta.BobMidWC(
+				curve,
+				pm.keyData.PaillierPKs[j],
+				rangeProofs[j],
+				pm.w,
+				cAs[j],
+				pm.keyData.NTildej[j],
+				pm.keyData.H1j[j],
+				pm.keyData.H2j[j],
+				pm.keyData.NTildej[i],
+				pm.keyData.H1j[i],
+				pm.keyData.H2j[i],
+				pm.bigWs[i],
+			)
+		}(j)
+	}
+	wg.Wait()
+
+	var tssMessages []tss.Message
+	for j, otherPartyID := range partiesIDs {
+		if j == i {
+			continue
+		}
+
+		for _, err := range errs[2*j : 2*j+2] {
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot compute MtA with member [%v]: [%v]",
+					pm.identityConverter.TssPartyIDToMemberIndex(otherPartyID),
+					err,
+				)
+			}
+		}
+
+		tssMessages = append(
+			tssMessages,
+			signing.NewSignRound2Message(
+				otherPartyID,
+				partyID,
+				c1jis[j],
+				pi1jis[j],
+				c2jis[j],
+				pi2jis[j],
+			),
+		)
+	}
+
+	_, peersPayload, err := common.AggregateTssMessages(
+		tssMessages,
+		pm.symmetricKeys,
+		pm.identityConverter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot aggregate presigning round two messages: [%w]",
+			err,
+		)
+	}
+
+	return &tssRoundTwoMessage{
+		senderID:     pm.id,
+		peersPayload: peersPayload,
+		sessionID:    pm.sessionID,
+	}, nil
+}
+
+// presigningRoundThree performs the third round of the TSS signing. The
+// member completes the share conversions and computes its additive shares
+// theta of k*gamma and sigma of k*w. The theta share is broadcast.
+func (pm *presigningMember) presigningRoundThree(
+	tssRoundTwoMessages []*tssRoundTwoMessage,
+) (*tssRoundThreeMessage, error) {
+	curve := pm.tssParameters.EC()
+	partyID := pm.tssParameters.PartyID()
+	partiesIDs := pm.tssParameters.Parties().IDs()
+	i := partyID.Index
+
+	bobMessages := make([]*signing.SignRound2Message, len(partiesIDs))
+	for _, tssRoundTwoMessage := range tssRoundTwoMessages {
+		senderID := tssRoundTwoMessage.SenderID()
+
+		peerPayload, err := pm.decryptPeerPayload(
+			senderID,
+			tssRoundTwoMessage.peersPayload,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		senderPartyID, bobMessage, err :=
+			parseTssMessage[*signing.SignRound2Message](
+				pm.tssParameters,
+				pm.identityConverter,
+				senderID,
+				peerPayload,
+				false,
+			)
+		if err != nil {
+			return nil, err
+		}
+
+		bobMessages[senderPartyID.Index] = bobMessage
+	}
+
+	// All messages are validated before the share conversions start so no
+	// conversion is left running if one of the messages is invalid.
+	proofsBob := make([]*mta.ProofBob, len(partiesIDs))
+	proofsBobWC := make([]*mta.ProofBobWC, len(partiesIDs))
+	c1jis := make([]*big.Int, len(partiesIDs))
+	c2jis := make([]*big.Int, len(partiesIDs))
+	for j := range partiesIDs {
+		if j == i {
+			continue
+		}
+
+		if bobMessages[j] == nil {
+			return nil, fmt.Errorf(
+				"no presigning round two message from member [%v]",
+				pm.identityConverter.TssPartyIDToMemberIndex(partiesIDs[j]),
+			)
+		}
+
+		proofBob, err := bobMessages[j].UnmarshalProofBob()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot unmarshal Bob proof of member [%v]: [%v]",
+				pm.identityConverter.TssPartyIDToMemberIndex(partiesIDs[j]),
+				err,
+			)
+		}
+		proofBobWC, err := bobMessages[j].UnmarshalProofBobWC(curve)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot unmarshal Bob WC proof of member [%v]: [%v]",
+				pm.identityConverter.TssPartyIDToMemberIndex(partiesIDs[j]),
+				err,
+			)
+		}
+
+		proofsBob[j] = proofBob
+		proofsBobWC[j] = proofBobWC
+		c1jis[j] = new(big.Int).SetBytes(bobMessages[j].GetC1())
+		c2jis[j] = new(big.Int).SetBytes(bobMessages[j].GetC2())
+	}
+
+	alphas := make([]*big.Int, len(partiesIDs))
+	us := make([]*big.Int, len(partiesIDs))
+
+	errs := make([]error, 2*len(partiesIDs))
+	wg := sync.WaitGroup{}
+	for j := range partiesIDs {
+		if j == i {
+			continue
+		}
+
+		wg.Add(2)
+		go func(j int) {
+			defer wg.Done()
+			alphas[j], errs[2*j] = mta.AliceEnd(
+				curve,
+				pm.keyData.PaillierPKs[i],
+				proofsBob[j],
+				pm.keyData.H1j[i],
+				pm.keyData.H2j[i],
+				pm.cis[j],
+				c1jis[j],
+				pm.keyData.NTildej[i],
+				pm.keyData.PaillierSK,
+			)
+		}(j)
+		go func(j int) {
+			defer wg.Done()
+			us[j], errs[2*j+1] = mta.AliceEndWC(
+				curve,
+				pm.keyData.PaillierPKs[i],
+				proofsBobWC[j],
+				pm.bigWs[j],
+				pm.cis[j],
+				c2jis[j],
+				pm.keyData.NTildej[i],
+				pm.keyData.H1j[i],
+				pm.keyData.H2j[i],
+				pm.keyData.PaillierSK,
+			)
+		}(j)
+	}
+	wg.Wait()
+
+	modN := tsslibcommon.ModInt(curve.Params().N)
+	theta := modN.Mul(pm.k, pm.gamma)
+	sigma := modN.Mul(pm.k, pm.w)
+
+	for j, otherPartyID := range partiesIDs {
+		if j == i {
+			continue
+		}
+
+		for _, err := range errs[2*j : 2*j+2] {
+			if err != nil {
+				return nil, fmt.Errorf(
+					"cannot complete MtA with member [%v]: [%v]",
+					pm.identityConverter.TssPartyIDToMemberIndex(otherPartyID),
+					err,
+				)
+			}
+		}
+
+		theta = modN.Add(theta, alphas[j].Add(alphas[j], pm.betas[j]))
+		sigma = modN.Add(sigma, us[j].Add(us[j], pm.vs[j]))
+	}
+
+	pm.theta = theta
+	pm.sigma = sigma
+
+	broadcastPayload, err := tssBroadcastPayload(
+		signing.NewSignRound3Message(partyID, theta),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tssRoundThreeMessage{
+		senderID:         pm.id,
+		broadcastPayload: broadcastPayload,
+		sessionID:        pm.sessionID,
+	}, nil
+}
+
+// presigningRoundFour performs the fourth round of the TSS signing. The
+// member computes the inverse of the sum of all theta shares and reveals
+// its gamma point along with a proof of knowledge of gamma.
+func (pm *presigningMember) presigningRoundFour(
+	tssRoundThreeMessages []*tssRoundThreeMessage,
+) (*tssRoundFourMessage, error) {
+	curve := pm.tssParameters.EC()
+	modN := tsslibcommon.ModInt(curve.Params().N)
+
+	thetaSum := new(big.Int).Set(pm.theta)
+	for _, tssRoundThreeMessage := range tssRoundThreeMessages {
+		_, thetaMessage, err := parseTssMessage[*signing.SignRound3Message](
+			pm.tssParameters,
+			pm.identityConverter,
+			tssRoundThreeMessage.SenderID(),
+			tssRoundThreeMessage.broadcastPayload,
+			true,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		thetaSum = modN.Add(
+			thetaSum,
+			new(big.Int).SetBytes(thetaMessage.GetTheta()),
+		)
+	}
+
+	pm.thetaInverse = modN.ModInverse(thetaSum)
+	if pm.thetaInverse == nil {
+		return nil, fmt.Errorf("sum of theta shares is not invertible")
+	}
+
+	gammaProof, err := schnorr.NewZKProof(pm.gamma, pm.bigGamma)
+	if err != nil {
+		return nil, fmt.Errorf("cannot prove knowledge of gamma: [%v]", err)
+	}
+
+	broadcastPayload, err := tssBroadcastPayload(
+		signing.NewSignRound4Message(
+			pm.tssParameters.PartyID(),
+			pm.bigGammaDe,
+			gammaProof,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tssRoundFourMessage{
+		senderID:         pm.id,
+		broadcastPayload: broadcastPayload,
+		sessionID:        pm.sessionID,
+	}, nil
+}
+
+// presigningFinalize completes the presigning process. The member opens
+// the gamma points of other members, verifies their proofs and computes
+// the signature nonce point R. The member's secret shares are moved to the
+// resulting presignature.
+func (pm *presigningMember) presigningFinalize(
+	tssRoundFourMessages []*tssRoundFourMessage,
+) error {
+	curve := pm.tssParameters.EC()
+
+	bigR := pm.bigGamma
+	for _, tssRoundFourMessage := range tssRoundFourMessages {
+		senderID := tssRoundFourMessage.SenderID()
+
+		senderPartyID, gammaMessage, err :=
+			parseTssMessage[*signing.SignRound4Message](
+				pm.tssParameters,
+				pm.identityConverter,
+				senderID,
+				tssRoundFourMessage.broadcastPayload,
+				true,
+			)
+		if err != nil {
+			return err
+		}
+
+		commitment := commitments.HashCommitDecommit{
+			C: pm.bigGammaCommitments[senderPartyID.Index],
+			D: gammaMessage.UnmarshalDeCommitment(),
+		}
+		ok, bigGammaCoordinates := commitment.DeCommit()
+		if !ok || len(bigGammaCoordinates) != 2 {
+			return fmt.Errorf(
+				"cannot open gamma point commitment of member [%v]",
+				senderID,
+			)
+		}
+
+		bigGamma, err := crypto.NewECPoint(
+			curve,
+			bigGammaCoordinates[0],
+			bigGammaCoordinates[1],
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"invalid gamma point of member [%v]: [%v]",
+				senderID,
+				err,
+			)
+		}
+
+		gammaProof, err := gammaMessage.UnmarshalZKProof(curve)
+		if err != nil || !gammaProof.Verify(bigGamma) {
+			return fmt.Errorf(
+				"invalid gamma proof of member [%v]",
+				senderID,
+			)
+		}
+
+		bigR, err = bigR.Add(bigGamma)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot add gamma point of member [%v]: [%v]",
+				senderID,
+				err,
+			)
+		}
+	}
+
+	pm.presignature = &Presignature{
+		membersIndexes: pm.group.OperatingMemberIndexes(),
+		k:              pm.k,
+		sigma:          pm.sigma,
+		bigR:           bigR.ScalarMult(pm.thetaInverse),
+	}
+
+	// Secret shares must live only in the presignature from now on.
+	pm.k, pm.sigma, pm.w, pm.gamma = nil, nil, nil, nil
+
+	return nil
+}
+
+// presignedRoundFive performs the fifth round of the TSS signing which is
+// the first round of signing with a presignature. The member computes its
+// signature share and commits to the points used to prove it.
+func (pm *presignedMember) presignedRoundFive() (*tssRoundFiveMessage, error) {
+	curve := pm.tssParameters.EC()
+	modN := tsslibcommon.ModInt(curve.Params().N)
+
+	pm.si = modN.Add(
+		modN.Mul(pm.message, pm.k),
+		modN.Mul(pm.bigR.X(), pm.sigma),
+	)
+
+	// The signature share is computed so the presignature shares are no
+	// longer needed.
+	pm.k, pm.sigma = nil, nil
+
+	pm.li = tsslibcommon.GetRandomPositiveInt(curve.Params().N)
+	pm.roi = tsslibcommon.GetRandomPositiveInt(curve.Params().N)
+	pm.bigAi = crypto.ScalarBaseMult(curve, pm.roi)
+
+	bigVi, err := pm.bigR.ScalarMult(pm.si).Add(
+		crypto.ScalarBaseMult(curve, pm.li),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute V point: [%v]", err)
+	}
+	pm.bigVi = bigVi
+
+	commitment := commitments.NewHashCommitment(
+		pm.bigVi.X(),
+		pm.bigVi.Y(),
+		pm.bigAi.X(),
+		pm.bigAi.Y(),
+	)
+	pm.viaiDe = commitment.D
+
+	broadcastPayload, err := tssBroadcastPayload(
+		signing.NewSignRound5Message(pm.tssParameters.PartyID(), commitment.C),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tssRoundFiveMessage{
+		senderID:         pm.id,
+		broadcastPayload: broadcastPayload,
+		sessionID:        pm.sessionID,
+	}, nil
+}
+
+// presignedRoundSix performs the sixth round of the TSS signing. The member
+// opens its commitment from round five and proves the knowledge of secrets
+// behind the committed points.
+func (pm *presignedMember) presignedRoundSix(
+	tssRoundFiveMessages []*tssRoundFiveMessage,
+) (*tssRoundSixMessage, error) {
+	for _, tssRoundFiveMessage := range tssRoundFiveMessages {
+		senderPartyID, commitmentMessage, err :=
+			parseTssMessage[*signing.SignRound5Message](
+				pm.tssParameters,
+				pm.identityConverter,
+				tssRoundFiveMessage.SenderID(),
+				tssRoundFiveMessage.broadcastPayload,
+				true,
+			)
+		if err != nil {
+			return nil, err
+		}
+
+		pm.viaiCommitments[senderPartyID.Index] =
+			commitmentMessage.UnmarshalCommitment()
+	}
+
+	aiProof, err := schnorr.NewZKProof(pm.roi, pm.bigAi)
+	if err != nil {
+		return nil, fmt.Errorf("cannot prove knowledge of A point: [%v]", err)
+	}
+	viProof, err := schnorr.NewZKVProof(pm.bigVi, pm.bigR, pm.si, pm.li)
+	if err != nil {
+		return nil, fmt.Errorf("cannot prove knowledge of V point: [%v]", err)
+	}
+
+	broadcastPayload, err := tssBroadcastPayload(
+		signing.NewSignRound6Message(
+			pm.tssParameters.PartyID(),
+			pm.viaiDe,
+			aiProof,
+			viProof,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tssRoundSixMessage{
+		senderID:         pm.id,
+		broadcastPayload: broadcastPayload,
+		sessionID:        pm.sessionID,
+	}, nil
+}
+
+// presignedRoundSeven performs the seventh round of the TSS signing. The
+// member opens commitments of other members, verifies their proofs and
+// commits to its U and T points used to check the signature shares.
+func (pm *presignedMember) presignedRoundSeven(
+	tssRoundSixMessages []*tssRoundSixMessage,
+) (*tssRoundSevenMessage, error) {
+	curve := pm.tssParameters.EC()
+	modN := tsslibcommon.ModInt(curve.Params().N)
+	publicKey := pm.privateKeyShare.Data().ECDSAPub
+
+	// V = g^(-m) * y^(-r) * Π(Vj), A = Π(Aj)
+	minusM := modN.Sub(big.NewInt(0), pm.message)
+	minusR := modN.Sub(big.NewInt(0), pm.bigR.X())
+	gToMinusMX, gToMinusMY := curve.ScalarBaseMult(minusM.Bytes())
+	yToMinusRX, yToMinusRY := curve.ScalarMult(
+		publicKey.X(),
+		publicKey.Y(),
+		minusR.Bytes(),
+	)
+	vx, vy := curve.Add(gToMinusMX, gToMinusMY, yToMinusRX, yToMinusRY)
+	vx, vy = curve.Add(vx, vy, pm.bigVi.X(), pm.bigVi.Y())
+	ax, ay := pm.bigAi.X(), pm.bigAi.Y()
+
+	for _, tssRoundSixMessage := range tssRoundSixMessages {
+		senderID := tssRoundSixMessage.SenderID()
+
+		senderPartyID, proofsMessage, err :=
+			parseTssMessage[*signing.SignRound6Message](
+				pm.tssParameters,
+				pm.identityConverter,
+				senderID,
+				tssRoundSixMessage.broadcastPayload,
+				true,
+			)
+		if err != nil {
+			return nil, err
+		}
+
+		commitment := commitments.HashCommitDecommit{
+			C: pm.viaiCommitments[senderPartyID.Index],
+			D: proofsMessage.UnmarshalDeCommitment(),
+		}
+		ok, values := commitment.DeCommit()
+		if !ok || len(values) != 4 {
+			return nil, fmt.Errorf(
+				"cannot open V and A points commitment of member [%v]",
+				senderID,
+			)
+		}
+
+		bigVj, err := crypto.NewECPoint(curve, values[0], values[1])
+		if err != nil {
+			return nil, fmt.Errorf(
+				"invalid V point of member [%v]: [%v]",
+				senderID,
+				err,
+			)
+		}
+		bigAj, err := crypto.NewECPoint(curve, values[2], values[3])
+		if err != nil {
+			return nil, fmt.Errorf(
+				"invalid A point of member [%v]: [%v]",
+				senderID,
+				err,
+			)
+		}
+
+		ajProof, err := proofsMessage.UnmarshalZKProof(curve)
+		if err != nil || !ajProof.Verify(bigAj) {
+			return nil, fmt.Errorf(
+				"invalid A point proof of member [%v]",
+				senderID,
+			)
+		}
+		vjProof, err := proofsMessage.UnmarshalZKVProof(curve)
+		if err != nil || !vjProof.Verify(bigVj, pm.bigR) {
+			return nil, fmt.Errorf(
+				"invalid V point proof of member [%v]",
+				senderID,
+			)
+		}
+
+		vx, vy = curve.Add(vx, vy, bigVj.X(), bigVj.Y())
+		ax, ay = curve.Add(ax, ay, bigAj.X(), bigAj.Y())
+	}
+
+	uix, uiy := curve.ScalarMult(vx, vy, pm.roi.Bytes())
+	tix, tiy := curve.ScalarMult(ax, ay, pm.li.Bytes())
+	pm.bigUi = crypto.NewECPointNoCurveCheck(curve, uix, uiy)
+	pm.bigTi = crypto.NewECPointNoCurveCheck(curve, tix, tiy)
+
+	commitment := commitments.NewHashCommitment(uix, uiy, tix, tiy)
+	pm.uitiDe = commitment.D
+
+	broadcastPayload, err := tssBroadcastPayload(
+		signing.NewSignRound7Message(pm.tssParameters.PartyID(), commitment.C),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tssRoundSevenMessage{
+		senderID:         pm.id,
+		broadcastPayload: broadcastPayload,
+		sessionID:        pm.sessionID,
+	}, nil
+}
+
+// presignedRoundEight performs the eighth round of the TSS signing. The
+// member opens its commitment from round seven.
+func (pm *presignedMember) presignedRoundEight(
+	tssRoundSevenMessages []*tssRoundSevenMessage,
+) (*tssRoundEightMessage, error) {
+	for _, tssRoundSevenMessage := range tssRoundSevenMessages {
+		senderPartyID, commitmentMessage, err :=
+			parseTssMessage[*signing.SignRound7Message](
+				pm.tssParameters,
+				pm.identityConverter,
+				tssRoundSevenMessage.SenderID(),
+				tssRoundSevenMessage.broadcastPayload,
+				true,
+			)
+		if err != nil {
+			return nil, err
+		}
+
+		pm.uitiCommitments[senderPartyID.Index] =
+			commitmentMessage.UnmarshalCommitment()
+	}
+
+	broadcastPayload, err := tssBroadcastPayload(
+		signing.NewSignRound8Message(pm.tssParameters.PartyID(), pm.uitiDe),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tssRoundEightMessage{
+		senderID:         pm.id,
+		broadcastPayload: broadcastPayload,
+		sessionID:        pm.sessionID,
+	}, nil
+}
+
+// presignedRoundNine performs the ninth round of the TSS signing. The member
+// opens commitments of other members and reveals its signature share only
+// if the sums of all U and T points are equal.
+func (pm *presignedMember) presignedRoundNine(
+	tssRoundEightMessages []*tssRoundEightMessage,
+) (*tssRoundNineMessage, error) {
+	curve := pm.tssParameters.EC()
+
+	ux, uy := pm.bigUi.X(), pm.bigUi.Y()
+	tx, ty := pm.bigTi.X(), pm.bigTi.Y()
+
+	for _, tssRoundEightMessage := range tssRoundEightMessages {
+		senderID := tssRoundEightMessage.SenderID()
+
+		senderPartyID, decommitmentMessage, err :=
+			parseTssMessage[*signing.SignRound8Message](
+				pm.tssParameters,
+				pm.identityConverter,
+				senderID,
+				tssRoundEightMessage.broadcastPayload,
+				true,
+			)
+		if err != nil {
+			return nil, err
+		}
+
+		commitment := commitments.HashCommitDecommit{
+			C: pm.uitiCommitments[senderPartyID.Index],
+			D: decommitmentMessage.UnmarshalDeCommitment(),
+		}
+		// Unlike the TSS library, reject the decommitment if any of the
+		// checks fails.
+		ok, values := commitment.DeCommit()
+		if !ok || len(values) != 4 {
+			return nil, fmt.Errorf(
+				"cannot open U and T points commitment of member [%v]",
+				senderID,
+			)
+		}
+
+		ux, uy = curve.Add(ux, uy, values[0], values[1])
+		tx, ty = curve.Add(tx, ty, values[2], values[3])
+	}
+
+	if ux.Cmp(tx) != 0 || uy.Cmp(ty) != 0 {
+		return nil, fmt.Errorf("sums of U and T points are not equal")
+	}
+
+	broadcastPayload, err := tssBroadcastPayload(
+		signing.NewSignRound9Message(pm.tssParameters.PartyID(), pm.si),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tssRoundNineMessage{
+		senderID:         pm.id,
+		broadcastPayload: broadcastPayload,
+		sessionID:        pm.sessionID,
+	}, nil
+}
+
+// presignedFinalize completes the signing with a presignature by combining
+// signature shares of all members and verifying the resulting signature.
+func (pm *presignedMember) presignedFinalize(
+	tssRoundNineMessages []*tssRoundNineMessage,
+) error {
+	curve := pm.tssParameters.EC()
+	n := curve.Params().N
+	modN := tsslibcommon.ModInt(n)
+
+	s := new(big.Int).Set(pm.si)
+	for _, tssRoundNineMessage := range tssRoundNineMessages {
+		_, shareMessage, err := parseTssMessage[*signing.SignRound9Message](
+			pm.tssParameters,
+			pm.identityConverter,
+			tssRoundNineMessage.SenderID(),
+			tssRoundNineMessage.broadcastPayload,
+			true,
+		)
+		if err != nil {
+			return err
+		}
+
+		s = modN.Add(s, shareMessage.UnmarshalS())
+	}
+
+	r := pm.bigR.X()
+
+	recoveryID := 0
+	if r.Cmp(n) > 0 {
+		recoveryID = 2
+	}
+	if pm.bigR.Y().Bit(0) != 0 {
+		recoveryID |= 1
+	}
+
+	// Normalize the signature to the lower S value the same way the TSS
+	// library does.
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+		recoveryID ^= 1
+	}
+
+	publicKey := pm.privateKeyShare.Data().ECDSAPub
+	if !ecdsa.Verify(
+		&ecdsa.PublicKey{
+			Curve: curve,
+			X:     publicKey.X(),
+			Y:     publicKey.Y(),
+		},
+		pm.message.Bytes(),
+		r,
+		s,
+	) {
+		return fmt.Errorf("signature verification failed")
+	}
+
+	bitSizeInBytes := curve.Params().BitSize / 8
+	rBytes := r.FillBytes(make([]byte, bitSizeInBytes))
+	sBytes := s.FillBytes(make([]byte, bitSizeInBytes))
+
+	pm.tssResult = &tsslibcommon.SignatureData{
+		Signature:         append(append([]byte{}, rBytes...), sBytes...),
+		SignatureRecovery: []byte{byte(recoveryID)},
+		R:                 rBytes,
+		S:                 sBytes,
+		M:                 pm.message.Bytes(),
+	}
+
+	return nil
+}
+
+// decryptPeerPayload decrypts the point-to-point part of a message sent by
+// the given member and intended for this member.
+func (skgm *symmetricKeyGeneratingMember) decryptPeerPayload(
+	senderID group.MemberIndex,
+	peersPayload map[group.MemberIndex][]byte,
+) ([]byte, error) {
+	encryptedPeerPayload, ok := peersPayload[skgm.id]
+	if !ok {
+		return nil, fmt.Errorf(
+			"no P2P part in the message from member [%v]",
+			senderID,
+		)
+	}
+
+	symmetricKey, ok := skgm.symmetricKeys[senderID]
+	if !ok {
+		return nil, fmt.Errorf(
+			"cannot get symmetric key with member [%v]",
+			senderID,
+		)
+	}
+
+	peerPayload, err := symmetricKey.Decrypt(encryptedPeerPayload)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot decrypt P2P part of the message from member [%v]: [%v]",
+			senderID,
+			err,
+		)
+	}
+
+	return peerPayload, nil
+}
+
+// parseTssMessage parses the given TSS message payload sent by the given
+// member and makes sure it holds a valid content of type T. Returns the
+// sorted TSS party ID of the sender along with the message content.
+func parseTssMessage[T tss.MessageContent](
+	tssParameters *tss.Parameters,
+	converter common.IdentityConverter,
+	senderID group.MemberIndex,
+	payload []byte,
+	isBroadcast bool,
+) (*tss.PartyID, T, error) {
+	var content T
+
+	senderPartyID := common.ResolveSortedTssPartyID(
+		tssParameters,
+		senderID,
+		converter,
+	)
+	if senderPartyID == nil {
+		return nil, content, fmt.Errorf(
+			"member [%v] is not a signing party",
+			senderID,
+		)
+	}
+
+	parsedMessage, err := tss.ParseWireMessage(
+		payload,
+		senderPartyID,
+		isBroadcast,
+	)
+	if err != nil {
+		return nil, content, fmt.Errorf(
+			"cannot parse TSS message from member [%v]: [%v]",
+			senderID,
+			err,
+		)
+	}
+
+	content, ok := parsedMessage.Content().(T)
+	if !ok || !parsedMessage.ValidateBasic() {
+		return nil, content, fmt.Errorf(
+			"invalid TSS message from member [%v]",
+			senderID,
+		)
+	}
+
+	return senderPartyID, content, nil
+}
+
+// tssBroadcastPayload returns the wire payload of the given TSS broadcast
+// message.
+func tssBroadcastPayload(tssMessage tss.Message) ([]byte, error) {
+	payload, _, err := tssMessage.WireBytes()
+	if err != nil {
+		return nil, fmt.Errorf("cannot unpack TSS message: [%v]", err)
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,506 @@
+package signing
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	mathrand "math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/keep-network/keep-core/pkg/internal/testutils"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+)
+
+func TestPresigning(t *testing.T) {
+	members, err := initializePresigningMembersGroup(
+		dishonestThreshold,
+		groupSize,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	presignatures, err := executePresigning(members)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMembersIndexes := []group.MemberIndex{1, 2, 3}
+	for i, presignature := range presignatures {
+		if !reflect.DeepEqual(
+			expectedMembersIndexes,
+			presignature.MembersIndexes(),
+		) {
+			t.Errorf(
+				"unexpected members of presignature [%v]\n"+
+					"expected: [%v]\n"+
+					"actual:   [%v]",
+				i,
+				expectedMembersIndexes,
+				presignature.MembersIndexes(),
+			)
+		}
+
+		if !bytes.Equal(
+			presignatures[0].PublicNonce(),
+			presignature.PublicNonce(),
+		) {
+			t.Errorf("presignature [%v] has a different public nonce", i)
+		}
+
+		if presignature.IsConsumed() {
+			t.Errorf("presignature [%v] is consumed", i)
+		}
+	}
+}
+
+func TestPresignedSigning(t *testing.T) {
+	members, err := initializePresigningMembersGroup(
+		dishonestThreshold,
+		groupSize,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	presignatures, err := executePresigning(members)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := big.NewInt(200)
+
+	var presignedMembers []*presignedMember
+	for i, member := range members {
+		k, sigma, err := presignatures[i].consume()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		member.message = message
+		presignedMembers = append(
+			presignedMembers,
+			member.initializePresignedSigning(
+				k,
+				sigma,
+				presignatures[i].bigR,
+			),
+		)
+	}
+
+	if err := executePresignedSigning(presignedMembers); err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey := members[0].privateKeyShare.PublicKey()
+	signatures := make(map[string]bool)
+
+	for _, member := range presignedMembers {
+		signature := member.Result().Signature
+
+		if !ecdsa.Verify(publicKey, message.Bytes(), signature.R, signature.S) {
+			t.Errorf(
+				"member [%v] signature verification failed",
+				member.id,
+			)
+		}
+
+		signatures[signature.String()] = true
+	}
+
+	testutils.AssertIntsEqual(
+		t,
+		"count of distinct signatures produced by the group",
+		1,
+		len(signatures),
+	)
+
+	for i, presignature := range presignatures {
+		if !presignature.IsConsumed() {
+			t.Errorf("presignature [%v] is not consumed", i)
+		}
+	}
+}
+
+func TestPresignedSigning_RandomMessagesAndSubsets(t *testing.T) {
+	// The test fixtures represent a 3-of-5 signing group.
+	const (
+		fixturesGroupSize          = 5
+		fixturesDishonestThreshold = 2
+		iterations                 = 10
+	)
+
+	for i := 0; i < iterations; i++ {
+		members, err := initializePresigningMembersGroup(
+			fixturesDishonestThreshold,
+			fixturesGroupSize,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Leave out a random number of members, keeping at least as many
+		// members as the honest threshold. All members share the same group
+		// instance so it is enough to mark the excluded members once.
+		// Operating members are initialized again so their TSS parameters
+		// reflect the actual operating members.
+		signingGroup := members[0].group
+		excludedCount := mathrand.Intn(
+			fixturesGroupSize - signingGroup.HonestThreshold() + 1,
+		)
+		for _, j := range mathrand.Perm(fixturesGroupSize)[:excludedCount] {
+			signingGroup.MarkMemberAsInactive(members[j].id)
+		}
+
+		var operatingMembers []*presigningMember
+		for _, member := range members {
+			if signingGroup.IsOperating(member.id) {
+				operatingMembers = append(
+					operatingMembers,
+					member.symmetricKeyGeneratingMember.initializePresigning(),
+				)
+			}
+		}
+
+		presignatures, err := executePresigning(operatingMembers)
+		if err != nil {
+			t.Fatalf(
+				"presigning of members [%v] failed: [%v]",
+				signingGroup.OperatingMemberIndexes(),
+				err,
+			)
+		}
+
+		messageBytes := make([]byte, 32)
+		if _, err := rand.Read(messageBytes); err != nil {
+			t.Fatal(err)
+		}
+		message := new(big.Int).SetBytes(messageBytes)
+
+		var presignedMembers []*presignedMember
+		for j, member := range operatingMembers {
+			k, sigma, err := presignatures[j].consume()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			member.message = message
+			presignedMembers = append(
+				presignedMembers,
+				member.initializePresignedSigning(
+					k,
+					sigma,
+					presignatures[j].bigR,
+				),
+			)
+		}
+
+		if err := executePresignedSigning(presignedMembers); err != nil {
+			t.Fatalf(
+				"signing of message [%x] by members [%v] failed: [%v]",
+				messageBytes,
+				signingGroup.OperatingMemberIndexes(),
+				err,
+			)
+		}
+
+		publicKey := members[0].privateKeyShare.PublicKey()
+		for _, member := range presignedMembers {
+			signature := member.Result().Signature
+
+			if !ecdsa.Verify(
+				publicKey,
+				message.Bytes(),
+				signature.R,
+				signature.S,
+			) {
+				t.Errorf(
+					"signature of message [%x] produced by member [%v] "+
+						"of members [%v] does not verify against the "+
+						"group public key",
+					messageBytes,
+					member.id,
+					signingGroup.OperatingMemberIndexes(),
+				)
+			}
+		}
+	}
+}
+
+func TestPresignedSigning_WrongPresignature(t *testing.T) {
+	members, err := initializePresigningMembersGroup(
+		dishonestThreshold,
+		groupSize,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	presignatures, err := executePresigning(members)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherMembers, err := initializePresigningMembersGroup(
+		dishonestThreshold,
+		groupSize,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherPresignatures, err := executePresigning(otherMembers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var presignedMembers []*presignedMember
+	for i, member := range members {
+		presignature := presignatures[i]
+		// The last member uses a presignature from another presigning
+		// session so the signing must fail.
+		if i == len(members)-1 {
+			presignature = otherPresignatures[i]
+		}
+
+		k, sigma, err := presignature.consume()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		member.message = big.NewInt(200)
+		presignedMembers = append(
+			presignedMembers,
+			member.initializePresignedSigning(k, sigma, presignature.bigR),
+		)
+	}
+
+	err = executePresignedSigning(presignedMembers)
+	if err == nil {
+		t.Fatal("expected signing error")
+	}
+}
+
+func TestExecuteWithPresignature_ConsumedPresignature(t *testing.T) {
+	members, err := initializePresigningMembersGroup(
+		dishonestThreshold,
+		groupSize,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	presignatures, err := executePresigning(members)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	presignature := presignatures[0]
+	if _, _, err := presignature.consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ExecuteWithPresignature(
+		context.Background(),
+		&testutils.MockLogger{},
+		big.NewInt(200),
+		sessionID,
+		members[0].id,
+		members[0].privateKeyShare,
+		presignature,
+		groupSize,
+		dishonestThreshold,
+		nil,
+		nil,
+	)
+	if err != errPresignatureConsumed {
+		t.Fatalf(
+			"unexpected error\nexpected: [%v]\nactual:   [%v]",
+			errPresignatureConsumed,
+			err,
+		)
+	}
+}
+
+func initializePresigningMembersGroup(
+	dishonestThreshold int,
+	groupSize int,
+) ([]*presigningMember, error) {
+	symmetricKeyGeneratingMembers, ephemeralPublicKeyMessages, err :=
+		initializeSymmetricKeyGeneratingMembersGroup(
+			dishonestThreshold,
+			groupSize,
+		)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot generate symmetric key generating members group: [%v]",
+			err,
+		)
+	}
+
+	var presigningMembers []*presigningMember
+	for _, member := range symmetricKeyGeneratingMembers {
+		err := member.generateSymmetricKeys(
+			receivedFrom(ephemeralPublicKeyMessages, member.id),
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot generate symmetric keys for member [%v]: [%v]",
+				member.id,
+				err,
+			)
+		}
+
+		member.message = nil
+		member.presigning = true
+
+		presigningMembers = append(
+			presigningMembers,
+			member.initializePresigning(),
+		)
+	}
+
+	return presigningMembers, nil
+}
+
+func executePresigning(members []*presigningMember) ([]*Presignature, error) {
+	var tssRoundOneMessages []*tssRoundOneMessage
+	for _, member := range members {
+		message, err := member.presigningRoundOne()
+		if err != nil {
+			return nil, err
+		}
+		tssRoundOneMessages = append(tssRoundOneMessages, message)
+	}
+
+	var tssRoundTwoMessages []*tssRoundTwoMessage
+	for _, member := range members {
+		message, err := member.presigningRoundTwo(
+			receivedFrom(tssRoundOneMessages, member.id),
+		)
+		if err != nil {
+			return nil, err
+		}
+		tssRoundTwoMessages = append(tssRoundTwoMessages, message)
+	}
+
+	var tssRoundThreeMessages []*tssRoundThreeMessage
+	for _, member := range members {
+		message, err := member.presigningRoundThree(
+			receivedFrom(tssRoundTwoMessages, member.id),
+		)
+		if err != nil {
+			return nil, err
+		}
+		tssRoundThreeMessages = append(tssRoundThreeMessages, message)
+	}
+
+	var tssRoundFourMessages []*tssRoundFourMessage
+	for _, member := range members {
+		message, err := member.presigningRoundFour(
+			receivedFrom(tssRoundThreeMessages, member.id),
+		)
+		if err != nil {
+			return nil, err
+		}
+		tssRoundFourMessages = append(tssRoundFourMessages, message)
+	}
+
+	var presignatures []*Presignature
+	for _, member := range members {
+		err := member.presigningFinalize(
+			receivedFrom(tssRoundFourMessages, member.id),
+		)
+		if err != nil {
+			return nil, err
+		}
+		presignatures = append(presignatures, member.Presignature())
+	}
+
+	return presignatures, nil
+}
+
+func executePresignedSigning(members []*presignedMember) error {
+	var tssRoundFiveMessages []*tssRoundFiveMessage
+	for _, member := range members {
+		message, err := member.presignedRoundFive()
+		if err != nil {
+			return err
+		}
+		tssRoundFiveMessages = append(tssRoundFiveMessages, message)
+	}
+
+	var tssRoundSixMessages []*tssRoundSixMessage
+	for _, member := range members {
+		message, err := member.presignedRoundSix(
+			receivedFrom(tssRoundFiveMessages, member.id),
+		)
+		if err != nil {
+			return err
+		}
+		tssRoundSixMessages = append(tssRoundSixMessages, message)
+	}
+
+	var tssRoundSevenMessages []*tssRoundSevenMessage
+	for _, member := range members {
+		message, err := member.presignedRoundSeven(
+			receivedFrom(tssRoundSixMessages, member.id),
+		)
+		if err != nil {
+			return err
+		}
+		tssRoundSevenMessages = append(tssRoundSevenMessages, message)
+	}
+
+	var tssRoundEightMessages []*tssRoundEightMessage
+	for _, member := range members {
+		message, err := member.presignedRoundEight(
+			receivedFrom(tssRoundSevenMessages, member.id),
+		)
+		if err != nil {
+			return err
+		}
+		tssRoundEightMessages = append(tssRoundEightMessages, message)
+	}
+
+	var tssRoundNineMessages []*tssRoundNineMessage
+	for _, member := range members {
+		message, err := member.presignedRoundNine(
+			receivedFrom(tssRoundEightMessages, member.id),
+		)
+		if err != nil {
+			return err
+		}
+		tssRoundNineMessages = append(tssRoundNineMessages, message)
+	}
+
+	for _, member := range members {
+		err := member.presignedFinalize(
+			receivedFrom(tssRoundNineMessages, member.id),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// receivedFrom returns the given messages except the ones sent by the
+// given receiver.
+func receivedFrom[T message](
+	messages []T,
+	receiverID group.MemberIndex,
+) []T {
+	var received []T
+	for _, message := range messages {
+		if message.SenderID() != receiverID {
+			received = append(received, message)
+		}
+	}
+	return received
+}
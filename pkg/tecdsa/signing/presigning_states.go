@@ -0,0 +1,592 @@
+package signing
+
+import (
+	"context"
+
+	"github.com/keep-network/keep-core/pkg/net"
+	"github.com/keep-network/keep-core/pkg/protocol/group"
+	"github.com/keep-network/keep-core/pkg/protocol/state"
+)
+
+// presigningRoundOneState is the state during which presigning members broadcast TSS
+// round one messages.
+// `tssRoundOneMessage`s are valid in this state.
+type presigningRoundOneState struct {
+	*state.BaseAsyncState
+
+	channel net.BroadcastChannel
+	member  *presigningMember
+}
+
+func (pros *presigningRoundOneState) Initiate(ctx context.Context) error {
+	message, err := pros.member.presigningRoundOne()
+	if err != nil {
+		return err
+	}
+
+	if err := pros.channel.Send(ctx, message, net.BackoffRetransmissionStrategy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (pros *presigningRoundOneState) Receive(netMessage net.Message) error {
+	if protocolMessage, ok := netMessage.Payload().(message); ok {
+		if pros.member.shouldAcceptMessage(
+			protocolMessage.SenderID(),
+			netMessage.SenderPublicKey(),
+		) && pros.member.sessionID == protocolMessage.SessionID() {
+			pros.ReceiveToHistory(netMessage)
+		}
+	}
+
+	return nil
+}
+
+func (pros *presigningRoundOneState) CanTransition() bool {
+	messagingDone := len(receivedMessages[*tssRoundOneMessage](pros.BaseAsyncState)) ==
+		len(pros.member.group.OperatingMemberIndexes())-1
+
+	return messagingDone
+}
+
+func (pros *presigningRoundOneState) Next() (state.AsyncState, error) {
+	return &presigningRoundTwoState{
+		BaseAsyncState: pros.BaseAsyncState,
+		channel:        pros.channel,
+		member:         pros.member,
+	}, nil
+}
+
+func (pros *presigningRoundOneState) MemberIndex() group.MemberIndex {
+	return pros.member.id
+}
+
+// presigningRoundTwoState is the state during which presigning members broadcast TSS
+// round two messages.
+// `tssRoundTwoMessage`s are valid in this state.
+type presigningRoundTwoState struct {
+	*state.BaseAsyncState
+
+	channel net.BroadcastChannel
+	member  *presigningMember
+}
+
+func (prts *presigningRoundTwoState) Initiate(ctx context.Context) error {
+	message, err := prts.member.presigningRoundTwo(
+		receivedMessages[*tssRoundOneMessage](prts.BaseAsyncState),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := prts.channel.Send(ctx, message, net.BackoffRetransmissionStrategy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (prts *presigningRoundTwoState) Receive(netMessage net.Message) error {
+	if protocolMessage, ok := netMessage.Payload().(message); ok {
+		if prts.member.shouldAcceptMessage(
+			protocolMessage.SenderID(),
+			netMessage.SenderPublicKey(),
+		) && prts.member.sessionID == protocolMessage.SessionID() {
+			prts.ReceiveToHistory(netMessage)
+		}
+	}
+
+	return nil
+}
+
+func (prts *presigningRoundTwoState) CanTransition() bool {
+	messagingDone := len(receivedMessages[*tssRoundTwoMessage](prts.BaseAsyncState)) ==
+		len(prts.member.group.OperatingMemberIndexes())-1
+
+	return messagingDone
+}
+
+func (prts *presigningRoundTwoState) Next() (state.AsyncState, error) {
+	return &presigningRoundThreeState{
+		BaseAsyncState: prts.BaseAsyncState,
+		channel:        prts.channel,
+		member:         prts.member,
+	}, nil
+}
+
+func (prts *presigningRoundTwoState) MemberIndex() group.MemberIndex {
+	return prts.member.id
+}
+
+// presigningRoundThreeState is the state during which presigning members broadcast TSS
+// round three messages.
+// `tssRoundThreeMessage`s are valid in this state.
+type presigningRoundThreeState struct {
+	*state.BaseAsyncState
+
+	channel net.BroadcastChannel
+	member  *presigningMember
+}
+
+func (prts *presigningRoundThreeState) Initiate(ctx context.Context) error {
+	message, err := prts.member.presigningRoundThree(
+		receivedMessages[*tssRoundTwoMessage](prts.BaseAsyncState),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := prts.channel.Send(ctx, message, net.BackoffRetransmissionStrategy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (prts *presigningRoundThreeState) Receive(netMessage net.Message) error {
+	if protocolMessage, ok := netMessage.Payload().(message); ok {
+		if prts.member.shouldAcceptMessage(
+			protocolMessage.SenderID(),
+			netMessage.SenderPublicKey(),
+		) && prts.member.sessionID == protocolMessage.SessionID() {
+			prts.ReceiveToHistory(netMessage)
+		}
+	}
+
+	return nil
+}
+
+func (prts *presigningRoundThreeState) CanTransition() bool {
+	messagingDone := len(receivedMessages[*tssRoundThreeMessage](prts.BaseAsyncState)) ==
+		len(prts.member.group.OperatingMemberIndexes())-1
+
+	return messagingDone
+}
+
+func (prts *presigningRoundThreeState) Next() (state.AsyncState, error) {
+	return &presigningRoundFourState{
+		BaseAsyncState: prts.BaseAsyncState,
+		channel:        prts.channel,
+		member:         prts.member,
+	}, nil
+}
+
+func (prts *presigningRoundThreeState) MemberIndex() group.MemberIndex {
+	return prts.member.id
+}
+
+// presigningRoundFourState is the state during which presigning members broadcast TSS
+// round four messages.
+// `tssRoundFourMessage`s are valid in this state.
+type presigningRoundFourState struct {
+	*state.BaseAsyncState
+
+	channel net.BroadcastChannel
+	member  *presigningMember
+}
+
+func (prfs *presigningRoundFourState) Initiate(ctx context.Context) error {
+	message, err := prfs.member.presigningRoundFour(
+		receivedMessages[*tssRoundThreeMessage](prfs.BaseAsyncState),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := prfs.channel.Send(ctx, message, net.BackoffRetransmissionStrategy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (prfs *presigningRoundFourState) Receive(netMessage net.Message) error {
+	if protocolMessage, ok := netMessage.Payload().(message); ok {
+		if prfs.member.shouldAcceptMessage(
+			protocolMessage.SenderID(),
+			netMessage.SenderPublicKey(),
+		) && prfs.member.sessionID == protocolMessage.SessionID() {
+			prfs.ReceiveToHistory(netMessage)
+		}
+	}
+
+	return nil
+}
+
+func (prfs *presigningRoundFourState) CanTransition() bool {
+	messagingDone := len(receivedMessages[*tssRoundFourMessage](prfs.BaseAsyncState)) ==
+		len(prfs.member.group.OperatingMemberIndexes())-1
+
+	return messagingDone
+}
+
+func (prfs *presigningRoundFourState) Next() (state.AsyncState, error) {
+	return &presigningFinalizationState{
+		BaseAsyncState: prfs.BaseAsyncState,
+		channel:        prfs.channel,
+		member:         prfs.member,
+	}, nil
+}
+
+func (prfs *presigningRoundFourState) MemberIndex() group.MemberIndex {
+	return prfs.member.id
+}
+
+// presigningFinalizationState is the last state of the presigning protocol - in this
+// state, presigning is completed. No messages are valid in this state.
+//
+// State prepares a result that is returned to the caller.
+type presigningFinalizationState struct {
+	*state.BaseAsyncState
+
+	channel net.BroadcastChannel
+	member  *presigningMember
+}
+
+func (pfs *presigningFinalizationState) Initiate(ctx context.Context) error {
+	return pfs.member.presigningFinalize(
+		receivedMessages[*tssRoundFourMessage](pfs.BaseAsyncState),
+	)
+}
+
+func (pfs *presigningFinalizationState) Receive(net.Message) error {
+	return nil
+}
+
+func (pfs *presigningFinalizationState) CanTransition() bool {
+	return true
+}
+
+func (pfs *presigningFinalizationState) Next() (state.AsyncState, error) {
+	return nil, nil
+}
+
+func (pfs *presigningFinalizationState) MemberIndex() group.MemberIndex {
+	return pfs.member.id
+}
+
+func (pfs *presigningFinalizationState) result() *Presignature {
+	return pfs.member.Presignature()
+}
+
+// presignedRoundFiveState is the state during which presigned signing members broadcast TSS
+// round five messages.
+// `tssRoundFiveMessage`s are valid in this state.
+type presignedRoundFiveState struct {
+	*state.BaseAsyncState
+
+	channel net.BroadcastChannel
+	member  *presignedMember
+}
+
+func (prfs *presignedRoundFiveState) Initiate(ctx context.Context) error {
+	message, err := prfs.member.presignedRoundFive()
+	if err != nil {
+		return err
+	}
+
+	if err := prfs.channel.Send(ctx, message, net.BackoffRetransmissionStrategy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (prfs *presignedRoundFiveState) Receive(netMessage net.Message) error {
+	if protocolMessage, ok := netMessage.Payload().(message); ok {
+		if prfs.member.shouldAcceptMessage(
+			protocolMessage.SenderID(),
+			netMessage.SenderPublicKey(),
+		) && prfs.member.sessionID == protocolMessage.SessionID() {
+			prfs.ReceiveToHistory(netMessage)
+		}
+	}
+
+	return nil
+}
+
+func (prfs *presignedRoundFiveState) CanTransition() bool {
+	messagingDone := len(receivedMessages[*tssRoundFiveMessage](prfs.BaseAsyncState)) ==
+		len(prfs.member.group.OperatingMemberIndexes())-1
+
+	return messagingDone
+}
+
+func (prfs *presignedRoundFiveState) Next() (state.AsyncState, error) {
+	return &presignedRoundSixState{
+		BaseAsyncState: prfs.BaseAsyncState,
+		channel:        prfs.channel,
+		member:         prfs.member,
+	}, nil
+}
+
+func (prfs *presignedRoundFiveState) MemberIndex() group.MemberIndex {
+	return prfs.member.id
+}
+
+// presignedRoundSixState is the state during which presigned signing members broadcast TSS
+// round six messages.
+// `tssRoundSixMessage`s are valid in this state.
+type presignedRoundSixState struct {
+	*state.BaseAsyncState
+
+	channel net.BroadcastChannel
+	member  *presignedMember
+}
+
+func (prss *presignedRoundSixState) Initiate(ctx context.Context) error {
+	message, err := prss.member.presignedRoundSix(
+		receivedMessages[*tssRoundFiveMessage](prss.BaseAsyncState),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := prss.channel.Send(ctx, message, net.BackoffRetransmissionStrategy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (prss *presignedRoundSixState) Receive(netMessage net.Message) error {
+	if protocolMessage, ok := netMessage.Payload().(message); ok {
+		if prss.member.shouldAcceptMessage(
+			protocolMessage.SenderID(),
+			netMessage.SenderPublicKey(),
+		) && prss.member.sessionID == protocolMessage.SessionID() {
+			prss.ReceiveToHistory(netMessage)
+		}
+	}
+
+	return nil
+}
+
+func (prss *presignedRoundSixState) CanTransition() bool {
+	messagingDone := len(receivedMessages[*tssRoundSixMessage](prss.BaseAsyncState)) ==
+		len(prss.member.group.OperatingMemberIndexes())-1
+
+	return messagingDone
+}
+
+func (prss *presignedRoundSixState) Next() (state.AsyncState, error) {
+	return &presignedRoundSevenState{
+		BaseAsyncState: prss.BaseAsyncState,
+		channel:        prss.channel,
+		member:         prss.member,
+	}, nil
+}
+
+func (prss *presignedRoundSixState) MemberIndex() group.MemberIndex {
+	return prss.member.id
+}
+
+// presignedRoundSevenState is the state during which presigned signing members broadcast TSS
+// round seven messages.
+// `tssRoundSevenMessage`s are valid in this state.
+type presignedRoundSevenState struct {
+	*state.BaseAsyncState
+
+	channel net.BroadcastChannel
+	member  *presignedMember
+}
+
+func (prss *presignedRoundSevenState) Initiate(ctx context.Context) error {
+	message, err := prss.member.presignedRoundSeven(
+		receivedMessages[*tssRoundSixMessage](prss.BaseAsyncState),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := prss.channel.Send(ctx, message, net.BackoffRetransmissionStrategy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (prss *presignedRoundSevenState) Receive(netMessage net.Message) error {
+	if protocolMessage, ok := netMessage.Payload().(message); ok {
+		if prss.member.shouldAcceptMessage(
+			protocolMessage.SenderID(),
+			netMessage.SenderPublicKey(),
+		) && prss.member.sessionID == protocolMessage.SessionID() {
+			prss.ReceiveToHistory(netMessage)
+		}
+	}
+
+	return nil
+}
+
+func (prss *presignedRoundSevenState) CanTransition() bool {
+	messagingDone := len(receivedMessages[*tssRoundSevenMessage](prss.BaseAsyncState)) ==
+		len(prss.member.group.OperatingMemberIndexes())-1
+
+	return messagingDone
+}
+
+func (prss *presignedRoundSevenState) Next() (state.AsyncState, error) {
+	return &presignedRoundEightState{
+		BaseAsyncState: prss.BaseAsyncState,
+		channel:        prss.channel,
+		member:         prss.member,
+	}, nil
+}
+
+func (prss *presignedRoundSevenState) MemberIndex() group.MemberIndex {
+	return prss.member.id
+}
+
+// presignedRoundEightState is the state during which presigned signing members broadcast TSS
+// round eight messages.
+// `tssRoundEightMessage`s are valid in this state.
+type presignedRoundEightState struct {
+	*state.BaseAsyncState
+
+	channel net.BroadcastChannel
+	member  *presignedMember
+}
+
+func (pres *presignedRoundEightState) Initiate(ctx context.Context) error {
+	message, err := pres.member.presignedRoundEight(
+		receivedMessages[*tssRoundSevenMessage](pres.BaseAsyncState),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := pres.channel.Send(ctx, message, net.BackoffRetransmissionStrategy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (pres *presignedRoundEightState) Receive(netMessage net.Message) error {
+	if protocolMessage, ok := netMessage.Payload().(message); ok {
+		if pres.member.shouldAcceptMessage(
+			protocolMessage.SenderID(),
+			netMessage.SenderPublicKey(),
+		) && pres.member.sessionID == protocolMessage.SessionID() {
+			pres.ReceiveToHistory(netMessage)
+		}
+	}
+
+	return nil
+}
+
+func (pres *presignedRoundEightState) CanTransition() bool {
+	messagingDone := len(receivedMessages[*tssRoundEightMessage](pres.BaseAsyncState)) ==
+		len(pres.member.group.OperatingMemberIndexes())-1
+
+	return messagingDone
+}
+
+func (pres *presignedRoundEightState) Next() (state.AsyncState, error) {
+	return &presignedRoundNineState{
+		BaseAsyncState: pres.BaseAsyncState,
+		channel:        pres.channel,
+		member:         pres.member,
+	}, nil
+}
+
+func (pres *presignedRoundEightState) MemberIndex() group.MemberIndex {
+	return pres.member.id
+}
+
+// presignedRoundNineState is the state during which presigned signing members broadcast TSS
+// round nine messages.
+// `tssRoundNineMessage`s are valid in this state.
+type presignedRoundNineState struct {
+	*state.BaseAsyncState
+
+	channel net.BroadcastChannel
+	member  *presignedMember
+}
+
+func (prns *presignedRoundNineState) Initiate(ctx context.Context) error {
+	message, err := prns.member.presignedRoundNine(
+		receivedMessages[*tssRoundEightMessage](prns.BaseAsyncState),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := prns.channel.Send(ctx, message, net.BackoffRetransmissionStrategy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (prns *presignedRoundNineState) Receive(netMessage net.Message) error {
+	if protocolMessage, ok := netMessage.Payload().(message); ok {
+		if prns.member.shouldAcceptMessage(
+			protocolMessage.SenderID(),
+			netMessage.SenderPublicKey(),
+		) && prns.member.sessionID == protocolMessage.SessionID() {
+			prns.ReceiveToHistory(netMessage)
+		}
+	}
+
+	return nil
+}
+
+func (prns *presignedRoundNineState) CanTransition() bool {
+	messagingDone := len(receivedMessages[*tssRoundNineMessage](prns.BaseAsyncState)) ==
+		len(prns.member.group.OperatingMemberIndexes())-1
+
+	return messagingDone
+}
+
+func (prns *presignedRoundNineState) Next() (state.AsyncState, error) {
+	return &presignedFinalizationState{
+		BaseAsyncState: prns.BaseAsyncState,
+		channel:        prns.channel,
+		member:         prns.member,
+	}, nil
+}
+
+func (prns *presignedRoundNineState) MemberIndex() group.MemberIndex {
+	return prns.member.id
+}
+
+// presignedFinalizationState is the last state of the signing with a presignature - in this
+// state, signing is completed. No messages are valid in this state.
+//
+// State prepares a result that is returned to the caller.
+type presignedFinalizationState struct {
+	*state.BaseAsyncState
+
+	channel net.BroadcastChannel
+	member  *presignedMember
+}
+
+func (pfs *presignedFinalizationState) Initiate(ctx context.Context) error {
+	return pfs.member.presignedFinalize(
+		receivedMessages[*tssRoundNineMessage](pfs.BaseAsyncState),
+	)
+}
+
+func (pfs *presignedFinalizationState) Receive(net.Message) error {
+	return nil
+}
+
+func (pfs *presignedFinalizationState) CanTransition() bool {
+	return true
+}
+
+func (pfs *presignedFinalizationState) Next() (state.AsyncState, error) {
+	return nil, nil
+}
+
+func (pfs *presignedFinalizationState) MemberIndex() group.MemberIndex {
+	return pfs.member.id
+}
+
+func (pfs *presignedFinalizationState) result() *Result {
+	return pfs.member.Result()
+}
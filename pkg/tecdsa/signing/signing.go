@@ -11,6 +11,7 @@ import (
 	"github.com/keep-network/keep-core/pkg/net"
 	"github.com/keep-network/keep-core/pkg/protocol/group"
 	"github.com/keep-network/keep-core/pkg/tecdsa"
+	"golang.org/x/exp/slices"
 )
 
 // Execute runs the tECDSA signing protocol, given a message to sign,
@@ -76,6 +77,160 @@ func Execute(
 	return finalizationState.result(), nil
 }
 
+// ExecutePresigning runs the message-independent part of the tECDSA signing
+// protocol and returns a presignature that can be later used to sign one
+// message using ExecuteWithPresignature. The presignature is computed by
+// all members of the signing group except the excluded ones and can be used
+// only by exactly the same set of members. The number of members computing
+// the presignature must be equal to the honest threshold of the group.
+func ExecutePresigning(
+	ctx context.Context,
+	logger log.StandardLogger,
+	sessionID string,
+	memberIndex group.MemberIndex,
+	privateKeyShare *tecdsa.PrivateKeyShare,
+	groupSize int,
+	dishonestThreshold int,
+	excludedMembersIndexes []group.MemberIndex,
+	channel net.BroadcastChannel,
+	membershipValidator *group.MembershipValidator,
+) (*Presignature, error) {
+	logger.Debugf("[member:%v] initializing presigning member", memberIndex)
+
+	member := newMember(
+		logger,
+		memberIndex,
+		groupSize,
+		dishonestThreshold,
+		membershipValidator,
+		sessionID,
+		nil,
+		privateKeyShare,
+	)
+	member.presigning = true
+
+	// Mark excluded members as disqualified in order to not exchange messages
+	// with them.
+	for _, excludedMemberIndex := range excludedMembersIndexes {
+		if excludedMemberIndex != member.id {
+			member.group.MarkMemberAsDisqualified(excludedMemberIndex)
+		}
+	}
+
+	// Signing with a presignature requires all members that computed it
+	// to be present so the presignature must be computed by exactly the
+	// honest threshold of members.
+	operatingMembersCount := len(member.group.OperatingMemberIndexes())
+	if operatingMembersCount != member.group.HonestThreshold() {
+		return nil, fmt.Errorf(
+			"presignature must be computed by [%v] members; got [%v]",
+			member.group.HonestThreshold(),
+			operatingMembersCount,
+		)
+	}
+
+	initialState := &ephemeralKeyPairGenerationState{
+		BaseAsyncState: state.NewBaseAsyncState(),
+		channel:        channel,
+		member:         member.initializeEphemeralKeysGeneration(),
+	}
+
+	stateMachine := state.NewAsyncMachine(logger, ctx, channel, initialState)
+
+	lastState, err := stateMachine.Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	finalizationState, ok := lastState.(*presigningFinalizationState)
+	if !ok {
+		return nil, fmt.Errorf("execution ended on state: %T", lastState)
+	}
+
+	return finalizationState.result(), nil
+}
+
+// ExecuteWithPresignature runs the message-dependent part of the tECDSA
+// signing protocol using the given presignature. All members that computed
+// the presignature must take part in the signing. The presignature is
+// consumed before the signing starts so it cannot be used again, regardless
+// of the signing outcome.
+func ExecuteWithPresignature(
+	ctx context.Context,
+	logger log.StandardLogger,
+	message *big.Int,
+	sessionID string,
+	memberIndex group.MemberIndex,
+	privateKeyShare *tecdsa.PrivateKeyShare,
+	presignature *Presignature,
+	groupSize int,
+	dishonestThreshold int,
+	channel net.BroadcastChannel,
+	membershipValidator *group.MembershipValidator,
+) (*Result, error) {
+	logger.Debugf("[member:%v] initializing presigned member", memberIndex)
+
+	if message.Cmp(tecdsa.Curve.Params().N) >= 0 {
+		return nil, fmt.Errorf("message is not lower than the curve order")
+	}
+
+	member := newMember(
+		logger,
+		memberIndex,
+		groupSize,
+		dishonestThreshold,
+		membershipValidator,
+		sessionID,
+		message,
+		privateKeyShare,
+	)
+
+	presignatureMembersIndexes := presignature.MembersIndexes()
+	if !slices.Contains(presignatureMembersIndexes, member.id) {
+		return nil, fmt.Errorf(
+			"member [%v] did not compute the presignature",
+			member.id,
+		)
+	}
+
+	// Mark members that did not compute the presignature as disqualified in
+	// order to not exchange messages with them.
+	for _, groupMemberIndex := range member.group.MemberIndexes() {
+		if !slices.Contains(presignatureMembersIndexes, groupMemberIndex) {
+			member.group.MarkMemberAsDisqualified(groupMemberIndex)
+		}
+	}
+
+	k, sigma, err := presignature.consume()
+	if err != nil {
+		return nil, err
+	}
+
+	initialState := &presignedRoundFiveState{
+		BaseAsyncState: state.NewBaseAsyncState(),
+		channel:        channel,
+		member: member.initializePresignedSigning(
+			k,
+			sigma,
+			presignature.bigR,
+		),
+	}
+
+	stateMachine := state.NewAsyncMachine(logger, ctx, channel, initialState)
+
+	lastState, err := stateMachine.Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	finalizationState, ok := lastState.(*presignedFinalizationState)
+	if !ok {
+		return nil, fmt.Errorf("execution ended on state: %T", lastState)
+	}
+
+	return finalizationState.result(), nil
+}
+
 // RegisterUnmarshallers initializes the given broadcast channel to be able to
 // perform signing protocol interactions by registering all the required
 // protocol message unmarshallers.
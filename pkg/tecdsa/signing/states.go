@@ -99,6 +99,14 @@ func (skgs *symmetricKeyGenerationState) CanTransition() bool {
 }
 
 func (skgs *symmetricKeyGenerationState) Next() (state.AsyncState, error) {
+	if skgs.member.presigning {
+		return &presigningRoundOneState{
+			BaseAsyncState: skgs.BaseAsyncState,
+			channel:        skgs.channel,
+			member:         skgs.member.initializePresigning(),
+		}, nil
+	}
+
 	return &tssRoundOneState{
 		BaseAsyncState: skgs.BaseAsyncState,
 		channel:        skgs.channel,